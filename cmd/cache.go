@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the per-file analysis cache",
+	Long: `Manage the per-file analysis cache.
+
+ars scan caches per-file results (functions, complexity, comment counts,
+duplication hashes, test assertions) under $XDG_CACHE_HOME/ars, keyed by
+file content, analyzer version and configuration. Unchanged files are not
+re-parsed on the next scan. Use --no-cache on scan to bypass it.`,
+}
+
+var cacheCleanCmd = &cobra.Command{
+	Use:          "clean",
+	Short:        "Remove all cached analysis results",
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cache.DefaultDir()
+		if err != nil {
+			return err
+		}
+		if err := cache.Clean(dir); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed analysis cache: %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheCleanCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	c7 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c7_agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/config"
+)
+
+var calibrateFixtures string
+
+var calibrateCmd = &cobra.Command{
+	Use:   "calibrate",
+	Short: "Check that the judge model ranks C7 responses like the heuristics",
+	Long: `Check that the judge model ranks C7 responses like the heuristics.
+
+calibrate scores a set of strong and weak agent responses for M2-M5 with the
+judge used by c7.scoring: judge and reports, per metric, whether the judge
+ranks each pair the same way as the keyword heuristics. The judge comes from
+the judge section of .arsrc.yml (default: the Claude CLI). The built-in
+fixtures can be replaced with --fixtures; see the README for their layout.
+
+Exits non-zero when the judge and the heuristics disagree on any pair.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectCfg, err := config.LoadProjectConfig(".", configPath)
+		if err != nil {
+			return fmt.Errorf("load project config: %w", err)
+		}
+		judge, err := projectCfg.C4Judge()
+		if err != nil {
+			return fmt.Errorf("configure judge: %w", err)
+		}
+		if judge == nil {
+			if judge, err = agent.NewJudge(agent.JudgeConfig{}); err != nil {
+				return fmt.Errorf("configure judge: %w", err)
+			}
+		}
+
+		var cases []metrics.CalibrationCase
+		if calibrateFixtures != "" {
+			cases, err = metrics.LoadCalibrationSet(os.DirFS(calibrateFixtures))
+		} else {
+			cases, err = metrics.DefaultCalibrationSet()
+		}
+		if err != nil {
+			return fmt.Errorf("load calibration fixtures: %w", err)
+		}
+
+		report, err := c7.Calibrate(cmd.Context(), judge, cases)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "judge %s\n%s", judge.Info(), report)
+		if report.Agreement() < 1 {
+			return fmt.Errorf("judge and heuristic rankings disagree on %d of %d pairs", report.Pairs-report.Agreements, report.Pairs)
+		}
+		return nil
+	},
+}
+
+func init() {
+	calibrateCmd.Flags().StringVar(&calibrateFixtures, "fixtures", "", "directory of calibration fixtures (default: built-in set)")
+	calibrateCmd.Flags().StringVar(&configPath, "config", "", "path to .arsrc.yml project config file")
+	rootCmd.AddCommand(calibrateCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCalibrate_FixedScoreJudgeDisagrees(t *testing.T) {
+	resetScanFlags()
+	defer func() { calibrateFixtures = "" }()
+	cfgPath := filepath.Join(t.TempDir(), ".arsrc.yml")
+	cfg := `version: 1
+judge:
+  backend: command
+  command: ["sh", "-c", "cat >/dev/null; echo '{\"score\": 6, \"reason\": \"fixed\"}'"]
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"calibrate", "--config", cfgPath})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "disagree on 4 of 4 pairs") {
+		t.Fatalf("Execute() error = %v, want a disagreement on all pairs", err)
+	}
+	for _, want := range []string{"judge command", "agreement: 0/4 pairs"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCalibrate_MissingFixtures(t *testing.T) {
+	resetScanFlags()
+	defer func() { calibrateFixtures = "" }()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"calibrate", "--fixtures", filepath.Join(t.TempDir(), "missing")})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected error for a missing fixtures directory")
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/lsp"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server that publishes agent readiness diagnostics",
+	Long: `Run a Language Server Protocol server over stdin/stdout.
+
+The server publishes diagnostics for each open file:
+  - functions whose complexity or length passes the C1 scoring breakpoints
+  - duplicated blocks, linked to their twin via related information
+  - exported symbols never referenced from another package (dead exports)
+  - public APIs without documentation
+
+Files are re-analyzed on save. Only the saved file's package (Go) or the
+saved file's language (Python, TypeScript) is re-parsed; the full scan
+pipeline and LLM features are never run.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server := lsp.NewServer(cmd.InOrStdin(), cmd.OutOrStdout(), os.Stderr)
+		return server.Run(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
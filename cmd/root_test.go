@@ -6,15 +6,17 @@ import (
 )
 
 func TestRootCommandHasExpectedSubcommands(t *testing.T) {
-	found := false
-	for _, c := range rootCmd.Commands() {
-		if c.Name() == "scan" {
-			found = true
-			break
+	for _, name := range []string{"scan", "lsp", "watch", "cache", "calibrate"} {
+		found := false
+		for _, c := range rootCmd.Commands() {
+			if c.Name() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("root command should have '%s' subcommand", name)
 		}
-	}
-	if !found {
-		t.Error("root command should have 'scan' subcommand")
 	}
 }
 
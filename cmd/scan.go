@@ -1,15 +1,25 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/config"
+	"github.com/ingo-eichhorst/agent-readyness/internal/events"
 	"github.com/ingo-eichhorst/agent-readyness/internal/pipeline"
 	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
 )
 
 var (
@@ -22,6 +32,20 @@ var (
 	baselinePath string // Path to previous JSON for trend comparison
 	badgeOutput  bool   // Generate shields.io badge markdown
 	debugDir     string // C7 response persistence directory
+	noCache      bool   // Disable the per-file results cache
+	llmCacheMode string // LLM response cache mode: off, read or readwrite
+
+	llmBudgetUSD    float64 // Maximum LLM spend in USD (0 = unlimited)
+	llmBudgetTokens int     // Maximum LLM tokens (0 = unlimited)
+	c7Repeats       int     // Runs of every C7 metric, for confidence intervals
+
+	scanTimeout time.Duration // Stop the scan after this long with a partial report (0 = no limit)
+	strict      bool          // Exit non-zero when an analyzer fails
+
+	allowPlugins bool // Run the external plugin commands declared in .arsrc.yml
+
+	eventsFormat string // Progress event stream format: "" (none) or ndjson
+	eventsOut    string // Where events go: stderr, fd:N or a file path
 )
 
 var scanCmd = &cobra.Command{
@@ -75,14 +99,73 @@ Claude CLI is detected. Use --no-llm to disable all LLM features.`,
 				threshold = projectCfg.Scoring.Threshold
 			}
 		}
+		backend, err := projectCfg.AgentBackend()
+		if err != nil {
+			return fmt.Errorf("configure agent backend: %w", err)
+		}
+		judge, err := projectCfg.C4Judge()
+		if err != nil {
+			return fmt.Errorf("configure judge: %w", err)
+		}
+		c7Opts, err := projectCfg.C7MetricOptions(dir)
+		if err != nil {
+			return fmt.Errorf("load C7 tasks: %w", err)
+		}
+		llmMode, err := cache.ParseMode(llmCacheMode)
+		if err != nil {
+			return err
+		}
+		if llmBudgetUSD < 0 || llmBudgetTokens < 0 {
+			return fmt.Errorf("LLM budget must be >= 0")
+		}
+		if c7Repeats < 1 {
+			return fmt.Errorf("--c7-repeats must be >= 1")
+		}
+		c7Opts.Repeats = c7Repeats
+		var plugins []plugin.Plugin
+		if allowPlugins {
+			if plugins, err = projectCfg.LoadPlugins(dir); err != nil {
+				return fmt.Errorf("load plugins: %w", err)
+			}
+		} else if commands := projectCfg.PluginCommands(); len(commands) > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Plugins in .arsrc.yml not run (%s); pass --allow-plugins to run them\n", strings.Join(commands, ", "))
+		}
+		if scanTimeout < 0 {
+			return fmt.Errorf("--timeout must be >= 0")
+		}
+		eventStream, closeEvents, err := openEvents(eventsFormat, eventsOut)
+		if err != nil {
+			return err
+		}
+		defer closeEvents()
+
+		// Ctrl-C, SIGTERM or --timeout stop the scan gracefully: agents are
+		// stopped, C7's worktrees removed and a partial report written. A
+		// second signal terminates immediately.
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if scanTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, scanTimeout)
+			defer cancel()
+		}
+		defer context.AfterFunc(ctx, stop)()
 
 		spinner := pipeline.NewSpinner(os.Stderr)
 		onProgress := func(stage, detail string) {
 			spinner.Update(detail)
 		}
-		spinner.Start("Scanning...")
+		if eventStream == nil || eventsOut != "stderr" {
+			spinner.Start("Scanning...") // events on stderr replace the spinner
+		}
 
 		p := pipeline.New(cmd.OutOrStdout(), verbose, cfg, threshold, jsonOutput, onProgress)
+		if eventStream != nil {
+			p.SetEvents(eventStream.Emit)
+		}
+		if eventStream == nil || eventsOut != "stderr" {
+			p.SetC7Progress(os.Stderr)
+		}
 
 		// Show CLI status and handle LLM feature enablement
 		cliStatus := p.GetCLIStatus()
@@ -100,6 +183,35 @@ Claude CLI is detected. Use --no-llm to disable all LLM features.`,
 			}
 		}
 
+		// Configure the C7 agent backend selected in .arsrc.yml
+		if backend != nil && !noLLM {
+			p.SetAgentBackend(backend)
+			fmt.Fprintf(cmd.OutOrStdout(), "C7 agent backend: %s\n", backend.Name())
+		}
+
+		p.SetStrict(strict)
+		p.SetPlugins(plugins)
+		p.SetPathRules(projectCfg.PathRules())
+		p.SetGeneratedRules(projectCfg.GeneratedRules())
+		p.SetC7MetricOptions(c7Opts)
+		p.SetAgentLimits(projectCfg.AgentLimits())
+
+		// Configure the C4 judge selected in .arsrc.yml
+		if judge != nil && !noLLM {
+			p.SetJudge(judge)
+			fmt.Fprintf(cmd.OutOrStdout(), "C4 judge: %s\n", judge.Info())
+		}
+
+		// Cap LLM spend: C7 plans its samples against what remains
+		if (llmBudgetUSD > 0 || llmBudgetTokens > 0) && !noLLM {
+			budget := agent.NewBudget(llmBudgetUSD, llmBudgetTokens, projectCfg.LLMPrices())
+			if err := budget.CheckPrices(projectCfg.PricedModels()...); err != nil {
+				return fmt.Errorf("LLM budget: %w", err)
+			}
+			p.SetLLMBudget(budget)
+			fmt.Fprintf(cmd.OutOrStdout(), "LLM budget: %s\n", budget)
+		}
+
 		// Configure debug output
 		if debug {
 			p.SetC7Debug(true)
@@ -123,7 +235,24 @@ Claude CLI is detected. Use --no-llm to disable all LLM features.`,
 			p.SetBadgeOutput(true)
 		}
 
-		err = p.Run(dir)
+		// Reuse per-file results of unchanged files from earlier scans
+		if !noCache {
+			if cacheDir, cacheErr := cache.DefaultDir(); cacheErr == nil {
+				p.SetCache(cache.Open(cacheDir, projectCfg.CacheKey()))
+			}
+		}
+
+		// Reuse LLM responses for unchanged samples
+		if llmMode != cache.ModeOff && !noLLM {
+			if cacheDir, cacheErr := cache.DefaultDir(); cacheErr == nil {
+				ttl, maxBytes := projectCfg.LLMCacheLimits()
+				store := cache.OpenLLM(cacheDir, llmMode, ttl, maxBytes)
+				_ = store.Prune() // best-effort, like the cache itself
+				p.SetLLMCache(store)
+			}
+		}
+
+		err = p.Run(ctx, dir)
 		if err != nil {
 			spinner.Stop("") // clear spinner before error
 			return err
@@ -149,9 +278,52 @@ func init() {
 	scanCmd.Flags().StringVar(&baselinePath, "baseline", "", "path to previous JSON output for trend comparison")
 	scanCmd.Flags().BoolVar(&badgeOutput, "badge", false, "generate shields.io badge markdown URL")
 	scanCmd.Flags().StringVar(&debugDir, "debug-dir", "", "directory for C7 response persistence and replay")
+	scanCmd.Flags().BoolVar(&noCache, "no-cache", false, "disable the per-file analysis cache ($XDG_CACHE_HOME/ars)")
+	scanCmd.Flags().StringVar(&llmCacheMode, "llm-cache", "readwrite", "LLM response cache mode: off, read or readwrite ($XDG_CACHE_HOME/ars/llm)")
+	scanCmd.Flags().Float64Var(&llmBudgetUSD, "llm-budget-usd", 0, "maximum LLM spend in USD; C7 runs fewer samples or skips metrics to stay within it")
+	scanCmd.Flags().IntVar(&llmBudgetTokens, "llm-budget-tokens", 0, "maximum LLM tokens; C7 runs fewer samples or skips metrics to stay within it")
+	scanCmd.Flags().IntVar(&c7Repeats, "c7-repeats", 1, "run every C7 metric N times and report mean, standard deviation and 95% confidence intervals")
+	scanCmd.Flags().DurationVar(&scanTimeout, "timeout", 0, "stop the scan after this long (e.g. 10m) and report the categories finished so far; 0 for no limit")
+	scanCmd.Flags().BoolVar(&allowPlugins, "allow-plugins", false, "run the external plugin commands declared in .arsrc.yml (they execute code from the scanned project)")
+	scanCmd.Flags().BoolVar(&strict, "strict", false, "exit with code 3 if an analyzer fails instead of scoring without it")
+	scanCmd.Flags().StringVar(&eventsFormat, "events", "", "write machine-readable progress events; only \"ndjson\" is supported")
+	scanCmd.Flags().StringVar(&eventsOut, "events-out", "stderr", "destination of --events: stderr, fd:N (an inherited file descriptor) or a file path")
 	rootCmd.AddCommand(scanCmd)
 }
 
+// openEvents opens the progress event stream selected by --events and
+// --events-out. Without --events it returns a nil stream.
+func openEvents(format, dest string) (*events.NDJSON, func(), error) {
+	noop := func() {}
+	switch format {
+	case "":
+		return nil, noop, nil
+	case "ndjson":
+	default:
+		return nil, noop, fmt.Errorf("unknown --events format %q (supported: ndjson)", format)
+	}
+
+	if dest == "stderr" {
+		return events.NewNDJSON(os.Stderr), noop, nil
+	}
+	if fd, ok := strings.CutPrefix(dest, "fd:"); ok {
+		n, err := strconv.Atoi(fd)
+		if err != nil || n < 0 {
+			return nil, noop, fmt.Errorf("invalid --events-out file descriptor %q", dest)
+		}
+		f := os.NewFile(uintptr(n), "events")
+		if f == nil {
+			return nil, noop, fmt.Errorf("invalid --events-out file descriptor %q", dest)
+		}
+		return events.NewNDJSON(f), func() { f.Close() }, nil
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, noop, fmt.Errorf("open --events-out: %w", err)
+	}
+	return events.NewNDJSON(f), func() { f.Close() }, nil
+}
+
 // validateProject checks that dir exists, is a directory, and contains recognized source files.
 func validateProject(dir string) error {
 	info, err := os.Stat(dir)
@@ -167,12 +339,14 @@ func validateProject(dir string) error {
 
 	// Check for any recognized project indicator
 	indicators := []string{
-		"go.mod",            // Go
-		"pyproject.toml",    // Python
-		"setup.py",          // Python
-		"requirements.txt",  // Python
-		"tsconfig.json",     // TypeScript
-		"package.json",      // JavaScript/TypeScript
+		"go.mod",              // Go
+		"go.work",             // Go workspace
+		"pyproject.toml",      // Python
+		"setup.py",            // Python
+		"requirements.txt",    // Python
+		"tsconfig.json",       // TypeScript
+		"package.json",        // JavaScript/TypeScript
+		"pnpm-workspace.yaml", // pnpm workspace
 	}
 
 	for _, f := range indicators {
@@ -238,6 +238,7 @@ func resetScanFlags() {
 	badgeOutput = false
 	debugDir = ""
 	verbose = false
+	llmBudgetUSD = 0
 }
 
 // makeMinimalGoProject creates a temp dir with a minimal Go module for scanning.
@@ -294,6 +295,25 @@ func TestScanRunE_ValidProject_NoLLM(t *testing.T) {
 	}
 }
 
+func TestScanRunE_BudgetRefusesUnpricedModel(t *testing.T) {
+	resetScanFlags()
+	defer resetScanFlags()
+	dir := makeMinimalGoProject(t)
+	cfg := "agent:\n  backend: openai\n  model: gpt-5-codex\n"
+	if err := os.WriteFile(filepath.Join(dir, ".arsrc.yml"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"scan", "--llm-budget-usd", "1", dir})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no price known for gpt-5-codex") {
+		t.Fatalf("scan error = %v, want the unpriced model refused", err)
+	}
+}
+
 func TestScanRunE_JSONOutput(t *testing.T) {
 	resetScanFlags()
 	dir := makeMinimalGoProject(t)
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/config"
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/internal/output"
+	"github.com/ingo-eichhorst/agent-readyness/internal/pipeline"
+	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/internal/watch"
+)
+
+var watchDebounce time.Duration
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <directory>",
+	Short: "Re-score a project live as files change",
+	Long: `Watch a project directory and re-score it whenever files change.
+
+Parsed Go packages and Tree-sitter trees stay in memory. A change re-parses
+only the affected package or file and re-runs only the categories that can
+depend on it (e.g. a README edit re-runs C4 only, a commit re-runs C5).
+The dashboard shows each category's score with deltas since the last change
+and since watching began.
+
+C7 (agent evaluation) and LLM-based C4 metrics are never run in watch mode.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			return fmt.Errorf("cannot resolve path: %s", err)
+		}
+		if err := validateProject(dir); err != nil {
+			return err
+		}
+
+		cfg, err := scoring.LoadConfig("")
+		if err != nil {
+			return fmt.Errorf("load scoring config: %w", err)
+		}
+		projectCfg, err := config.LoadProjectConfig(dir, configPath)
+		if err != nil {
+			return fmt.Errorf("load project config: %w", err)
+		}
+		if projectCfg != nil {
+			projectCfg.ApplyToScoringConfig(cfg)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+
+		w := cmd.OutOrStdout()
+		clear := false
+		if f, ok := w.(*os.File); ok {
+			clear = isatty.IsTerminal(f.Fd())
+		}
+
+		inc := pipeline.NewIncremental(dir, cfg)
+		defer inc.Close()
+		inc.SetPathRules(projectCfg.PathRules())
+		inc.SetGeneratedRules(projectCfg.GeneratedRules())
+
+		walker := discovery.NewWalker()
+		walker.SetPathRules(projectCfg.PathRules())
+		watcher, err := watch.New(dir, watchDebounce, walker)
+		if err != nil {
+			return fmt.Errorf("start file watcher: %w", err)
+		}
+		defer watcher.Close()
+
+		fmt.Fprintf(w, "Scanning %s...\n", dir)
+		began := time.Now()
+		start, warnings, err := inc.Analyze(ctx)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		output.RenderWatchDashboard(w, output.WatchFrame{
+			Dir: dir, Current: start, Start: start,
+			Warnings: warnings, Elapsed: time.Since(began), At: time.Now(),
+		}, clear)
+
+		current := start
+		err = watcher.Run(ctx, func(paths []string) {
+			began := time.Now()
+			scored, rerun, warnings, err := inc.Update(ctx, paths)
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if err != nil {
+				fmt.Fprintf(w, "Warning: re-analysis failed: %v\n", err)
+				return
+			}
+			if scored == nil {
+				return // no analyzer reads the changed files
+			}
+			changed := make([]string, 0, len(paths))
+			for _, p := range paths {
+				if rel, relErr := filepath.Rel(dir, p); relErr == nil {
+					changed = append(changed, rel)
+				}
+			}
+			output.RenderWatchDashboard(w, output.WatchFrame{
+				Dir: dir, Current: scored, Start: start, Previous: current,
+				Changed: changed, Rerun: rerun, Warnings: warnings,
+				Elapsed: time.Since(began), At: time.Now(),
+			}, clear)
+			current = scored
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&configPath, "config", "", "path to .arsrc.yml project config file")
+	watchCmd.Flags().DurationVar(&watchDebounce, "debounce", watch.DefaultDebounce, "quiet period after the last change before re-analyzing")
+	rootCmd.AddCommand(watchCmd)
+}
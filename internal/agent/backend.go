@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+// DefaultBackend is the agent backend used when none is configured.
+const DefaultBackend = "claude"
+
+// Backend runs C7 agent prompts against one kind of coding agent.
+type Backend interface {
+	metrics.Executor
+	// Name returns a short label for output, e.g. "claude" or "openai (gpt-4o)".
+	Name() string
+	// Check reports whether the backend is usable, e.g. that its CLI is installed.
+	Check() error
+}
+
+// BackendConfig selects and configures an agent backend (agent section of .arsrc.yml).
+type BackendConfig struct {
+	Backend   string   // registry name: "claude", "openai" or "command"; empty means DefaultBackend
+	Model     string   // model name; optional for claude and command, required for openai
+	BaseURL   string   // openai: chat-completions API base URL
+	APIKeyEnv string   // openai: environment variable holding the API key
+	MaxTurns  int      // openai: maximum tool-use round trips per prompt
+	Command   []string // command: argv template with {prompt}, {tools}, {workdir} and {model} placeholders
+}
+
+// backendFactory creates a Backend from its configuration.
+type backendFactory func(cfg BackendConfig) (Backend, error)
+
+// backends is the registry of agent backends by name.
+var backends = map[string]backendFactory{
+	"claude":  newClaudeBackend,
+	"openai":  newOpenAIBackend,
+	"command": newCommandBackend,
+}
+
+// BackendNames returns the names of all registered backends, sorted.
+func BackendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewBackend creates the backend selected by cfg.Backend.
+func NewBackend(cfg BackendConfig) (Backend, error) {
+	name := cfg.Backend
+	if name == "" {
+		name = DefaultBackend
+	}
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown agent backend %q (available: %s)", name, strings.Join(BackendNames(), ", "))
+	}
+	return factory(cfg)
+}
+
+// claudeBackend runs prompts through the Claude CLI in headless mode.
+type claudeBackend struct {
+	model string
+}
+
+func newClaudeBackend(cfg BackendConfig) (Backend, error) {
+	return &claudeBackend{model: cfg.Model}, nil
+}
+
+func (b *claudeBackend) Name() string {
+	if b.model != "" {
+		return "claude (" + b.model + ")"
+	}
+	return "claude"
+}
+
+// resolvedModel implements modelResolver.
+func (b *claudeBackend) resolvedModel(workDir string) string {
+	return claudeModel(b.model, workDir)
+}
+
+func (b *claudeBackend) Check() error {
+	return CheckClaudeCLI()
+}
+
+func (b *claudeBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	return (&cliExecutorAdapter{workDir: workDir, model: b.model}).ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+}
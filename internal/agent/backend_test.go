@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+func TestNewBackend(t *testing.T) {
+	b, err := NewBackend(BackendConfig{})
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+	if b.Name() != "claude" {
+		t.Errorf("default backend = %q, want claude", b.Name())
+	}
+
+	if _, err := NewBackend(BackendConfig{Backend: "gemini"}); err == nil || !strings.Contains(err.Error(), "available: claude, command, openai") {
+		t.Errorf("unknown backend error = %v", err)
+	}
+	if _, err := NewBackend(BackendConfig{Backend: "openai"}); err == nil {
+		t.Error("expected error for openai backend without model")
+	}
+	if _, err := NewBackend(BackendConfig{Backend: "command", Command: []string{"aider"}}); err == nil {
+		t.Error("expected error for command template without {prompt}")
+	}
+}
+
+// fakeChatServer serves chat completions: the first request asks for a Read
+// tool call, the second answers with the content of the tool result.
+func fakeChatServer(t *testing.T, requests *[]chatRequest) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var req chatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		*requests = append(*requests, req)
+
+		var msg chatMessage
+		msg.Role = "assistant"
+		last := req.Messages[len(req.Messages)-1]
+		if last.Role == "tool" {
+			msg.Content = "The file says: " + strings.TrimSpace(last.Content)
+		} else {
+			call := chatToolCall{ID: "call_1", Type: "function"}
+			call.Function.Name = "Read"
+			call.Function.Arguments = `{"file_path":"README.md"}`
+			msg.ToolCalls = []chatToolCall{call}
+		}
+		resp := map[string]any{"choices": []map[string]any{{"message": msg, "finish_reason": "stop"}}}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestOpenAIBackend_ToolLoop(t *testing.T) {
+	var requests []chatRequest
+	srv := fakeChatServer(t, &requests)
+	defer srv.Close()
+	t.Setenv("TEST_AGENT_KEY", "test-key")
+
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "README.md"), []byte("hello agents\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewBackend(BackendConfig{Backend: "openai", Model: "test-model", BaseURL: srv.URL + "/", APIKeyEnv: "TEST_AGENT_KEY"})
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+	if err := b.Check(); err != nil {
+		t.Errorf("Check() error: %v", err)
+	}
+
+	var trace []metrics.ToolCall
+	ctx := metrics.WithToolCallRecorder(context.Background(), func(calls []metrics.ToolCall) { trace = calls })
+	out, err := b.ExecutePrompt(ctx, workDir, "What does the README say?", "Read,Glob,Grep", 10*time.Second)
+	if err != nil {
+		t.Fatalf("ExecutePrompt() error: %v", err)
+	}
+	if want := "The file says: 1\thello agents"; out != want {
+		t.Errorf("ExecutePrompt() = %q, want %q", out, want)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	if requests[0].Model != "test-model" || len(requests[0].Tools) != 3 {
+		t.Errorf("first request: model %q, %d tools", requests[0].Model, len(requests[0].Tools))
+	}
+	if len(trace) != 1 || trace[0].Tool != "Read" || trace[0].Path != "README.md" || trace[0].Error {
+		t.Errorf("tool-call trace = %+v, want one successful Read of README.md", trace)
+	}
+}
+
+func TestOpenAIBackend_TraceWithoutFinalAnswer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := chatToolCall{ID: "call_1", Type: "function"}
+		call.Function.Name = "Read"
+		call.Function.Arguments = `{"file_path":"README.md"}`
+		msg := chatMessage{Role: "assistant", ToolCalls: []chatToolCall{call}}
+		json.NewEncoder(w).Encode(map[string]any{"choices": []map[string]any{{"message": msg, "finish_reason": "tool_calls"}}})
+	}))
+	defer srv.Close()
+
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "README.md"), []byte("hello agents\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBackend(BackendConfig{Backend: "openai", Model: "m", BaseURL: srv.URL, MaxTurns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trace []metrics.ToolCall
+	ctx := metrics.WithToolCallRecorder(context.Background(), func(calls []metrics.ToolCall) { trace = calls })
+	if _, err := b.ExecutePrompt(ctx, workDir, "hi", "Read", 10*time.Second); err == nil {
+		t.Fatal("ExecutePrompt() error = nil, want no final answer")
+	}
+	if len(trace) != 2 || trace[0].Tool != "Read" || trace[1].Tool != "Read" {
+		t.Errorf("tool-call trace = %+v, want the two Reads made before giving up", trace)
+	}
+}
+
+func TestOpenAIBackend_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	b, err := NewBackend(BackendConfig{Backend: "openai", Model: "m", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.ExecutePrompt(context.Background(), t.TempDir(), "hi", "Read", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "HTTP 429") || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("ExecutePrompt() error = %v, want HTTP 429 with body", err)
+	}
+}
+
+func TestOpenAIBackend_CheckRequiresKeyForPublicAPI(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	b, err := NewBackend(BackendConfig{Backend: "openai", Model: "gpt-4o"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Check(); err == nil {
+		t.Error("expected Check() error without OPENAI_API_KEY")
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	workDir := t.TempDir()
+	b, err := NewBackend(BackendConfig{
+		Backend: "command",
+		Model:   "m1",
+		Command: []string{"sh", "-c", `echo "$0 [$1] $(basename "$PWD")"`, "{prompt}", "{tools}/{model}"},
+	})
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+	if err := b.Check(); err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+
+	out, err := b.ExecutePrompt(context.Background(), workDir, "explain main.go", "Read,Grep", 10*time.Second)
+	if err != nil {
+		t.Fatalf("ExecutePrompt() error: %v", err)
+	}
+	if want := "explain main.go [Read,Grep/m1] " + filepath.Base(workDir); out != want {
+		t.Errorf("ExecutePrompt() = %q, want %q", out, want)
+	}
+
+	failing, _ := NewBackend(BackendConfig{Backend: "command", Command: []string{"sh", "-c", "echo boom >&2; exit 3", "{prompt}"}})
+	if _, err := failing.ExecutePrompt(context.Background(), workDir, "x", "", time.Second); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("ExecutePrompt() error = %v, want stderr in error", err)
+	}
+}
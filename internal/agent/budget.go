@@ -0,0 +1,450 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Budget estimation constants.
+const (
+	charsPerToken       = 4      // approximate characters per token for backends that report no usage
+	estimatedCallInput  = 20_000 // input tokens of an agent call before any has been measured
+	estimatedCallOutput = 1_000  // output tokens of an agent call before any has been measured
+)
+
+// ErrBudgetExhausted is returned for LLM calls refused because the budget is spent.
+var ErrBudgetExhausted = errors.New("LLM budget exhausted")
+
+// Price is a model's API price in USD per million tokens.
+type Price struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// PriceTable maps model names to prices. A model matches its own entry or,
+// failing that, the entry it extends with a snapshot suffix: a date
+// ("-20250929", "-2024-08-06", "@20250929"), "-latest" or a ":tag". Other
+// suffixes are not stripped, so "claude-opus-4-6" does not take the price of
+// "claude-opus-4": a new model version has no price until one is added.
+type PriceTable map[string]Price
+
+// DefaultPrices are the built-in list prices. Entries in .arsrc.yml's
+// pricing section are added to or override them.
+var DefaultPrices = PriceTable{
+	"claude-opus-4":     {InputPerMTok: 15, OutputPerMTok: 75},
+	"claude-opus-4-0":   {InputPerMTok: 15, OutputPerMTok: 75},
+	"claude-opus-4-1":   {InputPerMTok: 15, OutputPerMTok: 75},
+	"claude-opus-4-5":   {InputPerMTok: 5, OutputPerMTok: 25},
+	"claude-sonnet-4":   {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-sonnet-4-0": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-sonnet-4-5": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-haiku-4-5":  {InputPerMTok: 1, OutputPerMTok: 5},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4},
+	"opus":              {InputPerMTok: 5, OutputPerMTok: 25},
+	"sonnet":            {InputPerMTok: 3, OutputPerMTok: 15},
+	"haiku":             {InputPerMTok: 1, OutputPerMTok: 5},
+	"gpt-4o":            {InputPerMTok: 2.5, OutputPerMTok: 10},
+	"gpt-4o-mini":       {InputPerMTok: 0.15, OutputPerMTok: 0.6},
+	"gpt-4.1":           {InputPerMTok: 2, OutputPerMTok: 8},
+	"gpt-4.1-mini":      {InputPerMTok: 0.4, OutputPerMTok: 1.6},
+}
+
+// snapshotSuffix matches what may follow a priced model name in the ID of
+// the same model (see PriceTable).
+var snapshotSuffix = regexp.MustCompile(`^([-@]\d{8}|-\d{4}-\d{2}-\d{2}|-latest|:.+)$`)
+
+// fallbackPrice prices usage the backend reports without a model (estimated
+// command backend usage) at the blended rate the progress display estimates
+// with.
+var fallbackPrice = Price{InputPerMTok: costRatePerMTok, OutputPerMTok: costRatePerMTok}
+
+// Lookup returns the price of model and whether the table has one. An empty
+// model gets the fallback price.
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if model == "" {
+		return fallbackPrice, true
+	}
+	if p, ok := t[model]; ok {
+		return p, true
+	}
+	for name, p := range t {
+		if rest, ok := strings.CutPrefix(model, name); ok && snapshotSuffix.MatchString(rest) {
+			return p, true
+		}
+	}
+	return Price{}, false
+}
+
+// Cost returns the cost of u: the backend's own figure if it reported one,
+// otherwise its tokens at the model's price. ok is false if neither is known.
+func (t PriceTable) Cost(u metrics.Usage) (usd float64, ok bool) {
+	if u.CostUSD > 0 {
+		return u.CostUSD, true
+	}
+	p, ok := t.Lookup(u.Model)
+	if !ok {
+		return 0, false
+	}
+	return (float64(u.InputTokens)*p.InputPerMTok + float64(u.OutputTokens)*p.OutputPerMTok) / tokensPerMillion, true
+}
+
+// Unpriced returns the models, sorted, that t has no price for.
+func (t PriceTable) Unpriced(models ...string) []string {
+	var unpriced []string
+	for _, m := range models {
+		if _, ok := t.Lookup(m); !ok && !slices.Contains(unpriced, m) {
+			unpriced = append(unpriced, m)
+		}
+	}
+	sort.Strings(unpriced)
+	return unpriced
+}
+
+// Budget meters LLM usage and enforces optional cost and token limits. It is
+// shared by every analyzer calling an LLM and safe for concurrent use. Calls
+// already in flight when a limit is reached still complete, so spend can
+// exceed the limit by at most the calls running in parallel. A nil *Budget
+// meters and limits nothing.
+//
+// Usage of a model without a price is counted in tokens only. With a cost
+// limit that makes the spend unknown, so the budget refuses further calls
+// instead of guessing, and reports the model.
+type Budget struct {
+	maxUSD    float64
+	maxTokens int
+	prices    PriceTable
+
+	mu       sync.Mutex
+	spentUSD float64
+	tokens   int
+	calls    int
+	dropped  int
+	refused  int
+	skipped  []string
+	unpriced []string
+}
+
+// NewBudget returns a budget with the given limits; zero means no limit.
+// prices are merged over DefaultPrices.
+func NewBudget(maxUSD float64, maxTokens int, prices PriceTable) *Budget {
+	table := make(PriceTable, len(DefaultPrices)+len(prices))
+	for name, p := range DefaultPrices {
+		table[name] = p
+	}
+	for name, p := range prices {
+		table[name] = p
+	}
+	return &Budget{maxUSD: maxUSD, maxTokens: maxTokens, prices: table}
+}
+
+// CheckPrices returns an error if the budget has a cost limit and no price for
+// one of models, the models a scan is configured to call, so a scan is not
+// started against a limit it cannot enforce.
+func (b *Budget) CheckPrices(models ...string) error {
+	if b == nil || b.maxUSD <= 0 {
+		return nil
+	}
+	if unpriced := b.prices.Unpriced(models...); len(unpriced) > 0 {
+		return fmt.Errorf("no price known for %s; add it to the pricing section of .arsrc.yml", strings.Join(unpriced, ", "))
+	}
+	return nil
+}
+
+// meter returns an unlimited budget with b's prices, for metering part of a
+// run on its own.
+func (b *Budget) meter() *Budget {
+	if b == nil {
+		return NewBudget(0, 0, nil)
+	}
+	return &Budget{prices: b.prices}
+}
+
+// Add records the usage of one LLM call.
+func (b *Budget) Add(u metrics.Usage) {
+	if b == nil {
+		return
+	}
+	cost, priced := b.prices.Cost(u)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !priced && !slices.Contains(b.unpriced, u.Model) {
+		b.unpriced = append(b.unpriced, u.Model)
+	}
+	b.spentUSD += cost
+	b.tokens += u.Tokens()
+	b.calls++
+}
+
+// Spent returns the cost and tokens recorded so far.
+func (b *Budget) Spent() (usd float64, tokens int) {
+	if b == nil {
+		return 0, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spentUSD, b.tokens
+}
+
+// Exhausted reports whether a limit has been reached.
+func (b *Budget) Exhausted() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.exhaustedLocked()
+}
+
+func (b *Budget) exhaustedLocked() bool {
+	return (b.maxUSD > 0 && (b.spentUSD >= b.maxUSD || len(b.unpriced) > 0)) || (b.maxTokens > 0 && b.tokens >= b.maxTokens)
+}
+
+// Skip records that work was skipped to stay within the budget, e.g.
+// "C7 change_success".
+func (b *Budget) Skip(what string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.skipped = append(b.skipped, what)
+}
+
+// refuse counts a call refused because the budget was exhausted.
+func (b *Budget) refuse() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.exhaustedLocked() {
+		return nil
+	}
+	b.refused++
+	return ErrBudgetExhausted
+}
+
+// Report summarizes usage and cuts for the scored result. It returns nil for
+// a budget without limits.
+func (b *Budget) Report() *types.LLMBudget {
+	if b == nil || (b.maxUSD <= 0 && b.maxTokens <= 0) {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &types.LLMBudget{
+		LimitUSD:       b.maxUSD,
+		LimitTokens:    b.maxTokens,
+		SpentUSD:       b.spentUSD,
+		TokensUsed:     b.tokens,
+		Exhausted:      b.exhaustedLocked() || len(b.skipped) > 0,
+		DroppedSamples: b.dropped,
+		RefusedCalls:   b.refused,
+		Skipped:        append([]string(nil), b.skipped...),
+		UnpricedModels: append([]string(nil), b.unpriced...),
+	}
+}
+
+// affordableCalls estimates how many more agent calls fit the remaining
+// budget, from the average call so far or, before any, a typical agent call
+// at the fallback price. It returns math.MaxInt without limits.
+func (b *Budget) affordableCalls() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	callUSD := fallbackPrice.InputPerMTok*estimatedCallInput/tokensPerMillion + fallbackPrice.OutputPerMTok*estimatedCallOutput/tokensPerMillion
+	callTokens := float64(estimatedCallInput + estimatedCallOutput)
+	if b.calls > 0 {
+		callUSD = b.spentUSD / float64(b.calls)
+		callTokens = float64(b.tokens) / float64(b.calls)
+	}
+
+	calls := math.MaxInt
+	if b.maxUSD > 0 && callUSD > 0 {
+		calls = min(calls, int(math.Max(b.maxUSD-b.spentUSD, 0)/callUSD))
+	}
+	if b.maxTokens > 0 && callTokens > 0 {
+		calls = min(calls, int(math.Max(float64(b.maxTokens-b.tokens), 0)/callTokens))
+	}
+	return calls
+}
+
+// Plan fits the C7 sample lists into the remaining budget. counts holds each
+// metric's number of samples and perSample its agent calls per sample. First
+// samples are dropped from the metrics with the most, down to one each; if
+// that is not enough, whole metrics are skipped from the end of the run
+// order. It returns the sample count to run per metric, 0 for skipped ones.
+func (b *Budget) Plan(counts, perSample []int) []int {
+	planned := append([]int(nil), counts...)
+	if b == nil {
+		return planned
+	}
+	affordable := b.affordableCalls()
+	total := 0
+	for i, n := range planned {
+		total += n * perSample[i]
+	}
+
+	dropped := 0
+	for total > affordable {
+		largest := -1
+		for i, n := range planned {
+			if n > 1 && (largest < 0 || n >= planned[largest]) {
+				largest = i
+			}
+		}
+		if largest < 0 {
+			break
+		}
+		planned[largest]--
+		total -= perSample[largest]
+		dropped++
+	}
+	for i := len(planned) - 1; i >= 0 && total > affordable; i-- {
+		total -= planned[i] * perSample[i]
+		dropped += planned[i]
+		planned[i] = 0
+	}
+
+	b.mu.Lock()
+	b.dropped += dropped
+	b.mu.Unlock()
+	return planned
+}
+
+// skippedMetric reports whether Plan skipped the C7 metric with this ID.
+func (b *Budget) skippedMetric(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.skipped {
+		if s == "C7 "+id {
+			return true
+		}
+	}
+	return false
+}
+
+// noteRefused counts the samples of mr whose agent call the budget refused as
+// dropped, and marks mr skipped if that was all of them.
+func (b *Budget) noteRefused(mr *metrics.MetricResult) {
+	if b == nil {
+		return
+	}
+	refused := 0
+	for _, s := range mr.Samples {
+		if strings.Contains(s.Error, ErrBudgetExhausted.Error()) {
+			refused++
+		}
+	}
+	if refused == 0 {
+		return
+	}
+	b.mu.Lock()
+	b.dropped += refused
+	b.mu.Unlock()
+	if refused == len(mr.Samples) {
+		mr.Skipped = ErrBudgetExhausted.Error()
+		b.Skip("C7 " + mr.MetricID)
+	}
+}
+
+// budgetBackend refuses agent calls once the budget is exhausted and records
+// the usage of the others. Usage the backend does not report is estimated
+// from the prompt and response length.
+type budgetBackend struct {
+	Backend
+	budget *Budget
+}
+
+// NewBudgetBackend wraps b to meter and limit its calls with budget. A nil
+// budget returns b unchanged.
+func NewBudgetBackend(b Backend, budget *Budget) Backend {
+	if budget == nil {
+		return b
+	}
+	return &budgetBackend{Backend: b, budget: budget}
+}
+
+// unwrap implements wrapper.
+func (b *budgetBackend) unwrap() any { return b.Backend }
+
+// ExecutePrompt implements metrics.Executor.
+func (b *budgetBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	if err := b.budget.refuse(); err != nil {
+		return "", err
+	}
+	ctx, reported := meterCall(ctx, b.budget)
+	response, err := b.Backend.ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+	if !*reported {
+		metrics.RecordUsage(ctx, estimateUsage(prompt, response))
+	}
+	return response, err
+}
+
+// budgetJudge is the Judge counterpart of budgetBackend.
+type budgetJudge struct {
+	Judge
+	budget *Budget
+}
+
+// NewBudgetJudge wraps j to meter and limit its calls with budget. A nil
+// budget or judge returns j unchanged.
+func NewBudgetJudge(j Judge, budget *Budget) Judge {
+	if budget == nil || j == nil {
+		return j
+	}
+	return &budgetJudge{Judge: j, budget: budget}
+}
+
+// unwrap implements wrapper.
+func (j *budgetJudge) unwrap() any { return j.Judge }
+
+// EvaluateContent implements Judge.
+func (j *budgetJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
+	if err := j.budget.refuse(); err != nil {
+		return EvaluationResult{}, err
+	}
+	ctx, reported := meterCall(ctx, j.budget)
+	result, err := j.Judge.EvaluateContent(ctx, systemPrompt, content)
+	if !*reported {
+		metrics.RecordUsage(ctx, estimateUsage(systemPrompt+content, result.Reason))
+	}
+	return result, err
+}
+
+// meterCall returns a context recording usage in budget and a flag set once
+// the backend has reported usage itself.
+func meterCall(ctx context.Context, budget *Budget) (context.Context, *bool) {
+	reported := new(bool)
+	return metrics.WithUsageRecorder(ctx, func(u metrics.Usage) {
+		*reported = true
+		budget.Add(u)
+	}), reported
+}
+
+// estimateUsage approximates the usage of a call from its text length.
+func estimateUsage(input, output string) metrics.Usage {
+	return metrics.Usage{InputTokens: len(input) / charsPerToken, OutputTokens: len(output) / charsPerToken}
+}
+
+// String describes the limits, e.g. "$5.00" or "$5.00, 2000000 tokens".
+func (b *Budget) String() string {
+	var parts []string
+	if b.maxUSD > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f", b.maxUSD))
+	}
+	if b.maxTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens", b.maxTokens))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestPriceTable(t *testing.T) {
+	prices := NewBudget(0, 0, PriceTable{"qwen2.5-coder": {InputPerMTok: 0, OutputPerMTok: 0}}).prices
+
+	tests := []struct {
+		model string
+		want  Price
+		ok    bool
+	}{
+		{"claude-sonnet-4-5-20250929", DefaultPrices["claude-sonnet-4-5"], true},
+		{"claude-opus-4-5", DefaultPrices["claude-opus-4-5"], true},
+		{"claude-opus-4-20250514", DefaultPrices["claude-opus-4"], true},
+		{"claude-opus-4-1-20250805", DefaultPrices["claude-opus-4-1"], true},
+		{"claude-3-5-haiku-latest", DefaultPrices["claude-3-5-haiku"], true},
+		{"claude-opus-4-5@20251101", DefaultPrices["claude-opus-4-5"], true},
+		{"gpt-4o-mini", DefaultPrices["gpt-4o-mini"], true},
+		{"gpt-4o-2024-08-06", DefaultPrices["gpt-4o"], true},
+		{"qwen2.5-coder:7b", Price{}, true},
+		{"", fallbackPrice, true},
+		{"claude-opus-4-6", Price{}, false}, // a newer version, not a snapshot of claude-opus-4
+		{"gpt-4o-audio-preview", Price{}, false},
+		{"unknown-model", Price{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := prices.Lookup(tt.model); got != tt.want || ok != tt.ok {
+			t.Errorf("Lookup(%q) = %+v, %v, want %+v, %v", tt.model, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	cost, ok := prices.Cost(metrics.Usage{Model: "claude-sonnet-4", InputTokens: 1_000_000, OutputTokens: 100_000})
+	if !ok || math.Abs(cost-4.5) > 1e-9 {
+		t.Errorf("Cost() = %v, %v, want 4.5 (3 input + 1.5 output)", cost, ok)
+	}
+	if cost, ok := prices.Cost(metrics.Usage{Model: "claude-opus-4-6", InputTokens: 10, CostUSD: 0.25}); !ok || cost != 0.25 {
+		t.Errorf("Cost() = %v, %v, want the reported 0.25", cost, ok)
+	}
+	if _, ok := prices.Cost(metrics.Usage{Model: "claude-opus-4-6", InputTokens: 10}); ok {
+		t.Error("Cost() of an unpriced model without a reported cost should not be known")
+	}
+	if got := prices.Unpriced("gpt-4o", "new-model", "claude-opus-4-6", "new-model"); !reflect.DeepEqual(got, []string{"claude-opus-4-6", "new-model"}) {
+		t.Errorf("Unpriced() = %v", got)
+	}
+}
+
+func TestBudget_UnpricedModel(t *testing.T) {
+	if err := NewBudget(1, 0, nil).CheckPrices("gpt-4o", "gpt-5-codex"); err == nil || !strings.Contains(err.Error(), "gpt-5-codex") {
+		t.Errorf("CheckPrices() error = %v, want one naming gpt-5-codex", err)
+	}
+	if err := NewBudget(0, 1000, nil).CheckPrices("gpt-5-codex"); err != nil {
+		t.Errorf("CheckPrices() without a cost limit = %v, want nil", err)
+	}
+	if err := NewBudget(1, 0, PriceTable{"gpt-5-codex": {InputPerMTok: 1, OutputPerMTok: 10}}).CheckPrices("gpt-5-codex"); err != nil {
+		t.Errorf("CheckPrices() with a configured price = %v, want nil", err)
+	}
+
+	// Usage of an unpriced model counts its tokens but makes the spend
+	// unknown, so a cost limit refuses further calls.
+	budget := NewBudget(5, 0, nil)
+	b := NewBudgetBackend(&usageBackend{usage: &metrics.Usage{Model: "gpt-5-codex", InputTokens: 100, OutputTokens: 10}}, budget)
+	if _, err := b.ExecutePrompt(context.Background(), "", "prompt", "Read", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if usd, tokens := budget.Spent(); usd != 0 || tokens != 110 {
+		t.Errorf("Spent() = $%v, %d tokens, want $0, 110", usd, tokens)
+	}
+	if _, err := b.ExecutePrompt(context.Background(), "", "prompt", "Read", time.Minute); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("ExecutePrompt() error = %v, want ErrBudgetExhausted", err)
+	}
+	if report := budget.Report(); !report.Exhausted || !reflect.DeepEqual(report.UnpricedModels, []string{"gpt-5-codex"}) {
+		t.Errorf("Report() = %+v, want exhausted with gpt-5-codex unpriced", report)
+	}
+
+	// A token limit alone does not need prices.
+	tokensOnly := NewBudget(0, 1000, nil)
+	NewBudgetBackend(&usageBackend{usage: &metrics.Usage{Model: "gpt-5-codex", InputTokens: 100}}, tokensOnly).ExecutePrompt(context.Background(), "", "prompt", "Read", time.Minute)
+	if tokensOnly.Exhausted() {
+		t.Error("token-only budget should not be exhausted by an unpriced model")
+	}
+}
+
+func TestBudget_Plan(t *testing.T) {
+	// Before any call, an agent call is estimated at 21k tokens.
+	callTokens := estimatedCallInput + estimatedCallOutput
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		counts    []int
+		perSample []int
+		want      []int
+		dropped   int
+	}{
+		{"fits", 100 * callTokens, []int{3, 5, 1}, []int{3, 1, 1}, []int{3, 5, 1}, 0},
+		{"drop samples from the largest", 9 * callTokens, []int{2, 5, 3}, []int{1, 1, 1}, []int{2, 4, 3}, 1},
+		{"down to one each", 3 * callTokens, []int{2, 5, 3}, []int{1, 1, 1}, []int{1, 1, 1}, 7},
+		{"then skip from the end", 2 * callTokens, []int{2, 5, 3}, []int{1, 1, 1}, []int{1, 1, 0}, 8},
+		{"calls per sample", 5 * callTokens, []int{1, 3}, []int{3, 1}, []int{1, 2}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBudget(0, tt.maxTokens, nil)
+			if got := b.Plan(tt.counts, tt.perSample); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan() = %v, want %v", got, tt.want)
+			}
+			if b.dropped != tt.dropped {
+				t.Errorf("dropped = %d, want %d", b.dropped, tt.dropped)
+			}
+		})
+	}
+
+	var unlimited *Budget
+	if got := unlimited.Plan([]int{4}, []int{1}); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("nil budget Plan() = %v, want unchanged", got)
+	}
+}
+
+// usageBackend reports fixed usage for every call, like the Claude CLI.
+type usageBackend struct {
+	usage *metrics.Usage // nil reports none, like a command backend
+	calls int
+}
+
+func (b *usageBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	b.calls++
+	if b.usage != nil {
+		metrics.RecordUsage(ctx, *b.usage)
+	}
+	return "0123456789abcdef", nil
+}
+
+func (b *usageBackend) Name() string { return "usage" }
+func (b *usageBackend) Check() error { return nil }
+
+func TestBudgetBackend(t *testing.T) {
+	budget := NewBudget(1, 0, nil)
+	inner := &usageBackend{usage: &metrics.Usage{InputTokens: 100, OutputTokens: 10, CostUSD: 0.6}}
+	b := NewBudgetBackend(inner, budget)
+
+	var seen int
+	ctx := metrics.WithUsageRecorder(context.Background(), func(u metrics.Usage) { seen += u.Tokens() })
+	for range 2 {
+		if _, err := b.ExecutePrompt(ctx, "", "prompt", "Read", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if usd, tokens := budget.Spent(); math.Abs(usd-1.2) > 1e-9 || tokens != 220 {
+		t.Errorf("Spent() = $%v, %d tokens, want $1.20, 220", usd, tokens)
+	}
+	if seen != 220 {
+		t.Errorf("outer recorder saw %d tokens, want 220", seen)
+	}
+
+	// The limit is reached: further calls are refused without running.
+	if _, err := b.ExecutePrompt(ctx, "", "prompt", "Read", time.Minute); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("ExecutePrompt() error = %v, want ErrBudgetExhausted", err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("backend calls = %d, want 2", inner.calls)
+	}
+	report := budget.Report()
+	if !report.Exhausted || report.RefusedCalls != 1 {
+		t.Errorf("Report() = %+v, want exhausted with 1 refused call", report)
+	}
+
+	// Backends without usage reports are estimated from text length.
+	estimated := NewBudget(0, 1000, nil)
+	NewBudgetBackend(&usageBackend{}, estimated).ExecutePrompt(context.Background(), "", "0123456789ab", "Read", time.Minute)
+	if _, tokens := estimated.Spent(); tokens != 3+4 {
+		t.Errorf("estimated tokens = %d, want 7", tokens)
+	}
+}
+
+// countMetric selects count samples and asks the agent once per sample.
+type countMetric struct {
+	id    string
+	count int
+}
+
+func (m *countMetric) ID() string             { return m.id }
+func (m *countMetric) Name() string           { return m.id }
+func (m *countMetric) Description() string    { return m.id }
+func (m *countMetric) Timeout() time.Duration { return time.Minute }
+func (m *countMetric) SampleCount() int       { return m.count }
+
+func (m *countMetric) SelectSamples(targets []*types.AnalysisTarget) []metrics.Sample {
+	return make([]metrics.Sample, m.count)
+}
+
+func (m *countMetric) Execute(ctx context.Context, workDir string, samples []metrics.Sample, executor metrics.Executor) metrics.MetricResult {
+	result := metrics.MetricResult{MetricID: m.id, MetricName: m.id, Score: 5}
+	for range samples {
+		sr := metrics.SampleResult{Score: 5}
+		if _, err := executor.ExecutePrompt(ctx, workDir, "prompt", "Read", time.Minute); err != nil {
+			sr.Error = err.Error()
+		}
+		result.Samples = append(result.Samples, sr)
+	}
+	return result
+}
+
+func TestRunMetrics_Budget(t *testing.T) {
+	callTokens := estimatedCallInput + estimatedCallOutput
+	budget := NewBudget(0, 2*callTokens, nil)
+	inner := &usageBackend{usage: &metrics.Usage{InputTokens: 50, OutputTokens: 50}}
+
+	result := RunMetricsSequential(context.Background(), t.TempDir(), nil, nil, NewBudgetBackend(inner, budget), RunOptions{
+		Metrics: []metrics.Metric{&countMetric{id: "a", count: 3}, &countMetric{id: "b", count: 1}, &countMetric{id: "c", count: 2}},
+		Budget:  budget,
+	})
+
+	if n := len(result.Results[0].Samples); n != 1 {
+		t.Errorf("metric a ran %d samples, want 1", n)
+	}
+	if result.Results[0].TokensUsed != 100 || result.TotalTokens != 200 {
+		t.Errorf("tokens = %d (metric a), %d (total), want 100, 200", result.Results[0].TokensUsed, result.TotalTokens)
+	}
+	if got := result.Results[2]; got.Skipped == "" || len(got.Samples) != 0 {
+		t.Errorf("metric c = %+v, want skipped without samples", got)
+	}
+
+	report := budget.Report()
+	if report.DroppedSamples != 4 || !reflect.DeepEqual(report.Skipped, []string{"C7 c"}) {
+		t.Errorf("Report() = %+v, want 4 dropped samples and C7 c skipped", report)
+	}
+}
@@ -2,7 +2,10 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -102,3 +105,63 @@ func resetCLICache() {
 	cliStatusOnce = sync.Once{}
 	cachedCLIStatus = CLIStatus{}
 }
+
+// claudeModel returns the model the Claude CLI runs with in dir when passed
+// model ("" for none): model itself, else the CLI's own setting from
+// ANTHROPIC_MODEL or the "model" key of the project's local and shared
+// settings or the user's settings, in that order. Without any, the CLI picks
+// a default that changes between releases, so the result names the
+// installed CLI version instead, e.g. "default (claude 2.1.12)".
+func claudeModel(model, dir string) string {
+	if model != "" {
+		return model
+	}
+	if env := os.Getenv("ANTHROPIC_MODEL"); env != "" {
+		return env
+	}
+	settings := []string{
+		filepath.Join(dir, ".claude", "settings.local.json"),
+		filepath.Join(dir, ".claude", "settings.json"),
+	}
+	if configDir := claudeConfigDir(); configDir != "" {
+		settings = append(settings, filepath.Join(configDir, "settings.json"))
+	}
+	for _, path := range settings {
+		if m := settingsModel(path); m != "" {
+			return m
+		}
+	}
+	if version := GetCLIStatus().Version; version != "" {
+		return "default (" + version + ")"
+	}
+	return "default"
+}
+
+// claudeConfigDir returns the Claude CLI's user configuration directory:
+// $CLAUDE_CONFIG_DIR or ~/.claude, or "" if neither can be determined.
+func claudeConfigDir() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude")
+}
+
+// settingsModel returns the "model" key of a Claude CLI settings file, or ""
+// if the file is missing, unreadable or sets no model.
+func settingsModel(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var settings struct {
+		Model string `json:"model"`
+	}
+	if json.Unmarshal(data, &settings) != nil {
+		return ""
+	}
+	return settings.Model
+}
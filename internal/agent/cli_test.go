@@ -3,7 +3,9 @@ package agent
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 )
 
@@ -154,3 +156,53 @@ func containsSubstr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestClaudeModel(t *testing.T) {
+	origLookPath := lookPathFunc
+	origRunVersion := runVersionCmd
+	defer func() {
+		lookPathFunc = origLookPath
+		runVersionCmd = origRunVersion
+		resetCLICache()
+	}()
+	lookPathFunc = func(file string) (string, error) { return "/usr/local/bin/claude", nil }
+	runVersionCmd = func(ctx context.Context, path string) ([]byte, error) { return []byte("claude 2.1.12"), nil }
+	resetCLICache()
+
+	project := t.TempDir()
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	t.Setenv("ANTHROPIC_MODEL", "")
+	writeSettings := func(path, model string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{"model": "`+model+`"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := claudeModel("", project); got != "default (claude 2.1.12)" {
+		t.Errorf("no setting: claudeModel() = %q, want the CLI version's default", got)
+	}
+	writeSettings(filepath.Join(configDir, "settings.json"), "opus")
+	if got := claudeModel("", project); got != "opus" {
+		t.Errorf("user settings: claudeModel() = %q, want opus", got)
+	}
+	writeSettings(filepath.Join(project, ".claude", "settings.json"), "sonnet")
+	if got := claudeModel("", project); got != "sonnet" {
+		t.Errorf("project settings: claudeModel() = %q, want sonnet", got)
+	}
+	writeSettings(filepath.Join(project, ".claude", "settings.local.json"), "haiku")
+	if got := claudeModel("", project); got != "haiku" {
+		t.Errorf("local settings: claudeModel() = %q, want haiku", got)
+	}
+	t.Setenv("ANTHROPIC_MODEL", "claude-opus-4-5")
+	if got := claudeModel("", project); got != "claude-opus-4-5" {
+		t.Errorf("ANTHROPIC_MODEL: claudeModel() = %q, want claude-opus-4-5", got)
+	}
+	if got := claudeModel("claude-sonnet-4-5", project); got != "claude-sonnet-4-5" {
+		t.Errorf("explicit model: claudeModel() = %q, want claude-sonnet-4-5", got)
+	}
+}
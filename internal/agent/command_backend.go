@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// commandBackend runs prompts through an arbitrary agent CLI (aider, codex, ...)
+// described by an argv template. The command runs in the workspace and its
+// standard output is the agent's response.
+type commandBackend struct {
+	argv  []string
+	model string
+}
+
+func newCommandBackend(cfg BackendConfig) (Backend, error) {
+	if len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("agent backend command requires a command template")
+	}
+	hasPrompt := false
+	for _, arg := range cfg.Command {
+		if strings.Contains(arg, "{prompt}") {
+			hasPrompt = true
+		}
+	}
+	if !hasPrompt {
+		return nil, fmt.Errorf("agent backend command: template must contain {prompt}")
+	}
+	return &commandBackend{argv: cfg.Command, model: cfg.Model}, nil
+}
+
+func (b *commandBackend) Name() string {
+	return "command (" + b.argv[0] + ")"
+}
+
+func (b *commandBackend) Check() error {
+	if _, err := execLookPath(b.argv[0]); err != nil {
+		return fmt.Errorf("agent backend command: %s not found", b.argv[0])
+	}
+	return nil
+}
+
+// ExecutePrompt substitutes the placeholders and runs the command. Like the
+// Claude CLI executor, it sends SIGINT on timeout before force-killing.
+func (b *commandBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	r := strings.NewReplacer("{prompt}", prompt, "{tools}", tools, "{workdir}", workDir, "{model}", b.model)
+	args := make([]string, len(b.argv))
+	for i, arg := range b.argv {
+		args[i] = r.Replace(arg)
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workDir
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = taskCommandGraceSec
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("task timed out after %s", timeout)
+		}
+		preview := stderr.String()
+		if len(preview) > outputPreviewMax {
+			preview = preview[:outputPreviewMax] + "..."
+		}
+		return "", fmt.Errorf("%s: %w: %s", args[0], err, strings.TrimSpace(preview))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
 )
 
 // Evaluator configuration constants.
@@ -39,8 +41,10 @@ func defaultCommandRunner(ctx context.Context, name string, args ...string) ([]b
 }
 
 // Evaluator performs content quality evaluation using the Claude CLI.
+// It is the default Judge.
 type Evaluator struct {
 	timeout    time.Duration
+	model      string // passed as --model when set
 	runCommand commandRunnerFunc
 }
 
@@ -71,6 +75,9 @@ func (e *Evaluator) EvaluateContent(ctx context.Context, systemPrompt, content s
 		"--output-format", "json",
 		"--json-schema", schema,
 	}
+	if e.model != "" {
+		args = append(args, "--model", e.model)
+	}
 
 	// Create command with timeout
 	evalCtx, cancel := context.WithTimeout(ctx, e.timeout)
@@ -96,6 +103,8 @@ func (e *Evaluator) EvaluateContent(ctx context.Context, systemPrompt, content s
 		SessionID        string           `json:"session_id"`
 		Result           string           `json:"result"`
 		StructuredOutput EvaluationResult `json:"structured_output"`
+		TotalCostUSD     float64          `json:"total_cost_usd"`
+		Usage            cliUsage         `json:"usage"`
 	}
 
 	if err := json.Unmarshal(output, &resp); err != nil {
@@ -106,6 +115,10 @@ func (e *Evaluator) EvaluateContent(ctx context.Context, systemPrompt, content s
 		return EvaluationResult{}, fmt.Errorf("failed to parse CLI response: %w (got: %s)", err, preview)
 	}
 
+	if u := resp.Usage.usage(e.model, resp.TotalCostUSD); u.Tokens() > 0 || u.CostUSD > 0 {
+		metrics.RecordUsage(ctx, u)
+	}
+
 	// Validate score range
 	if resp.StructuredOutput.Score < scoreMin || resp.StructuredOutput.Score > scoreMax {
 		return EvaluationResult{}, fmt.Errorf("score out of range (1-10): %d", resp.StructuredOutput.Score)
@@ -116,28 +129,16 @@ func (e *Evaluator) EvaluateContent(ctx context.Context, systemPrompt, content s
 
 // EvaluateWithRetry runs EvaluateContent with one retry on failure.
 func (e *Evaluator) EvaluateWithRetry(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
-	result, err := e.EvaluateContent(ctx, systemPrompt, content)
-	if err == nil {
-		return result, nil
-	}
-
-	// Check if context is already canceled
-	if ctx.Err() != nil {
-		return EvaluationResult{}, ctx.Err()
-	}
-
-	// Wait before retry
-	select {
-	case <-ctx.Done():
-		return EvaluationResult{}, ctx.Err()
-	case <-time.After(retryDelay):
-	}
+	return EvaluateWithRetry(ctx, e, systemPrompt, content)
+}
 
-	// Retry once
-	result, err = e.EvaluateContent(ctx, systemPrompt, content)
-	if err != nil {
-		return EvaluationResult{}, fmt.Errorf("evaluation failed after retry: %w", err)
-	}
+// resolvedModel implements modelResolver. The CLI runs in the current
+// directory, so dir is ignored.
+func (e *Evaluator) resolvedModel(dir string) string {
+	return claudeModel(e.model, "")
+}
 
-	return result, nil
+// Info implements Judge. The Claude CLI does not expose sampling temperature.
+func (e *Evaluator) Info() JudgeInfo {
+	return JudgeInfo{Backend: "claude", Model: e.model}
 }
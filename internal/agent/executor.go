@@ -1,12 +1,18 @@
 package agent
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
 )
 
 // executor configuration constants.
@@ -22,6 +28,7 @@ var execLookPath = exec.LookPath
 // executor manages Claude CLI subprocess invocation for agent tasks.
 type executor struct {
 	workDir string // Isolated workspace path for agent execution
+	model   string // Optional model passed via --model
 }
 
 // newExecutor creates an executor that runs tasks in the given work directory.
@@ -42,11 +49,33 @@ func CheckClaudeCLI() error {
 	return nil
 }
 
-// cliResponse represents the JSON output from Claude CLI in headless mode.
+// cliResponse represents the final "result" event of the Claude CLI's
+// stream-json output in headless mode.
 type cliResponse struct {
-	Type      string `json:"type"`       // "result"
-	SessionID string `json:"session_id"` // Session identifier
-	Result    string `json:"result"`     // Agent's text response
+	Type         string   `json:"type"`           // "result"
+	SessionID    string   `json:"session_id"`     // Session identifier
+	Result       string   `json:"result"`         // Agent's text response
+	TotalCostUSD float64  `json:"total_cost_usd"` // Cost of the session as computed by the CLI
+	Usage        cliUsage `json:"usage"`          // Token usage of the session
+}
+
+// cliUsage is the token usage reported in Claude CLI JSON output.
+type cliUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+}
+
+// usage converts the CLI's figures for a session run with model ("" for the
+// CLI default).
+func (u cliUsage) usage(model string, costUSD float64) metrics.Usage {
+	return metrics.Usage{
+		Model:        model,
+		InputTokens:  u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens,
+		OutputTokens: u.OutputTokens,
+		CostUSD:      costUSD,
+	}
 }
 
 // ExecuteTask runs a single task against the Claude CLI.
@@ -66,16 +95,19 @@ func (e *executor) ExecuteTask(ctx context.Context, t task) taskResult {
 	defer cancel()
 
 	cmd := e.buildTaskCommand(taskCtx, t)
-	output, err := cmd.CombinedOutput()
+	stream := newStreamParser(e.workDir, result.StartTime)
+	output, err := runStreaming(cmd, stream)
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
+	result.ToolCalls = stream.calls
 
 	if err != nil {
-		classifyExecError(&result, taskCtx, err, output, timeout)
+		// API errors such as rate limits end the stream with an error result.
+		classifyExecError(&result, taskCtx, err, append(output, stream.result...), timeout)
 		return result
 	}
 
-	parsed, parseErr := parseJSONOutput(output)
+	parsed, parseErr := stream.final()
 	if parseErr != nil {
 		result.Status = statusError
 		result.Error = fmt.Sprintf("failed to parse CLI output: %v", parseErr)
@@ -85,14 +117,47 @@ func (e *executor) ExecuteTask(ctx context.Context, t task) taskResult {
 	result.Status = statusCompleted
 	result.Response = parsed.Result
 	result.SessionID = parsed.SessionID
+	result.Usage = parsed.Usage.usage(e.model, parsed.TotalCostUSD)
 	return result
 }
 
+// runStreaming runs cmd and feeds each line of its standard output to stream
+// as it arrives, so tool calls are timed. It returns the standard error and
+// any output lines that were not events, for error messages.
+func runStreaming(cmd *exec.Cmd, stream *streamParser) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	var other bytes.Buffer
+	r := bufio.NewReader(stdout)
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 && !stream.event(line, time.Now()) {
+			other.Write(line)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	err = cmd.Wait()
+	return append(stderr.Bytes(), other.Bytes()...), err
+}
+
 func (e *executor) buildTaskCommand(ctx context.Context, t task) *exec.Cmd {
-	args := []string{"-p", t.Prompt, "--output-format", "json"}
+	args := []string{"-p", t.Prompt, "--output-format", "stream-json", "--verbose"}
 	if t.ToolsAllowed != "" {
 		args = append(args, "--allowedTools", t.ToolsAllowed)
 	}
+	if e.model != "" {
+		args = append(args, "--model", e.model)
+	}
 	cmd := exec.CommandContext(ctx, "claude", args...)
 	cmd.Dir = e.workDir
 	cmd.Cancel = func() error {
@@ -138,3 +203,120 @@ func parseJSONOutput(output []byte) (*cliResponse, error) {
 
 	return &resp, nil
 }
+
+// streamEvent is one line of the Claude CLI's stream-json output. Assistant
+// messages carry tool_use blocks, user messages the matching tool_result
+// blocks, and the last event is the result.
+type streamEvent struct {
+	Type    string `json:"type"`
+	Message struct {
+		Content json.RawMessage `json:"content"` // blocks, or a plain string
+	} `json:"message"`
+}
+
+// streamContent is a content block of a stream-json message.
+type streamContent struct {
+	Type      string          `json:"type"`
+	ID        string          `json:"id"`          // tool_use
+	Name      string          `json:"name"`        // tool_use
+	Input     json.RawMessage `json:"input"`       // tool_use
+	ToolUseID string          `json:"tool_use_id"` // tool_result
+	IsError   bool            `json:"is_error"`    // tool_result
+}
+
+// streamParser collects the tool-call trace and the final result from
+// stream-json events.
+type streamParser struct {
+	workDir string
+	start   time.Time
+	calls   []metrics.ToolCall
+	pending map[string]int // tool_use ID -> index in calls
+	result  []byte         // raw result event
+}
+
+func newStreamParser(workDir string, start time.Time) *streamParser {
+	return &streamParser{workDir: workDir, start: start, calls: []metrics.ToolCall{}, pending: make(map[string]int)}
+}
+
+// event handles one output line received at time at. It reports whether the
+// line was a JSON event.
+func (p *streamParser) event(line []byte, at time.Time) bool {
+	var ev streamEvent
+	if err := json.Unmarshal(line, &ev); err != nil {
+		return false
+	}
+	var content []streamContent
+	_ = json.Unmarshal(ev.Message.Content, &content) // string content has no tool blocks
+
+	switch ev.Type {
+	case "result":
+		p.result = append([]byte(nil), line...)
+	case "assistant":
+		for _, c := range content {
+			if c.Type != "tool_use" {
+				continue
+			}
+			p.pending[c.ID] = len(p.calls)
+			p.calls = append(p.calls, newToolCall(p.workDir, c.Name, c.Input, at.Sub(p.start)))
+		}
+	case "user":
+		for _, c := range content {
+			i, ok := p.pending[c.ToolUseID]
+			if c.Type != "tool_result" || !ok {
+				continue
+			}
+			delete(p.pending, c.ToolUseID)
+			p.calls[i].Duration = at.Sub(p.start) - p.calls[i].Start
+			p.calls[i].Error = c.IsError
+		}
+	}
+	return ev.Type != ""
+}
+
+// final parses the result event.
+func (p *streamParser) final() (*cliResponse, error) {
+	if p.result == nil {
+		return nil, fmt.Errorf("no result event")
+	}
+	return parseJSONOutput(p.result)
+}
+
+// toolInputMax caps the recorded arguments of a tool call, which for Write
+// and Edit contain whole files.
+const toolInputMax = 200
+
+// newToolCall records a call of tool with the JSON arguments input, started
+// at offset start. The targeted path is made relative to workDir.
+func newToolCall(workDir, tool string, input json.RawMessage, start time.Duration) metrics.ToolCall {
+	call := metrics.ToolCall{Tool: tool, Start: start}
+	var compact bytes.Buffer
+	if json.Compact(&compact, input) == nil {
+		call.Input = compact.String()
+	} else {
+		call.Input = string(input)
+	}
+	if len(call.Input) > toolInputMax {
+		call.Input = call.Input[:toolInputMax] + "..."
+	}
+
+	var args struct {
+		FilePath string `json:"file_path"`
+		Path     string `json:"path"`
+	}
+	if json.Unmarshal(input, &args) != nil {
+		return call
+	}
+	path := args.FilePath
+	if path == "" {
+		path = args.Path
+	}
+	if path != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(workDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		}
+	}
+	if path != "" {
+		call.Path = filepath.ToSlash(filepath.Clean(path))
+	}
+	return call
+}
@@ -11,6 +11,7 @@ import (
 // cliExecutorAdapter adapts the real Claude CLI executor to the metrics.Executor interface.
 type cliExecutorAdapter struct {
 	workDir string
+	model   string // optional --model flag
 }
 
 // newCLIExecutorAdapter creates an adapter for the given workspace directory.
@@ -37,7 +38,9 @@ func (a *cliExecutorAdapter) ExecutePrompt(ctx context.Context, workDir, prompt,
 	}
 
 	exec := newExecutor(dir)
+	exec.model = a.model
 	result := exec.ExecuteTask(ctx, t)
+	metrics.RecordToolCalls(ctx, result.ToolCalls)
 
 	if result.Status != statusCompleted {
 		if result.Error != "" {
@@ -46,6 +49,9 @@ func (a *cliExecutorAdapter) ExecutePrompt(ctx context.Context, workDir, prompt,
 		return "", fmt.Errorf("execution status: %s", result.Status)
 	}
 
+	if result.Usage.Tokens() > 0 || result.Usage.CostUSD > 0 {
+		metrics.RecordUsage(ctx, result.Usage)
+	}
 	return result.Response, nil
 }
 
@@ -1,11 +1,16 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
 )
 
 func TestCheckClaudeCLI_Available(t *testing.T) {
@@ -128,7 +133,7 @@ func TestCreateWorkspace_NonGitDir(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	workDir, cleanup, err := CreateWorkspace(tmpDir)
+	workDir, cleanup, err := CreateWorkspace(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("CreateWorkspace failed: %v", err)
 	}
@@ -142,6 +147,38 @@ func TestCreateWorkspace_NonGitDir(t *testing.T) {
 	cleanup()
 }
 
+func TestCreateWorkspace_Cancelled(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := CreateWorkspace(ctx, repo); err != context.Canceled {
+		t.Fatalf("CreateWorkspace with cancelled context: err = %v, want context.Canceled", err)
+	}
+	out, err := exec.Command("git", "-C", repo, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		t.Fatalf("git worktree list: %v", err)
+	}
+	if n := strings.Count(string(out), "worktree "); n != 1 {
+		t.Errorf("cancelled CreateWorkspace left %d extra worktrees:\n%s", n-1, out)
+	}
+}
+
 func TestCreateWorkspace_WithGitRepo(t *testing.T) {
 	// Use the actual ARS repo for this test
 	// Find repo root by looking for .git
@@ -163,7 +200,7 @@ func TestCreateWorkspace_WithGitRepo(t *testing.T) {
 		repoRoot = parent
 	}
 
-	workDir, cleanup, err := CreateWorkspace(repoRoot)
+	workDir, cleanup, err := CreateWorkspace(context.Background(), repoRoot)
 	if err != nil {
 		t.Fatalf("CreateWorkspace failed: %v", err)
 	}
@@ -215,6 +252,64 @@ func TestExecutor_JSONParsing_Valid(t *testing.T) {
 	}
 }
 
+func TestExecutor_JSONParsing_Usage(t *testing.T) {
+	output := `{"type":"result","result":"ok","total_cost_usd":0.042,"usage":{"input_tokens":12,"cache_creation_input_tokens":3000,"cache_read_input_tokens":15000,"output_tokens":450}}`
+
+	resp, err := parseJSONOutput([]byte(output))
+	if err != nil {
+		t.Fatalf("parseJSONOutput failed: %v", err)
+	}
+	u := resp.Usage.usage("sonnet", resp.TotalCostUSD)
+	if u.InputTokens != 18012 || u.OutputTokens != 450 || u.CostUSD != 0.042 || u.Model != "sonnet" {
+		t.Errorf("usage = %+v, want 18012 input (incl. cache), 450 output, $0.042", u)
+	}
+}
+
+func TestStreamParser(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := newStreamParser("/work", start)
+	lines := []struct {
+		at    time.Duration
+		event string
+	}{
+		{0, `{"type":"system","subtype":"init","session_id":"s1"}`},
+		{2 * time.Second, `{"type":"assistant","message":{"content":[{"type":"text","text":"Looking."},{"type":"tool_use","id":"t1","name":"Grep","input":{"pattern":"Open","path":"/work/store"}},{"type":"tool_use","id":"t2","name":"Read","input":{"file_path":"/work/store/store.go"}}]}}`},
+		{3 * time.Second, `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"store.go:12"}]}}`},
+		{4 * time.Second, `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"no such file","is_error":true}]}}`},
+		{4 * time.Second, `{"type":"user","message":{"content":"plain text"}}`},
+		{6 * time.Second, `{"type":"result","subtype":"success","result":"store/store.go: Open","session_id":"s1"}`},
+	}
+	for _, l := range lines {
+		if !p.event([]byte(l.event+"\n"), start.Add(l.at)) {
+			t.Errorf("event not recognized: %s", l.event)
+		}
+	}
+	if p.event([]byte("Error: something went wrong\n"), start) {
+		t.Error("non-JSON line reported as an event")
+	}
+
+	want := []metrics.ToolCall{
+		{Tool: "Grep", Input: `{"pattern":"Open","path":"/work/store"}`, Path: "store", Start: 2 * time.Second, Duration: time.Second},
+		{Tool: "Read", Input: `{"file_path":"/work/store/store.go"}`, Path: "store/store.go", Start: 2 * time.Second, Duration: 2 * time.Second, Error: true},
+	}
+	if len(p.calls) != len(want) {
+		t.Fatalf("calls = %+v, want %+v", p.calls, want)
+	}
+	for i := range want {
+		if p.calls[i] != want[i] {
+			t.Errorf("calls[%d] = %+v, want %+v", i, p.calls[i], want[i])
+		}
+	}
+	resp, err := p.final()
+	if err != nil || resp.Result != "store/store.go: Open" {
+		t.Errorf("final() = %+v, %v, want the result event", resp, err)
+	}
+
+	if _, err := newStreamParser("/work", start).final(); err == nil {
+		t.Error("final() without a result event should fail")
+	}
+}
+
 func TestExecutor_JSONParsing_Malformed(t *testing.T) {
 	malformedCases := []struct {
 		name  string
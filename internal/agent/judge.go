@@ -0,0 +1,265 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultJudge is the judge backend used when none is configured.
+const DefaultJudge = "claude"
+
+// Judge scores content against the criteria in a system prompt (1-10).
+// C4 uses it for its LLM-based documentation metrics.
+type Judge interface {
+	EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error)
+	// Info identifies the model behind the scores, for reporting.
+	Info() JudgeInfo
+}
+
+// JudgeInfo identifies the backend and settings that produced a judge's scores.
+type JudgeInfo struct {
+	Backend     string   // registry name, e.g. "claude"
+	Model       string   // empty if the backend's default model is used
+	Temperature *float64 // nil if the backend does not expose sampling temperature
+}
+
+// String describes the judge, e.g. "openai (qwen2.5-coder, temperature 0.0)".
+func (i JudgeInfo) String() string {
+	var details []string
+	if i.Model != "" {
+		details = append(details, i.Model)
+	}
+	if i.Temperature != nil {
+		details = append(details, fmt.Sprintf("temperature %.1f", *i.Temperature))
+	}
+	if len(details) == 0 {
+		return i.Backend
+	}
+	return i.Backend + " (" + strings.Join(details, ", ") + ")"
+}
+
+// JudgeConfig selects and configures a judge backend (judge section of .arsrc.yml).
+type JudgeConfig struct {
+	Backend     string        // registry name: "claude", "openai" or "command"; empty means DefaultJudge
+	Model       string        // model name; required for openai
+	Temperature *float64      // openai: sampling temperature (default 0 for reproducible scores)
+	BaseURL     string        // openai: chat-completions API base URL
+	APIKeyEnv   string        // openai: environment variable holding the API key
+	Command     []string      // command: argv template with an optional {model} placeholder
+	Timeout     time.Duration // per evaluation; 0 means 60 seconds
+}
+
+// judgeFactory creates a Judge from its configuration.
+type judgeFactory func(cfg JudgeConfig) (Judge, error)
+
+// judges is the registry of judge backends by name.
+var judges = map[string]judgeFactory{
+	"claude":  newClaudeJudge,
+	"openai":  newOpenAIJudge,
+	"command": newCommandJudge,
+}
+
+// JudgeNames returns the names of all registered judge backends, sorted.
+func JudgeNames() []string {
+	names := make([]string, 0, len(judges))
+	for name := range judges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewJudge creates the judge selected by cfg.Backend.
+func NewJudge(cfg JudgeConfig) (Judge, error) {
+	name := cfg.Backend
+	if name == "" {
+		name = DefaultJudge
+	}
+	factory, ok := judges[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown judge backend %q (available: %s)", name, strings.Join(JudgeNames(), ", "))
+	}
+	return factory(cfg)
+}
+
+// EvaluateWithRetry runs j.EvaluateContent with one retry on failure.
+func EvaluateWithRetry(ctx context.Context, j Judge, systemPrompt, content string) (EvaluationResult, error) {
+	result, err := j.EvaluateContent(ctx, systemPrompt, content)
+	if err == nil {
+		return result, nil
+	}
+
+	// Check if context is already canceled
+	if ctx.Err() != nil {
+		return EvaluationResult{}, ctx.Err()
+	}
+
+	// Wait before retry
+	select {
+	case <-ctx.Done():
+		return EvaluationResult{}, ctx.Err()
+	case <-time.After(retryDelay):
+	}
+
+	// Retry once
+	result, err = j.EvaluateContent(ctx, systemPrompt, content)
+	if err != nil {
+		return EvaluationResult{}, fmt.Errorf("evaluation failed after retry: %w", err)
+	}
+
+	return result, nil
+}
+
+// newClaudeJudge returns the Claude CLI evaluator. The CLI has no temperature
+// setting, so configuring one is an error rather than silently ignored.
+func newClaudeJudge(cfg JudgeConfig) (Judge, error) {
+	if cfg.Temperature != nil {
+		return nil, fmt.Errorf("judge backend claude does not support temperature")
+	}
+	e := NewEvaluator(cfg.Timeout)
+	e.model = cfg.Model
+	return e, nil
+}
+
+// parseJudgeOutput extracts the {"score", "reason"} object from a model's
+// free-text reply and validates the score range.
+func parseJudgeOutput(output string) (EvaluationResult, error) {
+	start := strings.Index(output, "{")
+	end := strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return EvaluationResult{}, fmt.Errorf("no JSON object in response (got: %s)", previewText(output))
+	}
+	var result EvaluationResult
+	if err := json.Unmarshal([]byte(output[start:end+1]), &result); err != nil {
+		return EvaluationResult{}, fmt.Errorf("failed to parse response: %w (got: %s)", err, previewText(output))
+	}
+	if result.Score < scoreMin || result.Score > scoreMax {
+		return EvaluationResult{}, fmt.Errorf("score out of range (1-10): %d", result.Score)
+	}
+	return result, nil
+}
+
+func previewText(s string) string {
+	if len(s) > errorPreviewMax {
+		return s[:errorPreviewMax] + "..."
+	}
+	return s
+}
+
+// openAIJudge scores content with an OpenAI-compatible chat-completions
+// endpoint, e.g. a self-hosted model.
+type openAIJudge struct {
+	chatClient
+	model       string
+	temperature float64
+	timeout     time.Duration
+}
+
+func newOpenAIJudge(cfg JudgeConfig) (Judge, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("judge backend openai requires a model")
+	}
+	j := &openAIJudge{
+		chatClient: newChatClient(cfg.BaseURL, cfg.APIKeyEnv),
+		model:      cfg.Model,
+		timeout:    cfg.Timeout,
+	}
+	if cfg.Temperature != nil {
+		j.temperature = *cfg.Temperature
+	}
+	if j.timeout == 0 {
+		j.timeout = defaultEvalTimeout
+	}
+	return j, nil
+}
+
+func (j *openAIJudge) Info() JudgeInfo {
+	temperature := j.temperature
+	return JudgeInfo{Backend: "openai", Model: j.model, Temperature: &temperature}
+}
+
+func (j *openAIJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
+	if err := j.check(); err != nil {
+		return EvaluationResult{}, fmt.Errorf("judge backend openai: %w", err)
+	}
+	evalCtx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	temperature := j.temperature
+	msg, err := j.complete(evalCtx, chatRequest{
+		Model: j.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: content},
+		},
+		Temperature: &temperature,
+	})
+	if err != nil {
+		if evalCtx.Err() == context.DeadlineExceeded {
+			return EvaluationResult{}, fmt.Errorf("evaluation timed out after %v", j.timeout)
+		}
+		return EvaluationResult{}, err
+	}
+	return parseJudgeOutput(msg.Content)
+}
+
+// commandJudge scores content with a local command such as "ollama run <model>".
+// The system prompt and content are written to its standard input; the reply
+// on standard output must contain the JSON score object.
+type commandJudge struct {
+	argv    []string
+	model   string
+	timeout time.Duration
+}
+
+func newCommandJudge(cfg JudgeConfig) (Judge, error) {
+	if len(cfg.Command) == 0 {
+		return nil, fmt.Errorf("judge backend command requires a command")
+	}
+	if cfg.Temperature != nil {
+		return nil, fmt.Errorf("judge backend command does not support temperature; set it in the command")
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultEvalTimeout
+	}
+	return &commandJudge{argv: cfg.Command, model: cfg.Model, timeout: timeout}, nil
+}
+
+func (j *commandJudge) Info() JudgeInfo {
+	return JudgeInfo{Backend: "command", Model: j.model}
+}
+
+func (j *commandJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
+	evalCtx, cancel := context.WithTimeout(ctx, j.timeout)
+	defer cancel()
+
+	args := make([]string, len(j.argv))
+	for i, arg := range j.argv {
+		args[i] = strings.ReplaceAll(arg, "{model}", j.model)
+	}
+	cmd := exec.CommandContext(evalCtx, args[0], args[1:]...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = commandWaitDelay
+	cmd.Stdin = strings.NewReader(systemPrompt + "\n\n---\n\n" + content)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if evalCtx.Err() == context.DeadlineExceeded {
+			return EvaluationResult{}, fmt.Errorf("evaluation timed out after %v", j.timeout)
+		}
+		return EvaluationResult{}, fmt.Errorf("%s: %w (output: %s)", args[0], err, previewText(stderr.String()))
+	}
+	return parseJudgeOutput(string(output))
+}
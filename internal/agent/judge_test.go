@@ -0,0 +1,125 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewJudge(t *testing.T) {
+	j, err := NewJudge(JudgeConfig{Model: "sonnet"})
+	if err != nil {
+		t.Fatalf("NewJudge() error: %v", err)
+	}
+	if info := j.Info(); info.Backend != "claude" || info.Model != "sonnet" || info.Temperature != nil {
+		t.Errorf("default judge info = %+v", info)
+	}
+
+	temperature := 0.5
+	tests := []JudgeConfig{
+		{Backend: "gemini"},
+		{Backend: "openai"},
+		{Backend: "command"},
+		{Backend: "claude", Temperature: &temperature},
+	}
+	for _, cfg := range tests {
+		if _, err := NewJudge(cfg); err == nil {
+			t.Errorf("NewJudge(%+v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestEvaluator_ModelFlag(t *testing.T) {
+	j, _ := NewJudge(JudgeConfig{Model: "haiku"})
+	e := j.(*Evaluator)
+	var gotArgs []string
+	e.SetCommandRunner(func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		gotArgs = args
+		return []byte(`{"structured_output":{"score":6,"reason":"ok"}}`), nil
+	})
+	if _, err := e.EvaluateContent(context.Background(), "rate", "content"); err != nil {
+		t.Fatalf("EvaluateContent() error: %v", err)
+	}
+	if got := strings.Join(gotArgs, " "); !strings.HasSuffix(got, "--model haiku") {
+		t.Errorf("args = %q, want --model haiku", got)
+	}
+}
+
+func TestParseJudgeOutput(t *testing.T) {
+	tests := []struct {
+		output    string
+		wantScore int
+		wantErr   bool
+	}{
+		{`{"score": 8, "reason": "clear"}`, 8, false},
+		{"Here is my evaluation:\n```json\n{\"score\": 3, \"reason\": \"thin\"}\n```", 3, false},
+		{`{"score": 11, "reason": "too high"}`, 0, true},
+		{"I cannot rate this.", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseJudgeOutput(tt.output)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseJudgeOutput(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			continue
+		}
+		if got.Score != tt.wantScore {
+			t.Errorf("parseJudgeOutput(%q) score = %d, want %d", tt.output, got.Score, tt.wantScore)
+		}
+	}
+}
+
+func TestOpenAIJudge(t *testing.T) {
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		resp := map[string]any{"choices": []map[string]any{{
+			"message": map[string]string{"role": "assistant", "content": `{"score": 9, "reason": "excellent"}`},
+		}}}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	j, err := NewJudge(JudgeConfig{Backend: "openai", Model: "llama3", BaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewJudge() error: %v", err)
+	}
+	result, err := EvaluateWithRetry(context.Background(), j, ReadmeClarityPrompt, "# Project")
+	if err != nil {
+		t.Fatalf("EvaluateWithRetry() error: %v", err)
+	}
+	if result.Score != 9 || result.Reason != "excellent" {
+		t.Errorf("result = %+v", result)
+	}
+	if got.Model != "llama3" || got.Temperature == nil || *got.Temperature != 0 || len(got.Messages) != 2 || got.Messages[0].Content != ReadmeClarityPrompt {
+		t.Errorf("request = %+v", got)
+	}
+	if s := j.Info().String(); s != "openai (llama3, temperature 0.0)" {
+		t.Errorf("Info().String() = %q", s)
+	}
+}
+
+func TestCommandJudge(t *testing.T) {
+	// The command echoes the model and the first line of stdin (the system prompt) into the reason.
+	j, err := NewJudge(JudgeConfig{
+		Backend: "command",
+		Model:   "m1",
+		Command: []string{"sh", "-c", `read first; printf 'Result: {"score": 5, "reason": "%s %s"}\n' "$0" "$first"`, "{model}"},
+		Timeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewJudge() error: %v", err)
+	}
+	result, err := j.EvaluateContent(context.Background(), "Rate the docs.", "# Project")
+	if err != nil {
+		t.Fatalf("EvaluateContent() error: %v", err)
+	}
+	if result.Score != 5 || result.Reason != "m1 Rate the docs." {
+		t.Errorf("result = %+v", result)
+	}
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+)
+
+// CachedBackend serves agent responses from the LLM response cache. Calls are
+// keyed by backend and the model it resolves to (see modelResolver), tools, prompt, run, repeat and the content of the
+// sample file, so an unchanged sample reuses its response across scans while
+// any edit to it is a miss.
+type CachedBackend struct {
+	Backend
+	store *cache.LLMStore
+	hits  atomic.Int64
+}
+
+// NewCachedBackend wraps b with store; a nil store caches nothing. Prompts
+// whose tools can write files (M6's change tasks) always run, since their
+// effect on the workspace is the point.
+func NewCachedBackend(b Backend, store *cache.LLMStore) *CachedBackend {
+	return &CachedBackend{Backend: b, store: store}
+}
+
+// Hits returns the number of responses served from the cache.
+func (c *CachedBackend) Hits() int {
+	return int(c.hits.Load())
+}
+
+// ExecutePrompt implements metrics.Executor.
+func (c *CachedBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	if writesFiles(tools) {
+		return c.Backend.ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+	}
+	call := metrics.CallFromContext(ctx)
+	parts := []string{"agent", c.Name(), resolveModel(c.Backend, workDir), tools, prompt, call.File, fileDigest(workDir, call.File), strconv.Itoa(call.Run)}
+	if call.Repeat > 0 {
+		parts = append(parts, "repeat", strconv.Itoa(call.Repeat))
+	}
+	key := cache.LLMKey(parts...)
+
+	var entry agentEntry
+	if c.store.Get(key, &entry) {
+		c.hits.Add(1)
+		if entry.ToolCalls != nil {
+			metrics.RecordToolCalls(ctx, entry.ToolCalls)
+		}
+		return entry.Response, nil
+	}
+	entry = agentEntry{}
+	ctx = metrics.WithToolCallRecorder(ctx, func(calls []metrics.ToolCall) { entry.ToolCalls = calls })
+	response, err := c.Backend.ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+	if err == nil {
+		entry.Response = response
+		c.store.Put(key, entry)
+	}
+	return response, err
+}
+
+// agentEntry is a cached agent response. ToolCalls is replayed to tool-call
+// recorders on a hit; null if the backend does not trace tool calls.
+type agentEntry struct {
+	Response  string             `json:"response"`
+	ToolCalls []metrics.ToolCall `json:"tool_calls"`
+}
+
+// writesFiles reports whether a comma-separated tool list allows edits.
+func writesFiles(tools string) bool {
+	for _, tool := range strings.Split(tools, ",") {
+		switch strings.TrimSpace(tool) {
+		case "Edit", "Write", "Bash":
+			return true
+		}
+	}
+	return false
+}
+
+// modelResolver is implemented by backends and judges whose model can be set
+// outside ars, like the Claude CLI's default model. Their name or info alone
+// would let responses of one model be served for another.
+type modelResolver interface {
+	// resolvedModel returns the model used for calls in dir.
+	resolvedModel(dir string) string
+}
+
+// wrapper is implemented by backends and judges wrapping another one.
+type wrapper interface {
+	unwrap() any
+}
+
+// resolveModel returns the model v, a Backend or Judge, resolves to in dir
+// through any wrappers, or "" if its configuration already names the model.
+func resolveModel(v any, dir string) string {
+	for {
+		switch x := v.(type) {
+		case modelResolver:
+			return x.resolvedModel(dir)
+		case wrapper:
+			v = x.unwrap()
+		default:
+			return ""
+		}
+	}
+}
+
+// fileDigest returns the SHA-256 of relPath's content under dir, or "" if
+// there is no such file.
+func fileDigest(dir, relPath string) string {
+	if relPath == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, relPath))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedJudge serves judge scores from the LLM response cache, keyed by the
+// judge's backend, resolved model and temperature, the system prompt and the content.
+type cachedJudge struct {
+	Judge
+	store *cache.LLMStore
+}
+
+// NewCachedJudge wraps j with store. A nil store returns j unchanged.
+func NewCachedJudge(j Judge, store *cache.LLMStore) Judge {
+	if store == nil || j == nil {
+		return j
+	}
+	return &cachedJudge{Judge: j, store: store}
+}
+
+// EvaluateContent implements Judge.
+func (c *cachedJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
+	key := cache.LLMKey("judge", c.Info().String(), resolveModel(c.Judge, ""), systemPrompt, content)
+
+	var result EvaluationResult
+	if c.store.Get(key, &result) {
+		return result, nil
+	}
+	result, err := c.Judge.EvaluateContent(ctx, systemPrompt, content)
+	if err == nil {
+		c.store.Put(key, result)
+	}
+	return result, err
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+)
+
+// countingBackend answers every prompt with a fixed response after two tool
+// calls, and counts prompts.
+type countingBackend struct {
+	calls int
+}
+
+func (b *countingBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	b.calls++
+	metrics.RecordToolCalls(ctx, []metrics.ToolCall{
+		{Tool: "Read", Input: `{"file_path":"a.go"}`, Path: "a.go", Duration: time.Millisecond},
+		{Tool: "Read", Input: `{"file_path":"a.go"}`, Path: "a.go", Start: time.Second},
+	})
+	return "response", nil
+}
+
+func (b *countingBackend) Name() string { return "fake (model)" }
+func (b *countingBackend) Check() error { return nil }
+
+func TestCachedBackend(t *testing.T) {
+	workDir := t.TempDir()
+	file := filepath.Join(workDir, "a.go")
+	if err := os.WriteFile(file, []byte("package a\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inner := &countingBackend{}
+	b := NewCachedBackend(inner, cache.OpenLLM(t.TempDir(), cache.ModeReadWrite, 0, 0))
+	ctx := metrics.WithCall(context.Background(), metrics.Call{File: "a.go"})
+	run := func(ctx context.Context, tools string) {
+		t.Helper()
+		if _, err := b.ExecutePrompt(ctx, workDir, "explain a.go", tools, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run(ctx, "Read")
+	var toolCalls []metrics.ToolCall
+	run(metrics.WithToolCallRecorder(ctx, func(calls []metrics.ToolCall) { toolCalls = calls }), "Read")
+	if inner.calls != 1 || b.Hits() != 1 {
+		t.Errorf("same sample: calls = %d, hits = %d, want 1, 1", inner.calls, b.Hits())
+	}
+	if len(toolCalls) != 2 || toolCalls[1].Start != time.Second || toolCalls[0].Path != "a.go" {
+		t.Errorf("cached tool calls = %+v, want the two traced reads", toolCalls)
+	}
+
+	// Another M1 run of the same prompt is a separate call.
+	run(metrics.WithCall(context.Background(), metrics.Call{File: "a.go", Run: 1}), "Read")
+	if inner.calls != 2 {
+		t.Errorf("new run: calls = %d, want 2", inner.calls)
+	}
+
+	// Editing the sample invalidates its responses.
+	if err := os.WriteFile(file, []byte("package a\n\nfunc F() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run(ctx, "Read")
+	if inner.calls != 3 {
+		t.Errorf("changed sample: calls = %d, want 3", inner.calls)
+	}
+
+	// Tasks that may write files are never cached.
+	run(ctx, "Read,Edit")
+	run(ctx, "Read,Edit")
+	if inner.calls != 5 {
+		t.Errorf("write tools: calls = %d, want 5", inner.calls)
+	}
+}
+
+// defaultModelBackend is a countingBackend whose model, like the Claude
+// CLI's default, is not part of its name.
+type defaultModelBackend struct {
+	countingBackend
+	model string
+}
+
+func (b *defaultModelBackend) Name() string                        { return "fake" }
+func (b *defaultModelBackend) resolvedModel(workDir string) string { return b.model }
+
+func TestCachedBackend_ResolvedModel(t *testing.T) {
+	inner := &defaultModelBackend{model: "sonnet"}
+	// The budget wrapper sits between the cache and the backend, as in C7.
+	b := NewCachedBackend(NewBudgetBackend(inner, NewBudget(0, 0, nil)), cache.OpenLLM(t.TempDir(), cache.ModeReadWrite, 0, 0))
+	run := func() {
+		t.Helper()
+		if _, err := b.ExecutePrompt(context.Background(), t.TempDir(), "explain a.go", "Read", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run()
+	run()
+	if inner.calls != 1 {
+		t.Errorf("same model: calls = %d, want 1", inner.calls)
+	}
+	// Switching the default model is a miss even though the name is unchanged.
+	inner.model = "opus"
+	run()
+	if inner.calls != 2 {
+		t.Errorf("new default model: calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestCachedJudge(t *testing.T) {
+	calls := 0
+	j := &funcJudge{evaluate: func(content string) EvaluationResult {
+		calls++
+		return EvaluationResult{Score: 8, Reason: "clear"}
+	}}
+	cached := NewCachedJudge(j, cache.OpenLLM(t.TempDir(), cache.ModeReadWrite, 0, 0))
+
+	for range 2 {
+		got, err := cached.EvaluateContent(context.Background(), "system", "readme")
+		if err != nil || got.Score != 8 {
+			t.Fatalf("EvaluateContent() = %+v, %v", got, err)
+		}
+	}
+	cached.EvaluateContent(context.Background(), "system", "other readme")
+	if calls != 2 {
+		t.Errorf("judge calls = %d, want 2", calls)
+	}
+	if NewCachedJudge(j, nil) != Judge(j) {
+		t.Error("NewCachedJudge with a nil store should return the judge unchanged")
+	}
+
+	// The Claude CLI judge without a model is keyed by the CLI's default.
+	t.Setenv("ANTHROPIC_MODEL", "claude-opus-4-5")
+	if got := resolveModel(NewBudgetJudge(NewEvaluator(0), NewBudget(0, 0, nil)), ""); got != "claude-opus-4-5" {
+		t.Errorf("resolveModel(claude judge) = %q, want the ANTHROPIC_MODEL default", got)
+	}
+	if got := resolveModel(j, ""); got != "" {
+		t.Errorf("resolveModel(func judge) = %q, want empty", got)
+	}
+}
+
+// funcJudge is a Judge backed by a function.
+type funcJudge struct {
+	evaluate func(content string) EvaluationResult
+}
+
+func (j *funcJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (EvaluationResult, error) {
+	return j.evaluate(content), nil
+}
+
+func (j *funcJudge) Info() JudgeInfo { return JudgeInfo{Backend: "func"} }
@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// calibrationFixtures is the built-in calibration set: small purpose-written
+// Python files under workspace/ and, per judged metric, a strong and a weak
+// response to a prompt about them.
+//
+//go:embed calibration
+var calibrationFixtures embed.FS
+
+// calibrationDirs maps the fixture directories of a calibration set to the
+// metrics whose responses they hold.
+var calibrationDirs = map[string]string{
+	"m2_comprehension": "code_behavior_comprehension",
+	"m3_navigation":    "cross_file_navigation",
+	"m4_identifiers":   "identifier_interpretability",
+	"m5_documentation": "documentation_accuracy_detection",
+}
+
+// calibrationWorkspace is the subdirectory of a calibration set holding the
+// sampled files, at their paths relative to the workspace.
+const calibrationWorkspace = "workspace"
+
+// CalibrationCase is a captured agent response used to compare judge scoring
+// with heuristic scoring, together with the sample and prompt it answered.
+type CalibrationCase struct {
+	MetricID string
+	Name     string // fixture file name, e.g. "thorough_explanation.txt"
+	Sample   Sample
+	Prompt   string
+	Code     string // the sample's file as the judge sees it; "" if missing
+	Response string
+}
+
+// calibrationSample is the JSON sidecar of a response fixture, describing
+// the sample and the prompt the agent was given.
+type calibrationSample struct {
+	File     string `json:"file"`
+	Function string `json:"function,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Prompt   string `json:"prompt"`
+}
+
+// CalibrationScore holds both scores of one case.
+type CalibrationScore struct {
+	Case          CalibrationCase
+	Heuristic     int
+	Judge         int
+	Justification string
+}
+
+// CalibrationReport compares judge and heuristic rankings. Only pairs of
+// cases for the same metric that the heuristic scores differently are
+// compared.
+type CalibrationReport struct {
+	Scores     []CalibrationScore
+	Pairs      int // same-metric pairs with different heuristic scores
+	Agreements int // pairs the judge orders the same way (ties disagree)
+}
+
+// Agreement is the share of compared pairs ranked the same way by judge and
+// heuristic (1 if there are no pairs).
+func (r CalibrationReport) Agreement() float64 {
+	if r.Pairs == 0 {
+		return 1
+	}
+	return float64(r.Agreements) / float64(r.Pairs)
+}
+
+// DefaultCalibrationSet returns the built-in calibration set, which covers
+// every metric a judge can score (M2-M5).
+func DefaultCalibrationSet() ([]CalibrationCase, error) {
+	fsys, err := fs.Sub(calibrationFixtures, "calibration")
+	if err != nil {
+		return nil, err
+	}
+	return LoadCalibrationSet(fsys)
+}
+
+// LoadCalibrationSet reads response fixtures from fsys, one directory per
+// metric (m2_comprehension, m3_navigation, m4_identifiers, m5_documentation)
+// with one *.txt response per file. Missing directories are skipped, but a
+// set without any response is an error. Each response needs a *.json sidecar of the same name with the sample's file
+// (relative to workspace/, where the file is read from), function, line and
+// the prompt.
+func LoadCalibrationSet(fsys fs.FS) ([]CalibrationCase, error) {
+	subdirs := make([]string, 0, len(calibrationDirs))
+	for sub := range calibrationDirs {
+		subdirs = append(subdirs, sub)
+	}
+	sort.Strings(subdirs)
+
+	var cases []CalibrationCase
+	for _, sub := range subdirs {
+		files, err := fs.Glob(fsys, path.Join(sub, "*.txt"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("read calibration fixture: %w", err)
+			}
+			meta, err := loadCalibrationSample(fsys, strings.TrimSuffix(file, ".txt")+".json")
+			if err != nil {
+				return nil, err
+			}
+			sample := Sample{FilePath: meta.File, FunctionName: meta.Function, StartLine: meta.Line}
+			cases = append(cases, CalibrationCase{
+				MetricID: calibrationDirs[sub],
+				Name:     path.Base(file),
+				Sample:   sample,
+				Prompt:   meta.Prompt,
+				Code:     calibrationCode(fsys, sample),
+				Response: string(data),
+			})
+		}
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no calibration fixtures found")
+	}
+	return cases, nil
+}
+
+// loadCalibrationSample reads the sidecar of a response fixture.
+func loadCalibrationSample(fsys fs.FS, name string) (calibrationSample, error) {
+	var meta calibrationSample
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return meta, fmt.Errorf("read calibration sample: %w", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("parse calibration sample %s: %w", name, err)
+	}
+	return meta, nil
+}
+
+// calibrationCode reads a case's sampled file from the set's workspace, like
+// sampleCode does from a scan's. It returns "" if the file is missing.
+func calibrationCode(fsys fs.FS, sample Sample) string {
+	data, err := fs.ReadFile(fsys, path.Join(calibrationWorkspace, sample.FilePath))
+	if err != nil {
+		return ""
+	}
+	return truncateCode(data)
+}
+
+// Calibrate scores every case with its metric's heuristic and with judge
+// using the metric's rubric, then counts the pairs both rank the same way.
+// The judge sees the same content as during a scan: the sample's code, the
+// prompt and the response.
+func Calibrate(ctx context.Context, judge Judge, cases []CalibrationCase) (CalibrationReport, error) {
+	var report CalibrationReport
+	for _, c := range cases {
+		rubric, heuristic, ok := calibrationScorer(c.MetricID)
+		if !ok {
+			return CalibrationReport{}, fmt.Errorf("calibration case %s: metric %q has no judge rubric", c.Name, c.MetricID)
+		}
+		h, _ := heuristic(c.Response)
+		j, justification, err := judge.Score(ctx, rubric, judgeContent(c.Code, c.Sample, c.Prompt, c.Response))
+		if err != nil {
+			return CalibrationReport{}, fmt.Errorf("judge calibration case %s: %w", c.Name, err)
+		}
+		report.Scores = append(report.Scores, CalibrationScore{Case: c, Heuristic: h, Judge: j, Justification: justification})
+	}
+
+	for i, a := range report.Scores {
+		for _, b := range report.Scores[i+1:] {
+			if a.Case.MetricID != b.Case.MetricID || a.Heuristic == b.Heuristic {
+				continue
+			}
+			report.Pairs++
+			if (a.Heuristic > b.Heuristic) == (a.Judge > b.Judge) && a.Judge != b.Judge {
+				report.Agreements++
+			}
+		}
+	}
+	return report, nil
+}
+
+// String summarizes the report, one line per case.
+func (r CalibrationReport) String() string {
+	var b strings.Builder
+	for _, s := range r.Scores {
+		fmt.Fprintf(&b, "%-34s %-30s heuristic=%2d judge=%2d  %s\n",
+			s.Case.MetricID, s.Case.Name, s.Heuristic, s.Judge, s.Justification)
+	}
+	fmt.Fprintf(&b, "agreement: %d/%d pairs (%.0f%%)\n", r.Agreements, r.Pairs, r.Agreement()*100)
+	return b.String()
+}
+
+// calibrationScorer returns the judge rubric and heuristic scoring of a metric.
+func calibrationScorer(metricID string) (string, scoreFunc, bool) {
+	switch metricID {
+	case "code_behavior_comprehension":
+		return m2JudgeRubric, newM2ComprehensionMetric().scoreComprehensionResponse, true
+	case "cross_file_navigation":
+		return m3JudgeRubric, newM3NavigationMetric().scoreNavigationResponse, true
+	case "identifier_interpretability":
+		return m4JudgeRubric, newM4IdentifiersMetric().scoreIdentifierResponse, true
+	case "documentation_accuracy_detection":
+		return m5JudgeRubric, newM5DocumentationMetric().scoreDocumentationResponse, true
+	default:
+		return "", nil, false
+	}
+}
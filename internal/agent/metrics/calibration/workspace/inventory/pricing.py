@@ -0,0 +1,16 @@
+"""Price calculations for orders."""
+
+DEFAULT_DISCOUNT = 0.1
+
+
+def apply_discount(price, discount=DEFAULT_DISCOUNT):
+    """Return price reduced by discount, a fraction between 0 and 1.
+
+    The default discount is 5%.
+    """
+    return round(price * (1 - discount), 2)
+
+
+def order_total(prices):
+    """Return the sum of prices, or None for an empty order."""
+    return sum(prices)
@@ -0,0 +1,26 @@
+"""Stock levels for the warehouse."""
+
+from inventory.store import load_counts, save_counts
+
+LOW_STOCK = 5
+
+
+def reserve(sku, qty):
+    """Reserve qty units of sku and return the units left.
+
+    Raises ValueError if qty is not positive or fewer than qty units are in stock.
+    """
+    if qty <= 0:
+        raise ValueError("qty must be positive")
+    counts = load_counts()
+    available = counts.get(sku, 0)
+    if available < qty:
+        raise ValueError(f"only {available} units of {sku} in stock")
+    counts[sku] = available - qty
+    save_counts(counts)
+    return counts[sku]
+
+
+def low_stock(counts):
+    """Return the SKUs with fewer than LOW_STOCK units, sorted."""
+    return sorted(sku for sku, n in counts.items() if n < LOW_STOCK)
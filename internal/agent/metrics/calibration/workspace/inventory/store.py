@@ -0,0 +1,19 @@
+"""JSON file storage for stock counts."""
+
+import json
+from pathlib import Path
+
+COUNTS_FILE = Path("data/counts.json")
+
+
+def load_counts():
+    """Return the stock counts by SKU, or an empty dict if none are stored."""
+    if not COUNTS_FILE.exists():
+        return {}
+    return json.loads(COUNTS_FILE.read_text())
+
+
+def save_counts(counts):
+    """Write the stock counts by SKU, creating the data directory if needed."""
+    COUNTS_FILE.parent.mkdir(parents=True, exist_ok=True)
+    COUNTS_FILE.write_text(json.dumps(counts, indent=2))
@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// Call identifies the sample an executor call belongs to, so that executors
+// such as the LLM response cache can key on the sample's content.
+type Call struct {
+	File string // sample file relative to the workspace; empty for repository-level prompts
+	Run  int    // repetition of the same prompt, e.g. M1's consistency runs
+
+	Repeat int // repetition of the whole metric with --c7-repeats, set by WithRepeat
+}
+
+type callKey struct{}
+
+// WithCall returns a context carrying c for the executor. The repeat set by
+// WithRepeat is kept.
+func WithCall(ctx context.Context, c Call) context.Context {
+	c.Repeat = CallFromContext(ctx).Repeat
+	return context.WithValue(ctx, callKey{}, c)
+}
+
+// WithRepeat returns a context whose calls belong to repeat r of a metric, so
+// that repeats are not served each other's cached responses.
+func WithRepeat(ctx context.Context, r int) context.Context {
+	return context.WithValue(ctx, callKey{}, Call{Repeat: r})
+}
+
+// CallFromContext returns the Call set by WithCall, or the zero Call.
+func CallFromContext(ctx context.Context) Call {
+	c, _ := ctx.Value(callKey{}).(Call)
+	return c
+}
+
+// Usage is the token usage of one LLM call as reported by its backend.
+type Usage struct {
+	Model        string  // model that served the call; empty if unknown
+	InputTokens  int     // prompt tokens, including cached ones
+	OutputTokens int     // completion tokens
+	CostUSD      float64 // cost reported by the backend; 0 to price by model
+}
+
+// Tokens returns the total number of tokens used.
+func (u Usage) Tokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+type usageKey struct{}
+
+// WithUsageRecorder returns a context whose RecordUsage calls fn, then any
+// recorder of the parent context. Recorders may be called concurrently.
+func WithUsageRecorder(ctx context.Context, fn func(Usage)) context.Context {
+	parent, _ := ctx.Value(usageKey{}).(func(Usage))
+	return context.WithValue(ctx, usageKey{}, func(u Usage) {
+		fn(u)
+		if parent != nil {
+			parent(u)
+		}
+	})
+}
+
+// RecordUsage reports the usage of an LLM call to the context's recorders.
+// Backends call it once per model request.
+func RecordUsage(ctx context.Context, u Usage) {
+	if record, ok := ctx.Value(usageKey{}).(func(Usage)); ok {
+		record(u)
+	}
+}
+
+// ToolCall is one tool invocation an agent made while answering a prompt.
+type ToolCall struct {
+	Tool     string        `json:"tool"`               // e.g. "Read", "Grep"
+	Input    string        `json:"input"`              // arguments as compact JSON
+	Path     string        `json:"path,omitempty"`     // file or directory the call targets, relative to the workspace
+	Start    time.Duration `json:"start"`              // offset from the start of the prompt
+	Duration time.Duration `json:"duration,omitempty"` // until the tool result arrived; 0 if unknown
+	Error    bool          `json:"error,omitempty"`    // the tool reported an error
+}
+
+// ToolCallStats are the navigation effort figures derived from a trace.
+type ToolCallStats struct {
+	Calls          int // tool calls
+	FilesRead      int // distinct files read
+	RedundantReads int // reads repeating an earlier read of the same file and range
+}
+
+// SummarizeToolCalls derives the navigation effort of a trace.
+func SummarizeToolCalls(calls []ToolCall) ToolCallStats {
+	stats := ToolCallStats{Calls: len(calls)}
+	files := make(map[string]bool)
+	reads := make(map[string]bool)
+	for _, c := range calls {
+		if c.Tool != "Read" || c.Path == "" {
+			continue
+		}
+		if !files[c.Path] {
+			files[c.Path] = true
+			stats.FilesRead++
+		}
+		if reads[c.Input] {
+			stats.RedundantReads++
+		}
+		reads[c.Input] = true
+	}
+	return stats
+}
+
+// recordSample returns a context that records the tool calls and retries of
+// the executor calls under it into sr.
+func recordSample(ctx context.Context, sr *SampleResult) context.Context {
+	ctx = WithRetryRecorder(ctx, func() { sr.Retries++ })
+	return traceToolCalls(ctx, &sr.ToolCalls)
+}
+
+// traceToolCalls returns a context that appends the tool calls recorded under
+// it to *calls, leaving *calls non-nil once a backend reported a trace.
+func traceToolCalls(ctx context.Context, calls *[]ToolCall) context.Context {
+	return WithToolCallRecorder(ctx, func(c []ToolCall) {
+		*calls = append(*calls, c...)
+		if *calls == nil {
+			*calls = []ToolCall{}
+		}
+	})
+}
+
+type toolCallsKey struct{}
+
+// WithToolCallRecorder returns a context whose RecordToolCalls calls fn, then
+// any recorder of the parent context.
+func WithToolCallRecorder(ctx context.Context, fn func([]ToolCall)) context.Context {
+	parent, _ := ctx.Value(toolCallsKey{}).(func([]ToolCall))
+	return context.WithValue(ctx, toolCallsKey{}, func(calls []ToolCall) {
+		fn(calls)
+		if parent != nil {
+			parent(calls)
+		}
+	})
+}
+
+// RecordToolCalls reports the tool calls an agent made while answering a
+// prompt, in order. Backends that can trace them call it once per prompt,
+// also when the prompt fails, with an empty trace if the agent used no tools.
+func RecordToolCalls(ctx context.Context, calls []ToolCall) {
+	if record, ok := ctx.Value(toolCallsKey{}).(func([]ToolCall)); ok {
+		record(calls)
+	}
+}
+
+type retryKey struct{}
+
+// WithRetryRecorder returns a context whose RecordRetry calls fn, then any
+// recorder of the parent context.
+func WithRetryRecorder(ctx context.Context, fn func()) context.Context {
+	parent, _ := ctx.Value(retryKey{}).(func())
+	return context.WithValue(ctx, retryKey{}, func() {
+		fn()
+		if parent != nil {
+			parent()
+		}
+	})
+}
+
+// RecordRetry reports that an executor call is retried after a retryable
+// error such as a rate limit.
+func RecordRetry(ctx context.Context) {
+	if record, ok := ctx.Value(retryKey{}).(func()); ok {
+		record()
+	}
+}
@@ -11,6 +11,9 @@ type promptFunc func(sample Sample) string
 // scoreFunc scores a response and returns the score and trace.
 type scoreFunc func(response string) (int, ScoreTrace)
 
+// sampleScoreFunc scores a response with access to the sample, e.g. its ground truth.
+type sampleScoreFunc func(sample Sample, response string) (int, ScoreTrace)
+
 // executeConfig holds the configuration for a standard metric execution loop.
 type executeConfig struct {
 	metricID   string
@@ -19,6 +22,10 @@ type executeConfig struct {
 	tools      string
 	buildPrompt promptFunc
 	scoreResponse scoreFunc
+	scoreSample   sampleScoreFunc // takes precedence over scoreResponse when set
+
+	judge  Judge  // when set with a rubric, scores samples without ground truth
+	rubric string // judge rubric for this metric
 }
 
 // executeStandardMetric runs the common Execute loop shared by m2-m5.
@@ -68,29 +75,33 @@ func executeSamples(ctx context.Context, workDir string, samples []Sample, execu
 
 func executeSingleSample(ctx context.Context, workDir string, sample Sample, executor Executor, cfg executeConfig, timePerSample time.Duration) SampleResult {
 	sampleStart := time.Now()
-	sampleCtx, cancel := context.WithTimeout(ctx, timePerSample)
-	defer cancel()
-
-	prompt := cfg.buildPrompt(sample)
-	response, err := executor.ExecutePrompt(sampleCtx, workDir, prompt, cfg.tools, timePerSample)
-
-	sr := SampleResult{
-		Sample:   sample,
-		Response: response,
-		Prompt:   prompt,
-		Duration: time.Since(sampleStart),
-	}
+	sr := SampleResult{Sample: sample, Prompt: cfg.buildPrompt(sample)}
+	sampleCtx := recordSample(WithCall(ctx, Call{File: sample.FilePath}), &sr)
+	response, err := executor.ExecutePrompt(sampleCtx, workDir, sr.Prompt, cfg.tools, timePerSample)
+	sr.Response = response
+	sr.Duration = time.Since(sampleStart)
 
 	if err != nil {
 		sr.Error = err.Error()
 		sr.Score = 0
+	} else if cfg.judge != nil && cfg.rubric != "" && !hasGroundTruth(sample) {
+		// The judge gets the parent context: the sample's budget is spent on the agent
+		sr.Score, sr.ScoreTrace = judgeSample(ctx, workDir, sample, sr.Prompt, response, cfg)
 	} else {
-		sr.Score, sr.ScoreTrace = cfg.scoreResponse(response)
+		sr.Score, sr.ScoreTrace = scoreHeuristic(sample, response, cfg)
 	}
 
 	return sr
 }
 
+// scoreHeuristic scores a response with the metric's own scoring function.
+func scoreHeuristic(sample Sample, response string, cfg executeConfig) (int, ScoreTrace) {
+	if cfg.scoreSample != nil {
+		return cfg.scoreSample(sample, response)
+	}
+	return cfg.scoreResponse(response)
+}
+
 func finalizeMetricResult(result MetricResult, sampleResults []SampleResult, totalScore int, successCount int, startTime time.Time) MetricResult {
 	result.Samples = sampleResults
 	result.Duration = time.Since(startTime)
@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// GroundTruth supplies facts derived from static analysis, so that metrics can
+// score agent answers against the actual code instead of keyword heuristics.
+type GroundTruth interface {
+	// Navigation returns the dependencies of the file at relPath, or false if
+	// the file's language has no import graph.
+	Navigation(relPath string) (*NavigationTruth, bool)
+	// DocMismatches returns a copy of the file at relPath with injected
+	// documentation mismatches, or false if none can be seeded.
+	DocMismatches(relPath string) (*SeededDoc, bool)
+	// Functions returns the names of the functions declared in the file at
+	// relPath, or false if the file was not analyzed.
+	Functions(relPath string) ([]string, bool)
+	// DocumentedSymbols returns the exported functions and types of the file
+	// at relPath that have a doc comment, or false if there are none.
+	DocumentedSymbols(relPath string) ([]DocumentedSymbol, bool)
+}
+
+// DocumentedSymbol is an exported function or type with its doc comment.
+type DocumentedSymbol struct {
+	Name string // symbol name
+	Kind string // "func" or "type"
+	Doc  string // doc comment without comment markers
+}
+
+// NavigationTruth is the ground truth for a cross-file navigation sample.
+type NavigationTruth struct {
+	Files        []string        // files declaring the project functions the sample calls; a complete trace names them
+	Dependencies []string        // all files in the sample's transitive import closure; naming them is correct
+	Symbols      []string        // project functions the sample calls ("Type.Method" for methods)
+	Identifiers  map[string]bool // every identifier in the project's source, to tell invented symbols from real ones
+}
+
+// GroundTruthMatch compares the items of one kind ("files", "symbols",
+// "mismatches") an agent referenced with the ground truth.
+type GroundTruthMatch struct {
+	Kind         string
+	Matched      []string // expected and referenced
+	Missed       []string // expected but not referenced
+	Hallucinated []string // referenced but not part of the ground truth
+}
+
+// Precision is the share of referenced items that are correct (1 if none were referenced).
+func (g GroundTruthMatch) Precision() float64 {
+	referenced := len(g.Matched) + len(g.Hallucinated)
+	if referenced == 0 {
+		return 1
+	}
+	return float64(len(g.Matched)) / float64(referenced)
+}
+
+// Recall is the share of expected items that were referenced (1 if none were expected).
+func (g GroundTruthMatch) Recall() float64 {
+	expected := len(g.Matched) + len(g.Missed)
+	if expected == 0 {
+		return 1
+	}
+	return float64(len(g.Matched)) / float64(expected)
+}
+
+// F1 is the harmonic mean of precision and recall.
+func (g GroundTruthMatch) F1() float64 {
+	p, r := g.Precision(), g.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+// AttachGroundTruth adds the ground truth m scores against to its samples.
+// Samples whose file has no ground truth keep heuristic scoring.
+func AttachGroundTruth(m Metric, samples []Sample, gt GroundTruth) {
+	if gt == nil {
+		return
+	}
+	switch m.(type) {
+	case *m1Consistency:
+		for i := range samples {
+			if fns, ok := gt.Functions(samples[i].FilePath); ok {
+				samples[i].Functions = fns
+			}
+		}
+	case *m3Navigation:
+		for i := range samples {
+			if nt, ok := gt.Navigation(samples[i].FilePath); ok {
+				samples[i].Navigation = nt
+			}
+		}
+	case *m5Documentation:
+		for i := range samples {
+			if doc, ok := gt.DocMismatches(samples[i].FilePath); ok {
+				samples[i].Seeded = doc
+			}
+		}
+	case *m7Localization:
+		for i := range samples {
+			if symbols, ok := gt.DocumentedSymbols(samples[i].FilePath); ok {
+				attachFeature(&samples[i], symbols)
+			}
+		}
+	}
+}
+
+var (
+	// sourcePathPattern matches source file paths mentioned in a response.
+	sourcePathPattern = regexp.MustCompile(`[\w./-]*\w\.(?:go|py|pyi|ts|tsx|js|jsx|mjs|cjs)\b`)
+	// callPattern matches call-shaped identifiers such as "Load(" or "store.Save(".
+	callPattern = regexp.MustCompile(`\b([A-Za-z_][\w]*(?:\.[A-Za-z_][\w]*)*)\s*\(`)
+	// codeSpanPattern matches identifiers in backticks such as "`Load`" or "`Store.Save()`".
+	codeSpanPattern = regexp.MustCompile("`([A-Za-z_][\\w]*(?:\\.[A-Za-z_][\\w]*)*)(?:\\(\\))?`")
+)
+
+// mentionedFiles returns the source file paths mentioned in a response,
+// normalized to slash-separated paths without a leading "./".
+func mentionedFiles(response string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, m := range sourcePathPattern.FindAllString(response, -1) {
+		m = strings.TrimPrefix(m, "./")
+		if !seen[m] {
+			seen[m] = true
+			files = append(files, m)
+		}
+	}
+	return files
+}
+
+// mentionedSymbols returns the identifiers a response uses in code context
+// (calls and code spans), keyed by their last segment.
+func mentionedSymbols(response string) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, pattern := range []*regexp.Regexp{callPattern, codeSpanPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(response, -1) {
+			parts := strings.Split(m[1], ".")
+			symbols[parts[len(parts)-1]] = true
+		}
+	}
+	return symbols
+}
+
+// pathMatches reports whether a mentioned path refers to file: the same path,
+// or a path suffix such as "store.go" for "internal/store/store.go".
+func pathMatches(mentioned, file string) bool {
+	return mentioned == file || strings.HasSuffix(file, "/"+mentioned)
+}
+
+// matchNavigationFiles compares the files a response mentions with the truth.
+// Mentions of the sample itself, its expected files or any other dependency are
+// correct; other files are hallucinated dependencies.
+func matchNavigationFiles(response, samplePath string, truth *NavigationTruth) GroundTruthMatch {
+	match := GroundTruthMatch{Kind: "files"}
+	mentioned := mentionedFiles(response)
+
+	for _, file := range truth.Files {
+		found := false
+		for _, m := range mentioned {
+			if pathMatches(m, file) {
+				found = true
+				break
+			}
+		}
+		if found {
+			match.Matched = append(match.Matched, file)
+		} else {
+			match.Missed = append(match.Missed, file)
+		}
+	}
+
+	allowed := append([]string{samplePath}, truth.Dependencies...)
+	allowed = append(allowed, truth.Files...)
+	for _, m := range mentioned {
+		ok := false
+		for _, file := range allowed {
+			if pathMatches(m, file) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			match.Hallucinated = append(match.Hallucinated, m)
+		}
+	}
+	return match
+}
+
+// matchNavigationSymbols compares the symbols a response uses in code context
+// with the functions the sample calls. Identifiers that occur nowhere in the
+// project are hallucinated; real identifiers outside the truth are ignored.
+func matchNavigationSymbols(response string, truth *NavigationTruth) GroundTruthMatch {
+	match := GroundTruthMatch{Kind: "symbols"}
+	mentioned := mentionedSymbols(response)
+
+	expected := make(map[string]bool)
+	for _, sym := range truth.Symbols {
+		parts := strings.Split(sym, ".")
+		name := parts[len(parts)-1]
+		expected[name] = true
+		if mentioned[name] {
+			match.Matched = append(match.Matched, sym)
+		} else {
+			match.Missed = append(match.Missed, sym)
+		}
+	}
+
+	for name := range mentioned {
+		if !expected[name] && truth.Identifiers != nil && !truth.Identifiers[name] {
+			match.Hallucinated = append(match.Hallucinated, name)
+		}
+	}
+	sort.Strings(match.Hallucinated)
+	return match
+}
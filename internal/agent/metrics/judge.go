@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// judgeMaxCodeBytes caps the code sample sent to the judge.
+const judgeMaxCodeBytes = 16000
+
+// Judge scores a response against rubric text (1-10) with a justification.
+// It is implemented by adapters over the agent package's judges.
+type Judge interface {
+	Score(ctx context.Context, rubric, content string) (score int, justification string, err error)
+}
+
+// hasGroundTruth reports whether a sample is scored against static analysis,
+// which takes precedence over the judge.
+func hasGroundTruth(sample Sample) bool {
+	return sample.Navigation != nil || sample.Seeded != nil || sample.Functions != nil
+}
+
+// judgeSample scores a response with cfg's judge and rubric. If the judge
+// fails, the metric's heuristic score is used and the failure is recorded in
+// the trace's justification.
+func judgeSample(ctx context.Context, workDir string, sample Sample, prompt, response string, cfg executeConfig) (int, ScoreTrace) {
+	content := judgeContent(sampleCode(workDir, sample), sample, prompt, response)
+	score, justification, err := cfg.judge.Score(ctx, cfg.rubric, content)
+	if err != nil {
+		score, trace := scoreHeuristic(sample, response, cfg)
+		trace.Justification = "judge failed, scored heuristically: " + err.Error()
+		return score, trace
+	}
+	trace := ScoreTrace{BaseScore: score, Justification: justification}
+	return computeScore(&trace), trace
+}
+
+// judgeContent assembles what the judge sees: the code sample (if any), the
+// task given to the agent and the agent's response.
+func judgeContent(code string, sample Sample, prompt, response string) string {
+	var b strings.Builder
+	if code != "" {
+		fmt.Fprintf(&b, "Code sample (%s):\n```\n%s\n```\n\n", sample.FilePath, code)
+	}
+	if prompt != "" {
+		fmt.Fprintf(&b, "Task given to the agent:\n%s\n\n", prompt)
+	}
+	fmt.Fprintf(&b, "Agent response:\n%s", response)
+	return b.String()
+}
+
+// sampleCode reads the sample's file from the workspace, truncated to
+// judgeMaxCodeBytes. It returns "" for samples without a readable file.
+func sampleCode(workDir string, sample Sample) string {
+	if sample.FilePath == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, sample.FilePath))
+	if err != nil {
+		return ""
+	}
+	return truncateCode(data)
+}
+
+// truncateCode returns a code sample cut to judgeMaxCodeBytes.
+func truncateCode(data []byte) string {
+	if len(data) > judgeMaxCodeBytes {
+		return string(data[:judgeMaxCodeBytes]) + "\n[truncated]"
+	}
+	return string(data)
+}
@@ -2,7 +2,9 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
@@ -14,7 +16,7 @@ import (
 // M1 sample selection and scoring constants.
 const (
 	m1SampleCount    = 1               // One file, run multiple times
-	m1Timeout        = 540 * time.Second // 3 runs of 180s each
+	m1RunTimeout     = 180 * time.Second // Timeout per run
 	m1Runs           = 3               // Number of repeat runs per sample
 	m1MinFileLOC     = 50              // Minimum file size for sample selection
 	m1MaxFileLOC     = 200             // Maximum file size for sample selection
@@ -27,6 +29,7 @@ const (
 	m1LowVariance    = 5.0             // Variance % below which score is excellent
 	m1MedVariance    = 15.0            // Variance % below which score is good
 	m1HighVariance   = 30.0            // Variance % below which score is fair
+	m1TruthPoints    = 9               // Points for a correct, reproducible function list above the minimum score
 )
 
 // M1 score trace delta values.
@@ -39,6 +42,8 @@ const (
 
 // M1Consistency measures task execution reproducibility across multiple runs.
 // It tests the same simple task 3 times and measures variance in completion.
+// With ground truth, each run is scored by precision/recall against the
+// file's actual functions and the runs by their agreement (Jaccard).
 //
 // Research basis: Agent benchmarks show ~13% variance in results; consistency
 // is critical for reliability in production use.
@@ -50,13 +55,22 @@ type m1Consistency struct {
 
 // newM1ConsistencyMetric creates a Task Execution Consistency metric.
 func newM1ConsistencyMetric() *m1Consistency {
+	return newM1ConsistencyMetricWith(m1Runs, m1SampleCount)
+}
+
+// newM1ConsistencyMetricWith creates a Task Execution Consistency metric with
+// the given run and sample counts; the timeout grows with both.
+func newM1ConsistencyMetricWith(runs, sampleCount int) *m1Consistency {
 	return &m1Consistency{
-		sampleCount: m1SampleCount,
-		timeout:     m1Timeout,
-		runs:        m1Runs,
+		sampleCount: sampleCount,
+		timeout:     m1RunTimeout * time.Duration(runs*sampleCount),
+		runs:        runs,
 	}
 }
 
+// callsPerSample implements CallsPerSample: every sample is run m.runs times.
+func (m *m1Consistency) callsPerSample() int { return m.runs }
+
 // ID returns the metric identifier.
 func (m *m1Consistency) ID() string { return "task_execution_consistency" }
 
@@ -74,7 +88,7 @@ func (m *m1Consistency) Timeout() time.Duration { return m.timeout }
 // SampleCount returns the number of samples to evaluate.
 func (m *m1Consistency) SampleCount() int { return m.sampleCount }
 
-// SelectSamples picks 1 file with moderate size (50-200 LOC) and 3-10 functions.
+// SelectSamples picks files (1 by default) with moderate size (50-200 LOC) and 3-10 functions.
 // Uses deterministic heuristics: count `func ` occurrences, prefer moderate complexity.
 func (m *m1Consistency) SelectSamples(targets []*types.AnalysisTarget) []Sample {
 	funcPattern := regexp.MustCompile(`(?m)^func\s+`)
@@ -153,7 +167,9 @@ func m1SelectTopCandidates(candidates []Sample, limit int) []Sample {
 	return candidates
 }
 
-// Execute runs the same task 3 times and measures variance.
+// Execute runs the same task on each sample several times. Samples with
+// ground truth are scored by correctness and agreement, others by variance.
+// The metric score is the mean across samples.
 func (m *m1Consistency) Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult {
 	result := MetricResult{
 		MetricID:   m.ID(),
@@ -167,49 +183,214 @@ func (m *m1Consistency) Execute(ctx context.Context, workDir string, samples []S
 		return result
 	}
 
-	runResults, scores := m.runConsistencyTrials(ctx, workDir, samples[0], executor)
-	result.Samples = runResults
+	var sampleScores []int
+	for _, sample := range samples {
+		runResults, scores := m.runConsistencyTrials(ctx, workDir, sample, executor)
+		result.Samples = append(result.Samples, runResults...)
+		if len(scores) == 0 {
+			continue
+		}
+		if sample.Functions != nil {
+			sampleScores = append(sampleScores, m1TruthScore(runResults))
+		} else {
+			sampleScores = append(sampleScores, m1VarianceScore(scores))
+		}
+	}
 	result.Duration = time.Since(startTime)
 
-	if len(scores) == 0 {
+	if len(sampleScores) == 0 {
 		result.Score = 0
 		result.Error = "all runs failed"
 		return result
 	}
 
-	result.Score = m1VarianceScore(scores)
+	total := 0
+	for _, s := range sampleScores {
+		total += s
+	}
+	result.Score = int(math.Round(float64(total) / float64(len(sampleScores))))
 	return result
 }
 
 func (m *m1Consistency) runConsistencyTrials(ctx context.Context, workDir string, sample Sample, executor Executor) ([]SampleResult, []int) {
 	var runResults []SampleResult
 	scores := make([]int, 0, m.runs)
-	perRunTimeout := m.timeout / time.Duration(m.runs)
+	perRunTimeout := m.timeout / time.Duration(m.runs*max(m.sampleCount, 1))
 
 	for i := 0; i < m.runs; i++ {
-		runCtx, cancel := context.WithTimeout(ctx, perRunTimeout)
+		sr := SampleResult{Sample: sample}
+		runStart := time.Now()
+		runCtx := recordSample(WithCall(ctx, Call{File: sample.FilePath, Run: i}), &sr)
 
 		prompt := fmt.Sprintf(`Read the file at %s and list all function names defined in it.
 Return ONLY a JSON array of function names, e.g.: ["func1", "func2"]
 Do not include any explanation, just the JSON array.`, sample.FilePath)
 
 		response, err := executor.ExecutePrompt(runCtx, workDir, prompt, "Read", perRunTimeout)
-		cancel()
 
-		sr := SampleResult{Sample: sample, Response: response, Prompt: prompt}
+		sr.Response, sr.Prompt, sr.Duration = response, prompt, time.Since(runStart)
 		if err != nil {
 			sr.Error = err.Error()
 			sr.Score = 0
 		} else {
-			sr.ScoreTrace = m1ScoreResponse(strings.TrimSpace(response))
+			if sample.Functions != nil {
+				sr.ScoreTrace = m1ScoreTruth(strings.TrimSpace(response), sample.Functions)
+			} else {
+				sr.ScoreTrace = m1ScoreResponse(strings.TrimSpace(response))
+			}
 			sr.Score = computeScore(&sr.ScoreTrace)
 			scores = append(scores, sr.Score)
 		}
 		runResults = append(runResults, sr)
 	}
+	m1SetRunAgreement(runResults)
 	return runResults, scores
 }
 
+// m1ScoreTruth scores one run's function list by its F1 against the
+// functions declared in the file.
+func m1ScoreTruth(response string, functions []string) ScoreTrace {
+	match := matchFunctionList(parseFunctionList(response), functions)
+	trace := ScoreTrace{BaseScore: minScore, GroundTruth: []GroundTruthMatch{match}}
+	delta := int(math.Round(match.F1() * m1TruthPoints))
+	trace.Indicators = append(trace.Indicators, IndicatorMatch{
+		Name: "ground_truth:functions", Matched: delta > 0, Delta: delta,
+	})
+	return trace
+}
+
+// m1SetRunAgreement records in each successful ground-truth run its mean
+// Jaccard similarity with the other runs' function lists.
+func m1SetRunAgreement(runResults []SampleResult) {
+	var sets []map[string]bool
+	var idx []int
+	for i, sr := range runResults {
+		if sr.Error != "" || sr.Sample.Functions == nil {
+			continue
+		}
+		sets = append(sets, normalizedSet(parseFunctionList(strings.TrimSpace(sr.Response))))
+		idx = append(idx, i)
+	}
+	if len(sets) < 2 {
+		return
+	}
+	for a, i := range idx {
+		sum := 0.0
+		for b := range sets {
+			if a != b {
+				sum += jaccard(sets[a], sets[b])
+			}
+		}
+		agreement := sum / float64(len(sets)-1)
+		runResults[i].ScoreTrace.RunAgreement = &agreement
+	}
+}
+
+// m1TruthScore combines correctness and reproducibility: the mean F1 of the
+// runs times their mean agreement, so that an agent repeating the same wrong
+// list scores as low as its correctness, and a correct but unstable one loses
+// points for the variation.
+func m1TruthScore(runResults []SampleResult) int {
+	var f1Sum, agreementSum float64
+	runs := 0
+	for _, sr := range runResults {
+		if sr.Error != "" || len(sr.ScoreTrace.GroundTruth) == 0 {
+			continue
+		}
+		f1Sum += sr.ScoreTrace.GroundTruth[0].F1()
+		agreement := 1.0
+		if sr.ScoreTrace.RunAgreement != nil {
+			agreement = *sr.ScoreTrace.RunAgreement
+		}
+		agreementSum += agreement
+		runs++
+	}
+	if runs == 0 {
+		return minScore
+	}
+	meanF1 := f1Sum / float64(runs)
+	meanAgreement := agreementSum / float64(runs)
+	return minScore + int(math.Round(meanF1*meanAgreement*m1TruthPoints))
+}
+
+// parseFunctionList extracts the function names from a JSON array in a
+// response, falling back to the quoted strings inside the brackets.
+func parseFunctionList(response string) []string {
+	start := strings.Index(response, "[")
+	end := strings.LastIndex(response, "]")
+	if start < 0 || end < start {
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal([]byte(response[start:end+1]), &names); err == nil {
+		return names
+	}
+	for _, m := range quotedStringPattern.FindAllStringSubmatch(response[start:end+1], -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+var quotedStringPattern = regexp.MustCompile(`"([^"]+)"`)
+
+// normalizeFunctionName reduces "(*Store).Save", "Store.Save" and "Save()" to "Save",
+// so that listings with or without receivers and classes compare equal.
+func normalizeFunctionName(name string) string {
+	name = strings.TrimSuffix(strings.TrimSpace(name), "()")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func normalizedSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[normalizeFunctionName(n)] = true
+	}
+	return set
+}
+
+// matchFunctionList compares a listed set of functions with the declared ones.
+func matchFunctionList(listed, functions []string) GroundTruthMatch {
+	match := GroundTruthMatch{Kind: "functions"}
+	listedSet := normalizedSet(listed)
+	declared := normalizedSet(functions)
+	for _, fn := range functions {
+		if listedSet[normalizeFunctionName(fn)] {
+			match.Matched = append(match.Matched, fn)
+		} else {
+			match.Missed = append(match.Missed, fn)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, n := range listed {
+		norm := normalizeFunctionName(n)
+		if !declared[norm] && !seen[norm] {
+			seen[norm] = true
+			match.Hallucinated = append(match.Hallucinated, n)
+		}
+	}
+	return match
+}
+
+// jaccard returns |a ∩ b| / |a ∪ b|, or 1 if both are empty.
+func jaccard(a, b map[string]bool) float64 {
+	union := len(b)
+	inter := 0
+	for k := range a {
+		if b[k] {
+			inter++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 1
+	}
+	return float64(inter) / float64(union)
+}
+
 func m1ScoreResponse(response string) ScoreTrace {
 	trace := ScoreTrace{BaseScore: 0}
 
@@ -29,8 +29,17 @@ const (
 type m2Comprehension struct {
 	sampleCount int
 	timeout     time.Duration
+	judge       Judge // scores responses with m2JudgeRubric; nil keeps heuristic scoring
 }
 
+// m2JudgeRubric is the judge rubric for comprehension answers.
+const m2JudgeRubric = `The agent was asked to explain what the code sample does.
+- 9-10: Correctly explains the purpose, the important control flow, error handling and edge cases, and the return values and side effects, all consistent with the code.
+- 6-8: Correct on purpose and main behavior but misses some branches, error paths or side effects.
+- 3-5: Vague or generic; restates names or structure without explaining behavior, or contains a notable error.
+- 1-2: Wrong, invented behavior, or no real explanation.
+Length and confident wording alone earn nothing; judge only accuracy and coverage against the code.`
+
 // newM2ComprehensionMetric creates a Code Behavior Comprehension metric.
 func newM2ComprehensionMetric() *m2Comprehension {
 	return &m2Comprehension{
@@ -160,6 +169,8 @@ Focus on:
 Be specific and reference actual code elements.`, sample.FilePath)
 		},
 		scoreResponse: m.scoreComprehensionResponse,
+		judge:         m.judge,
+		rubric:        m2JudgeRubric,
 	})
 }
 
@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
@@ -19,6 +20,7 @@ const (
 	m3BaseScore         = 2                // Starting score before heuristic adjustments
 	m3DepthPathCount    = 6                // Min path reference count for depth indicator
 	m3ExtensiveWordCount = 200             // Min word count for extensive response indicator
+	m3TruthPoints        = 9                // Points distributed by ground-truth F1 above the minimum score
 )
 
 // m3Navigation measures the agent's ability to trace dependencies across files.
@@ -29,8 +31,17 @@ const (
 type m3Navigation struct {
 	sampleCount int
 	timeout     time.Duration
+	judge       Judge // scores responses with m3JudgeRubric; nil keeps heuristic scoring
 }
 
+// m3JudgeRubric is the judge rubric for dependency traces without an import graph.
+const m3JudgeRubric = `The agent was asked to list the dependencies of the code sample, explain what each provides, and trace data flow from one function through other files.
+- 9-10: Lists the actual imports, explains each correctly, and traces a concrete data flow through real files and functions of the project.
+- 6-8: Imports are correct but the data flow is shallow or stays within the sample.
+- 3-5: Lists imports without explaining them, or the trace is generic.
+- 1-2: Invents files, functions or imports that the code does not use.
+Penalize file paths and function names that do not appear in or follow from the code.`
+
 // newM3NavigationMetric creates a Cross-File Navigation metric.
 func newM3NavigationMetric() *m3Navigation {
 	return &m3Navigation{
@@ -148,9 +159,51 @@ Format your response as:
 Reference actual file paths and function names from the codebase.`, sample.FilePath)
 		},
 		scoreResponse: m.scoreNavigationResponse,
+		scoreSample:   m.scoreNavigationSample,
+		judge:         m.judge,
+		rubric:        m3JudgeRubric,
 	})
 }
 
+// scoreNavigationSample scores against the import graph when the sample has
+// ground truth and falls back to heuristics otherwise.
+func (m *m3Navigation) scoreNavigationSample(sample Sample, response string) (int, ScoreTrace) {
+	if sample.Navigation == nil {
+		return m.scoreNavigationResponse(response)
+	}
+	return m.scoreNavigationTruth(sample.FilePath, response, sample.Navigation)
+}
+
+// scoreNavigationTruth scores the files and symbols the agent traced by their
+// F1 against the sample's actual dependencies and call targets, so that missed
+// dependencies and invented ones both cost points while verbosity earns none.
+// Files and symbols share the points; without project calls, files get all.
+func (m *m3Navigation) scoreNavigationTruth(samplePath, response string, truth *NavigationTruth) (int, ScoreTrace) {
+	files := matchNavigationFiles(response, samplePath, truth)
+	symbols := matchNavigationSymbols(response, truth)
+	trace := ScoreTrace{BaseScore: minScore, GroundTruth: []GroundTruthMatch{files, symbols}}
+
+	filePoints, symbolPoints := float64(m3TruthPoints), 0.0
+	if len(truth.Symbols) > 0 {
+		filePoints, symbolPoints = m3TruthPoints/2.0, m3TruthPoints/2.0
+	}
+	for _, g := range []struct {
+		match  GroundTruthMatch
+		points float64
+	}{{files, filePoints}, {symbols, symbolPoints}} {
+		if g.points == 0 {
+			continue
+		}
+		delta := int(math.Round(g.match.F1() * g.points))
+		trace.Indicators = append(trace.Indicators, IndicatorMatch{
+			Name:    "ground_truth:" + g.match.Kind,
+			Matched: delta > 0,
+			Delta:   delta,
+		})
+	}
+	return computeScore(&trace), trace
+}
+
 // scoreNavigationResponse uses grouped heuristics to score the navigation trace.
 // The ScoreTrace is the source of truth: FinalScore = BaseScore + sum(Deltas), clamped.
 //
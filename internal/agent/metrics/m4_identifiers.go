@@ -29,8 +29,17 @@ const (
 type m4Identifiers struct {
 	sampleCount int
 	timeout     time.Duration
+	judge       Judge // scores responses with m4JudgeRubric; nil keeps heuristic scoring
 }
 
+// m4JudgeRubric is the judge rubric for identifier interpretations.
+const m4JudgeRubric = `The agent was asked to interpret an identifier from its name alone, then verify the interpretation against the code sample.
+- 9-10: The name-based interpretation matches what the code actually does (purpose, kind and domain) and the verification cites the code correctly.
+- 6-8: Mostly right but misses the kind of identifier or part of its purpose.
+- 3-5: Only partially right, or the verification is missing or superficial.
+- 1-2: The interpretation contradicts the code.
+Judge the interpretation against the code, not the agent's own claim of accuracy.`
+
 // newM4IdentifiersMetric creates an Identifier Interpretability metric.
 func newM4IdentifiersMetric() *m4Identifiers {
 	return &m4Identifiers{
@@ -239,6 +248,8 @@ Format:
 - Accuracy: [how accurate was your interpretation?]`, sample.FunctionName, sample.FilePath, sample.StartLine)
 		},
 		scoreResponse: m.scoreIdentifierResponse,
+		judge:         m.judge,
+		rubric:        m4JudgeRubric,
 	})
 }
 
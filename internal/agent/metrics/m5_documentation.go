@@ -3,6 +3,7 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
@@ -20,6 +21,8 @@ const (
 	m5BlockCommentLinesEst   = 3                // Estimated average lines per block comment
 	m5PercentMultiplier      = 100              // Multiplier for density-to-percent display
 	m5BaseScore              = 3                // Starting score before heuristic adjustments
+	m5RecallPoints           = 9                // Points for detecting all seeded mismatches
+	m5FalsePositivePenalty   = 4                // Points lost when every report is a false positive
 )
 
 // m5Documentation measures the agent's ability to detect comment/code mismatches.
@@ -30,8 +33,17 @@ const (
 type m5Documentation struct {
 	sampleCount int
 	timeout     time.Duration
+	judge       Judge // scores responses with m5JudgeRubric; nil keeps heuristic scoring
 }
 
+// m5JudgeRubric is the judge rubric for documentation reviews without seeded mismatches.
+const m5JudgeRubric = `The agent was asked to check whether the comments in the code sample accurately describe the code and to report mismatches.
+- 9-10: Every reported mismatch is real and cites the comment and the contradicting code; no real mismatch in the sample is missed; accurate comments are not flagged.
+- 6-8: Finds the main real mismatches with minor omissions or one doubtful report.
+- 3-5: Generic remarks about documentation quality, or several unfounded reports.
+- 1-2: Reports mismatches that do not exist or misses obvious ones.
+Reporting every comment as suspicious is not thoroughness; false reports cost as much as misses.`
+
 // newM5DocumentationMetric creates a Documentation Accuracy Detection metric.
 func newM5DocumentationMetric() *m5Documentation {
 	return &m5Documentation{
@@ -154,14 +166,24 @@ Consider:
 
 Respond with JSON only: {"score": N, "reason": "brief explanation"}`
 
-// Execute asks the agent to detect documentation accuracy issues.
+// Execute asks the agent to detect documentation accuracy issues. Samples with
+// ground truth are evaluated on their seeded copy, written to workDir for the
+// duration of the metric.
 func (m *m5Documentation) Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult {
+	samples = append([]Sample(nil), samples...)
+	cleanup := WriteSeededDocs(workDir, samples)
+	defer cleanup()
+
 	return executeStandardMetric(ctx, workDir, samples, executor, executeConfig{
 		metricID:   m.ID(),
 		metricName: m.Name(),
 		timeout:    m.timeout,
 		tools:      "Read",
 		buildPrompt: func(sample Sample) string {
+			filePath := sample.FilePath
+			if sample.Seeded != nil {
+				filePath = sample.Seeded.Path
+			}
 			return fmt.Sprintf(`Analyze the documentation accuracy in %s.
 
 Your task:
@@ -184,12 +206,49 @@ For each mismatch:
 - Code does: [what the code actually does]
 - Issue: [why this is a mismatch]
 
-If all documentation appears accurate, state that clearly.`, sample.FilePath)
+If all documentation appears accurate, state that clearly.`, filePath)
 		},
 		scoreResponse: m.scoreDocumentationResponse,
+		scoreSample:   m.scoreDocumentationSample,
+		judge:         m.judge,
+		rubric:        m5JudgeRubric,
 	})
 }
 
+// scoreDocumentationSample scores against the seeded mismatches when the
+// sample has them and falls back to heuristics otherwise.
+func (m *m5Documentation) scoreDocumentationSample(sample Sample, response string) (int, ScoreTrace) {
+	if sample.Seeded == nil {
+		return m.scoreDocumentationResponse(response)
+	}
+	return m.scoreSeededResponse(response, sample.Seeded)
+}
+
+// scoreSeededResponse scores detection recall of the injected mismatches and
+// penalizes the false-positive rate of the reported ones, so that listing
+// every comment as suspicious does not pay off.
+func (m *m5Documentation) scoreSeededResponse(response string, doc *SeededDoc) (int, ScoreTrace) {
+	match := matchSeededMismatches(response, doc)
+	trace := ScoreTrace{BaseScore: minScore, GroundTruth: []GroundTruthMatch{match}}
+
+	detected := int(math.Round(match.Recall() * m5RecallPoints))
+	trace.Indicators = append(trace.Indicators, IndicatorMatch{
+		Name: "seeded:detected", Matched: detected > 0, Delta: detected,
+	})
+
+	reports := len(splitReports(response))
+	falsePositiveRate := 0.0
+	if reports > 0 {
+		falsePositiveRate = float64(len(match.Hallucinated)) / float64(reports)
+	}
+	penalty := int(math.Round(falsePositiveRate * m5FalsePositivePenalty))
+	trace.Indicators = append(trace.Indicators, IndicatorMatch{
+		Name: "seeded:false_positives", Matched: len(match.Hallucinated) > 0, Delta: -penalty,
+	})
+
+	return computeScore(&trace), trace
+}
+
 // scoreDocumentationResponse uses grouped heuristics to score the documentation analysis.
 // The ScoreTrace is the source of truth: FinalScore = BaseScore + sum(Deltas), clamped.
 //
@@ -0,0 +1,534 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// M6 sample selection and scoring constants.
+const (
+	m6SampleCount     = 2                 // One rename and one add-parameter task
+	m6Timeout         = 600 * time.Second // Total agent timeout across all tasks
+	m6VerifyTimeout   = 300 * time.Second // Timeout per build or test command
+	m6MaxCallerFiles  = 4                 // Keep tasks small: at most this many calling files
+	m6IdealCallers    = 2                 // Preferred number of calling files
+	m6DiffSlackLines  = 4                 // Changed lines a focused diff may add beyond the call sites
+	m6BodyLines       = 10                // add_parameter: lines the new parameter may take in the body
+	m6AppliedPoints   = 3                 // Points for making the requested change
+	m6VerifiedPoints  = 4                 // Points for the project still building and passing tests
+	m6OutputTailBytes = 2000              // Failing command output kept in the trace
+	m6Tools           = "Read,Glob,Grep,Edit,Write"
+	m6ParamName       = "verbose" // Name of the parameter add_parameter tasks introduce
+)
+
+// Change task kinds.
+const (
+	changeRename       = "rename"
+	changeAddParameter = "add_parameter"
+)
+
+// ChangeTask is a small synthetic change an agent makes in a throwaway worktree.
+type ChangeTask struct {
+	Kind     string         // "rename" or "add_parameter"
+	NewName  string         // rename: the function's new name
+	Language types.Language // selects the build and test commands
+	Files    []string       // files expected to change: the declaring file and, where needed, its callers
+	MaxLines int            // changed lines (added + deleted) a focused diff stays within
+}
+
+// ChangeOutcome records how the project fared after an agent's change.
+type ChangeOutcome struct {
+	Passed       bool     // build and tests succeeded after the change
+	Output       string   // failing command and the tail of its output
+	LinesAdded   int      // lines added across all files
+	LinesDeleted int      // lines deleted across all files
+	FilesTouched []string // files added, modified or deleted
+}
+
+// commandRunner runs a build or test command line in dir.
+type commandRunner func(ctx context.Context, dir, command string) (output string, err error)
+
+// m6ChangeSuccess measures whether an agent can make small, well-defined code
+// changes that leave the project building with passing tests. Each task runs
+// in its own git worktree, so edits never reach the project or other metrics.
+type m6ChangeSuccess struct {
+	sampleCount int
+	timeout     time.Duration
+	run         commandRunner
+}
+
+// newM6ChangeSuccessMetric creates a Change Success metric.
+func newM6ChangeSuccessMetric() *m6ChangeSuccess {
+	return &m6ChangeSuccess{
+		sampleCount: m6SampleCount,
+		timeout:     m6Timeout,
+		run:         runCommandLine,
+	}
+}
+
+// ID returns the metric identifier.
+func (m *m6ChangeSuccess) ID() string { return "change_success" }
+
+// Name returns the human-readable metric name.
+func (m *m6ChangeSuccess) Name() string { return "Change Success" }
+
+// Description returns what this metric measures.
+func (m *m6ChangeSuccess) Description() string {
+	return "Measures whether agent edits keep the project building and its tests passing"
+}
+
+// Timeout returns the per-metric timeout duration.
+func (m *m6ChangeSuccess) Timeout() time.Duration { return m.timeout }
+
+// SampleCount returns the number of samples to evaluate.
+func (m *m6ChangeSuccess) SampleCount() int { return m.sampleCount }
+
+// m6FuncPatterns match top-level function declarations that are safe to
+// rename or extend, capturing the name.
+var m6FuncPatterns = map[types.Language]*regexp.Regexp{
+	types.LangGo:         regexp.MustCompile(`(?m)^func ([A-Za-z_]\w*)\(`),
+	types.LangPython:     regexp.MustCompile(`(?m)^def ([A-Za-z]\w*)\(`),
+	types.LangTypeScript: regexp.MustCompile(`(?m)^export (?:async )?function ([A-Za-z_$][\w$]*)\s*\(`),
+}
+
+// m6MethodPatterns match method declarations. Functions sharing a name with a
+// method are skipped, since their call sites cannot be told apart by text.
+var m6MethodPatterns = map[types.Language]*regexp.Regexp{
+	types.LangGo:     regexp.MustCompile(`(?m)^func \([^)]*\) ([A-Za-z_]\w*)\(`),
+	types.LangPython: regexp.MustCompile(`(?m)^[ \t]+def ([A-Za-z_]\w*)\(`),
+}
+
+// m6Candidate is a function with its call sites, a possible change target.
+type m6Candidate struct {
+	file        types.SourceFile
+	name        string
+	lang        types.Language
+	sites       int      // calls across the target, excluding the declaration
+	callerFiles []string // other files calling the function
+}
+
+// SelectSamples picks one function to rename and one to extend with a
+// parameter. Candidates are declared once, called from 1-4 other files and
+// written in a language with known build commands; fewer call sites make a
+// smaller task.
+func (m *m6ChangeSuccess) SelectSamples(targets []*types.AnalysisTarget) []Sample {
+	candidates := m6CollectCandidates(targets)
+	sort.Slice(candidates, func(i, j int) bool {
+		si, sj := m6SelectionScore(candidates[i]), m6SelectionScore(candidates[j])
+		if si != sj {
+			return si > sj
+		}
+		if candidates[i].file.RelPath != candidates[j].file.RelPath {
+			return candidates[i].file.RelPath < candidates[j].file.RelPath
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var samples []Sample
+	for _, kind := range []string{changeRename, changeAddParameter} {
+		if len(samples) >= m.sampleCount {
+			break
+		}
+		for _, c := range candidates {
+			if len(samples) > 0 && samples[0].FunctionName == c.name {
+				continue
+			}
+			samples = append(samples, m6NewSample(c, kind))
+			break
+		}
+	}
+	return samples
+}
+
+// m6CollectCandidates finds uniquely declared functions called from other files.
+func m6CollectCandidates(targets []*types.AnalysisTarget) []m6Candidate {
+	var candidates []m6Candidate
+	for _, target := range targets {
+		pattern, ok := m6FuncPatterns[target.Language]
+		if !ok || target.Language.BuildCommands() == nil {
+			continue
+		}
+
+		declared := make(map[string]int)
+		var decls []m6Candidate
+		for _, file := range target.Files {
+			for _, match := range pattern.FindAllSubmatch(file.Content, -1) {
+				name := string(match[1])
+				declared[name]++
+				if file.Class == types.ClassSource && !m6ReservedName(name) {
+					decls = append(decls, m6Candidate{file: file, name: name, lang: target.Language})
+				}
+			}
+			if methods, ok := m6MethodPatterns[target.Language]; ok {
+				for _, match := range methods.FindAllSubmatch(file.Content, -1) {
+					declared[string(match[1])]++
+				}
+			}
+		}
+
+		for _, c := range decls {
+			if declared[c.name] != 1 {
+				continue
+			}
+			call := regexp.MustCompile(`\b` + regexp.QuoteMeta(c.name) + `\s*\(`)
+			for _, file := range target.Files {
+				n := len(call.FindAllIndex(file.Content, -1))
+				if file.RelPath == c.file.RelPath {
+					n-- // the declaration itself
+				} else if n > 0 {
+					c.callerFiles = append(c.callerFiles, file.RelPath)
+				}
+				c.sites += n
+			}
+			if len(c.callerFiles) > 0 && len(c.callerFiles) <= m6MaxCallerFiles {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	return candidates
+}
+
+// m6ReservedName reports names a change task must not touch: entry points,
+// test functions and private helpers.
+func m6ReservedName(name string) bool {
+	return name == "main" || name == "init" || strings.HasPrefix(name, "Test") ||
+		strings.HasPrefix(name, "test_") || strings.HasPrefix(name, "_")
+}
+
+// m6SelectionScore prefers functions with a couple of calling files and few call sites.
+func m6SelectionScore(c m6Candidate) float64 {
+	return -float64(abs(len(c.callerFiles)-m6IdealCallers)) - float64(c.sites)/100
+}
+
+// m6NewSample builds the change task of the given kind for a candidate.
+func m6NewSample(c m6Candidate, kind string) Sample {
+	task := &ChangeTask{Kind: kind, Language: c.lang, Files: []string{c.file.RelPath}}
+	var desc string
+	switch kind {
+	case changeRename:
+		task.NewName = c.name + "Renamed"
+		if c.lang == types.LangPython {
+			task.NewName = c.name + "_renamed"
+		}
+		task.Files = append(task.Files, c.callerFiles...)
+		task.MaxLines = 2*(c.sites+1) + m6DiffSlackLines
+		desc = fmt.Sprintf("Rename %s (%d call sites in %d files)", c.name, c.sites, len(c.callerFiles))
+	case changeAddParameter:
+		task.MaxLines = 2 + m6BodyLines
+		if c.lang == types.LangGo {
+			// Go has no default arguments, so every caller passes the zero value.
+			task.Files = append(task.Files, c.callerFiles...)
+			task.MaxLines += 2 * c.sites
+		}
+		desc = fmt.Sprintf("Add a parameter to %s (%d call sites in %d files)", c.name, c.sites, len(c.callerFiles))
+	}
+	return Sample{
+		FilePath:       c.file.RelPath,
+		FunctionName:   c.name,
+		SelectionScore: m6SelectionScore(c),
+		Description:    desc,
+		Change:         task,
+	}
+}
+
+// changePrompt describes the change task to the agent.
+func changePrompt(sample Sample) string {
+	task := sample.Change
+	var instructions string
+	switch {
+	case task.Kind == changeRename:
+		instructions = fmt.Sprintf(`Rename the function %s, declared in %s, to %s.
+Update every caller in the repository so that the project still builds and its tests pass.`,
+			sample.FunctionName, sample.FilePath, task.NewName)
+	case task.Language == types.LangGo:
+		instructions = fmt.Sprintf(`Add a new last parameter %s bool to the function %s, declared in %s.
+When %s is false the function must behave exactly as before. Update every caller to pass false.`,
+			m6ParamName, sample.FunctionName, sample.FilePath, m6ParamName)
+	default:
+		defaultValue := "false"
+		if task.Language == types.LangPython {
+			defaultValue = "False"
+		}
+		instructions = fmt.Sprintf(`Add a new optional last parameter %s to the function %s, declared in %s, with the default value %s.
+With the default value the function must behave exactly as before, so existing callers need no changes.`,
+			m6ParamName, sample.FunctionName, sample.FilePath, defaultValue)
+	}
+	return instructions + `
+
+Edit the files directly. Change nothing else.
+When you are done, list the files you changed.`
+}
+
+// Execute gives the agent each change task in a fresh worktree of workDir,
+// then runs the language's build and test commands there. Tasks whose
+// project does not build and pass its tests before the change are not scored.
+func (m *m6ChangeSuccess) Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult {
+	result := MetricResult{
+		MetricID:   m.ID(),
+		MetricName: m.Name(),
+	}
+	startTime := time.Now()
+
+	if len(samples) == 0 {
+		return emptyMetricResult(result, startTime)
+	}
+
+	timePerSample := m.timeout / time.Duration(len(samples))
+	baselines := make(map[types.Language]error)
+	var sampleResults []SampleResult
+	totalScore, successCount := 0, 0
+	for _, sample := range samples {
+		sr := m.runChangeTask(ctx, workDir, sample, executor, timePerSample, baselines)
+		if sr.Error == "" {
+			totalScore += sr.Score
+			successCount++
+		}
+		sampleResults = append(sampleResults, sr)
+	}
+	return finalizeMetricResult(result, sampleResults, totalScore, successCount, startTime)
+}
+
+// runChangeTask runs one change task. baselines caches per language whether
+// the unchanged project builds and passes its tests.
+func (m *m6ChangeSuccess) runChangeTask(ctx context.Context, workDir string, sample Sample, executor Executor, timeout time.Duration, baselines map[types.Language]error) (sr SampleResult) {
+	sr.Sample = sample
+	start := time.Now()
+	defer func() { sr.Duration = time.Since(start) }()
+
+	task := sample.Change
+	if task == nil {
+		sr.Error = "sample has no change task"
+		return sr
+	}
+
+	dir, cleanup, err := addWorktree(ctx, workDir)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	defer cleanup()
+
+	baseline, checked := baselines[task.Language]
+	if !checked {
+		baseline = m.verify(ctx, dir, task.Language)
+		baselines[task.Language] = baseline
+		// Drop build artifacts .gitignore misses, so they do not count as changes.
+		gitOutput(ctx, dir, "clean", "-fd")
+	}
+	if baseline != nil {
+		sr.Error = fmt.Sprintf("build or tests fail before the change: %v", baseline)
+		return sr
+	}
+	params := declaredParams(dir, sample)
+
+	sr.Prompt = changePrompt(sample)
+	sr.Response, err = executor.ExecutePrompt(recordSample(ctx, &sr), dir, sr.Prompt, m6Tools, timeout)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+
+	outcome, err := diffOutcome(ctx, dir)
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	applied := changeApplied(dir, sample, params)
+	if applied {
+		if verifyErr := m.verify(ctx, dir, task.Language); verifyErr != nil {
+			outcome.Output = verifyErr.Error()
+		} else {
+			outcome.Passed = true
+		}
+	}
+	sr.ScoreTrace = scoreChange(task, outcome, applied)
+	sr.Score = sr.ScoreTrace.FinalScore
+	return sr
+}
+
+// scoreChange scores a change: whether it was made at all, whether the
+// project still builds and passes its tests, and whether the diff stayed
+// small and within the expected files. Build and diff points require the
+// change to be made, so that doing nothing does not score.
+func scoreChange(task *ChangeTask, outcome *ChangeOutcome, applied bool) ScoreTrace {
+	trace := ScoreTrace{BaseScore: minScore, Change: outcome}
+	add := func(name string, matched bool, points int) {
+		delta := 0
+		if matched {
+			delta = points
+		}
+		trace.Indicators = append(trace.Indicators, IndicatorMatch{Name: name, Matched: matched, Delta: delta})
+	}
+
+	expected := make(map[string]bool, len(task.Files))
+	for _, f := range task.Files {
+		expected[f] = true
+	}
+	scoped := len(outcome.FilesTouched) > 0
+	for _, f := range outcome.FilesTouched {
+		if !expected[f] {
+			scoped = false
+		}
+	}
+
+	add("change:applied", applied, m6AppliedPoints)
+	add("change:build_and_tests_pass", applied && outcome.Passed, m6VerifiedPoints)
+	add(fmt.Sprintf("change:diff<=%d_lines", task.MaxLines), applied && outcome.LinesAdded+outcome.LinesDeleted <= task.MaxLines, 1)
+	add("change:files_in_scope", applied && scoped, 1)
+	computeScore(&trace)
+	return trace
+}
+
+// changeApplied reports whether the worktree contains the requested change:
+// for a rename, the new declaration and no remaining calls of the old name in
+// the expected files; for a new parameter, a longer parameter list.
+func changeApplied(dir string, sample Sample, paramsBefore int) bool {
+	task := sample.Change
+	if task.Kind == changeAddParameter {
+		after := declaredParams(dir, sample)
+		return paramsBefore >= 0 && after > paramsBefore
+	}
+
+	decl, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(sample.FilePath)))
+	if err != nil || !declaresFunction(decl, task.Language, task.NewName) {
+		return false
+	}
+	oldCall := regexp.MustCompile(`\b` + regexp.QuoteMeta(sample.FunctionName) + `\s*\(`)
+	for _, f := range task.Files {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(f)))
+		if err == nil && oldCall.Match(content) {
+			return false
+		}
+	}
+	return true
+}
+
+// declaresFunction reports whether content declares a top-level function name.
+func declaresFunction(content []byte, lang types.Language, name string) bool {
+	for _, m := range m6FuncPatterns[lang].FindAllSubmatch(content, -1) {
+		if string(m[1]) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredParams counts the parameters of the sample's function in the
+// worktree, or returns -1 if the declaration is not found.
+func declaredParams(dir string, sample Sample) int {
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(sample.FilePath)))
+	if err != nil {
+		return -1
+	}
+	name := regexp.QuoteMeta(sample.FunctionName)
+	var decl *regexp.Regexp
+	switch sample.Change.Language {
+	case types.LangGo:
+		decl = regexp.MustCompile(`(?m)^func ` + name + `\(([^)]*)\)`)
+	case types.LangPython:
+		decl = regexp.MustCompile(`(?m)^def ` + name + `\(([^)]*)\)`)
+	default:
+		decl = regexp.MustCompile(`(?m)^export (?:async )?function ` + name + `\s*\(([^)]*)\)`)
+	}
+	m := decl.FindSubmatch(content)
+	if m == nil {
+		return -1
+	}
+	n := 0
+	for _, p := range strings.Split(string(m[1]), ",") {
+		if strings.TrimSpace(p) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// verify runs the language's build and test commands in dir and returns the
+// first failure with the tail of its output.
+func (m *m6ChangeSuccess) verify(ctx context.Context, dir string, lang types.Language) error {
+	for _, command := range lang.BuildCommands() {
+		cmdCtx, cancel := context.WithTimeout(ctx, m6VerifyTimeout)
+		out, err := m.run(cmdCtx, dir, command)
+		cancel()
+		if err != nil {
+			out = strings.TrimSpace(out)
+			if len(out) > m6OutputTailBytes {
+				out = "..." + out[len(out)-m6OutputTailBytes:]
+			}
+			return fmt.Errorf("%s: %w\n%s", command, err, out)
+		}
+	}
+	return nil
+}
+
+// runCommandLine runs a command line split on whitespace, without a shell.
+func runCommandLine(ctx context.Context, dir, command string) (string, error) {
+	fields := strings.Fields(command)
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// addWorktree checks out HEAD of the repository at dir into a new temporary
+// worktree and returns it with a function that removes it again.
+func addWorktree(ctx context.Context, dir string) (string, func(), error) {
+	worktree, err := os.MkdirTemp("", "ars-c7-change-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	if _, err := gitOutput(ctx, dir, "worktree", "add", "--detach", worktree, "HEAD"); err != nil {
+		os.RemoveAll(worktree)
+		return "", nil, fmt.Errorf("change tasks need a git repository: %w", err)
+	}
+	cleanup := func() {
+		gitOutput(context.Background(), dir, "worktree", "remove", "--force", worktree)
+		os.RemoveAll(worktree)
+	}
+	return worktree, cleanup, nil
+}
+
+// diffOutcome stages all changes in the worktree and returns their size and files.
+func diffOutcome(ctx context.Context, dir string) (*ChangeOutcome, error) {
+	if _, err := gitOutput(ctx, dir, "add", "-A"); err != nil {
+		return nil, err
+	}
+	out, err := gitOutput(ctx, dir, "diff", "--cached", "--numstat", "--no-renames")
+	if err != nil {
+		return nil, err
+	}
+	outcome := &ChangeOutcome{}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0]) // "-" for binary files
+		deleted, _ := strconv.Atoi(fields[1])
+		outcome.LinesAdded += added
+		outcome.LinesDeleted += deleted
+		outcome.FilesTouched = append(outcome.FilesTouched, fields[2])
+	}
+	return outcome, nil
+}
+
+// gitOutput runs git in dir and returns its output.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,315 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// M7 sample selection and scoring constants.
+const (
+	m7SampleCount     = 3                 // Number of symbols to locate
+	m7Timeout         = 360 * time.Second // Total timeout across all samples
+	m7Candidates      = 3                 // Ranked answers the agent may give
+	m7MinDescWords    = 5                 // Descriptions shorter than this are too vague to search for
+	m7MaxDescWords    = 40                // Longer doc comments are cut to their first sentences
+	m7Top1Points      = 9                 // Points for naming the symbol first
+	m7Top3Points      = 6                 // Points for naming the symbol second or third
+	m7FilePoints      = 3                 // Points for naming the right file with another symbol
+	m7ToolCallsHigh   = 10                // Tool calls above this cost a point
+	m7ToolCallsExcess = 20                // Tool calls above this cost another point
+	m7Tools           = "Glob,Grep"
+)
+
+// FeatureTruth is the ground truth for a feature localization sample: a
+// documented symbol and the description derived from its doc comment.
+type FeatureTruth struct {
+	Symbol      string // the function or type implementing the feature
+	Kind        string // "func" or "type"
+	File        string // file declaring the symbol
+	Description string // what the symbol does, without its name
+}
+
+// LocalizationOutcome records where the expected symbol ranked among the
+// agent's answers.
+type LocalizationOutcome struct {
+	Expected  string   // "file: Symbol" the agent should have named
+	Answers   []string // the agent's ranked "file: Symbol" answers
+	Rank      int      // 1-based rank of the expected symbol; 0 if not named
+	FileRank  int      // 1-based rank of the first answer in the expected file; 0 if none
+	ToolCalls int      // tool calls the agent made; -1 if the backend does not report them
+}
+
+// m7Localization measures whether an agent can find the code implementing a
+// feature described in plain language. ARS takes the description from a
+// symbol's doc comment, so the expected answer is known; the agent only has
+// search tools, so it cannot read its way through the project.
+//
+// Research basis: Agentless (Xia et al., 2024) resolves SWE-bench issues by
+// first localizing the relevant files and functions; a repair can only
+// succeed where localization did.
+type m7Localization struct {
+	sampleCount int
+	timeout     time.Duration
+}
+
+// newM7LocalizationMetric creates a Feature Localization metric.
+func newM7LocalizationMetric() *m7Localization {
+	return &m7Localization{
+		sampleCount: m7SampleCount,
+		timeout:     m7Timeout,
+	}
+}
+
+// ID returns the metric identifier.
+func (m *m7Localization) ID() string { return "feature_localization" }
+
+// Name returns the human-readable metric name.
+func (m *m7Localization) Name() string { return "Feature Localization" }
+
+// Description returns what this metric measures.
+func (m *m7Localization) Description() string {
+	return "Measures whether an agent can find the code implementing a described feature"
+}
+
+// Timeout returns the per-metric timeout duration.
+func (m *m7Localization) Timeout() time.Duration { return m.timeout }
+
+// SampleCount returns the number of samples to evaluate.
+func (m *m7Localization) SampleCount() int { return m.sampleCount }
+
+// m7DocumentedPatterns match documented exported top-level functions and types.
+var m7DocumentedPatterns = map[types.Language]*regexp.Regexp{
+	types.LangGo:         regexp.MustCompile(`(?m)^//.*\n(?:func|type) [A-Z]`),
+	types.LangPython:     regexp.MustCompile(`(?m)^(?:def|class) [A-Za-z]\w*[^\n]*:[ \t]*\n[ \t]+[rRuU]?(?:"""|''')`),
+	types.LangTypeScript: regexp.MustCompile(`(?m)\*/[ \t]*\nexport (?:default )?(?:async )?(?:function|class) `),
+}
+
+// SelectSamples picks the source files with the most documented exports. The
+// symbol to locate is chosen when the ground truth is attached.
+func (m *m7Localization) SelectSamples(targets []*types.AnalysisTarget) []Sample {
+	var candidates []Sample
+	for _, target := range targets {
+		pattern, ok := m7DocumentedPatterns[target.Language]
+		if !ok {
+			continue
+		}
+		for _, file := range target.Files {
+			if file.Class != types.ClassSource {
+				continue
+			}
+			n := len(pattern.FindAllIndex(file.Content, -1))
+			if n == 0 {
+				continue
+			}
+			candidates = append(candidates, Sample{
+				FilePath:       file.RelPath,
+				SelectionScore: float64(n),
+				Description:    fmt.Sprintf("Documented exports (%d)", n),
+			})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].SelectionScore != candidates[j].SelectionScore {
+			return candidates[i].SelectionScore > candidates[j].SelectionScore
+		}
+		return candidates[i].FilePath < candidates[j].FilePath
+	})
+	if len(candidates) > m.sampleCount {
+		candidates = candidates[:m.sampleCount]
+	}
+	return candidates
+}
+
+// attachFeature sets the feature a sample asks for: the symbol whose doc
+// comment gives the longest usable description, the first on ties.
+func attachFeature(sample *Sample, symbols []DocumentedSymbol) {
+	best, bestWords := -1, 0
+	var bestDesc string
+	for i, sym := range symbols {
+		desc := describeFeature(sym.Name, sym.Doc)
+		if words := len(strings.Fields(desc)); words > bestWords {
+			best, bestWords, bestDesc = i, words, desc
+		}
+	}
+	if best < 0 {
+		return
+	}
+	sym := symbols[best]
+	sample.Feature = &FeatureTruth{Symbol: sym.Name, Kind: sym.Kind, File: sample.FilePath, Description: bestDesc}
+	sample.FunctionName = sym.Name
+	sample.Description = fmt.Sprintf("Locate %s from its doc comment", sym.Name)
+}
+
+// sentenceEnd matches the end of a sentence within a doc comment.
+var sentenceEnd = regexp.MustCompile(`[.!?](?:\s|$)`)
+
+// describeFeature turns a doc comment into a description of what the symbol
+// does without naming it: the first sentences of the first paragraph, with a
+// leading symbol name (the Go convention "Load reads ...") removed and
+// further mentions replaced by "it" or dropped. It returns "" if too little text remains.
+func describeFeature(name, doc string) string {
+	paragraph := strings.SplitN(strings.TrimSpace(doc), "\n\n", 2)[0]
+	text := strings.Join(strings.Fields(paragraph), " ")
+	quoted := regexp.QuoteMeta(name)
+	text = regexp.MustCompile(`^(?:(?:[Aa]n?|[Tt]he)\s+)?`+quoted+`(?:\(\))?\s+`).ReplaceAllString(text, "")
+	mention := regexp.MustCompile(`\b` + quoted + `\b(?:\(\))?`)
+
+	// Take sentences until the description is specific enough. Later
+	// sentences naming the symbol (usage notes) are left out.
+	var desc string
+	for text != "" && len(strings.Fields(desc)) < m7MinDescWords {
+		end := len(text)
+		if loc := sentenceEnd.FindStringIndex(text); loc != nil {
+			end = loc[0] + 1
+		}
+		sentence := text[:end]
+		if desc != "" && mention.MatchString(sentence) {
+			break
+		}
+		desc = strings.TrimSpace(desc + " " + mention.ReplaceAllString(sentence, "it"))
+		text = strings.TrimSpace(text[end:])
+	}
+
+	words := strings.Fields(desc)
+	if len(words) < m7MinDescWords {
+		return ""
+	}
+	if len(words) > m7MaxDescWords {
+		words = append(words[:m7MaxDescWords], "...")
+	}
+	desc = strings.Join(words, " ")
+	return strings.ToUpper(desc[:1]) + desc[1:]
+}
+
+// localizationPrompt asks the agent to find the sample's symbol from its description.
+func localizationPrompt(sample Sample) string {
+	kind := "a top-level function (not a method)"
+	if sample.Feature.Kind == "type" {
+		kind = "a type (class, struct or interface)"
+	}
+	return fmt.Sprintf(`Find where this feature is implemented in the repository:
+
+"%s"
+
+It is implemented by %s. Search the code with the tools you have to find it.
+
+Answer with up to %d candidates, most likely first, one per line in this format:
+1. path/to/file: SymbolName
+Give paths relative to the repository root and nothing else.`, sample.Feature.Description, kind, m7Candidates)
+}
+
+// localizationAnswer matches one "N. path: Symbol" line of an answer. The path
+// may be in backticks and carry a line number; the symbol may be qualified.
+var localizationAnswer = regexp.MustCompile("(?m)^\\s*(?:\\d+[.)]|[-*])\\s*`?([\\w./-]*\\w\\.(?:go|py|pyi|ts|tsx|js|jsx|mjs|cjs))(?::\\d+)?`?\\s*(?::|-|—|#|,)?\\s*`?([A-Za-z_$][\\w$]*(?:\\.[A-Za-z_$][\\w$]*)*)")
+
+// parseLocalizationAnswers returns the agent's ranked answers as file and
+// symbol (last segment of a qualified name), at most m7Candidates.
+func parseLocalizationAnswers(response string) [][2]string {
+	var answers [][2]string
+	for _, m := range localizationAnswer.FindAllStringSubmatch(response, -1) {
+		parts := strings.Split(m[2], ".")
+		answers = append(answers, [2]string{strings.TrimPrefix(m[1], "./"), parts[len(parts)-1]})
+		if len(answers) == m7Candidates {
+			break
+		}
+	}
+	return answers
+}
+
+// scoreLocalization scores an answer by the rank of the expected symbol, with
+// partial credit for the right file. Finding it with many tool calls costs up
+// to two points, so that agents that search efficiently score higher.
+func scoreLocalization(truth *FeatureTruth, response string, toolCalls int) ScoreTrace {
+	outcome := &LocalizationOutcome{Expected: truth.File + ": " + truth.Symbol, ToolCalls: toolCalls}
+	for i, a := range parseLocalizationAnswers(response) {
+		outcome.Answers = append(outcome.Answers, a[0]+": "+a[1])
+		if !pathMatches(a[0], truth.File) {
+			continue
+		}
+		if outcome.FileRank == 0 {
+			outcome.FileRank = i + 1
+		}
+		if outcome.Rank == 0 && a[1] == truth.Symbol {
+			outcome.Rank = i + 1
+		}
+	}
+
+	trace := ScoreTrace{BaseScore: minScore, Localization: outcome}
+	add := func(name string, matched bool, points int) {
+		delta := 0
+		if matched {
+			delta = points
+		}
+		trace.Indicators = append(trace.Indicators, IndicatorMatch{Name: name, Matched: matched, Delta: delta})
+	}
+	found := outcome.FileRank > 0
+	add("localization:top1", outcome.Rank == 1, m7Top1Points)
+	add(fmt.Sprintf("localization:top%d", m7Candidates), outcome.Rank > 1, m7Top3Points)
+	add("localization:file_only", found && outcome.Rank == 0, m7FilePoints)
+	add(fmt.Sprintf("tool_calls>%d", m7ToolCallsHigh), found && toolCalls > m7ToolCallsHigh, -1)
+	add(fmt.Sprintf("tool_calls>%d", m7ToolCallsExcess), found && toolCalls > m7ToolCallsExcess, -1)
+	computeScore(&trace)
+	return trace
+}
+
+// Execute asks the agent to locate each sample's symbol and counts the tool
+// calls it needs. Samples without a documented symbol (no ground truth) fail.
+func (m *m7Localization) Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult {
+	result := MetricResult{
+		MetricID:   m.ID(),
+		MetricName: m.Name(),
+	}
+	startTime := time.Now()
+
+	if len(samples) == 0 {
+		return emptyMetricResult(result, startTime)
+	}
+
+	timePerSample := m.timeout / time.Duration(len(samples))
+	var sampleResults []SampleResult
+	totalScore, successCount := 0, 0
+	for _, sample := range samples {
+		sr := m.locate(ctx, workDir, sample, executor, timePerSample)
+		if sr.Error == "" {
+			totalScore += sr.Score
+			successCount++
+		}
+		sampleResults = append(sampleResults, sr)
+	}
+	return finalizeMetricResult(result, sampleResults, totalScore, successCount, startTime)
+}
+
+// locate runs one localization prompt.
+func (m *m7Localization) locate(ctx context.Context, workDir string, sample Sample, executor Executor, timeout time.Duration) (sr SampleResult) {
+	sr.Sample = sample
+	start := time.Now()
+	defer func() { sr.Duration = time.Since(start) }()
+
+	if sample.Feature == nil {
+		sr.Error = "no documented symbol with a usable description"
+		return sr
+	}
+
+	sampleCtx := recordSample(WithCall(ctx, Call{File: sample.FilePath}), &sr)
+	sr.Prompt = localizationPrompt(sample)
+	response, err := executor.ExecutePrompt(sampleCtx, workDir, sr.Prompt, m7Tools, timeout)
+	sr.Response = response
+	if err != nil {
+		sr.Error = err.Error()
+		return sr
+	}
+	toolCalls := -1
+	if sr.ToolCalls != nil {
+		toolCalls = len(sr.ToolCalls)
+	}
+	sr.ScoreTrace = scoreLocalization(sample.Feature, response, toolCalls)
+	sr.Score = sr.ScoreTrace.FinalScore
+	return sr
+}
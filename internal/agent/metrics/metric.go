@@ -1,4 +1,4 @@
-// Package metrics provides 5 MECE agent evaluation metrics for C7.
+// Package metrics provides the agent evaluation metrics for C7.
 //
 // The metrics are:
 //   - M1: Task Execution Consistency - measures reproducibility across runs
@@ -6,6 +6,8 @@
 //   - M3: Cross-File Navigation - measures dependency tracing ability
 //   - M4: Identifier Interpretability - measures name-based purpose inference
 //   - M5: Documentation Accuracy Detection - measures comment/code mismatch detection
+//   - M6: Change Success - measures whether agent edits still build and pass tests
+//   - M7: Feature Localization - measures finding the code that implements a described feature
 package metrics
 
 import (
@@ -32,6 +34,15 @@ type Metric interface {
 	Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult
 }
 
+// CallsPerSample returns how many agent calls m makes per sample: M1 repeats
+// its task on every sample, the other metrics call the agent once.
+func CallsPerSample(m Metric) int {
+	if c, ok := m.(interface{ callsPerSample() int }); ok {
+		return c.callsPerSample()
+	}
+	return 1
+}
+
 // Sample represents a code sample selected for metric evaluation.
 type Sample struct {
 	FilePath       string  // Absolute path to file
@@ -40,6 +51,14 @@ type Sample struct {
 	EndLine        int     // Optional: line range end
 	SelectionScore float64 // Score used for deterministic selection
 	Description    string  // Why this sample was selected
+
+	Navigation *NavigationTruth // M3: dependencies from the import graph; nil scores heuristically
+	Seeded     *SeededDoc       // M5: copy with injected doc mismatches; nil scores heuristically
+	Functions  []string         // M1: functions declared in the file (from the AST); nil scores heuristically
+	Change     *ChangeTask      // M6: the synthetic change the agent is asked to make
+	Feature    *FeatureTruth    // M7: the documented symbol the agent is asked to find
+	Language   types.Language   // Custom tasks: the file's language, for prompt placeholders
+	Lines      int              // Custom tasks: the file's line count, for prompt placeholders
 }
 
 // IndicatorMatch records a single heuristic indicator check and its point contribution.
@@ -54,6 +73,12 @@ type ScoreTrace struct {
 	BaseScore  int              // Starting score before adjustments (typically 5)
 	Indicators []IndicatorMatch // Each indicator checked and its result
 	FinalScore int              // Score after clamping to 1-10
+
+	GroundTruth   []GroundTruthMatch   // Per item kind when scored against ground truth
+	RunAgreement  *float64             // M1: mean Jaccard similarity with the other runs' answers
+	Change        *ChangeOutcome       // M6: build/test result and diff of the agent's change
+	Localization  *LocalizationOutcome // M7: where the expected symbol ranked among the agent's answers
+	Justification string               // Judge's reasoning when an LLM judge scored the response
 }
 
 // SampleResult holds the outcome of evaluating one sample.
@@ -65,6 +90,11 @@ type SampleResult struct {
 	ScoreTrace ScoreTrace    // Heuristic scoring breakdown
 	Duration   time.Duration // How long this sample took
 	Error      string        // Empty if successful
+
+	// ToolCalls is the agent's tool-call trace; nil if the backend does not
+	// trace tool calls.
+	ToolCalls []ToolCall
+	Retries   int // executor calls retried after rate limits or transient errors
 }
 
 // MetricResult holds the complete outcome of a metric evaluation.
@@ -76,9 +106,18 @@ type MetricResult struct {
 	TokensUsed int
 	Duration   time.Duration
 	Error      string // Empty if successful
+	Skipped    string // Why the metric did not run, e.g. the LLM budget; empty if it ran
+
+	// RepeatScores holds the score of each repeat with --c7-repeats,
+	// types.FailedRepeat for a repeat that failed; nil for a single run.
+	// Score is the rounded mean of the others.
+	RepeatScores []int
 }
 
-// Executor abstracts Claude CLI execution for testability.
+// Executor abstracts Claude CLI execution for testability. The executor
+// applies timeout to each attempt at the agent itself, so time spent waiting
+// for a concurrency slot, a rate limit or a retry backoff does not count
+// against it.
 type Executor interface {
 	ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (response string, err error)
 }
@@ -109,3 +148,13 @@ func newM4Identifiers() Metric {
 func newM5Documentation() Metric {
 	return newM5DocumentationMetric()
 }
+
+// newM6ChangeSuccess creates the Change Success metric.
+func newM6ChangeSuccess() Metric {
+	return newM6ChangeSuccessMetric()
+}
+
+// newM7Localization creates the Feature Localization metric.
+func newM7Localization() Metric {
+	return newM7LocalizationMetric()
+}
@@ -2,10 +2,14 @@ package metrics
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
@@ -33,10 +37,10 @@ func (m *mockExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, tools
 	return m.response, nil
 }
 
-func TestAllMetricsReturns5(t *testing.T) {
+func TestAllMetricsReturns7(t *testing.T) {
 	metrics := AllMetrics()
-	if len(metrics) != 5 {
-		t.Errorf("AllMetrics() returned %d metrics, want 5", len(metrics))
+	if len(metrics) != 7 {
+		t.Errorf("AllMetrics() returned %d metrics, want 7", len(metrics))
 	}
 }
 
@@ -72,6 +76,8 @@ func TestGetMetricByID(t *testing.T) {
 		{"cross_file_navigation", "Cross-File Navigation"},
 		{"identifier_interpretability", "Identifier Interpretability"},
 		{"documentation_accuracy_detection", "Documentation Accuracy Detection"},
+		{"change_success", "Change Success"},
+		{"feature_localization", "Feature Localization"},
 	}
 
 	for _, tc := range tests {
@@ -103,6 +109,76 @@ func TestM1Consistency_SelectSamples(t *testing.T) {
 	}
 }
 
+// sequenceExecutor returns its responses in turn, repeating the last one.
+type sequenceExecutor struct {
+	responses []string
+	calls     int
+}
+
+func (s *sequenceExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	r := s.responses[min(s.calls, len(s.responses)-1)]
+	s.calls++
+	return r, nil
+}
+
+func TestM1_Execute_GroundTruth(t *testing.T) {
+	sample := Sample{FilePath: "store.go", Functions: []string{"Open", "Store.Save", "helper"}}
+
+	tests := []struct {
+		name      string
+		responses []string
+		wantScore int
+	}{
+		{"correct and consistent", []string{`["Open", "Save", "helper"]`}, 10},
+		{"consistently wrong", []string{`["foo", "bar"]`}, 1},
+		{"correct but unstable", []string{`["Open", "Store.Save", "helper"]`, `["Open", "Save", "helper"]`, `["Open"]`}, 5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newM1ConsistencyMetric()
+			result := m.Execute(context.Background(), t.TempDir(), []Sample{sample}, &sequenceExecutor{responses: tc.responses})
+			if result.Score != tc.wantScore {
+				t.Errorf("Score = %d, want %d", result.Score, tc.wantScore)
+			}
+			if len(result.Samples) != m1Runs {
+				t.Fatalf("got %d runs, want %d", len(result.Samples), m1Runs)
+			}
+			for i, sr := range result.Samples {
+				if len(sr.ScoreTrace.GroundTruth) != 1 || sr.ScoreTrace.RunAgreement == nil {
+					t.Errorf("run %d: trace lacks ground truth or agreement: %+v", i, sr.ScoreTrace)
+				}
+			}
+		})
+	}
+
+	// The wrong answer lists its invented functions.
+	trace := m1ScoreTruth(`["foo", "Open"]`, sample.Functions)
+	g := trace.GroundTruth[0]
+	if len(g.Matched) != 1 || len(g.Missed) != 2 || len(g.Hallucinated) != 1 || g.Hallucinated[0] != "foo" {
+		t.Errorf("match = %+v", g)
+	}
+}
+
+func TestNewMetrics_Options(t *testing.T) {
+	defaults := NewMetrics(Options{})
+	for i, m := range AllMetrics() {
+		if defaults[i].ID() != m.ID() || defaults[i].Timeout() != m.Timeout() || defaults[i].SampleCount() != m.SampleCount() {
+			t.Errorf("NewMetrics(Options{})[%d] differs from AllMetrics()", i)
+		}
+	}
+
+	m := NewMetrics(Options{ConsistencyRuns: 5, ConsistencySamples: 2})[0]
+	if m.SampleCount() != 2 || m.Timeout() != 10*m1RunTimeout {
+		t.Errorf("M1 SampleCount() = %d, Timeout() = %v; want 2 and %v", m.SampleCount(), m.Timeout(), 10*m1RunTimeout)
+	}
+	samples := []Sample{{FilePath: "a.go"}, {FilePath: "b.go"}}
+	result := m.Execute(context.Background(), t.TempDir(), samples, &mockExecutor{response: `["f"]`})
+	if len(result.Samples) != 10 {
+		t.Errorf("got %d runs, want 5 runs x 2 samples", len(result.Samples))
+	}
+}
+
 func TestM2Comprehension_SelectSamples(t *testing.T) {
 	m := newM2Comprehension()
 
@@ -433,6 +509,126 @@ func TestM3_ScoreNavigationResponse(t *testing.T) {
 	}
 }
 
+func TestM3_ScoreNavigationTruth(t *testing.T) {
+	m := newM3Navigation().(*m3Navigation)
+	truth := &NavigationTruth{
+		Files:        []string{"internal/store/store.go"},
+		Dependencies: []string{"internal/store/store.go", "internal/store/cache.go"},
+		Symbols:      []string{"Store.Save"},
+		Identifiers:  map[string]bool{"main": true, "Save": true, "Load": true, "Store": true},
+	}
+
+	tests := []struct {
+		name         string
+		response     string
+		wantScore    int
+		hallucinated []string
+	}{
+		{
+			name:      "exact trace",
+			response:  "Data Flow Trace: main() -> `Store.Save()` in internal/store/store.go",
+			wantScore: 10,
+		},
+		{
+			name:         "invented dependency and symbol",
+			response:     "Data Flow Trace: main() -> `Store.Save()` in store.go -> Flush() in internal/disk/flush.go",
+			wantScore:    7,
+			hallucinated: []string{"internal/disk/flush.go", "Flush"},
+		},
+		{
+			name:      "verbose answer without the trace",
+			response:  strings.Repeat("The module imports packages that provide data flow -> purpose. ", 50),
+			wantScore: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			score, trace := m.scoreNavigationSample(Sample{FilePath: "cmd/main.go", Navigation: truth}, tc.response)
+			if score != tc.wantScore {
+				t.Errorf("score = %d, want %d (trace %+v)", score, tc.wantScore, trace)
+			}
+			if len(trace.GroundTruth) != 2 {
+				t.Fatalf("GroundTruth has %d kinds, want 2", len(trace.GroundTruth))
+			}
+			var hallucinated []string
+			for _, g := range trace.GroundTruth {
+				hallucinated = append(hallucinated, g.Hallucinated...)
+			}
+			if strings.Join(hallucinated, ",") != strings.Join(tc.hallucinated, ",") {
+				t.Errorf("hallucinated = %v, want %v", hallucinated, tc.hallucinated)
+			}
+		})
+	}
+
+	// Without ground truth the heuristics apply.
+	_, trace := m.scoreNavigationSample(Sample{FilePath: "cmd/main.go"}, "main() -> Save()")
+	if trace.GroundTruth != nil || trace.BaseScore != m3BaseScore {
+		t.Errorf("heuristic fallback: got trace %+v", trace)
+	}
+}
+
+type fakeGroundTruth map[string]*NavigationTruth
+
+func (f fakeGroundTruth) Navigation(relPath string) (*NavigationTruth, bool) {
+	nt, ok := f[relPath]
+	return nt, ok
+}
+
+func (f fakeGroundTruth) Functions(relPath string) ([]string, bool) {
+	if _, ok := f[relPath]; !ok {
+		return nil, false
+	}
+	return []string{"Open", "Store.Save"}, true
+}
+
+func (f fakeGroundTruth) DocMismatches(relPath string) (*SeededDoc, bool) {
+	if _, ok := f[relPath]; !ok {
+		return nil, false
+	}
+	return &SeededDoc{Path: SeedDir + "/" + relPath}, true
+}
+
+func (f fakeGroundTruth) DocumentedSymbols(relPath string) ([]DocumentedSymbol, bool) {
+	if _, ok := f[relPath]; !ok {
+		return nil, false
+	}
+	return []DocumentedSymbol{
+		{Name: "Open", Kind: "func", Doc: "Open opens a store."},
+		{Name: "Store", Kind: "type", Doc: "Store keeps records on disk, one file per record."},
+	}, true
+}
+
+func TestAttachGroundTruth(t *testing.T) {
+	nt := &NavigationTruth{Files: []string{"b.go"}}
+	gt := fakeGroundTruth{"a.go": nt}
+
+	samples := []Sample{{FilePath: "a.go"}, {FilePath: "c.go"}}
+	AttachGroundTruth(newM3Navigation(), samples, gt)
+	if samples[0].Navigation != nt || samples[1].Navigation != nil {
+		t.Errorf("M3 samples: got %+v", samples)
+	}
+
+	samples = []Sample{{FilePath: "a.go"}}
+	AttachGroundTruth(newM1Consistency(), samples, gt)
+	if samples[0].Functions == nil || samples[0].Navigation != nil {
+		t.Errorf("M1 samples: got %+v", samples)
+	}
+
+	samples = []Sample{{FilePath: "a.go"}}
+	AttachGroundTruth(newM2Comprehension(), samples, gt)
+	if samples[0].Navigation != nil || samples[0].Functions != nil {
+		t.Error("M2 samples should not get ground truth")
+	}
+	AttachGroundTruth(newM3Navigation(), samples, nil)
+
+	samples = []Sample{{FilePath: "a.go"}, {FilePath: "c.go"}}
+	AttachGroundTruth(newM7Localization(), samples, gt)
+	if f := samples[0].Feature; f == nil || f.Symbol != "Store" || f.File != "a.go" || samples[1].Feature != nil {
+		t.Errorf("M7 samples: got %+v", samples)
+	}
+}
+
 // Test scoring heuristics for M4 (Identifiers)
 func TestM4_ScoreIdentifierResponse(t *testing.T) {
 	m := newM4Identifiers().(*m4Identifiers)
@@ -513,6 +709,134 @@ func TestM5_ScoreDocumentationResponse(t *testing.T) {
 	}
 }
 
+const seededGoSource = `package store
+
+// Open opens the database at path.
+// The timeout defaults to 30 seconds.
+func Open(path string, timeout int) (*DB, error) {
+	return nil, nil
+}
+
+// Exists returns true if the key is present.
+func (d *DB) Exists(key string) bool {
+	return d.get(key) != nil
+}
+`
+
+func TestSeedMismatches(t *testing.T) {
+	doc, ok := SeedMismatches("store/db.go", []byte(seededGoSource))
+	if !ok {
+		t.Fatal("SeedMismatches() found nothing to seed")
+	}
+	if doc.Path != ".ars-seeded/store/db.go" {
+		t.Errorf("Path = %q", doc.Path)
+	}
+
+	want := []Mismatch{
+		{Kind: MismatchParamName, Line: 3, Symbol: "Open", Original: "path", Injected: "input"},
+		{Kind: MismatchReturnCondition, Line: 9, Symbol: "Exists", Original: "true", Injected: "false"},
+		{Kind: MismatchDefaultValue, Line: 4, Symbol: "Open", Original: "30", Injected: "60"},
+	}
+	if len(doc.Mismatches) != len(want) {
+		t.Fatalf("got %d mismatches, want %d: %v", len(doc.Mismatches), len(want), doc.Mismatches)
+	}
+	for i, w := range want {
+		if doc.Mismatches[i] != w {
+			t.Errorf("mismatch %d = %+v, want %+v", i, doc.Mismatches[i], w)
+		}
+	}
+	content := string(doc.Content)
+	if !strings.Contains(content, "database at input.\n// The timeout defaults to 60 seconds") ||
+		!strings.Contains(content, "Exists returns false if") {
+		t.Errorf("seeded content missing injections:\n%s", content)
+	}
+	if strings.Contains(content, "func Open(input") {
+		t.Error("code must not be altered, only documentation")
+	}
+
+	py := "def load(filename, retries=3):\n    \"\"\"Load filename.\n\n    Retries defaults to 3.\n    \"\"\"\n    pass\n"
+	doc, ok = SeedMismatches("app/io.py", []byte(py))
+	if !ok || len(doc.Mismatches) != 2 {
+		t.Fatalf("Python: got %+v, %v", doc, ok)
+	}
+
+	if _, ok := SeedMismatches("notes.txt", []byte(seededGoSource)); ok {
+		t.Error("unsupported language should not be seeded")
+	}
+	if _, ok := SeedMismatches("plain.go", []byte("package p\n\nfunc f() {}\n")); ok {
+		t.Error("undocumented file should not be seeded")
+	}
+}
+
+func TestM5_ScoreSeededResponse(t *testing.T) {
+	m := newM5Documentation().(*m5Documentation)
+	doc, _ := SeedMismatches("store/db.go", []byte(seededGoSource))
+
+	tests := []struct {
+		name      string
+		response  string
+		wantScore int
+		missed    int
+		falsePos  int
+	}{
+		{
+			name: "all detected",
+			response: `## Potential Mismatches
+- Location: line 3
+- Comment says: database at input
+- Location: line 3, default timeout
+- Location: Exists
+- Comment says: returns false, but Exists returns true when the key is present`,
+			wantScore: 10,
+		},
+		{
+			name: "one detected plus false positive",
+			response: `- Location: line 9
+- Issue: Exists returns false in the comment
+- Location: line 40
+- Issue: unrelated comment`,
+			wantScore: 2,
+			missed:    2,
+			falsePos:  1,
+		},
+		{
+			name:      "nothing reported",
+			response:  "## Summary\nAll documentation appears accurate.",
+			wantScore: 1,
+			missed:    3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			score, trace := m.scoreDocumentationSample(Sample{FilePath: "store/db.go", Seeded: doc}, tc.response)
+			if score != tc.wantScore {
+				t.Errorf("score = %d, want %d (trace %+v)", score, tc.wantScore, trace)
+			}
+			g := trace.GroundTruth[0]
+			if len(g.Missed) != tc.missed || len(g.Hallucinated) != tc.falsePos {
+				t.Errorf("missed=%v hallucinated=%v, want %d and %d", g.Missed, g.Hallucinated, tc.missed, tc.falsePos)
+			}
+		})
+	}
+}
+
+func TestWriteSeededDocs(t *testing.T) {
+	workDir := t.TempDir()
+	doc, _ := SeedMismatches("store/db.go", []byte(seededGoSource))
+	samples := []Sample{{FilePath: "store/db.go", Seeded: doc}, {FilePath: "other.go"}}
+
+	cleanup := WriteSeededDocs(workDir, samples)
+	got, err := os.ReadFile(filepath.Join(workDir, SeedDir, "store", "db.go"))
+	if err != nil || string(got) != string(doc.Content) {
+		t.Fatalf("seeded copy not written: %v", err)
+	}
+	cleanup()
+	if _, err := os.Stat(filepath.Join(workDir, SeedDir)); !os.IsNotExist(err) {
+		t.Errorf("cleanup left %s behind", SeedDir)
+	}
+}
+
 // TestScoreTrace_SumsCorrectly verifies that for each metric (M2-M5), the
 // ScoreTrace is the source of truth: BaseScore + sum(Deltas) == FinalScore
 // (before clamping). Also checks that non-empty responses produce indicators.
@@ -893,3 +1217,626 @@ func TestM4_Score_Fixtures(t *testing.T) {
 		})
 	}
 }
+
+func TestM6ChangeSuccess_SelectSamples(t *testing.T) {
+	m := newM6ChangeSuccessMetric()
+	target := &types.AnalysisTarget{
+		Language: types.LangGo,
+		Files: []types.SourceFile{
+			{RelPath: "parse.go", Class: types.ClassSource, Content: []byte("package p\n\nfunc Parse(s string) int { return helper(s) }\n\nfunc helper(s string) int { return len(s) }\n\nfunc Format(n int) string { return \"\" }\n")},
+			{RelPath: "a.go", Class: types.ClassSource, Content: []byte("package p\n\nfunc A() { Parse(\"a\"); Format(1) }\n")},
+			{RelPath: "parse_test.go", Class: types.ClassTest, Content: []byte("package p\n\nfunc TestParse(t *testing.T) { Parse(\"\") }\n")},
+		},
+	}
+
+	samples := m.SelectSamples([]*types.AnalysisTarget{target})
+	if len(samples) != 2 {
+		t.Fatalf("got %d samples, want 2", len(samples))
+	}
+
+	rename := samples[0]
+	if rename.FunctionName != "Parse" || rename.Change.Kind != changeRename || rename.Change.NewName != "ParseRenamed" {
+		t.Errorf("rename sample = %s %+v, want Parse renamed to ParseRenamed", rename.FunctionName, rename.Change)
+	}
+	if want := []string{"parse.go", "a.go", "parse_test.go"}; strings.Join(rename.Change.Files, ",") != strings.Join(want, ",") {
+		t.Errorf("rename files = %v, want %v", rename.Change.Files, want)
+	}
+
+	add := samples[1]
+	if add.FunctionName != "Format" || add.Change.Kind != changeAddParameter {
+		t.Errorf("add_parameter sample = %s %+v, want Format", add.FunctionName, add.Change)
+	}
+	if !strings.Contains(changePrompt(add), "Update every caller to pass false") {
+		t.Errorf("Go add_parameter prompt should ask to update callers:\n%s", changePrompt(add))
+	}
+}
+
+// editExecutor applies edits to files in the workspace instead of running an agent.
+type editExecutor struct {
+	edits map[string]string // relative path -> new content
+}
+
+func (e *editExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	for rel, content := range e.edits {
+		if err := os.WriteFile(filepath.Join(workDir, rel), []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+	return "Changed the files.", nil
+}
+
+func TestM6ChangeSuccess_Execute(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	files := map[string]string{
+		"parse.go": "package p\n\nfunc Parse(s string) int { return len(s) }\n",
+		"a.go":     "package p\n\nfunc A() int { return Parse(\"a\") }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	sample := Sample{
+		FilePath:     "parse.go",
+		FunctionName: "Parse",
+		Change: &ChangeTask{
+			Kind: changeRename, NewName: "ParseRenamed", Language: types.LangGo,
+			Files: []string{"parse.go", "a.go"}, MaxLines: 8,
+		},
+	}
+	m := newM6ChangeSuccessMetric()
+	// Stand-in for go build/test: fails while a.go calls an undeclared Parse.
+	m.run = func(ctx context.Context, dir, command string) (string, error) {
+		decl, _ := os.ReadFile(filepath.Join(dir, "parse.go"))
+		caller, _ := os.ReadFile(filepath.Join(dir, "a.go"))
+		if strings.Contains(string(caller), "Parse(") && !strings.Contains(string(decl), "func Parse(") {
+			return "a.go:3: undefined: Parse", fmt.Errorf("exit status 1")
+		}
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name      string
+		edits     map[string]string
+		wantScore int
+		wantPass  bool
+	}{
+		{
+			name: "complete rename",
+			edits: map[string]string{
+				"parse.go": "package p\n\nfunc ParseRenamed(s string) int { return len(s) }\n",
+				"a.go":     "package p\n\nfunc A() int { return ParseRenamed(\"a\") }\n",
+			},
+			wantScore: 10,
+			wantPass:  true,
+		},
+		{
+			name: "unrelated file touched",
+			edits: map[string]string{
+				"parse.go": "package p\n\nfunc ParseRenamed(s string) int { return len(s) }\n",
+				"a.go":     "package p\n\nfunc A() int { return ParseRenamed(\"a\") }\n",
+				"notes.md": "Renamed Parse.\n",
+			},
+			wantScore: 9,
+			wantPass:  true,
+		},
+		{
+			name: "caller not updated",
+			edits: map[string]string{
+				"parse.go": "package p\n\nfunc ParseRenamed(s string) int { return len(s) }\n",
+			},
+			wantScore: 1,
+		},
+		{
+			name:      "no change",
+			edits:     nil,
+			wantScore: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := m.Execute(context.Background(), repo, []Sample{sample}, &editExecutor{edits: tc.edits})
+			if len(result.Samples) != 1 || result.Samples[0].Error != "" {
+				t.Fatalf("unexpected result: %+v", result)
+			}
+			sr := result.Samples[0]
+			if sr.Score != tc.wantScore {
+				t.Errorf("score = %d, want %d; trace %+v", sr.Score, tc.wantScore, sr.ScoreTrace.Indicators)
+			}
+			if sr.ScoreTrace.Change == nil || sr.ScoreTrace.Change.Passed != tc.wantPass {
+				t.Errorf("change outcome = %+v, want passed=%v", sr.ScoreTrace.Change, tc.wantPass)
+			}
+		})
+	}
+
+	// The project itself is never modified.
+	if data, _ := os.ReadFile(filepath.Join(repo, "a.go")); string(data) != files["a.go"] {
+		t.Errorf("project file changed: %q", data)
+	}
+}
+
+func writeTaskSuite(t *testing.T, dir, name, content string) {
+	t.Helper()
+	tasksDir := filepath.Join(dir, TasksDir)
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tasksDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTasks(t *testing.T) {
+	dir := t.TempDir()
+	if tasks, err := LoadTasks(dir); err != nil || tasks != nil {
+		t.Fatalf("LoadTasks() without tasks dir = %v, %v; want nil, nil", tasks, err)
+	}
+
+	writeTaskSuite(t, dir, "db.yml", `tasks:
+  - id: add_migration
+    name: Add Migration
+    prompt: "Describe how to add a column to the table defined in {file}."
+    tools: [Read, Grep]
+    timeout: 2m
+    weight: 0.2
+    samples:
+      glob: "db/**/*.py"
+      language: python
+      min_loc: 10
+      count: 2
+    rubric:
+      groups:
+        - name: migration
+          members: [alembic, migration]
+      patterns:
+        - regex: 'op\.add_column'
+          delta: 2
+`)
+	tasks, err := LoadTasks(dir)
+	if err != nil {
+		t.Fatalf("LoadTasks() error: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("LoadTasks() returned %d tasks, want 1", len(tasks))
+	}
+	task := tasks[0]
+	if task.ID != "add_migration" || task.Timeout != 2*time.Minute || task.EffectiveWeight() != 0.2 {
+		t.Errorf("task = %+v", task)
+	}
+	if task.Samples.Count != 2 || task.Samples.Language != "python" {
+		t.Errorf("samples = %+v", task.Samples)
+	}
+
+	invalid := map[string]string{
+		"builtin id":    "tasks:\n  - id: change_success\n    prompt: x\n    rubric: {negative: [x]}\n",
+		"no prompt":     "tasks:\n  - id: a\n    rubric: {negative: [x]}\n",
+		"no rubric":     "tasks:\n  - id: a\n    prompt: x\n",
+		"bad regex":     "tasks:\n  - id: a\n    prompt: x\n    rubric: {patterns: [{regex: '('}]}\n",
+		"bad glob":      "tasks:\n  - id: a\n    prompt: x\n    samples: {glob: 'db/[a-z'}\n    rubric: {negative: [x]}\n",
+		"bad language":  "tasks:\n  - id: a\n    prompt: x\n    samples: {language: cobol}\n    rubric: {negative: [x]}\n",
+		"unknown field": "tasks:\n  - id: a\n    prompt: x\n    rubirc: {negative: [x]}\n",
+		"write tool":    "tasks:\n  - id: a\n    prompt: x\n    tools: [Read, Write]\n    rubric: {negative: [x]}\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTaskSuite(t, dir, "bad.yml", content)
+			if _, err := LoadTasks(dir); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestCustomTask_SelectSamples(t *testing.T) {
+	var files []types.SourceFile
+	for i, lines := range []int{5, 50, 80, 120, 400} {
+		files = append(files, types.SourceFile{
+			RelPath: fmt.Sprintf("db/models/m%d.py", i), Language: types.LangPython, Lines: lines, Class: types.ClassSource,
+		})
+	}
+	files = append(files,
+		types.SourceFile{RelPath: "api/views.py", Language: types.LangPython, Lines: 100, Class: types.ClassSource},
+		types.SourceFile{RelPath: "db/models/test_m.py", Language: types.LangPython, Lines: 100, Class: types.ClassTest},
+	)
+	targets := []*types.AnalysisTarget{{Language: types.LangPython, Files: files}}
+
+	m := newCustomTask(TaskSpec{
+		ID:      "add_migration",
+		Prompt:  "Explain {file} ({language}, {lines} lines).",
+		Samples: SampleSelector{Glob: "db/**/*.py", MinLOC: 10, MaxLOC: 200, Count: 2},
+	}, nil)
+	samples := m.SelectSamples(targets)
+	if len(samples) != 2 {
+		t.Fatalf("SelectSamples() returned %d samples, want 2", len(samples))
+	}
+	if samples[0].FilePath != "db/models/m1.py" || samples[1].FilePath != "db/models/m2.py" {
+		t.Errorf("samples = %s, %s", samples[0].FilePath, samples[1].FilePath)
+	}
+	if got := m.buildPrompt(samples[0]); got != "Explain db/models/m1.py (python, 50 lines)." {
+		t.Errorf("buildPrompt() = %q", got)
+	}
+
+	api := newCustomTask(TaskSpec{ID: "api", Prompt: "Explain {file}.", Samples: SampleSelector{Glob: "{api,web}/*.py"}}, nil)
+	if samples := api.SelectSamples(targets); len(samples) != 1 || samples[0].FilePath != "api/views.py" {
+		t.Errorf("SelectSamples() with alternatives = %+v, want api/views.py", samples)
+	}
+
+	repo := newCustomTask(TaskSpec{ID: "overview", Prompt: "Summarize the architecture."}, nil)
+	if samples := repo.SelectSamples(targets); len(samples) != 1 || samples[0].FilePath != "" {
+		t.Errorf("repository-level task samples = %+v, want one without a file", samples)
+	}
+}
+
+// fakeJudge returns a fixed score and records what it was given.
+type fakeJudge struct {
+	score   int
+	err     error
+	rubric  string
+	content string
+}
+
+func (f *fakeJudge) Score(ctx context.Context, rubric, content string) (int, string, error) {
+	f.rubric, f.content = rubric, content
+	return f.score, "fixed score", f.err
+}
+
+func TestCustomTask_Score(t *testing.T) {
+	spec := TaskSpec{
+		ID:     "add_migration",
+		Prompt: "Add a column to {file}.",
+		Rubric: Rubric{
+			Groups:        []RubricGroup{{Name: "migration", Members: []string{"Alembic", "migration"}}},
+			Negative:      []string{"not sure"},
+			Patterns:      []RubricPattern{{Name: "add_column", Regex: `op\.add_column`, Delta: 2}},
+			ExpectedFiles: []string{"{file}", "db/migrations/*.py"},
+		},
+	}
+	if err := spec.validate(); err != nil {
+		t.Fatal(err)
+	}
+	m := newCustomTask(spec, nil)
+	sample := Sample{FilePath: "db/models/user.py"}
+
+	response := "Create an Alembic migration in db/migrations/0042_email.py calling op.add_column, " +
+		"then update db/models/user.py."
+	score, trace := m.scoreHeuristic(sample, response)
+	// base 5 + group 1 + pattern 2 + expected files 3
+	if score != 10 || trace.FinalScore != 10 {
+		t.Errorf("score = %d, want 10 (trace %+v)", score, trace)
+	}
+	if len(trace.GroundTruth) != 1 || len(trace.GroundTruth[0].Matched) != 2 {
+		t.Errorf("ground truth = %+v, want both expected files matched", trace.GroundTruth)
+	}
+
+	score, _ = m.scoreHeuristic(sample, "I'm not sure; edit user.py.")
+	// base 5 - 1 negative + round(0.5 * 3) for user.py
+	if score != 6 {
+		t.Errorf("weak response score = %d, want 6", score)
+	}
+
+	spec.Rubric.Judge = "Full marks if the migration is reversible."
+	judge := &fakeJudge{score: 3}
+	m = newCustomTask(spec, judge)
+	result := m.Execute(context.Background(), t.TempDir(), []Sample{sample}, &mockExecutor{response: response})
+	trace = result.Samples[0].ScoreTrace
+	if result.Score != 3 || trace.Justification != "fixed score" || judge.rubric != spec.Rubric.Judge {
+		t.Errorf("judged score = %d, justification %q, rubric %q", result.Score, trace.Justification, judge.rubric)
+	}
+	if !strings.Contains(judge.content, "Add a column to db/models/user.py.") || !strings.Contains(judge.content, response) {
+		t.Errorf("judge content = %q, want the prompt and response", judge.content)
+	}
+
+	judge.err = fmt.Errorf("judge offline")
+	result = m.Execute(context.Background(), t.TempDir(), []Sample{sample}, &mockExecutor{response: response})
+	trace = result.Samples[0].ScoreTrace
+	if result.Score != 10 || !strings.Contains(trace.Justification, "judge offline") {
+		t.Errorf("fallback score = %d, justification %q; want heuristic 10", result.Score, trace.Justification)
+	}
+}
+
+func TestNewMetrics_Tasks(t *testing.T) {
+	ms := NewMetrics(Options{Tasks: []TaskSpec{{ID: "overview", Prompt: "Summarize."}}})
+	if len(ms) != 8 || ms[7].ID() != "overview" || ms[7].Name() != "overview" {
+		t.Fatalf("NewMetrics() = %d metrics, want the task last", len(ms))
+	}
+	if IsBuiltin("overview") || !IsBuiltin("change_success") {
+		t.Error("IsBuiltin() misclassifies metrics")
+	}
+
+	judgeOnly := TaskSpec{ID: "review", Prompt: "Review {file}.", Rubric: Rubric{Judge: "Is it thorough?"}}
+	result := newCustomTask(judgeOnly, nil).Execute(context.Background(), t.TempDir(), []Sample{{FilePath: "a.go"}}, &mockExecutor{})
+	if result.Error == "" {
+		t.Error("expected error for a judge-only rubric without a judge")
+	}
+}
+
+// lengthJudge scores longer responses higher, standing in for a judge that
+// prefers thorough answers; invert reverses its preference. It fails content
+// without a code sample or task, which a calibration judge must see.
+type lengthJudge struct {
+	invert bool
+}
+
+func (j lengthJudge) Score(ctx context.Context, rubric, content string) (int, string, error) {
+	if !strings.HasPrefix(content, "Code sample (") || !strings.Contains(content, "Task given to the agent:\n") {
+		return 0, "", fmt.Errorf("content lacks the code sample or task: %.80q", content)
+	}
+	_, response, _ := strings.Cut(content, "Agent response:\n")
+	score := min(1+len(response)/400, 10)
+	if j.invert {
+		score = 11 - score
+	}
+	return score, fmt.Sprintf("%d bytes", len(response)), nil
+}
+
+func TestCalibrate_Fixtures(t *testing.T) {
+	cases, err := DefaultCalibrationSet()
+	if err != nil {
+		t.Fatalf("DefaultCalibrationSet() error: %v", err)
+	}
+	if len(cases) != 8 {
+		t.Fatalf("DefaultCalibrationSet() returned %d cases, want 8", len(cases))
+	}
+	metricsSeen := map[string]bool{}
+	for _, c := range cases {
+		metricsSeen[c.MetricID] = true
+		if c.Code == "" || c.Prompt == "" || !strings.Contains(c.Prompt, c.Sample.FilePath) {
+			t.Errorf("case %s: sample %q, prompt %.40q, %d bytes of code; want the sample's file and prompt", c.Name, c.Sample.FilePath, c.Prompt, len(c.Code))
+		}
+	}
+	if len(metricsSeen) != 4 {
+		t.Errorf("calibration set covers %v, want M2-M5", metricsSeen)
+	}
+	if id := cases[4].Sample; id.FunctionName != "low_stock" || id.StartLine != 24 {
+		t.Errorf("M4 case sample = %+v, want the identifier and its line", id)
+	}
+
+	report, err := Calibrate(context.Background(), lengthJudge{}, cases)
+	if err != nil {
+		t.Fatalf("Calibrate() error: %v", err)
+	}
+	// Each metric's strong fixture outscores its weak one heuristically
+	if report.Pairs != 4 {
+		t.Fatalf("Pairs = %d, want 4\n%s", report.Pairs, report)
+	}
+	if report.Agreement() != 1 {
+		t.Errorf("Agreement() = %.2f, want 1\n%s", report.Agreement(), report)
+	}
+
+	report, err = Calibrate(context.Background(), lengthJudge{invert: true}, cases)
+	if err != nil {
+		t.Fatalf("Calibrate() error: %v", err)
+	}
+	if report.Agreement() != 0 {
+		t.Errorf("inverted judge Agreement() = %.2f, want 0\n%s", report.Agreement(), report)
+	}
+
+	if _, err := Calibrate(context.Background(), lengthJudge{}, []CalibrationCase{{MetricID: "change_success"}}); err == nil {
+		t.Error("expected error for a metric without a judge rubric")
+	}
+
+	bare := fstest.MapFS{"m2_comprehension/bare.txt": {Data: []byte("It adds.")}}
+	if _, err := LoadCalibrationSet(bare); err == nil {
+		t.Error("expected error for a response without its sample sidecar")
+	}
+	if _, err := LoadCalibrationSet(fstest.MapFS{}); err == nil {
+		t.Error("expected error for a set without responses")
+	}
+}
+
+func TestJudgeScoring(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte("package calc\n\nfunc Add(a, b int) int { return a + b }\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	judge := &fakeJudge{score: 9}
+	ms := NewMetrics(Options{Judge: judge, JudgeScoring: true})
+	m2 := ms[1]
+
+	result := m2.Execute(context.Background(), dir, []Sample{{FilePath: "calc.go"}}, &mockExecutor{response: "Add returns the sum."})
+	trace := result.Samples[0].ScoreTrace
+	if result.Score != 9 || trace.Justification != "fixed score" {
+		t.Errorf("judged M2 score = %d, justification %q; want 9", result.Score, trace.Justification)
+	}
+	if judge.rubric != m2JudgeRubric || !strings.Contains(judge.content, "func Add(a, b int) int") {
+		t.Errorf("judge got rubric %.40q and content %q, want M2's rubric and the code sample", judge.rubric, judge.content)
+	}
+
+	// Ground truth takes precedence over the judge
+	judge.content = ""
+	truth := &NavigationTruth{Files: []string{"calc.go"}}
+	result = ms[2].Execute(context.Background(), dir, []Sample{{FilePath: "main.go", Navigation: truth}}, &mockExecutor{response: "main.go calls calc.go"})
+	if judge.content != "" || result.Samples[0].ScoreTrace.Justification != "" {
+		t.Error("judge scored a sample with ground truth")
+	}
+
+	// Without judge scoring, M2 keeps its heuristics
+	judge.content = ""
+	NewMetrics(Options{Judge: judge})[1].Execute(context.Background(), dir, []Sample{{FilePath: "calc.go"}}, &mockExecutor{response: "x"})
+	if judge.content != "" {
+		t.Error("judge used without JudgeScoring")
+	}
+}
+
+func TestM7Localization_SelectSamples(t *testing.T) {
+	m := newM7LocalizationMetric()
+	target := &types.AnalysisTarget{
+		Language: types.LangGo,
+		Files: []types.SourceFile{
+			{RelPath: "store.go", Class: types.ClassSource, Content: []byte("package p\n\n// Open opens a store.\nfunc Open() {}\n\n// Store keeps records.\ntype Store struct{}\n\nfunc helper() {}\n")},
+			{RelPath: "util.go", Class: types.ClassSource, Content: []byte("package p\n\n// Clamp limits n to a range.\nfunc Clamp(n int) int { return n }\n")},
+			{RelPath: "plain.go", Class: types.ClassSource, Content: []byte("package p\n\nfunc Plain() {}\n")},
+			{RelPath: "store_test.go", Class: types.ClassTest, Content: []byte("package p\n\n// TestOpen tests Open.\nfunc TestOpen(t *testing.T) {}\n")},
+		},
+	}
+
+	samples := m.SelectSamples([]*types.AnalysisTarget{target})
+	if len(samples) != 2 || samples[0].FilePath != "store.go" || samples[1].FilePath != "util.go" {
+		t.Fatalf("samples = %+v, want store.go, util.go", samples)
+	}
+}
+
+func TestDescribeFeature(t *testing.T) {
+	tests := []struct {
+		name, doc, want string
+	}{
+		{"Load", "Load reads the configuration file and applies defaults.\n\nIt never fails.", "Reads the configuration file and applies defaults."},
+		{"Store", "A Store keeps records on disk, one file each. Use Open to create a Store.", "Keeps records on disk, one file each."},
+		{"Parse", "Parse reads input. Parse never panics on bad input.", ""},
+		{"Sum", "Sum adds numbers. Call Sum() with any list of ints.", ""},
+		{"save", "Persist a record to disk and return its id.", "Persist a record to disk and return its id."},
+		{"Short", "Short does it.", ""},
+		{"Hello", "Hello returns a greeting. It is friendly.", "Returns a greeting. It is friendly."},
+	}
+	for _, tc := range tests {
+		if got := describeFeature(tc.name, tc.doc); got != tc.want {
+			t.Errorf("describeFeature(%q, %q) = %q, want %q", tc.name, tc.doc, got, tc.want)
+		}
+	}
+}
+
+func TestM7_ScoreLocalization(t *testing.T) {
+	truth := &FeatureTruth{Symbol: "Open", Kind: "func", File: "internal/store/store.go"}
+	tests := []struct {
+		name      string
+		response  string
+		toolCalls int
+		want      int
+		rank      int
+	}{
+		{"top1", "1. internal/store/store.go: Open\n2. internal/store/db.go: Connect", 4, 10, 1},
+		{"top3 qualified", "1. `store/db.go`: Connect\n2. `store/store.go:12`: store.Open()", 4, 7, 2},
+		{"file only", "1. internal/store/store.go: Close", -1, 4, 0},
+		{"miss", "1. cmd/main.go: main", 3, 1, 0},
+		{"top1 with many tool calls", "1. internal/store/store.go - Open", 25, 8, 1},
+		{"fourth answer ignored", "1. a.go: A\n2. b.go: B\n3. c.go: C\n4. internal/store/store.go: Open", 2, 1, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trace := scoreLocalization(truth, tc.response, tc.toolCalls)
+			if trace.FinalScore != tc.want || trace.Localization.Rank != tc.rank {
+				t.Errorf("score, rank = %d, %d, want %d, %d (answers %v)", trace.FinalScore, trace.Localization.Rank, tc.want, tc.rank, trace.Localization.Answers)
+			}
+		})
+	}
+}
+
+// toolCallExecutor answers with a fixed response after the given number of
+// Grep calls.
+type toolCallExecutor struct {
+	response  string
+	toolCalls int
+}
+
+func (e *toolCallExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	calls := make([]ToolCall, e.toolCalls)
+	for i := range calls {
+		calls[i] = ToolCall{Tool: "Grep", Input: `{"pattern":"Open"}`}
+	}
+	RecordToolCalls(ctx, calls)
+	return e.response, nil
+}
+
+func TestM7Localization_Execute(t *testing.T) {
+	m := newM7LocalizationMetric()
+	samples := []Sample{
+		{FilePath: "store.go", Feature: &FeatureTruth{Symbol: "Open", Kind: "func", File: "store.go", Description: "Opens a store in the given directory."}},
+		{FilePath: "util.go"},
+	}
+	exec := &toolCallExecutor{response: "1. store.go: Open", toolCalls: 12}
+
+	result := m.Execute(context.Background(), t.TempDir(), samples, exec)
+	if result.Score != 9 {
+		t.Errorf("Score = %d, want 9 (top-1 with 12 tool calls)", result.Score)
+	}
+	if loc := result.Samples[0].ScoreTrace.Localization; loc == nil || loc.ToolCalls != 12 {
+		t.Errorf("Localization = %+v, want 12 tool calls", loc)
+	}
+	if len(result.Samples[0].ToolCalls) != 12 || result.Samples[0].Duration <= 0 {
+		t.Errorf("sample trace = %d calls in %s, want 12 and a duration", len(result.Samples[0].ToolCalls), result.Samples[0].Duration)
+	}
+	if !strings.Contains(result.Samples[0].Prompt, "Opens a store in the given directory.") || strings.Contains(result.Samples[0].Prompt, "Open\n") {
+		t.Errorf("prompt should describe the feature without naming it:\n%s", result.Samples[0].Prompt)
+	}
+	if result.Samples[1].Error == "" {
+		t.Error("sample without a documented symbol should fail")
+	}
+}
+
+func TestSummarizeToolCalls(t *testing.T) {
+	calls := []ToolCall{
+		{Tool: "Glob", Input: `{"pattern":"**/*.go"}`},
+		{Tool: "Read", Input: `{"file_path":"a.go"}`, Path: "a.go"},
+		{Tool: "Grep", Input: `{"pattern":"Open","path":"a.go"}`, Path: "a.go"},
+		{Tool: "Read", Input: `{"file_path":"b.go"}`, Path: "b.go"},
+		{Tool: "Read", Input: `{"file_path":"a.go"}`, Path: "a.go"},
+		{Tool: "Read", Input: `{"file_path":"a.go","offset":200}`, Path: "a.go"},
+	}
+	want := ToolCallStats{Calls: 6, FilesRead: 2, RedundantReads: 1}
+	if got := SummarizeToolCalls(calls); got != want {
+		t.Errorf("SummarizeToolCalls = %+v, want %+v", got, want)
+	}
+}
+
+func TestExecuteStandardMetric_RecordsToolCalls(t *testing.T) {
+	cfg := executeConfig{
+		metricID:      "test",
+		timeout:       time.Minute,
+		buildPrompt:   func(Sample) string { return "prompt" },
+		scoreResponse: func(string) (int, ScoreTrace) { return 5, ScoreTrace{FinalScore: 5} },
+	}
+	samples := []Sample{{FilePath: "a.go"}}
+
+	result := executeStandardMetric(context.Background(), t.TempDir(), samples, &toolCallExecutor{response: "ok", toolCalls: 0}, cfg)
+	if calls := result.Samples[0].ToolCalls; calls == nil || len(calls) != 0 {
+		t.Errorf("traced backend without tool use: ToolCalls = %#v, want empty, non-nil", calls)
+	}
+
+	result = executeStandardMetric(context.Background(), t.TempDir(), samples, &mockExecutor{response: "ok"}, cfg)
+	if calls := result.Samples[0].ToolCalls; calls != nil {
+		t.Errorf("untraced backend: ToolCalls = %#v, want nil", calls)
+	}
+}
+
+// retryingExecutor reports two retries before answering.
+type retryingExecutor struct{}
+
+func (retryingExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	RecordRetry(ctx)
+	RecordRetry(ctx)
+	return "ok", nil
+}
+
+func TestExecute_RecordsRetries(t *testing.T) {
+	samples := []Sample{{FilePath: "a.go"}}
+	total := 0
+	ctx := WithRetryRecorder(context.Background(), func() { total++ })
+
+	result := newM2ComprehensionMetric().Execute(ctx, t.TempDir(), samples, retryingExecutor{})
+	if result.Samples[0].Retries != 2 {
+		t.Errorf("M2 sample Retries = %d, want 2", result.Samples[0].Retries)
+	}
+	result = newM1ConsistencyMetric().Execute(ctx, t.TempDir(), samples, retryingExecutor{})
+	for i, s := range result.Samples {
+		if s.Retries != 2 {
+			t.Errorf("M1 run %d Retries = %d, want 2 (counted per run)", i, s.Retries)
+		}
+	}
+	if total != 2+2*len(result.Samples) {
+		t.Errorf("parent recorder saw %d retries, want %d", total, 2+2*len(result.Samples))
+	}
+}
@@ -7,13 +7,65 @@ var allMetrics = []Metric{
 	newM3Navigation(),
 	newM4Identifiers(),
 	newM5Documentation(),
+	newM6ChangeSuccess(),
+	newM7Localization(),
 }
 
-// AllMetrics returns all 5 MECE metrics.
+// AllMetrics returns all C7 metrics.
 func AllMetrics() []Metric {
 	return allMetrics
 }
 
+// Options tunes metric instances created with NewMetrics. Zero values keep
+// the defaults.
+type Options struct {
+	ConsistencyRuns    int // M1: runs per sample (default 3)
+	ConsistencySamples int // M1: number of sampled files (default 1)
+
+	Tasks        []TaskSpec // user-defined tasks (see LoadTasks), run after the built-in metrics
+	Judge        Judge      // scores task rubrics with judge text; nil uses their heuristic criteria
+	JudgeScoring bool       // M2-M5: score responses with Judge instead of keyword heuristics
+
+	Repeats int // runs of every metric's samples, for confidence intervals (--c7-repeats; default 1)
+}
+
+// NewMetrics returns fresh instances of all C7 metrics configured by opts,
+// in the same order as AllMetrics, followed by the user-defined tasks.
+func NewMetrics(opts Options) []Metric {
+	runs, samples := m1Runs, m1SampleCount
+	if opts.ConsistencyRuns > 0 {
+		runs = opts.ConsistencyRuns
+	}
+	if opts.ConsistencySamples > 0 {
+		samples = opts.ConsistencySamples
+	}
+	m2, m3, m4, m5 := newM2ComprehensionMetric(), newM3NavigationMetric(), newM4IdentifiersMetric(), newM5DocumentationMetric()
+	if opts.JudgeScoring {
+		// Ground truth (M3's import graph, M5's seeded mismatches) still
+		// takes precedence over the judge where available.
+		m2.judge, m3.judge, m4.judge, m5.judge = opts.Judge, opts.Judge, opts.Judge, opts.Judge
+	}
+	ms := []Metric{
+		newM1ConsistencyMetricWith(runs, samples),
+		m2,
+		m3,
+		m4,
+		m5,
+		newM6ChangeSuccess(),
+		newM7Localization(),
+	}
+	for _, spec := range opts.Tasks {
+		ms = append(ms, newCustomTask(spec, opts.Judge))
+	}
+	return ms
+}
+
+// IsBuiltin reports whether id is one of the built-in metrics M1-M7 rather
+// than a user-defined task.
+func IsBuiltin(id string) bool {
+	return getMetric(id) != nil
+}
+
 // getMetric returns a metric by ID, or nil if not found.
 func getMetric(id string) Metric {
 	for _, m := range allMetrics {
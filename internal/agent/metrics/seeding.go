@@ -0,0 +1,425 @@
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SeedDir is the worktree directory M5 writes seeded copies to. Go, Python and
+// JavaScript tooling skip dot-directories, so builds in the worktree are unaffected.
+const SeedDir = ".ars-seeded"
+
+// Mismatch kinds injected into seeded copies.
+const (
+	MismatchParamName       = "param_name"       // doc comment names a parameter that does not exist
+	MismatchReturnCondition = "return_condition" // documented true/false result flipped
+	MismatchDefaultValue    = "default_value"    // documented default changed
+)
+
+const (
+	seedLineTolerance = 2     // Reported line numbers within this distance match an injection
+	seedMinParamLen   = 3     // Shorter parameter names are too ambiguous to rename in prose
+	seedFilePerm      = 0o644 // Permissions of seeded copies
+	seedDirPerm       = 0o755 // Permissions of seed directories
+)
+
+// Mismatch is one doc/code mismatch injected into a seeded copy.
+type Mismatch struct {
+	Kind     string // MismatchParamName, MismatchReturnCondition or MismatchDefaultValue
+	Line     int    // 1-based line of the altered comment
+	Symbol   string // function whose documentation was altered
+	Original string // documented text before injection
+	Injected string // documented text after injection
+}
+
+// String describes the mismatch for score traces.
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s line %d (%s): %q -> %q", m.Kind, m.Line, m.Symbol, m.Original, m.Injected)
+}
+
+// SeededDoc is a copy of a sample file with known mismatches injected into
+// its documentation. It is the M5 ground truth.
+type SeededDoc struct {
+	Path       string // slash-separated path of the copy, relative to the worktree
+	Content    []byte
+	Mismatches []Mismatch
+}
+
+var (
+	goFuncPattern = regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?(\w+)\s*(?:\[[^\]]*\])?\(([^)]*)\)`)
+	pyFuncPattern = regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)\s*\(([^)]*)\)`)
+	tsFuncPattern = regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(\w+)\s*(?:<[^>]*>)?\(([^)]*)\)`)
+
+	leadingIdentPattern = regexp.MustCompile(`^[A-Za-z_]\w*`)
+	boolResultPattern   = regexp.MustCompile(`(?i)\b(returns?|is|evaluates to)\s+(true|false)\b`)
+	defaultValuePattern = regexp.MustCompile(`(?i)\bdefaults?\s+(?:is\s+|to\s+|of\s+|value\s+is\s+)?(\d+)\b`)
+	lineNumberPattern   = regexp.MustCompile(`\b\d+\b`)
+	reportStartPattern  = regexp.MustCompile(`(?i)location:`)
+)
+
+// renameCandidates are plausible but wrong parameter names for param_name mismatches.
+var renameCandidates = []string{"input", "value", "source", "target", "options"}
+
+// docFunc is a function signature and the lines of its documentation.
+type docFunc struct {
+	name     string
+	params   []string
+	docLines []int // 0-based indexes of documentation lines
+}
+
+// SeedMismatches copies the file content and injects up to one mismatch of
+// each kind into the documentation of its functions. It returns false if the
+// language is unsupported or the documentation offers nothing to alter.
+func SeedMismatches(relPath string, content []byte) (*SeededDoc, bool) {
+	lines := strings.Split(string(content), "\n")
+	funcs := findDocFuncs(path.Ext(relPath), lines)
+	if len(funcs) == 0 {
+		return nil, false
+	}
+
+	var mismatches []Mismatch
+	altered := make(map[int]bool)
+	for _, inject := range []func([]string, docFunc, map[int]bool) (Mismatch, bool){
+		injectParamName, injectReturnCondition, injectDefaultValue,
+	} {
+		for _, fn := range funcs {
+			if m, ok := inject(lines, fn, altered); ok {
+				altered[m.Line-1] = true
+				mismatches = append(mismatches, m)
+				break
+			}
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil, false
+	}
+	return &SeededDoc{
+		Path:       path.Join(SeedDir, filepath.ToSlash(relPath)),
+		Content:    []byte(strings.Join(lines, "\n")),
+		Mismatches: mismatches,
+	}, true
+}
+
+// WriteSeededDocs writes the seeded copies of samples into workDir. Samples
+// whose copy cannot be written lose their seeding and are scored heuristically.
+// The returned function removes all written copies.
+func WriteSeededDocs(workDir string, samples []Sample) func() {
+	var written []string
+	for i := range samples {
+		doc := samples[i].Seeded
+		if doc == nil {
+			continue
+		}
+		dest := filepath.Join(workDir, filepath.FromSlash(doc.Path))
+		if err := os.MkdirAll(filepath.Dir(dest), seedDirPerm); err != nil {
+			samples[i].Seeded = nil
+			continue
+		}
+		if err := os.WriteFile(dest, doc.Content, seedFilePerm); err != nil {
+			samples[i].Seeded = nil
+			continue
+		}
+		written = append(written, dest)
+	}
+	return func() {
+		for _, f := range written {
+			os.Remove(f)
+		}
+		if len(written) > 0 {
+			os.RemoveAll(filepath.Join(workDir, SeedDir))
+		}
+	}
+}
+
+// findDocFuncs finds single-line function signatures with documentation:
+// comment lines directly above (Go, TypeScript) or a docstring below (Python).
+func findDocFuncs(ext string, lines []string) []docFunc {
+	var pattern *regexp.Regexp
+	switch ext {
+	case ".go":
+		pattern = goFuncPattern
+	case ".py":
+		pattern = pyFuncPattern
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+		pattern = tsFuncPattern
+	default:
+		return nil
+	}
+
+	var funcs []docFunc
+	for i, line := range lines {
+		m := pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		fn := docFunc{name: m[1], params: parseParamNames(ext, m[2])}
+		if ext == ".py" {
+			fn.docLines = docstringLines(lines, i)
+		} else {
+			fn.docLines = commentLinesAbove(lines, i)
+		}
+		if len(fn.docLines) > 0 {
+			funcs = append(funcs, fn)
+		}
+	}
+	return funcs
+}
+
+// parseParamNames extracts parameter names from a parameter list. Go groups
+// names before a shared type ("a, b int"), so single-word Go entries only
+// count as names when a later entry carries a type.
+func parseParamNames(ext, list string) []string {
+	var names []string
+	pending := 0
+	for _, piece := range strings.Split(list, ",") {
+		piece = strings.TrimLeft(strings.TrimSpace(piece), "*.")
+		name := leadingIdentPattern.FindString(piece)
+		if name == "" || name == "self" || name == "cls" || name == "this" {
+			continue
+		}
+		if ext == ".go" {
+			if len(strings.Fields(piece)) < 2 {
+				names = append(names, name)
+				pending++
+				continue
+			}
+			pending = 0
+		}
+		names = append(names, name)
+	}
+	// Trailing single-word Go entries are unnamed parameter types.
+	return names[:len(names)-pending]
+}
+
+// commentLinesAbove returns the contiguous comment lines directly above line i.
+func commentLinesAbove(lines []string, i int) []int {
+	var doc []int
+	for j := i - 1; j >= 0; j-- {
+		t := strings.TrimSpace(lines[j])
+		if !strings.HasPrefix(t, "//") && !strings.HasPrefix(t, "*") && !strings.HasPrefix(t, "/*") {
+			break
+		}
+		doc = append([]int{j}, doc...)
+	}
+	return doc
+}
+
+// docstringLines returns the lines of the docstring following the def on line i.
+func docstringLines(lines []string, i int) []int {
+	if i+1 >= len(lines) {
+		return nil
+	}
+	first := strings.TrimSpace(lines[i+1])
+	var quote string
+	switch {
+	case strings.HasPrefix(first, `"""`):
+		quote = `"""`
+	case strings.HasPrefix(first, `'''`):
+		quote = `'''`
+	default:
+		return nil
+	}
+	doc := []int{i + 1}
+	if strings.Count(first, quote) >= 2 {
+		return doc
+	}
+	for j := i + 2; j < len(lines); j++ {
+		doc = append(doc, j)
+		if strings.Contains(lines[j], quote) {
+			break
+		}
+	}
+	return doc
+}
+
+// injectParamName renames a documented parameter in the comment to a name
+// the function does not have.
+func injectParamName(lines []string, fn docFunc, altered map[int]bool) (Mismatch, bool) {
+	docText := ""
+	for _, li := range fn.docLines {
+		docText += lines[li] + "\n"
+	}
+	for _, param := range fn.params {
+		if len(param) < seedMinParamLen || param == fn.name {
+			continue
+		}
+		word := regexp.MustCompile(`\b` + regexp.QuoteMeta(param) + `\b`)
+		replacement := ""
+		for _, c := range renameCandidates {
+			if c != param && !containsString(fn.params, c) && !regexp.MustCompile(`\b`+c+`\b`).MatchString(docText) {
+				replacement = c
+				break
+			}
+		}
+		if replacement == "" {
+			continue
+		}
+		for _, li := range fn.docLines {
+			if altered[li] {
+				continue
+			}
+			loc := word.FindStringIndex(lines[li])
+			if loc == nil {
+				continue
+			}
+			lines[li] = lines[li][:loc[0]] + replacement + lines[li][loc[1]:]
+			return Mismatch{Kind: MismatchParamName, Line: li + 1, Symbol: fn.name, Original: param, Injected: replacement}, true
+		}
+	}
+	return Mismatch{}, false
+}
+
+// injectReturnCondition flips a documented boolean result ("returns true if").
+func injectReturnCondition(lines []string, fn docFunc, altered map[int]bool) (Mismatch, bool) {
+	for _, li := range fn.docLines {
+		if altered[li] {
+			continue
+		}
+		m := boolResultPattern.FindStringSubmatchIndex(lines[li])
+		if m == nil {
+			continue
+		}
+		original := lines[li][m[4]:m[5]]
+		injected := flipBool(original)
+		lines[li] = lines[li][:m[4]] + injected + lines[li][m[5]:]
+		return Mismatch{Kind: MismatchReturnCondition, Line: li + 1, Symbol: fn.name, Original: original, Injected: injected}, true
+	}
+	return Mismatch{}, false
+}
+
+// injectDefaultValue changes a documented numeric default ("defaults to 30").
+func injectDefaultValue(lines []string, fn docFunc, altered map[int]bool) (Mismatch, bool) {
+	for _, li := range fn.docLines {
+		if altered[li] {
+			continue
+		}
+		m := defaultValuePattern.FindStringSubmatchIndex(lines[li])
+		if m == nil {
+			continue
+		}
+		original := lines[li][m[2]:m[3]]
+		n, err := strconv.Atoi(original)
+		if err != nil {
+			continue
+		}
+		injected := strconv.Itoa(n * 2)
+		if n == 0 {
+			injected = "1"
+		}
+		lines[li] = lines[li][:m[2]] + injected + lines[li][m[3]:]
+		return Mismatch{Kind: MismatchDefaultValue, Line: li + 1, Symbol: fn.name, Original: original, Injected: injected}, true
+	}
+	return Mismatch{}, false
+}
+
+// flipBool swaps true and false, keeping the capitalization style.
+func flipBool(s string) string {
+	var flipped string
+	if strings.EqualFold(s, "true") {
+		flipped = "false"
+	} else {
+		flipped = "true"
+	}
+	switch {
+	case s == strings.ToUpper(s):
+		return strings.ToUpper(flipped)
+	case s[0] >= 'A' && s[0] <= 'Z':
+		return strings.ToUpper(flipped[:1]) + flipped[1:]
+	}
+	return flipped
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSeededMismatches compares the mismatches a response reports with the
+// injected ones. Each "Location:" block is one report and detects at most one
+// injection: one whose function and injected text it mentions, or else the
+// nearest one whose line its location names (within seedLineTolerance).
+// Reports detecting no injection are false positives.
+func matchSeededMismatches(response string, doc *SeededDoc) GroundTruthMatch {
+	match := GroundTruthMatch{Kind: "mismatches"}
+
+	detected := make([]bool, len(doc.Mismatches))
+	for _, report := range splitReports(response) {
+		i := detectedMismatch(report, doc.Mismatches, detected)
+		if i < 0 {
+			match.Hallucinated = append(match.Hallucinated, firstLine(report))
+			continue
+		}
+		detected[i] = true
+	}
+	for i, mm := range doc.Mismatches {
+		if detected[i] {
+			match.Matched = append(match.Matched, mm.String())
+		} else {
+			match.Missed = append(match.Missed, mm.String())
+		}
+	}
+	return match
+}
+
+// detectedMismatch returns the index of the mismatch a report detects,
+// preferring ones not yet detected, or -1.
+func detectedMismatch(report string, mismatches []Mismatch, detected []bool) int {
+	best, bestDist := -1, seedLineTolerance+1
+	for i, mm := range mismatches {
+		injected := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(mm.Injected) + `\b`)
+		if strings.Contains(report, mm.Symbol) && injected.MatchString(report) {
+			dist := -1 // mentioning the injected text beats any line match
+			if detected[i] {
+				dist = 0
+			}
+			if dist < bestDist {
+				best, bestDist = i, dist
+			}
+			continue
+		}
+		for _, n := range lineNumberPattern.FindAllString(firstLine(report), -1) {
+			line, _ := strconv.Atoi(n)
+			dist := line - mm.Line
+			if dist < 0 {
+				dist = -dist
+			}
+			if detected[i] {
+				dist += seedLineTolerance // re-detections only win when nothing else matches
+			}
+			if dist < bestDist {
+				best, bestDist = i, dist
+			}
+		}
+	}
+	return best
+}
+
+// splitReports splits a response into its "Location:" blocks.
+func splitReports(response string) []string {
+	starts := reportStartPattern.FindAllStringIndex(response, -1)
+	reports := make([]string, len(starts))
+	for i, s := range starts {
+		end := len(response)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		reports[i] = strings.TrimSpace(response[s[1]:end])
+	}
+	return reports
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return strings.TrimSpace(s)
+}
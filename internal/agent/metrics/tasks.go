@@ -0,0 +1,418 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// TasksDir is where user-defined C7 tasks live, relative to the project root.
+const TasksDir = ".ars/tasks"
+
+// Custom task defaults.
+const (
+	taskDefaultTools       = "Read,Glob,Grep"
+	taskDefaultTimeout     = 180 * time.Second // Total timeout across all samples
+	taskDefaultSampleCount = 1
+	taskDefaultBaseScore   = 5
+	taskDefaultWeight      = 0.10 // Same as MetricWeightLow in the scoring config
+	taskExpectedFilePoints = 3    // Points for naming all expected files, scaled by recall
+)
+
+// taskTools are the tools a custom task may allow. Tasks run in the scanned
+// repository itself, so only read-only tools are permitted; M6 is the only
+// metric that writes, and it does so in a private worktree.
+var taskTools = map[string]bool{"Read": true, "Glob": true, "Grep": true}
+
+// taskIDPattern restricts task IDs to the form of the built-in metric IDs,
+// since they become metric names in the scoring config and reports.
+var taskIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// TaskSpec is a user-defined C7 evaluation task from .ars/tasks/*.yml.
+//
+// The prompt is a template: {file}, {language} and {lines} are replaced with
+// the sampled file's path, language and line count. A prompt without {file}
+// is a repository-level task and runs once.
+type TaskSpec struct {
+	ID          string         `yaml:"id"`          // metric ID, e.g. "add_migration"
+	Name        string         `yaml:"name"`        // display name; defaults to the ID
+	Description string         `yaml:"description"` // what the task measures
+	Prompt      string         `yaml:"prompt"`      // prompt template
+	Tools       []string       `yaml:"tools"`       // allowed tools, a subset of Read, Glob and Grep; default all three
+	Timeout     time.Duration  `yaml:"timeout"`     // total across samples, e.g. "3m"; default 180s
+	Weight      float64        `yaml:"weight"`      // weight within C7; default 0.10
+	Samples     SampleSelector `yaml:"samples"`
+	Rubric      Rubric         `yaml:"rubric"`
+}
+
+// SampleSelector chooses the source files a task runs on. Files are taken in
+// path order, spread evenly across the matches.
+type SampleSelector struct {
+	Glob     string `yaml:"glob"`     // slash-separated path glob with * and **, e.g. "internal/**/*.go"
+	Language string `yaml:"language"` // "go", "python" or "typescript"
+	MinLOC   int    `yaml:"min_loc"`
+	MaxLOC   int    `yaml:"max_loc"` // 0 means no limit
+	Count    int    `yaml:"count"`   // number of samples; default 1
+}
+
+// Rubric scores a task's responses. Heuristic criteria start from BaseScore
+// and add their deltas like the built-in metrics; when Judge is set and a
+// judge is available, the judge's score replaces them.
+type Rubric struct {
+	BaseScore     int             `yaml:"base_score"`     // default 5
+	Groups        []RubricGroup   `yaml:"groups"`         // +1 per group with any member in the response
+	Negative      []string        `yaml:"negative"`       // -1 per phrase in the response
+	Patterns      []RubricPattern `yaml:"patterns"`       // regular expressions with their deltas
+	ExpectedFiles []string        `yaml:"expected_files"` // paths or globs the response should name; placeholders allowed
+	Judge         string          `yaml:"judge"`          // rubric text for an LLM judge
+}
+
+// RubricGroup is a named group of phrases; matching is case-insensitive.
+type RubricGroup struct {
+	Name    string   `yaml:"name"`
+	Members []string `yaml:"members"`
+}
+
+// RubricPattern adds Delta when Regex matches the response.
+type RubricPattern struct {
+	Name  string `yaml:"name"` // defaults to the regex
+	Regex string `yaml:"regex"`
+	Delta int    `yaml:"delta"` // default +1
+
+	re *regexp.Regexp
+}
+
+// taskFile is the layout of a task suite file.
+type taskFile struct {
+	Tasks []TaskSpec `yaml:"tasks"`
+}
+
+// LoadTasks reads the task suites in dir's .ars/tasks directory (*.yml and
+// *.yaml, in name order). It returns nil if the directory does not exist.
+func LoadTasks(dir string) ([]TaskSpec, error) {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, TasksDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var tasks []TaskSpec
+	seen := make(map[string]string)
+	for _, m := range allMetrics {
+		seen[m.ID()] = "built-in metrics"
+	}
+	for _, file := range files {
+		suite, err := loadTaskFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range suite {
+			if prev, ok := seen[t.ID]; ok {
+				return nil, fmt.Errorf("%s: task %q already defined in %s", file, t.ID, prev)
+			}
+			seen[t.ID] = file
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// loadTaskFile decodes and validates one task suite.
+func loadTaskFile(file string) ([]TaskSpec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read task suite %s: %w", file, err)
+	}
+	var suite taskFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&suite); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse task suite %s: %w", file, err)
+	}
+	for i := range suite.Tasks {
+		if err := suite.Tasks[i].validate(); err != nil {
+			return nil, fmt.Errorf("invalid task suite %s: %w", file, err)
+		}
+	}
+	return suite.Tasks, nil
+}
+
+// validate checks a task and compiles its patterns.
+func (t *TaskSpec) validate() error {
+	if !taskIDPattern.MatchString(t.ID) {
+		return fmt.Errorf("task id %q must be lowercase letters, digits and underscores", t.ID)
+	}
+	if strings.TrimSpace(t.Prompt) == "" {
+		return fmt.Errorf("task %s: prompt is required", t.ID)
+	}
+	for _, tool := range t.Tools {
+		if !taskTools[tool] {
+			return fmt.Errorf("task %s: tool %q not allowed (expected Read, Glob or Grep)", t.ID, tool)
+		}
+	}
+	if t.Timeout < 0 {
+		return fmt.Errorf("task %s: timeout must be >= 0, got %s", t.ID, t.Timeout)
+	}
+	if t.Weight < 0 {
+		return fmt.Errorf("task %s: weight must be >= 0, got %f", t.ID, t.Weight)
+	}
+	s := t.Samples
+	if s.Language != "" && types.Language(s.Language).BuildCommands() == nil {
+		return fmt.Errorf("task %s: unsupported language %q (expected go, python or typescript)", t.ID, s.Language)
+	}
+	if s.MinLOC < 0 || s.MaxLOC < 0 || s.Count < 0 {
+		return fmt.Errorf("task %s: sample min_loc, max_loc and count must be >= 0", t.ID)
+	}
+	if s.MaxLOC > 0 && s.MaxLOC < s.MinLOC {
+		return fmt.Errorf("task %s: sample max_loc %d is below min_loc %d", t.ID, s.MaxLOC, s.MinLOC)
+	}
+	if s.Glob != "" && !discovery.ValidGlob(s.Glob) {
+		return fmt.Errorf("task %s: invalid sample glob %q", t.ID, s.Glob)
+	}
+
+	r := &t.Rubric
+	if len(r.Groups) == 0 && len(r.Negative) == 0 && len(r.Patterns) == 0 && len(r.ExpectedFiles) == 0 && r.Judge == "" {
+		return fmt.Errorf("task %s: rubric needs groups, negative, patterns, expected_files or judge", t.ID)
+	}
+	for i := range r.Patterns {
+		p := &r.Patterns[i]
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return fmt.Errorf("task %s: invalid rubric pattern: %w", t.ID, err)
+		}
+		p.re = re
+	}
+	return nil
+}
+
+// customTask runs a TaskSpec as a C7 metric.
+type customTask struct {
+	spec  TaskSpec
+	judge Judge // scores judge rubrics; nil falls back to the heuristic criteria
+}
+
+// newCustomTask creates a metric for a validated task spec.
+func newCustomTask(spec TaskSpec, judge Judge) *customTask {
+	return &customTask{spec: spec, judge: judge}
+}
+
+// ID returns the task ID.
+func (m *customTask) ID() string { return m.spec.ID }
+
+// Name returns the task's display name.
+func (m *customTask) Name() string {
+	if m.spec.Name != "" {
+		return m.spec.Name
+	}
+	return m.spec.ID
+}
+
+// Description returns what the task measures.
+func (m *customTask) Description() string { return m.spec.Description }
+
+// Timeout returns the per-metric timeout duration.
+func (m *customTask) Timeout() time.Duration {
+	if m.spec.Timeout > 0 {
+		return m.spec.Timeout
+	}
+	return taskDefaultTimeout
+}
+
+// SampleCount returns the number of samples to evaluate.
+func (m *customTask) SampleCount() int {
+	if !m.perFile() {
+		return 1
+	}
+	if m.spec.Samples.Count > 0 {
+		return m.spec.Samples.Count
+	}
+	return taskDefaultSampleCount
+}
+
+// EffectiveWeight returns the task's weight within C7: the configured weight
+// or the default.
+func (t TaskSpec) EffectiveWeight() float64 {
+	if t.Weight > 0 {
+		return t.Weight
+	}
+	return taskDefaultWeight
+}
+
+// perFile reports whether the prompt refers to a sampled file.
+func (m *customTask) perFile() bool {
+	return strings.Contains(m.spec.Prompt, "{file}")
+}
+
+// SelectSamples picks source files matching the selector, spread evenly over
+// the matches in path order. Repository-level tasks get a single sample.
+func (m *customTask) SelectSamples(targets []*types.AnalysisTarget) []Sample {
+	if !m.perFile() {
+		return []Sample{{Description: "Repository-level task"}}
+	}
+
+	sel := m.spec.Samples
+	var matches []types.SourceFile
+	for _, target := range targets {
+		for _, file := range target.Files {
+			if file.Class != types.ClassSource {
+				continue
+			}
+			if sel.Language != "" && file.Language != types.Language(sel.Language) {
+				continue
+			}
+			if file.Lines < sel.MinLOC || (sel.MaxLOC > 0 && file.Lines > sel.MaxLOC) {
+				continue
+			}
+			if sel.Glob != "" && !discovery.MatchGlob(sel.Glob, file.RelPath) {
+				continue
+			}
+			matches = append(matches, file)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].RelPath < matches[j].RelPath })
+
+	count := min(m.SampleCount(), len(matches))
+	samples := make([]Sample, 0, count)
+	for i := 0; i < count; i++ {
+		file := matches[i*len(matches)/count]
+		samples = append(samples, Sample{
+			FilePath:    file.RelPath,
+			Description: fmt.Sprintf("%s (%s, %d LOC)", file.RelPath, file.Language, file.Lines),
+			Language:    file.Language,
+			Lines:       file.Lines,
+		})
+	}
+	return samples
+}
+
+// Execute runs the task prompt for each sample and scores it with the rubric.
+func (m *customTask) Execute(ctx context.Context, workDir string, samples []Sample, executor Executor) MetricResult {
+	r := m.spec.Rubric
+	if r.Judge != "" && m.judge == nil && len(r.Groups)+len(r.Negative)+len(r.Patterns)+len(r.ExpectedFiles) == 0 {
+		return MetricResult{MetricID: m.ID(), MetricName: m.Name(), Error: "rubric requires an LLM judge, but none is available"}
+	}
+
+	tools := taskDefaultTools
+	if len(m.spec.Tools) > 0 {
+		tools = strings.Join(m.spec.Tools, ",")
+	}
+	return executeStandardMetric(ctx, workDir, samples, executor, executeConfig{
+		metricID:    m.ID(),
+		metricName:  m.Name(),
+		timeout:     m.Timeout(),
+		tools:       tools,
+		buildPrompt: m.buildPrompt,
+		scoreSample: m.scoreHeuristic,
+		judge:       m.judge,
+		rubric:      r.Judge,
+	})
+}
+
+// buildPrompt fills the prompt template for a sample.
+func (m *customTask) buildPrompt(sample Sample) string {
+	return expandTaskPlaceholders(m.spec.Prompt, sample)
+}
+
+// expandTaskPlaceholders replaces {file}, {language} and {lines} in s.
+func expandTaskPlaceholders(s string, sample Sample) string {
+	return strings.NewReplacer(
+		"{file}", sample.FilePath,
+		"{language}", string(sample.Language),
+		"{lines}", strconv.Itoa(sample.Lines),
+	).Replace(s)
+}
+
+// scoreHeuristic applies the rubric's groups, negative phrases, patterns and
+// expected files. It is the fallback when the rubric's judge is unavailable.
+func (m *customTask) scoreHeuristic(sample Sample, response string) (int, ScoreTrace) {
+	r := m.spec.Rubric
+	trace := ScoreTrace{BaseScore: r.BaseScore}
+	if trace.BaseScore == 0 {
+		trace.BaseScore = taskDefaultBaseScore
+	}
+	responseLower := strings.ToLower(response)
+
+	groups := make([]indicatorGroup, 0, len(r.Groups))
+	for _, g := range r.Groups {
+		members := make([]string, 0, len(g.Members))
+		for _, member := range g.Members {
+			members = append(members, strings.ToLower(member))
+		}
+		groups = append(groups, indicatorGroup{name: g.Name, members: members})
+	}
+	trace.Indicators = append(trace.Indicators, matchGroups(responseLower, groups)...)
+
+	negative := make([]string, 0, len(r.Negative))
+	for _, phrase := range r.Negative {
+		negative = append(negative, strings.ToLower(phrase))
+	}
+	trace.Indicators = append(trace.Indicators, matchNegativeIndicators(responseLower, negative)...)
+
+	for _, p := range r.Patterns {
+		name, delta := p.Name, p.Delta
+		if name == "" {
+			name = p.Regex
+		}
+		if delta == 0 {
+			delta = 1
+		}
+		matched := p.re != nil && p.re.MatchString(response)
+		if !matched {
+			delta = 0
+		}
+		trace.Indicators = append(trace.Indicators, IndicatorMatch{Name: "pattern:" + name, Matched: matched, Delta: delta})
+	}
+
+	if len(r.ExpectedFiles) > 0 {
+		match := matchExpectedFiles(response, r.ExpectedFiles, sample)
+		trace.GroundTruth = append(trace.GroundTruth, match)
+		delta := int(math.Round(match.Recall() * taskExpectedFilePoints))
+		trace.Indicators = append(trace.Indicators, IndicatorMatch{
+			Name:    fmt.Sprintf("expected_files:%d/%d", len(match.Matched), len(r.ExpectedFiles)),
+			Matched: delta > 0,
+			Delta:   delta,
+		})
+	}
+	return computeScore(&trace), trace
+}
+
+// matchExpectedFiles checks which expected paths or globs the response names.
+func matchExpectedFiles(response string, expected []string, sample Sample) GroundTruthMatch {
+	match := GroundTruthMatch{Kind: "files"}
+	mentioned := mentionedFiles(response)
+	for _, want := range expected {
+		want = expandTaskPlaceholders(want, sample)
+		found := false
+		for _, m := range mentioned {
+			if pathMatches(m, want) || discovery.MatchGlob(want, m) {
+				found = true
+				break
+			}
+		}
+		if found {
+			match.Matched = append(match.Matched, want)
+		} else {
+			match.Missed = append(match.Missed, want)
+		}
+	}
+	return match
+}
@@ -0,0 +1,265 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+// OpenAI-compatible backend defaults.
+const (
+	defaultOpenAIBaseURL  = "https://api.openai.com/v1"
+	defaultOpenAIKeyEnv   = "OPENAI_API_KEY"
+	defaultOpenAIMaxTurns = 20
+	openAIErrorPreviewMax = 500 // Max characters of an error response body in errors
+	openAISystemPrompt    = `You are a coding agent working in a repository. All paths are relative to the repository root.
+Use the provided tools to inspect the code before answering; do not guess file contents.
+When you are done, reply with your final answer as plain text.`
+)
+
+// openAIBackend runs prompts against an OpenAI-compatible chat-completions
+// endpoint. Tool calls are executed locally against the workspace (see localTools),
+// so any model with function calling can act as a read-only coding agent.
+type openAIBackend struct {
+	chatClient
+	model    string
+	maxTurns int
+}
+
+// chatClient sends requests to an OpenAI-compatible chat-completions endpoint.
+// It is shared by the agent backend and the C4 judge.
+type chatClient struct {
+	baseURL   string
+	apiKeyEnv string
+	client    *http.Client
+}
+
+func newChatClient(baseURL, apiKeyEnv string) chatClient {
+	c := chatClient{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		apiKeyEnv: apiKeyEnv,
+		client:    &http.Client{},
+	}
+	if c.baseURL == "" {
+		c.baseURL = defaultOpenAIBaseURL
+	}
+	if c.apiKeyEnv == "" {
+		c.apiKeyEnv = defaultOpenAIKeyEnv
+	}
+	return c
+}
+
+// check requires an API key for the public OpenAI API only; self-hosted
+// compatible servers often run without authentication.
+func (c chatClient) check() error {
+	if c.baseURL == defaultOpenAIBaseURL && os.Getenv(c.apiKeyEnv) == "" {
+		return fmt.Errorf("%s is not set", c.apiKeyEnv)
+	}
+	return nil
+}
+
+func newOpenAIBackend(cfg BackendConfig) (Backend, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("agent backend openai requires a model")
+	}
+	b := &openAIBackend{
+		chatClient: newChatClient(cfg.BaseURL, cfg.APIKeyEnv),
+		model:      cfg.Model,
+		maxTurns:   cfg.MaxTurns,
+	}
+	if b.maxTurns <= 0 {
+		b.maxTurns = defaultOpenAIMaxTurns
+	}
+	return b, nil
+}
+
+func (b *openAIBackend) Name() string {
+	return "openai (" + b.model + ")"
+}
+
+func (b *openAIBackend) Check() error {
+	if err := b.check(); err != nil {
+		return fmt.Errorf("agent backend openai: %w", err)
+	}
+	return nil
+}
+
+type chatMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+type chatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type chatTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Parameters  map[string]any `json:"parameters"`
+	} `json:"function"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Tools       []chatTool    `json:"tools,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// ExecutePrompt runs the tool-use loop: the model is called until it answers
+// without requesting tools or maxTurns round trips are used up.
+func (b *openAIBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	available := selectTools(tools)
+	req := chatRequest{
+		Model: b.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: openAISystemPrompt},
+			{Role: "user", Content: prompt},
+		},
+	}
+	for _, t := range available {
+		var ct chatTool
+		ct.Type = "function"
+		ct.Function.Name = t.name
+		ct.Function.Description = t.description
+		ct.Function.Parameters = t.parameters
+		req.Tools = append(req.Tools, ct)
+	}
+
+	start := time.Now()
+	// Failed prompts report the tool calls made until they ended, too.
+	trace := []metrics.ToolCall{}
+	defer func() { metrics.RecordToolCalls(ctx, trace) }()
+	for turn := 0; turn < b.maxTurns; turn++ {
+		msg, err := b.complete(ctx, req)
+		if err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("task timed out after %s", timeout)
+			}
+			return "", err
+		}
+		if len(msg.ToolCalls) == 0 {
+			return msg.Content, nil
+		}
+
+		req.Messages = append(req.Messages, msg)
+		for _, call := range msg.ToolCalls {
+			traced := newToolCall(workDir, call.Function.Name, json.RawMessage(call.Function.Arguments), time.Since(start))
+			out := runToolCall(workDir, tools, call)
+			traced.Duration = time.Since(start) - traced.Start
+			traced.Error = strings.HasPrefix(out, "Error: ")
+			trace = append(trace, traced)
+
+			req.Messages = append(req.Messages, chatMessage{
+				Role:       "tool",
+				ToolCallID: call.ID,
+				Content:    out,
+			})
+		}
+	}
+	return "", fmt.Errorf("no final answer after %d tool-use turns", b.maxTurns)
+}
+
+// runToolCall executes one requested tool call and returns its result, or an
+// error message the model can react to.
+func runToolCall(workDir, allowed string, call chatToolCall) string {
+	var tool *localTool
+	for _, t := range selectTools(allowed) {
+		if t.name == call.Function.Name {
+			tool = &t
+			break
+		}
+	}
+	if tool == nil {
+		return fmt.Sprintf("Error: tool %q is not available", call.Function.Name)
+	}
+	out, err := tool.run(workDir, json.RawMessage(call.Function.Arguments))
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return out
+}
+
+// complete sends one chat-completions request and returns the first choice's message.
+func (c chatClient) complete(ctx context.Context, req chatRequest) (chatMessage, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return chatMessage{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return chatMessage{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if key := os.Getenv(c.apiKeyEnv); key != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return chatMessage{}, fmt.Errorf("chat completion request: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return chatMessage{}, fmt.Errorf("read chat completion response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		preview := string(data)
+		if len(preview) > openAIErrorPreviewMax {
+			preview = preview[:openAIErrorPreviewMax] + "..."
+		}
+		return chatMessage{}, fmt.Errorf("chat completion: HTTP %d: %s", resp.StatusCode, preview)
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return chatMessage{}, fmt.Errorf("parse chat completion response: %w", err)
+	}
+	if parsed.Usage != nil {
+		metrics.RecordUsage(ctx, metrics.Usage{
+			Model:        req.Model,
+			InputTokens:  parsed.Usage.PromptTokens,
+			OutputTokens: parsed.Usage.CompletionTokens,
+		})
+	}
+	if len(parsed.Choices) == 0 {
+		return chatMessage{}, fmt.Errorf("chat completion returned no choices")
+	}
+	return parsed.Choices[0].Message, nil
+}
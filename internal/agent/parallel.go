@@ -2,11 +2,15 @@ package agent
 
 import (
 	"context"
+	"math"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/events"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -14,9 +18,29 @@ import (
 type ParallelResult struct {
 	Results     []metrics.MetricResult
 	TotalTokens int
+	CostUSD     float64 // usage of all LLM calls priced per model
 	Errors      []error
 }
 
+// RunOptions configures which metrics run and what they are scored against.
+type RunOptions struct {
+	Metrics []metrics.Metric    // metrics to run; nil means metrics.AllMetrics()
+	Truth   metrics.GroundTruth // static-analysis ground truth for metrics that support it; may be nil
+	Budget  *Budget             // LLM budget the samples are planned against; nil runs every sample
+	Repeats int                 // runs of every metric's samples (--c7-repeats); 0 or 1 runs them once
+}
+
+func (o RunOptions) repeats() int {
+	return max(o.Repeats, 1)
+}
+
+func (o RunOptions) metricList() []metrics.Metric {
+	if o.Metrics == nil {
+		return metrics.AllMetrics()
+	}
+	return o.Metrics
+}
+
 // RunMetricsParallel executes all metrics concurrently with progress updates.
 // It does not abort on individual metric failures - all metrics run to completion.
 // If executor is nil, a default CLIExecutorAdapter is created for live CLI execution.
@@ -26,8 +50,9 @@ func RunMetricsParallel(
 	targets []*types.AnalysisTarget,
 	progress *C7Progress,
 	executor metrics.Executor,
+	opts RunOptions,
 ) ParallelResult {
-	allMetrics := metrics.AllMetrics()
+	allMetrics := opts.metricList()
 	result := ParallelResult{
 		Results: make([]metrics.MetricResult, len(allMetrics)),
 		Errors:  make([]error, 0),
@@ -37,13 +62,18 @@ func RunMetricsParallel(
 		executor = newCLIExecutorAdapter(workDir)
 	}
 
+	samples := planSamples(allMetrics, targets, opts)
+	meter := opts.Budget.meter()
+	ctx = metrics.WithUsageRecorder(ctx, meter.Add)
+
 	g, ctx := errgroup.WithContext(ctx)
 	var mu sync.Mutex
 
 	for i, m := range allMetrics {
 		i, m := i, m
 		g.Go(func() error {
-			mr := runSingleMetric(ctx, m, workDir, targets, executor, progress)
+			mr := runSingleMetric(ctx, m, workDir, samples[i], executor, opts, progress)
+			emitMetricEnd(ctx, mr)
 			mu.Lock()
 			result.Results[i] = mr
 			reportMetricProgress(progress, m.ID(), mr)
@@ -56,22 +86,107 @@ func RunMetricsParallel(
 	for _, r := range result.Results {
 		result.TotalTokens += r.TokensUsed
 	}
+	result.CostUSD, _ = meter.Spent()
 	return result
 }
 
-func runSingleMetric(ctx context.Context, m metrics.Metric, workDir string, targets []*types.AnalysisTarget, executor metrics.Executor, progress *C7Progress) metrics.MetricResult {
-	samples := m.SelectSamples(targets)
+// planSamples selects every metric's samples, attaches ground truth and, with
+// a budget, trims the lists to what the budget is expected to afford. A nil
+// entry means the metric was skipped for the budget.
+func planSamples(allMetrics []metrics.Metric, targets []*types.AnalysisTarget, opts RunOptions) [][]metrics.Sample {
+	samples := make([][]metrics.Sample, len(allMetrics))
+	counts := make([]int, len(allMetrics))
+	perSample := make([]int, len(allMetrics))
+	for i, m := range allMetrics {
+		samples[i] = m.SelectSamples(targets)
+		metrics.AttachGroundTruth(m, samples[i], opts.Truth)
+		counts[i] = len(samples[i])
+		perSample[i] = metrics.CallsPerSample(m) * opts.repeats()
+	}
+	if opts.Budget == nil {
+		return samples
+	}
+
+	for i, n := range opts.Budget.Plan(counts, perSample) {
+		switch {
+		case n == 0 && counts[i] > 0:
+			samples[i] = nil
+			opts.Budget.Skip("C7 " + allMetrics[i].ID())
+		case n < counts[i]:
+			samples[i] = samples[i][:n]
+		}
+	}
+	return samples
+}
+
+// runSingleMetric executes m on its planned samples, once per repeat, and
+// totals the tokens its agent calls used. A metric without samples because of
+// the budget is not run.
+func runSingleMetric(ctx context.Context, m metrics.Metric, workDir string, samples []metrics.Sample, executor metrics.Executor, opts RunOptions, progress *C7Progress) metrics.MetricResult {
+	budget := opts.Budget
+	if samples == nil && budget != nil && budget.skippedMetric(m.ID()) {
+		return metrics.MetricResult{MetricID: m.ID(), MetricName: m.Name(), Skipped: ErrBudgetExhausted.Error()}
+	}
+	repeats := opts.repeats()
+	total := len(samples) * repeats
 	if progress != nil {
-		progress.SetMetricRunning(m.ID(), len(samples))
+		progress.SetMetricRunning(m.ID(), total)
 	}
-	return executeMetricWithProgress(ctx, m, workDir, samples, executor, progress)
+	events.Emit(ctx, types.Event{Type: types.EventMetricStart, Metric: m.ID(), Total: total})
+	executor = newSampleCounter(executor, m, total, progress)
+
+	var tokens atomic.Int64
+	ctx = metrics.WithUsageRecorder(ctx, func(u metrics.Usage) { tokens.Add(int64(u.Tokens())) })
+	var mr metrics.MetricResult
+	if repeats == 1 {
+		mr = executeMetricWithProgress(ctx, m, workDir, samples, executor, progress)
+	} else {
+		runs := make([]metrics.MetricResult, 0, repeats)
+		for r := 0; r < repeats && (r == 0 || ctx.Err() == nil); r++ { // mergeRepeats needs a run
+			runs = append(runs, executeMetricWithProgress(metrics.WithRepeat(ctx, r), m, workDir, samples, executor, progress))
+		}
+		mr = mergeRepeats(runs)
+	}
+	if mr.TokensUsed == 0 {
+		mr.TokensUsed = int(tokens.Load())
+	}
+	budget.noteRefused(&mr)
+	return mr
+}
+
+// mergeRepeats combines the results of a metric's repeats: the samples of all
+// repeats, their total tokens and duration, and the rounded mean of the scores
+// of the repeats that did not fail. It only reports an error if all failed.
+func mergeRepeats(runs []metrics.MetricResult) metrics.MetricResult {
+	merged := metrics.MetricResult{MetricID: runs[0].MetricID, MetricName: runs[0].MetricName}
+	var sum, scored int
+	for _, r := range runs {
+		merged.Samples = append(merged.Samples, r.Samples...)
+		merged.TokensUsed += r.TokensUsed
+		merged.Duration += r.Duration
+		if r.Error != "" {
+			merged.Error = r.Error
+			merged.RepeatScores = append(merged.RepeatScores, types.FailedRepeat)
+			continue
+		}
+		merged.RepeatScores = append(merged.RepeatScores, r.Score)
+		sum += r.Score
+		scored++
+	}
+	if scored > 0 {
+		merged.Score = int(math.Round(float64(sum) / float64(scored)))
+		merged.Error = ""
+	}
+	return merged
 }
 
 func reportMetricProgress(progress *C7Progress, metricID string, mr metrics.MetricResult) {
 	if progress == nil {
 		return
 	}
-	if mr.Error != "" {
+	if mr.Skipped != "" {
+		progress.SetMetricFailed(metricID, "skipped: "+mr.Skipped)
+	} else if mr.Error != "" {
 		progress.SetMetricFailed(metricID, mr.Error)
 	} else {
 		progress.SetMetricComplete(metricID, mr.Score)
@@ -88,14 +203,59 @@ func executeMetricWithProgress(
 	executor metrics.Executor,
 	progress *C7Progress,
 ) metrics.MetricResult {
-	// Execute the metric
-	result := m.Execute(ctx, workDir, samples, executor)
+	return m.Execute(ctx, workDir, samples, executor)
+}
 
-	// Progress updates happen inside Execute() via callbacks,
-	// or we can track sample progress here if needed.
-	// For now, the metric handles its own sample iteration.
+// sampleCounter is an Executor that reports a metric's sample progress: a
+// sample is finished once its metric made CallsPerSample agent calls for it.
+type sampleCounter struct {
+	metrics.Executor
+	metricID string
+	perCall  int // calls per sample
+	total    int // samples planned, including repeats
+	progress *C7Progress
+	calls    atomic.Int64
+}
 
-	return result
+func newSampleCounter(executor metrics.Executor, m metrics.Metric, total int, progress *C7Progress) *sampleCounter {
+	return &sampleCounter{
+		Executor: executor,
+		metricID: m.ID(),
+		perCall:  metrics.CallsPerSample(m),
+		total:    total,
+		progress: progress,
+	}
+}
+
+// ExecutePrompt runs the prompt and, if it finished a sample, updates the
+// progress display and emits an events.EventMetricSample.
+func (c *sampleCounter) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	response, err := c.Executor.ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+	n := int(c.calls.Add(1))
+	if n%c.perCall == 0 {
+		sample := min(n/c.perCall, c.total)
+		if c.progress != nil {
+			c.progress.SetMetricSample(c.metricID, sample)
+		}
+		events.Emit(ctx, types.Event{Type: types.EventMetricSample, Metric: c.metricID, Sample: sample, Total: c.total})
+	}
+	return response, err
+}
+
+// emitMetricEnd reports a finished metric to the context's event stream.
+func emitMetricEnd(ctx context.Context, mr metrics.MetricResult) {
+	e := types.Event{
+		Type:       types.EventMetricEnd,
+		Metric:     mr.MetricID,
+		Score:      mr.Score,
+		Tokens:     mr.TokensUsed,
+		DurationMS: mr.Duration.Milliseconds(),
+		Error:      mr.Error,
+	}
+	if mr.Skipped != "" {
+		e.Error = "skipped: " + mr.Skipped
+	}
+	events.Emit(ctx, e)
 }
 
 // RunMetricsSequential executes all metrics sequentially (fallback/debugging).
@@ -106,8 +266,9 @@ func RunMetricsSequential(
 	targets []*types.AnalysisTarget,
 	progress *C7Progress,
 	executor metrics.Executor,
+	opts RunOptions,
 ) ParallelResult {
-	allMetrics := metrics.AllMetrics()
+	allMetrics := opts.metricList()
 	result := ParallelResult{
 		Results: make([]metrics.MetricResult, len(allMetrics)),
 		Errors:  make([]error, 0),
@@ -118,27 +279,14 @@ func RunMetricsSequential(
 		executor = newCLIExecutorAdapter(workDir)
 	}
 
+	samples := planSamples(allMetrics, targets, opts)
+	meter := opts.Budget.meter()
+	ctx = metrics.WithUsageRecorder(ctx, meter.Add)
 	for i, m := range allMetrics {
-		samples := m.SelectSamples(targets)
-
-		if progress != nil {
-			progress.SetMetricRunning(m.ID(), len(samples))
-		}
-
-		metricResult := m.Execute(ctx, workDir, samples, executor)
+		metricResult := runSingleMetric(ctx, m, workDir, samples[i], executor, opts, progress)
+		emitMetricEnd(ctx, metricResult)
 		result.Results[i] = metricResult
-
-		if metricResult.Error != "" {
-			if progress != nil {
-				progress.SetMetricFailed(m.ID(), metricResult.Error)
-			}
-		} else {
-			if progress != nil {
-				progress.SetMetricComplete(m.ID(), metricResult.Score)
-				progress.AddTokens(metricResult.TokensUsed)
-			}
-		}
-
+		reportMetricProgress(progress, m.ID(), metricResult)
 		result.TotalTokens += metricResult.TokensUsed
 
 		// Check for context cancellation between metrics
@@ -147,5 +295,6 @@ func RunMetricsSequential(
 		}
 	}
 
+	result.CostUSD, _ = meter.Spent()
 	return result
 }
@@ -3,9 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/events"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -21,10 +24,10 @@ func TestRunMetricsParallel_NoTargets(t *testing.T) {
 	ctx := context.Background()
 
 	// Running with no targets should not panic
-	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{})
+	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{}, RunOptions{})
 
-	// Should have 5 results (one per metric)
-	if len(result.Results) != 5 {
+	// Should have 7 results (one per metric)
+	if len(result.Results) != 7 {
 		t.Errorf("got %d results, want 5", len(result.Results))
 	}
 
@@ -46,10 +49,10 @@ func TestRunMetricsParallel_NoTargets(t *testing.T) {
 func TestRunMetricsSequential_NoTargets(t *testing.T) {
 	ctx := context.Background()
 
-	result := RunMetricsSequential(ctx, "/tmp", nil, nil, &noopExecutor{})
+	result := RunMetricsSequential(ctx, "/tmp", nil, nil, &noopExecutor{}, RunOptions{})
 
-	if len(result.Results) != 5 {
-		t.Errorf("got %d results, want 5", len(result.Results))
+	if len(result.Results) != 7 {
+		t.Errorf("got %d results, want 7", len(result.Results))
 	}
 
 	// Results should be in the same order as AllMetrics
@@ -59,6 +62,8 @@ func TestRunMetricsSequential_NoTargets(t *testing.T) {
 		"cross_file_navigation",
 		"identifier_interpretability",
 		"documentation_accuracy_detection",
+		"change_success",
+		"feature_localization",
 	}
 
 	for i, expected := range expectedIDs {
@@ -73,7 +78,7 @@ func TestRunMetricsParallel_ContextCancellation(t *testing.T) {
 	cancel() // Cancel immediately
 
 	// Should complete without hanging
-	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{})
+	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{}, RunOptions{})
 
 	// Should still have results (possibly with errors)
 	if len(result.Results) == 0 {
@@ -86,7 +91,7 @@ func TestRunMetricsSequential_ContextCancellation(t *testing.T) {
 	cancel() // Cancel immediately
 
 	// Should complete without hanging
-	result := RunMetricsSequential(ctx, "/tmp", nil, nil, &noopExecutor{})
+	result := RunMetricsSequential(ctx, "/tmp", nil, nil, &noopExecutor{}, RunOptions{})
 
 	// Should have at least some results
 	if len(result.Results) == 0 {
@@ -104,14 +109,16 @@ func TestRunMetricsParallel_WithProgress(t *testing.T) {
 		"cross_file_navigation",
 		"identifier_interpretability",
 		"documentation_accuracy_detection",
+		"change_success",
+		"feature_localization",
 	}
 	progress := NewC7Progress(nil, ids, nil)
 
-	result := RunMetricsParallel(ctx, "/tmp", nil, progress, &noopExecutor{})
+	result := RunMetricsParallel(ctx, "/tmp", nil, progress, &noopExecutor{}, RunOptions{})
 
 	// Results should be populated
-	if len(result.Results) != 5 {
-		t.Errorf("got %d results, want 5", len(result.Results))
+	if len(result.Results) != 7 {
+		t.Errorf("got %d results, want 7", len(result.Results))
 	}
 
 	// Progress should reflect all metrics being processed
@@ -126,6 +133,31 @@ func TestRunMetricsParallel_WithProgress(t *testing.T) {
 	progress.mu.Unlock()
 }
 
+func TestSampleCounter_ReportsFinishedSamples(t *testing.T) {
+	m := metrics.AllMetrics()[0] // M1: several calls per sample
+	perSample := metrics.CallsPerSample(m)
+	progress := NewC7Progress(nil, []string{m.ID()}, nil)
+	var got []types.Event
+	ctx := events.WithEmitter(context.Background(), func(e types.Event) { got = append(got, e) })
+
+	c := newSampleCounter(&noopExecutor{}, m, 2, progress)
+	for i := 0; i < 2*perSample; i++ {
+		_, _ = c.ExecutePrompt(ctx, "/tmp", "prompt", "Read", time.Second)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d sample events, want 2: %+v", len(got), got)
+	}
+	if got[1].Type != types.EventMetricSample || got[1].Sample != 2 || got[1].Total != 2 || got[1].Metric != m.ID() {
+		t.Errorf("last event = %+v, want sample 2 of 2", got[1])
+	}
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+	if progress.metrics[m.ID()].CurrentSample != 2 {
+		t.Errorf("progress sample = %d, want 2", progress.metrics[m.ID()].CurrentSample)
+	}
+}
+
 func TestRunMetricsSequential_WithProgress(t *testing.T) {
 	ctx := context.Background()
 
@@ -135,13 +167,15 @@ func TestRunMetricsSequential_WithProgress(t *testing.T) {
 		"cross_file_navigation",
 		"identifier_interpretability",
 		"documentation_accuracy_detection",
+		"change_success",
+		"feature_localization",
 	}
 	progress := NewC7Progress(nil, ids, nil)
 
-	result := RunMetricsSequential(ctx, "/tmp", nil, progress, &noopExecutor{})
+	result := RunMetricsSequential(ctx, "/tmp", nil, progress, &noopExecutor{}, RunOptions{})
 
-	if len(result.Results) != 5 {
-		t.Errorf("got %d results, want 5", len(result.Results))
+	if len(result.Results) != 7 {
+		t.Errorf("got %d results, want 7", len(result.Results))
 	}
 }
 
@@ -149,7 +183,7 @@ func TestParallelResult_TotalTokensAccumulation(t *testing.T) {
 	// This tests that token counts are properly accumulated
 	ctx := context.Background()
 
-	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{})
+	result := RunMetricsParallel(ctx, "/tmp", nil, nil, &noopExecutor{}, RunOptions{})
 
 	// TotalTokens should be sum of all metric token counts
 	var expectedTotal int
@@ -165,11 +199,11 @@ func TestParallelResult_TotalTokensAccumulation(t *testing.T) {
 func TestRunMetricsParallel_AllMetricsComplete(t *testing.T) {
 	ctx := context.Background()
 
-	// Even with empty targets, all 5 metrics should complete (with errors)
-	result := RunMetricsParallel(ctx, "/tmp", []*types.AnalysisTarget{}, nil, &noopExecutor{})
+	// Even with empty targets, all 7 metrics should complete (with errors)
+	result := RunMetricsParallel(ctx, "/tmp", []*types.AnalysisTarget{}, nil, &noopExecutor{}, RunOptions{})
 
-	if len(result.Results) != 5 {
-		t.Errorf("got %d results, want 5", len(result.Results))
+	if len(result.Results) != 7 {
+		t.Errorf("got %d results, want 7", len(result.Results))
 	}
 
 	// Verify each metric has a non-empty ID and name
@@ -198,12 +232,12 @@ func TestRunMetricsSequential_StopsOnContextCancel(t *testing.T) {
 	// Cancel after a brief moment (simulates timeout)
 	cancel()
 
-	result := RunMetricsSequential(ctx, "/tmp", targets, nil, &noopExecutor{})
+	result := RunMetricsSequential(ctx, "/tmp", targets, nil, &noopExecutor{}, RunOptions{})
 
 	// Should have stopped early due to context cancellation
-	// May not have all 5 results if it checked context between metrics
-	if len(result.Results) > 5 {
-		t.Errorf("got %d results, want <= 5", len(result.Results))
+	// May not have all 7 results if it checked context between metrics
+	if len(result.Results) > 7 {
+		t.Errorf("got %d results, want <= 7", len(result.Results))
 	}
 }
 
@@ -218,3 +252,40 @@ func TestCLIExecutorAdapter_Creation(t *testing.T) {
 		t.Errorf("workDir = %q, want %q", adapter.workDir, "/test/dir")
 	}
 }
+
+// repeatMetric scores by repeat, failing the repeat fail.
+type repeatMetric struct {
+	countMetric
+	fail int
+}
+
+func (m *repeatMetric) Execute(ctx context.Context, workDir string, samples []metrics.Sample, executor metrics.Executor) metrics.MetricResult {
+	r := metrics.CallFromContext(ctx).Repeat
+	if r == m.fail {
+		return metrics.MetricResult{MetricID: m.id, MetricName: m.id, Error: "agent failed"}
+	}
+	return metrics.MetricResult{MetricID: m.id, MetricName: m.id, Score: 4 + 2*r, Samples: make([]metrics.SampleResult, len(samples))}
+}
+
+func TestRunMetrics_Repeats(t *testing.T) {
+	steady := &repeatMetric{countMetric: countMetric{id: "steady", count: 2}, fail: -1}
+	flaky := &repeatMetric{countMetric: countMetric{id: "flaky", count: 1}, fail: 1}
+	result := RunMetricsParallel(context.Background(), t.TempDir(), nil, nil, &noopExecutor{}, RunOptions{
+		Metrics: []metrics.Metric{steady, flaky},
+		Repeats: 3,
+	})
+
+	got := result.Results[0]
+	if got.Score != 6 || !slices.Equal(got.RepeatScores, []int{4, 6, 8}) || len(got.Samples) != 6 {
+		t.Errorf("steady: Score %d, RepeatScores %v, %d samples; want 6, [4 6 8], 6", got.Score, got.RepeatScores, len(got.Samples))
+	}
+	got = result.Results[1]
+	if got.Score != 6 || !slices.Equal(got.RepeatScores, []int{4, types.FailedRepeat, 8}) || got.Error != "" {
+		t.Errorf("flaky: Score %d, RepeatScores %v, Error %q; want 6, [4 -1 8], no error", got.Score, got.RepeatScores, got.Error)
+	}
+
+	single := RunMetricsParallel(context.Background(), t.TempDir(), nil, nil, &noopExecutor{}, RunOptions{Metrics: []metrics.Metric{steady}})
+	if single.Results[0].Score != 4 || single.Results[0].RepeatScores != nil {
+		t.Errorf("single run: Score %d, RepeatScores %v; want 4, nil", single.Results[0].Score, single.Results[0].RepeatScores)
+	}
+}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sync"
 	"time"
@@ -49,15 +50,15 @@ type C7Progress struct {
 	totalTokens int
 	startTime   time.Time
 	isTTY       bool
-	writer      *os.File
+	writer      io.Writer
 	ticker      *time.Ticker
 	done        chan struct{}
 	active      bool
 }
 
 // NewC7Progress creates a new progress display.
-// If writer is not a TTY, display operations are no-ops.
-func NewC7Progress(w *os.File, metricIDs []string, metricNames []string) *C7Progress {
+// If writer is not a terminal, display operations are no-ops.
+func NewC7Progress(w io.Writer, metricIDs []string, metricNames []string) *C7Progress {
 	metrics := make(map[string]*metricProgress, len(metricIDs))
 	for i, id := range metricIDs {
 		name := id
@@ -74,7 +75,7 @@ func NewC7Progress(w *os.File, metricIDs []string, metricNames []string) *C7Prog
 	return &C7Progress{
 		metrics:     metrics,
 		metricOrder: metricIDs,
-		isTTY:       isatty.IsTerminal(w.Fd()) || isatty.IsCygwinTerminal(w.Fd()),
+		isTTY:       isTerminal(w),
 		writer:      w,
 		done:        make(chan struct{}),
 	}
@@ -248,7 +249,7 @@ func (p *C7Progress) printSummary() {
 	fmt.Fprintf(p.writer, "C7 Evaluation complete in %s | Tokens: %s | Cost: $%.2f\n", elapsed, tokenStr, costUSD)
 }
 
-// shortMetricID returns a short display ID (M1-M5) for a metric ID.
+// shortMetricID returns a short display ID (M1-M7) for a metric ID.
 func shortMetricID(id string) string {
 	switch id {
 	case "task_execution_consistency":
@@ -261,6 +262,10 @@ func shortMetricID(id string) string {
 		return "M4"
 	case "documentation_accuracy_detection":
 		return "M5"
+	case "change_success":
+		return "M6"
+	case "feature_localization":
+		return "M7"
 	default:
 		if len(id) >= 2 {
 			return id[:2]
@@ -279,3 +284,9 @@ func formatTokens(n int) string {
 	}
 	return fmt.Sprintf("%d,%03d,%03d", n/tokensPerMillion, (n/tokensPerThousand)%tokensPerThousand, n%tokensPerThousand)
 }
+
+// isTerminal reports whether w is a terminal the display can redraw.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && f != nil && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
@@ -22,6 +22,10 @@ type DebugResponse struct {
 	Response    string  `json:"response"`
 	Duration    float64 `json:"duration_seconds"`
 	Error       string  `json:"error,omitempty"`
+
+	// ToolCalls is the agent's tool-call trace, replayed to tool-call
+	// recorders; null if the backend did not trace tool calls.
+	ToolCalls []metrics.ToolCall `json:"tool_calls"`
 }
 
 // SaveResponses persists metric results as individual JSON files in debugDir.
@@ -41,6 +45,7 @@ func SaveResponses(debugDir string, results []metrics.MetricResult) error {
 				Response:    sr.Response,
 				Duration:    sr.Duration.Seconds(),
 				Error:       sr.Error,
+				ToolCalls:   sr.ToolCalls,
 			}
 
 			filename := fmt.Sprintf("%s_%d.json", mr.MetricID, sampleIdx)
@@ -119,6 +124,16 @@ func (r *ReplayExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, too
 
 	key := fmt.Sprintf("%s_%d", metricID, idx)
 	resp, ok := r.responses[key]
+	if !ok && metricID == "unknown" {
+		// User-defined task prompts have no distinctive text; match the
+		// captured prompt exactly instead.
+		for _, captured := range r.responses {
+			if captured.Prompt == prompt {
+				resp, ok = captured, true
+				break
+			}
+		}
+	}
 	if !ok {
 		return "", fmt.Errorf("no replay data for %s", key)
 	}
@@ -126,6 +141,9 @@ func (r *ReplayExecutor) ExecutePrompt(ctx context.Context, workDir, prompt, too
 	if resp.Error != "" {
 		return "", fmt.Errorf("replayed error: %s", resp.Error)
 	}
+	if resp.ToolCalls != nil {
+		metrics.RecordToolCalls(ctx, resp.ToolCalls)
+	}
 
 	return resp.Response, nil
 }
@@ -145,6 +163,10 @@ func identifyMetricFromPrompt(prompt string) string {
 		return "identifier_interpretability"
 	case strings.Contains(lower, "review the documentation") || strings.Contains(lower, "identify any inaccuracies") || strings.Contains(lower, "documentation accuracy"):
 		return "documentation_accuracy_detection"
+	case strings.Contains(lower, "change nothing else"):
+		return "change_success"
+	case strings.Contains(lower, "find where this feature is implemented"):
+		return "feature_localization"
 	default:
 		return "unknown"
 	}
@@ -50,6 +50,9 @@ func TestSaveLoadResponses(t *testing.T) {
 					Score:    5,
 					Duration: 3200 * time.Millisecond,
 					Error:    "",
+					ToolCalls: []metrics.ToolCall{
+						{Tool: "Read", Input: `{"file_path":"/test/handler.go"}`, Path: "/test/handler.go", Start: time.Second},
+					},
 				},
 			},
 			Duration: 4 * time.Second,
@@ -106,6 +109,12 @@ func TestSaveLoadResponses(t *testing.T) {
 	if m3.MetricID != "cross_file_navigation" {
 		t.Errorf("MetricID = %q, want %q", m3.MetricID, "cross_file_navigation")
 	}
+	if m2.ToolCalls != nil {
+		t.Errorf("untraced sample: ToolCalls = %+v, want nil", m2.ToolCalls)
+	}
+	if len(m3.ToolCalls) != 1 || m3.ToolCalls[0].Start != time.Second {
+		t.Errorf("ToolCalls = %+v, want the traced Read at 1s", m3.ToolCalls)
+	}
 }
 
 func TestReplayExecutor(t *testing.T) {
@@ -233,6 +242,11 @@ func TestIdentifyMetricFromPrompt(t *testing.T) {
 			prompt:   "Read the comments and identify any inaccuracies in the documentation.",
 			expected: "documentation_accuracy_detection",
 		},
+		{
+			name:     "M7 feature localization",
+			prompt:   "Find where this feature is implemented in the repository:\n\n\"Reads the configuration file and applies defaults.\"",
+			expected: "feature_localization",
+		},
 		{
 			name:     "unknown prompt",
 			prompt:   "Hello, how are you?",
@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"context"
+	"math/rand/v2"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+// DefaultMaxRetries is how often an agent call failing with a retryable error
+// is retried unless Limits.MaxRetries says otherwise.
+const DefaultMaxRetries = 3
+
+// Retry backoff: the delay before retry n is retryBaseDelay*2^n, capped at
+// retryMaxDelay, of which the second half is random jitter.
+const (
+	retryBaseDelay = 2 * time.Second
+	retryMaxDelay  = time.Minute
+)
+
+// Limits bound the load C7 puts on the agent backend, e.g. when several CI
+// jobs share an API key.
+type Limits struct {
+	MaxConcurrency    int // agent calls in flight at once; 0 for no limit
+	RequestsPerMinute int // agent calls started per minute; 0 for no limit
+	MaxRetries        int // retries of a call failing with a rate limit or transient error
+}
+
+// retryablePattern matches the errors of rate-limited or overloaded APIs: HTTP
+// 429 and 5xx responses of OpenAI-compatible endpoints, and the API errors the
+// Claude CLI prints before exiting.
+var retryablePattern = regexp.MustCompile(`(?i)rate.?limit|too many requests|overloaded|(http|api error:?|status:?) (429|5\d\d)\b`)
+
+// retryable reports whether err is worth retrying after a backoff.
+func retryable(err error) bool {
+	return err != nil && retryablePattern.MatchString(err.Error())
+}
+
+// throttledBackend caps the concurrency and start rate of agent calls and
+// retries calls failing with retryable errors with exponential backoff.
+type throttledBackend struct {
+	Backend
+	limits Limits
+	slots  chan struct{} // one per call in flight; nil without a concurrency limit
+	bucket *tokenBucket  // nil without a rate limit
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// NewThrottledBackend wraps b to enforce limits. Retries are reported to the
+// context's retry recorders (see metrics.WithRetryRecorder).
+func NewThrottledBackend(b Backend, limits Limits) Backend {
+	if limits.MaxConcurrency <= 0 && limits.RequestsPerMinute <= 0 && limits.MaxRetries <= 0 {
+		return b
+	}
+	t := &throttledBackend{Backend: b, limits: limits, sleep: sleepContext}
+	if limits.MaxConcurrency > 0 {
+		t.slots = make(chan struct{}, limits.MaxConcurrency)
+	}
+	if limits.RequestsPerMinute > 0 {
+		t.bucket = newTokenBucket(float64(limits.RequestsPerMinute)/60, max(limits.MaxConcurrency, 1), time.Now)
+	}
+	return t
+}
+
+// unwrap implements wrapper.
+func (t *throttledBackend) unwrap() any { return t.Backend }
+
+// ExecutePrompt implements metrics.Executor.
+func (t *throttledBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := t.execute(ctx, workDir, prompt, tools, timeout)
+		if err == nil || attempt >= t.limits.MaxRetries || !retryable(err) || ctx.Err() != nil {
+			return response, err
+		}
+		metrics.RecordRetry(ctx)
+		if sleepErr := t.sleep(ctx, backoff(attempt)); sleepErr != nil {
+			return response, err
+		}
+	}
+}
+
+// execute runs one attempt once a slot and a rate token are available. The
+// attempt's timeout starts only then, so queueing does not eat into it.
+func (t *throttledBackend) execute(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+			defer func() { <-t.slots }()
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if t.bucket != nil {
+		if err := t.sleep(ctx, t.bucket.reserve()); err != nil {
+			return "", err
+		}
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return t.Backend.ExecutePrompt(ctx, workDir, prompt, tools, timeout)
+}
+
+// backoff returns the jittered delay before retry attempt+1.
+func backoff(attempt int) time.Duration {
+	d := retryMaxDelay
+	if attempt < 16 {
+		d = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// tokenBucket is a requests-per-time limiter: it holds up to burst tokens,
+// refilled at rate per second, and every call takes one.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now(), now: now}
+}
+
+// reserve takes a token and returns how long the caller must wait until it is
+// due. Tokens are reserved in call order, so waiting callers are served
+// first-come, first-served.
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,204 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  string
+		want bool
+	}{
+		{"chat completion: HTTP 429: {\"error\":\"rate limited\"}", true},
+		{"chat completion: HTTP 503: upstream unavailable", true},
+		{"execution failed: exit code 1: API Error: 529 {\"type\":\"overloaded_error\"}", true},
+		{"execution failed: exit code 1: API Error: 429 rate_limit_error", true},
+		{"chat completion: HTTP 401: invalid api key", false},
+		{"task timed out after 60 seconds", false},
+		{"execution failed: exit code 1: read 500 lines", false},
+	}
+	for _, tc := range tests {
+		if got := retryable(errors.New(tc.err)); got != tc.want {
+			t.Errorf("retryable(%q) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// flakyBackend fails the first failures calls with err and tracks how many
+// calls run at once.
+type flakyBackend struct {
+	failures int
+	err      error
+
+	mu       sync.Mutex
+	calls    int
+	inFlight int
+	peak     int
+}
+
+func (b *flakyBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	b.mu.Lock()
+	b.calls++
+	call := b.calls
+	b.inFlight++
+	b.peak = max(b.peak, b.inFlight)
+	b.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	b.mu.Lock()
+	b.inFlight--
+	b.mu.Unlock()
+	if call <= b.failures {
+		return "", b.err
+	}
+	return "ok", nil
+}
+
+func (b *flakyBackend) Name() string { return "flaky" }
+func (b *flakyBackend) Check() error { return nil }
+
+func TestThrottledBackend_Retries(t *testing.T) {
+	inner := &flakyBackend{failures: 2, err: errors.New("chat completion: HTTP 429: slow down")}
+	b := NewThrottledBackend(inner, Limits{MaxRetries: 3}).(*throttledBackend)
+	var delays []time.Duration
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	retries := 0
+	ctx := metrics.WithRetryRecorder(context.Background(), func() { retries++ })
+	out, err := b.ExecutePrompt(ctx, "", "hi", "Read", time.Minute)
+	if err != nil || out != "ok" {
+		t.Fatalf("ExecutePrompt() = %q, %v, want ok after retries", out, err)
+	}
+	if inner.calls != 3 || retries != 2 {
+		t.Errorf("calls, retries = %d, %d, want 3, 2", inner.calls, retries)
+	}
+	if len(delays) != 2 || delays[0] < retryBaseDelay/2 || delays[0] > retryBaseDelay || delays[1] < retryBaseDelay || delays[1] > 2*retryBaseDelay {
+		t.Errorf("backoff delays = %v, want jittered 1-2s then 2-4s", delays)
+	}
+
+	// Errors that are not retryable, and retries beyond the limit, fail.
+	inner = &flakyBackend{failures: 1, err: errors.New("chat completion: HTTP 401: bad key")}
+	b.Backend = inner
+	if _, err := b.ExecutePrompt(context.Background(), "", "hi", "Read", time.Minute); err == nil || inner.calls != 1 {
+		t.Errorf("non-retryable error: err = %v after %d calls, want failure after 1", err, inner.calls)
+	}
+	inner = &flakyBackend{failures: 10, err: errors.New("API Error: 529 overloaded")}
+	b.Backend = inner
+	if _, err := b.ExecutePrompt(context.Background(), "", "hi", "Read", time.Minute); err == nil || inner.calls != 4 {
+		t.Errorf("persistent rate limit: err = %v after %d calls, want failure after 4", err, inner.calls)
+	}
+}
+
+func TestThrottledBackend_MaxConcurrency(t *testing.T) {
+	inner := &flakyBackend{}
+	b := NewThrottledBackend(inner, Limits{MaxConcurrency: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.ExecutePrompt(context.Background(), "", "hi", "Read", time.Minute)
+		}()
+	}
+	wg.Wait()
+	if inner.calls != 8 || inner.peak > 2 {
+		t.Errorf("calls = %d, peak concurrency = %d, want 8 calls, at most 2 at once", inner.calls, inner.peak)
+	}
+}
+
+// deadlineBackend fails with a rate limit and records how much of its
+// context's deadline is left when each attempt starts.
+type deadlineBackend struct {
+	remaining []time.Duration
+}
+
+func (b *deadlineBackend) ExecutePrompt(ctx context.Context, workDir, prompt, tools string, timeout time.Duration) (string, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "", errors.New("no deadline")
+	}
+	b.remaining = append(b.remaining, time.Until(deadline))
+	return "", errors.New("HTTP 429: slow down")
+}
+
+func (b *deadlineBackend) Name() string { return "deadline" }
+func (b *deadlineBackend) Check() error { return nil }
+
+func TestThrottledBackend_TimeoutPerAttempt(t *testing.T) {
+	inner := &deadlineBackend{}
+	b := NewThrottledBackend(inner, Limits{MaxRetries: 2}).(*throttledBackend)
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		return sleepContext(ctx, 60*time.Millisecond)
+	}
+
+	const timeout = 100 * time.Millisecond
+	if _, err := b.ExecutePrompt(context.Background(), "", "hi", "Read", timeout); err == nil {
+		t.Fatal("expected the last attempt's error")
+	}
+	if len(inner.remaining) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(inner.remaining))
+	}
+	for i, left := range inner.remaining {
+		if left < timeout/2 {
+			t.Errorf("attempt %d started with %v of its deadline left, want a fresh %v", i, left, timeout)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(1, 2, func() time.Time { return now }) // 60 per minute, burst 2
+
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		waits = append(waits, b.reserve())
+	}
+	want := []time.Duration{0, 0, time.Second, 2 * time.Second}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("waits = %v, want %v", waits, want)
+			break
+		}
+	}
+
+	now = now.Add(10 * time.Second) // refills to the burst, not beyond
+	if w1, w2, w3 := b.reserve(), b.reserve(), b.reserve(); w1 != 0 || w2 != 0 || w3 != time.Second {
+		t.Errorf("after refill: waits = %v, %v, %v, want 0, 0, 1s", w1, w2, w3)
+	}
+}
+
+func TestThrottledBackend_RateLimit(t *testing.T) {
+	inner := &flakyBackend{}
+	b := NewThrottledBackend(inner, Limits{RequestsPerMinute: 60}).(*throttledBackend)
+	var slept atomic.Int64
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		slept.Add(int64(d))
+		return nil
+	}
+	for i := 0; i < 3; i++ {
+		b.ExecutePrompt(context.Background(), "", "hi", "Read", time.Minute)
+	}
+	// One call starts at once; the next two wait about one and two seconds.
+	if got := time.Duration(slept.Load()); got < 2900*time.Millisecond || got > 3*time.Second {
+		t.Errorf("total wait = %s, want about 3s", got)
+	}
+}
+
+func TestNewThrottledBackend_NoLimits(t *testing.T) {
+	inner := &flakyBackend{}
+	if b := NewThrottledBackend(inner, Limits{}); b != Backend(inner) {
+		t.Errorf("NewThrottledBackend without limits = %T, want the backend unchanged", b)
+	}
+}
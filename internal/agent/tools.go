@@ -0,0 +1,407 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+)
+
+// Limits keeping local tool results within a model's context window.
+const (
+	toolReadMaxLines   = 2000 // lines returned by Read without an explicit limit
+	toolReadMaxLineLen = 2000 // characters per line before truncation
+	toolGlobMaxResults = 200  // paths returned by Glob
+	toolGrepMaxResults = 200  // matching lines returned by Grep
+)
+
+// localTool is a tool executed on behalf of an HTTP-backed agent, mirroring
+// the Claude CLI tool of the same name. Write and Edit are only offered when a
+// metric asks for them, e.g. C7 change tasks in a throwaway worktree.
+type localTool struct {
+	name        string
+	description string
+	parameters  map[string]any // JSON schema of the arguments
+	run         func(root string, args json.RawMessage) (string, error)
+}
+
+// localTools are the tools an HTTP backend can offer, by name.
+var localTools = map[string]localTool{
+	"Read": {
+		name:        "Read",
+		description: "Read a file from the repository. Returns lines prefixed with line numbers.",
+		parameters: objectSchema(map[string]any{
+			"file_path": stringProp("Path of the file, relative to the repository root"),
+			"offset":    intProp("1-based line to start reading from"),
+			"limit":     intProp("Maximum number of lines to read"),
+		}, "file_path"),
+		run: runRead,
+	},
+	"Glob": {
+		name:        "Glob",
+		description: "Find files by glob pattern (supports ** for any number of directories), e.g. \"**/*.go\".",
+		parameters: objectSchema(map[string]any{
+			"pattern": stringProp("Glob pattern matched against paths relative to the search directory"),
+			"path":    stringProp("Directory to search in, relative to the repository root (default: root)"),
+		}, "pattern"),
+		run: runGlob,
+	},
+	"Grep": {
+		name:        "Grep",
+		description: "Search file contents with a regular expression. Returns path:line:text for each match.",
+		parameters: objectSchema(map[string]any{
+			"pattern": stringProp("Regular expression (Go RE2 syntax)"),
+			"path":    stringProp("File or directory to search, relative to the repository root (default: root)"),
+			"glob":    stringProp("Only search files whose name matches this glob, e.g. \"*.py\""),
+		}, "pattern"),
+		run: runGrep,
+	},
+	"Write": {
+		name:        "Write",
+		description: "Write a file in the repository, replacing its content. Parent directories are created.",
+		parameters: objectSchema(map[string]any{
+			"file_path": stringProp("Path of the file, relative to the repository root"),
+			"content":   stringProp("The complete new content of the file"),
+		}, "file_path", "content"),
+		run: runWrite,
+	},
+	"Edit": {
+		name:        "Edit",
+		description: "Replace text in a file. old_string must occur exactly once unless replace_all is set.",
+		parameters: objectSchema(map[string]any{
+			"file_path":   stringProp("Path of the file, relative to the repository root"),
+			"old_string":  stringProp("The exact text to replace"),
+			"new_string":  stringProp("The replacement text"),
+			"replace_all": boolProp("Replace every occurrence of old_string"),
+		}, "file_path", "old_string", "new_string"),
+		run: runEdit,
+	},
+}
+
+func objectSchema(props map[string]any, required ...string) map[string]any {
+	return map[string]any{"type": "object", "properties": props, "required": required}
+}
+
+func stringProp(desc string) map[string]any {
+	return map[string]any{"type": "string", "description": desc}
+}
+
+func intProp(desc string) map[string]any {
+	return map[string]any{"type": "integer", "description": desc}
+}
+
+func boolProp(desc string) map[string]any {
+	return map[string]any{"type": "boolean", "description": desc}
+}
+
+// selectTools returns the local tools named in a comma-separated list such as
+// "Read,Glob,Grep". Unknown names are ignored.
+func selectTools(list string) []localTool {
+	var tools []localTool
+	for _, name := range strings.Split(list, ",") {
+		if t, ok := localTools[strings.TrimSpace(name)]; ok {
+			tools = append(tools, t)
+		}
+	}
+	return tools
+}
+
+// resolveToolPath resolves a tool path argument against root and rejects paths
+// outside of it, so an agent cannot read beyond the workspace. Symbolic links
+// are followed before the check, so a link inside the repository cannot lead
+// out of it.
+func resolveToolPath(root, p string) (string, error) {
+	if p == "" {
+		return root, nil
+	}
+	abs := p
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, p)
+	}
+	abs = filepath.Clean(abs)
+	if !within(root, abs) {
+		return "", fmt.Errorf("path %s is outside the repository", p)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	real, err := evalExisting(abs)
+	if err != nil {
+		return "", err
+	}
+	if !within(realRoot, real) {
+		return "", fmt.Errorf("path %s is outside the repository", p)
+	}
+	return abs, nil
+}
+
+// evalExisting resolves the symbolic links in path. For a path that does not
+// exist yet, such as a file about to be written, it resolves the nearest
+// existing ancestor and appends the rest.
+func evalExisting(path string) (string, error) {
+	var rest []string
+	for {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return filepath.Join(append([]string{real}, rest...)...), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		rest = append([]string{filepath.Base(path)}, rest...)
+		path = parent
+	}
+}
+
+// within reports whether the clean path is root or lies below it.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func runRead(root string, raw json.RawMessage) (string, error) {
+	var args struct {
+		FilePath string `json:"file_path"`
+		Offset   int    `json:"offset"`
+		Limit    int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	path, err := resolveToolPath(root, args.FilePath)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	start := max(args.Offset, 1)
+	limit := args.Limit
+	if limit <= 0 {
+		limit = toolReadMaxLines
+	}
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if line < start {
+			continue
+		}
+		if line >= start+limit {
+			break
+		}
+		text := scanner.Text()
+		if len(text) > toolReadMaxLineLen {
+			text = text[:toolReadMaxLineLen] + "..."
+		}
+		fmt.Fprintf(&b, "%6d\t%s\n", line, text)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func runGlob(root string, raw json.RawMessage) (string, error) {
+	var args struct {
+		Pattern string `json:"pattern"`
+		Path    string `json:"path"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	dir, err := resolveToolPath(root, args.Path)
+	if err != nil {
+		return "", err
+	}
+	if !discovery.ValidGlob(args.Pattern) {
+		return "", fmt.Errorf("invalid glob %q", args.Pattern)
+	}
+
+	var matches []string
+	err = walkFiles(dir, func(path, rel string) bool {
+		if discovery.MatchGlob(args.Pattern, rel) {
+			relRoot, _ := filepath.Rel(root, path)
+			matches = append(matches, filepath.ToSlash(relRoot))
+		}
+		return len(matches) < toolGlobMaxResults
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "No files found", nil
+	}
+	return strings.Join(matches, "\n"), nil
+}
+
+func runGrep(root string, raw json.RawMessage) (string, error) {
+	var args struct {
+		Pattern string `json:"pattern"`
+		Path    string `json:"path"`
+		Glob    string `json:"glob"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	re, err := regexp.Compile(args.Pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+	target, err := resolveToolPath(root, args.Path)
+	if err != nil {
+		return "", err
+	}
+	if args.Glob != "" && !discovery.ValidGlob(args.Glob) {
+		return "", fmt.Errorf("invalid glob %q", args.Glob)
+	}
+
+	var matches []string
+	grepFile := func(path string) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return
+		}
+		relRoot, _ := filepath.Rel(root, path)
+		for i, line := range strings.Split(string(data), "\n") {
+			if len(matches) >= toolGrepMaxResults {
+				return
+			}
+			if re.MatchString(line) {
+				matches = append(matches, fmt.Sprintf("%s:%d:%s", filepath.ToSlash(relRoot), i+1, line))
+			}
+		}
+	}
+
+	if info, statErr := os.Stat(target); statErr == nil && !info.IsDir() {
+		grepFile(target)
+	} else {
+		err = walkFiles(target, func(path, rel string) bool {
+			if args.Glob == "" || discovery.MatchGlob(args.Glob, filepath.Base(path)) || discovery.MatchGlob(args.Glob, rel) {
+				grepFile(path)
+			}
+			return len(matches) < toolGrepMaxResults
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(matches) == 0 {
+		return "No matches found", nil
+	}
+	return strings.Join(matches, "\n"), nil
+}
+
+func runWrite(root string, raw json.RawMessage) (string, error) {
+	var args struct {
+		FilePath string `json:"file_path"`
+		Content  string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	path, err := resolveToolPath(root, args.FilePath)
+	if err != nil {
+		return "", err
+	}
+	if path == root {
+		return "", fmt.Errorf("file_path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(args.Content), 0644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Wrote %d bytes to %s", len(args.Content), args.FilePath), nil
+}
+
+func runEdit(root string, raw json.RawMessage) (string, error) {
+	var args struct {
+		FilePath   string `json:"file_path"`
+		OldString  string `json:"old_string"`
+		NewString  string `json:"new_string"`
+		ReplaceAll bool   `json:"replace_all"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	path, err := resolveToolPath(root, args.FilePath)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if args.OldString == "" {
+		return "", fmt.Errorf("old_string must not be empty")
+	}
+	content := string(data)
+	n := strings.Count(content, args.OldString)
+	switch {
+	case n == 0:
+		return "", fmt.Errorf("old_string not found in %s", args.FilePath)
+	case n > 1 && !args.ReplaceAll:
+		return "", fmt.Errorf("old_string occurs %d times in %s; add context or set replace_all", n, args.FilePath)
+	}
+	replaced := 1
+	if args.ReplaceAll {
+		replaced = n
+	}
+	content = strings.Replace(content, args.OldString, args.NewString, replaced)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Replaced %d occurrence(s) in %s", replaced, args.FilePath), nil
+}
+
+// walkFiles calls fn for each regular file under dir with its slash-separated
+// path relative to dir, skipping VCS and dependency directories. It stops
+// when fn returns false.
+func walkFiles(dir string, fn func(path, rel string) bool) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			switch d.Name() {
+			case ".git", "node_modules", "vendor", "__pycache__":
+				if path != dir {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if !fn(path, filepath.ToSlash(rel)) {
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return err
+}
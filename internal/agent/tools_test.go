@@ -0,0 +1,140 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveToolPath_RejectsEscapes(t *testing.T) {
+	root := t.TempDir()
+	for _, p := range []string{"../secret", "/etc/passwd", "a/../../b"} {
+		if _, err := resolveToolPath(root, p); err == nil {
+			t.Errorf("resolveToolPath(%q) succeeded, want error", p)
+		}
+	}
+	if got, err := resolveToolPath(root, "pkg/../main.go"); err != nil || got != filepath.Join(root, "main.go") {
+		t.Errorf("resolveToolPath(pkg/../main.go) = %q, %v", got, err)
+	}
+}
+
+func TestResolveToolPath_RejectsSymlinkEscapes(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "linkdir")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("main.go", filepath.Join(root, "alias.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"link.txt", "linkdir/secret.txt", "linkdir/new.txt", "linkdir/sub/new.txt"} {
+		if _, err := resolveToolPath(root, p); err == nil {
+			t.Errorf("resolveToolPath(%q) succeeded, want error", p)
+		}
+	}
+	for _, p := range []string{"alias.go", "new/dir/file.go"} {
+		if _, err := resolveToolPath(root, p); err != nil {
+			t.Errorf("resolveToolPath(%q) error: %v", p, err)
+		}
+	}
+	if _, err := runRead(root, []byte(`{"file_path":"link.txt"}`)); err == nil {
+		t.Error("Read through symlink out of root succeeded, want error")
+	}
+	if _, err := runWrite(root, []byte(`{"file_path":"linkdir/new.txt","content":"x"}`)); err == nil {
+		t.Error("Write through symlink out of root succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); err == nil {
+		t.Error("Write created a file outside root")
+	}
+}
+
+func TestLocalTools(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":             "package main\n\nfunc main() {}\n",
+		"pkg/util/util.go":    "package util\n\nfunc Helper() {}\n",
+		"node_modules/x/x.go": "func Helper() {}\n",
+		"docs/guide.md":       "Call Helper to start.\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := runRead(root, []byte(`{"file_path":"main.go","offset":3,"limit":1}`))
+	if err != nil || out != "     3\tfunc main() {}\n" {
+		t.Errorf("Read = %q, %v", out, err)
+	}
+
+	out, err = runGlob(root, []byte(`{"pattern":"**/*.go"}`))
+	if err != nil || out != "main.go\npkg/util/util.go" {
+		t.Errorf("Glob = %q, %v", out, err)
+	}
+
+	out, err = runGlob(root, []byte(`{"pattern":"**/*.{go,md}"}`))
+	if err != nil || out != "docs/guide.md\nmain.go\npkg/util/util.go" {
+		t.Errorf("Glob with alternatives = %q, %v", out, err)
+	}
+
+	if _, err := runGlob(root, []byte(`{"pattern":"src/[a-z"}`)); err == nil {
+		t.Error("Glob with a malformed pattern succeeded, want error")
+	}
+
+	out, err = runGrep(root, []byte(`{"pattern":"func Helper","glob":"*.go"}`))
+	if err != nil || out != "pkg/util/util.go:3:func Helper() {}" {
+		t.Errorf("Grep = %q, %v", out, err)
+	}
+
+	out, err = runGrep(root, []byte(`{"pattern":"Helper","glob":"{docs,pkg}/**/*.{go,md}"}`))
+	if err != nil || out != "docs/guide.md:1:Call Helper to start.\npkg/util/util.go:3:func Helper() {}" {
+		t.Errorf("Grep with alternatives = %q, %v", out, err)
+	}
+
+	if _, err := runRead(root, []byte(`{"file_path":"../outside.txt"}`)); err == nil || !strings.Contains(err.Error(), "outside the repository") {
+		t.Errorf("Read outside root error = %v", err)
+	}
+
+	if got := runToolCall(root, "Read", chatToolCall{Function: struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	}{Name: "Grep", Arguments: `{"pattern":"x"}`}}); !strings.Contains(got, "not available") {
+		t.Errorf("disallowed tool result = %q", got)
+	}
+}
+
+func TestLocalWriteTools(t *testing.T) {
+	root := t.TempDir()
+
+	if _, err := runWrite(root, []byte(`{"file_path":"pkg/a.go","content":"package pkg\n\nfunc A() {}\nfunc B() { A() }\n"}`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := runEdit(root, []byte(`{"file_path":"pkg/a.go","old_string":"A()","new_string":"C()"}`)); err == nil || !strings.Contains(err.Error(), "occurs 2 times") {
+		t.Errorf("ambiguous Edit error = %v", err)
+	}
+	out, err := runEdit(root, []byte(`{"file_path":"pkg/a.go","old_string":"A()","new_string":"C()","replace_all":true}`))
+	if err != nil || out != "Replaced 2 occurrence(s) in pkg/a.go" {
+		t.Errorf("Edit = %q, %v", out, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(root, "pkg", "a.go"))
+	if string(data) != "package pkg\n\nfunc C() {}\nfunc B() { C() }\n" {
+		t.Errorf("content after Edit = %q", data)
+	}
+
+	if _, err := runWrite(root, []byte(`{"file_path":"../escape.go","content":"x"}`)); err == nil {
+		t.Error("Write outside root succeeded, want error")
+	}
+}
@@ -1,7 +1,11 @@
 // Package agent provides C7 agent evaluation infrastructure for headless Claude Code execution.
 package agent
 
-import "time"
+import (
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
 
 // taskStatus represents the completion status of an agent task.
 type taskStatus string
@@ -29,14 +33,16 @@ type task struct {
 
 // taskResult holds the outcome of executing a single task.
 type taskResult struct {
-	TaskID    string        // Which task was executed
-	Status    taskStatus    // Completion status
-	Response  string        // Agent's text response (if completed)
-	SessionID string        // Claude session ID (for debugging)
-	StartTime time.Time     // When execution began
-	EndTime   time.Time     // When execution finished
-	Duration  time.Duration // EndTime - StartTime
-	Error     string        // Error message (if status is error)
+	TaskID    string             // Which task was executed
+	Status    taskStatus         // Completion status
+	Response  string             // Agent's text response (if completed)
+	SessionID string             // Claude session ID (for debugging)
+	StartTime time.Time          // When execution began
+	EndTime   time.Time          // When execution finished
+	Duration  time.Duration      // EndTime - StartTime
+	Error     string             // Error message (if status is error)
+	Usage     metrics.Usage      // Tokens and cost reported by the CLI (if completed)
+	ToolCalls []metrics.ToolCall // Tool calls streamed by the CLI, up to the end or failure of the task
 }
 
 // c7EvaluationResult holds the complete C7 evaluation outcome.
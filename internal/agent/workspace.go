@@ -1,23 +1,28 @@
 package agent
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
 // CreateWorkspace creates an isolated directory for agent execution.
 // It attempts to use git worktree for efficient isolation. If the project
 // is not a git repository, it falls back to read-only mode using the original
 // directory (agent tasks use read-only tools, so this is safe).
+// ctx bounds creating the worktree; cleanup removes it even after ctx is done,
+// so a cancelled scan leaves no stale ars-c7-* worktrees behind.
 //
 // Returns:
 //   - workDir: the directory path for agent execution
 //   - cleanup: function to call when done (removes worktree if created)
 //   - err: error if workspace creation fails
-func CreateWorkspace(projectDir string) (workDir string, cleanup func(), err error) {
+func CreateWorkspace(ctx context.Context, projectDir string) (workDir string, cleanup func(), err error) {
 	// Create temp directory for worktree
 	worktreeDir, err := os.MkdirTemp("", "ars-c7-*")
 	if err != nil {
@@ -29,19 +34,23 @@ func CreateWorkspace(projectDir string) (workDir string, cleanup func(), err err
 	if _, statErr := os.Stat(gitDir); os.IsNotExist(statErr) {
 		// Not a git repo - fall back to read-only mode
 		os.RemoveAll(worktreeDir) // Clean up unused temp dir
-		log.Printf("[C7] Warning: %s is not a git repository, using read-only mode", projectDir)
+		reportWorkspace(ctx, "not a git repository, using read-only mode")
 		return projectDir, func() {}, nil
 	}
 
 	// Attempt to create git worktree
-	cmd := exec.Command("git", "worktree", "add", worktreeDir, "HEAD", "--detach")
+	cmd := exec.CommandContext(ctx, "git", "worktree", "add", worktreeDir, "HEAD", "--detach")
 	cmd.Dir = projectDir
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Worktree failed (maybe git issues) - fall back to read-only mode
 		os.RemoveAll(worktreeDir)
-		log.Printf("[C7] Warning: git worktree failed (%v), using read-only mode. Output: %s",
-			err, string(output))
+		if ctx.Err() != nil {
+			pruneWorktrees(projectDir) // drop a half-registered worktree
+			return "", nil, ctx.Err()
+		}
+		reportWorkspace(ctx, fmt.Sprintf("git worktree failed (%v), using read-only mode. Output: %s",
+			err, string(output)))
 		return projectDir, func() {}, nil
 	}
 
@@ -50,13 +59,33 @@ func CreateWorkspace(projectDir string) (workDir string, cleanup func(), err err
 		// Remove worktree from git
 		removeCmd := exec.Command("git", "worktree", "remove", worktreeDir, "--force")
 		removeCmd.Dir = projectDir
-		if err := removeCmd.Run(); err != nil {
+		removeErr := removeCmd.Run()
+		if removeErr != nil {
 			// If git worktree remove fails, try direct removal
-			log.Printf("[C7] Warning: git worktree remove failed: %v", err)
+			reportWorkspace(ctx, fmt.Sprintf("git worktree remove failed: %v", removeErr))
 		}
 		// Clean up the directory
 		os.RemoveAll(worktreeDir)
+		if removeErr != nil {
+			pruneWorktrees(projectDir)
+		}
 	}
 
 	return worktreeDir, cleanup, nil
 }
+
+// pruneWorktrees removes git's bookkeeping of worktrees whose directory is gone.
+func pruneWorktrees(projectDir string) {
+	cmd := exec.Command("git", "worktree", "prune")
+	cmd.Dir = projectDir
+	_ = cmd.Run()
+}
+
+// reportWorkspace records a C7 workspace problem as a warning diagnostic.
+func reportWorkspace(ctx context.Context, msg string) {
+	diagnostics.Report(ctx, types.Diagnostic{
+		Severity: types.SeverityWarning,
+		Analyzer: "C7",
+		Message:  msg,
+	})
+}
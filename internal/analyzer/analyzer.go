@@ -70,6 +70,6 @@ func NewC6Analyzer(tsParser *parser.TreeSitterParser) *C6Analyzer {
 }
 
 // NewC7Analyzer creates a C7 (Agent) analyzer.
-func NewC7Analyzer() *C7Analyzer {
-	return c7.NewC7Analyzer()
+func NewC7Analyzer(tsParser *parser.TreeSitterParser) *C7Analyzer {
+	return c7.NewC7Analyzer(tsParser)
 }
@@ -0,0 +1,192 @@
+package c1
+
+import (
+	"bytes"
+	"go/ast"
+
+	"github.com/fzipp/gocyclo"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// c1FactsVersion identifies the c1FileFacts format and the algorithms producing it.
+// Bump it whenever either changes so that stale cache entries are ignored.
+const c1FactsVersion = 1
+
+// dupWindow is a hashed statement window of one file (see collectBlockWindows).
+type dupWindow struct {
+	Hash      uint64 `json:"hash"`
+	StartLine int    `json:"start"`
+	EndLine   int    `json:"end"`
+}
+
+// c1FileFacts holds the C1 results that depend only on a single file's content.
+// Duplication is cross-file, so only the file's statement windows are kept;
+// matching happens after all files' facts are collected.
+type c1FileFacts struct {
+	Functions []types.FunctionMetric `json:"functions"`
+	Lines     int                    `json:"lines"`
+	Windows   []dupWindow            `json:"windows"`
+}
+
+// SetCache enables the per-file results cache. A nil store disables it.
+func (a *C1Analyzer) SetCache(store *cache.Store) {
+	a.cache = store
+}
+
+// fileFacts is one file's facts together with the path they are reported under.
+type fileFacts struct {
+	file string
+	c1FileFacts
+}
+
+// goFileFacts computes per-file facts for the source packages' syntax trees,
+// using contents (absolute path -> bytes) for cache keys where available.
+// Packages loaded without syntax contribute their cached facts.
+func (a *C1Analyzer) goFileFacts(pkgs []*parser.ParsedPackage, contents map[string][]byte) []fileFacts {
+	var result []fileFacts
+	for _, pkg := range pkgs {
+		files := shared.GoPackageFacts(a.cache, "c1-go", c1FactsVersion, pkg, contents, func(f *ast.File) c1FileFacts {
+			return goComputeFacts(pkg, f)
+		})
+		for _, ff := range files {
+			facts := ff.Facts
+			for i := range facts.Functions {
+				facts.Functions[i].Package = pkg.PkgPath
+				facts.Functions[i].File = ff.File
+			}
+			result = append(result, fileFacts{file: ff.File, c1FileFacts: facts})
+		}
+	}
+	return result
+}
+
+// GoResultsCached reports whether the C1 facts of every file of pkg are cached,
+// given the content of its files. Test packages are not analyzed by C1.
+func (a *C1Analyzer) GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	return pkg.ForTest != "" || shared.GoFactsCached(a.cache, "c1-go", c1FactsVersion, pkg, contents)
+}
+
+// goComputeFacts analyzes one parsed Go file.
+func goComputeFacts(pkg *parser.ParsedPackage, f *ast.File) c1FileFacts {
+	complexityMap := make(map[posKey]int)
+	for _, s := range gocyclo.AnalyzeASTFile(f, pkg.Fset, nil) {
+		complexityMap[posKey{s.Pos.Filename, s.Pos.Line}] = s.Complexity
+	}
+
+	var facts c1FileFacts
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				facts.Functions = append(facts.Functions, buildFunctionMetric(pkg, n, complexityMap))
+			}
+		case *ast.BlockStmt:
+			for _, seq := range collectBlockWindows(pkg, n, nil) {
+				facts.Windows = append(facts.Windows, dupWindow{Hash: seq.hash, StartLine: seq.startLine, EndLine: seq.endLine})
+			}
+		}
+		return true
+	})
+	facts.Lines = pkg.Fset.Position(f.End()).Line
+	return facts
+}
+
+// treeSitterFileFacts computes per-file facts for a Python or TypeScript target's
+// source files. Files whose facts are cached are not parsed at all.
+func (a *C1Analyzer) treeSitterFileFacts(target *types.AnalysisTarget) []fileFacts {
+	lang := target.Language
+	isTest, compute := isTestFileByPath, pyComputeFacts
+	if lang == types.LangTypeScript {
+		isTest, compute = tsIsTestFile, tsComputeFacts
+	}
+
+	var result []fileFacts
+	for _, sf := range target.Files {
+		if sf.Class != types.ClassSource && sf.Class != types.ClassTest {
+			continue
+		}
+		if isTest(sf.RelPath) {
+			continue
+		}
+		content, err := parser.SourceContent(sf)
+		if err != nil {
+			continue
+		}
+		facts, err := cache.Memo(a.cache, "c1-"+string(lang), c1FactsVersion, content, func() (c1FileFacts, error) {
+			pf, err := a.tsParser.ParseSource(lang, sf, content)
+			if err != nil {
+				return c1FileFacts{}, err
+			}
+			defer pf.Tree.Close()
+			return compute(pf), nil
+		})
+		if err != nil {
+			continue
+		}
+		for i := range facts.Functions {
+			facts.Functions[i].File = sf.RelPath
+		}
+		result = append(result, fileFacts{file: sf.RelPath, c1FileFacts: facts})
+	}
+	return result
+}
+
+// pyComputeFacts analyzes one parsed Python file.
+func pyComputeFacts(f *parser.ParsedTreeSitterFile) c1FileFacts {
+	facts := c1FileFacts{
+		Functions: pyAnalyzeFunctions([]*parser.ParsedTreeSitterFile{f}),
+		Lines:     bytes.Count(f.Content, []byte("\n")) + 1,
+	}
+	var seqs []pyDupSeq
+	pyCollectDupSequences(f.Tree.RootNode(), f.RelPath, f.Content, dupMinStatements, dupMinLines, &seqs)
+	for _, s := range seqs {
+		facts.Windows = append(facts.Windows, dupWindow{Hash: s.hash, StartLine: s.startLine, EndLine: s.endLine})
+	}
+	return facts
+}
+
+// tsComputeFacts analyzes one parsed TypeScript file.
+func tsComputeFacts(f *parser.ParsedTreeSitterFile) c1FileFacts {
+	facts := c1FileFacts{
+		Functions: tsAnalyzeFunctions([]*parser.ParsedTreeSitterFile{f}),
+		Lines:     bytes.Count(f.Content, []byte("\n")) + 1,
+	}
+	var seqs []tsDupSeq
+	tsCollectDupSequences(f.Tree.RootNode(), f.RelPath, f.Content, dupMinStatements, dupMinLines, &seqs)
+	for _, s := range seqs {
+		facts.Windows = append(facts.Windows, dupWindow{Hash: s.hash, StartLine: s.startLine, EndLine: s.endLine})
+	}
+	return facts
+}
+
+// accumulateFacts adds one language's per-file facts to the accumulator: functions,
+// file size summary, and duplicates matched across that language's files.
+func accumulateFacts(acc *c1Accumulator, files []fileFacts) {
+	if len(files) == 0 {
+		return
+	}
+	var sequences []stmtSeq
+	totalLines, maxLines, maxEntity := 0, 0, ""
+	for _, f := range files {
+		acc.functions = append(acc.functions, f.Functions...)
+		totalLines += f.Lines
+		if f.Lines > maxLines {
+			maxLines, maxEntity = f.Lines, f.file
+		}
+		for _, w := range f.Windows {
+			sequences = append(sequences, stmtSeq{hash: w.Hash, file: f.file, startLine: w.StartLine, endLine: w.EndLine})
+		}
+	}
+	acc.fileSizes = append(acc.fileSizes, types.MetricSummary{
+		Avg:       float64(totalLines) / float64(len(files)),
+		Max:       maxLines,
+		MaxEntity: maxEntity,
+	})
+
+	blocks, duplicatedLines := findDuplicatePairs(groupSequencesByHash(sequences))
+	accumulateDuplication(acc, blocks, computeDuplicationRate(duplicatedLines, totalLines))
+}
@@ -1,6 +1,7 @@
 package c1
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
 	"go/token"
@@ -10,8 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/fzipp/gocyclo"
 	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -31,6 +32,7 @@ const (
 type C1Analyzer struct {
 	pkgs     []*parser.ParsedPackage
 	tsParser *parser.TreeSitterParser
+	cache    *cache.Store // nil disables the per-file results cache
 }
 
 // NewC1Analyzer creates a C1Analyzer with Tree-sitter parser for multi-language analysis.
@@ -62,7 +64,7 @@ type c1Accumulator struct {
 
 // Analyze runs all 6 C1 sub-analyses on the given packages and returns
 // a combined AnalysisResult with Category "C1".
-func (a *C1Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (a *C1Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	metrics := &c1MetricsResult{
 		AfferentCoupling: make(map[string]int),
 		EfferentCoupling: make(map[string]int),
@@ -70,10 +72,13 @@ func (a *C1Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 	acc := &c1Accumulator{}
 
 	if a.pkgs != nil {
-		a.accumulateGo(acc, metrics)
+		a.accumulateGo(acc, metrics, shared.GoFileContents(targets))
 	}
 
 	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		a.accumulateTarget(target, acc)
 	}
 
@@ -87,19 +92,15 @@ func (a *C1Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 }
 
 // accumulateGo runs Go-specific C1 analysis and adds results to the accumulator.
-func (a *C1Analyzer) accumulateGo(acc *c1Accumulator, metrics *c1MetricsResult) {
-	goFunctions, goDuplicates, goDupRate, goFileSize, goCoupling := a.analyzeGoC1()
-	acc.functions = append(acc.functions, goFunctions...)
-	acc.duplicates = append(acc.duplicates, goDuplicates...)
-	if goDupRate > 0 {
-		acc.totalDupRate += goDupRate
-		acc.dupRateCount++
-	}
-	acc.fileSizes = append(acc.fileSizes, goFileSize)
-	for k, v := range goCoupling.afferent {
+// contents maps absolute file paths to content already read for the targets.
+func (a *C1Analyzer) accumulateGo(acc *c1Accumulator, metrics *c1MetricsResult, contents map[string][]byte) {
+	srcPkgs := a.goSourcePackages()
+	accumulateFacts(acc, a.goFileFacts(srcPkgs, contents))
+	coupling := analyzeGoCoupling(srcPkgs)
+	for k, v := range coupling.afferent {
 		metrics.AfferentCoupling[k] = v
 	}
-	for k, v := range goCoupling.efferent {
+	for k, v := range coupling.efferent {
 		metrics.EfferentCoupling[k] = v
 	}
 }
@@ -111,29 +112,8 @@ func (a *C1Analyzer) accumulateTarget(target *types.AnalysisTarget, acc *c1Accum
 	}
 
 	switch target.Language {
-	case types.LangPython:
-		parsed, err := a.tsParser.ParseTargetFiles(target)
-		if err != nil {
-			return
-		}
-		defer parser.CloseAll(parsed)
-		srcFiles := pyFilterSourceFiles(parsed)
-		acc.functions = append(acc.functions, pyAnalyzeFunctions(srcFiles)...)
-		acc.fileSizes = append(acc.fileSizes, pyAnalyzeFileSizes(srcFiles))
-		dups, rate := pyAnalyzeDuplication(srcFiles)
-		accumulateDuplication(acc, dups, rate)
-
-	case types.LangTypeScript:
-		parsed, err := a.tsParser.ParseTargetFiles(target)
-		if err != nil {
-			return
-		}
-		defer parser.CloseAll(parsed)
-		srcFiles := tsFilterSourceFiles(parsed)
-		acc.functions = append(acc.functions, tsAnalyzeFunctions(srcFiles)...)
-		acc.fileSizes = append(acc.fileSizes, tsAnalyzeFileSizes(srcFiles))
-		dups, rate := tsAnalyzeDuplication(srcFiles)
-		accumulateDuplication(acc, dups, rate)
+	case types.LangPython, types.LangTypeScript:
+		accumulateFacts(acc, a.treeSitterFileFacts(target))
 	}
 }
 
@@ -179,22 +159,20 @@ type goCouplingResult struct {
 	efferent map[string]int
 }
 
-// analyzeGoC1 runs Go-specific C1 analysis and returns its components.
-func (a *C1Analyzer) analyzeGoC1() ([]types.FunctionMetric, []types.DuplicateBlock, float64, types.MetricSummary, goCouplingResult) {
-	pkgs := a.pkgs
+// goSourcePackages returns the analyzer's Go packages, excluding test variants.
+func (a *C1Analyzer) goSourcePackages() []*parser.ParsedPackage {
 	var srcPkgs []*parser.ParsedPackage
-	for _, pkg := range pkgs {
+	for _, pkg := range a.pkgs {
 		if pkg.ForTest != "" {
 			continue
 		}
 		srcPkgs = append(srcPkgs, pkg)
 	}
+	return srcPkgs
+}
 
-	functions := analyzeFunctions(srcPkgs)
-	fileSize := analyzeFileSizes(srcPkgs)
-	duplicates, dupRate := analyzeDuplication(srcPkgs)
-
-	// Coupling
+// analyzeGoCoupling computes afferent and efferent coupling per source package.
+func analyzeGoCoupling(srcPkgs []*parser.ParsedPackage) goCouplingResult {
 	modulePath := detectModulePath(srcPkgs)
 	graph := shared.BuildImportGraph(srcPkgs, modulePath)
 	coupling := goCouplingResult{
@@ -205,23 +183,7 @@ func (a *C1Analyzer) analyzeGoC1() ([]types.FunctionMetric, []types.DuplicateBlo
 		coupling.afferent[pkg.PkgPath] = len(graph.Reverse[pkg.PkgPath])
 		coupling.efferent[pkg.PkgPath] = len(graph.Forward[pkg.PkgPath])
 	}
-
-	return functions, duplicates, dupRate, fileSize, coupling
-}
-
-// analyzeFunctions extracts per-function complexity and line count from all source packages.
-// Computes cyclomatic complexity for all functions using gocyclo.
-// Matches complexity results to function declarations by position (line number).
-// Minimum complexity is 1 (function with no branches has complexity=1).
-func analyzeFunctions(pkgs []*parser.ParsedPackage) []types.FunctionMetric {
-	var allFunctions []types.FunctionMetric
-
-	for _, pkg := range pkgs {
-		complexityMap := buildComplexityMap(pkg)
-		extractFunctionMetrics(pkg, complexityMap, &allFunctions)
-	}
-
-	return allFunctions
+	return coupling
 }
 
 // posKey represents a file and line number for complexity lookup.
@@ -230,36 +192,6 @@ type posKey struct {
 	line int
 }
 
-// buildComplexityMap computes complexity for all functions in a package.
-func buildComplexityMap(pkg *parser.ParsedPackage) map[posKey]int {
-	var stats gocyclo.Stats
-	for _, f := range pkg.Syntax {
-		stats = gocyclo.AnalyzeASTFile(f, pkg.Fset, stats)
-	}
-
-	complexityByPos := make(map[posKey]int)
-	for _, s := range stats {
-		complexityByPos[posKey{s.Pos.Filename, s.Pos.Line}] = s.Complexity
-	}
-	return complexityByPos
-}
-
-// extractFunctionMetrics walks the AST extracting function metrics.
-func extractFunctionMetrics(pkg *parser.ParsedPackage, complexityMap map[posKey]int, allFunctions *[]types.FunctionMetric) {
-	for _, f := range pkg.Syntax {
-		ast.Inspect(f, func(n ast.Node) bool {
-			fn, ok := n.(*ast.FuncDecl)
-			if !ok || fn.Body == nil {
-				return true
-			}
-
-			metric := buildFunctionMetric(pkg, fn, complexityMap)
-			*allFunctions = append(*allFunctions, metric)
-			return true
-		})
-	}
-}
-
 // buildFunctionMetric creates a FunctionMetric from a function declaration.
 func buildFunctionMetric(pkg *parser.ParsedPackage, fn *ast.FuncDecl, complexityMap map[posKey]int) types.FunctionMetric {
 	pos := pkg.Fset.Position(fn.Pos())
@@ -351,36 +283,6 @@ func computeFunctionLengthSummary(functions []types.FunctionMetric) types.Metric
 	}
 }
 
-// analyzeFileSizes measures lines per file across all source packages.
-func analyzeFileSizes(pkgs []*parser.ParsedPackage) types.MetricSummary {
-	var sum int
-	var count int
-	maxVal := 0
-	maxEntity := ""
-
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			lines := pkg.Fset.Position(f.End()).Line
-			sum += lines
-			count++
-			if lines > maxVal {
-				maxVal = lines
-				maxEntity = pkg.Fset.Position(f.Pos()).Filename
-			}
-		}
-	}
-
-	if count == 0 {
-		return types.MetricSummary{}
-	}
-
-	return types.MetricSummary{
-		Avg:       float64(sum) / float64(count),
-		Max:       maxVal,
-		MaxEntity: maxEntity,
-	}
-}
-
 // detectModulePath extracts the module path from go.mod in the package directory,
 // or infers it from the first package's import path.
 func detectModulePath(pkgs []*parser.ParsedPackage) string {
@@ -423,7 +325,7 @@ func detectModulePath(pkgs []*parser.ParsedPackage) string {
 	return ""
 }
 
-// analyzeDuplication detects duplicate code blocks using AST statement-sequence hashing.
+// Duplicate code blocks are detected using AST statement-sequence hashing.
 //
 // Algorithm approach:
 // - Sliding window over statement sequences within each block
@@ -434,7 +336,9 @@ func detectModulePath(pkgs []*parser.ParsedPackage) string {
 // - dupMinStatements=3: Reduces false positives from trivial assignments/returns
 // - dupMinLines=6: Focuses on substantial duplicated logic worth refactoring
 //
-// Returns the list of duplicate blocks and the duplication rate (% of lines duplicated).
+// Windows are collected per file (and cached, see c1FileFacts); matching runs across
+// all files of a language in accumulateFacts.
+
 // stmtSeq represents a hashed statement sequence with its source location.
 type stmtSeq struct {
 	hash      uint64
@@ -443,36 +347,6 @@ type stmtSeq struct {
 	endLine   int
 }
 
-// analyzeDuplication detects duplicate code blocks using AST statement-sequence hashing.
-func analyzeDuplication(pkgs []*parser.ParsedPackage) ([]types.DuplicateBlock, float64) {
-	sequences, totalLines := collectStatementSequences(pkgs)
-	groups := groupSequencesByHash(sequences)
-	blocks, duplicatedLines := findDuplicatePairs(groups)
-	rate := computeDuplicationRate(duplicatedLines, totalLines)
-	return blocks, rate
-}
-
-// collectStatementSequences extracts all hashed statement windows from packages.
-func collectStatementSequences(pkgs []*parser.ParsedPackage) ([]stmtSeq, int) {
-	var sequences []stmtSeq
-	totalLines := 0
-
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			totalLines += pkg.Fset.Position(f.End()).Line
-			ast.Inspect(f, func(n ast.Node) bool {
-				block, ok := n.(*ast.BlockStmt)
-				if !ok {
-					return true
-				}
-				sequences = collectBlockWindows(pkg, block, sequences)
-				return true
-			})
-		}
-	}
-	return sequences, totalLines
-}
-
 // collectBlockWindows generates sliding-window statement hashes for a single block.
 func collectBlockWindows(pkg *parser.ParsedPackage, block *ast.BlockStmt, sequences []stmtSeq) []stmtSeq {
 	for i := 0; i <= len(block.List)-dupMinStatements; i++ {
@@ -1,6 +1,7 @@
 package c1
 
 import (
+	"context"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -18,7 +19,7 @@ func testdataDir() string {
 func loadTestPackages(t *testing.T, subdir string) []*parser.ParsedPackage {
 	t.Helper()
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse(filepath.Join(testdataDir(), subdir))
+	pkgs, err := p.Parse(context.Background(), filepath.Join(testdataDir(), subdir))
 	if err != nil {
 		t.Fatalf("failed to parse %s: %v", subdir, err)
 	}
@@ -32,7 +33,7 @@ func TestC1_CyclomaticComplexity(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -81,7 +82,7 @@ func TestC1_FunctionLength(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -114,7 +115,7 @@ func TestC1_FileSize(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -137,7 +138,7 @@ func TestC1_AfferentCoupling(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -158,7 +159,7 @@ func TestC1_EfferentCoupling(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -185,7 +186,7 @@ func TestC1_Duplication(t *testing.T) {
 
 	analyzer := &C1Analyzer{}
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
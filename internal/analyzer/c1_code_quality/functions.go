@@ -0,0 +1,47 @@
+package c1
+
+import (
+	"path/filepath"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// FileFunctions returns the functions declared in each source file of the
+// targets, keyed by slash-separated path relative to the target root. It uses
+// the same walkers as C1Metrics.Functions; C7 checks agents' function listings
+// against it. tsParser may be nil to skip Python and TypeScript.
+func FileFunctions(pkgs []*parser.ParsedPackage, tsParser *parser.TreeSitterParser, targets []*types.AnalysisTarget) map[string][]types.FunctionMetric {
+	a := &C1Analyzer{pkgs: pkgs, tsParser: tsParser}
+	functions := make(map[string][]types.FunctionMetric)
+
+	for _, target := range targets {
+		var files []fileFacts
+		switch target.Language {
+		case types.LangGo:
+			if pkgs == nil {
+				continue
+			}
+			files = a.goFileFacts(a.goSourcePackages(), shared.GoFileContents([]*types.AnalysisTarget{target}))
+		case types.LangPython, types.LangTypeScript:
+			if tsParser == nil {
+				continue
+			}
+			files = a.treeSitterFileFacts(target)
+		}
+		for _, f := range files {
+			rel := f.file
+			if filepath.IsAbs(rel) {
+				r, err := filepath.Rel(target.RootDir, rel)
+				if err != nil || !filepath.IsLocal(r) {
+					continue
+				}
+				rel = r
+			}
+			rel = filepath.ToSlash(rel)
+			functions[rel] = append(functions[rel], f.Functions...)
+		}
+	}
+	return functions
+}
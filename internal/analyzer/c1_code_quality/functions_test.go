@@ -0,0 +1,49 @@
+package c1
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestFileFunctions(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	pkgs := loadTestPackages(t, "coupling")
+	goTarget := &types.AnalysisTarget{Language: types.LangGo, RootDir: filepath.Join(testdataDir(), "coupling")}
+	pyDir := filepath.Join(testdataDir(), "valid-python-project")
+	pyTarget := &types.AnalysisTarget{
+		Language: types.LangPython,
+		RootDir:  pyDir,
+		Files: []types.SourceFile{{
+			Path: filepath.Join(pyDir, "utils.py"), RelPath: "utils.py", Language: types.LangPython, Class: types.ClassSource,
+		}},
+	}
+
+	functions := FileFunctions(pkgs, tsParser, []*types.AnalysisTarget{goTarget, pyTarget})
+
+	names := func(file string) []string {
+		var n []string
+		for _, fn := range functions[file] {
+			n = append(n, fn.Name)
+		}
+		return n
+	}
+	if got := names("pkga/a.go"); !reflect.DeepEqual(got, []string{"UseB"}) {
+		t.Errorf("pkga/a.go functions = %v, want [UseB]", got)
+	}
+	want := []string{
+		"DataProcessor.__init__", "DataProcessor.process_record", "DataProcessor.validate",
+		"simple_add", "load_config", "format_output", "find_duplicates",
+	}
+	if got := names("utils.py"); !reflect.DeepEqual(got, want) {
+		t.Errorf("utils.py functions = %v, want %v", got, want)
+	}
+}
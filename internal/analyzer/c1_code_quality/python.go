@@ -20,7 +20,6 @@ import (
 // Constants for Python C1 metrics computation.
 const (
 	p90PercentilePy     = 0.9
-	toPercentC1Py       = 100.0
 	maxHashNodeDepthPy  = 5
 	maxHashNodeChildPy  = 10
 )
@@ -262,118 +261,6 @@ type pyDupSeq struct {
 	endLine   int
 }
 
-// pyAnalyzeDuplication detects duplicate code blocks in Python using statement-sequence hashing.
-//
-// Python-specific approach:
-// - Uses Tree-sitter AST to identify statement sequences within blocks
-// - Applies sliding window over statements in function/class bodies
-// - Normalizes variable names via structural hashing to detect renamed copies
-// - Thresholds: dupMinStatements=3, dupMinLines=6 (same as Go analysis for consistency)
-//
-// Returns duplicate blocks and duplication rate (% of lines duplicated).
-func pyAnalyzeDuplication(files []*parser.ParsedTreeSitterFile) ([]types.DuplicateBlock, float64) {
-	sequences, totalLines := pyCollectAllDupSequences(files)
-	groups := pyGroupSequencesByHash(sequences)
-	blocks, duplicatedLines := pyFindDuplicateBlocks(groups)
-	rate := pyCalculateDuplicationRate(duplicatedLines, totalLines)
-
-	return blocks, rate
-}
-
-// pyCollectAllDupSequences collects duplicate sequences from all files.
-func pyCollectAllDupSequences(files []*parser.ParsedTreeSitterFile) ([]pyDupSeq, int) {
-	var sequences []pyDupSeq
-	totalLines := 0
-
-	for _, f := range files {
-		totalLines += bytes.Count(f.Content, []byte("\n")) + 1
-		root := f.Tree.RootNode()
-		pyCollectDupSequences(root, f.RelPath, f.Content, dupMinStatements, dupMinLines, &sequences)
-	}
-
-	return sequences, totalLines
-}
-
-// pyGroupSequencesByHash groups sequences by their hash value.
-func pyGroupSequencesByHash(sequences []pyDupSeq) map[uint64][]pyDupSeq {
-	groups := make(map[uint64][]pyDupSeq)
-	for _, seq := range sequences {
-		groups[seq.hash] = append(groups[seq.hash], seq)
-	}
-	return groups
-}
-
-// pyFindDuplicateBlocks finds duplicate blocks from grouped sequences.
-func pyFindDuplicateBlocks(groups map[uint64][]pyDupSeq) ([]types.DuplicateBlock, map[string]map[int]bool) {
-	var blocks []types.DuplicateBlock
-	duplicatedLines := make(map[string]map[int]bool)
-
-	for _, group := range groups {
-		if len(group) < 2 {
-			continue
-		}
-
-		pyProcessDuplicateGroup(group, &blocks, duplicatedLines)
-	}
-
-	return blocks, duplicatedLines
-}
-
-// pyProcessDuplicateGroup processes a group of duplicate sequences.
-func pyProcessDuplicateGroup(group []pyDupSeq, blocks *[]types.DuplicateBlock, duplicatedLines map[string]map[int]bool) {
-	for i := 0; i < len(group); i++ {
-		for j := i + 1; j < len(group); j++ {
-			a, b := group[i], group[j]
-
-			if pySequencesOverlap(a, b) {
-				continue
-			}
-
-			*blocks = append(*blocks, types.DuplicateBlock{
-				FileA:     a.file,
-				StartA:    a.startLine,
-				EndA:      a.endLine,
-				FileB:     b.file,
-				StartB:    b.startLine,
-				EndB:      b.endLine,
-				LineCount: a.endLine - a.startLine + 1,
-			})
-
-			pyMarkDuplicatedLines([]pyDupSeq{a, b}, duplicatedLines)
-		}
-	}
-}
-
-// pySequencesOverlap checks if two sequences overlap in the same file.
-func pySequencesOverlap(a, b pyDupSeq) bool {
-	return a.file == b.file && a.startLine < b.endLine && b.startLine < a.endLine
-}
-
-// pyMarkDuplicatedLines marks lines as duplicated in the tracking map.
-func pyMarkDuplicatedLines(seqs []pyDupSeq, duplicatedLines map[string]map[int]bool) {
-	for _, s := range seqs {
-		if duplicatedLines[s.file] == nil {
-			duplicatedLines[s.file] = make(map[int]bool)
-		}
-		for l := s.startLine; l <= s.endLine; l++ {
-			duplicatedLines[s.file][l] = true
-		}
-	}
-}
-
-// pyCalculateDuplicationRate calculates the duplication rate from duplicated lines.
-func pyCalculateDuplicationRate(duplicatedLines map[string]map[int]bool, totalLines int) float64 {
-	dupLineCount := 0
-	for _, lines := range duplicatedLines {
-		dupLineCount += len(lines)
-	}
-
-	if totalLines > 0 {
-		return float64(dupLineCount) / float64(totalLines) * toPercentC1Py
-	}
-	return 0
-}
-
 // pyCollectDupSequences walks the AST collecting hashed statement sequences from block nodes.
 //
 // Sliding window approach:
@@ -1,9 +1,12 @@
 package c1
 
 import (
+	"context"
 	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -220,7 +223,7 @@ func TestPyC1Integration(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
@@ -276,7 +279,7 @@ func TestC1_GoRegressionWithNewConstructor(t *testing.T) {
 
 	analyzer := NewC1Analyzer(nil) // nil tsParser is fine for Go-only
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -298,3 +301,47 @@ func TestC1_GoRegressionWithNewConstructor(t *testing.T) {
 		t.Errorf("MultiBranch complexity = %d, want >= 6", c)
 	}
 }
+
+func TestPyC1_CachedResultsMatch(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	testDir, _ := filepath.Abs("../../../testdata/valid-python-project")
+	targets := []*types.AnalysisTarget{{
+		Language: types.LangPython,
+		RootDir:  testDir,
+		Files: []types.SourceFile{
+			{Path: filepath.Join(testDir, "utils.py"), RelPath: "utils.py", Language: types.LangPython, Class: types.ClassSource},
+			{Path: filepath.Join(testDir, "app.py"), RelPath: "app.py", Language: types.LangPython, Class: types.ClassSource},
+		},
+	}}
+
+	analyze := func(store *cache.Store) *c1MetricsResult {
+		t.Helper()
+		a := NewC1Analyzer(tsParser)
+		a.SetCache(store)
+		result, err := a.Analyze(context.Background(), targets)
+		if err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+		return result.Metrics["c1"].(*c1MetricsResult)
+	}
+
+	uncached := analyze(nil)
+	store := cache.Open(t.TempDir(), "")
+	cold := analyze(store)
+	warm := analyze(store)
+
+	for name, got := range map[string]*c1MetricsResult{"cold": cold, "warm": warm} {
+		if !reflect.DeepEqual(got.Functions, uncached.Functions) {
+			t.Errorf("%s cache: Functions = %+v, want %+v", name, got.Functions, uncached.Functions)
+		}
+		if got.FileSize != uncached.FileSize || got.DuplicationRate != uncached.DuplicationRate {
+			t.Errorf("%s cache: FileSize/DuplicationRate = %+v/%v, want %+v/%v",
+				name, got.FileSize, got.DuplicationRate, uncached.FileSize, uncached.DuplicationRate)
+		}
+	}
+}
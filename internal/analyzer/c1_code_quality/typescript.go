@@ -20,7 +20,6 @@ import (
 // Constants for TypeScript C1 metrics computation.
 const (
 	p90PercentileTS      = 0.9
-	toPercentC1TS        = 100.0
 	maxHashNodeDepth     = 5
 	maxHashNodeChildren  = 10
 )
@@ -324,124 +323,6 @@ type tsDupSeq struct {
 	endLine   int
 }
 
-// tsAnalyzeDuplication detects duplicate code blocks in TypeScript using statement-sequence hashing.
-//
-// TypeScript-specific approach:
-// - Uses Tree-sitter AST to identify statement sequences within blocks
-// - Applies sliding window over statements in function/class bodies and statement blocks
-// - Normalizes variable names via structural hashing to detect renamed copies
-// - Thresholds: dupMinStatements=3, dupMinLines=6 (same as Go/Python for consistency)
-//
-// Returns duplicate blocks and duplication rate (% of lines duplicated).
-//
-// Why duplication matters for agents: When agents modify duplicated code, they
-// must find and update ALL copies to maintain consistency. Missing even one copy
-// creates subtle bugs where behavior diverges. High duplication (>10%) dramatically
-// increases agent error rates because the "find all copies" step often fails.
-// Agents lack the contextual memory to reliably track duplicates across files.
-func tsAnalyzeDuplication(files []*parser.ParsedTreeSitterFile) ([]types.DuplicateBlock, float64) {
-	sequences, totalLines := tsCollectAllDupSequences(files)
-	groups := tsGroupSequencesByHash(sequences)
-	blocks, duplicatedLines := tsFindDuplicateBlocks(groups)
-	rate := tsCalculateDuplicationRate(duplicatedLines, totalLines)
-
-	return blocks, rate
-}
-
-// tsCollectAllDupSequences collects duplicate sequences from all files.
-func tsCollectAllDupSequences(files []*parser.ParsedTreeSitterFile) ([]tsDupSeq, int) {
-	var sequences []tsDupSeq
-	totalLines := 0
-
-	for _, f := range files {
-		totalLines += bytes.Count(f.Content, []byte("\n")) + 1
-		root := f.Tree.RootNode()
-		tsCollectDupSequences(root, f.RelPath, f.Content, dupMinStatements, dupMinLines, &sequences)
-	}
-
-	return sequences, totalLines
-}
-
-// tsGroupSequencesByHash groups sequences by their hash value.
-func tsGroupSequencesByHash(sequences []tsDupSeq) map[uint64][]tsDupSeq {
-	groups := make(map[uint64][]tsDupSeq)
-	for _, seq := range sequences {
-		groups[seq.hash] = append(groups[seq.hash], seq)
-	}
-	return groups
-}
-
-// tsFindDuplicateBlocks finds duplicate blocks from grouped sequences.
-func tsFindDuplicateBlocks(groups map[uint64][]tsDupSeq) ([]types.DuplicateBlock, map[string]map[int]bool) {
-	var blocks []types.DuplicateBlock
-	duplicatedLines := make(map[string]map[int]bool)
-
-	for _, group := range groups {
-		if len(group) < 2 {
-			continue
-		}
-
-		tsProcessDuplicateGroup(group, &blocks, duplicatedLines)
-	}
-
-	return blocks, duplicatedLines
-}
-
-// tsProcessDuplicateGroup processes a group of duplicate sequences.
-func tsProcessDuplicateGroup(group []tsDupSeq, blocks *[]types.DuplicateBlock, duplicatedLines map[string]map[int]bool) {
-	for i := 0; i < len(group); i++ {
-		for j := i + 1; j < len(group); j++ {
-			a, b := group[i], group[j]
-
-			if tsSequencesOverlap(a, b) {
-				continue
-			}
-
-			*blocks = append(*blocks, types.DuplicateBlock{
-				FileA:     a.file,
-				StartA:    a.startLine,
-				EndA:      a.endLine,
-				FileB:     b.file,
-				StartB:    b.startLine,
-				EndB:      b.endLine,
-				LineCount: a.endLine - a.startLine + 1,
-			})
-
-			tsMarkDuplicatedLines([]tsDupSeq{a, b}, duplicatedLines)
-		}
-	}
-}
-
-// tsSequencesOverlap checks if two sequences overlap in the same file.
-func tsSequencesOverlap(a, b tsDupSeq) bool {
-	return a.file == b.file && a.startLine < b.endLine && b.startLine < a.endLine
-}
-
-// tsMarkDuplicatedLines marks lines as duplicated in the tracking map.
-func tsMarkDuplicatedLines(seqs []tsDupSeq, duplicatedLines map[string]map[int]bool) {
-	for _, s := range seqs {
-		if duplicatedLines[s.file] == nil {
-			duplicatedLines[s.file] = make(map[int]bool)
-		}
-		for l := s.startLine; l <= s.endLine; l++ {
-			duplicatedLines[s.file][l] = true
-		}
-	}
-}
-
-// tsCalculateDuplicationRate calculates the duplication rate from duplicated lines.
-func tsCalculateDuplicationRate(duplicatedLines map[string]map[int]bool, totalLines int) float64 {
-	dupLineCount := 0
-	for _, lines := range duplicatedLines {
-		dupLineCount += len(lines)
-	}
-
-	if totalLines > 0 {
-		return float64(dupLineCount) / float64(totalLines) * toPercentC1TS
-	}
-	return 0
-}
-
 // tsCollectDupSequences walks the AST collecting hashed statement sequences from statement_block nodes.
 func tsCollectDupSequences(node *tree_sitter.Node, file string, content []byte, minStmts, minLines int, seqs *[]tsDupSeq) {
 	if node == nil {
@@ -1,6 +1,7 @@
 package c1
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
@@ -234,7 +235,7 @@ func TestTsC1Integration(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
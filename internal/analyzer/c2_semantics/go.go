@@ -6,6 +6,8 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -19,7 +21,26 @@ const (
 
 // c2GoAnalyzer computes C2 (Semantic Explicitness) metrics for Go code using go/ast.
 type c2GoAnalyzer struct {
-	pkgs []*parser.ParsedPackage
+	pkgs  []*parser.ParsedPackage
+	cache *cache.Store // nil disables the per-file results cache
+}
+
+// c2FactsVersion identifies the c2GoFileFacts format and the algorithms producing
+// it. Bump it whenever either changes so that stale cache entries are ignored.
+const c2FactsVersion = 1
+
+// c2GoFileFacts are the C2 counts of a single Go file. The metrics are ratios
+// of their sums over all source files.
+type c2GoFileFacts struct {
+	LOC             int `json:"loc"`
+	Functions       int `json:"functions"`
+	TypeRefs        int `json:"type_refs"`
+	AnyRefs         int `json:"any_refs"`
+	Identifiers     int `json:"identifiers"`
+	ConsistentNames int `json:"consistent_names"`
+	MagicNumbers    int `json:"magic_numbers"`
+	PointerUsages   int `json:"pointer_usages"`
+	CheckedUsages   int `json:"checked_usages"`
 }
 
 // Analyze computes C2 metrics for a Go AnalysisTarget.
@@ -46,109 +67,131 @@ func (a *c2GoAnalyzer) Analyze(target *types.AnalysisTarget) (*types.C2LanguageM
 		TypeStrictness:         1,
 	}
 
-	// Count total LOC
+	var sum c2GoFileFacts
+	contents := shared.GoFileContents([]*types.AnalysisTarget{target})
 	for _, pkg := range srcPkgs {
-		for _, f := range pkg.Syntax {
-			metrics.LOC += pkg.Fset.Position(f.End()).Line
+		files := shared.GoPackageFacts(a.cache, "c2-go", c2FactsVersion, pkg, contents, func(f *ast.File) c2GoFileFacts {
+			return goComputeFacts(pkg.Fset, f)
+		})
+		for _, ff := range files {
+			sum.add(ff.Facts)
 		}
 	}
+	metrics.LOC = sum.LOC
+	metrics.TotalFunctions = sum.Functions
 
 	// C2-GO-01: interface{}/any usage rate (NullSafety metric)
-	anyUsage := analyzeAnyUsage(srcPkgs)
-	metrics.NullSafety = anyUsage.safetyPercent
+	anySafety := anySafetyPercent(sum.TypeRefs, sum.AnyRefs)
+	metrics.NullSafety = anySafety
 
 	// C2-GO-02: Naming consistency
-	naming := analyzeNamingConsistency(srcPkgs)
-	metrics.NamingConsistency = naming.consistencyPercent
-	metrics.TotalIdentifiers = naming.totalChecked
+	metrics.NamingConsistency = computeConsistencyPercent(sum.ConsistentNames, sum.Identifiers)
+	metrics.TotalIdentifiers = sum.Identifiers
 
 	// C2-GO-03: Magic numbers
-	magic := analyzeMagicNumbers(srcPkgs)
-	metrics.MagicNumberCount = magic.count
+	metrics.MagicNumberCount = sum.MagicNumbers
 	if metrics.LOC > 0 {
-		metrics.MagicNumberRatio = float64(magic.count) / float64(metrics.LOC) * toPerKLOCGo
+		metrics.MagicNumberRatio = float64(sum.MagicNumbers) / float64(metrics.LOC) * toPerKLOCGo
 	}
 
 	// C2-GO-04: Nil safety patterns
-	nilSafety := analyzeNilSafety(srcPkgs)
 	// Blend interface{}/any safety with nil safety (equal weight)
-	if nilSafety.totalPointerUsages > 0 {
-		nilSafetyPercent := float64(nilSafety.checkedUsages) / float64(nilSafety.totalPointerUsages) * toPercentGo
+	if sum.PointerUsages > 0 {
+		nilSafetyPercent := float64(sum.CheckedUsages) / float64(sum.PointerUsages) * toPercentGo
 		if nilSafetyPercent > toPercentGo {
 			nilSafetyPercent = toPercentGo
 		}
 		// Average of any-safety and nil-safety
-		metrics.NullSafety = (anyUsage.safetyPercent + nilSafetyPercent) / 2
-	}
-
-	// Count total functions
-	for _, pkg := range srcPkgs {
-		for _, f := range pkg.Syntax {
-			ast.Inspect(f, func(n ast.Node) bool {
-				if _, ok := n.(*ast.FuncDecl); ok {
-					metrics.TotalFunctions++
-				}
-				return true
-			})
-		}
+		metrics.NullSafety = (anySafety + nilSafetyPercent) / 2
 	}
 
 	return metrics, nil
 }
 
+// add adds the counts of another file.
+func (f *c2GoFileFacts) add(o c2GoFileFacts) {
+	f.LOC += o.LOC
+	f.Functions += o.Functions
+	f.TypeRefs += o.TypeRefs
+	f.AnyRefs += o.AnyRefs
+	f.Identifiers += o.Identifiers
+	f.ConsistentNames += o.ConsistentNames
+	f.MagicNumbers += o.MagicNumbers
+	f.PointerUsages += o.PointerUsages
+	f.CheckedUsages += o.CheckedUsages
+}
+
+// goComputeFacts analyzes one parsed Go file.
+func goComputeFacts(fset *token.FileSet, f *ast.File) c2GoFileFacts {
+	anyUsage := analyzeAnyUsage(f)
+	naming := analyzeNamingConsistency(f)
+	nilSafety := analyzeNilSafety(f)
+	facts := c2GoFileFacts{
+		LOC:             fset.Position(f.End()).Line,
+		TypeRefs:        anyUsage.totalTypeRefs,
+		AnyRefs:         anyUsage.anyRefs,
+		Identifiers:     naming.totalChecked,
+		ConsistentNames: naming.consistent,
+		MagicNumbers:    analyzeMagicNumbers(f).count,
+		PointerUsages:   nilSafety.totalPointerUsages,
+		CheckedUsages:   nilSafety.checkedUsages,
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncDecl); ok {
+			facts.Functions++
+		}
+		return true
+	})
+	return facts
+}
+
 // anyUsageResult holds interface{}/any usage analysis results.
 type anyUsageResult struct {
 	totalTypeRefs int
 	anyRefs       int
-	safetyPercent float64 // 100 - anyUsagePercent
 }
 
-// analyzeAnyUsage counts interface{}/any usage across all source packages.
-func analyzeAnyUsage(pkgs []*parser.ParsedPackage) anyUsageResult {
+// analyzeAnyUsage counts interface{}/any usage in a file.
+func analyzeAnyUsage(f *ast.File) anyUsageResult {
 	var result anyUsageResult
 
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			ast.Inspect(f, func(n ast.Node) bool {
-				switch node := n.(type) {
-				case *ast.InterfaceType:
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.InterfaceType:
+			result.totalTypeRefs++
+			// Empty interface: Methods.List is nil or empty
+			if node.Methods == nil || len(node.Methods.List) == 0 {
+				result.anyRefs++
+			}
+		case *ast.Ident:
+			if node.Name == "any" {
+				// Check if this is the builtin 'any' type (not a local variable named 'any')
+				if node.Obj == nil {
 					result.totalTypeRefs++
-					// Empty interface: Methods.List is nil or empty
-					if node.Methods == nil || len(node.Methods.List) == 0 {
-						result.anyRefs++
-					}
-				case *ast.Ident:
-					if node.Name == "any" {
-						// Check if this is the builtin 'any' type (not a local variable named 'any')
-						if node.Obj == nil {
-							result.totalTypeRefs++
-							result.anyRefs++
-						}
-					}
+					result.anyRefs++
 				}
-				return true
-			})
-		}
-	}
-
-	if result.totalTypeRefs == 0 {
-		result.safetyPercent = toPercentGo // No type refs means no unsafe usage
-	} else {
-		anyPercent := float64(result.anyRefs) / float64(result.totalTypeRefs) * toPercentGo
-		result.safetyPercent = toPercentGo - anyPercent
-		if result.safetyPercent < 0 {
-			result.safetyPercent = 0
+			}
 		}
-	}
+		return true
+	})
 
 	return result
 }
 
+// anySafetyPercent returns 100 minus the percentage of type references that
+// are interface{}/any.
+func anySafetyPercent(totalTypeRefs, anyRefs int) float64 {
+	if totalTypeRefs == 0 {
+		return toPercentGo // No type refs means no unsafe usage
+	}
+	anyPercent := float64(anyRefs) / float64(totalTypeRefs) * toPercentGo
+	return max(toPercentGo-anyPercent, 0)
+}
+
 // namingResult holds naming consistency analysis results.
 type namingResult struct {
-	totalChecked       int
-	consistent         int
-	consistencyPercent float64
+	totalChecked int
+	consistent   int
 }
 
 // commonAcronyms are uppercase abbreviations that are valid in Go identifiers.
@@ -162,20 +205,15 @@ var commonAcronyms = map[string]bool{
 	"IO": true, "DB": true, "UI": true, "OK": true,
 }
 
-// analyzeNamingConsistency checks Go naming conventions.
-func analyzeNamingConsistency(pkgs []*parser.ParsedPackage) namingResult {
+// analyzeNamingConsistency checks Go naming conventions in a file.
+func analyzeNamingConsistency(f *ast.File) namingResult {
 	var result namingResult
 
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			ast.Inspect(f, func(n ast.Node) bool {
-				checkNamingConsistencyForNode(n, &result)
-				return true
-			})
-		}
-	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		checkNamingConsistencyForNode(n, &result)
+		return true
+	})
 
-	result.consistencyPercent = computeConsistencyPercent(result.consistent, result.totalChecked)
 	return result
 }
 
@@ -298,37 +336,33 @@ type magicNumberResult struct {
 	count int
 }
 
-// analyzeMagicNumbers counts magic numbers outside const blocks.
-func analyzeMagicNumbers(pkgs []*parser.ParsedPackage) magicNumberResult {
+// analyzeMagicNumbers counts magic numbers outside const blocks in a file.
+func analyzeMagicNumbers(f *ast.File) magicNumberResult {
 	var result magicNumberResult
 
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			ast.Inspect(f, func(n ast.Node) bool {
-				// Skip const declarations
-				if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.CONST {
-					return false // Don't walk into const blocks
-				}
-
-				lit, ok := n.(*ast.BasicLit)
-				if !ok {
-					return true
-				}
+	ast.Inspect(f, func(n ast.Node) bool {
+		// Skip const declarations
+		if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.CONST {
+			return false // Don't walk into const blocks
+		}
 
-				if lit.Kind != token.INT && lit.Kind != token.FLOAT {
-					return true
-				}
+		lit, ok := n.(*ast.BasicLit)
+		if !ok {
+			return true
+		}
 
-				// Exclude 0, 1, -1
-				if isCommonNumericLiteral(lit.Value) {
-					return true
-				}
+		if lit.Kind != token.INT && lit.Kind != token.FLOAT {
+			return true
+		}
 
-				result.count++
-				return true
-			})
+		// Exclude 0, 1, -1
+		if isCommonNumericLiteral(lit.Value) {
+			return true
 		}
-	}
+
+		result.count++
+		return true
+	})
 
 	return result
 }
@@ -348,53 +382,49 @@ type nilSafetyResult struct {
 	checkedUsages      int
 }
 
-// analyzeNilSafety computes a simple nil-check-to-pointer-usage ratio.
-func analyzeNilSafety(pkgs []*parser.ParsedPackage) nilSafetyResult {
+// analyzeNilSafety counts a file's pointer usages and the nil checks covering them.
+func analyzeNilSafety(f *ast.File) nilSafetyResult {
 	var result nilSafetyResult
 
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			// Count nil checks and pointer dereferences per function
-			ast.Inspect(f, func(n ast.Node) bool {
-				fn, ok := n.(*ast.FuncDecl)
-				if !ok || fn.Body == nil {
-					return true
-				}
+	// Count nil checks and pointer dereferences per function
+	ast.Inspect(f, func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			return true
+		}
 
-				var nilChecks int
-				var pointerUsages int
-
-				ast.Inspect(fn.Body, func(inner ast.Node) bool {
-					switch node := inner.(type) {
-					case *ast.BinaryExpr:
-						// Count nil comparisons (x == nil or x != nil)
-						if (node.Op == token.EQL || node.Op == token.NEQ) && isNilIdent(node.Y) {
-							nilChecks++
-						}
-						if (node.Op == token.EQL || node.Op == token.NEQ) && isNilIdent(node.X) {
-							nilChecks++
-						}
-					case *ast.StarExpr:
-						// Pointer dereference (excluding type expressions)
-						if _, isType := node.X.(*ast.Ident); isType {
-							pointerUsages++
-						}
-					}
-					return true
-				})
-
-				result.totalPointerUsages += pointerUsages
-				// Each nil check "covers" one pointer usage
-				covered := nilChecks
-				if covered > pointerUsages {
-					covered = pointerUsages
-				}
-				result.checkedUsages += covered
+		var nilChecks int
+		var pointerUsages int
 
-				return false // Don't recurse into nested functions from here
-			})
+		ast.Inspect(fn.Body, func(inner ast.Node) bool {
+			switch node := inner.(type) {
+			case *ast.BinaryExpr:
+				// Count nil comparisons (x == nil or x != nil)
+				if (node.Op == token.EQL || node.Op == token.NEQ) && isNilIdent(node.Y) {
+					nilChecks++
+				}
+				if (node.Op == token.EQL || node.Op == token.NEQ) && isNilIdent(node.X) {
+					nilChecks++
+				}
+			case *ast.StarExpr:
+				// Pointer dereference (excluding type expressions)
+				if _, isType := node.X.(*ast.Ident); isType {
+					pointerUsages++
+				}
+			}
+			return true
+		})
+
+		result.totalPointerUsages += pointerUsages
+		// Each nil check "covers" one pointer usage
+		covered := nilChecks
+		if covered > pointerUsages {
+			covered = pointerUsages
 		}
-	}
+		result.checkedUsages += covered
+
+		return false // Don't recurse into nested functions from here
+	})
 
 	return result
 }
@@ -1,6 +1,7 @@
 package c2
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
@@ -10,7 +11,7 @@ import (
 func TestC2GoAnalyzer_SelfAnalysis(t *testing.T) {
 	// Parse this project's own codebase for C2 analysis
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse("../../../")
+	pkgs, err := p.Parse(context.Background(), "../../../")
 	if err != nil {
 		t.Fatalf("failed to parse project: %v", err)
 	}
@@ -26,7 +27,7 @@ func TestC2GoAnalyzer_SelfAnalysis(t *testing.T) {
 
 	metrics, err := analyzer.Analyze(target)
 	if err != nil {
-		t.Fatalf("c2GoAnalyzer.Analyze() error: %v", err)
+		t.Fatalf("c2GoAnalyzer.Analyze(context.Background(), ) error: %v", err)
 	}
 
 	// Go is statically typed: TypeAnnotationCoverage must be 100
@@ -81,7 +82,7 @@ func TestC2GoAnalyzer_SelfAnalysis(t *testing.T) {
 
 func TestC2Analyzer_GoTarget(t *testing.T) {
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse("../../../")
+	pkgs, err := p.Parse(context.Background(), "../../../")
 	if err != nil {
 		t.Fatalf("failed to parse project: %v", err)
 	}
@@ -96,9 +97,9 @@ func TestC2Analyzer_GoTarget(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
-		t.Fatalf("C2Analyzer.Analyze() error: %v", err)
+		t.Fatalf("C2Analyzer.Analyze(context.Background(), ) error: %v", err)
 	}
 
 	if result.Category != "C2" {
@@ -136,9 +137,9 @@ func TestC2Analyzer_GoTarget(t *testing.T) {
 func TestC2Analyzer_EmptyTargets(t *testing.T) {
 	analyzer := &C2Analyzer{}
 
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
-		t.Fatalf("C2Analyzer.Analyze(nil) error: %v", err)
+		t.Fatalf("C2Analyzer.Analyze(context.Background(), nil) error: %v", err)
 	}
 
 	if result.Category != "C2" {
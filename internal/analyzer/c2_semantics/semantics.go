@@ -1,6 +1,10 @@
 package c2
 
 import (
+	"context"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -12,6 +16,7 @@ type C2Analyzer struct {
 	goAnalyzer *c2GoAnalyzer
 	pyAnalyzer *c2PythonAnalyzer
 	tsAnalyzer *c2TypeScriptAnalyzer
+	cache      *cache.Store // nil disables the per-file results cache
 }
 
 // NewC2Analyzer creates a C2Analyzer with Tree-sitter-based language analyzers.
@@ -33,20 +38,38 @@ func (a *C2Analyzer) Name() string {
 // SetGoPackages stores Go-specific parsed packages for use during Analyze.
 func (a *C2Analyzer) SetGoPackages(pkgs []*parser.ParsedPackage) {
 	if a.goAnalyzer == nil {
-		a.goAnalyzer = &c2GoAnalyzer{}
+		a.goAnalyzer = &c2GoAnalyzer{cache: a.cache}
 	}
 	a.goAnalyzer.pkgs = pkgs
 }
 
+// SetCache enables the per-file results cache for Go files. A nil store
+// disables it.
+func (a *C2Analyzer) SetCache(store *cache.Store) {
+	a.cache = store
+	if a.goAnalyzer != nil {
+		a.goAnalyzer.cache = store
+	}
+}
+
+// GoResultsCached reports whether the C2 facts of every file of pkg are cached,
+// given the content of its files. Test packages are not analyzed by C2.
+func (a *C2Analyzer) GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	return pkg.ForTest != "" || shared.GoFactsCached(a.cache, "c2-go", c2FactsVersion, pkg, contents)
+}
+
 // Analyze runs C2 analysis on the given analysis targets.
 // It dispatches to the appropriate language-specific analyzer for each target,
 // then aggregates per-language results weighted by LOC.
-func (a *C2Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (a *C2Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	metrics := &types.C2Metrics{
 		PerLanguage: make(map[types.Language]*types.C2LanguageMetrics),
 	}
 
 	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		switch target.Language {
 		case types.LangGo:
 			if a.goAnalyzer == nil {
@@ -1,6 +1,7 @@
 package c2
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -156,7 +157,7 @@ func TestC2Analyzer_MultiLanguageDispatch(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
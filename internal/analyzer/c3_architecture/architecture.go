@@ -1,12 +1,16 @@
 package c3
 
 import (
+	"context"
+	"go/ast"
 	"go/types"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	arstypes "github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -21,6 +25,7 @@ const (
 type C3Analyzer struct {
 	pkgs     []*parser.ParsedPackage
 	tsParser *parser.TreeSitterParser
+	cache    *cache.Store // nil disables the per-file results cache
 }
 
 // NewC3Analyzer creates a C3Analyzer with Tree-sitter parser for multi-language analysis.
@@ -39,14 +44,17 @@ func (a *C3Analyzer) SetGoPackages(pkgs []*parser.ParsedPackage) {
 }
 
 // Analyze runs all 5 C3 sub-analyses and returns a combined AnalysisResult.
-func (a *C3Analyzer) Analyze(targets []*arstypes.AnalysisTarget) (*arstypes.AnalysisResult, error) {
+func (a *C3Analyzer) Analyze(ctx context.Context, targets []*arstypes.AnalysisTarget) (*arstypes.AnalysisResult, error) {
 	metrics := &arstypes.C3Metrics{}
 
 	if a.pkgs != nil {
-		metrics = a.analyzeGoC3()
+		metrics = a.analyzeGoC3(shared.GoFileContents(targets))
 	}
 
 	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		a.analyzeTarget(target, metrics)
 	}
 
@@ -138,8 +146,9 @@ func mergeFanoutMetrics(metrics *arstypes.C3Metrics, graph *shared.ImportGraph,
 	}
 }
 
-// analyzeGoC3 runs Go-specific C3 analysis.
-func (a *C3Analyzer) analyzeGoC3() *arstypes.C3Metrics {
+// analyzeGoC3 runs Go-specific C3 analysis. contents maps absolute file paths
+// to content already read for the targets.
+func (a *C3Analyzer) analyzeGoC3(contents map[string][]byte) *arstypes.C3Metrics {
 	srcPkgs := filterSourcePackages(a.pkgs)
 
 	modulePath := detectModulePath(srcPkgs)
@@ -149,7 +158,7 @@ func (a *C3Analyzer) analyzeGoC3() *arstypes.C3Metrics {
 	fanout := analyzeModuleFanout(srcPkgs, graph)
 	cycles := detectCircularDeps(graph)
 	importComp := analyzeImportComplexity(srcPkgs, modulePath)
-	deadExports := detectDeadCode(srcPkgs)
+	deadExports := a.detectDeadCode(srcPkgs, contents)
 
 	return &arstypes.C3Metrics{
 		MaxDirectoryDepth: maxDepth,
@@ -392,25 +401,35 @@ type exportedSymbol struct {
 	file string
 	line int
 	kind string
-	obj  types.Object
 }
 
-func detectDeadCode(pkgs []*parser.ParsedPackage) []arstypes.DeadExport {
-	exports := collectExportedSymbols(pkgs)
-	crossPkgRef := buildCrossPackageRefs(pkgs)
-	return filterDeadExports(exports, crossPkgRef, len(pkgs))
-}
-
-// collectExportedSymbols gathers all exported funcs and types from packages.
-func collectExportedSymbols(pkgs []*parser.ParsedPackage) []exportedSymbol {
+func (a *C3Analyzer) detectDeadCode(pkgs []*parser.ParsedPackage, contents map[string][]byte) []arstypes.DeadExport {
 	var exports []exportedSymbol
+	crossPkgRef := make(map[string]bool)
 	for _, pkg := range pkgs {
-		if pkg.Types == nil || pkg.TypesInfo == nil {
-			continue
+		var byFile map[string]c3GoFileFacts
+		files := shared.GoPackageFacts(a.cache, "c3-go", c3FactsVersion, pkg, contents, func(f *ast.File) c3GoFileFacts {
+			if byFile == nil {
+				byFile = goDeadCodeFacts(pkg)
+			}
+			return byFile[pkg.Fset.Position(f.Pos()).Filename]
+		})
+
+		var pkgExports []exportedSymbol
+		for _, ff := range files {
+			for _, e := range ff.Facts.Exports {
+				pkgExports = append(pkgExports, exportedSymbol{
+					pkg: pkg.PkgPath, name: e.Name, file: ff.File, line: e.Line, kind: e.Kind,
+				})
+			}
+			for _, key := range ff.Facts.Refs {
+				crossPkgRef[key] = true
+			}
 		}
-		exports = append(exports, collectPackageExports(pkg)...)
+		sort.Slice(pkgExports, func(i, j int) bool { return pkgExports[i].name < pkgExports[j].name })
+		exports = append(exports, pkgExports...)
 	}
-	return exports
+	return filterDeadExports(exports, crossPkgRef, len(pkgs))
 }
 
 // collectPackageExports extracts exported funcs and types from a single package scope.
@@ -428,7 +447,7 @@ func collectPackageExports(pkg *parser.ParsedPackage) []exportedSymbol {
 		}
 		file, line := objectPosition(obj, pkg)
 		exports = append(exports, exportedSymbol{
-			pkg: pkg.PkgPath, name: name, file: file, line: line, kind: kind, obj: obj,
+			pkg: pkg.PkgPath, name: name, file: file, line: line, kind: kind,
 		})
 	}
 	return exports
@@ -460,30 +479,19 @@ func objectPosition(obj types.Object, pkg *parser.ParsedPackage) (string, int) {
 	return "", 0
 }
 
-// buildCrossPackageRefs builds a set of objects referenced from a different package.
-func buildCrossPackageRefs(pkgs []*parser.ParsedPackage) map[types.Object]bool {
-	crossPkgRef := make(map[types.Object]bool)
-	for _, pkg := range pkgs {
-		if pkg.TypesInfo == nil {
-			continue
-		}
-		for _, obj := range pkg.TypesInfo.Uses {
-			if obj.Pkg() != nil && obj.Pkg().Path() != pkg.PkgPath {
-				crossPkgRef[obj] = true
-			}
-		}
-	}
-	return crossPkgRef
+// objectKey returns the lookup key for a package-level object.
+func objectKey(pkgPath, name string) string {
+	return pkgPath + "." + name
 }
 
 // filterDeadExports returns exports that have no cross-package references.
-func filterDeadExports(exports []exportedSymbol, crossPkgRef map[types.Object]bool, pkgCount int) []arstypes.DeadExport {
+func filterDeadExports(exports []exportedSymbol, crossPkgRef map[string]bool, pkgCount int) []arstypes.DeadExport {
 	if pkgCount <= 1 {
 		return nil
 	}
 	var dead []arstypes.DeadExport
 	for _, exp := range exports {
-		if crossPkgRef[exp.obj] {
+		if crossPkgRef[objectKey(exp.pkg, exp.name)] {
 			continue
 		}
 		dead = append(dead, arstypes.DeadExport{
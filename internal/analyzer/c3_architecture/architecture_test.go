@@ -1,6 +1,7 @@
 package c3
 
 import (
+	"context"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -19,7 +20,7 @@ func testdataDir() string {
 func loadTestPackages(t *testing.T, subdir string) []*parser.ParsedPackage {
 	t.Helper()
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse(filepath.Join(testdataDir(), subdir))
+	pkgs, err := p.Parse(context.Background(), filepath.Join(testdataDir(), subdir))
 	if err != nil {
 		t.Fatalf("failed to parse %s: %v", subdir, err)
 	}
@@ -45,7 +46,7 @@ func TestC3DirectoryDepth(t *testing.T) {
 
 	a := &C3Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -68,7 +69,7 @@ func TestC3ModuleFanout(t *testing.T) {
 
 	a := &C3Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -92,7 +93,7 @@ func TestC3CircularDeps(t *testing.T) {
 
 	a := &C3Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -111,7 +112,7 @@ func TestC3ImportComplexity(t *testing.T) {
 
 	a := &C3Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -135,7 +136,7 @@ func TestC3DeadCode(t *testing.T) {
 
 	a := &C3Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -169,7 +170,7 @@ func TestC3AnalyzerInterface(t *testing.T) {
 
 	pkgs := loadTestPackages(t, "deepnest")
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -0,0 +1,83 @@
+package c3
+
+import (
+	"sort"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+)
+
+// c3FactsVersion identifies the c3GoFileFacts format and the algorithms producing
+// it. Bump it whenever either changes so that stale cache entries are ignored.
+const c3FactsVersion = 1
+
+// goExport is an exported func or type declared in a Go file.
+type goExport struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+}
+
+// c3GoFileFacts are the dead code inputs that depend only on a single Go file:
+// the exports it declares and the package-level objects of other packages it
+// references, keyed by objectKey. Matching them happens across all files.
+type c3GoFileFacts struct {
+	Exports []goExport `json:"exports"`
+	Refs    []string   `json:"refs"`
+}
+
+// SetCache enables the per-file results cache. A nil store disables it.
+func (a *C3Analyzer) SetCache(store *cache.Store) {
+	a.cache = store
+}
+
+// GoResultsCached reports whether the C3 facts of every file of pkg are cached,
+// given the content of its files. Test packages are not analyzed by C3, and the
+// import graph only needs the package's imports.
+func (a *C3Analyzer) GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	return pkg.ForTest != "" || shared.GoFactsCached(a.cache, "c3-go", c3FactsVersion, pkg, contents)
+}
+
+// goDeadCodeFacts computes the dead code facts of each file of a type-checked
+// package, keyed by absolute file path. References are keyed by objectKey
+// rather than object identity, so that packages type-checked in separate
+// go/packages calls (e.g. a single package reloaded by the LSP after a save),
+// or not at all because their facts are cached, still resolve against each other.
+func goDeadCodeFacts(pkg *parser.ParsedPackage) map[string]c3GoFileFacts {
+	facts := make(map[string]c3GoFileFacts)
+	if pkg.TypesInfo == nil {
+		return facts
+	}
+	if pkg.Types != nil {
+		for _, exp := range collectPackageExports(pkg) {
+			f := facts[exp.file]
+			f.Exports = append(f.Exports, goExport{Name: exp.name, Line: exp.line, Kind: exp.kind})
+			facts[exp.file] = f
+		}
+	}
+
+	refs := make(map[string]map[string]bool) // file -> referenced object keys
+	for ident, obj := range pkg.TypesInfo.Uses {
+		if obj.Pkg() == nil || obj.Pkg().Path() == pkg.PkgPath {
+			continue
+		}
+		if obj.Parent() != obj.Pkg().Scope() {
+			continue // methods, fields and locals never shadow a package-level export
+		}
+		file := pkg.Fset.Position(ident.Pos()).Filename
+		if refs[file] == nil {
+			refs[file] = make(map[string]bool)
+		}
+		refs[file][objectKey(obj.Pkg().Path(), obj.Name())] = true
+	}
+	for file, keys := range refs {
+		f := facts[file]
+		for key := range keys {
+			f.Refs = append(f.Refs, key)
+		}
+		sort.Strings(f.Refs)
+		facts[file] = f
+	}
+	return facts
+}
@@ -0,0 +1,419 @@
+package c3
+
+import (
+	"go/ast"
+	"go/types"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tree_sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	arstypes "github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// FileDeps describes what one source file uses from other files of the project.
+type FileDeps struct {
+	Imports []string          // project files imported directly (slash-separated relative paths)
+	Calls   map[string]string // project functions called from the file ("Type.Method" for methods) -> declaring file
+}
+
+// DependencyIndex holds file-level dependencies of a project's source files,
+// derived from the same import graphs as C3's coupling metrics. C7 uses it as
+// ground truth for cross-file navigation.
+type DependencyIndex struct {
+	files map[string]*FileDeps
+}
+
+// BuildDependencyIndex indexes Go files from pkgs and Python/TypeScript files
+// of targets (parsed with tsParser, which may be nil to skip them).
+func BuildDependencyIndex(pkgs []*parser.ParsedPackage, tsParser *parser.TreeSitterParser, targets []*arstypes.AnalysisTarget) *DependencyIndex {
+	idx := &DependencyIndex{files: make(map[string]*FileDeps)}
+	for _, target := range targets {
+		switch target.Language {
+		case arstypes.LangGo:
+			idx.addGo(pkgs, target.RootDir)
+		case arstypes.LangPython, arstypes.LangTypeScript:
+			if tsParser == nil {
+				continue
+			}
+			parsed, err := tsParser.ParseTargetFiles(target)
+			if err != nil {
+				continue
+			}
+			if target.Language == arstypes.LangPython {
+				idx.addPython(pyFilterSourceFiles(parsed))
+			} else {
+				idx.addTypeScript(tsFilterSourceFiles(parsed))
+			}
+			parser.CloseAll(parsed)
+		}
+	}
+	return idx
+}
+
+// Deps returns the dependencies of the file at relPath.
+func (d *DependencyIndex) Deps(relPath string) (*FileDeps, bool) {
+	deps, ok := d.files[filepath.ToSlash(relPath)]
+	return deps, ok
+}
+
+// Closure returns all project files relPath depends on transitively, sorted.
+func (d *DependencyIndex) Closure(relPath string) []string {
+	start := filepath.ToSlash(relPath)
+	seen := map[string]bool{start: true}
+	queue := []string{start}
+	var closure []string
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		deps, ok := d.files[f]
+		if !ok {
+			continue
+		}
+		for _, dep := range deps.Imports {
+			if !seen[dep] {
+				seen[dep] = true
+				closure = append(closure, dep)
+				queue = append(queue, dep)
+			}
+		}
+	}
+	sort.Strings(closure)
+	return closure
+}
+
+func (d *DependencyIndex) deps(relPath string) *FileDeps {
+	deps, ok := d.files[relPath]
+	if !ok {
+		deps = &FileDeps{Calls: make(map[string]string)}
+		d.files[relPath] = deps
+	}
+	return deps
+}
+
+// addGo indexes Go files. A file depends on every non-test file of the module
+// packages it imports; its calls are resolved through the type checker.
+func (d *DependencyIndex) addGo(pkgs []*parser.ParsedPackage, rootDir string) {
+	relOf := func(abs string) string {
+		rel, err := filepath.Rel(rootDir, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return ""
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	modulePkgs := make(map[string]*parser.ParsedPackage)
+	for _, pkg := range filterSourcePackages(pkgs) {
+		modulePkgs[pkg.PkgPath] = pkg
+	}
+
+	for _, pkg := range modulePkgs {
+		for i, file := range pkg.Syntax {
+			if i >= len(pkg.GoFiles) {
+				break
+			}
+			rel := relOf(pkg.GoFiles[i])
+			if rel == "" {
+				continue
+			}
+			deps := d.deps(rel)
+			for _, spec := range file.Imports {
+				imported, ok := modulePkgs[strings.Trim(spec.Path.Value, `"`)]
+				if !ok {
+					continue
+				}
+				for _, f := range imported.GoFiles {
+					if r := relOf(f); r != "" {
+						deps.Imports = appendUnique(deps.Imports, r)
+					}
+				}
+			}
+			goCollectCalls(file, pkg, modulePkgs, rel, relOf, deps)
+		}
+	}
+}
+
+// goCollectCalls records the module functions and methods a file calls.
+func goCollectCalls(file *ast.File, pkg *parser.ParsedPackage, modulePkgs map[string]*parser.ParsedPackage, rel string, relOf func(string) string, deps *FileDeps) {
+	if pkg.TypesInfo == nil {
+		return
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		var ident *ast.Ident
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			ident = fun
+		case *ast.SelectorExpr:
+			ident = fun.Sel
+		default:
+			return true
+		}
+		fn, ok := pkg.TypesInfo.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() == nil {
+			return true
+		}
+		if _, ok := modulePkgs[fn.Pkg().Path()]; !ok {
+			return true
+		}
+		declFile := relOf(pkg.Fset.Position(fn.Pos()).Filename)
+		if declFile == "" || declFile == rel {
+			return true
+		}
+		deps.Calls[goFuncName(fn)] = declFile
+		return true
+	})
+}
+
+// goFuncName returns "Name" for functions and "Type.Name" for methods.
+func goFuncName(fn *types.Func) string {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Name()
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	if named, ok := recv.(*types.Named); ok {
+		return named.Obj().Name() + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+// addPython indexes Python files. Calls are resolved for names imported with
+// "from module import name" and for "module.name(...)" after "import module".
+func (d *DependencyIndex) addPython(files []*parser.ParsedTreeSitterFile) {
+	moduleFiles := make(map[string]string)
+	for _, f := range files {
+		moduleFiles[pyFileToModule(f.RelPath)] = filepath.ToSlash(f.RelPath)
+	}
+	graph := pyBuildImportGraph(files)
+
+	for _, f := range files {
+		rel := filepath.ToSlash(f.RelPath)
+		fromModule := pyFileToModule(f.RelPath)
+		deps := d.deps(rel)
+		for _, mod := range graph.Forward[fromModule] {
+			deps.Imports = appendUnique(deps.Imports, moduleFiles[mod])
+		}
+
+		names := make(map[string]string)   // imported name -> declaring file
+		modules := make(map[string]string) // local module alias -> module file
+		shared.WalkTree(f.Tree.RootNode(), func(node *tree_sitter.Node) {
+			switch node.Kind() {
+			case "import_statement":
+				pyCollectModuleAliases(node, f.Content, moduleFiles, modules)
+			case "import_from_statement":
+				pyCollectFromImports(node, f.Content, fromModule, moduleFiles, names, modules)
+			}
+		})
+
+		shared.WalkTree(f.Tree.RootNode(), func(node *tree_sitter.Node) {
+			if node.Kind() != "call" {
+				return
+			}
+			fn := node.ChildByFieldName("function")
+			if fn == nil {
+				return
+			}
+			switch fn.Kind() {
+			case "identifier":
+				if declFile, ok := names[shared.NodeText(fn, f.Content)]; ok {
+					deps.Calls[shared.NodeText(fn, f.Content)] = declFile
+				}
+			case "attribute":
+				obj := fn.ChildByFieldName("object")
+				attr := fn.ChildByFieldName("attribute")
+				if obj == nil || attr == nil {
+					return
+				}
+				if modFile, ok := modules[shared.NodeText(obj, f.Content)]; ok && modFile != rel {
+					deps.Calls[shared.NodeText(attr, f.Content)] = modFile
+				}
+			}
+		})
+	}
+}
+
+// pyCollectModuleAliases maps the local names of "import a.b [as c]" to project module files.
+func pyCollectModuleAliases(node *tree_sitter.Node, content []byte, moduleFiles, modules map[string]string) {
+	for i := uint(0); i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if child == nil {
+			continue
+		}
+		switch child.Kind() {
+		case "dotted_name":
+			name := shared.NodeText(child, content)
+			if file, ok := moduleFiles[name]; ok {
+				modules[name] = file
+			}
+		case "aliased_import":
+			nameNode := child.ChildByFieldName("name")
+			aliasNode := child.ChildByFieldName("alias")
+			if nameNode == nil || aliasNode == nil {
+				continue
+			}
+			if file, ok := moduleFiles[shared.NodeText(nameNode, content)]; ok {
+				modules[shared.NodeText(aliasNode, content)] = file
+			}
+		}
+	}
+}
+
+// pyCollectFromImports maps names imported with "from module import name [as alias]"
+// to the module's file, or to the submodule's file if the name is a module itself.
+func pyCollectFromImports(node *tree_sitter.Node, content []byte, fromModule string, moduleFiles, names, modules map[string]string) {
+	modNode := node.ChildByFieldName("module_name")
+	if modNode == nil {
+		return
+	}
+	modName := shared.NodeText(modNode, content)
+	if strings.HasPrefix(modName, ".") {
+		modName = pyResolveRelativeImport(fromModule, modName)
+	}
+
+	add := func(name, local string) {
+		if file, ok := moduleFiles[modName+"."+name]; ok {
+			modules[local] = file
+		} else if file, ok := moduleFiles[modName]; ok {
+			names[local] = file
+		}
+	}
+	for i := uint(0); i < node.ChildCount(); i++ {
+		child := node.Child(i)
+		if child == nil || child == modNode {
+			continue
+		}
+		switch child.Kind() {
+		case "dotted_name":
+			if node.FieldNameForChild(uint32(i)) == "name" {
+				name := shared.NodeText(child, content)
+				add(name, name)
+			}
+		case "aliased_import":
+			nameNode := child.ChildByFieldName("name")
+			aliasNode := child.ChildByFieldName("alias")
+			if nameNode != nil && aliasNode != nil {
+				add(shared.NodeText(nameNode, content), shared.NodeText(aliasNode, content))
+			}
+		}
+	}
+}
+
+// addTypeScript indexes TypeScript/JavaScript files. Calls are resolved for
+// named, default and namespace imports from relative modules.
+func (d *DependencyIndex) addTypeScript(files []*parser.ParsedTreeSitterFile) {
+	knownFiles := make(map[string]string)
+	for _, f := range files {
+		knownFiles[tsNormalizePath(f.RelPath)] = filepath.ToSlash(f.RelPath)
+	}
+	graph := tsBuildImportGraph(files)
+
+	for _, f := range files {
+		rel := filepath.ToSlash(f.RelPath)
+		deps := d.deps(rel)
+		for _, dep := range graph.Forward[tsNormalizePath(f.RelPath)] {
+			deps.Imports = appendUnique(deps.Imports, knownFiles[dep])
+		}
+
+		names := make(map[string][2]string) // local name -> {exported name, declaring file}
+		namespaces := make(map[string]string)
+		shared.WalkTree(f.Tree.RootNode(), func(node *tree_sitter.Node) {
+			if node.Kind() != "import_statement" {
+				return
+			}
+			src := node.ChildByFieldName("source")
+			if src == nil {
+				return
+			}
+			modulePath := tsStripQuotes(shared.NodeText(src, f.Content))
+			if !strings.HasPrefix(modulePath, ".") {
+				return
+			}
+			resolved := tsNormalizePath(path.Join(path.Dir(rel), modulePath))
+			declFile, ok := knownFiles[resolved]
+			if !ok {
+				return
+			}
+			tsCollectImportBindings(node, f.Content, declFile, names, namespaces)
+		})
+
+		shared.WalkTree(f.Tree.RootNode(), func(node *tree_sitter.Node) {
+			if node.Kind() != "call_expression" {
+				return
+			}
+			fn := node.ChildByFieldName("function")
+			if fn == nil {
+				return
+			}
+			switch fn.Kind() {
+			case "identifier":
+				if binding, ok := names[shared.NodeText(fn, f.Content)]; ok {
+					deps.Calls[binding[0]] = binding[1]
+				}
+			case "member_expression":
+				obj := fn.ChildByFieldName("object")
+				prop := fn.ChildByFieldName("property")
+				if obj == nil || prop == nil {
+					return
+				}
+				if declFile, ok := namespaces[shared.NodeText(obj, f.Content)]; ok {
+					deps.Calls[shared.NodeText(prop, f.Content)] = declFile
+				}
+			}
+		})
+	}
+}
+
+// tsCollectImportBindings records the local bindings of one import statement.
+func tsCollectImportBindings(node *tree_sitter.Node, content []byte, declFile string, names map[string][2]string, namespaces map[string]string) {
+	for i := uint(0); i < node.ChildCount(); i++ {
+		clause := node.Child(i)
+		if clause == nil || clause.Kind() != "import_clause" {
+			continue
+		}
+		for j := uint(0); j < clause.ChildCount(); j++ {
+			inner := clause.Child(j)
+			if inner == nil {
+				continue
+			}
+			switch inner.Kind() {
+			case "identifier": // default import
+				local := shared.NodeText(inner, content)
+				names[local] = [2]string{local, declFile}
+			case "named_imports":
+				for k := uint(0); k < inner.ChildCount(); k++ {
+					spec := inner.Child(k)
+					if spec == nil || spec.Kind() != "import_specifier" {
+						continue
+					}
+					nameNode := spec.ChildByFieldName("name")
+					if nameNode == nil {
+						continue
+					}
+					name := shared.NodeText(nameNode, content)
+					local := name
+					if alias := spec.ChildByFieldName("alias"); alias != nil {
+						local = shared.NodeText(alias, content)
+					}
+					names[local] = [2]string{name, declFile}
+				}
+			case "namespace_import":
+				for k := uint(0); k < inner.ChildCount(); k++ {
+					if c := inner.Child(k); c != nil && c.Kind() == "identifier" {
+						namespaces[shared.NodeText(c, content)] = declFile
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package c3
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// writeTarget writes files into a temp dir and returns a target listing them as sources.
+func writeTarget(t *testing.T, lang types.Language, files map[string]string) *types.AnalysisTarget {
+	t.Helper()
+	dir := t.TempDir()
+	target := &types.AnalysisTarget{Language: lang, RootDir: dir}
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		target.Files = append(target.Files, types.SourceFile{Path: path, RelPath: rel, Language: lang, Class: types.ClassSource})
+	}
+	return target
+}
+
+func TestDependencyIndex_Go(t *testing.T) {
+	pkgs := loadTestPackages(t, "coupling")
+	target := &types.AnalysisTarget{Language: types.LangGo, RootDir: filepath.Join(testdataDir(), "coupling")}
+
+	idx := BuildDependencyIndex(pkgs, nil, []*types.AnalysisTarget{target})
+	deps, ok := idx.Deps("pkga/a.go")
+	if !ok {
+		t.Fatal("pkga/a.go not indexed")
+	}
+	if !reflect.DeepEqual(deps.Imports, []string{"pkgb/b.go"}) {
+		t.Errorf("Imports = %v, want [pkgb/b.go]", deps.Imports)
+	}
+	if !reflect.DeepEqual(deps.Calls, map[string]string{"Hello": "pkgb/b.go"}) {
+		t.Errorf("Calls = %v, want Hello -> pkgb/b.go", deps.Calls)
+	}
+	if got := idx.Closure("pkgb/b.go"); len(got) != 0 {
+		t.Errorf("Closure(pkgb/b.go) = %v, want empty", got)
+	}
+}
+
+func TestDependencyIndex_Python(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	target := writeTarget(t, types.LangPython, map[string]string{
+		"app/main.py":    "from app.service import run as start\nimport app.store as store\n\ndef main():\n    start()\n    store.save(1)\n",
+		"app/service.py": "from .store import save\n\ndef run():\n    save(2)\n",
+		"app/store.py":   "def save(x):\n    return x\n",
+	})
+
+	idx := BuildDependencyIndex(nil, tsParser, []*types.AnalysisTarget{target})
+	deps, ok := idx.Deps("app/main.py")
+	if !ok {
+		t.Fatal("app/main.py not indexed")
+	}
+	if !reflect.DeepEqual(deps.Calls, map[string]string{"start": "app/service.py", "save": "app/store.py"}) {
+		t.Errorf("Calls = %v", deps.Calls)
+	}
+	if got := idx.Closure("app/main.py"); !reflect.DeepEqual(got, []string{"app/service.py", "app/store.py"}) {
+		t.Errorf("Closure(app/main.py) = %v", got)
+	}
+}
+
+func TestDependencyIndex_TypeScript(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	target := writeTarget(t, types.LangTypeScript, map[string]string{
+		"src/index.ts":       "import { format as fmt } from './util/format';\nimport * as api from './api';\n\nexport function main() {\n  return fmt(api.fetchUser(1));\n}\n",
+		"src/util/format.ts": "export function format(x: unknown): string {\n  return String(x);\n}\n",
+		"src/api/index.ts":   "export function fetchUser(id: number) {\n  return { id };\n}\n",
+	})
+
+	idx := BuildDependencyIndex(nil, tsParser, []*types.AnalysisTarget{target})
+	deps, ok := idx.Deps("src/index.ts")
+	if !ok {
+		t.Fatal("src/index.ts not indexed")
+	}
+	want := map[string]string{"format": "src/util/format.ts", "fetchUser": "src/api/index.ts"}
+	if !reflect.DeepEqual(deps.Calls, want) {
+		t.Errorf("Calls = %v, want %v", deps.Calls, want)
+	}
+	if got := idx.Closure("src/index.ts"); !reflect.DeepEqual(got, []string{"src/api/index.ts", "src/util/format.ts"}) {
+		t.Errorf("Closure(src/index.ts) = %v", got)
+	}
+}
@@ -0,0 +1,204 @@
+package c3
+
+import (
+	"go/ast"
+	"path/filepath"
+	"strings"
+
+	tree_sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	arstypes "github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// DocumentedExport is an exported function or type with a doc comment. C7
+// derives feature descriptions from the comments and asks agents to find the
+// symbol again.
+type DocumentedExport struct {
+	Name string // symbol name
+	Kind string // "func" or "type"
+	File string // declaring file (slash-separated relative path)
+	Line int
+	Doc  string // doc comment without comment markers
+}
+
+// DocumentedExports returns the documented exported functions and types of
+// the source files of targets, keyed by file, in declaration order. Like dead
+// code detection it covers Go packages from pkgs and the top-level definitions
+// of Python and TypeScript files (parsed with tsParser, which may be nil to
+// skip them). Methods are not included.
+func DocumentedExports(pkgs []*parser.ParsedPackage, tsParser *parser.TreeSitterParser, targets []*arstypes.AnalysisTarget) map[string][]DocumentedExport {
+	exports := make(map[string][]DocumentedExport)
+	add := func(e DocumentedExport) {
+		if e.Doc = strings.TrimSpace(e.Doc); e.Doc != "" {
+			exports[e.File] = append(exports[e.File], e)
+		}
+	}
+	for _, target := range targets {
+		switch target.Language {
+		case arstypes.LangGo:
+			goDocumentedExports(pkgs, target.RootDir, add)
+		case arstypes.LangPython, arstypes.LangTypeScript:
+			if tsParser == nil {
+				continue
+			}
+			parsed, err := tsParser.ParseTargetFiles(target)
+			if err != nil {
+				continue
+			}
+			if target.Language == arstypes.LangPython {
+				pyDocumentedExports(pyFilterSourceFiles(parsed), add)
+			} else {
+				tsDocumentedExports(tsFilterSourceFiles(parsed), add)
+			}
+			parser.CloseAll(parsed)
+		}
+	}
+	return exports
+}
+
+// goDocumentedExports collects exported top-level functions and types of the
+// source packages under rootDir. A type declared alone in its group may be
+// documented on the group.
+func goDocumentedExports(pkgs []*parser.ParsedPackage, rootDir string, add func(DocumentedExport)) {
+	for _, pkg := range filterSourcePackages(pkgs) {
+		for i, file := range pkg.Syntax {
+			if i >= len(pkg.GoFiles) {
+				break
+			}
+			rel, err := filepath.Rel(rootDir, pkg.GoFiles[i])
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			line := func(n ast.Node) int { return pkg.Fset.Position(n.Pos()).Line }
+
+			for _, decl := range file.Decls {
+				switch d := decl.(type) {
+				case *ast.FuncDecl:
+					if d.Recv == nil && d.Name.IsExported() && d.Doc != nil {
+						add(DocumentedExport{Name: d.Name.Name, Kind: "func", File: rel, Line: line(d.Name), Doc: d.Doc.Text()})
+					}
+				case *ast.GenDecl:
+					for _, spec := range d.Specs {
+						ts, ok := spec.(*ast.TypeSpec)
+						if !ok || !ts.Name.IsExported() {
+							continue
+						}
+						doc := ts.Doc
+						if doc == nil && len(d.Specs) == 1 {
+							doc = d.Doc
+						}
+						if doc != nil {
+							add(DocumentedExport{Name: ts.Name.Name, Kind: "type", File: rel, Line: line(ts.Name), Doc: doc.Text()})
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// pyDocumentedExports collects public top-level functions and classes with a
+// docstring.
+func pyDocumentedExports(files []*parser.ParsedTreeSitterFile, add func(DocumentedExport)) {
+	for _, f := range files {
+		root := f.Tree.RootNode()
+		for i := uint(0); i < root.ChildCount(); i++ {
+			child := root.Child(i)
+			if child == nil {
+				continue
+			}
+			def, ok := pyExtractDefinition(child, f.Content, f.RelPath)
+			if !ok {
+				continue
+			}
+			node := child
+			if node.Kind() == "decorated_definition" {
+				node = pyDecoratedInner(node)
+			}
+			if node == nil {
+				continue
+			}
+			add(DocumentedExport{Name: def.name, Kind: def.kind, File: f.RelPath, Line: def.line, Doc: pyDocstring(node, f.Content)})
+		}
+	}
+}
+
+// pyDecoratedInner returns the function or class a decorated_definition wraps.
+func pyDecoratedInner(node *tree_sitter.Node) *tree_sitter.Node {
+	for j := uint(0); j < node.ChildCount(); j++ {
+		if inner := node.Child(j); inner != nil && (inner.Kind() == "function_definition" || inner.Kind() == "class_definition") {
+			return inner
+		}
+	}
+	return nil
+}
+
+// pyDocstring returns the docstring of a function or class definition, or "".
+func pyDocstring(def *tree_sitter.Node, content []byte) string {
+	body := def.ChildByFieldName("body")
+	if body == nil || body.NamedChildCount() == 0 {
+		return ""
+	}
+	first := body.NamedChild(0)
+	if first == nil || first.Kind() != "expression_statement" || first.NamedChildCount() == 0 {
+		return ""
+	}
+	str := first.NamedChild(0)
+	if str == nil || str.Kind() != "string" {
+		return ""
+	}
+	text := strings.TrimLeft(shared.NodeText(str, content), "rRuUbB")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+		if strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) && len(text) >= 2*len(quote) {
+			return text[len(quote) : len(text)-len(quote)]
+		}
+	}
+	return ""
+}
+
+// tsDocumentedExports collects exported functions and classes directly
+// preceded by a JSDoc comment.
+func tsDocumentedExports(files []*parser.ParsedTreeSitterFile, add func(DocumentedExport)) {
+	for _, f := range files {
+		root := f.Tree.RootNode()
+		var prev *tree_sitter.Node
+		for i := uint(0); i < root.ChildCount(); i++ {
+			child := root.Child(i)
+			if child == nil {
+				continue
+			}
+			if child.Kind() == "export_statement" && prev != nil && prev.Kind() == "comment" {
+				if doc := tsJSDocText(shared.NodeText(prev, f.Content)); doc != "" {
+					var defs []tsExportDef
+					tsCollectExportedDefs(child, f.Content, f.RelPath, &defs)
+					for _, d := range defs {
+						if d.kind == "func" || d.kind == "type" {
+							add(DocumentedExport{Name: d.name, Kind: d.kind, File: d.file, Line: d.line, Doc: doc})
+						}
+					}
+				}
+			}
+			prev = child
+		}
+	}
+}
+
+// tsJSDocText strips the markers of a /** ... */ comment and drops its block
+// tags (@param, @returns, ...). Other comments yield "".
+func tsJSDocText(comment string) string {
+	if !strings.HasPrefix(comment, "/**") || !strings.HasSuffix(comment, "*/") {
+		return ""
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(comment, "/**"), "*/"), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if strings.HasPrefix(line, "@") {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
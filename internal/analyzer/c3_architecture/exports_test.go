@@ -0,0 +1,97 @@
+package c3
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestDocumentedExports_Go(t *testing.T) {
+	pkgs := loadTestPackages(t, "coupling")
+	target := &types.AnalysisTarget{Language: types.LangGo, RootDir: filepath.Join(testdataDir(), "coupling")}
+
+	exports := DocumentedExports(pkgs, nil, []*types.AnalysisTarget{target})
+	got := exports["pkgb/b.go"]
+	if len(got) != 1 || got[0].Name != "Hello" || got[0].Kind != "func" || got[0].Line != 5 {
+		t.Fatalf("pkgb/b.go exports = %+v, want Hello (func, line 5)", got)
+	}
+	if want := "Hello returns a greeting. pkgb has no intra-module imports (efferent=0).\npkgb is imported by pkga, so afferent=1."; got[0].Doc != want {
+		t.Errorf("Doc = %q, want %q", got[0].Doc, want)
+	}
+}
+
+func TestDocumentedExports_TreeSitter(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	py := writeTarget(t, types.LangPython, map[string]string{
+		"app/store.py": `def save(x):
+    """Persist a record to disk."""
+    return x
+
+
+def load():
+    return None
+
+
+@cached
+class Registry:
+    '''Keeps track of loaded plugins.'''
+
+
+def _helper():
+    """Private."""
+`,
+		"tests/test_store.py": "def test_save():\n    \"\"\"Checks save.\"\"\"\n",
+	})
+	ts := writeTarget(t, types.LangTypeScript, map[string]string{
+		"src/cart.ts": `/**
+ * Computes the total price of a cart.
+ * @param items the cart items
+ */
+export function total(items: number[]): number {
+  return items.reduce((a, b) => a + b, 0);
+}
+
+// Not a JSDoc comment.
+export function count(items: number[]): number {
+  return items.length;
+}
+
+/** A shopping cart. */
+export class Cart {}
+`,
+	})
+
+	exports := DocumentedExports(nil, tsParser, []*types.AnalysisTarget{py, ts})
+	want := map[string][]DocumentedExport{
+		"app/store.py": {
+			{Name: "save", Kind: "func", File: "app/store.py", Line: 1, Doc: "Persist a record to disk."},
+			{Name: "Registry", Kind: "type", File: "app/store.py", Line: 11, Doc: "Keeps track of loaded plugins."},
+		},
+		"src/cart.ts": {
+			{Name: "total", Kind: "func", File: "src/cart.ts", Line: 5, Doc: "Computes the total price of a cart."},
+			{Name: "Cart", Kind: "type", File: "src/cart.ts", Line: 15, Doc: "A shopping cart."},
+		},
+	}
+	if len(exports) != len(want) {
+		t.Errorf("files = %d, want %d (test files and undocumented exports skipped): %+v", len(exports), len(want), exports)
+	}
+	for file, defs := range want {
+		got := exports[file]
+		if len(got) != len(defs) {
+			t.Errorf("%s: got %+v, want %+v", file, got, defs)
+			continue
+		}
+		for i := range defs {
+			if got[i] != defs[i] {
+				t.Errorf("%s[%d] = %+v, want %+v", file, i, got[i], defs[i])
+			}
+		}
+	}
+}
@@ -347,7 +347,7 @@ func pyFlagDeadExports(defs []pyDefinition, importedNames map[string]bool) []typ
 			dead = append(dead, types.DeadExport{
 				Package: "",
 				Name:    d.name,
-				File:    filepath.Base(d.file),
+				File:    filepath.ToSlash(d.file),
 				Line:    d.line,
 				Kind:    d.kind,
 			})
@@ -1,6 +1,7 @@
 package c3
 
 import (
+	"context"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -20,7 +21,7 @@ func pyTestdataDir() string {
 func pyLoadTestPackages(t *testing.T, subdir string) []*parser.ParsedPackage {
 	t.Helper()
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse(filepath.Join(pyTestdataDir(), subdir))
+	pkgs, err := p.Parse(context.Background(), filepath.Join(pyTestdataDir(), subdir))
 	if err != nil {
 		t.Fatalf("failed to parse %s: %v", subdir, err)
 	}
@@ -255,7 +256,7 @@ func TestPyC3Integration(t *testing.T) {
 		},
 	}
 
-	result, err := a.Analyze(targets)
+	result, err := a.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
@@ -277,7 +278,7 @@ func TestC3_GoRegressionWithNewConstructor(t *testing.T) {
 
 	a := NewC3Analyzer(nil)
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -240,7 +240,7 @@ func tsFlagDeadExports(defs []tsExportDef, importedNames map[string]bool) []type
 			dead = append(dead, types.DeadExport{
 				Package: "",
 				Name:    d.name,
-				File:    filepath.Base(d.file),
+				File:    filepath.ToSlash(d.file),
 				Line:    d.line,
 				Kind:    d.kind,
 			})
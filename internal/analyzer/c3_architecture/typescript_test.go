@@ -1,6 +1,7 @@
 package c3
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
@@ -242,7 +243,7 @@ func TestTsC3Integration(t *testing.T) {
 		},
 	}
 
-	result, err := a.Analyze(targets)
+	result, err := a.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
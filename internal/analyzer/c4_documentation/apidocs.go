@@ -0,0 +1,222 @@
+package c4
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+
+	tree_sitter "github.com/tree-sitter/go-tree-sitter"
+
+	tsp "github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// APIDecl is a single public API declaration found in a source file.
+type APIDecl struct {
+	Name       string
+	Kind       string // e.g. "func", "type", "class"; TypeScript uses the declaration keyword
+	Line       int    // 1-based line of the declaration
+	Documented bool   // true if a doc comment, docstring or JSDoc precedes it
+}
+
+// FindUndocumentedAPIs returns the public APIs in a single source file that lack
+// documentation. It applies the same rules as the C4 api_doc_coverage metric, so
+// editor diagnostics and the report always agree. tsParser may be nil for Go files.
+func FindUndocumentedAPIs(sf types.SourceFile, tsParser *tsp.TreeSitterParser) ([]APIDecl, error) {
+	decls, err := fileAPIDecls(sf, tsParser)
+	if err != nil {
+		return nil, err
+	}
+	var undocumented []APIDecl
+	for _, d := range decls {
+		if !d.Documented {
+			undocumented = append(undocumented, d)
+		}
+	}
+	return undocumented, nil
+}
+
+// fileAPIDecls dispatches a single file to its language-specific collector.
+func fileAPIDecls(sf types.SourceFile, tsParser *tsp.TreeSitterParser) ([]APIDecl, error) {
+	switch sf.Language {
+	case types.LangGo:
+		fset := token.NewFileSet()
+		f, err := goparser.ParseFile(fset, sf.Path, sf.Content, goparser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		return goFileAPIDecls(fset, f), nil
+	case types.LangPython:
+		if tsParser == nil {
+			return nil, fmt.Errorf("tree-sitter parser required for %s", sf.Language)
+		}
+		tree, err := tsParser.ParseFile(types.LangPython, ".py", sf.Content)
+		if err != nil {
+			return nil, err
+		}
+		defer tree.Close()
+		return pythonFileAPIDecls(tree.RootNode(), sf.Content), nil
+	case types.LangTypeScript:
+		return typeScriptFileAPIDecls(sf.Content), nil
+	default:
+		return nil, fmt.Errorf("unsupported language: %s", sf.Language)
+	}
+}
+
+// countAPIDecls returns the total and documented counts for a list of declarations.
+func countAPIDecls(decls []APIDecl) (publicAPIs, documentedAPIs int) {
+	for _, d := range decls {
+		publicAPIs++
+		if d.Documented {
+			documentedAPIs++
+		}
+	}
+	return
+}
+
+// goFileAPIDecls collects exported functions, methods and types from a Go file.
+// A declaration counts as documented if it has a Doc comment or an associated
+// CommentMap entry (functions), or a doc comment on the type or its GenDecl (types).
+func goFileAPIDecls(fset *token.FileSet, f *ast.File) []APIDecl {
+	var decls []APIDecl
+	cmap := ast.NewCommentMap(fset, f, f.Comments)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch decl := n.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.IsExported() {
+				documented := decl.Doc != nil && len(decl.Doc.List) > 0
+				if !documented {
+					documented = len(cmap[decl]) > 0
+				}
+				decls = append(decls, APIDecl{
+					Name:       decl.Name.Name,
+					Kind:       "func",
+					Line:       fset.Position(decl.Name.Pos()).Line,
+					Documented: documented,
+				})
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				s, ok := spec.(*ast.TypeSpec)
+				if !ok || !s.Name.IsExported() {
+					continue
+				}
+				documented := (decl.Doc != nil && len(decl.Doc.List) > 0) ||
+					(s.Doc != nil && len(s.Doc.List) > 0)
+				decls = append(decls, APIDecl{
+					Name:       s.Name.Name,
+					Kind:       "type",
+					Line:       fset.Position(s.Name.Pos()).Line,
+					Documented: documented,
+				})
+			}
+		}
+		return true
+	})
+	return decls
+}
+
+// pythonFileAPIDecls collects public top-level functions and classes from a Python module.
+// Names starting with an underscore are private by convention and skipped.
+func pythonFileAPIDecls(root *tree_sitter.Node, content []byte) []APIDecl {
+	var decls []APIDecl
+	for i := uint(0); i < uint(root.ChildCount()); i++ {
+		child := root.Child(i)
+		if child == nil {
+			continue
+		}
+		kind := ""
+		switch child.Kind() {
+		case "function_definition":
+			kind = "func"
+		case "class_definition":
+			kind = "class"
+		default:
+			continue
+		}
+		name := extractPythonDefName(child, content)
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		decls = append(decls, APIDecl{
+			Name:       name,
+			Kind:       kind,
+			Line:       int(child.StartPosition().Row) + 1,
+			Documented: hasPythonDocstring(child),
+		})
+	}
+	return decls
+}
+
+// typeScriptFileAPIDecls collects export declarations from TypeScript source using a
+// line-based scan. A declaration is documented when a JSDoc block ends directly above it.
+func typeScriptFileAPIDecls(content []byte) []APIDecl {
+	var decls []APIDecl
+	lines := bytes.Split(content, []byte("\n"))
+	inJSDoc := false
+	hasJSDoc := false
+
+	for i, line := range lines {
+		lineStr := string(bytes.TrimSpace(line))
+
+		// Track JSDoc comments
+		if strings.HasPrefix(lineStr, "/**") {
+			inJSDoc = true
+			hasJSDoc = true
+		}
+		if inJSDoc && strings.Contains(lineStr, "*/") {
+			inJSDoc = false
+		}
+
+		// Check for export declarations
+		if strings.HasPrefix(lineStr, "export ") {
+			if kind := tsExportKind(lineStr); kind != "" {
+				decls = append(decls, APIDecl{
+					Name:       tsExportName(lineStr, kind),
+					Kind:       kind,
+					Line:       i + 1,
+					Documented: hasJSDoc,
+				})
+			}
+			hasJSDoc = false
+		} else if !strings.HasPrefix(lineStr, "*") && !strings.HasPrefix(lineStr, "//") && lineStr != "" {
+			// Non-comment, non-export line resets JSDoc tracking
+			if !inJSDoc {
+				hasJSDoc = false
+			}
+		}
+	}
+	return decls
+}
+
+// tsExportKinds lists the declaration keywords counted as public TypeScript APIs,
+// in the order they are checked.
+var tsExportKinds = []string{"function", "class", "const", "interface", "type"}
+
+// tsExportKind returns the declaration keyword of an export line, or "" if the
+// line does not export a counted declaration.
+func tsExportKind(line string) string {
+	for _, kind := range tsExportKinds {
+		if strings.Contains(line, kind+" ") {
+			return kind
+		}
+	}
+	return ""
+}
+
+// tsExportName extracts the declared identifier following the kind keyword.
+func tsExportName(line, kind string) string {
+	idx := strings.Index(line, kind+" ")
+	rest := strings.TrimSpace(line[idx+len(kind)+1:])
+	end := strings.IndexFunc(rest, func(r rune) bool {
+		return !(r == '_' || r == '$' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if end < 0 {
+		end = len(rest)
+	}
+	return rest[:end]
+}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"go/ast"
 	goparser "go/parser"
 	"go/token"
 	"os"
@@ -18,6 +17,7 @@ import (
 	tree_sitter "github.com/tree-sitter/go-tree-sitter"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	tsp "github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -39,19 +39,48 @@ const (
 // C4Analyzer implements the pipeline.Analyzer interface for C4: Documentation Quality.
 // It analyzes README presence, comment density, API doc coverage, and other documentation artifacts.
 type C4Analyzer struct {
-	tsParser  *tsp.TreeSitterParser
-	evaluator *agent.Evaluator // nil if LLM not enabled
+	tsParser *tsp.TreeSitterParser
+	judge    agent.Judge     // nil if LLM not enabled
+	cache    *cache.Store    // nil disables the per-file results cache
+	llmCache *cache.LLMStore // nil disables the LLM response cache
+	budget   *agent.Budget   // LLM budget shared with C7; nil for no limit
 }
 
 // NewC4Analyzer creates a C4Analyzer. Tree-sitter parser is needed for Python/TS analysis.
-// evaluator can be nil for static-only analysis.
+// LLM evaluation stays off until SetEvaluator or SetJudge is called.
 func NewC4Analyzer(tsParser *tsp.TreeSitterParser) *C4Analyzer {
-	return &C4Analyzer{tsParser: tsParser, evaluator: nil}
+	return &C4Analyzer{tsParser: tsParser}
 }
 
-// SetEvaluator enables CLI-based content quality evaluation.
+// SetEvaluator enables CLI-based content quality evaluation; nil disables it.
 func (a *C4Analyzer) SetEvaluator(eval *agent.Evaluator) {
-	a.evaluator = eval
+	if eval == nil {
+		a.judge = nil
+		return
+	}
+	a.judge = eval
+}
+
+// SetJudge enables content quality evaluation with any judge backend; nil disables it.
+func (a *C4Analyzer) SetJudge(j agent.Judge) {
+	a.judge = j
+}
+
+// SetCache enables the per-file results cache. A nil store disables it.
+func (a *C4Analyzer) SetCache(store *cache.Store) {
+	a.cache = store
+}
+
+// SetLLMCache enables the LLM response cache for judge evaluations. A nil
+// store disables it.
+func (a *C4Analyzer) SetLLMCache(store *cache.LLMStore) {
+	a.llmCache = store
+}
+
+// SetBudget meters and limits the judge calls with budget, shared with C7.
+// Nil removes the limit.
+func (a *C4Analyzer) SetBudget(budget *agent.Budget) {
+	a.budget = budget
 }
 
 // Name returns the analyzer display name.
@@ -75,11 +104,11 @@ func (a *C4Analyzer) Name() string {
 // - C4-06: CONTRIBUTING guide presence
 // - C4-07: Diagrams presence (architecture/design documentation)
 //
-// Optional LLM-based evaluation (if Claude CLI available):
+// Optional LLM-based evaluation (if Claude CLI available or a judge is configured):
 // - README clarity and completeness
 // - Example code quality and usefulness
 // - Overall documentation comprehensiveness
-func (a *C4Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (a *C4Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	if len(targets) == 0 {
 		return nil, fmt.Errorf("no targets provided")
 	}
@@ -92,8 +121,12 @@ func (a *C4Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 	analyzeStaticDocs(rootDir, metrics)
 	a.analyzeCodeMetrics(targets, metrics)
 
-	if a.evaluator != nil {
-		a.runLLMAnalysis(rootDir, metrics)
+	if a.judge != nil {
+		if a.budget.Exhausted() {
+			a.budget.Skip("C4 LLM evaluation")
+		} else {
+			a.runLLMAnalysis(ctx, rootDir, metrics)
+		}
 	}
 
 	metrics.Available = true
@@ -140,8 +173,49 @@ func (a *C4Analyzer) analyzeCodeMetrics(targets []*types.AnalysisTarget, metrics
 	}
 }
 
-// analyzeTargetCodeMetrics returns comment and API doc counts for a single language target.
+// c4FactsVersion identifies the docFileFacts format and the counting rules producing
+// it. Bump it whenever either changes so that stale cache entries are ignored.
+const c4FactsVersion = 1
+
+// docFileFacts are the comment and API doc counts of a single source file.
+type docFileFacts struct {
+	TotalLines     int `json:"total_lines"`
+	CommentLines   int `json:"comment_lines"`
+	PublicAPIs     int `json:"public_apis"`
+	DocumentedAPIs int `json:"documented_apis"`
+}
+
+// analyzeTargetCodeMetrics returns comment and API doc counts for a single language
+// target. Counts are computed per source file so that unchanged files can be served
+// from the results cache without being read by a parser.
 func (a *C4Analyzer) analyzeTargetCodeMetrics(target *types.AnalysisTarget) (totalLines, commentLines, publicAPIs, documentedAPIs int) {
+	if target.Language != types.LangGo && a.tsParser == nil {
+		return // never cache the zero counts of an unavailable parser
+	}
+	for _, sf := range target.Files {
+		if sf.Class != types.ClassSource {
+			continue
+		}
+		content, err := tsp.SourceContent(sf)
+		if err != nil {
+			continue
+		}
+		facts, _ := cache.Memo(a.cache, "c4-"+string(target.Language), c4FactsVersion, content, func() (docFileFacts, error) {
+			fileTarget := &types.AnalysisTarget{Language: target.Language, RootDir: target.RootDir, Files: []types.SourceFile{sf}}
+			var f docFileFacts
+			f.TotalLines, f.CommentLines, f.PublicAPIs, f.DocumentedAPIs = a.countCodeMetrics(fileTarget)
+			return f, nil
+		})
+		totalLines += facts.TotalLines
+		commentLines += facts.CommentLines
+		publicAPIs += facts.PublicAPIs
+		documentedAPIs += facts.DocumentedAPIs
+	}
+	return
+}
+
+// countCodeMetrics counts comment lines and documented APIs in a target's source files.
+func (a *C4Analyzer) countCodeMetrics(target *types.AnalysisTarget) (totalLines, commentLines, publicAPIs, documentedAPIs int) {
 	switch target.Language {
 	case types.LangGo:
 		totalLines, commentLines = analyzeGoComments(target)
@@ -160,30 +234,35 @@ func (a *C4Analyzer) analyzeTargetCodeMetrics(target *types.AnalysisTarget) (tot
 	return
 }
 
-// runLLMAnalysis performs LLM-based content quality evaluation using Claude CLI.
+// runLLMAnalysis performs LLM-based content quality evaluation using the judge.
 //
-// Optional analysis (requires Claude CLI installed):
+// Optional analysis (requires Claude CLI installed or a configured judge):
 // - README clarity: Evaluates readme comprehensiveness and structure
 // - Example quality: Assesses example code usefulness and completeness
 // - Docs completeness: Overall documentation coverage and gaps
 //
-// Uses agent.Evaluator with 5-minute timeout and retry logic.
+// Uses the judge with 5-minute timeout and retry logic.
 // Gracefully degrades if LLM unavailable (static metrics still provided).
-func (a *C4Analyzer) runLLMAnalysis(rootDir string, metrics *types.C4Metrics) {
+func (a *C4Analyzer) runLLMAnalysis(ctx context.Context, rootDir string, metrics *types.C4Metrics) {
 	metrics.LLMEnabled = true
+	info := a.judge.Info()
+	metrics.LLMJudge = info.Backend
+	metrics.LLMModel = info.Model
+	metrics.LLMTemperature = info.Temperature
 
-	ctx, cancel := context.WithTimeout(context.Background(), llmAnalysisTimeout)
+	ctx, cancel := context.WithTimeout(ctx, llmAnalysisTimeout)
 	defer cancel()
 
-	totalTokens := evaluateReadmeClarity(ctx, a.evaluator, rootDir, metrics)
-	totalTokens += evaluateExampleQuality(ctx, a.evaluator, rootDir, metrics)
-	totalTokens += evaluateCompleteness(ctx, a.evaluator, rootDir, metrics)
-	totalTokens += evaluateCrossRefCoherence(ctx, a.evaluator, rootDir, metrics)
+	judge := agent.NewCachedJudge(agent.NewBudgetJudge(a.judge, a.budget), a.llmCache)
+	totalTokens := evaluateReadmeClarity(ctx, judge, rootDir, metrics)
+	totalTokens += evaluateExampleQuality(ctx, judge, rootDir, metrics)
+	totalTokens += evaluateCompleteness(ctx, judge, rootDir, metrics)
+	totalTokens += evaluateCrossRefCoherence(ctx, judge, rootDir, metrics)
 
 	finalizeLLMMetrics(metrics, totalTokens, rootDir)
 }
 
-func evaluateReadmeClarity(ctx context.Context, evaluator *agent.Evaluator, rootDir string, metrics *types.C4Metrics) int {
+func evaluateReadmeClarity(ctx context.Context, judge agent.Judge, rootDir string, metrics *types.C4Metrics) int {
 	if !metrics.ReadmePresent {
 		return 0
 	}
@@ -193,7 +272,7 @@ func evaluateReadmeClarity(ctx context.Context, evaluator *agent.Evaluator, root
 		return 0
 	}
 
-	eval, err := evaluator.EvaluateWithRetry(ctx, agent.ReadmeClarityPrompt, readmeContent)
+	eval, err := agent.EvaluateWithRetry(ctx, judge, agent.ReadmeClarityPrompt, readmeContent)
 	if err == nil {
 		metrics.ReadmeClarity = eval.Score
 		return estimateTokens(readmeContent)
@@ -201,13 +280,13 @@ func evaluateReadmeClarity(ctx context.Context, evaluator *agent.Evaluator, root
 	return 0
 }
 
-func evaluateExampleQuality(ctx context.Context, evaluator *agent.Evaluator, rootDir string, metrics *types.C4Metrics) int {
+func evaluateExampleQuality(ctx context.Context, judge agent.Judge, rootDir string, metrics *types.C4Metrics) int {
 	exampleContent := collectExampleContent(rootDir)
 	if exampleContent == "" {
 		return 0
 	}
 
-	eval, err := evaluator.EvaluateWithRetry(ctx, agent.ExampleQualityPrompt, exampleContent)
+	eval, err := agent.EvaluateWithRetry(ctx, judge, agent.ExampleQualityPrompt, exampleContent)
 	if err == nil {
 		metrics.ExampleQuality = eval.Score
 		return estimateTokens(exampleContent)
@@ -215,13 +294,13 @@ func evaluateExampleQuality(ctx context.Context, evaluator *agent.Evaluator, roo
 	return 0
 }
 
-func evaluateCompleteness(ctx context.Context, evaluator *agent.Evaluator, rootDir string, metrics *types.C4Metrics) int {
+func evaluateCompleteness(ctx context.Context, judge agent.Judge, rootDir string, metrics *types.C4Metrics) int {
 	docsContent := collectDocsSummary(rootDir, metrics)
 	if docsContent == "" {
 		return 0
 	}
 
-	eval, err := evaluator.EvaluateWithRetry(ctx, agent.CompletenessPrompt, docsContent)
+	eval, err := agent.EvaluateWithRetry(ctx, judge, agent.CompletenessPrompt, docsContent)
 	if err == nil {
 		metrics.Completeness = eval.Score
 		return estimateTokens(docsContent)
@@ -229,7 +308,7 @@ func evaluateCompleteness(ctx context.Context, evaluator *agent.Evaluator, rootD
 	return 0
 }
 
-func evaluateCrossRefCoherence(ctx context.Context, evaluator *agent.Evaluator, rootDir string, metrics *types.C4Metrics) int {
+func evaluateCrossRefCoherence(ctx context.Context, judge agent.Judge, rootDir string, metrics *types.C4Metrics) int {
 	if !metrics.ReadmePresent {
 		return 0
 	}
@@ -239,7 +318,7 @@ func evaluateCrossRefCoherence(ctx context.Context, evaluator *agent.Evaluator,
 		return 0
 	}
 
-	eval, err := evaluator.EvaluateWithRetry(ctx, agent.CrossRefCoherencePrompt, readmeContent)
+	eval, err := agent.EvaluateWithRetry(ctx, judge, agent.CrossRefCoherencePrompt, readmeContent)
 	if err == nil {
 		metrics.CrossRefCoherence = eval.Score
 		return estimateTokens(readmeContent)
@@ -697,40 +776,9 @@ func analyzeGoAPIDocs(target *types.AnalysisTarget) (publicAPIs, documentedAPIs
 			continue
 		}
 
-		// Build comment map for the file
-		cmap := ast.NewCommentMap(fset, f, f.Comments)
-
-		// Check exported functions, types, and methods
-		ast.Inspect(f, func(n ast.Node) bool {
-			switch decl := n.(type) {
-			case *ast.FuncDecl:
-				if decl.Name.IsExported() {
-					publicAPIs++
-					// Check if there's a doc comment
-					if decl.Doc != nil && len(decl.Doc.List) > 0 {
-						documentedAPIs++
-					} else if comments := cmap[decl]; len(comments) > 0 {
-						documentedAPIs++
-					}
-				}
-			case *ast.GenDecl:
-				for _, spec := range decl.Specs {
-					switch s := spec.(type) {
-					case *ast.TypeSpec:
-						if s.Name.IsExported() {
-							publicAPIs++
-							// Check for doc comment on the type or containing GenDecl
-							if decl.Doc != nil && len(decl.Doc.List) > 0 {
-								documentedAPIs++
-							} else if s.Doc != nil && len(s.Doc.List) > 0 {
-								documentedAPIs++
-							}
-						}
-					}
-				}
-			}
-			return true
-		})
+		pa, da := countAPIDecls(goFileAPIDecls(fset, f))
+		publicAPIs += pa
+		documentedAPIs += da
 	}
 	return
 }
@@ -821,24 +869,7 @@ func analyzePythonAPIDocs(target *types.AnalysisTarget, tsParser *tsp.TreeSitter
 
 // countPythonAPIDocs walks the tree to find public functions/classes with docstrings.
 func countPythonAPIDocs(root *tree_sitter.Node, content []byte) (publicAPIs, documentedAPIs int) {
-	// Walk through direct children of module looking for function_definition and class_definition
-	for i := uint(0); i < uint(root.ChildCount()); i++ {
-		child := root.Child(i)
-		if child == nil {
-			continue
-		}
-		switch child.Kind() {
-		case "function_definition", "class_definition":
-			name := extractPythonDefName(child, content)
-			if !strings.HasPrefix(name, "_") { // Public if not starting with _
-				publicAPIs++
-				if hasPythonDocstring(child) {
-					documentedAPIs++
-				}
-			}
-		}
-	}
-	return
+	return countAPIDecls(pythonFileAPIDecls(root, content))
 }
 
 // extractPythonDefName extracts the name of a function or class definition.
@@ -932,43 +963,9 @@ func analyzeTypeScriptAPIDocs(target *types.AnalysisTarget, tsParser *tsp.TreeSi
 			}
 		}
 
-		// Use simpler regex-based approach for export detection
-		lines := bytes.Split(content, []byte("\n"))
-		inJSDoc := false
-		hasJSDoc := false
-
-		for _, line := range lines {
-			lineStr := string(bytes.TrimSpace(line))
-
-			// Track JSDoc comments
-			if strings.HasPrefix(lineStr, "/**") {
-				inJSDoc = true
-				hasJSDoc = true
-			}
-			if inJSDoc && strings.Contains(lineStr, "*/") {
-				inJSDoc = false
-			}
-
-			// Check for export declarations
-			if strings.HasPrefix(lineStr, "export ") {
-				if strings.Contains(lineStr, "function ") ||
-					strings.Contains(lineStr, "class ") ||
-					strings.Contains(lineStr, "const ") ||
-					strings.Contains(lineStr, "interface ") ||
-					strings.Contains(lineStr, "type ") {
-					publicAPIs++
-					if hasJSDoc {
-						documentedAPIs++
-					}
-				}
-				hasJSDoc = false
-			} else if !strings.HasPrefix(lineStr, "*") && !strings.HasPrefix(lineStr, "//") && lineStr != "" {
-				// Non-comment, non-export line resets JSDoc tracking
-				if !inJSDoc {
-					hasJSDoc = false
-				}
-			}
-		}
+		pa, da := countAPIDecls(typeScriptFileAPIDecls(content))
+		publicAPIs += pa
+		documentedAPIs += da
 	}
 	return
 }
@@ -1,11 +1,13 @@
 package c4
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -49,12 +51,12 @@ func TestC4Analyzer_Name(t *testing.T) {
 func TestC4Analyzer_EmptyTargets(t *testing.T) {
 	a := NewC4Analyzer(nil)
 
-	_, err := a.Analyze(nil)
+	_, err := a.Analyze(context.Background(), nil)
 	if err == nil {
 		t.Error("Analyze(nil) should return error")
 	}
 
-	_, err = a.Analyze([]*types.AnalysisTarget{})
+	_, err = a.Analyze(context.Background(), []*types.AnalysisTarget{})
 	if err == nil {
 		t.Error("Analyze([]) should return error")
 	}
@@ -310,7 +312,7 @@ func TestC4Analyzer_Category(t *testing.T) {
 	}
 
 	a := NewC4Analyzer(nil)
-	result, err := a.Analyze([]*types.AnalysisTarget{target})
+	result, err := a.Analyze(context.Background(), []*types.AnalysisTarget{target})
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
@@ -403,7 +405,7 @@ func TestC4Analyzer_Integration(t *testing.T) {
 	})
 	target.Files = goFiles
 
-	result, err := a.Analyze([]*types.AnalysisTarget{target})
+	result, err := a.Analyze(context.Background(), []*types.AnalysisTarget{target})
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
@@ -620,7 +622,7 @@ func TestC4Analyzer_RealRepo_MetricRanges(t *testing.T) {
 	})
 	target.Files = goFiles
 
-	result, err := a.Analyze([]*types.AnalysisTarget{target})
+	result, err := a.Analyze(context.Background(), []*types.AnalysisTarget{target})
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
@@ -652,3 +654,141 @@ func TestC4Analyzer_RealRepo_MetricRanges(t *testing.T) {
 		t.Errorf("PublicAPIs = %d, expected >= 0", c4.PublicAPIs)
 	}
 }
+
+func TestFindUndocumentedAPIs(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Skip("Tree-sitter not available:", err)
+	}
+	defer tsParser.Close()
+
+	tests := []struct {
+		name    string
+		lang    types.Language
+		content string
+		want    []APIDecl
+	}{
+		{
+			name: "go",
+			lang: types.LangGo,
+			content: `package lib
+
+// Documented does something.
+func Documented() {}
+
+func Bare() {}
+
+type Thing struct{}
+
+func (Thing) Method() {}
+`,
+			want: []APIDecl{
+				{Name: "Bare", Kind: "func", Line: 6},
+				{Name: "Thing", Kind: "type", Line: 8},
+				{Name: "Method", Kind: "func", Line: 10},
+			},
+		},
+		{
+			name: "python",
+			lang: types.LangPython,
+			content: `def documented():
+    """Doc."""
+
+def bare():
+    pass
+
+class Bare:
+    pass
+`,
+			want: []APIDecl{
+				{Name: "bare", Kind: "func", Line: 4},
+				{Name: "Bare", Kind: "class", Line: 7},
+			},
+		},
+		{
+			name: "typescript",
+			lang: types.LangTypeScript,
+			content: `/**
+ * Documented.
+ */
+export function documented(): void {}
+
+export const bareConst = 1;
+export interface BareIface {}
+`,
+			want: []APIDecl{
+				{Name: "bareConst", Kind: "const", Line: 6},
+				{Name: "BareIface", Kind: "interface", Line: 7},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindUndocumentedAPIs(types.SourceFile{
+				Path: "file", Language: tt.lang, Content: []byte(tt.content),
+			}, tsParser)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d undocumented APIs %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("api[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// fixedJudge scores every evaluation with the same score.
+type fixedJudge struct {
+	score int
+	calls int
+}
+
+func (j *fixedJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (agent.EvaluationResult, error) {
+	j.calls++
+	return agent.EvaluationResult{Score: j.score, Reason: "fixed"}, nil
+}
+
+func (j *fixedJudge) Info() agent.JudgeInfo {
+	temperature := 0.2
+	return agent.JudgeInfo{Backend: "openai", Model: "local-model", Temperature: &temperature}
+}
+
+func TestC4Analyzer_SetJudge(t *testing.T) {
+	dir := t.TempDir()
+	readme := "# Demo\n\nDemo does things.\n\n```go\nfmt.Println(\"hi\")\n```\n"
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	judge := &fixedJudge{score: 7}
+	a := NewC4Analyzer(nil)
+	a.SetJudge(judge)
+
+	result, err := a.Analyze(context.Background(), []*types.AnalysisTarget{{Language: types.LangGo, RootDir: dir}})
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	m := result.Metrics["c4"].(*types.C4Metrics)
+	if !m.LLMEnabled || m.ReadmeClarity != 7 || m.CrossRefCoherence != 7 {
+		t.Errorf("LLM metrics = enabled %v, clarity %d, coherence %d", m.LLMEnabled, m.ReadmeClarity, m.CrossRefCoherence)
+	}
+	if m.LLMJudge != "openai" || m.LLMModel != "local-model" || m.LLMTemperature == nil || *m.LLMTemperature != 0.2 {
+		t.Errorf("judge info = %q, %q, %v", m.LLMJudge, m.LLMModel, m.LLMTemperature)
+	}
+	if judge.calls == 0 {
+		t.Error("judge was not called")
+	}
+
+	// A nil evaluator disables LLM evaluation.
+	a.SetEvaluator(nil)
+	result, _ = a.Analyze(context.Background(), []*types.AnalysisTarget{{Language: types.LangGo, RootDir: dir}})
+	if result.Metrics["c4"].(*types.C4Metrics).LLMEnabled {
+		t.Error("LLM evaluation should be disabled after SetEvaluator(nil)")
+	}
+}
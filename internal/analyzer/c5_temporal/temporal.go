@@ -49,8 +49,9 @@ func (a *C5Analyzer) Name() string {
 	return "C5: Temporal Dynamics"
 }
 
-// Analyze runs the C5 temporal dynamics analysis on the repository.
-func (a *C5Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+// Analyze runs the C5 temporal dynamics analysis on the repository. If ctx is
+// cancelled while git log runs, the commits read so far are analyzed.
+func (a *C5Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	if len(targets) == 0 {
 		return nil, fmt.Errorf("no targets provided")
 	}
@@ -65,7 +66,7 @@ func (a *C5Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 		}, nil
 	}
 
-	metrics, err := analyzeGitHistory(rootDir, defaultAnalysisMonths)
+	metrics, err := analyzeGitHistory(ctx, rootDir, defaultAnalysisMonths)
 	if err != nil {
 		return nil, err
 	}
@@ -100,11 +101,12 @@ type fileChange struct {
 // - --no-merges: excludes merge commits to focus on authored changes
 //
 // Rename handling: Converts git's "{old => new}" notation to final path via resolveRenamePath.
-// Timeout: 25s context timeout with graceful degradation (returns partial results on timeout).
+// Timeout: 25s context timeout with graceful degradation (returns partial results on timeout
+// or when ctx is cancelled).
 // Binary files: Skipped (git shows "-" for added/deleted counts).
-func runGitLog(rootDir string, months int) ([]commitInfo, error) {
+func runGitLog(ctx context.Context, rootDir string, months int) ([]commitInfo, error) {
 	since := fmt.Sprintf("--since=%d months ago", months)
-	ctx, cancel := context.WithTimeout(context.Background(), gitLogTimeout)
+	ctx, cancel := context.WithTimeout(ctx, gitLogTimeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "git", "log",
@@ -236,8 +238,8 @@ func resolveRenamePath(path string) string {
 }
 
 // analyzeGitHistory parses git log and computes all C5 metrics.
-func analyzeGitHistory(rootDir string, months int) (*types.C5Metrics, error) {
-	commits, err := runGitLog(rootDir, months)
+func analyzeGitHistory(ctx context.Context, rootDir string, months int) (*types.C5Metrics, error) {
+	commits, err := runGitLog(ctx, rootDir, months)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package c5
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -44,12 +45,12 @@ func TestC5Analyzer_Name(t *testing.T) {
 func TestC5Analyzer_EmptyTargets(t *testing.T) {
 	a := NewC5Analyzer()
 
-	_, err := a.Analyze(nil)
+	_, err := a.Analyze(context.Background(), nil)
 	if err == nil {
 		t.Error("Analyze(nil) should return error")
 	}
 
-	_, err = a.Analyze([]*types.AnalysisTarget{})
+	_, err = a.Analyze(context.Background(), []*types.AnalysisTarget{})
 	if err == nil {
 		t.Error("Analyze([]) should return error")
 	}
@@ -58,7 +59,7 @@ func TestC5Analyzer_EmptyTargets(t *testing.T) {
 func TestC5Analyzer_NoGitDir(t *testing.T) {
 	dir := t.TempDir() // no .git inside
 	a := NewC5Analyzer()
-	result, err := a.Analyze(makeTarget(dir))
+	result, err := a.Analyze(context.Background(), makeTarget(dir))
 	if err != nil {
 		t.Fatalf("Analyze on non-git dir returned error: %v", err)
 	}
@@ -75,7 +76,7 @@ func TestC5Analyzer_NoGitDir(t *testing.T) {
 func TestC5Analyzer_Category(t *testing.T) {
 	root := findProjectRoot(t)
 	a := NewC5Analyzer()
-	result, err := a.Analyze(makeTarget(root))
+	result, err := a.Analyze(context.Background(), makeTarget(root))
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
@@ -96,7 +97,7 @@ func TestC5Analyzer_Category(t *testing.T) {
 func TestC5Analyzer_RealRepo(t *testing.T) {
 	root := findProjectRoot(t)
 	a := NewC5Analyzer()
-	result, err := a.Analyze(makeTarget(root))
+	result, err := a.Analyze(context.Background(), makeTarget(root))
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
@@ -129,7 +130,7 @@ func TestC5Analyzer_RealRepo(t *testing.T) {
 func TestC5Analyzer_RealRepo_MetricRanges(t *testing.T) {
 	root := findProjectRoot(t)
 	a := NewC5Analyzer()
-	result, err := a.Analyze(makeTarget(root))
+	result, err := a.Analyze(context.Background(), makeTarget(root))
 	if err != nil {
 		t.Fatalf("Analyze returned error: %v", err)
 	}
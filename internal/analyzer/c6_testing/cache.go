@@ -0,0 +1,153 @@
+package c6
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// c6FactsVersion identifies the testFileFacts format and the detection rules
+// producing it. Bump it whenever either changes so that stale cache entries are ignored.
+const c6FactsVersion = 1
+
+// testFileFacts are the C6 results that depend only on a single test file's content.
+type testFileFacts struct {
+	Tests          []types.TestFunctionMetric `json:"tests"`
+	HasExternalDep bool                       `json:"has_external_dep"`
+}
+
+// goFileFacts are the C6 results that depend only on a single Go file: its
+// length and its test functions, with their assertion counts and whether the
+// file imports an external dependency.
+type goFileFacts struct {
+	Lines          int                        `json:"lines"`
+	Tests          []types.TestFunctionMetric `json:"tests"`
+	HasExternalDep bool                       `json:"has_external_dep"`
+}
+
+// SetCache enables the per-file results cache. A nil store disables it.
+func (a *C6Analyzer) SetCache(store *cache.Store) {
+	a.cache = store
+}
+
+// GoResultsCached reports whether the C6 facts of every file of pkg are cached,
+// given the content of its files.
+func (a *C6Analyzer) GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	return shared.GoFactsCached(a.cache, "c6-go", c6FactsVersion, pkg, contents)
+}
+
+// goFacts returns the facts of each file of pkgs, using contents (absolute
+// path -> bytes) for cache keys where available. Packages loaded without
+// syntax contribute their cached facts.
+func (a *C6Analyzer) goFacts(pkgs []*parser.ParsedPackage, contents map[string][]byte) []shared.GoFileFacts[goFileFacts] {
+	var result []shared.GoFileFacts[goFileFacts]
+	for _, pkg := range pkgs {
+		files := shared.GoPackageFacts(a.cache, "c6-go", c6FactsVersion, pkg, contents, func(f *ast.File) goFileFacts {
+			return goComputeFacts(pkg.Fset, f)
+		})
+		for _, ff := range files {
+			for i := range ff.Facts.Tests {
+				ff.Facts.Tests[i].Package = pkg.PkgPath
+				ff.Facts.Tests[i].File = ff.File
+			}
+			result = append(result, ff)
+		}
+	}
+	return result
+}
+
+// goComputeFacts analyzes one parsed Go file.
+func goComputeFacts(fset *token.FileSet, f *ast.File) goFileFacts {
+	facts := goFileFacts{Lines: fset.Position(f.End()).Line}
+
+	imports := make(map[string]bool)
+	for _, imp := range f.Imports {
+		imports[strings.Trim(imp.Path.Value, `"`)] = true
+	}
+	facts.HasExternalDep = hasExternalDep(imports)
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name == nil || !isTestFunction(fn) {
+			continue
+		}
+		facts.Tests = append(facts.Tests, types.TestFunctionMetric{
+			Name:           fn.Name.Name,
+			Line:           fset.Position(fn.Pos()).Line,
+			AssertionCount: countAssertions(fn),
+		})
+	}
+	return facts
+}
+
+// treeSitterTestResult computes a Python or TypeScript target's test results.
+// Source files only contribute line counts and are never parsed; test files are
+// parsed only when their facts are not cached.
+func (a *C6Analyzer) treeSitterTestResult(target *types.AnalysisTarget) langTestResult {
+	lang := target.Language
+	isTest, compute := shared.IsTestFileByPath, pyTestFileFacts
+	if lang == types.LangTypeScript {
+		isTest, compute = shared.TsIsTestFile, tsTestFileFacts
+	}
+
+	var r langTestResult
+	testFileHasExtDep := make(map[string]bool)
+	for _, sf := range target.Files {
+		if sf.Class != types.ClassSource && sf.Class != types.ClassTest {
+			continue
+		}
+		content, err := parser.SourceContent(sf)
+		if err != nil {
+			continue
+		}
+		lines := bytes.Count(content, []byte("\n")) + 1
+		if !isTest(sf.RelPath) {
+			r.srcFileCount++
+			r.srcLOC += lines
+			continue
+		}
+		r.testFileCount++
+		r.testLOC += lines
+
+		facts, err := cache.Memo(a.cache, "c6-"+string(lang), c6FactsVersion, content, func() (testFileFacts, error) {
+			pf, err := a.tsParser.ParseSource(lang, sf, content)
+			if err != nil {
+				return testFileFacts{}, err
+			}
+			defer pf.Tree.Close()
+			return compute(pf), nil
+		})
+		if err != nil {
+			continue
+		}
+		for i := range facts.Tests {
+			facts.Tests[i].File = sf.RelPath
+		}
+		r.testFuncs = append(r.testFuncs, facts.Tests...)
+		testFileHasExtDep[sf.RelPath] = facts.HasExternalDep
+	}
+
+	r.isolation = vacuousIsolationScore
+	if len(r.testFuncs) > 0 {
+		r.isolation = calculateIsolationScore(r.testFuncs, testFileHasExtDep)
+	}
+	return r
+}
+
+// pyTestFileFacts analyzes one parsed Python test file.
+func pyTestFileFacts(f *parser.ParsedTreeSitterFile) testFileFacts {
+	tests, _, _ := pyDetectTests([]*parser.ParsedTreeSitterFile{f})
+	return testFileFacts{Tests: tests, HasExternalDep: pyFileHasExternalDep(f.Tree.RootNode(), f.Content)}
+}
+
+// tsTestFileFacts analyzes one parsed TypeScript test file.
+func tsTestFileFacts(f *parser.ParsedTreeSitterFile) testFileFacts {
+	tests, _, _ := tsDetectTests([]*parser.ParsedTreeSitterFile{f})
+	return testFileFacts{Tests: tests, HasExternalDep: tsFileHasExternalDep(f.Tree.RootNode(), f.Content)}
+}
@@ -137,70 +137,59 @@ func pyAnalyzeIsolation(files []*parser.ParsedTreeSitterFile, testFuncs []types.
 		if !shared.IsTestFileByPath(f.RelPath) {
 			continue
 		}
+		testFileHasExtDep[f.RelPath] = pyFileHasExternalDep(f.Tree.RootNode(), f.Content)
+	}
 
-		root := f.Tree.RootNode()
-		hasExtDep := false
+	return calculateIsolationScore(testFuncs, testFileHasExtDep)
+}
 
-		shared.WalkTree(root, func(node *tree_sitter.Node) {
-			kind := node.Kind()
-			if kind != "import_statement" && kind != "import_from_statement" {
-				return
-			}
+// pyFileHasExternalDep checks if a Python file imports any external dependencies.
+func pyFileHasExternalDep(root *tree_sitter.Node, content []byte) bool {
+	hasExtDep := false
 
-			// Extract module name
-			var modName string
-			switch kind {
-			case "import_statement":
-				for i := uint(0); i < node.ChildCount(); i++ {
-					child := node.Child(i)
-					if child != nil && (child.Kind() == "dotted_name" || child.Kind() == "aliased_import") {
-						if child.Kind() == "aliased_import" {
-							nameNode := child.ChildByFieldName("name")
-							if nameNode != nil {
-								modName = shared.NodeText(nameNode, f.Content)
-							}
-						} else {
-							modName = shared.NodeText(child, f.Content)
+	shared.WalkTree(root, func(node *tree_sitter.Node) {
+		kind := node.Kind()
+		if kind != "import_statement" && kind != "import_from_statement" {
+			return
+		}
+
+		// Extract module name
+		var modName string
+		switch kind {
+		case "import_statement":
+			for i := uint(0); i < node.ChildCount(); i++ {
+				child := node.Child(i)
+				if child != nil && (child.Kind() == "dotted_name" || child.Kind() == "aliased_import") {
+					if child.Kind() == "aliased_import" {
+						nameNode := child.ChildByFieldName("name")
+						if nameNode != nil {
+							modName = shared.NodeText(nameNode, content)
 						}
-					}
-				}
-			case "import_from_statement":
-				for i := uint(0); i < node.ChildCount(); i++ {
-					child := node.Child(i)
-					if child != nil && (child.Kind() == "dotted_name" || child.Kind() == "relative_import") {
-						modName = shared.NodeText(child, f.Content)
-						break
+					} else {
+						modName = shared.NodeText(child, content)
 					}
 				}
 			}
-
-			if modName == "" {
-				return
-			}
-
-			// Check against known external deps
-			topLevel := strings.Split(modName, ".")[0]
-			if pyExternalDepModules[topLevel] {
-				hasExtDep = true
+		case "import_from_statement":
+			for i := uint(0); i < node.ChildCount(); i++ {
+				child := node.Child(i)
+				if child != nil && (child.Kind() == "dotted_name" || child.Kind() == "relative_import") {
+					modName = shared.NodeText(child, content)
+					break
+				}
 			}
-		})
-
-		testFileHasExtDep[f.RelPath] = hasExtDep
-	}
+		}
 
-	// Count isolated vs total test functions
-	totalTests := len(testFuncs)
-	isolatedTests := 0
-	for _, tf := range testFuncs {
-		if !testFileHasExtDep[tf.File] {
-			isolatedTests++
+		if modName == "" {
+			return
 		}
-	}
 
-	return float64(isolatedTests) / float64(totalTests) * toPercentC6
-}
+		// Check against known external deps
+		topLevel := strings.Split(modName, ".")[0]
+		if pyExternalDepModules[topLevel] {
+			hasExtDep = true
+		}
+	})
 
-// pyCountLOC counts test LOC and source LOC for Python files.
-func pyCountLOC(files []*parser.ParsedTreeSitterFile) (testLOC, srcLOC int) {
-	return countLOCByTestFunc(files, shared.IsTestFileByPath)
+	return hasExtDep
 }
@@ -1,6 +1,7 @@
 package c6
 
 import (
+	"context"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -19,7 +20,7 @@ func testdataDir() string {
 func loadTestPackages(t *testing.T, subdir string) []*parser.ParsedPackage {
 	t.Helper()
 	p := &parser.GoPackagesParser{}
-	pkgs, err := p.Parse(filepath.Join(testdataDir(), subdir))
+	pkgs, err := p.Parse(context.Background(), filepath.Join(testdataDir(), subdir))
 	if err != nil {
 		t.Fatalf("failed to parse %s: %v", subdir, err)
 	}
@@ -221,7 +222,7 @@ func TestPyC6Integration(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
@@ -255,7 +256,7 @@ func TestC6_GoRegressionWithNewConstructor(t *testing.T) {
 
 	analyzer := NewC6Analyzer(nil)
 	analyzer.SetGoPackages(pkgs)
-	result, err := analyzer.Analyze(nil)
+	result, err := analyzer.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze failed: %v", err)
 	}
@@ -2,8 +2,8 @@
 package c6
 
 import (
+	"context"
 	"bufio"
-	"bytes"
 	"encoding/xml"
 	"fmt"
 	"go/ast"
@@ -11,22 +11,26 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer/shared"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 	"golang.org/x/tools/cover"
 )
 
-// countLOCByTestFunc counts test LOC and source LOC using the given test file predicate.
-func countLOCByTestFunc(files []*parser.ParsedTreeSitterFile, isTestFile func(string) bool) (testLOC, srcLOC int) {
-	for _, f := range files {
-		lines := bytes.Count(f.Content, []byte("\n")) + 1
-		if isTestFile(f.RelPath) {
-			testLOC += lines
-		} else {
-			srcLOC += lines
+// calculateIsolationScore returns the percentage of test functions whose file has
+// no external dependency. testFuncs must not be empty.
+func calculateIsolationScore(testFuncs []types.TestFunctionMetric, testFileHasExtDep map[string]bool) float64 {
+	totalTests := len(testFuncs)
+	isolatedTests := 0
+
+	for _, tf := range testFuncs {
+		if !testFileHasExtDep[tf.File] {
+			isolatedTests++
 		}
 	}
-	return
+
+	return float64(isolatedTests) / float64(totalTests) * toPercentC6
 }
 
 // updateAssertionDensity recomputes assertion density from all test functions in metrics.
@@ -71,6 +75,7 @@ const (
 type C6Analyzer struct {
 	pkgs     []*parser.ParsedPackage
 	tsParser *parser.TreeSitterParser
+	cache    *cache.Store // nil disables the per-file results cache
 }
 
 // NewC6Analyzer creates a C6Analyzer with Tree-sitter parser for multi-language analysis.
@@ -89,11 +94,11 @@ func (a *C6Analyzer) SetGoPackages(pkgs []*parser.ParsedPackage) {
 }
 
 // Analyze runs all 5 C6 sub-metrics over the parsed packages.
-func (a *C6Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (a *C6Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	metrics := &types.C6Metrics{}
 
 	if a.pkgs != nil {
-		goMetrics, err := a.analyzeGoC6()
+		goMetrics, err := a.analyzeGoC6(shared.GoFileContents(targets))
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +106,9 @@ func (a *C6Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 	}
 
 	for _, target := range targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		a.analyzeTarget(target, metrics)
 	}
 
@@ -133,29 +141,8 @@ func (a *C6Analyzer) analyzeTarget(target *types.AnalysisTarget, metrics *types.
 	}
 
 	switch target.Language {
-	case types.LangPython:
-		parsed, err := a.tsParser.ParseTargetFiles(target)
-		if err != nil {
-			return
-		}
-		defer parser.CloseAll(parsed)
-		testFuncs, testFileCount, srcFileCount := pyDetectTests(parsed)
-		testLOC, srcLOC := pyCountLOC(parsed)
-		isolation := pyAnalyzeIsolation(parsed, testFuncs)
-		r := langTestResult{testFuncs, testFileCount, srcFileCount, testLOC, srcLOC, isolation}
-		mergeTestResults(metrics, r)
-
-	case types.LangTypeScript:
-		parsed, err := a.tsParser.ParseTargetFiles(target)
-		if err != nil {
-			return
-		}
-		defer parser.CloseAll(parsed)
-		testFuncs, testFileCount, srcFileCount := tsDetectTests(parsed)
-		testLOC, srcLOC := tsCountLOC(parsed)
-		isolation := tsAnalyzeIsolation(parsed, testFuncs)
-		r := langTestResult{testFuncs, testFileCount, srcFileCount, testLOC, srcLOC, isolation}
-		mergeTestResults(metrics, r)
+	case types.LangPython, types.LangTypeScript:
+		mergeTestResults(metrics, a.treeSitterTestResult(target))
 	}
 
 	a.mergeCoverage(target, metrics)
@@ -205,8 +192,9 @@ func (a *C6Analyzer) mergeCoverage(target *types.AnalysisTarget, metrics *types.
 	}
 }
 
-// analyzeGoC6 runs Go-specific C6 analysis.
-func (a *C6Analyzer) analyzeGoC6() (*types.C6Metrics, error) {
+// analyzeGoC6 runs Go-specific C6 analysis. contents maps absolute file paths
+// to content already read for the targets.
+func (a *C6Analyzer) analyzeGoC6(contents map[string][]byte) (*types.C6Metrics, error) {
 	pkgs := a.pkgs
 	var srcPkgs, testPkgs []*parser.ParsedPackage
 	for _, pkg := range pkgs {
@@ -216,12 +204,14 @@ func (a *C6Analyzer) analyzeGoC6() (*types.C6Metrics, error) {
 			srcPkgs = append(srcPkgs, pkg)
 		}
 	}
+	srcFiles := a.goFacts(srcPkgs, contents)
+	testFiles := a.goFacts(testPkgs, contents)
 
 	metrics := &types.C6Metrics{}
 
 	metrics.TestFileCount = countFiles(testPkgs)
 	metrics.SourceFileCount = countFiles(srcPkgs)
-	metrics.TestToCodeRatio = calculateTestRatio(srcPkgs, testPkgs)
+	metrics.TestToCodeRatio = calculateTestRatio(countLOC(srcFiles), countLOC(testFiles))
 
 	rootDir := deriveRootDir(pkgs)
 	if rootDir != "" {
@@ -236,8 +226,8 @@ func (a *C6Analyzer) analyzeGoC6() (*types.C6Metrics, error) {
 		metrics.CoverageSource = "none"
 	}
 
-	metrics.TestIsolation = analyzeIsolation(testPkgs, metrics)
-	analyzeAssertions(testPkgs, metrics)
+	metrics.TestIsolation = analyzeIsolation(testFiles)
+	analyzeAssertions(testFiles, metrics)
 
 	return metrics, nil
 }
@@ -256,26 +246,18 @@ func countFiles(pkgs []*parser.ParsedPackage) int {
 }
 
 // calculateTestRatio calculates test LOC / source LOC.
-func calculateTestRatio(srcPkgs, testPkgs []*parser.ParsedPackage) float64 {
-	srcLOC := countLOC(srcPkgs)
-	testLOC := countLOC(testPkgs)
-
+func calculateTestRatio(srcLOC, testLOC int) float64 {
 	if srcLOC == 0 {
 		return 0
 	}
 	return float64(testLOC) / float64(srcLOC)
 }
 
-// countLOC counts total lines of code across packages using fset positions.
-func countLOC(pkgs []*parser.ParsedPackage) int {
+// countLOC counts total lines of code across files.
+func countLOC(files []shared.GoFileFacts[goFileFacts]) int {
 	total := 0
-	for _, pkg := range pkgs {
-		for _, f := range pkg.Syntax {
-			if pkg.Fset != nil {
-				endPos := pkg.Fset.Position(f.End())
-				total += endPos.Line
-			}
-		}
+	for _, f := range files {
+		total += f.Facts.Lines
 	}
 	return total
 }
@@ -429,52 +411,16 @@ var externalDepPackages = map[string]bool{
 	"net/smtp":     true,
 }
 
-// analyzeIsolation checks test packages for external dependency imports.
-// Returns percentage of isolated tests (0-100).
-func analyzeIsolation(testPkgs []*parser.ParsedPackage, metrics *types.C6Metrics) float64 {
+// analyzeIsolation checks the test functions of test package files for
+// external dependency imports. Returns percentage of isolated tests (0-100).
+func analyzeIsolation(testFiles []shared.GoFileFacts[goFileFacts]) float64 {
 	totalTests := 0
 	isolatedTests := 0
 
-	for _, pkg := range testPkgs {
-		// Collect file-level imports
-		fileImports := make(map[string]map[string]bool) // filename -> set of import paths
-		for _, f := range pkg.Syntax {
-			fname := ""
-			if pkg.Fset != nil {
-				fname = pkg.Fset.Position(f.Pos()).Filename
-			}
-			imports := make(map[string]bool)
-			for _, imp := range f.Imports {
-				path := strings.Trim(imp.Path.Value, `"`)
-				imports[path] = true
-			}
-			fileImports[fname] = imports
-		}
-
-		// Walk AST to find test functions
-		for _, f := range pkg.Syntax {
-			fname := ""
-			if pkg.Fset != nil {
-				fname = pkg.Fset.Position(f.Pos()).Filename
-			}
-			imports := fileImports[fname]
-
-			for _, decl := range f.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok || fn.Name == nil {
-					continue
-				}
-				if !isTestFunction(fn) {
-					continue
-				}
-
-				totalTests++
-				hasExtDep := hasExternalDep(imports)
-
-				if !hasExtDep {
-					isolatedTests++
-				}
-			}
+	for _, f := range testFiles {
+		totalTests += len(f.Facts.Tests)
+		if !f.Facts.HasExternalDep {
+			isolatedTests += len(f.Facts.Tests)
 		}
 	}
 
@@ -552,47 +498,21 @@ var testifyAssertionMethods = map[string]bool{
 	"ErrorAs":  true,
 }
 
-// analyzeAssertions counts assertions per test function and populates metrics.
-func analyzeAssertions(testPkgs []*parser.ParsedPackage, metrics *types.C6Metrics) {
+// analyzeAssertions collects the test functions of test package files with
+// their assertion counts and populates metrics.
+func analyzeAssertions(testFiles []shared.GoFileFacts[goFileFacts], metrics *types.C6Metrics) {
 	var totalAssertions int
 	maxAssertions := 0
 	maxEntity := ""
 
-	for _, pkg := range testPkgs {
-		for _, f := range pkg.Syntax {
-			fname := ""
-			if pkg.Fset != nil {
-				fname = pkg.Fset.Position(f.Pos()).Filename
-			}
+	for _, f := range testFiles {
+		for _, tfm := range f.Facts.Tests {
+			totalAssertions += tfm.AssertionCount
+			metrics.TestFunctions = append(metrics.TestFunctions, tfm)
 
-			for _, decl := range f.Decls {
-				fn, ok := decl.(*ast.FuncDecl)
-				if !ok || fn.Name == nil {
-					continue
-				}
-				if !isTestFunction(fn) {
-					continue
-				}
-
-				count := countAssertions(fn)
-				totalAssertions += count
-
-				tfm := types.TestFunctionMetric{
-					Package:        pkg.PkgPath,
-					Name:           fn.Name.Name,
-					File:           fname,
-					AssertionCount: count,
-				}
-				if pkg.Fset != nil {
-					tfm.Line = pkg.Fset.Position(fn.Pos()).Line
-				}
-
-				metrics.TestFunctions = append(metrics.TestFunctions, tfm)
-
-				if count > maxAssertions {
-					maxAssertions = count
-					maxEntity = fn.Name.Name
-				}
+			if tfm.AssertionCount > maxAssertions {
+				maxAssertions = tfm.AssertionCount
+				maxEntity = tfm.Name
 			}
 		}
 	}
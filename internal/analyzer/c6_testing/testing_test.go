@@ -1,6 +1,7 @@
 package c6
 
 import (
+	"context"
 	"go/ast"
 	goparser "go/parser"
 	"go/token"
@@ -81,7 +82,7 @@ func TestC6_TestDetection(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -113,7 +114,7 @@ func TestC6_TestToCodeRatio(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -255,7 +256,7 @@ func TestWithHTTP(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -295,7 +296,7 @@ func TestBar(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -345,7 +346,7 @@ func TestWithAssert(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -375,7 +376,7 @@ func TestC6_ResultCategory(t *testing.T) {
 
 	a := &C6Analyzer{}
 	a.SetGoPackages(pkgs)
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("Analyze: %v", err)
 	}
@@ -223,7 +223,7 @@ func tsAnalyzeIsolation(files []*parser.ParsedTreeSitterFile, testFuncs []types.
 	}
 
 	testFileHasExtDep := tsCheckTestFilesForExternalDeps(files)
-	return tsCalculateIsolationScore(testFuncs, testFileHasExtDep)
+	return calculateIsolationScore(testFuncs, testFileHasExtDep)
 }
 
 // tsCheckTestFilesForExternalDeps checks each test file for external dependencies.
@@ -294,22 +294,3 @@ func tsExtractTopLevelModule(modPath string) string {
 
 	return modPath[:idx]
 }
-
-// tsCalculateIsolationScore counts isolated vs total test functions.
-func tsCalculateIsolationScore(testFuncs []types.TestFunctionMetric, testFileHasExtDep map[string]bool) float64 {
-	totalTests := len(testFuncs)
-	isolatedTests := 0
-
-	for _, tf := range testFuncs {
-		if !testFileHasExtDep[tf.File] {
-			isolatedTests++
-		}
-	}
-
-	return float64(isolatedTests) / float64(totalTests) * toPercentC6
-}
-
-// tsCountLOC counts test LOC and source LOC for TypeScript files.
-func tsCountLOC(files []*parser.ParsedTreeSitterFile) (testLOC, srcLOC int) {
-	return countLOCByTestFunc(files, shared.TsIsTestFile)
-}
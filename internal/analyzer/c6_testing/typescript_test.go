@@ -1,6 +1,7 @@
 package c6
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
@@ -207,7 +208,7 @@ func TestTsC6Integration(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("Analyze() error: %v", err)
 	}
@@ -4,46 +4,81 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"time"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/internal/stats"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
 // C7 analysis constants.
 const (
-	c7CostRatePerMTok  = 5.0       // Sonnet 4.5 blended rate $/MTok
-	c7TokensPerMillion = 1_000_000 // Token-to-millions conversion
-	c7CharsPerToken    = 4         // Approximate characters per token
-
-	// MECE metric weights (duplicated from scoring config for quick display).
-	c7WeightM1 = 0.20 // Task Execution Consistency
-	c7WeightM2 = 0.25 // Code Behavior Comprehension
-	c7WeightM3 = 0.25 // Cross-File Navigation
-	c7WeightM4 = 0.15 // Identifier Interpretability
-	c7WeightM5 = 0.15 // Documentation Accuracy Detection
+	c7CharsPerToken = 4 // Approximate characters per token
+
+	// MECE metric weights (duplicated from scoring config for quick display):
+	// M1-M5 keep their original relative weights, M6 and M7 add 0.15 each,
+	// and all are normalized to a total of 1.
+	c7WeightSum = 1.30
+	c7WeightM1  = 0.20 / c7WeightSum // Task Execution Consistency
+	c7WeightM2  = 0.25 / c7WeightSum // Code Behavior Comprehension
+	c7WeightM3  = 0.25 / c7WeightSum // Cross-File Navigation
+	c7WeightM4  = 0.15 / c7WeightSum // Identifier Interpretability
+	c7WeightM5  = 0.15 / c7WeightSum // Documentation Accuracy Detection
+	c7WeightM6  = 0.15 / c7WeightSum // Change Success
+	c7WeightM7  = 0.15 / c7WeightSum // Feature Localization
 )
 
 // C7Analyzer implements the pipeline.Analyzer interface for C7: Agent Evaluation.
 type C7Analyzer struct {
-	evaluator   *agent.Evaluator
-	enabled     bool      // only runs if explicitly enabled
-	debug       bool      // debug mode flag
-	debugWriter io.Writer // where debug output goes (io.Discard or os.Stderr)
-	debugDir    string    // directory for response persistence and replay
+	evaluator      *agent.Evaluator
+	backend        agent.Backend // agent under evaluation; nil means the Claude CLI
+	judge          agent.Judge   // scores task rubrics and judge-mode M2-M5; nil means the evaluator
+	enabled        bool          // only runs if explicitly enabled
+	debug          bool          // debug mode flag
+	debugWriter    io.Writer     // where debug output goes (io.Discard or os.Stderr)
+	debugDir       string        // directory for response persistence and replay
+	progressWriter io.Writer     // where the live progress display goes (io.Discard or os.Stderr)
+
+	// Ground truth for scoring: Go packages and the Tree-sitter parser for
+	// Python/TypeScript import graphs (tsParser may be nil).
+	pkgs     []*parser.ParsedPackage
+	tsParser *parser.TreeSitterParser
+
+	metricOptions metrics.Options // run and sample counts
+	llmCache      *cache.LLMStore // nil disables the LLM response cache
+	budget        *agent.Budget   // LLM budget shared with other analyzers; nil for no limit
+	limits        agent.Limits    // concurrency, rate and retry limits of agent calls
 }
 
 // NewC7Analyzer creates a C7Analyzer. It's disabled by default.
-// debugWriter defaults to io.Discard to prevent nil writer if SetDebug is never called.
-func NewC7Analyzer() *C7Analyzer {
+// debugWriter and the progress display default to io.Discard, so nothing is
+// written unless SetDebug or SetProgressWriter is called.
+func NewC7Analyzer(tsParser *parser.TreeSitterParser) *C7Analyzer {
 	return &C7Analyzer{
-		enabled:     false,
-		debugWriter: io.Discard,
+		enabled:        false,
+		debugWriter:    io.Discard,
+		progressWriter: io.Discard,
+		tsParser:       tsParser,
+		limits:         agent.Limits{MaxRetries: agent.DefaultMaxRetries},
 	}
 }
 
+// SetGoPackages stores Go-specific parsed packages, used to derive the
+// ground truth that navigation answers are scored against.
+func (a *C7Analyzer) SetGoPackages(pkgs []*parser.ParsedPackage) {
+	a.pkgs = pkgs
+}
+
+// GoResultsCached reports whether Analyze can do without the syntax and type
+// information of pkg. It can only while C7 is disabled: the ground truth of
+// an evaluation is derived from the parsed packages, not from the cache.
+func (a *C7Analyzer) GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	return a.evaluator == nil && a.backend == nil
+}
+
 // Enable activates C7 analysis with the given CLI evaluator.
 func (a *C7Analyzer) Enable(evaluator *agent.Evaluator) {
 	a.evaluator = evaluator
@@ -51,7 +86,8 @@ func (a *C7Analyzer) Enable(evaluator *agent.Evaluator) {
 }
 
 // SetEvaluator sets the evaluator for C7 analysis.
-// Setting a non-nil evaluator auto-enables C7; setting nil disables it.
+// Setting a non-nil evaluator auto-enables C7; setting nil disables it,
+// including any backend set with SetBackend.
 // This method matches C4's pattern for LLM control.
 func (a *C7Analyzer) SetEvaluator(eval *agent.Evaluator) {
 	a.evaluator = eval
@@ -59,15 +95,60 @@ func (a *C7Analyzer) SetEvaluator(eval *agent.Evaluator) {
 		a.enabled = true
 	} else {
 		a.enabled = false
+		a.backend = nil
 	}
 }
 
+// SetBackend selects the agent backend C7 evaluates and enables C7, so that
+// agents other than the Claude CLI can be evaluated without it installed.
+func (a *C7Analyzer) SetBackend(b agent.Backend) {
+	a.backend = b
+	a.enabled = b != nil || a.evaluator != nil
+}
+
+// SetMetricOptions configures the metric instances used by Analyze.
+func (a *C7Analyzer) SetMetricOptions(opts metrics.Options) {
+	a.metricOptions = opts
+}
+
+// SetJudge selects the judge for the rubrics of user-defined tasks and for
+// M2-M5 in judge scoring mode. Without one, the Claude CLI evaluator is used
+// when available.
+func (a *C7Analyzer) SetJudge(j agent.Judge) {
+	a.judge = j
+}
+
+// SetLLMCache enables the LLM response cache for agent and judge calls. A nil
+// store disables it.
+func (a *C7Analyzer) SetLLMCache(store *cache.LLMStore) {
+	a.llmCache = store
+}
+
+// SetBudget limits the agent and judge calls with budget, shared with the
+// other analyzers calling an LLM. Samples are planned against what remains;
+// nil removes the limit.
+func (a *C7Analyzer) SetBudget(budget *agent.Budget) {
+	a.budget = budget
+}
+
+// SetLimits sets the concurrency, rate and retry limits of agent calls. By
+// default calls are unlimited and retried agent.DefaultMaxRetries times.
+func (a *C7Analyzer) SetLimits(limits agent.Limits) {
+	a.limits = limits
+}
+
 // SetDebug enables debug mode with the given writer for diagnostic output.
 func (a *C7Analyzer) SetDebug(enabled bool, w io.Writer) {
 	a.debug = enabled
 	a.debugWriter = w
 }
 
+// SetProgressWriter sets where the live progress display is drawn. It is only
+// drawn when w is a terminal.
+func (a *C7Analyzer) SetProgressWriter(w io.Writer) {
+	a.progressWriter = w
+}
+
 // SetDebugDir configures the directory for response persistence and replay.
 func (a *C7Analyzer) SetDebugDir(dir string) {
 	a.debugDir = dir
@@ -78,10 +159,11 @@ func (a *C7Analyzer) Name() string {
 	return "C7: Agent Evaluation"
 }
 
-// Analyze runs C7 agent evaluation using 5 MECE metrics in parallel.
-func (a *C7Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
-	// Check if LLM features are disabled (evaluator is nil)
-	if a.evaluator == nil {
+// Analyze runs C7 agent evaluation, running the metrics in parallel. Once ctx
+// is done, running agents are stopped and the samples finished so far scored.
+func (a *C7Analyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	// Check if LLM features are disabled (no evaluator and no backend)
+	if a.evaluator == nil && a.backend == nil {
 		return a.disabledResult(), nil
 	}
 
@@ -90,20 +172,30 @@ func (a *C7Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 	}
 	rootDir := targets[0].RootDir
 
-	// Check for Claude CLI
-	if err := agent.CheckClaudeCLI(); err != nil {
+	// Check that the agent backend is usable
+	backend := a.backend
+	if backend == nil {
+		backend, _ = agent.NewBackend(agent.BackendConfig{})
+	}
+	if err := backend.Check(); err != nil {
 		return a.disabledResult(), nil
 	}
 
 	// Create isolated workspace
-	workDir, cleanup, err := agent.CreateWorkspace(rootDir)
+	workDir, cleanup, err := agent.CreateWorkspace(ctx, rootDir)
 	if err != nil {
 		return nil, fmt.Errorf("create workspace: %w", err)
 	}
 	defer cleanup()
 
 	// Initialize metrics
-	allMetrics := metrics.AllMetrics()
+	opts := a.metricOptions
+	if a.judge != nil {
+		opts.Judge = rubricJudge{judge: agent.NewCachedJudge(agent.NewBudgetJudge(a.judge, a.budget), a.llmCache)}
+	} else if a.evaluator != nil {
+		opts.Judge = rubricJudge{judge: agent.NewCachedJudge(agent.NewBudgetJudge(a.evaluator, a.budget), a.llmCache)}
+	}
+	allMetrics := metrics.NewMetrics(opts)
 	metricIDs := make([]string, len(allMetrics))
 	metricNames := make([]string, len(allMetrics))
 	for i, m := range allMetrics {
@@ -112,30 +204,40 @@ func (a *C7Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 	}
 
 	// Create progress display
-	progress := agent.NewC7Progress(os.Stderr, metricIDs, metricNames)
+	progress := agent.NewC7Progress(a.progressWriter, metricIDs, metricNames)
 	progress.Start()
 	defer progress.Stop()
 
 	// Run metrics in parallel
-	ctx := context.Background()
 	startTime := time.Now()
 
-	// Determine executor: replay from files or live CLI
-	var executor metrics.Executor
+	// Determine executor: replay from files or the live backend
+	throttled := agent.NewThrottledBackend(backend, a.limits)
+	cached := agent.NewCachedBackend(agent.NewBudgetBackend(throttled, a.budget), a.llmCache)
+	var executor metrics.Executor = cached
+	replaying := false
 	if a.debugDir != "" {
 		responses, loadErr := agent.LoadResponses(a.debugDir)
 		if loadErr == nil && len(responses) > 0 {
 			fmt.Fprintf(a.debugWriter, "[C7 DEBUG] Replay mode: loading %d responses from %s\n", len(responses), a.debugDir)
 			executor = agent.NewReplayExecutor(responses)
+			replaying = true
 		} else {
 			fmt.Fprintf(a.debugWriter, "[C7 DEBUG] Capture mode: responses will be saved to %s\n", a.debugDir)
 		}
 	}
 
-	result := agent.RunMetricsParallel(ctx, workDir, targets, progress, executor)
-
-	// Save responses for future replay (only when in capture mode, i.e. executor was nil)
-	if a.debugDir != "" && executor == nil {
+	truth := newStaticGroundTruth(a.pkgs, a.tsParser, targets, workDir != rootDir)
+	result := agent.RunMetricsParallel(ctx, workDir, targets, progress, executor, agent.RunOptions{
+		Metrics: allMetrics,
+		Truth:   truth,
+		Budget:  a.budget,
+		Repeats: opts.Repeats,
+	})
+
+	// Save responses for future replay (only when in capture mode and
+	// complete, so a cut-short scan leaves no failed responses to replay)
+	if a.debugDir != "" && !replaying && ctx.Err() == nil {
 		if saveErr := agent.SaveResponses(a.debugDir, result.Results); saveErr != nil {
 			fmt.Fprintf(a.debugWriter, "[C7 DEBUG] Warning: failed to save responses: %v\n", saveErr)
 		} else {
@@ -145,6 +247,7 @@ func (a *C7Analyzer) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisRe
 
 	// Build C7Metrics from results
 	c7metrics := a.buildMetrics(result, startTime)
+	c7metrics.CachedResponses = cached.Hits()
 
 	return &types.AnalysisResult{
 		Name:     "C7: Agent Evaluation",
@@ -171,6 +274,7 @@ func (a *C7Analyzer) buildMetrics(result agent.ParallelResult, startTime time.Ti
 
 	a.processMetricResults(m, result.Results)
 	m.MECEScore = a.calculateWeightedScore(m)
+	a.summarizeRepeats(m)
 	a.populateTokensAndCost(m, result, startTime)
 
 	return m
@@ -178,11 +282,17 @@ func (a *C7Analyzer) buildMetrics(result agent.ParallelResult, startTime time.Ti
 
 // processMetricResults processes each metric result and populates C7Metrics fields.
 func (a *C7Analyzer) processMetricResults(m *types.C7Metrics, results []metrics.MetricResult) {
+	var samples []metrics.SampleResult
 	for _, mr := range results {
 		metricResult := a.buildMetricResult(mr)
 		m.MetricResults = append(m.MetricResults, metricResult)
 		a.assignIndividualScore(m, mr)
+		samples = append(samples, mr.Samples...)
+	}
+	for _, s := range samples {
+		m.Retries += s.Retries
 	}
+	m.Navigation = summarizeNavigation(samples)
 }
 
 // buildMetricResult constructs a C7MetricResult from a MetricResult.
@@ -200,11 +310,82 @@ func (a *C7Analyzer) buildMetricResult(mr metrics.MetricResult) types.C7MetricRe
 		metricResult.Status = "error"
 		metricResult.Reasoning = mr.Error
 	}
+	if mr.Skipped != "" {
+		metricResult.Status = "skipped"
+		metricResult.Reasoning = "skipped: " + mr.Skipped
+	}
+
+	if len(mr.RepeatScores) > 1 {
+		metricResult.RepeatScores = mr.RepeatScores
+		metricResult.Interval = stats.Interval(completedRepeats(mr.RepeatScores))
+	}
 
 	a.extractSampleData(&metricResult, mr.Samples)
+	metricResult.Navigation = summarizeNavigation(mr.Samples)
 	return metricResult
 }
 
+// summarizeNavigation averages the navigation effort of the samples whose
+// tool calls were traced; nil if there are none.
+func summarizeNavigation(samples []metrics.SampleResult) *types.C7Navigation {
+	var nav types.C7Navigation
+	for _, s := range samples {
+		if s.ToolCalls == nil {
+			continue
+		}
+		st := metrics.SummarizeToolCalls(s.ToolCalls)
+		nav.Samples++
+		nav.ToolCalls += float64(st.Calls)
+		nav.FilesRead += float64(st.FilesRead)
+		nav.RedundantReads += float64(st.RedundantReads)
+	}
+	if nav.Samples == 0 {
+		return nil
+	}
+	n := float64(nav.Samples)
+	nav.ToolCalls /= n
+	nav.FilesRead /= n
+	nav.RedundantReads /= n
+	return &nav
+}
+
+// completedRepeats returns the scores of the repeats that did not fail.
+func completedRepeats(scores []int) []float64 {
+	var values []float64
+	for _, s := range scores {
+		if s != types.FailedRepeat {
+			values = append(values, float64(s))
+		}
+	}
+	return values
+}
+
+// summarizeRepeats computes the MECE score of every repeat and replaces
+// MECEScore with their mean and its confidence interval. The per-metric
+// scores are already the means over the repeats.
+func (a *C7Analyzer) summarizeRepeats(m *types.C7Metrics) {
+	m.Repeats = max(a.metricOptions.Repeats, 1)
+	if m.Repeats == 1 {
+		return
+	}
+	var mece []float64
+	for r := 0; r < m.Repeats; r++ {
+		scores := make(map[string]int, len(m.MetricResults))
+		for _, mr := range m.MetricResults {
+			if r < len(mr.RepeatScores) && mr.RepeatScores[r] != types.FailedRepeat {
+				scores[mr.MetricID] = mr.RepeatScores[r]
+			}
+		}
+		if len(scores) > 0 {
+			mece = append(mece, a.weightedScore(scores))
+		}
+	}
+	m.MECEInterval = stats.Interval(mece)
+	if m.MECEInterval != nil {
+		m.MECEScore = m.MECEInterval.Mean
+	}
+}
+
 // extractSampleData extracts sample descriptions and debug data from samples.
 func (a *C7Analyzer) extractSampleData(metricResult *types.C7MetricResult, samples []metrics.SampleResult) {
 	for _, s := range samples {
@@ -219,8 +400,35 @@ func (a *C7Analyzer) extractSampleData(metricResult *types.C7MetricResult, sampl
 			Duration:    s.Duration.Seconds(),
 			ScoreTrace:  convertScoreTrace(s.ScoreTrace),
 			Error:       s.Error,
+			ToolTrace:   convertToolTrace(s.ToolCalls),
+			Retries:     s.Retries,
+		})
+	}
+}
+
+// convertToolTrace converts a tool-call trace to its report form; nil if the
+// backend did not trace tool calls.
+func convertToolTrace(calls []metrics.ToolCall) *types.C7ToolTrace {
+	if calls == nil {
+		return nil
+	}
+	st := metrics.SummarizeToolCalls(calls)
+	trace := &types.C7ToolTrace{
+		Calls:          make([]types.C7ToolCall, 0, len(calls)),
+		FilesRead:      st.FilesRead,
+		RedundantReads: st.RedundantReads,
+	}
+	for _, c := range calls {
+		trace.Calls = append(trace.Calls, types.C7ToolCall{
+			Tool:     c.Tool,
+			Input:    c.Input,
+			Path:     c.Path,
+			Start:    c.Start.Seconds(),
+			Duration: c.Duration.Seconds(),
+			Error:    c.Error,
 		})
 	}
+	return trace
 }
 
 // assignIndividualScore assigns the score to the appropriate C7Metrics field.
@@ -236,14 +444,53 @@ func (a *C7Analyzer) assignIndividualScore(m *types.C7Metrics, mr metrics.Metric
 		m.IdentifierInterpretability = mr.Score
 	case "documentation_accuracy_detection":
 		m.DocumentationAccuracyDetection = mr.Score
+	case "change_success":
+		m.ChangeSuccess = mr.Score
+	case "feature_localization":
+		m.FeatureLocalization = mr.Score
+		summarizeLocalization(m, mr.Samples)
+	}
+}
+
+// summarizeLocalization sets the top-1 and top-3 accuracy of the feature
+// localization samples that ran and their mean tool calls.
+func summarizeLocalization(m *types.C7Metrics, samples []metrics.SampleResult) {
+	var ran, top1, top3, calls, counted int
+	for _, s := range samples {
+		loc := s.ScoreTrace.Localization
+		if s.Error != "" || loc == nil {
+			continue
+		}
+		ran++
+		if loc.Rank == 1 {
+			top1++
+		}
+		if loc.Rank > 0 {
+			top3++
+		}
+		if loc.ToolCalls >= 0 {
+			calls += loc.ToolCalls
+			counted++
+		}
+	}
+	if ran == 0 {
+		return
+	}
+	m.LocalizationTop1 = float64(top1) / float64(ran)
+	m.LocalizationTop3 = float64(top3) / float64(ran)
+	m.LocalizationToolCalls = -1
+	if counted > 0 {
+		m.LocalizationToolCalls = float64(calls) / float64(counted)
 	}
 }
 
-// populateTokensAndCost calculates and populates token usage, duration, and cost.
+// populateTokensAndCost populates token usage, duration, and cost. Usage is
+// what the backends reported, priced per model, or estimated from text length
+// for backends that report none.
 func (a *C7Analyzer) populateTokensAndCost(m *types.C7Metrics, result agent.ParallelResult, startTime time.Time) {
 	m.TokensUsed = result.TotalTokens
 	m.TotalDuration = time.Since(startTime).Seconds()
-	m.CostUSD = float64(m.TokensUsed) / c7TokensPerMillion * c7CostRatePerMTok
+	m.CostUSD = result.CostUSD
 }
 
 // calculateWeightedScore computes MECE score using research-based weights.
@@ -252,33 +499,40 @@ func (a *C7Analyzer) populateTokensAndCost(m *types.C7Metrics, result agent.Para
 // while the scoring package uses the same weights for formal scoring with breakpoints.
 // If weights change, update both locations.
 func (a *C7Analyzer) calculateWeightedScore(m *types.C7Metrics) float64 {
-	// Weights from scoring config (internal/scoring/config.go):
-	// M1: 0.20, M2: 0.25, M3: 0.25, M4: 0.15, M5: 0.15
+	scores := make(map[string]int, len(m.MetricResults))
+	for _, mr := range m.MetricResults {
+		if mr.Status == "completed" {
+			scores[mr.MetricID] = mr.Score
+		}
+	}
+	return a.weightedScore(scores)
+}
+
+// weightedScore averages the scores of the completed metrics by metric ID
+// with the MECE weights.
+func (a *C7Analyzer) weightedScore(scores map[string]int) float64 {
+	// Weights from scoring config (internal/scoring/config.go), divided by 1.30:
+	// M1: 0.20, M2: 0.25, M3: 0.25, M4: 0.15, M5: 0.15, M6: 0.15, M7: 0.15
 	weights := map[string]float64{
 		"task_execution_consistency":       c7WeightM1,
 		"code_behavior_comprehension":      c7WeightM2,
 		"cross_file_navigation":            c7WeightM3,
 		"identifier_interpretability":      c7WeightM4,
 		"documentation_accuracy_detection": c7WeightM5,
+		"change_success":                   c7WeightM6,
+		"feature_localization":             c7WeightM7,
 	}
-
-	scores := map[string]int{
-		"task_execution_consistency":       m.TaskExecutionConsistency,
-		"code_behavior_comprehension":      m.CodeBehaviorComprehension,
-		"cross_file_navigation":            m.CrossFileNavigation,
-		"identifier_interpretability":      m.IdentifierInterpretability,
-		"documentation_accuracy_detection": m.DocumentationAccuracyDetection,
+	for _, t := range a.metricOptions.Tasks {
+		weights[t.ID] = t.EffectiveWeight()
 	}
 
 	totalWeight := 0.0
 	weightedSum := 0.0
 
 	for id, score := range scores {
-		if score > 0 { // Only include completed metrics
-			weight := weights[id]
-			weightedSum += float64(score) * weight
-			totalWeight += weight
-		}
+		weight := weights[id]
+		weightedSum += float64(score) * weight
+		totalWeight += weight
 	}
 
 	if totalWeight == 0 {
@@ -290,8 +544,28 @@ func (a *C7Analyzer) calculateWeightedScore(m *types.C7Metrics) float64 {
 // convertScoreTrace converts an internal metrics.ScoreTrace to the output types.C7ScoreTrace.
 func convertScoreTrace(st metrics.ScoreTrace) types.C7ScoreTrace {
 	trace := types.C7ScoreTrace{
-		BaseScore:  st.BaseScore,
-		FinalScore: st.FinalScore,
+		BaseScore:     st.BaseScore,
+		FinalScore:    st.FinalScore,
+		RunAgreement:  st.RunAgreement,
+		Justification: st.Justification,
+	}
+	if st.Change != nil {
+		trace.Change = &types.C7ChangeOutcome{
+			Passed:       st.Change.Passed,
+			Output:       st.Change.Output,
+			LinesAdded:   st.Change.LinesAdded,
+			LinesDeleted: st.Change.LinesDeleted,
+			FilesTouched: st.Change.FilesTouched,
+		}
+	}
+	if st.Localization != nil {
+		trace.Localization = &types.C7LocalizationOutcome{
+			Expected:  st.Localization.Expected,
+			Answers:   st.Localization.Answers,
+			Rank:      st.Localization.Rank,
+			FileRank:  st.Localization.FileRank,
+			ToolCalls: st.Localization.ToolCalls,
+		}
 	}
 	for _, ind := range st.Indicators {
 		trace.Indicators = append(trace.Indicators, types.C7IndicatorMatch{
@@ -300,6 +574,16 @@ func convertScoreTrace(st metrics.ScoreTrace) types.C7ScoreTrace {
 			Delta:   ind.Delta,
 		})
 	}
+	for _, g := range st.GroundTruth {
+		trace.GroundTruth = append(trace.GroundTruth, types.C7GroundTruthMatch{
+			Kind:         g.Kind,
+			Matched:      g.Matched,
+			Missed:       g.Missed,
+			Hallucinated: g.Hallucinated,
+			Precision:    g.Precision(),
+			Recall:       g.Recall(),
+		})
+	}
 	return trace
 }
 
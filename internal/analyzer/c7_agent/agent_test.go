@@ -1,20 +1,25 @@
 package c7
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
 func TestC7Analyzer_DisabledByDefault(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 
 	targets := []*types.AnalysisTarget{
 		{
@@ -23,7 +28,7 @@ func TestC7Analyzer_DisabledByDefault(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -40,7 +45,7 @@ func TestC7Analyzer_DisabledByDefault(t *testing.T) {
 }
 
 func TestC7Analyzer_Name(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	expected := "C7: Agent Evaluation"
 	if analyzer.Name() != expected {
 		t.Errorf("expected name %q, got %q", expected, analyzer.Name())
@@ -48,7 +53,7 @@ func TestC7Analyzer_Name(t *testing.T) {
 }
 
 func TestC7Analyzer_ResultCategory(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 
 	targets := []*types.AnalysisTarget{
 		{
@@ -57,7 +62,7 @@ func TestC7Analyzer_ResultCategory(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -68,7 +73,7 @@ func TestC7Analyzer_ResultCategory(t *testing.T) {
 }
 
 func TestC7Analyzer_Enable(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 
 	// Before enabling, it should be disabled
 	if analyzer.enabled {
@@ -103,7 +108,7 @@ func TestEstimateResponseTokens(t *testing.T) {
 }
 
 func TestC7Analyzer_SetDebug(t *testing.T) {
-	a := NewC7Analyzer()
+	a := NewC7Analyzer(nil)
 
 	// Default state: debug off, writer is io.Discard
 	if a.debug {
@@ -125,7 +130,7 @@ func TestC7Analyzer_SetDebug(t *testing.T) {
 }
 
 func TestC7Analyzer_DebugWriterNeverNil(t *testing.T) {
-	a := NewC7Analyzer()
+	a := NewC7Analyzer(nil)
 
 	// Even without SetDebug being called, debugWriter should be io.Discard (not nil)
 	if a.debugWriter == nil {
@@ -188,7 +193,7 @@ func mockParallelResult() agent.ParallelResult {
 }
 
 func TestBuildMetrics_AlwaysPopulatesDebugSamples(t *testing.T) {
-	a := NewC7Analyzer()
+	a := NewC7Analyzer(nil)
 	// debug is false by default -- DebugSamples should still be populated
 	// (debug flag only controls terminal output, not data capture)
 
@@ -211,7 +216,7 @@ func TestBuildMetrics_AlwaysPopulatesDebugSamples(t *testing.T) {
 // when debug is on. Note: debug flag now only controls terminal output, not data capture.
 // DebugSamples are always populated regardless of debug flag.
 func TestBuildMetrics_DebugOn_PopulatesDebugSamples(t *testing.T) {
-	a := NewC7Analyzer()
+	a := NewC7Analyzer(nil)
 	a.SetDebug(true, io.Discard)
 
 	result := a.buildMetrics(mockParallelResult(), time.Now())
@@ -277,12 +282,12 @@ func TestBuildMetrics_DebugOn_PopulatesDebugSamples(t *testing.T) {
 }
 
 func TestC7Analyzer_Analyze_NoTargets(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	// Enable with a mock evaluator
 	analyzer.Enable(agent.NewEvaluator(0))
 
 	// Analyze with empty targets
-	_, err := analyzer.Analyze([]*types.AnalysisTarget{})
+	_, err := analyzer.Analyze(context.Background(), []*types.AnalysisTarget{})
 	if err == nil {
 		t.Error("expected error for empty targets, got nil")
 	}
@@ -292,7 +297,7 @@ func TestC7Analyzer_Analyze_NoTargets(t *testing.T) {
 }
 
 func TestC7Analyzer_Analyze_EvaluatorNil(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	// Don't enable - evaluator remains nil
 
 	targets := []*types.AnalysisTarget{
@@ -302,7 +307,7 @@ func TestC7Analyzer_Analyze_EvaluatorNil(t *testing.T) {
 		},
 	}
 
-	result, err := analyzer.Analyze(targets)
+	result, err := analyzer.Analyze(context.Background(), targets)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -318,7 +323,7 @@ func TestC7Analyzer_Analyze_EvaluatorNil(t *testing.T) {
 }
 
 func TestC7Analyzer_DisabledResult(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	result := analyzer.disabledResult()
 
 	if result.Name != "C7: Agent Evaluation" {
@@ -338,7 +343,7 @@ func TestC7Analyzer_DisabledResult(t *testing.T) {
 }
 
 func TestC7Analyzer_SetDebugDir(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 
 	// Initially empty
 	if analyzer.debugDir != "" {
@@ -353,7 +358,7 @@ func TestC7Analyzer_SetDebugDir(t *testing.T) {
 }
 
 func TestC7Analyzer_SetEvaluator(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 
 	// Initially nil
 	if analyzer.evaluator != nil {
@@ -373,8 +378,36 @@ func TestC7Analyzer_SetEvaluator(t *testing.T) {
 	}
 }
 
+func TestC7Analyzer_SetBackend(t *testing.T) {
+	analyzer := NewC7Analyzer(nil)
+
+	// An unusable backend keeps C7 unavailable instead of failing the scan.
+	backend, err := agent.NewBackend(agent.BackendConfig{Backend: "command", Command: []string{"ars-no-such-agent", "{prompt}"}})
+	if err != nil {
+		t.Fatalf("NewBackend() error: %v", err)
+	}
+	analyzer.SetBackend(backend)
+	if !analyzer.enabled {
+		t.Error("SetBackend should enable the analyzer")
+	}
+
+	result, err := analyzer.Analyze(context.Background(), []*types.AnalysisTarget{{Language: types.LangGo, RootDir: t.TempDir()}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c7 := result.Metrics["c7"].(*types.C7Metrics); c7.Available {
+		t.Error("expected Available to be false when the backend is not installed")
+	}
+
+	// Disabling LLM features also clears the backend.
+	analyzer.SetEvaluator(nil)
+	if analyzer.enabled || analyzer.backend != nil {
+		t.Error("SetEvaluator(nil) should disable the analyzer and clear the backend")
+	}
+}
+
 func TestBuildMetrics_EmptyResults(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	startTime := time.Now()
 
 	emptyResult := agent.ParallelResult{
@@ -394,8 +427,75 @@ func TestBuildMetrics_EmptyResults(t *testing.T) {
 	}
 }
 
+func TestBuildMetrics_Repeats(t *testing.T) {
+	analyzer := NewC7Analyzer(nil)
+	analyzer.SetMetricOptions(metrics.Options{Repeats: 3})
+
+	m := analyzer.buildMetrics(agent.ParallelResult{Results: []metrics.MetricResult{
+		{MetricID: "code_behavior_comprehension", Score: 7, RepeatScores: []int{6, 7, 8}},
+		{MetricID: "cross_file_navigation", Score: 5, RepeatScores: []int{4, types.FailedRepeat, 6}},
+		{MetricID: "identifier_interpretability", Score: 0, RepeatScores: []int{0, 0, 0}},
+	}}, time.Now())
+
+	if m.Repeats != 3 {
+		t.Errorf("Repeats = %d, want 3", m.Repeats)
+	}
+	iv := m.MetricResults[0].Interval
+	if iv == nil || iv.Runs != 3 || iv.Mean != 7 || iv.StdDev != 1 {
+		t.Fatalf("M2 interval = %+v, want 3 runs, mean 7, stddev 1", iv)
+	}
+	if iv := m.MetricResults[1].Interval; iv == nil || iv.Runs != 2 || iv.Mean != 5 {
+		t.Errorf("M3 interval = %+v, want the 2 completed runs with mean 5", iv)
+	}
+	if iv := m.MetricResults[2].Interval; iv == nil || iv.Runs != 3 || iv.Mean != 0 {
+		t.Errorf("M4 interval = %+v, want 3 runs scoring 0", iv)
+	}
+	// M3 failed in the second repeat; M4's zeros count.
+	w2, w3, w4 := c7WeightM2, c7WeightM3, c7WeightM4
+	want := ((6*w2+4*w3)/(w2+w3+w4) + 7*w2/(w2+w4) + (8*w2+6*w3)/(w2+w3+w4)) / 3
+	if m.MECEInterval == nil || m.MECEInterval.Runs != 3 || math.Abs(m.MECEScore-want) > 1e-9 {
+		t.Errorf("MECE = %.3f, interval %+v; want %.3f", m.MECEScore, m.MECEInterval, want)
+	}
+}
+
+func TestBuildMetrics_ToolTrace(t *testing.T) {
+	analyzer := NewC7Analyzer(nil)
+	read := func(path string, start time.Duration) metrics.ToolCall {
+		return metrics.ToolCall{Tool: "Read", Input: `{"file_path":"` + path + `"}`, Path: path, Start: start, Duration: 500 * time.Millisecond}
+	}
+
+	m := analyzer.buildMetrics(agent.ParallelResult{Results: []metrics.MetricResult{
+		{MetricID: "cross_file_navigation", Score: 6, Samples: []metrics.SampleResult{
+			{Score: 6, ToolCalls: []metrics.ToolCall{read("a.go", 0), read("b.go", time.Second), read("a.go", 2*time.Second)}},
+			{Score: 6, ToolCalls: []metrics.ToolCall{}, Retries: 2},
+		}},
+		{MetricID: "identifier_interpretability", Score: 7, Samples: []metrics.SampleResult{{Score: 7}}},
+	}}, time.Now())
+
+	trace := m.MetricResults[0].DebugSamples[0].ToolTrace
+	if trace == nil || len(trace.Calls) != 3 || trace.FilesRead != 2 || trace.RedundantReads != 1 {
+		t.Fatalf("ToolTrace = %+v, want 3 calls reading 2 files, 1 redundantly", trace)
+	}
+	if c := trace.Calls[1]; c.Path != "b.go" || c.Start != 1 || c.Duration != 0.5 {
+		t.Errorf("Calls[1] = %+v, want b.go at 1s for 0.5s", c)
+	}
+	if m.MetricResults[1].DebugSamples[0].ToolTrace != nil || m.MetricResults[1].Navigation != nil {
+		t.Error("untraced samples should have no tool trace or navigation summary")
+	}
+	want := types.C7Navigation{Samples: 2, ToolCalls: 1.5, FilesRead: 1, RedundantReads: 0.5}
+	if nav := m.MetricResults[0].Navigation; nav == nil || *nav != want {
+		t.Errorf("M3 Navigation = %+v, want %+v", nav, want)
+	}
+	if m.Navigation == nil || *m.Navigation != want {
+		t.Errorf("Navigation = %+v, want %+v", m.Navigation, want)
+	}
+	if m.Retries != 2 || m.MetricResults[0].DebugSamples[1].Retries != 2 {
+		t.Errorf("Retries = %d (sample %d), want 2", m.Retries, m.MetricResults[0].DebugSamples[1].Retries)
+	}
+}
+
 func TestBuildMetrics_WithErrors(t *testing.T) {
-	analyzer := NewC7Analyzer()
+	analyzer := NewC7Analyzer(nil)
 	startTime := time.Now()
 
 	// Simulate some metrics succeeding, some failing
@@ -508,3 +608,58 @@ func TestC7MetricResult_DebugSamples_OmitEmpty_JSON(t *testing.T) {
 		t.Errorf("JSON should contain indicator name, got: %s", jsonStr)
 	}
 }
+
+func TestStaticGroundTruth_Navigation(t *testing.T) {
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		t.Fatalf("failed to create Tree-sitter parser: %v", err)
+	}
+	defer tsParser.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"app/main.py":    "from app.service import run\n\ndef main():\n    run()\n",
+		"app/service.py": "from app.store import save\n\ndef run():\n    save(1)\n",
+		"app/store.py":   "def save(value):\n    return value\n",
+	}
+	target := &types.AnalysisTarget{Language: types.LangPython, RootDir: dir}
+	for rel, content := range files {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		target.Files = append(target.Files, types.SourceFile{
+			Path: path, RelPath: rel, Language: types.LangPython, Class: types.ClassSource, Content: []byte(content),
+		})
+	}
+
+	gt := newStaticGroundTruth(nil, tsParser, []*types.AnalysisTarget{target}, false)
+	truth, ok := gt.Navigation("app/main.py")
+	if !ok {
+		t.Fatal("app/main.py has no ground truth")
+	}
+	if !reflect.DeepEqual(truth.Files, []string{"app/service.py"}) {
+		t.Errorf("Files = %v, want [app/service.py]", truth.Files)
+	}
+	if !reflect.DeepEqual(truth.Dependencies, []string{"app/service.py", "app/store.py"}) {
+		t.Errorf("Dependencies = %v, want service.py and store.py", truth.Dependencies)
+	}
+	if !reflect.DeepEqual(truth.Symbols, []string{"run"}) {
+		t.Errorf("Symbols = %v, want [run]", truth.Symbols)
+	}
+	if !truth.Identifiers["save"] || truth.Identifiers["flush"] {
+		t.Errorf("Identifiers should contain project identifiers only")
+	}
+	if _, ok := gt.Navigation("app/missing.py"); ok {
+		t.Error("unknown file should have no ground truth")
+	}
+	if _, ok := gt.DocMismatches("app/main.py"); ok {
+		t.Error("read-only workspace must not be seeded")
+	}
+	if fns, ok := gt.Functions("app/service.py"); !ok || !reflect.DeepEqual(fns, []string{"run"}) {
+		t.Errorf("Functions(app/service.py) = %v, %v; want [run]", fns, ok)
+	}
+}
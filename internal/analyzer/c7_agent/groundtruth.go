@@ -0,0 +1,130 @@
+package c7
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	c1 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c1_code_quality"
+	c3 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c3_architecture"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// identifierPattern matches identifiers in source text.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// staticGroundTruth derives metric ground truth from the project's import
+// graph, exports and source files. The index is built on first use, so metrics without
+// ground truth (or runs without samples) do not pay for it.
+type staticGroundTruth struct {
+	pkgs     []*parser.ParsedPackage
+	tsParser *parser.TreeSitterParser
+	targets  []*types.AnalysisTarget
+	seed     bool // whether the workspace is isolated, so seeded copies may be written to it
+
+	once        sync.Once
+	index       *c3.DependencyIndex
+	identifiers map[string]bool
+	functions   map[string][]types.FunctionMetric
+	exports     map[string][]c3.DocumentedExport
+}
+
+func newStaticGroundTruth(pkgs []*parser.ParsedPackage, tsParser *parser.TreeSitterParser, targets []*types.AnalysisTarget, seed bool) *staticGroundTruth {
+	return &staticGroundTruth{pkgs: pkgs, tsParser: tsParser, targets: targets, seed: seed}
+}
+
+func (g *staticGroundTruth) build() {
+	g.index = c3.BuildDependencyIndex(g.pkgs, g.tsParser, g.targets)
+	g.functions = c1.FileFunctions(g.pkgs, g.tsParser, g.targets)
+	g.exports = c3.DocumentedExports(g.pkgs, g.tsParser, g.targets)
+	g.identifiers = make(map[string]bool)
+	for _, target := range g.targets {
+		for _, file := range target.Files {
+			for _, id := range identifierPattern.FindAll(file.Content, -1) {
+				g.identifiers[string(id)] = true
+			}
+		}
+	}
+}
+
+// Navigation returns the call targets and transitive dependencies of relPath.
+// Go files list the files declaring the functions they call; Python and
+// TypeScript imports are file-level, so their direct imports are expected too.
+func (g *staticGroundTruth) Navigation(relPath string) (*metrics.NavigationTruth, bool) {
+	g.once.Do(g.build)
+	deps, ok := g.index.Deps(relPath)
+	if !ok {
+		return nil, false
+	}
+
+	expected := make(map[string]bool)
+	truth := &metrics.NavigationTruth{
+		Dependencies: g.index.Closure(relPath),
+		Identifiers:  g.identifiers,
+	}
+	for sym, file := range deps.Calls {
+		truth.Symbols = append(truth.Symbols, sym)
+		expected[file] = true
+	}
+	if path.Ext(relPath) != ".go" {
+		for _, file := range deps.Imports {
+			expected[file] = true
+		}
+	}
+	for file := range expected {
+		truth.Files = append(truth.Files, file)
+	}
+	sort.Strings(truth.Symbols)
+	sort.Strings(truth.Files)
+	return truth, true
+}
+
+// DocMismatches seeds documentation mismatches into a copy of relPath. It
+// returns false when the workspace is the project itself (read-only fallback),
+// so the project is never written to.
+func (g *staticGroundTruth) DocMismatches(relPath string) (*metrics.SeededDoc, bool) {
+	if !g.seed {
+		return nil, false
+	}
+	for _, target := range g.targets {
+		for _, file := range target.Files {
+			if file.RelPath == relPath {
+				return metrics.SeedMismatches(relPath, file.Content)
+			}
+		}
+	}
+	return nil, false
+}
+
+// Functions returns the functions C1 finds in relPath, with methods as
+// "Type.Method". A file without functions yields an empty, non-nil list.
+func (g *staticGroundTruth) Functions(relPath string) ([]string, bool) {
+	g.once.Do(g.build)
+	fns, ok := g.functions[relPath]
+	if !ok {
+		return nil, false
+	}
+	names := make([]string, 0, len(fns))
+	for _, fn := range fns {
+		names = append(names, fn.Name)
+	}
+	return names, true
+}
+
+// DocumentedSymbols returns the documented exported functions and types C3
+// finds in relPath.
+func (g *staticGroundTruth) DocumentedSymbols(relPath string) ([]metrics.DocumentedSymbol, bool) {
+	g.once.Do(g.build)
+	exports := g.exports[relPath]
+	if len(exports) == 0 {
+		return nil, false
+	}
+	symbols := make([]metrics.DocumentedSymbol, len(exports))
+	for i, e := range exports {
+		symbols[i] = metrics.DocumentedSymbol{Name: e.Name, Kind: e.Kind, Doc: e.Doc}
+	}
+	return symbols, true
+}
@@ -0,0 +1,39 @@
+package c7
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+// rubricSystemPrompt frames a task's rubric text for the judge.
+const rubricSystemPrompt = `You are grading a coding agent's response to a task on a software repository.
+Score the response from 1 (fails the rubric) to 10 (fully meets it) using only this rubric:
+
+%s
+
+Respond with a JSON object: {"score": <1-10>, "reason": "<one or two sentences>"}.`
+
+// rubricJudge adapts an agent.Judge to the metrics.Judge interface used by
+// user-defined tasks (metrics cannot import the agent package).
+type rubricJudge struct {
+	judge agent.Judge
+}
+
+// Score implements metrics.Judge.
+func (r rubricJudge) Score(ctx context.Context, rubric, content string) (int, string, error) {
+	result, err := agent.EvaluateWithRetry(ctx, r.judge, fmt.Sprintf(rubricSystemPrompt, rubric), content)
+	if err != nil {
+		return 0, "", err
+	}
+	return result.Score, result.Reason, nil
+}
+
+// Calibrate scores the calibration cases with judge, using the same rubrics
+// and content as judge scoring in a scan, and compares its rankings with the
+// heuristics'.
+func Calibrate(ctx context.Context, judge agent.Judge, cases []metrics.CalibrationCase) (metrics.CalibrationReport, error) {
+	return metrics.Calibrate(ctx, rubricJudge{judge: judge}, cases)
+}
@@ -0,0 +1,56 @@
+package c7
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+)
+
+// stubJudge is an agent.Judge returning a fixed result.
+type stubJudge struct {
+	result       agent.EvaluationResult
+	systemPrompt string
+}
+
+func (s *stubJudge) EvaluateContent(ctx context.Context, systemPrompt, content string) (agent.EvaluationResult, error) {
+	s.systemPrompt = systemPrompt
+	return s.result, nil
+}
+
+func (s *stubJudge) Info() agent.JudgeInfo { return agent.JudgeInfo{Backend: "stub"} }
+
+func TestRubricJudge(t *testing.T) {
+	stub := &stubJudge{result: agent.EvaluationResult{Score: 7, Reason: "covers the error path"}}
+	score, justification, err := rubricJudge{judge: stub}.Score(context.Background(), "Full marks if it is reversible.", "response")
+	if err != nil {
+		t.Fatalf("Score() error: %v", err)
+	}
+	if score != 7 || justification != "covers the error path" {
+		t.Errorf("Score() = %d, %q", score, justification)
+	}
+	if !strings.Contains(stub.systemPrompt, "Full marks if it is reversible.") || !strings.Contains(stub.systemPrompt, `"score"`) {
+		t.Errorf("system prompt %q lacks the rubric or the JSON format", stub.systemPrompt)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	cases, err := metrics.DefaultCalibrationSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := &stubJudge{result: agent.EvaluationResult{Score: 6, Reason: "fixed"}}
+	report, err := Calibrate(context.Background(), stub, cases)
+	if err != nil {
+		t.Fatalf("Calibrate() error: %v", err)
+	}
+	// A judge giving every response the same score agrees on no pair
+	if report.Pairs != 4 || report.Agreement() != 0 {
+		t.Errorf("report = %d pairs, agreement %.2f; want 4 pairs, 0\n%s", report.Pairs, report.Agreement(), report)
+	}
+	if !strings.Contains(stub.systemPrompt, `"score"`) {
+		t.Errorf("system prompt %q lacks the JSON format", stub.systemPrompt)
+	}
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"go/ast"
+	"os"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// GoFileFacts are an analyzer's facts for one Go file, reported under its
+// absolute path.
+type GoFileFacts[T any] struct {
+	File  string
+	Facts T
+}
+
+// GoFileContents maps the Go targets' file paths to their preloaded content.
+func GoFileContents(targets []*types.AnalysisTarget) map[string][]byte {
+	contents := make(map[string][]byte)
+	for _, target := range targets {
+		if target.Language != types.LangGo {
+			continue
+		}
+		for _, sf := range target.Files {
+			if len(sf.Content) > 0 {
+				contents[sf.Path] = sf.Content
+			}
+		}
+	}
+	return contents
+}
+
+// GoPackageFacts returns analyzer's facts (at version) for each file of pkg.
+// For a type-checked package, compute derives the facts of files missing from
+// store. A package loaded without syntax (see parser.GoPackagesParser.ParseExcept)
+// is served from store alone; its files without an entry are left out.
+// contents maps file paths to content already read; other files are read from disk.
+func GoPackageFacts[T any](store *cache.Store, analyzer string, version int, pkg *parser.ParsedPackage, contents map[string][]byte, compute func(f *ast.File) T) []GoFileFacts[T] {
+	var result []GoFileFacts[T]
+	if pkg.Syntax == nil {
+		for _, file := range pkg.GoFiles {
+			content := goFileContent(file, contents)
+			if content == nil {
+				continue
+			}
+			if facts, ok := cache.Lookup[T](store, analyzer, version, content); ok {
+				result = append(result, GoFileFacts[T]{File: file, Facts: facts})
+			}
+		}
+		return result
+	}
+
+	for _, f := range pkg.Syntax {
+		file := pkg.Fset.Position(f.Pos()).Filename
+		content := goFileContent(file, contents)
+		var facts T
+		if content == nil {
+			facts = compute(f)
+		} else {
+			facts, _ = cache.Memo(store, analyzer, version, content, func() (T, error) { return compute(f), nil })
+		}
+		result = append(result, GoFileFacts[T]{File: file, Facts: facts})
+	}
+	return result
+}
+
+// GoFactsCached reports whether store holds analyzer's facts (at version) for
+// every file of pkg, so that the package need not be type-checked for them.
+// contents maps file paths to their content; files missing from it count as
+// uncached.
+func GoFactsCached(store *cache.Store, analyzer string, version int, pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	for _, file := range pkg.GoFiles {
+		content, ok := contents[file]
+		if !ok || !store.Has(analyzer, version, content) {
+			return false
+		}
+	}
+	return true
+}
+
+// goFileContent returns the content of file from contents or disk, or nil if
+// it cannot be read.
+func goFileContent(file string, contents map[string][]byte) []byte {
+	if content, ok := contents[file]; ok {
+		return content
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	return content
+}
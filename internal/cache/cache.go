@@ -0,0 +1,148 @@
+// Package cache is a content-addressed on-disk store for per-file analysis results
+// and LLM responses.
+//
+// Entries are keyed by the SHA-256 of a file's content, the analyzer that produced
+// them, that analyzer's facts version and a hash of the active configuration. A
+// file whose content has not changed since the last scan is therefore never
+// re-parsed or re-analyzed, regardless of its path or modification time.
+// The cache is best-effort: unreadable or corrupt entries are treated as misses
+// and write failures are ignored.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// dirName is the cache directory name under the user cache directory.
+const dirName = "ars"
+
+// DefaultDir returns $XDG_CACHE_HOME/ars, falling back to the platform user
+// cache directory (e.g. ~/.cache/ars) when XDG_CACHE_HOME is unset.
+func DefaultDir() (string, error) {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, dirName), nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, dirName), nil
+}
+
+// Store reads and writes cached per-file results under a directory.
+// A nil *Store is valid and caches nothing.
+type Store struct {
+	dir        string
+	configHash string
+}
+
+// Open returns a Store rooted at dir whose keys include configHash (see ConfigHash).
+// The directory is created lazily on the first write.
+func Open(dir, configHash string) *Store {
+	return &Store{dir: dir, configHash: configHash}
+}
+
+// Clean removes every entry under dir. A missing directory is not an error.
+func Clean(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("remove cache %s: %w", dir, err)
+	}
+	return nil
+}
+
+// ConfigHash returns a stable hex digest of v's JSON encoding.
+func ConfigHash(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Memo returns the cached result of analyzer (at version) for content, or calls
+// compute and caches its result. Analyzers bump version whenever the shape of T or
+// the algorithm producing it changes. Errors from compute are returned uncached.
+func Memo[T any](s *Store, analyzer string, version int, content []byte, compute func() (T, error)) (T, error) {
+	if s == nil {
+		return compute()
+	}
+	if v, ok := Lookup[T](s, analyzer, version, content); ok {
+		return v, nil
+	}
+
+	v, err := compute()
+	if err != nil {
+		return v, err
+	}
+	writeAtomic(s.entryPath(analyzer, version, content), v)
+	return v, nil
+}
+
+// Lookup returns the cached result of analyzer (at version) for content without
+// computing it on a miss. Callers that can no longer compute the result, such as
+// analyzers given a Go package loaded without syntax, use it instead of Memo.
+func Lookup[T any](s *Store, analyzer string, version int, content []byte) (T, bool) {
+	var v T
+	if s == nil {
+		return v, false
+	}
+	data, err := os.ReadFile(s.entryPath(analyzer, version, content))
+	if err != nil || json.Unmarshal(data, &v) != nil {
+		return v, false
+	}
+	return v, true
+}
+
+// Has reports whether a readable result of analyzer (at version) for content is
+// cached. A nil *Store has nothing cached.
+func (s *Store) Has(analyzer string, version int, content []byte) bool {
+	_, ok := Lookup[json.RawMessage](s, analyzer, version, content)
+	return ok
+}
+
+// entryPath returns the file holding analyzer's result for content. Entries are
+// sharded by the first two key characters to keep directories small.
+func (s *Store) entryPath(analyzer string, version int, content []byte) string {
+	fileSum := sha256.Sum256(content)
+	h := sha256.New()
+	h.Write(fileSum[:])
+	h.Write([]byte{0})
+	h.Write([]byte(analyzer))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(version)))
+	h.Write([]byte{0})
+	h.Write([]byte(s.configHash))
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(s.dir, analyzer, key[:2], key+".json")
+}
+
+// writeAtomic stores v at path atomically, so concurrent scans never see partial entries.
+func writeAtomic(path string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type facts struct {
+	Lines int `json:"lines"`
+}
+
+func TestMemo_CachesByContentVersionAndConfig(t *testing.T) {
+	dir := t.TempDir()
+	s := Open(dir, ConfigHash(map[string]int{"threshold": 1}))
+
+	calls := 0
+	compute := func(lines int) func() (facts, error) {
+		return func() (facts, error) {
+			calls++
+			return facts{Lines: lines}, nil
+		}
+	}
+
+	got, err := Memo(s, "c1-go", 1, []byte("package a\n"), compute(2))
+	if err != nil || got.Lines != 2 || calls != 1 {
+		t.Fatalf("first lookup = %+v, %v (calls %d), want computed {2}", got, err, calls)
+	}
+
+	// Same content, analyzer, version and config: served from disk.
+	got, _ = Memo(s, "c1-go", 1, []byte("package a\n"), compute(99))
+	if got.Lines != 2 || calls != 1 {
+		t.Errorf("cached lookup = %+v (calls %d), want cached {2}", got, calls)
+	}
+
+	// Any key component changing is a miss.
+	Memo(s, "c1-go", 1, []byte("package b\n"), compute(3))
+	Memo(s, "c4-go", 1, []byte("package a\n"), compute(3))
+	Memo(s, "c1-go", 2, []byte("package a\n"), compute(3))
+	Memo(Open(dir, ConfigHash(map[string]int{"threshold": 2})), "c1-go", 1, []byte("package a\n"), compute(3))
+	if calls != 5 {
+		t.Errorf("calls = %d after four differing keys, want 5", calls)
+	}
+}
+
+func TestMemo_NilStoreAndErrors(t *testing.T) {
+	calls := 0
+	compute := func() (facts, error) {
+		calls++
+		return facts{Lines: 1}, nil
+	}
+	Memo(nil, "c1-go", 1, []byte("x"), compute)
+	Memo(nil, "c1-go", 1, []byte("x"), compute)
+	if calls != 2 {
+		t.Errorf("nil store: calls = %d, want 2 (nothing cached)", calls)
+	}
+
+	s := Open(t.TempDir(), "")
+	wantErr := errors.New("parse failed")
+	if _, err := Memo(s, "c1-go", 1, []byte("x"), func() (facts, error) { return facts{}, wantErr }); !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	got, _ := Memo(s, "c1-go", 1, []byte("x"), compute)
+	if got.Lines != 1 || calls != 3 {
+		t.Errorf("after failed compute: got %+v (calls %d), want recomputed", got, calls)
+	}
+}
+
+func TestMemo_CorruptEntryIsAMiss(t *testing.T) {
+	dir := t.TempDir()
+	s := Open(dir, "")
+	Memo(s, "c1-go", 1, []byte("x"), func() (facts, error) { return facts{Lines: 1}, nil })
+
+	path := s.entryPath("c1-go", 1, []byte("x"))
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := Memo(s, "c1-go", 1, []byte("x"), func() (facts, error) { return facts{Lines: 7}, nil })
+	if got.Lines != 7 {
+		t.Errorf("got %+v, want recomputed {7}", got)
+	}
+}
+
+func TestLookupAndHas(t *testing.T) {
+	s := Open(t.TempDir(), "")
+	if _, ok := Lookup[facts](s, "c1-go", 1, []byte("x")); ok {
+		t.Error("Lookup before Memo: hit, want miss")
+	}
+	if s.Has("c1-go", 1, []byte("x")) {
+		t.Error("Has before Memo = true, want false")
+	}
+	Memo(s, "c1-go", 1, []byte("x"), func() (facts, error) { return facts{Lines: 4}, nil })
+	if got, ok := Lookup[facts](s, "c1-go", 1, []byte("x")); !ok || got.Lines != 4 {
+		t.Errorf("Lookup after Memo = %+v, %v, want {4}, true", got, ok)
+	}
+	if !s.Has("c1-go", 1, []byte("x")) {
+		t.Error("Has after Memo = false, want true")
+	}
+	if s.Has("c1-go", 2, []byte("x")) {
+		t.Error("Has for another version = true, want false")
+	}
+	var nilStore *Store
+	if nilStore.Has("c1-go", 1, []byte("x")) {
+		t.Error("nil store Has = true, want false")
+	}
+}
+
+func TestClean(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "ars")
+	s := Open(dir, "")
+	Memo(s, "c1-go", 1, []byte("x"), func() (facts, error) { return facts{Lines: 1}, nil })
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("cache dir not created: %v", err)
+	}
+
+	if err := Clean(dir); err != nil {
+		t.Fatalf("Clean: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cache dir still exists after Clean: %v", err)
+	}
+	if err := Clean(dir); err != nil {
+		t.Errorf("Clean of missing dir: %v", err)
+	}
+}
+
+func TestDefaultDir_XDG(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/xdg-cache")
+	dir, err := DefaultDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join("/tmp/xdg-cache", "ars") {
+		t.Errorf("DefaultDir() = %q, want /tmp/xdg-cache/ars", dir)
+	}
+}
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LLM response cache defaults.
+const (
+	DefaultLLMTTL      = 7 * 24 * time.Hour
+	DefaultLLMMaxBytes = 256 << 20 // 256 MiB
+	llmDirName         = "llm"
+)
+
+// Mode controls how the LLM response cache is used.
+type Mode int
+
+const (
+	ModeOff       Mode = iota // neither read nor written
+	ModeRead                  // cached responses are used, new ones are not stored
+	ModeReadWrite             // cached responses are used and new ones stored (default)
+)
+
+// modeNames maps --llm-cache values to modes.
+var modeNames = map[string]Mode{"off": ModeOff, "read": ModeRead, "readwrite": ModeReadWrite}
+
+// ParseMode parses a --llm-cache value: "off", "read" or "readwrite".
+func ParseMode(s string) (Mode, error) {
+	m, ok := modeNames[s]
+	if !ok {
+		return ModeOff, fmt.Errorf("invalid LLM cache mode %q (expected off, read or readwrite)", s)
+	}
+	return m, nil
+}
+
+// String returns the mode's --llm-cache value.
+func (m Mode) String() string {
+	for name, mode := range modeNames {
+		if mode == m {
+			return name
+		}
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// LLMStore caches LLM responses on disk under content-addressed keys (see
+// LLMKey). Entries older than the TTL are misses; Prune removes them and the
+// oldest entries beyond the size limit. Like Store it is best-effort, and a
+// nil *LLMStore caches nothing.
+type LLMStore struct {
+	dir      string
+	mode     Mode
+	ttl      time.Duration
+	maxBytes int64
+	now      func() time.Time
+
+	hits, misses atomic.Int64
+}
+
+// llmEntry is the on-disk form of a cached response.
+type llmEntry struct {
+	Created time.Time       `json:"created"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// OpenLLM returns an LLMStore under dir (e.g. DefaultDir()) with the given
+// mode, TTL and size limit; zero limits use the defaults. ModeOff returns nil.
+func OpenLLM(dir string, mode Mode, ttl time.Duration, maxBytes int64) *LLMStore {
+	if mode == ModeOff {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = DefaultLLMTTL
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultLLMMaxBytes
+	}
+	return &LLMStore{dir: filepath.Join(dir, llmDirName), mode: mode, ttl: ttl, maxBytes: maxBytes, now: time.Now}
+}
+
+// LLMKey returns the cache key of an LLM call from the parts identifying it,
+// e.g. kind, backend and model, tools, prompt and the sample's content digest.
+func LLMKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get decodes the cached value for key into v and reports whether there was
+// a fresh entry.
+func (s *LLMStore) Get(key string, v any) bool {
+	if s == nil {
+		return false
+	}
+	var e llmEntry
+	data, err := os.ReadFile(s.entryPath(key))
+	if err != nil || json.Unmarshal(data, &e) != nil || s.now().Sub(e.Created) > s.ttl || json.Unmarshal(e.Value, v) != nil {
+		s.misses.Add(1)
+		return false
+	}
+	s.hits.Add(1)
+	return true
+}
+
+// Put stores v under key in readwrite mode.
+func (s *LLMStore) Put(key string, v any) {
+	if s == nil || s.mode != ModeReadWrite {
+		return
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	writeAtomic(s.entryPath(key), llmEntry{Created: s.now(), Value: value})
+}
+
+// Stats returns the number of cache hits and misses so far.
+func (s *LLMStore) Stats() (hits, misses int) {
+	if s == nil {
+		return 0, 0
+	}
+	return int(s.hits.Load()), int(s.misses.Load())
+}
+
+// Prune removes expired entries, then the oldest entries until the cache fits
+// its size limit. It only runs in readwrite mode.
+func (s *LLMStore) Prune() error {
+	if s == nil || s.mode != ModeReadWrite {
+		return nil
+	}
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var entries []entry
+	var total int64
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if s.now().Sub(info.ModTime()) > s.ttl {
+			os.Remove(path)
+			return nil
+		}
+		entries = append(entries, entry{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("prune LLM cache %s: %w", s.dir, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, e := range entries {
+		if total <= s.maxBytes {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			total -= e.size
+		}
+	}
+	return nil
+}
+
+// entryPath returns the file holding key's entry, sharded like Store's.
+func (s *LLMStore) entryPath(key string) string {
+	return filepath.Join(s.dir, key[:2], key+".json")
+}
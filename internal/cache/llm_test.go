@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLLMStore_Modes(t *testing.T) {
+	dir := t.TempDir()
+	key := LLMKey("agent", "claude", "Read", "explain", "a.go", "digest")
+
+	if OpenLLM(dir, ModeOff, 0, 0) != nil {
+		t.Fatal("OpenLLM(ModeOff) should return nil")
+	}
+
+	read := OpenLLM(dir, ModeRead, 0, 0)
+	read.Put(key, "ignored")
+	var got string
+	if read.Get(key, &got) {
+		t.Error("read mode stored a response")
+	}
+
+	rw := OpenLLM(dir, ModeReadWrite, 0, 0)
+	rw.Put(key, "response")
+	if !rw.Get(key, &got) || got != "response" {
+		t.Errorf("readwrite Get = %q, want cached response", got)
+	}
+	got = ""
+	if !read.Get(key, &got) || got != "response" {
+		t.Errorf("read mode Get = %q, want the stored response", got)
+	}
+	if hits, misses := rw.Stats(); hits != 1 || misses != 0 {
+		t.Errorf("Stats() = %d hits, %d misses, want 1, 0", hits, misses)
+	}
+
+	var nilStore *LLMStore
+	nilStore.Put(key, "x")
+	if nilStore.Get(key, &got) {
+		t.Error("nil store returned a hit")
+	}
+}
+
+func TestLLMKey_DistinguishesParts(t *testing.T) {
+	if LLMKey("ab", "c") == LLMKey("a", "bc") {
+		t.Error("LLMKey must separate its parts")
+	}
+	if LLMKey("a", "b") != LLMKey("a", "b") {
+		t.Error("LLMKey is not deterministic")
+	}
+}
+
+func TestLLMStore_TTL(t *testing.T) {
+	s := OpenLLM(t.TempDir(), ModeReadWrite, time.Hour, 0)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.Put("abcd", 7)
+
+	now = now.Add(59 * time.Minute)
+	var got int
+	if !s.Get("abcd", &got) || got != 7 {
+		t.Errorf("Get before expiry = %d, want 7", got)
+	}
+	now = now.Add(2 * time.Minute)
+	if s.Get("abcd", &got) {
+		t.Error("Get after the TTL returned a hit")
+	}
+}
+
+func TestLLMStore_Prune(t *testing.T) {
+	s := OpenLLM(t.TempDir(), ModeReadWrite, time.Hour, 1)
+	old := time.Now().Add(-2 * time.Hour)
+	for _, key := range []string{"aa01", "bb02", "cc03"} {
+		s.Put(key, "response "+key)
+	}
+	// aa01 expired; bb02 is older than cc03.
+	os.Chtimes(s.entryPath("aa01"), old, old)
+	older := time.Now().Add(-time.Minute)
+	os.Chtimes(s.entryPath("bb02"), older, older)
+	info, err := os.Stat(s.entryPath("cc03"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.maxBytes = info.Size()
+
+	if err := s.Prune(); err != nil {
+		t.Fatalf("Prune() error: %v", err)
+	}
+	for key, want := range map[string]bool{"aa01": false, "bb02": false, "cc03": true} {
+		if _, err := os.Stat(s.entryPath(key)); (err == nil) != want {
+			t.Errorf("%s exists = %v after Prune, want %v", key, err == nil, want)
+		}
+	}
+
+	// Pruning a cache that was never written is not an error.
+	empty := OpenLLM(filepath.Join(t.TempDir(), "missing"), ModeReadWrite, 0, 0)
+	if err := empty.Prune(); err != nil {
+		t.Errorf("Prune() on a missing directory: %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, name := range []string{"off", "read", "readwrite"} {
+		m, err := ParseMode(name)
+		if err != nil || m.String() != name {
+			t.Errorf("ParseMode(%q) = %v, %v", name, m, err)
+		}
+	}
+	if _, err := ParseMode("write"); err == nil {
+		t.Error("ParseMode(\"write\") should fail")
+	}
+}
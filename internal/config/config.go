@@ -5,10 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
 	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
 )
 
 // ProjectConfig represents the .arsrc.yml configuration file.
@@ -17,6 +25,88 @@ type ProjectConfig struct {
 	Scoring   scoringOverrides  `yaml:"scoring"`
 	Languages []string          `yaml:"languages"`
 	Metrics   map[string]metricOverrides `yaml:"metrics"`
+	Plugins   []pluginConfig    `yaml:"plugins"`
+	Agent     agentConfig       `yaml:"agent"`
+	Judge     judgeConfig       `yaml:"judge"`
+	C7        c7Config          `yaml:"c7"`
+	LLMCache  llmCacheConfig    `yaml:"llm_cache"`
+	Pricing   map[string]priceConfig `yaml:"pricing"`
+
+	// Include and Exclude are globs of paths relative to the project root
+	// (see discovery.PathRules). Include overrides the default skip list,
+	// Exclude drops paths from the scan.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	// Generated adds to the built-in generated-code conventions.
+	Generated generatedConfig `yaml:"generated"`
+}
+
+// generatedConfig adjusts which files are classified as generated (see
+// discovery.GeneratedRules).
+type generatedConfig struct {
+	Paths       []string `yaml:"paths"`        // globs of generated files
+	Markers     []string `yaml:"markers"`      // header comment text marking a file as generated
+	HandWritten []string `yaml:"hand_written"` // globs of files never classified as generated
+}
+
+// priceConfig is a model's price in USD per million tokens, used to cost LLM
+// usage for --llm-budget-usd. A model matches its own entry or, with a date,
+// -latest or :tag suffix, the entry of its base name (see agent.PriceTable).
+type priceConfig struct {
+	Input  float64 `yaml:"input"`  // per million input tokens
+	Output float64 `yaml:"output"` // per million output tokens
+}
+
+// llmCacheConfig limits the LLM response cache (see cache.OpenLLM).
+type llmCacheConfig struct {
+	TTL   time.Duration `yaml:"ttl"`    // entry lifetime, e.g. "72h"; default 7 days
+	MaxMB int64         `yaml:"max_mb"` // size limit in MiB; default 256
+}
+
+// c7Config tunes C7's agent evaluation metrics.
+type c7Config struct {
+	Consistency struct {
+		Runs    int `yaml:"runs"`    // runs per sampled file (default 3)
+		Samples int `yaml:"samples"` // number of sampled files (default 1)
+	} `yaml:"consistency"` // M1: Task Execution Consistency
+
+	// Scoring selects how M2-M5 responses are scored: "heuristic" (default,
+	// keyword indicators) or "judge" (the judge model with per-metric rubrics).
+	Scoring string `yaml:"scoring"`
+}
+
+// judgeConfig selects the model scoring C4's LLM metrics (see agent.NewJudge).
+type judgeConfig struct {
+	Backend     string        `yaml:"backend"`     // "claude" (default), "openai" or "command"
+	Model       string        `yaml:"model"`       // model name passed to the backend
+	Temperature *float64      `yaml:"temperature"` // openai: sampling temperature (default 0)
+	BaseURL     string        `yaml:"base_url"`    // openai: API base URL, e.g. a self-hosted model server
+	APIKeyEnv   string        `yaml:"api_key_env"` // openai: environment variable holding the API key
+	Command     []string      `yaml:"command"`     // command: argv reading the prompt on stdin, with an optional {model}
+	Timeout     time.Duration `yaml:"timeout"`     // per evaluation, e.g. "2m"; default 60s
+}
+
+// agentConfig selects the coding agent evaluated by C7 (see agent.NewBackend).
+type agentConfig struct {
+	Backend   string   `yaml:"backend"`     // "claude" (default), "openai" or "command"
+	Model     string   `yaml:"model"`       // model name passed to the backend
+	BaseURL   string   `yaml:"base_url"`    // openai: API base URL, e.g. a local OpenAI-compatible server
+	APIKeyEnv string   `yaml:"api_key_env"` // openai: environment variable holding the API key
+	MaxTurns  int      `yaml:"max_turns"`   // openai: maximum tool-use round trips per prompt
+	Command   []string `yaml:"command"`     // command: argv template with {prompt}, {tools}, {workdir} and {model}
+
+	// Limits on the load put on the backend (see agent.Limits).
+	MaxConcurrency    int  `yaml:"max_concurrency"`     // agent calls in flight at once; default unlimited
+	RequestsPerMinute int  `yaml:"requests_per_minute"` // agent calls started per minute; default unlimited
+	MaxRetries        *int `yaml:"max_retries"`         // retries on rate limits and transient errors; default 3
+}
+
+// pluginConfig declares an external plugin executable (see pkg/plugin).
+type pluginConfig struct {
+	Command string        `yaml:"command"` // executable; relative paths are resolved against the project directory
+	Args    []string      `yaml:"args"`
+	Timeout time.Duration `yaml:"timeout"` // per invocation, e.g. "30s"; default 60s
 }
 
 // scoringOverrides contains weight and threshold overrides.
@@ -90,6 +180,90 @@ func (c *ProjectConfig) Validate() error {
 		return fmt.Errorf("threshold must be >= 0, got %f", c.Scoring.Threshold)
 	}
 
+	for i, p := range c.Plugins {
+		if p.Command == "" {
+			return fmt.Errorf("plugin %d has no command", i+1)
+		}
+		if p.Timeout < 0 {
+			return fmt.Errorf("plugin %s: timeout must be >= 0, got %s", p.Command, p.Timeout)
+		}
+	}
+
+	if c.Agent.Backend != "" && !slices.Contains(agent.BackendNames(), c.Agent.Backend) {
+		return fmt.Errorf("unknown agent backend %q (available: %s)", c.Agent.Backend, strings.Join(agent.BackendNames(), ", "))
+	}
+	if c.Agent.MaxTurns < 0 {
+		return fmt.Errorf("agent max_turns must be >= 0, got %d", c.Agent.MaxTurns)
+	}
+	if c.Agent.MaxConcurrency < 0 {
+		return fmt.Errorf("agent max_concurrency must be >= 0, got %d", c.Agent.MaxConcurrency)
+	}
+	if c.Agent.RequestsPerMinute < 0 {
+		return fmt.Errorf("agent requests_per_minute must be >= 0, got %d", c.Agent.RequestsPerMinute)
+	}
+	if r := c.Agent.MaxRetries; r != nil && *r < 0 {
+		return fmt.Errorf("agent max_retries must be >= 0, got %d", *r)
+	}
+
+	if c.C7.Consistency.Runs < 0 {
+		return fmt.Errorf("c7 consistency runs must be >= 0, got %d", c.C7.Consistency.Runs)
+	}
+	if c.C7.Consistency.Samples < 0 {
+		return fmt.Errorf("c7 consistency samples must be >= 0, got %d", c.C7.Consistency.Samples)
+	}
+	if s := c.C7.Scoring; s != "" && s != "heuristic" && s != "judge" {
+		return fmt.Errorf("c7 scoring must be \"heuristic\" or \"judge\", got %q", s)
+	}
+
+	if c.Judge.Backend != "" && !slices.Contains(agent.JudgeNames(), c.Judge.Backend) {
+		return fmt.Errorf("unknown judge backend %q (available: %s)", c.Judge.Backend, strings.Join(agent.JudgeNames(), ", "))
+	}
+	if c.Judge.Temperature != nil && (*c.Judge.Temperature < 0 || *c.Judge.Temperature > 2) {
+		return fmt.Errorf("judge temperature must be between 0 and 2, got %g", *c.Judge.Temperature)
+	}
+	if c.Judge.Timeout < 0 {
+		return fmt.Errorf("judge timeout must be >= 0, got %s", c.Judge.Timeout)
+	}
+
+	if c.LLMCache.TTL < 0 {
+		return fmt.Errorf("llm_cache ttl must be >= 0, got %s", c.LLMCache.TTL)
+	}
+	if c.LLMCache.MaxMB < 0 {
+		return fmt.Errorf("llm_cache max_mb must be >= 0, got %d", c.LLMCache.MaxMB)
+	}
+
+	for model, p := range c.Pricing {
+		if p.Input < 0 || p.Output < 0 {
+			return fmt.Errorf("pricing for %q must be >= 0", model)
+		}
+	}
+
+	for _, g := range c.Include {
+		if !discovery.ValidGlob(g) {
+			return fmt.Errorf("invalid include glob %q", g)
+		}
+	}
+	for _, g := range c.Exclude {
+		if !discovery.ValidGlob(g) {
+			return fmt.Errorf("invalid exclude glob %q", g)
+		}
+	}
+	for _, g := range c.Generated.Paths {
+		if !discovery.ValidGlob(g) {
+			return fmt.Errorf("invalid generated.paths glob %q", g)
+		}
+	}
+	for _, g := range c.Generated.HandWritten {
+		if !discovery.ValidGlob(g) {
+			return fmt.Errorf("invalid generated.hand_written glob %q", g)
+		}
+	}
+	for _, m := range c.Generated.Markers {
+		if strings.TrimSpace(m) == "" {
+			return fmt.Errorf("generated.markers must not contain empty markers")
+		}
+	}
+
 	return nil
 }
 
@@ -99,11 +273,191 @@ func (c *ProjectConfig) ApplyToScoringConfig(sc *scoring.ScoringConfig) {
 		return
 	}
 
-	// Override category weights
+	// Override category weights. Unknown categories may belong to plugins,
+	// whose defaults are merged in later (see ScoringConfig.AddPluginDefaults).
+	if sc.Categories == nil {
+		sc.Categories = make(map[string]scoring.CategoryConfig)
+	}
 	for catName, weight := range c.Scoring.Weights {
-		if cat, ok := sc.Categories[catName]; ok {
-			cat.Weight = weight
-			sc.Categories[catName] = cat
+		cat := sc.Categories[catName]
+		cat.Weight = weight
+		sc.Categories[catName] = cat
+	}
+}
+
+// LoadPlugins starts the external plugins declared in the config, asks each
+// for its category and returns them for a scan of dir (see
+// pipeline.Pipeline.SetPlugins). The plugins are commands from the scanned
+// project, so callers run them only when the user allows it.
+func (c *ProjectConfig) LoadPlugins(dir string) ([]plugin.Plugin, error) {
+	if c == nil {
+		return nil, nil
+	}
+	var plugins []plugin.Plugin
+	for _, pc := range c.Plugins {
+		command := pc.Command
+		if strings.ContainsRune(command, '/') && !filepath.IsAbs(command) {
+			command = filepath.Join(dir, command)
+		}
+		p, err := plugin.LoadCommand(command, pc.Args, dir, pc.Timeout)
+		if err != nil {
+			return nil, err
 		}
+		plugins = append(plugins, p)
+	}
+	if err := plugin.Check(plugins); err != nil {
+		return nil, err
+	}
+	return plugins, nil
+}
+
+// PluginCommands returns the commands of the external plugins declared in
+// the config.
+func (c *ProjectConfig) PluginCommands() []string {
+	if c == nil {
+		return nil
+	}
+	var commands []string
+	for _, pc := range c.Plugins {
+		commands = append(commands, pc.Command)
+	}
+	return commands
+}
+
+// PathRules returns the configured include and exclude globs of file
+// discovery. Without a config file only the default skip list applies.
+func (c *ProjectConfig) PathRules() discovery.PathRules {
+	if c == nil {
+		return discovery.PathRules{}
+	}
+	return discovery.PathRules{Include: c.Include, Exclude: c.Exclude}
+}
+
+// GeneratedRules returns the configured additions to generated-file
+// detection. Without a config file only the built-in conventions apply.
+func (c *ProjectConfig) GeneratedRules() discovery.GeneratedRules {
+	if c == nil {
+		return discovery.GeneratedRules{}
+	}
+	return discovery.GeneratedRules{
+		Paths:       c.Generated.Paths,
+		Markers:     c.Generated.Markers,
+		HandWritten: c.Generated.HandWritten,
+	}
+}
+
+// CacheKey returns the configuration hash of the per-file results cache: a
+// digest of the settings that decide what is analyzed, the path and
+// generated-file rules. Scoring settings only apply after analysis, so
+// changing them keeps the cached results.
+func (c *ProjectConfig) CacheKey() string {
+	return cache.ConfigHash(struct {
+		Paths     discovery.PathRules
+		Generated discovery.GeneratedRules
+	}{c.PathRules(), c.GeneratedRules()})
+}
+
+// AgentBackend returns the configured C7 agent backend, or nil if the config
+// has no agent section and the default Claude CLI applies.
+func (c *ProjectConfig) AgentBackend() (agent.Backend, error) {
+	if c == nil || (c.Agent.Backend == "" && c.Agent.Model == "") {
+		return nil, nil
+	}
+	return agent.NewBackend(agent.BackendConfig{
+		Backend:   c.Agent.Backend,
+		Model:     c.Agent.Model,
+		BaseURL:   c.Agent.BaseURL,
+		APIKeyEnv: c.Agent.APIKeyEnv,
+		MaxTurns:  c.Agent.MaxTurns,
+		Command:   c.Agent.Command,
+	})
+}
+
+// AgentLimits returns the configured concurrency, rate and retry limits of C7
+// agent calls. Without a config file only the default retries apply.
+func (c *ProjectConfig) AgentLimits() agent.Limits {
+	limits := agent.Limits{MaxRetries: agent.DefaultMaxRetries}
+	if c == nil {
+		return limits
+	}
+	limits.MaxConcurrency = c.Agent.MaxConcurrency
+	limits.RequestsPerMinute = c.Agent.RequestsPerMinute
+	if c.Agent.MaxRetries != nil {
+		limits.MaxRetries = *c.Agent.MaxRetries
+	}
+	return limits
+}
+
+// C7MetricOptions returns the configured C7 metric options with the
+// user-defined tasks in dir's .ars/tasks directory; zero values keep the
+// defaults. Tasks are loaded even without a config file.
+func (c *ProjectConfig) C7MetricOptions(dir string) (metrics.Options, error) {
+	var opts metrics.Options
+	if c != nil {
+		opts.ConsistencyRuns = c.C7.Consistency.Runs
+		opts.ConsistencySamples = c.C7.Consistency.Samples
+		opts.JudgeScoring = c.C7.Scoring == "judge"
+	}
+	tasks, err := metrics.LoadTasks(dir)
+	if err != nil {
+		return metrics.Options{}, err
+	}
+	opts.Tasks = tasks
+	return opts, nil
+}
+
+// C4Judge returns the configured C4 judge, or nil if the config has no judge
+// section and the default Claude CLI applies.
+func (c *ProjectConfig) C4Judge() (agent.Judge, error) {
+	if c == nil || (c.Judge.Backend == "" && c.Judge.Model == "") {
+		return nil, nil
+	}
+	return agent.NewJudge(agent.JudgeConfig{
+		Backend:     c.Judge.Backend,
+		Model:       c.Judge.Model,
+		Temperature: c.Judge.Temperature,
+		BaseURL:     c.Judge.BaseURL,
+		APIKeyEnv:   c.Judge.APIKeyEnv,
+		Command:     c.Judge.Command,
+		Timeout:     c.Judge.Timeout,
+	})
+}
+
+// LLMCacheLimits returns the configured TTL and size limit in bytes of the LLM
+// response cache; zero values keep the defaults.
+func (c *ProjectConfig) LLMCacheLimits() (ttl time.Duration, maxBytes int64) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.LLMCache.TTL, c.LLMCache.MaxMB << 20
+}
+
+// PricedModels returns the models configured for the agent and the judge
+// whose usage is priced from the price table: those of openai backends. The
+// Claude CLI reports its own cost and command backends are estimated.
+func (c *ProjectConfig) PricedModels() []string {
+	if c == nil {
+		return nil
+	}
+	var models []string
+	if c.Agent.Backend == "openai" {
+		models = append(models, c.Agent.Model)
+	}
+	if c.Judge.Backend == "openai" {
+		models = append(models, c.Judge.Model)
+	}
+	return models
+}
+
+// LLMPrices returns the configured per-model prices, which override the
+// built-in ones (agent.DefaultPrices).
+func (c *ProjectConfig) LLMPrices() agent.PriceTable {
+	if c == nil || len(c.Pricing) == 0 {
+		return nil
+	}
+	prices := make(agent.PriceTable, len(c.Pricing))
+	for model, p := range c.Pricing {
+		prices[model] = agent.Price{InputPerMTok: p.Input, OutputPerMTok: p.Output}
 	}
+	return prices
 }
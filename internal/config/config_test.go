@@ -3,8 +3,11 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
 	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
 )
 
@@ -183,3 +186,381 @@ func TestValidate_NegativeThreshold(t *testing.T) {
 		t.Error("expected error for negative threshold")
 	}
 }
+
+func TestLoadProjectConfig_Plugins(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: 1
+scoring:
+  weights:
+    C8: 0.05
+plugins:
+  - command: ./tools/ars-security
+    args: ["--strict"]
+    timeout: 30s
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	if len(cfg.Plugins) != 1 || cfg.Plugins[0].Command != "./tools/ars-security" || cfg.Plugins[0].Timeout != 30*time.Second {
+		t.Errorf("Plugins = %+v", cfg.Plugins)
+	}
+
+	// Weights of plugin categories are kept until the plugin adds its metrics.
+	sc := scoring.DefaultConfig()
+	cfg.ApplyToScoringConfig(sc)
+	if sc.Categories["C8"].Weight != 0.05 {
+		t.Errorf("C8 weight = %v, want 0.05", sc.Categories["C8"].Weight)
+	}
+
+	if got := cfg.PluginCommands(); len(got) != 1 || got[0] != "./tools/ars-security" {
+		t.Errorf("PluginCommands() = %v", got)
+	}
+
+	// The command does not exist, so loading fails.
+	if _, err := cfg.LoadPlugins(tmpDir); err == nil {
+		t.Error("expected error for missing plugin executable")
+	}
+
+	cfg.Plugins[0].Command = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for plugin without command")
+	}
+}
+
+func TestLoadProjectConfig_Agent(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: 1
+agent:
+  backend: openai
+  model: qwen2.5-coder
+  base_url: http://localhost:11434/v1
+  max_turns: 8
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	backend, err := cfg.AgentBackend()
+	if err != nil {
+		t.Fatalf("AgentBackend() error: %v", err)
+	}
+	if backend == nil || backend.Name() != "openai (qwen2.5-coder)" {
+		t.Errorf("backend = %v, want openai (qwen2.5-coder)", backend)
+	}
+
+	// No agent section keeps the default Claude CLI.
+	if b, err := (&ProjectConfig{}).AgentBackend(); b != nil || err != nil {
+		t.Errorf("AgentBackend() without agent section = %v, %v; want nil, nil", b, err)
+	}
+
+	cfg.Agent.Backend = "gemini"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown agent backend")
+	}
+}
+
+func TestLoadProjectConfig_AgentLimits(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: 1
+agent:
+  max_concurrency: 2
+  requests_per_minute: 30
+  max_retries: 0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	if got, want := cfg.AgentLimits(), (agent.Limits{MaxConcurrency: 2, RequestsPerMinute: 30}); got != want {
+		t.Errorf("AgentLimits() = %+v, want %+v", got, want)
+	}
+	if got := (*ProjectConfig)(nil).AgentLimits(); got != (agent.Limits{MaxRetries: agent.DefaultMaxRetries}) {
+		t.Errorf("AgentLimits() without config = %+v, want default retries only", got)
+	}
+
+	cfg.Agent.RequestsPerMinute = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative requests_per_minute")
+	}
+}
+
+func TestLoadProjectConfig_C7(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: 1
+c7:
+  consistency:
+    runs: 5
+    samples: 2
+  scoring: judge
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	opts, err := cfg.C7MetricOptions(tmpDir)
+	if err != nil {
+		t.Fatalf("C7MetricOptions() error: %v", err)
+	}
+	if opts.ConsistencyRuns != 5 || opts.ConsistencySamples != 2 || !opts.JudgeScoring {
+		t.Errorf("C7MetricOptions() = %+v, want 5 runs, 2 samples and judge scoring", opts)
+	}
+
+	cfg.C7.Scoring = "llm"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown c7 scoring mode")
+	}
+	cfg.C7.Scoring = ""
+
+	cfg.C7.Consistency.Runs = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative consistency runs")
+	}
+}
+
+func TestC7MetricOptions_Tasks(t *testing.T) {
+	tmpDir := t.TempDir()
+	tasksDir := filepath.Join(tmpDir, ".ars", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	suite := `tasks:
+  - id: explain_handler
+    prompt: "Explain {file}."
+    rubric:
+      negative: ["not sure"]
+`
+	if err := os.WriteFile(filepath.Join(tasksDir, "api.yml"), []byte(suite), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tasks are loaded without a .arsrc.yml
+	var cfg *ProjectConfig
+	opts, err := cfg.C7MetricOptions(tmpDir)
+	if err != nil {
+		t.Fatalf("C7MetricOptions() error: %v", err)
+	}
+	if len(opts.Tasks) != 1 || opts.Tasks[0].ID != "explain_handler" {
+		t.Errorf("C7MetricOptions().Tasks = %+v, want explain_handler", opts.Tasks)
+	}
+
+	if err := os.WriteFile(filepath.Join(tasksDir, "bad.yml"), []byte("tasks:\n  - id: Bad\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.C7MetricOptions(tmpDir); err == nil {
+		t.Error("expected error for invalid task suite")
+	}
+}
+
+func TestLoadProjectConfig_Judge(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	content := `version: 1
+judge:
+  backend: openai
+  model: llama3.1:70b
+  base_url: http://llm.internal:8000/v1
+  temperature: 0.2
+  timeout: 2m
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	judge, err := cfg.C4Judge()
+	if err != nil {
+		t.Fatalf("C4Judge() error: %v", err)
+	}
+	if got := judge.Info().String(); got != "openai (llama3.1:70b, temperature 0.2)" {
+		t.Errorf("judge = %q", got)
+	}
+
+	temperature := 3.0
+	cfg.Judge.Temperature = &temperature
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for temperature above 2")
+	}
+	cfg.Judge.Temperature = nil
+	cfg.Judge.Backend = "bard"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown judge backend")
+	}
+}
+
+func TestLoadProjectConfig_LLMCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "llm_cache:\n  ttl: 72h\n  max_mb: 64\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	ttl, maxBytes := cfg.LLMCacheLimits()
+	if ttl != 72*time.Hour || maxBytes != 64<<20 {
+		t.Errorf("LLMCacheLimits() = %s, %d, want 72h, 64 MiB", ttl, maxBytes)
+	}
+
+	var none *ProjectConfig
+	if ttl, maxBytes := none.LLMCacheLimits(); ttl != 0 || maxBytes != 0 {
+		t.Errorf("nil config LLMCacheLimits() = %s, %d, want defaults", ttl, maxBytes)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte("llm_cache:\n  max_mb: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectConfig(tmpDir, ""); err == nil {
+		t.Error("expected error for negative llm_cache max_mb")
+	}
+}
+
+func TestLoadProjectConfig_Pricing(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "pricing:\n  my-model:\n    input: 0.5\n    output: 1.5\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	prices := cfg.LLMPrices()
+	if p := prices["my-model"]; p.InputPerMTok != 0.5 || p.OutputPerMTok != 1.5 {
+		t.Errorf("LLMPrices()[my-model] = %+v, want 0.5/1.5", p)
+	}
+
+	cfg.Agent = agentConfig{Backend: "openai", Model: "my-model"}
+	cfg.Judge = judgeConfig{Backend: "command", Model: "local"}
+	if got := cfg.PricedModels(); !reflect.DeepEqual(got, []string{"my-model"}) {
+		t.Errorf("PricedModels() = %v, want only the openai agent's model", got)
+	}
+
+	cfg.Pricing["my-model"] = priceConfig{Input: -1}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative price")
+	}
+}
+
+func TestLoadProjectConfig_PathRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "include:\n  - build\nexclude:\n  - \"**/gen\"\n  - \"**/*_pb2.py\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	rules := cfg.PathRules()
+	if len(rules.Include) != 1 || rules.Include[0] != "build" {
+		t.Errorf("PathRules().Include = %v, want [build]", rules.Include)
+	}
+	if len(rules.Exclude) != 2 || rules.Exclude[1] != "**/*_pb2.py" {
+		t.Errorf("PathRules().Exclude = %v, want [**/gen **/*_pb2.py]", rules.Exclude)
+	}
+
+	var none *ProjectConfig
+	if rules := none.PathRules(); rules.Include != nil || rules.Exclude != nil {
+		t.Errorf("nil config PathRules() = %+v, want none", rules)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte("exclude:\n  - \"gen/[a-z\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectConfig(tmpDir, ""); err == nil {
+		t.Error("expected error for malformed exclude glob")
+	}
+}
+
+func TestLoadProjectConfig_GeneratedRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	content := "generated:\n  paths:\n    - \"src/client/**\"\n  markers:\n    - \"Generated by acme-gen\"\n  hand_written:\n    - \"app/migrations/**\"\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadProjectConfig(tmpDir, "")
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	rules := cfg.GeneratedRules()
+	if len(rules.Paths) != 1 || rules.Paths[0] != "src/client/**" {
+		t.Errorf("GeneratedRules().Paths = %v, want [src/client/**]", rules.Paths)
+	}
+	if len(rules.Markers) != 1 || rules.Markers[0] != "Generated by acme-gen" {
+		t.Errorf("GeneratedRules().Markers = %v, want [Generated by acme-gen]", rules.Markers)
+	}
+	if len(rules.HandWritten) != 1 || rules.HandWritten[0] != "app/migrations/**" {
+		t.Errorf("GeneratedRules().HandWritten = %v, want [app/migrations/**]", rules.HandWritten)
+	}
+
+	for _, bad := range []string{
+		"generated:\n  paths:\n    - \"gen/[a-z\"\n",
+		"generated:\n  markers:\n    - \" \"\n",
+	} {
+		if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadProjectConfig(tmpDir, ""); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestProjectConfig_CacheKey(t *testing.T) {
+	load := func(content string) *ProjectConfig {
+		t.Helper()
+		tmpDir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(tmpDir, ".arsrc.yml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadProjectConfig(tmpDir, "")
+		if err != nil {
+			t.Fatalf("LoadProjectConfig() error: %v", err)
+		}
+		return cfg
+	}
+
+	var none *ProjectConfig
+	base := load("version: 1\n")
+	if none.CacheKey() != base.CacheKey() {
+		t.Error("nil config and empty config have different cache keys")
+	}
+	if got := load("version: 1\nscoring:\n  threshold: 9\n").CacheKey(); got != base.CacheKey() {
+		t.Error("scoring settings changed the cache key")
+	}
+	if got := load("exclude:\n  - gen\n").CacheKey(); got == base.CacheKey() {
+		t.Error("exclude globs did not change the cache key")
+	}
+	if got := load("generated:\n  markers:\n    - acme-gen\n").CacheKey(); got == base.CacheKey() {
+		t.Error("generated markers did not change the cache key")
+	}
+}
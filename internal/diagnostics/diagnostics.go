@@ -0,0 +1,32 @@
+// Package diagnostics carries the problems met during a scan from the stage
+// that meets them to the pipeline that reports them. Stages report through
+// the context, so they need no extra return values or writers.
+package diagnostics
+
+import (
+	"context"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+type recorderKey struct{}
+
+// WithRecorder returns a context whose Report calls fn, then any recorder of
+// the parent context. Recorders may be called concurrently.
+func WithRecorder(ctx context.Context, fn func(types.Diagnostic)) context.Context {
+	parent, _ := ctx.Value(recorderKey{}).(func(types.Diagnostic))
+	return context.WithValue(ctx, recorderKey{}, func(d types.Diagnostic) {
+		fn(d)
+		if parent != nil {
+			parent(d)
+		}
+	})
+}
+
+// Report hands d to the context's recorders. Without a recorder the
+// diagnostic is dropped.
+func Report(ctx context.Context, d types.Diagnostic) {
+	if record, ok := ctx.Value(recorderKey{}).(func(types.Diagnostic)); ok {
+		record(d)
+	}
+}
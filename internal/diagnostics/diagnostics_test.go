@@ -0,0 +1,25 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestReportWithoutRecorder(t *testing.T) {
+	// Must not panic.
+	Report(context.Background(), types.Diagnostic{Message: "dropped"})
+}
+
+func TestReportCallsNestedRecorders(t *testing.T) {
+	var outer, inner []string
+	ctx := WithRecorder(context.Background(), func(d types.Diagnostic) { outer = append(outer, d.Message) })
+	ctx = WithRecorder(ctx, func(d types.Diagnostic) { inner = append(inner, d.Message) })
+
+	Report(ctx, types.Diagnostic{Severity: types.SeverityWarning, Message: "a"})
+
+	if len(inner) != 1 || len(outer) != 1 {
+		t.Fatalf("inner = %v, outer = %v, want one diagnostic each", inner, outer)
+	}
+}
@@ -84,7 +84,7 @@ func TestClassifyTypeScriptFile(t *testing.T) {
 	}
 }
 
-func TestIsGeneratedFile(t *testing.T) {
+func TestHasGeneratedHeaderGo(t *testing.T) {
 	// Create temp files for testing
 	tmpDir := t.TempDir()
 
@@ -134,12 +134,12 @@ func main() {}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := isGeneratedFile(tt.path)
+			got, err := hasGeneratedHeader(tt.path, filepath.Base(tt.path), types.LangGo, nil)
 			if err != nil {
-				t.Fatalf("isGeneratedFile(%q) unexpected error: %v", tt.path, err)
+				t.Fatalf("hasGeneratedHeader(%q) unexpected error: %v", tt.path, err)
 			}
 			if got != tt.want {
-				t.Errorf("isGeneratedFile(%q) = %v, want %v", tt.path, got, tt.want)
+				t.Errorf("hasGeneratedHeader(%q) = %v, want %v", tt.path, got, tt.want)
 			}
 		})
 	}
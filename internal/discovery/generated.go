@@ -0,0 +1,207 @@
+package discovery
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// maxHeaderLines bounds how far into a file the header comment is searched
+// for a generated-code marker.
+const maxHeaderLines = 100
+
+// GeneratedRules adjust which files are classified as generated, on top of
+// the built-in header markers and file name conventions. Globs are matched
+// like the globs of PathRules, relative to its Dir.
+type GeneratedRules struct {
+	Paths       []string // globs of generated files
+	Markers     []string // header comment text marking a file as generated, matched case-insensitively
+	HandWritten []string // globs of files never classified as generated, for false positives
+}
+
+// generatedNames are the file name conventions of common code generators.
+// Go relies on its "Code generated ... DO NOT EDIT." header instead.
+var generatedNames = map[types.Language][]string{
+	types.LangPython: {
+		"**/*_pb2.py",      // protobuf
+		"**/*_pb2_grpc.py", // gRPC
+		"**/alembic/versions/*.py",
+		"**/migrations/versions/*.py",             // Alembic via Flask-Migrate
+		"**/migrations/[0-9][0-9][0-9][0-9]_*.py", // Django
+	},
+	types.LangTypeScript: {
+		"**/*.d.ts",         // declaration files
+		"**/*.generated.ts", // GraphQL Code Generator and others
+		"**/*.generated.tsx",
+		"**/*.gen.ts",
+		"**/*_pb.ts", // protobuf-ts, ts-protoc-gen
+		"**/__generated__/**",
+	},
+}
+
+// generatedMarkers match the header lines of common code generators in
+// Python and TypeScript, with the comment delimiters stripped (see
+// headerText). They are anchored to the generators' own wording so that
+// prose mentioning generated code or asking not to edit a file does not
+// match.
+var generatedMarkers = []*regexp.Regexp{
+	// Go's convention, also written by protoc-gen-ts_proto and buf
+	regexp.MustCompile(`^Code generated .* DO NOT EDIT\.?$`),
+	// protoc and others: a generated notice with DO NOT EDIT in capitals
+	regexp.MustCompile(`(?i:generated)\b.*\bDO NOT EDIT\b`),
+	// GraphQL Code Generator, protoc-gen-es, Relay
+	regexp.MustCompile(`(^|\s)@generated(\s|$)`),
+	// protoc, OpenAPI Generator, Django migrations
+	regexp.MustCompile(`(?i)^generated by (the protocol buffer compiler|openapi generator|django \d)`),
+	// OpenAPI Generator and swagger codegen class banners
+	regexp.MustCompile(`(?i)^note: this class is auto generated by (openapi generator|the swagger code generator program)`),
+	// swagger-typescript-api and other banners
+	regexp.MustCompile(`(?i)^this file (was|is) (auto-?|automatically )?generated (by|via|from) `),
+}
+
+// alembicRevision matches the "Revision ID:" line of an Alembic migration
+// docstring. It only marks Python files in a versions/ directory, where
+// Alembic keeps its migrations whatever the script location is called.
+var alembicRevision = regexp.MustCompile(`^Revision ID: \w+$`)
+
+// alembicVersionsGlob matches the files alembicRevision applies to.
+const alembicVersionsGlob = "**/versions/*.py"
+
+// isGenerated reports whether the file at path, with slash-separated path
+// rel relative to the project root, is generated code: by the rules' globs,
+// by a file name convention of its language, or by a marker in its header
+// comment.
+func (r GeneratedRules) isGenerated(path, rel string, lang types.Language) (bool, error) {
+	if _, ok := matchAncestors(r.HandWritten, rel); ok {
+		return false, nil
+	}
+	if _, ok := matchAncestors(r.Paths, rel); ok {
+		return true, nil
+	}
+	for _, g := range generatedNames[lang] {
+		if MatchGlob(g, rel) {
+			return true, nil
+		}
+	}
+	return hasGeneratedHeader(path, rel, lang, r.Markers)
+}
+
+// hasGeneratedHeader reports whether the header comment of a file, the
+// comments before its first line of code, carries a generated-code marker.
+// Go files must use Go's "// Code generated ... DO NOT EDIT." convention;
+// other languages may use any of generatedMarkers, and Alembic migrations
+// their revision header. extra markers, matched as case-insensitive
+// substrings, apply to all languages. rel is the slash-separated path of the
+// file relative to the project root.
+func hasGeneratedHeader(path, rel string, lang types.Language, extra []string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	markers := generatedMarkers
+	if lang == types.LangPython && MatchGlob(alembicVersionsGlob, rel) {
+		markers = append(markers[:len(markers):len(markers)], alembicRevision)
+	}
+
+	h := headerScanner{lang: lang}
+	scanner := bufio.NewScanner(f)
+	for n := 0; n < maxHeaderLines && scanner.Scan(); n++ {
+		line := scanner.Text()
+		if !h.inHeader(strings.TrimSpace(line)) {
+			return false, nil
+		}
+		if lang == types.LangGo && generatedPattern.MatchString(line) {
+			return true, nil
+		}
+		if lang != types.LangGo && matchesAny(headerText(line), markers) {
+			return true, nil
+		}
+		if containsAnyFold(strings.ToLower(line), extra) {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// headerScanner follows the comments at the top of a file line by line.
+type headerScanner struct {
+	lang     types.Language
+	blockEnd string // closing delimiter of the open block comment or docstring
+}
+
+// inHeader reports whether the trimmed line still belongs to the header: a
+// blank line or part of a comment, or for Python a module docstring.
+func (h *headerScanner) inHeader(line string) bool {
+	if h.blockEnd != "" {
+		if strings.Contains(line, h.blockEnd) {
+			h.blockEnd = ""
+		}
+		return true
+	}
+	if line == "" {
+		return true
+	}
+
+	if h.lang == types.LangPython {
+		if strings.HasPrefix(line, "#") {
+			return true
+		}
+		for _, q := range []string{`"""`, `'''`} {
+			if rest, ok := strings.CutPrefix(line, q); ok {
+				if !strings.Contains(rest, q) {
+					h.blockEnd = q
+				}
+				return true
+			}
+		}
+		return false
+	}
+
+	if strings.HasPrefix(line, "//") {
+		return true
+	}
+	if rest, ok := strings.CutPrefix(line, "/*"); ok {
+		if !strings.Contains(rest, "*/") {
+			h.blockEnd = "*/"
+		}
+		return true
+	}
+	return false
+}
+
+// headerText returns the text of a header line without its comment or
+// docstring delimiters, e.g. "Code generated by x. DO NOT EDIT." for
+// "// Code generated by x. DO NOT EDIT." or " * Code generated ...".
+func headerText(line string) string {
+	line = strings.TrimLeft(strings.TrimSpace(line), "#/*\"' \t")
+	return strings.TrimRight(line, "#/*\"' \t")
+}
+
+// matchesAny reports whether s matches any of the patterns.
+func matchesAny(s string, patterns []*regexp.Regexp) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAnyFold reports whether the lower-case s contains any of subs,
+// ignoring case.
+func containsAnyFold(s string, subs []string) bool {
+	for _, sub := range subs {
+		if sub != "" && strings.Contains(s, strings.ToLower(sub)) {
+			return true
+		}
+	}
+	return false
+}
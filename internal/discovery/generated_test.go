@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestHasGeneratedHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    bool
+	}{
+		{"protoc python", "api_pb.py", "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\n# source: api.proto\nimport sys\n", true},
+		{"openapi python docstring", "client.py", "# coding: utf-8\n\n\"\"\"\n    Petstore API\n\n    The version of the OpenAPI document: 1.0.0\n    Generated by OpenAPI Generator (https://openapi-generator.tech)\n\n    Do not edit the class manually.\n\"\"\"  # noqa: E501\n\nimport re\n", true},
+		{"alembic docstring", "db/versions/add_users.py", "\"\"\"add users table\n\nRevision ID: 1a2b3c\nRevises:\nCreate Date: 2024-01-01\n\n\"\"\"\nfrom alembic import op\n", true},
+		{"revision id outside versions", "release.py", "\"\"\"Release helpers.\n\nRevision ID: 1a2b3c\n\"\"\"\nimport os\n", false},
+		{"python marker after code", "app.py", "import os\n\n# auto-generated values below\nX = 1\n", false},
+		{"plain python", "app.py", "\"\"\"Application entry point.\"\"\"\n\nimport os\n", false},
+		{"graphql codegen ts", "types.ts", "/* eslint-disable */\n// @generated\nexport type Query = {};\n", true},
+		{"openapi ts block", "api.ts", "/* tslint:disable */\n/**\n * Petstore API\n * NOTE: This class is auto generated by OpenAPI Generator.\n * Do not edit the class manually.\n */\nexport class Api {}\n", true},
+		{"swagger-typescript-api banner", "Api.ts", "/* eslint-disable */\n/*\n * ---------------------------------------------------------------\n * ## THIS FILE WAS GENERATED VIA SWAGGER-TYPESCRIPT-API        ##\n * ---------------------------------------------------------------\n */\nexport class Api {}\n", true},
+		{"ts marker after code", "index.ts", "import x from './x';\n// do not edit\nexport {}\n", false},
+		{"go needs the go convention", "gen.go", "// @generated\npackage gen\n", false},
+		{"docstring mentions auto-generated ids", "ids.py", "\"\"\"Helpers that return auto-generated IDs.\n\nIDs are automatically generated on insert.\n\"\"\"\nimport uuid\n", false},
+		{"header asks not to edit", "schema.py", "# Do not edit without updating the schema\nFIELDS = []\n", false},
+		{"autogenerated in prose", "docs.ts", "/**\n * Builds the autogenerated API reference. Code generated here is checked in.\n */\nexport function build() {}\n", false},
+		{"generated mentioned in ts comment", "codegen.ts", "// This file was written to wrap the @generated-client package.\nexport {}\n", false},
+		{"code generated ts with go convention", "client.ts", "// Code generated by protoc-gen-ts_proto. DO NOT EDIT.\nexport {}\n", true},
+		{"django migration", "0002_auto.py", "# Generated by Django 4.2 on 2024-01-01 12:00\n\nfrom django.db import migrations\n", true},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), filepath.Base(tt.file))
+			writeFile(t, path, tt.content)
+			got, err := hasGeneratedHeader(path, tt.file, LanguageForFile(tt.file), nil)
+			if err != nil {
+				t.Fatalf("hasGeneratedHeader(%q) unexpected error: %v", tt.file, err)
+			}
+			if got != tt.want {
+				t.Errorf("hasGeneratedHeader(%q) = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+
+	custom := filepath.Join(dir, "models.py")
+	writeFile(t, custom, "# Built by ACME-Gen from schema.json\nclass Model: pass\n")
+	if got, _ := hasGeneratedHeader(custom, "models.py", types.LangPython, nil); got {
+		t.Error("custom marker detected without being configured")
+	}
+	if got, _ := hasGeneratedHeader(custom, "models.py", types.LangPython, []string{"built by acme-gen"}); !got {
+		t.Error("configured marker not detected")
+	}
+}
+
+func TestWalkerGeneratedFiles(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, "main.go", "package main\n")
+	writeTree(t, root, "zz_generated.go", "// Code generated by controller-gen. DO NOT EDIT.\n\npackage main\n")
+	writeTree(t, root, "py/app.py", "x = 1\n")
+	writeTree(t, root, "py/api_pb2.py", "x = 1\n")
+	writeTree(t, root, "py/api_pb2_grpc.py", "x = 1\n")
+	writeTree(t, root, "py/alembic/versions/1a2b_add_users.py", "x = 1\n")
+	writeTree(t, root, "py/shop/migrations/0001_initial.py", "x = 1\n")
+	writeTree(t, root, "py/shop/migrations/helpers.py", "x = 1\n")
+	writeTree(t, root, "web/index.ts", "export {}\n")
+	writeTree(t, root, "web/global.d.ts", "declare const x: number;\n")
+	writeTree(t, root, "web/graphql.generated.ts", "export {}\n")
+	writeTree(t, root, "web/__generated__/Query.graphql.ts", "export {}\n")
+	writeTree(t, root, "web/client/api.ts", "export {}\n")
+	writeTree(t, root, "web/shims.d.ts", "declare module 'x';\n")
+
+	w := NewWalker()
+	w.SetGeneratedRules(GeneratedRules{
+		Paths:       []string{"web/client"},
+		HandWritten: []string{"web/shims.d.ts"},
+	})
+	result, files := discoverFiles(t, w, root)
+
+	for _, rel := range []string{"main.go", "py/app.py", "py/shop/migrations/helpers.py", "web/index.ts", "web/shims.d.ts"} {
+		assertFile(t, files, rel, types.ClassSource, "")
+	}
+	generated := []string{
+		"zz_generated.go",
+		"py/api_pb2.py",
+		"py/api_pb2_grpc.py",
+		"py/alembic/versions/1a2b_add_users.py",
+		"py/shop/migrations/0001_initial.py",
+		"web/global.d.ts",
+		"web/graphql.generated.ts",
+		"web/__generated__/Query.graphql.ts",
+		"web/client/api.ts",
+	}
+	for _, rel := range generated {
+		assertFile(t, files, rel, types.ClassGenerated, "")
+	}
+	if result.GeneratedCount != len(generated) {
+		t.Errorf("GeneratedCount = %d, want %d", result.GeneratedCount, len(generated))
+	}
+}
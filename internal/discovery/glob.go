@@ -0,0 +1,111 @@
+package discovery
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash-separated path rel matches pattern.
+// Segments match as in path.Match, a "**" segment matches any number of
+// segments, including none, and {a,b} matches either alternative.
+func MatchGlob(pattern, rel string) bool {
+	return matchAlternatives(pattern, rel, false)
+}
+
+// matchGlobPrefix reports whether pattern can match rel or a path below it,
+// which decides whether a directory must be walked to find the matches.
+func matchGlobPrefix(pattern, rel string) bool {
+	return matchAlternatives(pattern, rel, true)
+}
+
+// matchAlternatives matches rel against each brace expansion of pattern.
+func matchAlternatives(pattern, rel string, prefix bool) bool {
+	parts := strings.Split(rel, "/")
+	for _, alt := range expandBraces(pattern) {
+		if matchSegments(strings.Split(alt, "/"), parts, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandBraces expands the {a,b} groups of pattern, which may nest, into the
+// patterns they stand for. Unbalanced or escaped braces are kept literally.
+func expandBraces(pattern string) []string {
+	open, depth := -1, 0
+	var commas []int
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			if depth == 0 {
+				open = i
+				commas = commas[:0]
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth > 0 {
+				continue
+			}
+			var expanded []string
+			start := open + 1
+			for _, end := range append(commas, i) {
+				alt := pattern[:open] + pattern[start:end] + pattern[i+1:]
+				expanded = append(expanded, expandBraces(alt)...)
+				start = end + 1
+			}
+			return expanded
+		}
+	}
+	return []string{pattern}
+}
+
+// matchSegments matches path segments against pattern segments. With prefix,
+// running out of path segments before the pattern ends is a match.
+func matchSegments(pattern, parts []string, prefix bool) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			if prefix {
+				return true
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:], false) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return prefix
+		}
+		if ok, err := path.Match(pattern[0], parts[0]); err != nil || !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// ValidGlob reports whether pattern is a well-formed glob for MatchGlob.
+func ValidGlob(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	for _, alt := range expandBraces(pattern) {
+		for _, seg := range strings.Split(alt, "/") {
+			if _, err := path.Match(seg, ""); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package discovery
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"packages/*", "packages/web", true},
+		{"packages/*", "packages/web/src", false},
+		{"packages/**", "packages/web/src", true},
+		{"**/gen", "gen", true},
+		{"**/gen", "api/v1/gen", true},
+		{"apps/*/ui", "apps/admin/ui", true},
+		{"apps/*/ui", "apps/ui", false},
+		{"*.pb.go", "api.pb.go", true},
+		{"src/**/*.{ts,tsx}", "src/components/App.tsx", true},
+		{"src/**/*.{ts,tsx}", "src/app.js", false},
+		{"{cmd,internal/{api,db}}/*.go", "internal/db/conn.go", true},
+		{"{cmd,internal/{api,db}}/*.go", "internal/web/app.go", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"{a,b", "{a,b", true},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGlobPrefix(t *testing.T) {
+	tests := []struct {
+		pattern, rel string
+		want         bool
+	}{
+		{"build/pkg/**", "build", true},
+		{"build/pkg/**", "build/pkg", true},
+		{"build/pkg", "build", true},
+		{"build/pkg", "dist", false},
+		{"**/gen", "node_modules", true},
+		{"tools/*/build", "tools/x", true},
+		{"{tools,scripts}/gen", "scripts", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlobPrefix(tt.pattern, tt.rel); got != tt.want {
+			t.Errorf("matchGlobPrefix(%q, %q) = %v, want %v", tt.pattern, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestValidGlob(t *testing.T) {
+	for _, g := range []string{"build", "**/gen/**", "*.pb.go", "src/[a-z]*", "*.{ts,tsx}"} {
+		if !ValidGlob(g) {
+			t.Errorf("ValidGlob(%q) = false, want true", g)
+		}
+	}
+	for _, g := range []string{"", "src/[a-z", "gen\\", "{src,lib/[a-z}"} {
+		if ValidGlob(g) {
+			t.Errorf("ValidGlob(%q) = true, want false", g)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package discovery
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// Sources of exclusion rules, as reported in types.ExclusionRule.
+const (
+	sourceDefault     = "default"
+	sourceConfig      = ".arsrc.yml"
+	sourceGlobal      = "core.excludesFile"
+	sourceInfoExclude = ".git/info/exclude"
+
+	gitIgnoreFile = ".gitignore"
+	arsIgnoreFile = ".arsignore"
+)
+
+// PathRules are the include and exclude globs of .arsrc.yml. They are
+// matched with MatchGlob against slash-separated paths relative to Dir; a
+// glob matching a directory applies to everything below it.
+type PathRules struct {
+	// Include walks paths the default skip list would skip, such as a Go
+	// package in build/ or a directory starting with ".".
+	Include []string
+	// Exclude skips paths, even if they are included or not ignored by git.
+	Exclude []string
+	// Dir is the project root the globs are relative to. If empty, it is the
+	// directory being walked; monorepo modules are walked with the globs of
+	// the whole repository.
+	Dir string
+}
+
+// included reports whether rel is matched by an include glob, itself or
+// through a parent directory.
+func (r PathRules) included(rel string) bool {
+	_, ok := matchAncestors(r.Include, rel)
+	return ok
+}
+
+// excluded returns the exclude glob matching rel, itself or through a parent
+// directory.
+func (r PathRules) excluded(rel string) (string, bool) {
+	return matchAncestors(r.Exclude, rel)
+}
+
+// mayInclude reports whether an include glob can match the directory rel or
+// a path below it.
+func (r PathRules) mayInclude(rel string) bool {
+	for _, g := range r.Include {
+		if matchGlobPrefix(g, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAncestors returns the first glob matching rel or one of its parent
+// directories.
+func matchAncestors(globs []string, rel string) (string, bool) {
+	for _, g := range globs {
+		for p := rel; p != "." && p != ""; p = parentDir(p) {
+			if MatchGlob(g, p) {
+				return g, true
+			}
+		}
+	}
+	return "", false
+}
+
+// parentDir returns the parent of a slash-separated relative path, or "."
+// for a top-level one.
+func parentDir(rel string) string {
+	if i := strings.LastIndexByte(rel, '/'); i >= 0 {
+		return rel[:i]
+	}
+	return "."
+}
+
+// ignoreRule is one pattern of a gitignore-style file.
+type ignoreRule struct {
+	source  string // file defining the rule, as reported
+	pattern string // as written, including a leading "!"
+	base    string // directory the pattern is relative to
+	negate  bool   // "!" re-includes what earlier rules excluded
+	ars     bool   // from .arsignore rather than git's ignore files
+	matcher *ignore.GitIgnore
+}
+
+// matches reports whether the rule's pattern matches the absolute path p.
+func (r *ignoreRule) matches(p string, isDir bool) bool {
+	rel, err := filepath.Rel(r.base, p)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	if isDir {
+		rel += "/"
+	}
+	return r.matcher.MatchesPath(rel)
+}
+
+// ignoreSet holds the gitignore-style rules that apply to a scan: the global
+// excludes file and .git/info/exclude of the enclosing repository, and the
+// .gitignore and .arsignore files of every directory from the repository root
+// down. As in git, later and deeper rules take precedence, and .arsignore
+// rules take precedence over git's, so "!" in .arsignore can bring back a
+// gitignored path.
+type ignoreSet struct {
+	top    string                  // outermost directory whose ignore files apply
+	global []ignoreRule            // core.excludesFile, then .git/info/exclude
+	byDir  map[string][]ignoreRule // .gitignore then .arsignore rules, by absolute directory
+	loaded map[string]bool         // directories whose ignore files were read
+}
+
+// loadIgnoreSet reads the rules applying to rootDir itself: those of the
+// enclosing git repository and of the ignore files from the repository root
+// down to rootDir. The ignore files of subdirectories are read by addDir as
+// the walk reaches them.
+func loadIgnoreSet(rootDir string) (*ignoreSet, error) {
+	s := &ignoreSet{top: rootDir, byDir: make(map[string][]ignoreRule), loaded: make(map[string]bool)}
+	repo := repoRoot(rootDir)
+	if repo != "" {
+		s.top = repo
+		if p := globalExcludesFile(rootDir); p != "" {
+			if err := s.addFile(&s.global, p, repo, sourceGlobal, false); err != nil {
+				return nil, err
+			}
+		}
+		if info, err := os.Stat(filepath.Join(repo, ".git")); err == nil && info.IsDir() {
+			if err := s.addFile(&s.global, filepath.Join(repo, ".git", "info", "exclude"), repo, sourceInfoExclude, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for dir := s.top; ; {
+		if err := s.addDir(dir, rootDir); err != nil {
+			return nil, err
+		}
+		if dir == rootDir {
+			break
+		}
+		rel, err := filepath.Rel(dir, rootDir)
+		if err != nil {
+			break
+		}
+		dir = filepath.Join(dir, strings.SplitN(rel, string(filepath.Separator), 2)[0])
+	}
+	return s, nil
+}
+
+// addDir reads the .gitignore and .arsignore files of dir, if any. Sources
+// are reported relative to rootDir. The rules read before an error are kept.
+func (s *ignoreSet) addDir(dir, rootDir string) error {
+	if s.loaded[dir] {
+		return nil
+	}
+	s.loaded[dir] = true
+	var rules []ignoreRule
+	for _, name := range []string{gitIgnoreFile, arsIgnoreFile} {
+		p := filepath.Join(dir, name)
+		source := name
+		if rel, err := filepath.Rel(rootDir, p); err == nil {
+			source = filepath.ToSlash(rel)
+		}
+		if err := s.addFile(&rules, p, dir, source, name == arsIgnoreFile); err != nil {
+			s.byDir[dir] = rules
+			return err
+		}
+	}
+	if len(rules) > 0 {
+		s.byDir[dir] = rules
+	}
+	return nil
+}
+
+// addFile appends the rules of the ignore file p, with patterns relative to
+// base, to rules. A missing file adds nothing.
+func (s *ignoreSet) addFile(rules *[]ignoreRule, p, base, source string, ars bool) error {
+	f, err := os.Open(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		trimmed := strings.TrimSpace(sc.Text())
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		pattern, negate := strings.CutPrefix(trimmed, "!")
+		*rules = append(*rules, ignoreRule{
+			source:  source,
+			pattern: trimmed,
+			base:    base,
+			negate:  negate,
+			ars:     ars,
+			matcher: ignore.CompileIgnoreLines(pattern),
+		})
+	}
+	return sc.Err()
+}
+
+// match returns the rule deciding that the absolute path p is ignored, or nil
+// if no rule ignores it. Only the ignore files of p's parent directories that
+// were read take part.
+func (s *ignoreSet) match(p string, isDir bool) *ignoreRule {
+	var dirs []string
+	for dir := filepath.Dir(p); ; dir = filepath.Dir(dir) {
+		if _, ok := s.byDir[dir]; ok {
+			dirs = append(dirs, dir)
+		}
+		if dir == s.top || dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	var last *ignoreRule
+	check := func(rules []ignoreRule, ars bool) {
+		for i := range rules {
+			if rules[i].ars == ars && rules[i].matches(p, isDir) {
+				last = &rules[i]
+			}
+		}
+	}
+	check(s.global, false)
+	for _, ars := range []bool{false, true} {
+		for i := len(dirs) - 1; i >= 0; i-- {
+			check(s.byDir[dirs[i]], ars)
+		}
+	}
+	if last == nil || last.negate {
+		return nil
+	}
+	return last
+}
+
+// repoRoot returns the root of the git repository containing dir, or "" if
+// dir is not inside one.
+func repoRoot(dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		if d == filepath.Dir(d) {
+			return ""
+		}
+	}
+}
+
+// globalExcludesFile returns git's core.excludesFile for the repository of
+// dir, or its default $XDG_CONFIG_HOME/git/ignore.
+func globalExcludesFile(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "config", "--type=path", "--get", "core.excludesFile").Output()
+	if p := strings.TrimSpace(string(out)); err == nil && p != "" {
+		return p
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "git", "ignore")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "git", "ignore")
+	}
+	return ""
+}
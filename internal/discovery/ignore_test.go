@@ -0,0 +1,195 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// isolateGitConfig keeps the user's git configuration out of a test.
+func isolateGitConfig(t *testing.T) {
+	t.Helper()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+func discoverFiles(t *testing.T, w *Walker, root string) (*types.ScanResult, map[string]types.DiscoveredFile) {
+	t.Helper()
+	result, err := w.Discover(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Discover returned error: %v", err)
+	}
+	files := make(map[string]types.DiscoveredFile)
+	for _, f := range result.Files {
+		files[filepath.ToSlash(f.RelPath)] = f
+	}
+	return result, files
+}
+
+func exclusionCount(result *types.ScanResult, source, pattern string) types.ExclusionRule {
+	for _, r := range result.Exclusions {
+		if r.Source == source && r.Pattern == pattern {
+			return r
+		}
+	}
+	return types.ExclusionRule{}
+}
+
+func TestWalkerNestedGitignore(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, ".gitignore", "*.gen.go\n")
+	writeTree(t, root, "main.go", "package main\n")
+	writeTree(t, root, "api/.gitignore", "tmp/\n!keep.gen.go\n")
+	writeTree(t, root, "api/api.go", "package api\n")
+	writeTree(t, root, "api/api.gen.go", "package api\n")
+	writeTree(t, root, "api/keep.gen.go", "package api\n")
+	writeTree(t, root, "api/tmp/scratch.go", "package tmp\n")
+	writeTree(t, root, "web/tmp/page.ts", "export {}\n")
+
+	result, files := discoverFiles(t, NewWalker(), root)
+
+	assertFile(t, files, "api/api.go", types.ClassSource, "")
+	assertFile(t, files, "api/api.gen.go", types.ClassExcluded, "gitignore")
+	assertFile(t, files, "api/keep.gen.go", types.ClassSource, "")
+	assertFile(t, files, "api/tmp/scratch.go", types.ClassExcluded, "gitignore")
+	assertFile(t, files, "web/tmp/page.ts", types.ClassSource, "")
+
+	if result.GitignoreCount != 2 {
+		t.Errorf("GitignoreCount = %d, want 2", result.GitignoreCount)
+	}
+	if r := exclusionCount(result, ".gitignore", "*.gen.go"); r.Files != 1 {
+		t.Errorf(".gitignore *.gen.go excluded %d files, want 1", r.Files)
+	}
+	if r := exclusionCount(result, "api/.gitignore", "tmp/"); r.Files != 1 {
+		t.Errorf("api/.gitignore tmp/ excluded %d files, want 1", r.Files)
+	}
+}
+
+func TestWalkerGitExcludeFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	isolateGitConfig(t)
+	root := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", root).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	global := filepath.Join(t.TempDir(), "global-ignore")
+	writeTree(t, filepath.Dir(global), "global-ignore", "*_scratch.py\n")
+	if out, err := exec.Command("git", "config", "--file", os.Getenv("GIT_CONFIG_GLOBAL"), "core.excludesFile", global).CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+	writeTree(t, root, ".git/info/exclude", "local/\n")
+	writeTree(t, root, "app.py", "x = 1\n")
+	writeTree(t, root, "notes_scratch.py", "x = 1\n")
+	writeTree(t, root, "local/tool.py", "x = 1\n")
+
+	// Scanning a subdirectory still applies the repository's rules.
+	writeTree(t, root, "sub/.keep", "")
+	writeTree(t, root, "sub/local/x.py", "x = 1\n")
+
+	result, files := discoverFiles(t, NewWalker(), root)
+	assertFile(t, files, "app.py", types.ClassSource, "")
+	assertFile(t, files, "notes_scratch.py", types.ClassExcluded, "gitignore")
+	assertFile(t, files, "local/tool.py", types.ClassExcluded, "gitignore")
+	if r := exclusionCount(result, sourceGlobal, "*_scratch.py"); r.Files != 1 {
+		t.Errorf("core.excludesFile excluded %d files, want 1", r.Files)
+	}
+	if r := exclusionCount(result, sourceInfoExclude, "local/"); r.Files != 2 {
+		t.Errorf(".git/info/exclude excluded %d files, want 2", r.Files)
+	}
+
+	_, subFiles := discoverFiles(t, NewWalker(), filepath.Join(root, "sub"))
+	assertFile(t, subFiles, "local/x.py", types.ClassExcluded, "gitignore")
+}
+
+func TestWalkerArsignore(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, ".gitignore", "generated/\n")
+	writeTree(t, root, ".arsignore", "*_pb2.py\n!generated/schema.py\n")
+	writeTree(t, root, "app.py", "x = 1\n")
+	writeTree(t, root, "api_pb2.py", "x = 1\n")
+	writeTree(t, root, "generated/schema.py", "x = 1\n")
+	writeTree(t, root, "generated/other.py", "x = 1\n")
+
+	result, files := discoverFiles(t, NewWalker(), root)
+	assertFile(t, files, "api_pb2.py", types.ClassExcluded, "arsignore")
+	assertFile(t, files, "generated/schema.py", types.ClassSource, "")
+	assertFile(t, files, "generated/other.py", types.ClassExcluded, "gitignore")
+	if r := exclusionCount(result, ".arsignore", "*_pb2.py"); r.Files != 1 {
+		t.Errorf(".arsignore *_pb2.py excluded %d files, want 1", r.Files)
+	}
+}
+
+func TestWalkerPathRules(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, "main.go", "package main\n")
+	writeTree(t, root, "build/build.go", "package build\n")
+	writeTree(t, root, "dist/bundle.ts", "export {}\n")
+	writeTree(t, root, "vendor/lib/lib.go", "package lib\n")
+	writeTree(t, root, "api/gen/client.ts", "export {}\n")
+	writeTree(t, root, "api/types.gen.ts", "export {}\n")
+	writeTree(t, root, "api/api.ts", "export {}\n")
+
+	w := NewWalker()
+	w.SetPathRules(PathRules{
+		Include: []string{"build", "vendor/lib"},
+		Exclude: []string{"**/gen", "**/*.gen.ts"},
+	})
+	result, files := discoverFiles(t, w, root)
+
+	assertFile(t, files, "build/build.go", types.ClassSource, "")
+	assertFile(t, files, "vendor/lib/lib.go", types.ClassSource, "")
+	assertFile(t, files, "api/api.ts", types.ClassSource, "")
+	assertFile(t, files, "api/types.gen.ts", types.ClassExcluded, "config")
+	if _, ok := files["dist/bundle.ts"]; ok {
+		t.Error("dist/ should still be skipped by default")
+	}
+	if _, ok := files["api/gen/client.ts"]; ok {
+		t.Error("api/gen should be excluded")
+	}
+
+	if r := exclusionCount(result, sourceConfig, "**/gen"); r.Dirs != 1 {
+		t.Errorf("exclude **/gen skipped %d dirs, want 1", r.Dirs)
+	}
+	if r := exclusionCount(result, sourceConfig, "**/*.gen.ts"); r.Files != 1 {
+		t.Errorf("exclude **/*.gen.ts excluded %d files, want 1", r.Files)
+	}
+	if r := exclusionCount(result, sourceDefault, "dist"); r.Dirs != 1 {
+		t.Errorf("default dist skipped %d dirs, want 1", r.Dirs)
+	}
+	if r := exclusionCount(result, sourceDefault, "build"); r.Dirs != 0 {
+		t.Errorf("included build/ counted as skipped: %+v", r)
+	}
+
+	if !w.ExcludesDir(filepath.Join(root, "api", "gen")) {
+		t.Error("ExcludesDir(api/gen) = false, want true")
+	}
+	if w.ExcludesDir(filepath.Join(root, "build")) {
+		t.Error("ExcludesDir(build) = true, want false")
+	}
+}
+
+func TestWalkerPathRulesDir(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, "services/web/src/app.ts", "export {}\n")
+	writeTree(t, root, "services/web/src/gen/api.ts", "export {}\n")
+
+	// A module is walked with the globs of the whole repository.
+	w := NewWalker()
+	w.SetPathRules(PathRules{Exclude: []string{"services/web/src/gen"}, Dir: root})
+	_, files := discoverFiles(t, w, filepath.Join(root, "services", "web"))
+	assertFile(t, files, "src/app.ts", types.ClassSource, "")
+	if _, ok := files["src/gen/api.ts"]; ok {
+		t.Error("src/gen should be excluded by a repository-relative glob")
+	}
+}
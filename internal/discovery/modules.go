@@ -0,0 +1,275 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Files that declare a module.
+const (
+	markerGoMod       = "go.mod"
+	markerGoWork      = "go.work"
+	markerPackageJSON = "package.json"
+	markerPnpm        = "pnpm-workspace.yaml"
+	markerPyproject   = "pyproject.toml"
+)
+
+// DetectModules finds the independently built modules below rootDir:
+//   - Go: the modules listed in go.work or, without one, every go.mod
+//   - TypeScript: the packages matched by the "workspaces" of the root
+//     package.json or by pnpm-workspace.yaml
+//   - Python: every pyproject.toml
+//
+// Directories a default walker skips are not searched. More than one module
+// makes rootDir a monorepo whose modules are analyzed separately. Modules are
+// returned in path order.
+func DetectModules(rootDir string) []types.Module {
+	return NewWalker().DetectModules(rootDir)
+}
+
+// DetectModules is like the package function DetectModules, but searches
+// only the directories w walks, honoring its path rules and ignore files.
+func (w *Walker) DetectModules(rootDir string) []types.Module {
+	found := w.findMarkers(rootDir)
+
+	var modules []types.Module
+	modules = append(modules, goModules(rootDir, found[markerGoMod])...)
+	modules = append(modules, workspacePackages(rootDir, found[markerPackageJSON])...)
+	for _, rel := range found[markerPyproject] {
+		m := newModule(rootDir, rel, types.LangPython, markerPyproject)
+		if name := pyprojectName(filepath.Join(m.Dir, markerPyproject)); name != "" {
+			m.Name = name
+		}
+		modules = append(modules, m)
+	}
+
+	sort.SliceStable(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Language < modules[j].Language
+	})
+	return modules
+}
+
+// NestedModuleDirs returns the directories of the modules of the same
+// language nested inside m, which belong to those modules rather than to m.
+func NestedModuleDirs(m types.Module, modules []types.Module) []string {
+	var dirs []string
+	for _, other := range modules {
+		if other.Dir == m.Dir || other.Language != m.Language {
+			continue
+		}
+		if rel, err := filepath.Rel(m.Dir, other.Dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			dirs = append(dirs, other.Dir)
+		}
+	}
+	return dirs
+}
+
+// findMarkers walks the directories of rootDir that w walks, except vendor
+// directories, and returns the directories, relative to rootDir, holding each
+// kind of module marker file.
+func (w *Walker) findMarkers(rootDir string) map[string][]string {
+	found := make(map[string][]string)
+	_ = filepath.WalkDir(rootDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != rootDir && (d.Name() == "vendor" || !w.WalksDir(rootDir, p)) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch d.Name() {
+		case markerGoMod, markerPackageJSON, markerPyproject:
+			rel, relErr := filepath.Rel(rootDir, filepath.Dir(p))
+			if relErr == nil {
+				found[d.Name()] = append(found[d.Name()], filepath.ToSlash(rel))
+			}
+		}
+		return nil
+	})
+	return found
+}
+
+// goModules returns the Go modules of rootDir: those used by go.work if it
+// exists, otherwise every directory with a go.mod.
+func goModules(rootDir string, goMods []string) []types.Module {
+	dirs := goMods
+	if data, err := os.ReadFile(filepath.Join(rootDir, markerGoWork)); err == nil {
+		if work, parseErr := modfile.ParseWork(markerGoWork, data, nil); parseErr == nil {
+			dirs = nil
+			for _, use := range work.Use {
+				dirs = append(dirs, path.Clean(filepath.ToSlash(use.Path)))
+			}
+		}
+	}
+
+	var modules []types.Module
+	for _, rel := range dirs {
+		m := newModule(rootDir, rel, types.LangGo, markerGoMod)
+		data, err := os.ReadFile(filepath.Join(m.Dir, markerGoMod))
+		if err != nil {
+			continue
+		}
+		if modPath := modfile.ModulePath(data); modPath != "" {
+			m.Name = modPath
+		}
+		modules = append(modules, m)
+	}
+	return modules
+}
+
+// workspacePackages returns the packages of an npm, yarn or pnpm workspace
+// rooted at rootDir, or nil if rootDir is not one.
+func workspacePackages(rootDir string, packageDirs []string) []types.Module {
+	patterns := workspacePatterns(rootDir)
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	var modules []types.Module
+	for _, rel := range packageDirs {
+		if rel == "." || !matchWorkspace(patterns, rel) {
+			continue
+		}
+		m := newModule(rootDir, rel, types.LangTypeScript, markerPackageJSON)
+		if name := packageJSONName(filepath.Join(m.Dir, markerPackageJSON)); name != "" {
+			m.Name = name
+		}
+		modules = append(modules, m)
+	}
+	return modules
+}
+
+// workspacePatterns reads the workspace globs of pnpm-workspace.yaml or of
+// the "workspaces" field of the root package.json, which is either a list or
+// an object with a "packages" list.
+func workspacePatterns(rootDir string) []string {
+	if data, err := os.ReadFile(filepath.Join(rootDir, markerPnpm)); err == nil {
+		var ws struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &ws) == nil && len(ws.Packages) > 0 {
+			return ws.Packages
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(rootDir, markerPackageJSON))
+	if err != nil {
+		return nil
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+	var list []string
+	if json.Unmarshal(pkg.Workspaces, &list) == nil {
+		return list
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &obj) == nil {
+		return obj.Packages
+	}
+	return nil
+}
+
+// matchWorkspace reports whether the package directory rel is matched by a
+// workspace glob and not excluded by a "!" glob.
+func matchWorkspace(patterns []string, rel string) bool {
+	matched := false
+	for _, p := range patterns {
+		if neg, ok := strings.CutPrefix(p, "!"); ok {
+			if MatchGlob(cleanGlob(neg), rel) {
+				return false
+			}
+			continue
+		}
+		if MatchGlob(cleanGlob(p), rel) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// cleanGlob normalizes a workspace glob such as "./packages/*/".
+func cleanGlob(p string) string {
+	p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+	return strings.TrimSuffix(p, "/")
+}
+
+// newModule returns the module in directory rel of rootDir, named after the
+// directory until its marker file provides a name.
+func newModule(rootDir, rel string, lang types.Language, marker string) types.Module {
+	name := rel
+	if rel == "." {
+		name = filepath.Base(rootDir)
+	}
+	return types.Module{
+		Name:     name,
+		Path:     rel,
+		Dir:      filepath.Join(rootDir, filepath.FromSlash(rel)),
+		Language: lang,
+		Marker:   marker,
+	}
+}
+
+// packageJSONName returns the "name" of a package.json, or "".
+func packageJSONName(p string) string {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(data, &pkg)
+	return pkg.Name
+}
+
+// pyprojectName returns the project name declared in the [project] or
+// [tool.poetry] table of a pyproject.toml, or "".
+func pyprojectName(p string) string {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+	table := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] ")
+			continue
+		}
+		if table != "project" && table != "tool.poetry" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "name" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
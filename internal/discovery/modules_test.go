@@ -0,0 +1,117 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestDetectModulesMonorepo(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/monorepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modules := DetectModules(root)
+
+	want := []struct {
+		name, path string
+		lang       types.Language
+	}{
+		{"example.com/util", "libs/util", types.LangGo},
+		{"@monorepo/web", "packages/web", types.LangTypeScript},
+		{"example.com/api", "services/api", types.LangGo},
+		{"monorepo-lint", "tools/lint", types.LangPython},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("DetectModules found %d modules, want %d: %+v", len(modules), len(want), modules)
+	}
+	for i, w := range want {
+		m := modules[i]
+		if m.Name != w.name || m.Path != w.path || m.Language != w.lang {
+			t.Errorf("module %d = {%s %s %s}, want {%s %s %s}", i, m.Name, m.Path, m.Language, w.name, w.path, w.lang)
+		}
+		if m.Dir != filepath.Join(root, filepath.FromSlash(w.path)) {
+			t.Errorf("module %d Dir = %s", i, m.Dir)
+		}
+	}
+}
+
+func TestDetectModulesSingleProject(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modules := DetectModules(root); len(modules) > 1 {
+		t.Errorf("DetectModules found %d modules in a single project, want at most 1", len(modules))
+	}
+}
+
+func TestDetectModulesFollowsWalker(t *testing.T) {
+	isolateGitConfig(t)
+	root := t.TempDir()
+	writeTree(t, root, "svc/a/go.mod", "module example.com/a\n")
+	writeTree(t, root, "svc/b/go.mod", "module example.com/b\n")
+	writeTree(t, root, "build/tool/go.mod", "module example.com/tool\n")
+	writeTree(t, root, "legacy/go.mod", "module example.com/legacy\n")
+	writeTree(t, root, "old/go.mod", "module example.com/old\n")
+	writeTree(t, root, ".arsignore", "old/\n")
+
+	w := NewWalker()
+	w.SetPathRules(PathRules{Include: []string{"build/tool"}, Exclude: []string{"legacy"}})
+	var paths []string
+	for _, m := range w.DetectModules(root) {
+		paths = append(paths, m.Path)
+	}
+	want := []string{"build/tool", "svc/a", "svc/b"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("DetectModules paths = %v, want %v", paths, want)
+	}
+}
+
+func TestDetectModulesPnpmWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, "package.json", `{"name": "root", "private": true}`)
+	writeTree(t, root, "pnpm-workspace.yaml", "packages:\n  - 'apps/**'\n  - '!apps/legacy'\n")
+	writeTree(t, root, "apps/site/package.json", `{"name": "site"}`)
+	writeTree(t, root, "apps/admin/ui/package.json", `{"name": "admin-ui"}`)
+	writeTree(t, root, "apps/legacy/package.json", `{"name": "legacy"}`)
+	writeTree(t, root, "node_modules/dep/package.json", `{"name": "dep"}`)
+
+	var names []string
+	for _, m := range DetectModules(root) {
+		names = append(names, m.Name)
+	}
+	want := []string{"admin-ui", "site"}
+	if len(names) != len(want) || names[0] != want[0] || names[1] != want[1] {
+		t.Errorf("DetectModules names = %v, want %v", names, want)
+	}
+}
+
+func TestNestedModuleDirs(t *testing.T) {
+	outer := types.Module{Dir: "/repo", Language: types.LangPython}
+	inner := types.Module{Dir: "/repo/plugins/x", Language: types.LangPython}
+	other := types.Module{Dir: "/repo/web", Language: types.LangTypeScript}
+	modules := []types.Module{outer, inner, other}
+
+	if got := NestedModuleDirs(outer, modules); len(got) != 1 || got[0] != inner.Dir {
+		t.Errorf("NestedModuleDirs(outer) = %v, want [%s]", got, inner.Dir)
+	}
+	if got := NestedModuleDirs(inner, modules); len(got) != 0 {
+		t.Errorf("NestedModuleDirs(inner) = %v, want none", got)
+	}
+}
+
+func writeTree(t *testing.T, root, rel, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,19 +1,21 @@
 package discovery
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
-	ignore "github.com/sabhiram/go-gitignore"
-
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
-// skipDirs lists directory names that should be skipped during walking.
+// skipDirs lists directory names that should be skipped during walking,
+// unless an include glob of .arsrc.yml matches them.
 var skipDirs = map[string]bool{
 	".git":         true,
 	"node_modules": true,
@@ -34,17 +36,72 @@ var langExtensions = map[string]types.Language{
 	".tsx": types.LangTypeScript,
 }
 
+// LanguageForFile returns the language of a file by its extension, or "" if the
+// extension is not a supported source language.
+func LanguageForFile(name string) types.Language {
+	return langExtensions[filepath.Ext(name)]
+}
+
+// ClassifyFile classifies a supported source file as source or test by its name.
+func ClassifyFile(name string, lang types.Language) types.FileClass {
+	return classifyByLanguage(filepath.Base(name), lang)
+}
+
+// defaultSkip returns the default skip rule matching a directory name, or ""
+// if the walker descends into it by default.
+func defaultSkip(name string) string {
+	if skipDirs[name] {
+		return name
+	}
+	if strings.HasPrefix(name, ".") {
+		return ".*"
+	}
+	return ""
+}
+
 // Walker discovers and classifies source files in a directory tree.
-type Walker struct{}
+type Walker struct {
+	skip      map[string]bool // absolute directories not to descend into
+	rules     PathRules
+	generated GeneratedRules
+
+	root    string     // root of the last Discover
+	ignores *ignoreSet // ignore files read by the last Discover
+}
 
 // NewWalker creates a new Walker instance.
 func NewWalker() *Walker {
 	return &Walker{}
 }
 
+// SkipDirs excludes the given absolute directories from discovery, such as
+// the nested modules of a monorepo module.
+func (w *Walker) SkipDirs(dirs ...string) {
+	if w.skip == nil {
+		w.skip = make(map[string]bool)
+	}
+	for _, d := range dirs {
+		w.skip[filepath.Clean(d)] = true
+	}
+}
+
+// SetPathRules sets the include and exclude globs of .arsrc.yml.
+func (w *Walker) SetPathRules(r PathRules) {
+	w.rules = r
+}
+
+// SetGeneratedRules sets the generated-file globs and markers of .arsrc.yml.
+func (w *Walker) SetGeneratedRules(r GeneratedRules) {
+	w.generated = r
+}
+
 // Discover walks rootDir recursively, discovers all source files (.go, .py, .ts, .tsx),
 // classifies them, and returns a ScanResult with file lists and counts.
-func (w *Walker) Discover(rootDir string) (*types.ScanResult, error) {
+// Besides the default skip list, files are excluded by the exclude globs, by
+// .arsignore files and by git's ignore rules: .gitignore files at any depth,
+// .git/info/exclude and core.excludesFile. The walk stops with ctx.Err() once
+// ctx is done.
+func (w *Walker) Discover(ctx context.Context, rootDir string) (*types.ScanResult, error) {
 	info, err := os.Stat(rootDir)
 	if err != nil {
 		return nil, fmt.Errorf("cannot access root directory: %w", err)
@@ -53,46 +110,169 @@ func (w *Walker) Discover(rootDir string) (*types.ScanResult, error) {
 		return nil, fmt.Errorf("%s is not a directory", rootDir)
 	}
 
-	gitIgnore, err := loadGitIgnore(rootDir)
+	ignores, err := loadIgnoreSet(rootDir)
 	if err != nil {
 		return nil, err
 	}
+	w.root, w.ignores = rootDir, ignores
 
 	result := &types.ScanResult{
 		RootDir:     rootDir,
 		PerLanguage: make(map[types.Language]int),
 	}
 
-	wc := &walkContext{rootDir: rootDir, gitIgnore: gitIgnore, result: result}
+	globRoot := w.rules.Dir
+	if globRoot == "" {
+		globRoot = rootDir
+	}
+	wc := &walkContext{
+		ctx:        ctx,
+		rootDir:    rootDir,
+		globRoot:   globRoot,
+		rules:      w.rules,
+		generated:  w.generated,
+		ignores:    ignores,
+		skip:       w.skip,
+		result:     result,
+		exclusions: make(map[[2]string]*types.ExclusionRule),
+	}
 	err = filepath.WalkDir(rootDir, wc.visitEntry)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
 	if err != nil {
 		return nil, fmt.Errorf("walk error: %w", err)
 	}
 
+	result.Exclusions = wc.exclusionList()
 	return result, nil
 }
 
-func loadGitIgnore(rootDir string) (*ignore.GitIgnore, error) {
-	gitignorePath := filepath.Join(rootDir, ".gitignore")
-	if _, err := os.Stat(gitignorePath); err != nil {
-		return nil, nil
+// ExcludesDir reports whether the last Discover left the directory dir out
+// because of an exclude glob of .arsrc.yml or an .arsignore rule. Go packages
+// are loaded by go/packages rather than found by the walk; this lets their
+// loader drop the same directories.
+func (w *Walker) ExcludesDir(dir string) bool {
+	if w.ignores == nil {
+		return false
 	}
-	gi, err := ignore.CompileIgnoreFile(gitignorePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse .gitignore: %w", err)
+	globRoot := w.rules.Dir
+	if globRoot == "" {
+		globRoot = w.root
+	}
+	if rel, err := filepath.Rel(globRoot, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		if _, ok := w.rules.excluded(filepath.ToSlash(rel)); ok {
+			return true
+		}
+	}
+	rule := w.ignores.match(dir, true)
+	return rule != nil && rule.ars
+}
+
+// WalksDir reports whether Discover of rootDir descends into the directory
+// dir: it is not skipped, left out by an exclude glob, skipped by default
+// without an include glob reaching into it, or excluded by an .arsignore
+// rule. Watchers and other walks of the project use it to visit the same
+// directories. The ignore files are read as needed, so it may be called
+// before Discover.
+func (w *Walker) WalksDir(rootDir, dir string) bool {
+	rel, err := filepath.Rel(rootDir, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	if rel == "." {
+		return true
+	}
+	if w.ignores == nil || w.root != rootDir {
+		ignores, err := loadIgnoreSet(rootDir)
+		if err != nil {
+			return true // as Discover would fail, nothing is known to be excluded
+		}
+		w.root, w.ignores = rootDir, ignores
+	}
+	globRoot := w.rules.Dir
+	if globRoot == "" {
+		globRoot = rootDir
+	}
+
+	path := rootDir
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		_ = w.ignores.addDir(path, rootDir)
+		path = filepath.Join(path, name)
+		if name == ".git" || w.skip[path] {
+			return false
+		}
+		var globRel string
+		if r, err := filepath.Rel(globRoot, path); err == nil && r != "." && !strings.HasPrefix(r, "..") {
+			globRel = filepath.ToSlash(r)
+		}
+		if _, ok := w.rules.excluded(globRel); ok {
+			return false
+		}
+		if defaultSkip(name) != "" && !w.rules.included(globRel) && !w.rules.mayInclude(globRel) {
+			return false
+		}
+		if rule := w.ignores.match(path, true); rule != nil && rule.ars {
+			return false
+		}
 	}
-	return gi, nil
+	return true
 }
 
 type walkContext struct {
-	rootDir   string
-	gitIgnore *ignore.GitIgnore
-	result    *types.ScanResult
+	ctx        context.Context
+	rootDir    string
+	globRoot   string // directory the include and exclude globs are relative to
+	rules      PathRules
+	generated  GeneratedRules
+	ignores    *ignoreSet
+	skip       map[string]bool
+	result     *types.ScanResult
+	exclusions map[[2]string]*types.ExclusionRule // by source and pattern
+}
+
+// exclude returns the counts of the rule with the given source and pattern.
+func (wc *walkContext) exclude(source, pattern string) *types.ExclusionRule {
+	key := [2]string{source, pattern}
+	r, ok := wc.exclusions[key]
+	if !ok {
+		r = &types.ExclusionRule{Source: source, Pattern: pattern}
+		wc.exclusions[key] = r
+	}
+	return r
+}
+
+// exclusionList returns the exclusion counts ordered by source and pattern.
+func (wc *walkContext) exclusionList() []types.ExclusionRule {
+	var list []types.ExclusionRule
+	for _, r := range wc.exclusions {
+		list = append(list, *r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Source != list[j].Source {
+			return list[i].Source < list[j].Source
+		}
+		return list[i].Pattern < list[j].Pattern
+	})
+	return list
+}
+
+// globRel returns path relative to the directory of the include and exclude
+// globs, slash-separated, or "" if it lies outside of it.
+func (wc *walkContext) globRel(path string) string {
+	rel, err := filepath.Rel(wc.globRoot, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel)
 }
 
 func (wc *walkContext) visitEntry(path string, d fs.DirEntry, err error) error {
+	if ctxErr := wc.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "warning: skipping %s: %v\n", path, err)
+		wc.report(types.SeverityWarning, path, fmt.Sprintf("skipped: %v", err))
 		wc.result.SkippedCount++
 		if d != nil && d.IsDir() {
 			return fs.SkipDir
@@ -101,24 +281,56 @@ func (wc *walkContext) visitEntry(path string, d fs.DirEntry, err error) error {
 	}
 
 	if d.Type()&fs.ModeSymlink != 0 {
-		fmt.Fprintf(os.Stderr, "warning: skipping symlink %s\n", path)
+		wc.report(types.SeverityInfo, path, "skipped symlink")
 		wc.result.SymlinkCount++
 		return nil
 	}
 
 	if d.IsDir() {
-		return wc.handleDir(d.Name())
+		if wc.skip[path] {
+			return fs.SkipDir
+		}
+		return wc.handleDir(path, d.Name())
 	}
 
 	return wc.handleFile(path, d.Name())
 }
 
-func (wc *walkContext) handleDir(name string) error {
-	if strings.HasPrefix(name, ".") && name != "." {
-		return fs.SkipDir
+// report records a discovery diagnostic for path, made relative to the root
+// where possible.
+func (wc *walkContext) report(sev types.Severity, path, msg string) {
+	if rel, err := filepath.Rel(wc.rootDir, path); err == nil {
+		path = rel
+	}
+	diagnostics.Report(wc.ctx, types.Diagnostic{
+		Severity: sev,
+		Analyzer: "discovery",
+		File:     filepath.ToSlash(path),
+		Message:  msg,
+	})
+}
+
+// handleDir decides whether to walk a directory: the exclude globs come
+// first, then the default skip list unless an include glob may match below
+// the directory. The ignore files of a walked directory are read for its
+// contents.
+func (wc *walkContext) handleDir(path, name string) error {
+	if path != wc.rootDir {
+		if name == ".git" {
+			return fs.SkipDir
+		}
+		rel := wc.globRel(path)
+		if glob, ok := wc.rules.excluded(rel); ok {
+			wc.exclude(sourceConfig, glob).Dirs++
+			return fs.SkipDir
+		}
+		if skip := defaultSkip(name); skip != "" && !wc.rules.included(rel) && !wc.rules.mayInclude(rel) {
+			wc.exclude(sourceDefault, skip).Dirs++
+			return fs.SkipDir
+		}
 	}
-	if skipDirs[name] {
-		return fs.SkipDir
+	if err := wc.ignores.addDir(path, wc.rootDir); err != nil {
+		wc.report(types.SeverityWarning, path, fmt.Sprintf("ignore rules partly read: %v", err))
 	}
 	return nil
 }
@@ -132,7 +344,7 @@ func (wc *walkContext) handleFile(path, name string) error {
 
 	relPath, err := filepath.Rel(wc.rootDir, path)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "warning: skipping %s: failed to compute relative path: %v\n", path, err)
+		wc.report(types.SeverityWarning, path, fmt.Sprintf("skipped: failed to compute relative path: %v", err))
 		wc.result.SkippedCount++
 		return nil
 	}
@@ -158,44 +370,61 @@ func (wc *walkContext) handleFile(path, name string) error {
 	return nil
 }
 
+// checkExclusions records file as excluded if a rule excludes it, checking
+// the exclude globs, vendor directories (unless included), and then the
+// ignore files, where the last matching rule decides. Files that are not
+// excluded are then recorded as generated if the generated rules say so.
 func (wc *walkContext) checkExclusions(file *types.DiscoveredFile, relPath string, lang types.Language) bool {
-	if isVendorPath(relPath) {
-		file.Class = types.ClassExcluded
-		file.ExcludeReason = "vendor"
-		wc.result.Files = append(wc.result.Files, *file)
-		wc.result.VendorCount++
-		wc.result.TotalFiles++
+	rel := wc.globRel(file.Path)
+	if glob, ok := wc.rules.excluded(rel); ok {
+		wc.excludeFile(file, "config")
+		wc.exclude(sourceConfig, glob).Files++
 		return true
 	}
 
-	if wc.gitIgnore != nil && wc.gitIgnore.MatchesPath(relPath) {
-		file.Class = types.ClassExcluded
-		file.ExcludeReason = "gitignore"
-		wc.result.Files = append(wc.result.Files, *file)
-		wc.result.GitignoreCount++
-		wc.result.TotalFiles++
+	if isVendorPath(relPath) && !wc.rules.included(rel) {
+		wc.excludeFile(file, "vendor")
+		wc.exclude(sourceDefault, "vendor").Files++
+		wc.result.VendorCount++
 		return true
 	}
 
-	if lang == types.LangGo {
-		generated, err := isGeneratedFile(file.Path)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "warning: skipping %s: failed to check generated status: %v\n", relPath, err)
-			wc.result.SkippedCount++
-			return true
-		}
-		if generated {
-			file.Class = types.ClassGenerated
-			wc.result.Files = append(wc.result.Files, *file)
-			wc.result.GeneratedCount++
-			wc.result.TotalFiles++
-			return true
+	if rule := wc.ignores.match(file.Path, false); rule != nil {
+		if rule.ars {
+			wc.excludeFile(file, "arsignore")
+		} else {
+			wc.excludeFile(file, "gitignore")
+			wc.result.GitignoreCount++
 		}
+		wc.exclude(rule.source, rule.pattern).Files++
+		return true
+	}
+
+	generated, err := wc.generated.isGenerated(file.Path, rel, lang)
+	if err != nil {
+		wc.report(types.SeverityWarning, file.Path, fmt.Sprintf("skipped: failed to check generated status: %v", err))
+		wc.result.SkippedCount++
+		return true
+	}
+	if generated {
+		file.Class = types.ClassGenerated
+		wc.result.Files = append(wc.result.Files, *file)
+		wc.result.GeneratedCount++
+		wc.result.TotalFiles++
+		return true
 	}
 
 	return false
 }
 
+// excludeFile records file as excluded for reason.
+func (wc *walkContext) excludeFile(file *types.DiscoveredFile, reason string) {
+	file.Class = types.ClassExcluded
+	file.ExcludeReason = reason
+	wc.result.Files = append(wc.result.Files, *file)
+	wc.result.TotalFiles++
+}
+
 func classifyByLanguage(name string, lang types.Language) types.FileClass {
 	switch lang {
 	case types.LangGo:
@@ -214,8 +443,8 @@ func classifyByLanguage(name string, lang types.Language) types.FileClass {
 func DetectProjectLanguages(rootDir string) []types.Language {
 	var langs []types.Language
 
-	// Go: go.mod or .go files
-	if fileExists(filepath.Join(rootDir, "go.mod")) || hasFileWithExt(rootDir, ".go") {
+	// Go: go.mod, go.work or .go files
+	if fileExists(filepath.Join(rootDir, "go.mod")) || fileExists(filepath.Join(rootDir, "go.work")) || hasFileWithExt(rootDir, ".go") {
 		langs = append(langs, types.LangGo)
 	}
 
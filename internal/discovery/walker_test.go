@@ -1,11 +1,13 @@
 package discovery
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"runtime"
 	"testing"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -16,7 +18,7 @@ func TestDiscoverValidProject(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(root)
+	result, err := w.Discover(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Discover(%q) returned error: %v", root, err)
 	}
@@ -92,7 +94,7 @@ func TestDiscoverPythonProject(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(root)
+	result, err := w.Discover(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Discover(%q) returned error: %v", root, err)
 	}
@@ -133,7 +135,7 @@ func TestDiscoverTypeScriptProject(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(root)
+	result, err := w.Discover(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Discover(%q) returned error: %v", root, err)
 	}
@@ -175,7 +177,7 @@ func TestDiscoverPolyglotProject(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(root)
+	result, err := w.Discover(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Discover(%q) returned error: %v", root, err)
 	}
@@ -260,7 +262,7 @@ func TestDiscoverEmptyDir(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	w := NewWalker()
-	result, err := w.Discover(tmpDir)
+	result, err := w.Discover(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("Discover(%q) returned error: %v", tmpDir, err)
 	}
@@ -275,7 +277,7 @@ func TestDiscoverEmptyDir(t *testing.T) {
 
 func TestDiscoverNonExistentDir(t *testing.T) {
 	w := NewWalker()
-	_, err := w.Discover("/nonexistent/path/that/does/not/exist")
+	_, err := w.Discover(context.Background(), "/nonexistent/path/that/does/not/exist")
 	if err == nil {
 		t.Error("expected error for non-existent directory, got nil")
 	}
@@ -309,12 +311,28 @@ func TestWalkerSymlink(t *testing.T) {
 		t.Skipf("directory symlink creation not supported: %v", err)
 	}
 
+	var diags []types.Diagnostic
+	ctx := diagnostics.WithRecorder(context.Background(), func(d types.Diagnostic) { diags = append(diags, d) })
+
 	w := NewWalker()
-	result, err := w.Discover(tmpDir)
+	result, err := w.Discover(ctx, tmpDir)
 	if err != nil {
 		t.Fatalf("Discover returned error: %v", err)
 	}
 
+	// Each skipped symlink should be reported as an info diagnostic
+	if len(diags) != result.SymlinkCount {
+		t.Errorf("got %d diagnostics for %d symlinks", len(diags), result.SymlinkCount)
+	}
+	for _, d := range diags {
+		if d.Severity != types.SeverityInfo || d.Analyzer != "discovery" {
+			t.Errorf("diagnostic = %+v, want info from discovery", d)
+		}
+		if d.File != "link.go" && d.File != "linkdir" {
+			t.Errorf("diagnostic file = %q, want a relative symlink path", d.File)
+		}
+	}
+
 	// The regular file should be found
 	found := false
 	for _, f := range result.Files {
@@ -374,7 +392,7 @@ func TestWalkerPermissionDenied(t *testing.T) {
 	})
 
 	w := NewWalker()
-	result, err := w.Discover(tmpDir)
+	result, err := w.Discover(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("Discover returned error: %v (should have continued)", err)
 	}
@@ -412,7 +430,7 @@ func TestWalkerUnicodePaths(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(tmpDir)
+	result, err := w.Discover(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("Discover returned error: %v", err)
 	}
@@ -458,7 +476,7 @@ func TestWalkerContinuesOnBadGeneratedCheck(t *testing.T) {
 	}
 
 	w := NewWalker()
-	result, err := w.Discover(tmpDir)
+	result, err := w.Discover(context.Background(), tmpDir)
 	if err != nil {
 		t.Fatalf("Discover returned error: %v (should have continued)", err)
 	}
@@ -504,3 +522,13 @@ func assertFile(t *testing.T, fileMap map[string]types.DiscoveredFile, relPath s
 		t.Errorf("file %q: ExcludeReason = %q, want %q", relPath, f.ExcludeReason, wantReason)
 	}
 }
+
+func TestWalkerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := NewWalker()
+	if _, err := w.Discover(ctx, "../../testdata/valid-go-project"); err != context.Canceled {
+		t.Errorf("Discover with cancelled context: err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,67 @@
+// Package events carries a scan's progress events from the stage that
+// produces them to the stream that writes them. Like diagnostics, stages
+// emit through the context, so deep callers such as C7's metric runner need
+// no extra parameters.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+type emitterKey struct{}
+
+// WithEmitter returns a context whose Emit calls fn. Emitters may be called
+// concurrently.
+func WithEmitter(ctx context.Context, fn func(types.Event)) context.Context {
+	return context.WithValue(ctx, emitterKey{}, fn)
+}
+
+// Emit hands e to the context's emitter, stamping its time if unset. Without
+// an emitter the event is dropped.
+func Emit(ctx context.Context, e types.Event) {
+	if emit, ok := ctx.Value(emitterKey{}).(func(types.Event)); ok {
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+		emit(e)
+	}
+}
+
+// NDJSON writes events as newline-delimited JSON, one object per line. It is
+// safe for concurrent use.
+type NDJSON struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewNDJSON returns an NDJSON stream writing to w.
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{enc: json.NewEncoder(w)}
+}
+
+// Emit writes e as one line. After a write error further events are dropped;
+// Err reports it.
+func (s *NDJSON) Emit(e types.Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = s.enc.Encode(e)
+	}
+}
+
+// Err returns the first write error, if any.
+func (s *NDJSON) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestEmitWithoutEmitter(t *testing.T) {
+	// Must not panic.
+	Emit(context.Background(), types.Event{Type: types.EventTokens})
+}
+
+func TestEmitStampsTime(t *testing.T) {
+	var got types.Event
+	ctx := WithEmitter(context.Background(), func(e types.Event) { got = e })
+
+	Emit(ctx, types.Event{Type: types.EventMetricSample, Metric: "m1", Sample: 1, Total: 2})
+
+	if got.Metric != "m1" || got.Time.IsZero() {
+		t.Errorf("emitted %+v, want metric m1 with a time", got)
+	}
+}
+
+func TestNDJSONWritesOneObjectPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewNDJSON(&buf)
+	s.Emit(types.Event{Type: types.EventStageStart, Stage: "discover"})
+	s.Emit(types.Event{Type: types.EventSummary, Summary: &types.ScanSummary{Composite: 7.5, Tier: "Agent-Ready"}})
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var e types.Event
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatalf("line 2 is not JSON: %v", err)
+	}
+	if e.Type != types.EventSummary || e.Summary == nil || e.Summary.Composite != 7.5 {
+		t.Errorf("decoded %+v, want the summary", e)
+	}
+	if strings.Contains(lines[0], `"summary"`) || !strings.Contains(lines[0], `"stage":"discover"`) {
+		t.Errorf("stage event should omit unset fields: %s", lines[0])
+	}
+}
@@ -0,0 +1,197 @@
+package lsp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// diagnosticSource is the Diagnostic.Source value shown by editors.
+const diagnosticSource = "ars"
+
+// Diagnostic codes, one per finding kind.
+const (
+	codeComplexity = "complexity"
+	codeFuncLength = "function-length"
+	codeDuplicate  = "duplication"
+	codeDeadExport = "dead-export"
+	codeAPIDoc     = "api-doc"
+)
+
+// Function thresholds reuse the C1 scoring breakpoints: a function is flagged as a
+// warning once it passes the breakpoint scored "weak", and as an error once it
+// passes the breakpoint scored at the minimum.
+var (
+	complexityWarn = scoring.ComplexityWeak
+	complexityErr  = scoring.ComplexityCritical
+	funcLenWarn    = scoring.FuncLenPoor
+	funcLenErr     = scoring.FuncLenCritical
+)
+
+// buildDiagnostics converts the findings for path into LSP diagnostics ordered by line.
+func buildDiagnostics(path string, f *fileFindings) []Diagnostic {
+	diags := []Diagnostic{}
+	for _, fn := range f.functions {
+		diags = append(diags, functionDiagnostics(fn)...)
+	}
+	diags = append(diags, duplicateDiagnostics(path, f.duplicates)...)
+	for _, d := range f.deadExports {
+		diags = append(diags, Diagnostic{
+			Range:    lineRange(d.Line),
+			Severity: SeverityInformation,
+			Code:     codeDeadExport,
+			Source:   diagnosticSource,
+			Message:  fmt.Sprintf("exported %s %s is not referenced from any other package", d.Kind, d.Name),
+		})
+	}
+	for _, api := range f.undocumented {
+		diags = append(diags, Diagnostic{
+			Range:    lineRange(api.Line),
+			Severity: SeverityInformation,
+			Code:     codeAPIDoc,
+			Source:   diagnosticSource,
+			Message:  fmt.Sprintf("public %s %s has no documentation comment", api.Kind, api.Name),
+		})
+	}
+	sort.SliceStable(diags, func(i, j int) bool {
+		return diags[i].Range.Start.Line < diags[j].Range.Start.Line
+	})
+	return diags
+}
+
+// functionDiagnostics flags a function whose complexity or length passes a breakpoint.
+func functionDiagnostics(fn types.FunctionMetric) []Diagnostic {
+	var diags []Diagnostic
+	if sev := severityFor(float64(fn.Complexity), complexityWarn, complexityErr); sev != 0 {
+		diags = append(diags, Diagnostic{
+			Range:    lineRange(fn.Line),
+			Severity: sev,
+			Code:     codeComplexity,
+			Source:   diagnosticSource,
+			Message: fmt.Sprintf("%s has cyclomatic complexity %d (threshold %.0f); split it so agents can reason about each branch",
+				fn.Name, fn.Complexity, complexityWarn),
+		})
+	}
+	if sev := severityFor(float64(fn.LineCount), funcLenWarn, funcLenErr); sev != 0 {
+		diags = append(diags, Diagnostic{
+			Range:    lineRange(fn.Line),
+			Severity: sev,
+			Code:     codeFuncLength,
+			Source:   diagnosticSource,
+			Message: fmt.Sprintf("%s is %d lines long (threshold %.0f); long functions exhaust agent context",
+				fn.Name, fn.LineCount, funcLenWarn),
+		})
+	}
+	return diags
+}
+
+// severityFor returns the severity for value, or 0 if it is within the warning threshold.
+func severityFor(value, warn, err float64) int {
+	switch {
+	case value > err:
+		return SeverityError
+	case value > warn:
+		return SeverityWarning
+	}
+	return 0
+}
+
+// lineSpan is an inclusive 1-based line range within a file.
+type lineSpan struct {
+	file       string
+	start, end int
+}
+
+// dupSide is one block of a duplicate pair in the analyzed file, with its twin.
+type dupSide struct {
+	own, twin lineSpan
+}
+
+// duplicateDiagnostics reports the duplicated regions of path, each with one
+// related-information entry per twin region. Duplication is detected over sliding
+// statement windows, so overlapping windows are merged into a single region
+// rather than stacking near-identical diagnostics.
+func duplicateDiagnostics(path string, dups []types.DuplicateBlock) []Diagnostic {
+	var sides []dupSide
+	for _, d := range dups {
+		a := lineSpan{d.FileA, d.StartA, d.EndA}
+		b := lineSpan{d.FileB, d.StartB, d.EndB}
+		if a.file == path {
+			sides = append(sides, dupSide{own: a, twin: b})
+		}
+		if b.file == path {
+			sides = append(sides, dupSide{own: b, twin: a})
+		}
+	}
+	sort.Slice(sides, func(i, j int) bool { return sides[i].own.start < sides[j].own.start })
+
+	var diags []Diagnostic
+	for i := 0; i < len(sides); {
+		region := sides[i].own
+		twins := []lineSpan{sides[i].twin}
+		j := i + 1
+		for ; j < len(sides) && sides[j].own.start <= region.end; j++ {
+			region.end = max(region.end, sides[j].own.end)
+			twins = append(twins, sides[j].twin)
+		}
+		i = j
+
+		diag := Diagnostic{
+			Range:    blockRange(region.start, region.end),
+			Severity: SeverityWarning,
+			Code:     codeDuplicate,
+			Source:   diagnosticSource,
+			Message:  fmt.Sprintf("%d-line block is duplicated elsewhere; extract a shared helper", region.end-region.start+1),
+		}
+		for _, twin := range mergeSpans(twins) {
+			diag.RelatedInformation = append(diag.RelatedInformation, DiagnosticRelatedInformation{
+				Location: Location{URI: pathToURI(twin.file), Range: blockRange(twin.start, twin.end)},
+				Message:  "duplicate of this block",
+			})
+		}
+		diags = append(diags, diag)
+	}
+	return diags
+}
+
+// mergeSpans merges overlapping spans within the same file.
+func mergeSpans(spans []lineSpan) []lineSpan {
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].file != spans[j].file {
+			return spans[i].file < spans[j].file
+		}
+		return spans[i].start < spans[j].start
+	})
+	var merged []lineSpan
+	for _, s := range spans {
+		last := len(merged) - 1
+		if last >= 0 && merged[last].file == s.file && s.start <= merged[last].end {
+			merged[last].end = max(merged[last].end, s.end)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// lineRange returns a range covering the whole of a 1-based line.
+func lineRange(line int) Range {
+	return blockRange(line, line)
+}
+
+// blockRange returns a range from the start of 1-based line start to the start of
+// the line after end, so editors highlight complete lines.
+func blockRange(start, end int) Range {
+	if start < 1 {
+		start = 1
+	}
+	if end < start {
+		end = start
+	}
+	return Range{
+		Start: Position{Line: start - 1},
+		End:   Position{Line: end},
+	}
+}
@@ -0,0 +1,222 @@
+// Package lsp implements a minimal Language Server Protocol server that publishes
+// ARS findings as editor diagnostics. It reuses the C1, C3 and C4 analyzers on a
+// per-file basis instead of running the full scan pipeline, so results update on
+// every save without re-parsing the whole project.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/version"
+)
+
+// Server speaks LSP over a pair of streams (typically stdin/stdout).
+type Server struct {
+	in     *bufio.Reader
+	out    io.Writer
+	logw   io.Writer
+	ws     *workspace
+	open   map[string]bool // URIs of documents currently open in the editor
+	closed bool            // shutdown request received
+}
+
+// NewServer creates a server reading requests from in and writing responses to out.
+// Analysis errors that cannot be reported to the client are written to logw.
+func NewServer(in io.Reader, out, logw io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(in),
+		out:  out,
+		logw: logw,
+		open: make(map[string]bool),
+	}
+}
+
+// Run processes messages until the client sends exit, the input stream ends,
+// or ctx is cancelled.
+func (s *Server) Run(ctx context.Context) error {
+	defer func() {
+		if s.ws != nil {
+			s.ws.close()
+		}
+	}()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		msg, err := readMessage(s.in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a single request or notification.
+func (s *Server) handle(msg *message) error {
+	switch msg.Method {
+	case "initialize":
+		var params initializeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return s.replyError(msg, codeInvalidParams, err.Error())
+		}
+		if err := s.initialize(params); err != nil {
+			return s.replyError(msg, codeInternalError, err.Error())
+		}
+		return s.reply(msg, initializeResult{
+			Capabilities: serverCapabilities{TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      saveOptions{IncludeText: false},
+			}},
+			ServerInfo: serverInfo{Name: "ars", Version: version.Version},
+		})
+	case "shutdown":
+		s.closed = true
+		return s.reply(msg, nil)
+	case "textDocument/didOpen":
+		return s.onDocument(msg, false)
+	case "textDocument/didSave":
+		return s.onDocument(msg, true)
+	case "textDocument/didClose":
+		var params textDocumentParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil
+		}
+		delete(s.open, params.TextDocument.URI)
+		return s.publish(params.TextDocument.URI, []Diagnostic{})
+	case "initialized", "textDocument/didChange", "$/cancelRequest", "$/setTrace":
+		return nil
+	}
+	if msg.ID != nil {
+		return s.replyError(msg, codeMethodNotFound, "method not found: "+msg.Method)
+	}
+	return nil
+}
+
+// initialize resolves the workspace root from the client's parameters.
+func (s *Server) initialize(params initializeParams) error {
+	uri := params.RootURI
+	if uri == "" && len(params.WorkspaceFolders) > 0 {
+		uri = params.WorkspaceFolders[0].URI
+	}
+	var root string
+	switch {
+	case uri != "":
+		path, err := uriToPath(uri)
+		if err != nil {
+			return fmt.Errorf("invalid root URI: %w", err)
+		}
+		root = path
+	case params.RootPath != "":
+		root = params.RootPath
+	default:
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		root = wd
+	}
+
+	ws, err := newWorkspace(filepath.Clean(root))
+	if err != nil {
+		return err
+	}
+	s.ws = ws
+	return nil
+}
+
+// onDocument analyzes an opened or saved document and publishes its diagnostics.
+// Saved documents trigger a reload of the file's package before analysis, after
+// which the other open documents are refreshed too: duplicates and dead exports
+// depend on code outside the saved file.
+func (s *Server) onDocument(msg *message, saved bool) error {
+	var params textDocumentParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return nil
+	}
+	if s.ws == nil || s.closed {
+		return nil
+	}
+	uri := params.TextDocument.URI
+	s.open[uri] = true
+	if err := s.diagnose(uri, saved); err != nil {
+		return err
+	}
+	if !saved {
+		return nil
+	}
+
+	others := make([]string, 0, len(s.open))
+	for other := range s.open {
+		if other != uri {
+			others = append(others, other)
+		}
+	}
+	sort.Strings(others)
+	for _, other := range others {
+		if err := s.diagnose(other, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diagnose analyzes the document at uri and publishes its diagnostics.
+// Analysis failures are logged and leave the previous diagnostics in place,
+// so a half-written file does not clear them.
+func (s *Server) diagnose(uri string, reload bool) error {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return nil
+	}
+	findings, err := s.ws.analyze(path, reload)
+	if err != nil {
+		fmt.Fprintf(s.logw, "ars lsp: analyze %s: %v\n", path, err)
+		return nil
+	}
+	return s.publish(uri, buildDiagnostics(path, findings))
+}
+
+// publish sends textDocument/publishDiagnostics for uri.
+func (s *Server) publish(uri string, diags []Diagnostic) error {
+	params, err := json.Marshal(PublishDiagnosticsParams{URI: uri, Diagnostics: diags})
+	if err != nil {
+		return err
+	}
+	return writeMessage(s.out, &message{Method: "textDocument/publishDiagnostics", Params: params})
+}
+
+// reply sends a successful response to a request.
+func (s *Server) reply(req *message, result any) error {
+	if req.ID == nil {
+		return nil
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	return writeMessage(s.out, &message{ID: req.ID, Result: result})
+}
+
+// replyError sends an error response to a request.
+func (s *Server) replyError(req *message, code int, msg string) error {
+	if req.ID == nil {
+		return nil
+	}
+	return writeMessage(s.out, &message{ID: req.ID, Error: &responseError{Code: code, Message: msg}})
+}
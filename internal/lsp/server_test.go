@@ -0,0 +1,353 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lspClient drives a Server over in-memory pipes.
+type lspClient struct {
+	t      *testing.T
+	w      *io.PipeWriter
+	r      *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func startServer(t *testing.T) *lspClient {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &lspClient{t: t, w: inW, r: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		c.done <- NewServer(inR, outW, io.Discard).Run(context.Background())
+		outW.Close()
+	}()
+	t.Cleanup(func() { inW.Close() })
+	return c
+}
+
+func (c *lspClient) send(method string, id bool, params any) {
+	c.t.Helper()
+	raw, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	msg := &message{Method: method, Params: raw}
+	if id {
+		c.nextID++
+		idRaw := json.RawMessage(fmt.Sprintf("%d", c.nextID))
+		msg.ID = &idRaw
+	}
+	if err := writeMessage(c.w, msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *lspClient) recv() *message {
+	c.t.Helper()
+	type result struct {
+		msg *message
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		msg, err := readMessage(c.r)
+		ch <- result{msg, err}
+	}()
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			c.t.Fatalf("read message: %v", res.err)
+		}
+		return res.msg
+	case <-time.After(60 * time.Second):
+		c.t.Fatal("timed out waiting for server message")
+	}
+	return nil
+}
+
+func (c *lspClient) recvDiagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.recv()
+	if msg.Method != "textDocument/publishDiagnostics" {
+		c.t.Fatalf("expected publishDiagnostics, got method=%q", msg.Method)
+	}
+	var params PublishDiagnosticsParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		c.t.Fatal(err)
+	}
+	return params
+}
+
+func (c *lspClient) initialize(root string) {
+	c.t.Helper()
+	c.send("initialize", true, map[string]any{"rootUri": pathToURI(root)})
+	resp := c.recv()
+	if resp.Error != nil {
+		c.t.Fatalf("initialize failed: %s", resp.Error.Message)
+	}
+	c.send("initialized", false, map[string]any{})
+}
+
+func docParams(path string) map[string]any {
+	return map[string]any{"textDocument": map[string]any{"uri": pathToURI(path)}}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// complexFunc returns Go source for a function whose single condition chains
+// the given number of || operands (complexity = operands + 1).
+func complexFunc(name string, operands int) string {
+	conds := make([]string, operands)
+	for i := range conds {
+		conds[i] = fmt.Sprintf("x == %d", i)
+	}
+	return fmt.Sprintf("// %s branches a lot.\nfunc %s(x int) bool {\n\tif %s {\n\t\treturn true\n\t}\n\treturn false\n}\n",
+		name, name, strings.Join(conds, " || "))
+}
+
+const dupBody = `	a := 1
+	b := 2
+	c := a + b
+	fmt.Println(c)
+	fmt.Println(a * b)
+	fmt.Println(c * a)
+	fmt.Println(b + c)
+`
+
+// setupGoProject writes a two-package module: lib holds every kind of finding,
+// app references lib.Used.
+func setupGoProject(t *testing.T) (root, libFile string) {
+	root = t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/proj\n\ngo 1.21\n")
+	libFile = filepath.Join(root, "lib", "lib.go")
+	writeFile(t, libFile, "package lib\n\nimport \"fmt\"\n\n"+
+		"// Used is referenced by app.\nfunc Used() {}\n\n"+
+		"func Undocumented() {}\n\n"+
+		"// DupA duplicates DupB.\nfunc DupA() {\n"+dupBody+"}\n\n"+
+		"// DupB duplicates DupA.\nfunc DupB() {\n"+dupBody+"}\n\n"+
+		complexFunc("Branchy", 25))
+	writeFile(t, filepath.Join(root, "app", "app.go"), "package app\n\n"+
+		"import \"example.com/proj/lib\"\n\n// Run calls lib.\nfunc Run() { lib.Used() }\n")
+	return root, libFile
+}
+
+func codes(diags []Diagnostic) map[string][]Diagnostic {
+	byCode := make(map[string][]Diagnostic)
+	for _, d := range diags {
+		byCode[d.Code] = append(byCode[d.Code], d)
+	}
+	return byCode
+}
+
+func TestServer_GoDiagnosticsOnOpen(t *testing.T) {
+	root, libFile := setupGoProject(t)
+	c := startServer(t)
+	c.initialize(root)
+
+	c.send("textDocument/didOpen", false, docParams(libFile))
+	params := c.recvDiagnostics()
+	if params.URI != pathToURI(libFile) {
+		t.Fatalf("URI = %q, want %q", params.URI, pathToURI(libFile))
+	}
+	byCode := codes(params.Diagnostics)
+
+	if got := byCode[codeComplexity]; len(got) != 1 || !strings.Contains(got[0].Message, "Branchy") {
+		t.Errorf("complexity diagnostics = %+v, want one for Branchy", got)
+	} else if got[0].Severity != SeverityWarning {
+		t.Errorf("complexity severity = %d, want warning for complexity 26", got[0].Severity)
+	}
+
+	dups := byCode[codeDuplicate]
+	if len(dups) != 2 {
+		t.Fatalf("duplication diagnostics = %d, want 2 (one per side)", len(dups))
+	}
+	for _, d := range dups {
+		if len(d.RelatedInformation) != 1 || d.RelatedInformation[0].Location.URI != pathToURI(libFile) {
+			t.Errorf("duplicate related information = %+v, want twin in same file", d.RelatedInformation)
+		}
+		if d.RelatedInformation[0].Location.Range.Start.Line == d.Range.Start.Line {
+			t.Error("related information should point at the twin, not the block itself")
+		}
+	}
+
+	dead := map[string]bool{}
+	for _, d := range byCode[codeDeadExport] {
+		dead[strings.Fields(d.Message)[2]] = true
+	}
+	if dead["Used"] {
+		t.Error("Used is referenced by app and must not be reported as dead")
+	}
+	if !dead["Undocumented"] || !dead["Branchy"] {
+		t.Errorf("dead exports = %v, want Undocumented and Branchy", dead)
+	}
+
+	if got := byCode[codeAPIDoc]; len(got) != 1 || !strings.Contains(got[0].Message, "Undocumented") {
+		t.Errorf("api-doc diagnostics = %+v, want one for Undocumented", got)
+	} else if got[0].Range.Start.Line != 7 {
+		t.Errorf("api-doc line = %d, want 7 (0-based)", got[0].Range.Start.Line)
+	}
+}
+
+func TestServer_ReanalyzesOnSave(t *testing.T) {
+	root, libFile := setupGoProject(t)
+	c := startServer(t)
+	c.initialize(root)
+
+	c.send("textDocument/didOpen", false, docParams(libFile))
+	if got := codes(c.recvDiagnostics().Diagnostics)[codeComplexity]; len(got) != 1 {
+		t.Fatalf("initial complexity diagnostics = %d, want 1", len(got))
+	}
+
+	// Make Branchy trivial and drop the duplicated functions.
+	writeFile(t, libFile, "package lib\n\n// Used is referenced by app.\nfunc Used() {}\n\n"+
+		"func Undocumented() {}\n\n"+complexFunc("Branchy", 1))
+
+	c.send("textDocument/didSave", false, docParams(libFile))
+	byCode := codes(c.recvDiagnostics().Diagnostics)
+	if n := len(byCode[codeComplexity]); n != 0 {
+		t.Errorf("complexity diagnostics after save = %d, want 0", n)
+	}
+	if n := len(byCode[codeDuplicate]); n != 0 {
+		t.Errorf("duplication diagnostics after save = %d, want 0", n)
+	}
+	if n := len(byCode[codeAPIDoc]); n != 1 {
+		t.Errorf("api-doc diagnostics after save = %d, want 1", n)
+	}
+}
+
+func TestServer_CloseClearsDiagnostics(t *testing.T) {
+	root, libFile := setupGoProject(t)
+	c := startServer(t)
+	c.initialize(root)
+
+	c.send("textDocument/didOpen", false, docParams(libFile))
+	c.recvDiagnostics()
+	c.send("textDocument/didClose", false, docParams(libFile))
+	if params := c.recvDiagnostics(); len(params.Diagnostics) != 0 {
+		t.Errorf("diagnostics after close = %d, want 0", len(params.Diagnostics))
+	}
+}
+
+func TestServer_PythonDiagnostics(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "pyproject.toml"), "[project]\nname = \"demo\"\n")
+	file := filepath.Join(root, "app.py")
+	var b strings.Builder
+	b.WriteString("def documented():\n    \"\"\"Has a docstring.\"\"\"\n    return 1\n\n\n")
+	b.WriteString("def branchy(x):\n")
+	for i := 0; i < 45; i++ {
+		fmt.Fprintf(&b, "    if x == %d:\n        return %d\n", i, i)
+	}
+	b.WriteString("    return -1\n")
+	writeFile(t, file, b.String())
+
+	c := startServer(t)
+	c.initialize(root)
+	c.send("textDocument/didOpen", false, docParams(file))
+	byCode := codes(c.recvDiagnostics().Diagnostics)
+
+	complexity := byCode[codeComplexity]
+	if len(complexity) != 1 || complexity[0].Severity != SeverityError {
+		t.Errorf("complexity diagnostics = %+v, want one error for branchy", complexity)
+	}
+	if length := byCode[codeFuncLength]; len(length) != 1 {
+		t.Errorf("function-length diagnostics = %d, want 1", len(length))
+	}
+	if api := byCode[codeAPIDoc]; len(api) != 1 || api[0].Range.Start.Line != 5 {
+		t.Errorf("api-doc diagnostics = %+v, want one for branchy at line 5", api)
+	}
+}
+
+func TestServer_PythonDeadExportsByPath(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "pyproject.toml"), "[project]\nname = \"demo\"\n")
+	private := filepath.Join(root, "alpha", "util.py")
+	writeFile(t, private, "def _helper():\n    return 1\n")
+	orphan := filepath.Join(root, "beta", "util.py")
+	writeFile(t, orphan, "def orphan():\n    \"\"\"Never imported.\"\"\"\n    return 2\n")
+	writeFile(t, filepath.Join(root, "main.py"), "import os\n")
+
+	c := startServer(t)
+	c.initialize(root)
+	c.send("textDocument/didOpen", false, docParams(private))
+	if dead := codes(c.recvDiagnostics().Diagnostics)[codeDeadExport]; len(dead) != 0 {
+		t.Errorf("dead exports in alpha/util.py = %+v, want none (orphan is in beta/util.py)", dead)
+	}
+	c.send("textDocument/didOpen", false, docParams(orphan))
+	if dead := codes(c.recvDiagnostics().Diagnostics)[codeDeadExport]; len(dead) != 1 {
+		t.Errorf("dead exports in beta/util.py = %+v, want orphan", dead)
+	}
+}
+
+func TestServer_HonorsProjectExcludes(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".arsrc.yml"), "version: 1\nexclude:\n  - scripts\n")
+	file := filepath.Join(root, "scripts", "tool.py")
+	writeFile(t, file, "def undocumented():\n    return 1\n")
+	writeFile(t, filepath.Join(root, "app.py"), "def main():\n    return 0\n")
+
+	c := startServer(t)
+	c.initialize(root)
+	c.send("textDocument/didOpen", false, docParams(file))
+	if diags := c.recvDiagnostics().Diagnostics; len(diags) != 0 {
+		t.Errorf("diagnostics for an excluded file = %+v, want none", diags)
+	}
+}
+
+func TestServer_UnknownRequest(t *testing.T) {
+	c := startServer(t)
+	c.send("textDocument/hover", true, map[string]any{})
+	resp := c.recv()
+	if resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("unknown request error = %+v, want method not found", resp.Error)
+	}
+}
+
+func TestServer_ShutdownAndExit(t *testing.T) {
+	c := startServer(t)
+	c.send("shutdown", true, nil)
+	if resp := c.recv(); resp.Error != nil {
+		t.Fatalf("shutdown failed: %s", resp.Error.Message)
+	}
+	c.send("exit", false, nil)
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("Run returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not exit")
+	}
+}
+
+func TestURIRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir with space", "file.go")
+	got, err := uriToPath(pathToURI(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != path {
+		t.Errorf("uriToPath(pathToURI(%q)) = %q", path, got)
+	}
+	if _, err := uriToPath("untitled:Untitled-1"); err == nil {
+		t.Error("expected error for non-file URI")
+	}
+}
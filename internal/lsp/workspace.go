@@ -0,0 +1,245 @@
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	c1 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c1_code_quality"
+	c3 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c3_architecture"
+	c4 "github.com/ingo-eichhorst/agent-readyness/internal/analyzer/c4_documentation"
+	"github.com/ingo-eichhorst/agent-readyness/internal/config"
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// fileFindings holds the raw analyzer output relevant to a single file.
+type fileFindings struct {
+	functions    []types.FunctionMetric
+	duplicates   []types.DuplicateBlock
+	deadExports  []types.DeadExport
+	undocumented []c4.APIDecl
+}
+
+// workspace keeps parsed state for a project between editor saves so that only
+// the changed file (Python/TypeScript) or package (Go) has to be re-parsed. The
+// cross-file C1 and C3 results are recomputed from the cached syntax trees.
+type workspace struct {
+	root     string
+	goCache  *parser.GoPackageCache
+	tsParser *parser.TreeSitterParser
+	walker   *discovery.Walker               // applies the path and generated rules of .arsrc.yml
+	files    map[string]types.DiscoveredFile // absolute path -> discovered non-Go file
+}
+
+// newWorkspace creates a workspace rooted at root. Parsing is deferred until the
+// first file is analyzed.
+func newWorkspace(root string) (*workspace, error) {
+	projectCfg, err := config.LoadProjectConfig(root, "")
+	if err != nil {
+		return nil, fmt.Errorf("load project config: %w", err)
+	}
+	walker := discovery.NewWalker()
+	walker.SetPathRules(projectCfg.PathRules())
+	walker.SetGeneratedRules(projectCfg.GeneratedRules())
+
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		return nil, fmt.Errorf("create tree-sitter parser: %w", err)
+	}
+	tsParser.EnableTreeCache()
+	return &workspace{
+		root:     root,
+		goCache:  parser.NewGoPackageCache(root),
+		tsParser: tsParser,
+		walker:   walker,
+	}, nil
+}
+
+// close releases Tree-sitter resources.
+func (ws *workspace) close() {
+	ws.tsParser.Close()
+}
+
+// analyze returns the findings for path. If reload is true the file's package
+// (Go) or file list (Python/TypeScript) is refreshed from disk first; the first
+// Go file analyzed parses the whole module.
+func (ws *workspace) analyze(path string, reload bool) (*fileFindings, error) {
+	switch discovery.LanguageForFile(path) {
+	case types.LangGo:
+		if strings.HasSuffix(path, "_test.go") {
+			return &fileFindings{}, nil
+		}
+		if reload {
+			ws.goCache.Invalidate(path)
+		}
+		pkgs, err := ws.goCache.Packages(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("parse Go packages: %w", err)
+		}
+		return ws.analyzeGo(path, pkgs)
+	case types.LangPython, types.LangTypeScript:
+		if err := ws.loadFiles(path, reload); err != nil {
+			return nil, err
+		}
+		return ws.analyzeTreeSitter(path)
+	}
+	return &fileFindings{}, nil
+}
+
+// analyzeGo runs the C1 and C3 analyzers over the cached packages and keeps the
+// results that belong to path.
+func (ws *workspace) analyzeGo(path string, pkgs []*parser.ParsedPackage) (*fileFindings, error) {
+	var pkgPath string
+	for _, pkg := range pkgs {
+		if pkg.ForTest == "" && parser.PackageDir(pkg) == filepath.Dir(path) {
+			pkgPath = pkg.PkgPath
+		}
+	}
+
+	c1a := c1.NewC1Analyzer(nil)
+	c1a.SetGoPackages(pkgs)
+	c1Result, err := c1a.Analyze(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c3a := c3.NewC3Analyzer(nil)
+	c3a.SetGoPackages(pkgs)
+	c3Result, err := c3a.Analyze(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := collectFindings(ws.root, path, c1Result, c3Result, func(d types.DeadExport) bool {
+		return d.Package == pkgPath && d.File == filepath.Base(path)
+	})
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	findings.undocumented, err = c4.FindUndocumentedAPIs(types.SourceFile{
+		Path: path, Language: types.LangGo, Content: content,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// loadFiles discovers the project's non-Go files on first use, and again whenever
+// a saved file is not yet known (e.g. it was just created).
+func (ws *workspace) loadFiles(path string, reload bool) error {
+	if ws.files != nil {
+		if _, known := ws.files[path]; known || !reload {
+			return nil
+		}
+	}
+	result, err := ws.walker.Discover(context.Background(), ws.root)
+	if err != nil {
+		return fmt.Errorf("discover files: %w", err)
+	}
+	ws.files = make(map[string]types.DiscoveredFile, len(result.Files))
+	for _, df := range result.Files {
+		if df.Language != types.LangGo {
+			ws.files[df.Path] = df
+		}
+	}
+	return nil
+}
+
+// analyzeTreeSitter runs the C1 and C3 analyzers over all Python and
+// TypeScript project files and keeps the results that belong to path. Only
+// files whose content changed since the previous call are parsed again; all
+// languages are analyzed so that pruning the tree cache afterwards keeps the
+// trees of the other language.
+func (ws *workspace) analyzeTreeSitter(path string) (*fileFindings, error) {
+	df, ok := ws.files[path]
+	if !ok || df.Class != types.ClassSource {
+		return &fileFindings{}, nil
+	}
+	defer ws.tsParser.PruneTreeCache()
+
+	byLang := make(map[types.Language]*types.AnalysisTarget)
+	var targets []*types.AnalysisTarget
+	for _, f := range ws.files {
+		if f.Class == types.ClassExcluded || f.Class == types.ClassGenerated {
+			continue
+		}
+		target, ok := byLang[f.Language]
+		if !ok {
+			target = &types.AnalysisTarget{Language: f.Language, RootDir: ws.root}
+			byLang[f.Language] = target
+			targets = append(targets, target)
+		}
+		target.Files = append(target.Files, types.SourceFile{
+			Path: f.Path, RelPath: f.RelPath, Language: f.Language, Class: f.Class,
+		})
+	}
+
+	c1Result, err := c1.NewC1Analyzer(ws.tsParser).Analyze(context.Background(), targets)
+	if err != nil {
+		return nil, err
+	}
+	c3Result, err := c3.NewC3Analyzer(ws.tsParser).Analyze(context.Background(), targets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tree-sitter analyzers report paths relative to the project root.
+	relPath := filepath.ToSlash(df.RelPath)
+	findings := collectFindings(ws.root, df.RelPath, c1Result, c3Result, func(d types.DeadExport) bool {
+		return d.File == relPath
+	})
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	findings.undocumented, err = c4.FindUndocumentedAPIs(types.SourceFile{
+		Path: path, RelPath: df.RelPath, Language: df.Language, Content: content,
+	}, ws.tsParser)
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// collectFindings filters C1 functions and duplicates to those reported under
+// reportedPath, and C3 dead exports to those accepted by isOwnDeadExport.
+// Duplicate block paths are made absolute (relative to root) for related information.
+func collectFindings(root, reportedPath string, c1Result, c3Result *types.AnalysisResult, isOwnDeadExport func(types.DeadExport) bool) *fileFindings {
+	findings := &fileFindings{}
+	absPath := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(root, p)
+	}
+
+	if m, ok := c1Result.Metrics["c1"].(*types.C1Metrics); ok {
+		for _, fn := range m.Functions {
+			if fn.File == reportedPath {
+				findings.functions = append(findings.functions, fn)
+			}
+		}
+		for _, d := range m.DuplicatedBlocks {
+			if d.FileA != reportedPath && d.FileB != reportedPath {
+				continue
+			}
+			d.FileA, d.FileB = absPath(d.FileA), absPath(d.FileB)
+			findings.duplicates = append(findings.duplicates, d)
+		}
+	}
+	if m, ok := c3Result.Metrics["c3"].(*types.C3Metrics); ok {
+		for _, d := range m.DeadExports {
+			if isOwnDeadExport(d) {
+				findings.deadExports = append(findings.deadExports, d)
+			}
+		}
+	}
+	return findings
+}
@@ -544,6 +544,14 @@ var researchCitations = []citation{
 		URL:         "https://arxiv.org/abs/2410.14684",
 		Description: "32.8% improvement with repository-level understanding; validates cross-file navigation importance",
 	},
+	{
+		Category:    "C7",
+		Title:       "Agentless: Demystifying LLM-based Software Engineering Agents",
+		Authors:     "Xia et al.",
+		Year:        2024,
+		URL:         "https://arxiv.org/abs/2407.01489",
+		Description: "Hierarchical fault localization (files, then functions) precedes repair; motivates feature localization",
+	},
 	{
 		Category:    "C7",
 		Title:       "How Accurately Do Large Language Models Understand Code?",
@@ -1202,6 +1202,65 @@ var metricDescriptions = map[string]metricDescription{
 <li>Use automated documentation linters</li>
 <li>Add CI checks for comment-code consistency</li>
 <li>Prefer self-documenting code over comments where possible</li>
+</ul>`,
+	},
+	"change_success": {
+		Brief:     "Whether agent edits keep the project building with passing tests. SWE-bench judges patches by running the repository's tests (Jimenez et al., 2024).",
+		Threshold: defaultExpandThreshold,
+		Detailed: `<h4>Definition</h4>
+<p>Measures whether an agent can make small, well-defined changes without breaking the project. The agent renames a function across its callers and adds a parameter that keeps the default behavior, each in a throwaway git worktree. ARS then runs the project's build and test commands and inspects the diff: whether the change was made, whether build and tests pass, whether the diff stays small and whether only the expected files were touched.</p>
+
+<h4>Why It Matters for AI Agents</h4>
+<p>Reading code is not the same as changing it safely. Agents working in code with hidden coupling, dynamic dispatch or missing tests make edits that look right but break the build or behavior elsewhere. A codebase where routine refactorings succeed on the first attempt is one an agent can be trusted to modify.</p>
+
+<h4>Research Evidence</h4>
+<p>SWE-bench established execution-based evaluation: a patch counts as resolving an issue only if the repository's tests pass afterwards <span class="citation">(Jimenez et al., 2024)</span>. Borg et al. found that agents break code more often in codebases with poor code health <span class="citation">(Borg et al., 2026)</span>.</p>
+<p><em>Note: The task is skipped (not scored) when the project is not a git repository or its build and tests already fail before the change.</em></p>
+
+<h4>Recommended Thresholds</h4>
+<ul>
+<li><strong>Score 10:</strong> Changes made, build and tests pass, small diff in the expected files</li>
+<li><strong>Score 8:</strong> Changes made and verified, but the diff sprawls or touches unrelated files</li>
+<li><strong>Score 4:</strong> Changes made, but the build or tests fail</li>
+<li><strong>Score 1:</strong> The requested change was not made</li>
+</ul>
+
+<h4>How to Improve</h4>
+<ul>
+<li>Keep build and test commands standard and fast (go test, pytest, npm test)</li>
+<li>Make the test suite pass on a clean checkout</li>
+<li>Avoid string-based or reflective calls that hide callers from search</li>
+<li>Keep functions' call sites few and explicit</li>
+</ul>`,
+	},
+	"feature_localization": {
+		Brief:     "Whether an agent can find the code implementing a described feature. Agentless localizes files and functions before repairing SWE-bench issues (Xia et al., 2024).",
+		Threshold: defaultExpandThreshold,
+		Detailed: `<h4>Definition</h4>
+<p>Measures how reliably and quickly an agent finds the code for a feature. ARS picks documented exported functions and types, derives a description of what each does from its doc comment without naming it, and asks the agent, which only has Glob and Grep, to name the implementing file and symbol as a ranked list of up to three answers. The report shows top-1 and top-3 accuracy and the tool calls the agent needed.</p>
+
+<h4>Why It Matters for AI Agents</h4>
+<p>Before an agent can change code it has to find it. In large repositories much of an agent's time goes into searching: grepping for vocabulary that the code does not use, opening files whose names say little about their content. Code organized by feature, named after the concepts it implements and documented in the same words makes that search short.</p>
+
+<h4>Research Evidence</h4>
+<p>Agentless resolves SWE-bench issues with a localization step that narrows the repository down to files, then classes and functions, before any edit <span class="citation">(Xia et al., 2024)</span>; a repair can only succeed where localization found the right place. RepoGraph shows that repository-level structure improves agents' results <span class="citation">(Ouyang et al., 2025)</span>.</p>
+<p><em>Note: The metric is skipped (not scored) when no source file has documented exports.</em></p>
+
+<h4>Recommended Thresholds</h4>
+<ul>
+<li><strong>Score 10:</strong> The symbol is the agent's first answer</li>
+<li><strong>Score 7:</strong> The symbol is the second or third answer</li>
+<li><strong>Score 4:</strong> Only the file is right</li>
+<li><strong>Score 1:</strong> Neither file nor symbol was found</li>
+</ul>
+<p>More than 10 and 20 tool calls each cost a point.</p>
+
+<h4>How to Improve</h4>
+<ul>
+<li>Name files and packages after the features they implement</li>
+<li>Use the same vocabulary in names, doc comments and issues</li>
+<li>Keep one concept per file instead of grab-bag utility modules</li>
+<li>Document exported functions and types with what they do, not how</li>
 </ul>`,
 	},
 }
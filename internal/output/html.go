@@ -31,6 +31,7 @@ import (
 const (
 	promptScoreThreshold = 9.0
 	weightToPercent      = 100.0
+	toolTimelineMaxRows  = 30 // tool calls shown per C7 sample timeline
 )
 
 //go:embed templates/report.html templates/styles.css
@@ -58,6 +59,23 @@ type htmlReportData struct {
 	InlineCSS       template.CSS // Safe: from our template
 	BadgeMarkdown   string       // Badge markdown for copy section
 	BadgeURL        string       // Badge URL for preview
+
+	CompositeInterval *types.ScoreInterval // uncertainty band with --c7-repeats; nil otherwise
+	Interrupted       []string             // categories cut short by Ctrl-C or --timeout
+	Diagnostics       []types.Diagnostic   // problems met during the scan
+	Modules           []htmlModule         // monorepo modules; nil for a single-module project
+}
+
+// htmlModule represents a monorepo module for HTML display.
+type htmlModule struct {
+	Name       string
+	Path       string
+	Language   string
+	LOC        int
+	Composite  float64
+	ScoreClass string // "ready", "assisted", "limited"
+	Tier       string
+	TierClass  string
 }
 
 // htmlCategory represents a category for HTML display.
@@ -69,7 +87,9 @@ type htmlCategory struct {
 	Available         bool   // whether category data is available
 	SubScores         []htmlSubScore
 	ImpactDescription string
-	Citations         []citation // Per-category citations
+	Citations         []citation           // Per-category citations
+	Interval          *types.ScoreInterval // Score over repeated runs; nil for one run
+	Interrupted       bool                 // scored from partial results
 }
 
 // htmlSubScore represents a metric sub-score for HTML display.
@@ -83,13 +103,14 @@ type htmlSubScore struct {
 	ScoreClass          string
 	WeightPct           float64 // Weight as percentage (0-100)
 	Available           bool
-	BriefDescription    string        // Always visible, 1-2 sentences
-	DetailedDescription template.HTML // Expandable content with sections
-	ShouldExpand        bool          // true if score below threshold
-	TraceHTML           template.HTML // Pre-rendered modal body content
-	HasTrace            bool          // Whether trace data is available
-	PromptHTML          template.HTML // Pre-rendered improvement prompt modal content
-	HasPrompt           bool          // Whether prompt data is available
+	BriefDescription    string               // Always visible, 1-2 sentences
+	DetailedDescription template.HTML        // Expandable content with sections
+	ShouldExpand        bool                 // true if score below threshold
+	TraceHTML           template.HTML        // Pre-rendered modal body content
+	HasTrace            bool                 // Whether trace data is available
+	PromptHTML          template.HTML        // Pre-rendered improvement prompt modal content
+	HasPrompt           bool                 // Whether prompt data is available
+	Interval            *types.ScoreInterval // Raw value over repeated runs; nil for one run
 }
 
 // TraceData holds analysis data needed for rendering call trace modals.
@@ -179,6 +200,11 @@ func (g *htmlGenerator) GenerateReport(w io.Writer, scored *types.ScoredResult,
 		InlineCSS:       template.CSS(string(cssBytes)), // Safe: from our template
 		BadgeMarkdown:   badge.Markdown,
 		BadgeURL:        badge.URL,
+
+		CompositeInterval: scored.CompositeInterval,
+		Interrupted:       scored.Interrupted,
+		Diagnostics:       scored.Diagnostics,
+		Modules:           buildHTMLModules(scored.Modules),
 	}
 
 	return g.tmpl.Execute(w, data)
@@ -209,6 +235,24 @@ func scoreToClass(score float64) string {
 	return "limited"
 }
 
+// buildHTMLModules converts monorepo module scores to HTML display format.
+func buildHTMLModules(modules []types.ModuleScore) []htmlModule {
+	var result []htmlModule
+	for _, m := range modules {
+		result = append(result, htmlModule{
+			Name:       m.Name,
+			Path:       m.Path,
+			Language:   string(m.Language),
+			LOC:        m.LOC,
+			Composite:  m.Composite,
+			ScoreClass: scoreToClass(m.Composite),
+			Tier:       m.Tier,
+			TierClass:  tierToClass(m.Tier),
+		})
+	}
+	return result
+}
+
 // buildHTMLCategories converts scored categories to HTML display format.
 func buildHTMLCategories(categories []types.CategoryScore, citations []citation, trace *TraceData) []htmlCategory {
 	result := make([]htmlCategory, 0, len(categories))
@@ -216,13 +260,15 @@ func buildHTMLCategories(categories []types.CategoryScore, citations []citation,
 	for _, cat := range categories {
 		hc := htmlCategory{
 			Name:              cat.Name,
-			DisplayName:       categoryDisplayName(cat.Name),
+			DisplayName:       categoryDisplayName(cat),
 			Score:             cat.Score,
 			ScoreClass:        scoreToClass(cat.Score),
 			Available:         cat.Score >= 0, // Infer from score
-			SubScores:         buildHTMLSubScores(cat.Name, cat.SubScores, trace),
-			ImpactDescription: categoryImpact(cat.Name),
+			SubScores:         buildHTMLSubScores(cat, trace),
+			ImpactDescription: categoryImpact(cat),
 			Citations:         filterCitationsByCategory(citations, cat.Name),
+			Interval:          cat.Interval,
+			Interrupted:       cat.Interrupted,
 		}
 		result = append(result, hc)
 	}
@@ -248,18 +294,18 @@ func filterCitationsByCategory(citations []citation, categoryName string) []cita
 // 2. Evidence attachment (top offenders per metric from scoring phase)
 // 3. Trace modal generation (breakpoint interpolation details for debugging)
 // 4. Improvement prompt generation (actionable suggestions with commands)
-func buildHTMLSubScores(categoryName string, subScores []types.SubScore, trace *TraceData) []htmlSubScore {
-	result := make([]htmlSubScore, 0, len(subScores))
-	c7MetricResults := extractC7MetricResults(categoryName, trace)
+func buildHTMLSubScores(cat types.CategoryScore, trace *TraceData) []htmlSubScore {
+	result := make([]htmlSubScore, 0, len(cat.SubScores))
+	c7MetricResults := extractC7MetricResults(cat.Name, trace)
 
-	for _, ss := range subScores {
+	for _, ss := range cat.SubScores {
 		if ss.Weight == 0.0 {
 			continue
 		}
 
 		hss := buildBaseSubScore(ss)
-		breakpoints := populateTraceData(&hss, categoryName, ss, c7MetricResults, trace)
-		populateImprovementPrompt(&hss, categoryName, ss, breakpoints, trace)
+		breakpoints := populateTraceData(&hss, cat.Name, ss, c7MetricResults, trace)
+		populateImprovementPrompt(&hss, cat, ss, breakpoints, trace)
 		result = append(result, hss)
 	}
 
@@ -287,10 +333,14 @@ func extractC7MetricResults(categoryName string, trace *TraceData) []types.C7Met
 // buildBaseSubScore creates an htmlSubScore with basic metric display fields.
 func buildBaseSubScore(ss types.SubScore) htmlSubScore {
 	desc := getMetricDescription(ss.MetricName)
+	displayName := metricDisplayName(ss.MetricName)
+	if ss.DisplayName != "" { // plugin metric
+		displayName, desc.Brief = ss.DisplayName, ss.Description
+	}
 	return htmlSubScore{
 		Key:                 ss.MetricName,
 		MetricName:          ss.MetricName,
-		DisplayName:         metricDisplayName(ss.MetricName),
+		DisplayName:         displayName,
 		RawValue:            ss.RawValue,
 		FormattedValue:      formatMetricValue(ss.MetricName, ss.RawValue, ss.Available),
 		Score:               ss.Score,
@@ -300,6 +350,7 @@ func buildBaseSubScore(ss types.SubScore) htmlSubScore {
 		BriefDescription:    desc.Brief,
 		DetailedDescription: desc.Detailed,
 		ShouldExpand:        ss.Score < desc.Threshold,
+		Interval:            ss.Interval,
 	}
 }
 
@@ -329,7 +380,8 @@ func populateTraceData(hss *htmlSubScore, categoryName string, ss types.SubScore
 }
 
 // populateImprovementPrompt adds improvement prompt HTML for metrics scoring below threshold.
-func populateImprovementPrompt(hss *htmlSubScore, categoryName string, ss types.SubScore, breakpoints []scoring.Breakpoint, trace *TraceData) {
+func populateImprovementPrompt(hss *htmlSubScore, cat types.CategoryScore, ss types.SubScore, breakpoints []scoring.Breakpoint, trace *TraceData) {
+	categoryName := cat.Name
 	if !ss.Available || ss.Score >= promptScoreThreshold || trace == nil {
 		return
 	}
@@ -347,10 +399,10 @@ func populateImprovementPrompt(hss *htmlSubScore, categoryName string, ss types.
 	targetValue, targetScore := nextTarget(ss.Score, breakpoints)
 	promptHTML := renderImprovementPrompt(promptParams{
 		CategoryName:    categoryName,
-		CategoryDisplay: categoryDisplayName(categoryName),
-		CategoryImpact:  categoryImpact(categoryName),
+		CategoryDisplay: categoryDisplayName(cat),
+		CategoryImpact:  categoryImpact(cat),
 		MetricName:      ss.MetricName,
-		MetricDisplay:   metricDisplayName(ss.MetricName),
+		MetricDisplay:   hss.DisplayName,
 		RawValue:        ss.RawValue,
 		FormattedValue:  formatMetricValue(ss.MetricName, ss.RawValue, ss.Available),
 		Score:           ss.Score,
@@ -396,7 +448,8 @@ func buildHTMLRecommendations(recs []recommend.Recommendation) []htmlRecommendat
 }
 
 // categoryDisplayName returns human-readable category name.
-func categoryDisplayName(name string) string {
+func categoryDisplayName(cat types.CategoryScore) string {
+	name := cat.Name
 	names := map[string]string{
 		"C1": "C1: Code Health",
 		"C2": "C2: Semantic Explicitness",
@@ -409,6 +462,9 @@ func categoryDisplayName(name string) string {
 	if dn, ok := names[name]; ok {
 		return dn
 	}
+	if cat.DisplayName != "" { // plugin category
+		return name + ": " + cat.DisplayName
+	}
 	return name
 }
 
@@ -454,6 +510,8 @@ func metricDisplayName(name string) string {
 		"cross_file_navigation":            "Cross-File Navigation",
 		"identifier_interpretability":      "Identifier Interpretability",
 		"documentation_accuracy_detection": "Documentation Accuracy Detection",
+		"change_success":                   "Change Success",
+		"feature_localization":             "Feature Localization",
 	}
 	if dn, ok := names[name]; ok {
 		return dn
@@ -504,7 +562,7 @@ func formatMetricValue(name string, value float64, available bool) string {
 // not just general code quality. For example, C1 doesn't say "complexity is bad";
 // it says "complexity prevents agents from reasoning safely" - the agent impact
 // is the key insight. These descriptions must be updated when adding categories.
-func categoryImpact(name string) string {
+func categoryImpact(cat types.CategoryScore) string {
 	impacts := map[string]string{
 		"C1": "Lower complexity and smaller functions help agents reason about and modify code safely.",
 		"C2": "Explicit types and consistent naming enable agents to understand code semantics without guessing.",
@@ -514,5 +572,8 @@ func categoryImpact(name string) string {
 		"C6": "Comprehensive tests let agents verify their changes don't break existing functionality.",
 		"C7": "Direct measurement of how well AI agents perform real-world coding tasks in your codebase.",
 	}
-	return impacts[name]
+	if impact, ok := impacts[cat.Name]; ok {
+		return impact
+	}
+	return cat.Impact
 }
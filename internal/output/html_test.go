@@ -234,6 +234,94 @@ func TestHTMLGenerator_WithBaseline(t *testing.T) {
 	}
 }
 
+func TestHTMLGenerator_Intervals(t *testing.T) {
+	gen, err := NewHTMLGenerator()
+	if err != nil {
+		t.Fatalf("NewHTMLGenerator() error = %v", err)
+	}
+
+	scored := &types.ScoredResult{
+		ProjectName: "test-project",
+		Composite:   7.0,
+		Tier:        "Agent-Assisted",
+		Categories: []types.CategoryScore{
+			{Name: "C7", Score: 6.0, Weight: 0.10, Interval: &types.ScoreInterval{Runs: 5, Mean: 6.0, StdDev: 0.4, Low: 5.6, High: 6.5},
+				SubScores: []types.SubScore{{MetricName: "code_behavior_comprehension", RawValue: 7, Score: 7, Weight: 0.2, Available: true,
+					Interval: &types.ScoreInterval{Runs: 5, Mean: 7, StdDev: 0.7, Low: 6.4, High: 7.6}}}},
+		},
+		CompositeInterval: &types.ScoreInterval{Runs: 5, Mean: 7.0, StdDev: 0.04, Low: 6.9, High: 7.1},
+	}
+
+	var buf bytes.Buffer
+	if err := gen.GenerateReport(&buf, scored, nil, nil, nil); err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{"95% CI 6.9&ndash;7.1 (&plusmn;0.0, 5 runs)", "95% CI 5.6&ndash;6.5", "&plusmn;0.7"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("GenerateReport() missing %q", want)
+		}
+	}
+}
+
+func TestGenerateReport_Interrupted(t *testing.T) {
+	gen, err := NewHTMLGenerator()
+	if err != nil {
+		t.Fatalf("NewHTMLGenerator() error = %v", err)
+	}
+
+	scored := &types.ScoredResult{
+		ProjectName: "test-project",
+		Composite:   7.0,
+		Tier:        "Agent-Assisted",
+		Categories:  []types.CategoryScore{{Name: "C7", Score: 6.0, Weight: 0.10, Interrupted: true}},
+		Interrupted: []string{"C5", "C7"},
+	}
+
+	var buf bytes.Buffer
+	if err := gen.GenerateReport(&buf, scored, nil, nil, nil); err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{"interrupted before C5, C7 finished", `<span class="cat-interrupted">partial</span>`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("GenerateReport() missing %q", want)
+		}
+	}
+}
+
+func TestGenerateReport_Diagnostics(t *testing.T) {
+	gen, err := NewHTMLGenerator()
+	if err != nil {
+		t.Fatalf("NewHTMLGenerator() error = %v", err)
+	}
+
+	scored := &types.ScoredResult{
+		ProjectName: "test-project",
+		Composite:   7.0,
+		Tier:        "Agent-Assisted",
+		Categories:  []types.CategoryScore{{Name: "C1", Score: 7.0, Weight: 0.25}},
+		Diagnostics: []types.Diagnostic{
+			{Severity: types.SeverityError, Analyzer: "C3", Message: "analyzer failed: boom"},
+			{Severity: types.SeverityWarning, Analyzer: "parser", File: "pkg/a.go", Message: "package x: undefined: y"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := gen.GenerateReport(&buf, scored, nil, nil, nil); err != nil {
+		t.Fatalf("GenerateReport() error = %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{`<section class="diagnostics">`, `<tr class="diag-error">`, "analyzer failed: boom", "pkg/a.go"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("GenerateReport() missing %q", want)
+		}
+	}
+}
+
 func TestTierToClass(t *testing.T) {
 	tests := []struct {
 		tier  string
@@ -321,12 +409,15 @@ func TestCategoryDisplayName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := categoryDisplayName(tt.name)
+			got := categoryDisplayName(types.CategoryScore{Name: tt.name})
 			if got != tt.want {
 				t.Errorf("categoryDisplayName(%q) = %q, want %q", tt.name, got, tt.want)
 			}
 		})
 	}
+	if got := categoryDisplayName(types.CategoryScore{Name: "C8", DisplayName: "Open TODOs"}); got != "C8: Open TODOs" {
+		t.Errorf("plugin categoryDisplayName = %q, want %q", got, "C8: Open TODOs")
+	}
 }
 
 func TestHTMLGenerator_SelfContained(t *testing.T) {
@@ -712,11 +803,56 @@ func buildC7DebugSamples(n int) []types.C7DebugSample {
 				},
 				FinalScore: 5,
 			},
+			ToolTrace: &types.C7ToolTrace{FilesRead: 12, RedundantReads: 3},
+		}
+		for j := 0; j < 15; j++ {
+			samples[i].ToolTrace.Calls = append(samples[i].ToolTrace.Calls, types.C7ToolCall{
+				Tool:     "Read",
+				Input:    fmt.Sprintf(`{"file_path":"internal/analyzer/c%d_test/file_%d.go"}`, i+1, j%12),
+				Path:     fmt.Sprintf("internal/analyzer/c%d_test/file_%d.go", i+1, j%12),
+				Start:    float64(j) * 0.2,
+				Duration: 0.1,
+			})
 		}
 	}
 	return samples
 }
 
+func TestRenderC7Trace_ToolTimeline(t *testing.T) {
+	results := []types.C7MetricResult{{
+		MetricID: "cross_file_navigation",
+		DebugSamples: []types.C7DebugSample{{
+			Description: "trace imports",
+			Duration:    10,
+			ToolTrace: &types.C7ToolTrace{
+				Calls: []types.C7ToolCall{
+					{Tool: "Grep", Input: `{"pattern":"<Open>"}`, Start: 1, Duration: 1},
+					{Tool: "Read", Input: `{"file_path":"store.go"}`, Path: "store.go", Start: 5, Duration: 2, Error: true},
+				},
+				FilesRead: 1,
+			},
+		}, {
+			Description: "untraced",
+		}},
+	}}
+
+	html := renderC7Trace("cross_file_navigation", results)
+	for _, want := range []string{
+		"Tool calls: 2 &middot; files read: 1 &middot; redundant reads: 0",
+		`<span>+5.0s</span><b>Read</b>`,
+		`style="left:50.0%;width:20.0%"`,
+		`trace-timeline-row error`,
+		`<span>{&#34;pattern&#34;:&#34;&lt;Open&gt;&#34;}</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("trace HTML missing %q", want)
+		}
+	}
+	if n := strings.Count(html, "Tool calls:"); n != 1 {
+		t.Errorf("timeline rendered %d times, want once (untraced sample has none)", n)
+	}
+}
+
 func TestPromptTemplateCoverage_AllMetrics(t *testing.T) {
 	gen, err := NewHTMLGenerator()
 	if err != nil {
@@ -17,6 +17,12 @@ type JSONReport struct {
 	Recommendations []jsonRecommendation `json:"recommendations"`
 	BadgeURL        string               `json:"badge_url,omitempty"`
 	BadgeMarkdown   string               `json:"badge_markdown,omitempty"`
+	LLMBudget       *types.LLMBudget     `json:"llm_budget,omitempty"`
+
+	CompositeInterval *types.ScoreInterval `json:"composite_interval,omitempty"` // with --c7-repeats
+	Interrupted       []string             `json:"interrupted,omitempty"`        // categories cut short by Ctrl-C or --timeout
+	Diagnostics       []types.Diagnostic   `json:"diagnostics,omitempty"`        // problems met during the scan
+	Modules           []jsonModule         `json:"modules,omitempty"`            // monorepo modules; totals are LOC-weighted
 }
 
 // jsonCategory represents a scoring category in JSON output.
@@ -26,6 +32,9 @@ type jsonCategory struct {
 	Weight    float64      `json:"weight"`
 	Available bool         `json:"available"` // whether category is available
 	SubScores []jsonMetric `json:"sub_scores"`
+
+	Interval    *types.ScoreInterval `json:"interval,omitempty"`    // score over repeated runs
+	Interrupted bool                 `json:"interrupted,omitempty"` // scored from partial results
 }
 
 // jsonMetric represents a single metric within a category in JSON output.
@@ -36,6 +45,18 @@ type jsonMetric struct {
 	Weight    float64              `json:"weight"`
 	Available bool                 `json:"available"`
 	Evidence  []types.EvidenceItem `json:"evidence"`
+	Interval  *types.ScoreInterval `json:"interval,omitempty"` // raw value over repeated runs
+}
+
+// jsonModule represents one module of a monorepo in JSON output.
+type jsonModule struct {
+	Name           string         `json:"name"`
+	Path           string         `json:"path"`
+	Language       string         `json:"language"`
+	LOC            int            `json:"loc"`
+	CompositeScore float64        `json:"composite_score"`
+	Tier           string         `json:"tier"`
+	Categories     []jsonCategory `json:"categories"`
 }
 
 // jsonRecommendation represents a single recommendation in JSON output.
@@ -58,6 +79,7 @@ type jsonRecommendation struct {
 func BuildJSONReport(scored *types.ScoredResult, recs []recommend.Recommendation, verbose bool, includeBadge bool) *JSONReport {
 	report := initializeJSONReport(scored)
 	buildCategories(report, scored.Categories)
+	buildModules(report, scored.Modules)
 	buildRecommendations(report, recs)
 	addBadgeIfRequested(report, scored, includeBadge)
 	return report
@@ -68,10 +90,20 @@ func initializeJSONReport(scored *types.ScoredResult) *JSONReport {
 		Version:        "3",
 		CompositeScore: scored.Composite,
 		Tier:           scored.Tier,
+		LLMBudget:      scored.LLMBudget,
+
+		CompositeInterval: scored.CompositeInterval,
+		Interrupted:       scored.Interrupted,
+		Diagnostics:       scored.Diagnostics,
 	}
 }
 
 func buildCategories(report *JSONReport, categories []types.CategoryScore) {
+	report.Categories = jsonCategories(categories)
+}
+
+func jsonCategories(categories []types.CategoryScore) []jsonCategory {
+	var result []jsonCategory
 	for _, cat := range categories {
 		jc := jsonCategory{
 			Name:      cat.Name,
@@ -79,8 +111,26 @@ func buildCategories(report *JSONReport, categories []types.CategoryScore) {
 			Weight:    cat.Weight,
 			Available: cat.Score >= 0,
 			SubScores: buildSubScores(cat.SubScores),
+			Interval:  cat.Interval,
+
+			Interrupted: cat.Interrupted,
 		}
-		report.Categories = append(report.Categories, jc)
+		result = append(result, jc)
+	}
+	return result
+}
+
+func buildModules(report *JSONReport, modules []types.ModuleScore) {
+	for _, m := range modules {
+		report.Modules = append(report.Modules, jsonModule{
+			Name:           m.Name,
+			Path:           m.Path,
+			Language:       string(m.Language),
+			LOC:            m.LOC,
+			CompositeScore: m.Composite,
+			Tier:           m.Tier,
+			Categories:     jsonCategories(m.Categories),
+		})
 	}
 }
 
@@ -98,6 +148,7 @@ func buildSubScores(subScores []types.SubScore) []jsonMetric {
 			Weight:    ss.Weight,
 			Available: ss.Available,
 			Evidence:  ev,
+			Interval:  ss.Interval,
 		})
 	}
 	return result
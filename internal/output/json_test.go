@@ -306,6 +306,125 @@ func TestJSONOmitsBadgeByDefault(t *testing.T) {
 	}
 }
 
+func TestJSONIncludesIntervals(t *testing.T) {
+	scored := newTestScoredResult()
+	report := BuildJSONReport(scored, nil, false, false)
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, report); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+	if strings.Contains(buf.String(), `"interval"`) {
+		t.Error("intervals should be omitted without repeated runs")
+	}
+
+	scored.CompositeInterval = &types.ScoreInterval{Runs: 3, Mean: 7.2, StdDev: 0.1, Low: 7.0, High: 7.4}
+	scored.Categories[0].Interval = &types.ScoreInterval{Runs: 3, Mean: 8.1, StdDev: 0.3, Low: 7.8, High: 8.5}
+	report = BuildJSONReport(scored, nil, false, false)
+	buf.Reset()
+	if err := RenderJSON(&buf, report); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+
+	var parsed JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if parsed.CompositeInterval == nil || parsed.CompositeInterval.Low != 7.0 || parsed.CompositeInterval.High != 7.4 {
+		t.Errorf("composite_interval = %+v, want 7.0-7.4", parsed.CompositeInterval)
+	}
+	if parsed.Categories[0].Interval == nil || parsed.Categories[0].Interval.Runs != 3 {
+		t.Errorf("category interval = %+v, want 3 runs", parsed.Categories[0].Interval)
+	}
+}
+
+func TestJSONIncludesInterrupted(t *testing.T) {
+	scored := newTestScoredResult()
+	scored.Interrupted = []string{"C7"}
+	scored.Categories[0].Interrupted = true
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, BuildJSONReport(scored, nil, false, false)); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+
+	var parsed JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(parsed.Interrupted) != 1 || parsed.Interrupted[0] != "C7" {
+		t.Errorf("interrupted = %v, want [C7]", parsed.Interrupted)
+	}
+	if !parsed.Categories[0].Interrupted || parsed.Categories[1].Interrupted {
+		t.Errorf("only the first category should be marked interrupted: %+v", parsed.Categories)
+	}
+}
+
+func TestJSONIncludesDiagnostics(t *testing.T) {
+	scored := newTestScoredResult()
+	scored.Diagnostics = []types.Diagnostic{
+		{Severity: types.SeverityError, Analyzer: "C3", Message: "analyzer failed: boom"},
+	}
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, BuildJSONReport(scored, nil, false, false)); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"severity": "error"`) {
+		t.Errorf("JSON should contain the diagnostic's severity:\n%s", buf.String())
+	}
+
+	var parsed JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(parsed.Diagnostics) != 1 || parsed.Diagnostics[0] != scored.Diagnostics[0] {
+		t.Errorf("diagnostics = %+v, want %+v", parsed.Diagnostics, scored.Diagnostics)
+	}
+
+	// A clean scan has no diagnostics key.
+	buf.Reset()
+	if err := RenderJSON(&buf, BuildJSONReport(newTestScoredResult(), nil, false, false)); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+	if strings.Contains(buf.String(), `"diagnostics"`) {
+		t.Error("clean scan should omit diagnostics")
+	}
+}
+
+func TestJSONIncludesModules(t *testing.T) {
+	scored := newTestScoredResult()
+	scored.Modules = []types.ModuleScore{{
+		Module:     types.Module{Name: "example.com/api", Path: "services/api", Language: types.LangGo},
+		LOC:        120,
+		Composite:  7.5,
+		Tier:       "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C1", Score: 7.5, Weight: 0.25}},
+	}}
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, BuildJSONReport(scored, nil, false, false)); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+
+	var parsed JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if len(parsed.Modules) != 1 {
+		t.Fatalf("modules = %+v, want one", parsed.Modules)
+	}
+	m := parsed.Modules[0]
+	if m.Path != "services/api" || m.Language != "go" || m.LOC != 120 || m.CompositeScore != 7.5 || len(m.Categories) != 1 {
+		t.Errorf("module = %+v", m)
+	}
+
+	// A single-module project has no modules key.
+	buf.Reset()
+	if err := RenderJSON(&buf, BuildJSONReport(newTestScoredResult(), nil, false, false)); err != nil {
+		t.Fatalf("RenderJSON error: %v", err)
+	}
+	if strings.Contains(buf.String(), `"modules"`) {
+		t.Error("single-module project should omit modules")
+	}
+}
+
 func TestJSONEvidenceNotNull(t *testing.T) {
 	scored := newTestScoredResult()
 	report := BuildJSONReport(scored, nil, false, false)
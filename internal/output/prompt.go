@@ -83,7 +83,7 @@ func writeBuildTestSection(prompt *strings.Builder, language string) {
 // writeTaskSection writes the Task section including Files to Focus On.
 func writeTaskSection(prompt *strings.Builder, params promptParams) {
 	prompt.WriteString("\n## Task\n\n")
-	prompt.WriteString(getMetricTaskGuidance(params.MetricName, params.RawValue, params.TargetValue, params.HasBreakpoints))
+	prompt.WriteString(getMetricTaskGuidance(params.MetricName, params.MetricDisplay, params.RawValue, params.TargetValue, params.HasBreakpoints))
 
 	if len(params.Evidence) > 0 {
 		writeEvidenceList(prompt, params.Evidence)
@@ -172,42 +172,29 @@ func nextTarget(score float64, breakpoints []scoring.Breakpoint) (targetValue fl
 
 // languageBuildCommands returns build/test commands for the detected language.
 func languageBuildCommands(lang string) string {
-	var cmds string
-	switch strings.ToLower(lang) {
-	case "go":
-		cmds = "go build ./...\ngo test ./..."
-	case "python":
-		cmds = "python -m pytest"
-	case "typescript":
-		cmds = "npm test"
-	default:
-		cmds = "# (adjust build/test commands for your project)"
+	cmds := "# (adjust build/test commands for your project)"
+	if c := types.Language(strings.ToLower(lang)).BuildCommands(); c != nil {
+		cmds = strings.Join(c, "\n")
 	}
 	return cmds + "\n(adjust commands for your project if different)"
 }
 
 // languageTestCommand returns the test-only command for the detected language.
 func languageTestCommand(lang string) string {
-	switch strings.ToLower(lang) {
-	case "go":
-		return "go test ./..."
-	case "python":
-		return "python -m pytest"
-	case "typescript":
-		return "npm test"
-	default:
+	c := types.Language(strings.ToLower(lang)).BuildCommands()
+	if c == nil {
 		return "# run your test suite"
 	}
+	return c[len(c)-1]
 }
 
 // getMetricTaskGuidance extracts improvement guidance from metric descriptions
 // and prepends a metric-specific action line.
-func getMetricTaskGuidance(metricName string, rawValue float64, targetValue float64, hasBreakpoints bool) string {
+func getMetricTaskGuidance(metricName, displayName string, rawValue float64, targetValue float64, hasBreakpoints bool) string {
 	var b strings.Builder
 
 	// Prepend metric-specific action line
 	if hasBreakpoints {
-		displayName := metricDisplayName(metricName)
 		b.WriteString(fmt.Sprintf("Improve the %s from %.4g to %.4g or better.\n\n", displayName, rawValue, targetValue))
 	}
 
@@ -193,7 +193,7 @@ func TestNextTarget_MaxScore(t *testing.T) {
 }
 
 func TestGetMetricTaskGuidance(t *testing.T) {
-	result := getMetricTaskGuidance("complexity_avg", 18.3, 10, true)
+	result := getMetricTaskGuidance("complexity_avg", "Complexity avg", 18.3, 10, true)
 
 	if result == "" {
 		t.Fatal("expected non-empty guidance")
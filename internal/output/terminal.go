@@ -34,6 +34,7 @@ const (
 	truncateLong     = 500 // Truncation limit for long text (responses)
 	separatorWide    = 60  // Wide separator width (C7 debug)
 	separatorNarrow  = 50  // Narrow separator width (C7 debug)
+	diagnosticsTopN  = 10  // Diagnostics shown without verbose
 )
 
 // Recommendation impact thresholds.
@@ -49,6 +50,7 @@ func RenderSummary(w io.Writer, result *types.ScanResult, analysisResults []*typ
 	renderFileCounts(w, result)
 	renderPerLanguageCounts(w, result)
 	renderExcludedCounts(w, result)
+	renderExclusionRules(w, result, verbose)
 	renderVerboseFileList(w, result, verbose)
 	renderAnalysisResults(w, analysisResults, verbose)
 }
@@ -93,6 +95,25 @@ func renderExcludedCounts(w io.Writer, result *types.ScanResult) {
 	}
 }
 
+// renderExclusionRules prints, in verbose mode, how many files and
+// directories each exclusion rule kept out of the scan.
+func renderExclusionRules(w io.Writer, result *types.ScanResult, verbose bool) {
+	if !verbose || len(result.Exclusions) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "  Exclusion rules:")
+	for _, r := range result.Exclusions {
+		var counts []string
+		if r.Files > 0 {
+			counts = append(counts, fmt.Sprintf("%d files", r.Files))
+		}
+		if r.Dirs > 0 {
+			counts = append(counts, fmt.Sprintf("%d dirs", r.Dirs))
+		}
+		fmt.Fprintf(w, "    %-20s %-24s %s\n", r.Source, r.Pattern, strings.Join(counts, ", "))
+	}
+}
+
 // renderVerboseFileList prints detailed file list if verbose mode is enabled.
 func renderVerboseFileList(w io.Writer, result *types.ScanResult, verbose bool) {
 	if !verbose {
@@ -244,6 +265,9 @@ func RenderScores(w io.Writer, scored *types.ScoredResult, verbose bool) {
 
 	for _, cat := range scored.Categories {
 		displayName := categoryDisplayNames[cat.Name]
+		if cat.DisplayName != "" { // plugin category
+			displayName = cat.DisplayName
+		}
 		if displayName == "" {
 			displayName = cat.Name
 		}
@@ -256,7 +280,7 @@ func RenderScores(w io.Writer, scored *types.ScoredResult, verbose bool) {
 		}
 
 		sc := scoreColor(cat.Score)
-		sc.Fprintf(w, "  %s%.1f / 10\n", label, cat.Score)
+		sc.Fprintf(w, "  %s%.1f / 10%s%s\n", label, cat.Score, formatInterval(cat.Interval), formatInterrupted(cat.Interrupted))
 
 		if verbose {
 			renderSubScores(w, cat.SubScores)
@@ -267,12 +291,62 @@ func RenderScores(w io.Writer, scored *types.ScoredResult, verbose bool) {
 
 	// Composite score
 	cc := scoreColor(scored.Composite)
-	cc.Fprintf(w, "  Composite Score:          %.1f / 10\n", scored.Composite)
+	cc.Fprintf(w, "  Composite Score:          %.1f / 10%s\n", scored.Composite, formatInterval(scored.CompositeInterval))
 
 	// Tier rating
 	tc := tierColor(scored.Tier)
 	fmt.Fprintf(w, "  Rating:                   ")
 	tc.Fprintln(w, scored.Tier)
+
+	renderLLMBudget(w, scored.LLMBudget)
+	if len(scored.Interrupted) > 0 {
+		color.New(color.FgYellow).Fprintf(w, "  Cut short:                %s (scan interrupted, partial report)\n", strings.Join(scored.Interrupted, ", "))
+	}
+}
+
+// formatInterrupted marks the score of a category cut short by cancellation.
+func formatInterrupted(interrupted bool) string {
+	if !interrupted {
+		return ""
+	}
+	return " (partial)"
+}
+
+// renderLLMBudget prints LLM spend against the budget and, if it ran out,
+// what was cut, since those scores rest on fewer samples or are missing.
+func renderLLMBudget(w io.Writer, b *types.LLMBudget) {
+	if b == nil {
+		return
+	}
+	var limits []string
+	if b.LimitUSD > 0 {
+		limits = append(limits, fmt.Sprintf("$%.2f", b.LimitUSD))
+	}
+	if b.LimitTokens > 0 {
+		limits = append(limits, fmt.Sprintf("%d tokens", b.LimitTokens))
+	}
+	fmt.Fprintf(w, "  LLM budget:               $%.2f, %d tokens of %s\n", b.SpentUSD, b.TokensUsed, strings.Join(limits, ", "))
+	if !b.Exhausted {
+		return
+	}
+
+	var cuts []string
+	if b.DroppedSamples > 0 {
+		cuts = append(cuts, fmt.Sprintf("%d C7 samples dropped", b.DroppedSamples))
+	}
+	if b.RefusedCalls > 0 {
+		cuts = append(cuts, fmt.Sprintf("%d calls refused", b.RefusedCalls))
+	}
+	if len(b.Skipped) > 0 {
+		cuts = append(cuts, "skipped "+strings.Join(b.Skipped, ", "))
+	}
+	if len(b.UnpricedModels) > 0 {
+		cuts = append(cuts, "no price for "+strings.Join(b.UnpricedModels, ", "))
+	}
+	if len(cuts) == 0 {
+		cuts = append(cuts, "limit reached")
+	}
+	color.New(color.FgYellow).Fprintf(w, "  Budget cuts:              %s\n", strings.Join(cuts, "; "))
 }
 
 // renderSubScores prints per-metric sub-score details indented beneath a category.
@@ -284,6 +358,9 @@ func renderSubScores(w io.Writer, subScores []types.SubScore) {
 		}
 
 		displayName := metricDisplayNames[ss.MetricName]
+		if ss.DisplayName != "" { // plugin metric
+			displayName = ss.DisplayName
+		}
 		if displayName == "" {
 			displayName = ss.MetricName
 		}
@@ -294,9 +371,18 @@ func renderSubScores(w io.Writer, subScores []types.SubScore) {
 			continue
 		}
 
-		fmt.Fprintf(w, "    %-22s %7.1f  ->  %-4.1f  (%.0f%%)\n",
-			displayName+":", ss.RawValue, ss.Score, ss.Weight*100)
+		fmt.Fprintf(w, "    %-22s %7.1f  ->  %-4.1f  (%.0f%%)%s\n",
+			displayName+":", ss.RawValue, ss.Score, ss.Weight*100, formatInterval(ss.Interval))
+	}
+}
+
+// formatInterval returns the suffix showing a score's spread over repeated
+// runs, or "" for a single run.
+func formatInterval(iv *types.ScoreInterval) string {
+	if iv == nil {
+		return ""
 	}
+	return fmt.Sprintf("  (±%.1f, 95%% CI %.1f-%.1f, %d runs)", iv.StdDev, iv.Low, iv.High, iv.Runs)
 }
 
 // scoreColor returns a color based on score thresholds: green >= 8, yellow >= 6, red < 6.
@@ -358,6 +444,73 @@ func RenderRecommendations(w io.Writer, recs []recommend.Recommendation) {
 	}
 }
 
+// RenderModules prints the scores of a monorepo's modules, on which the
+// totals of RenderScores are weighted by lines of code. Nothing is printed for
+// a single-module project.
+func RenderModules(w io.Writer, modules []types.ModuleScore) {
+	if len(modules) == 0 {
+		return
+	}
+
+	bold := color.New(color.Bold)
+	fmt.Fprintln(w)
+	bold.Fprintln(w, "Modules")
+	fmt.Fprintln(w, "\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500")
+
+	for _, m := range modules {
+		fmt.Fprintf(w, "  %-30s %-10s %7d LOC  ", m.Path, m.Language, m.LOC)
+		scoreColor(m.Composite).Fprintf(w, "%4.1f / 10", m.Composite)
+		fmt.Fprint(w, "  ")
+		tierColor(m.Tier).Fprintln(w, m.Tier)
+		if m.Name != m.Path {
+			color.New(color.FgHiBlack).Fprintf(w, "    %s\n", m.Name)
+		}
+	}
+}
+
+// RenderDiagnostics prints the problems met during the scan. Without verbose,
+// info diagnostics are hidden and at most diagnosticsTopN others are listed.
+// Nothing is printed when there is nothing to show.
+func RenderDiagnostics(w io.Writer, diags []types.Diagnostic, verbose bool) {
+	shown := diags
+	if !verbose {
+		shown = nil
+		for _, d := range diags {
+			if d.Severity != types.SeverityInfo {
+				shown = append(shown, d)
+			}
+		}
+	}
+	if len(shown) == 0 {
+		return
+	}
+
+	bold := color.New(color.Bold)
+	fmt.Fprintln(w)
+	bold.Fprintln(w, "Diagnostics")
+	fmt.Fprintln(w, "\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500")
+
+	for i, d := range shown {
+		if !verbose && i == diagnosticsTopN {
+			fmt.Fprintf(w, "  ... and %d more (use --verbose or --json to see all)\n", len(shown)-i)
+			break
+		}
+		sevColor := color.New(color.Faint)
+		switch d.Severity {
+		case types.SeverityError:
+			sevColor = color.New(color.FgRed)
+		case types.SeverityWarning:
+			sevColor = color.New(color.FgYellow)
+		}
+		sevColor.Fprintf(w, "  %-8s", d.Severity)
+		fmt.Fprintf(w, " %-10s", d.Analyzer)
+		if d.File != "" {
+			fmt.Fprintf(w, " %s:", d.File)
+		}
+		fmt.Fprintf(w, " %s\n", d.Message)
+	}
+}
+
 // joinCycle formats a dependency cycle as "A -> B -> C -> A".
 func joinCycle(cycle []string) string {
 	if len(cycle) == 0 {
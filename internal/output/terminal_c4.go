@@ -3,6 +3,7 @@ package output
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/fatih/color"
 
@@ -104,6 +105,9 @@ func renderC4LLMMetrics(w io.Writer, m *types.C4Metrics, bold *color.Color) {
 		cr := colorForIntInverse(m.CrossRefCoherence, c4LLMScoreRed, c4LLMScoreYellow)
 		cr.Fprintf(w, "    Cross-ref coherence: %d/10\n", m.CrossRefCoherence)
 		fmt.Fprintf(w, "    LLM cost:            $%.4f (%d tokens)\n", m.LLMCostUSD, m.LLMTokensUsed)
+		if m.LLMJudge != "" {
+			fmt.Fprintf(w, "    Judge:               %s\n", formatC4Judge(m))
+		}
 	} else {
 		dim := color.New(color.FgHiBlack)
 		dim.Fprintln(w, "    README clarity:      n/a (Claude CLI not detected)")
@@ -122,3 +126,18 @@ func renderC4VerboseMetrics(w io.Writer, m *types.C4Metrics, bold *color.Color)
 	fmt.Fprintf(w, "    Public APIs:         %d\n", m.PublicAPIs)
 	fmt.Fprintf(w, "    Documented APIs:     %d\n", m.DocumentedAPIs)
 }
+
+// formatC4Judge describes the judge behind the LLM scores, e.g. "openai (qwen2.5, temperature 0.0)".
+func formatC4Judge(m *types.C4Metrics) string {
+	var details []string
+	if m.LLMModel != "" {
+		details = append(details, m.LLMModel)
+	}
+	if m.LLMTemperature != nil {
+		details = append(details, fmt.Sprintf("temperature %.1f", *m.LLMTemperature))
+	}
+	if len(details) == 0 {
+		return m.LLMJudge
+	}
+	return m.LLMJudge + " (" + strings.Join(details, ", ") + ")"
+}
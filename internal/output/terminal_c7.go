@@ -7,6 +7,7 @@ import (
 
 	"github.com/fatih/color"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -61,29 +62,71 @@ func renderC7(w io.Writer, ar *types.AnalysisResult, verbose bool) {
 func renderC7Metrics(w io.Writer, m *types.C7Metrics) {
 	if m.TaskExecutionConsistency > 0 || m.CodeBehaviorComprehension > 0 ||
 		m.CrossFileNavigation > 0 || m.IdentifierInterpretability > 0 ||
-		m.DocumentationAccuracyDetection > 0 {
+		m.DocumentationAccuracyDetection > 0 || m.ChangeSuccess > 0 ||
+		m.FeatureLocalization > 0 {
 		renderC7MECEMetrics(w, m)
 	} else {
 		renderC7LegacyMetrics(w, m)
 	}
 }
 
-// renderC7MECEMetrics renders the 5 MECE metric scores.
+// renderC7MECEMetrics renders the MECE metric scores, followed by any
+// user-defined tasks. Change success and feature localization are omitted
+// when they could not run.
 func renderC7MECEMetrics(w io.Writer, m *types.C7Metrics) {
 	m1c := c7ScoreColor(m.TaskExecutionConsistency * c7ScoreScale)
-	m1c.Fprintf(w, "  M1 Exec Consistency:  %d/10\n", m.TaskExecutionConsistency)
+	m1c.Fprintf(w, "  M1 Exec Consistency:  %d/10%s\n", m.TaskExecutionConsistency, formatInterval(c7Interval(m, "task_execution_consistency")))
 
 	m2c := c7ScoreColor(m.CodeBehaviorComprehension * c7ScoreScale)
-	m2c.Fprintf(w, "  M2 Comprehension:     %d/10\n", m.CodeBehaviorComprehension)
+	m2c.Fprintf(w, "  M2 Comprehension:     %d/10%s\n", m.CodeBehaviorComprehension, formatInterval(c7Interval(m, "code_behavior_comprehension")))
 
 	m3c := c7ScoreColor(m.CrossFileNavigation * c7ScoreScale)
-	m3c.Fprintf(w, "  M3 Navigation:        %d/10\n", m.CrossFileNavigation)
+	m3c.Fprintf(w, "  M3 Navigation:        %d/10%s\n", m.CrossFileNavigation, formatInterval(c7Interval(m, "cross_file_navigation")))
 
 	m4c := c7ScoreColor(m.IdentifierInterpretability * c7ScoreScale)
-	m4c.Fprintf(w, "  M4 Identifiers:       %d/10\n", m.IdentifierInterpretability)
+	m4c.Fprintf(w, "  M4 Identifiers:       %d/10%s\n", m.IdentifierInterpretability, formatInterval(c7Interval(m, "identifier_interpretability")))
 
 	m5c := c7ScoreColor(m.DocumentationAccuracyDetection * c7ScoreScale)
-	m5c.Fprintf(w, "  M5 Documentation:     %d/10\n", m.DocumentationAccuracyDetection)
+	m5c.Fprintf(w, "  M5 Documentation:     %d/10%s\n", m.DocumentationAccuracyDetection, formatInterval(c7Interval(m, "documentation_accuracy_detection")))
+
+	if m.ChangeSuccess > 0 {
+		m6c := c7ScoreColor(m.ChangeSuccess * c7ScoreScale)
+		m6c.Fprintf(w, "  M6 Change Success:    %d/10%s\n", m.ChangeSuccess, formatInterval(c7Interval(m, "change_success")))
+	}
+
+	if m.FeatureLocalization > 0 {
+		m7c := c7ScoreColor(m.FeatureLocalization * c7ScoreScale)
+		m7c.Fprintf(w, "  M7 Localization:      %d/10%s%s\n", m.FeatureLocalization, formatInterval(c7Interval(m, "feature_localization")), formatLocalization(m))
+	}
+
+	for _, mr := range m.MetricResults {
+		if metrics.IsBuiltin(mr.MetricID) {
+			continue
+		}
+		tc := c7ScoreColor(mr.Score * c7ScoreScale)
+		tc.Fprintf(w, "  %-21s %d/10%s\n", truncateString(mr.MetricName, 20)+":", mr.Score, formatInterval(mr.Interval))
+	}
+}
+
+// formatLocalization returns the top-1/top-3 accuracy and mean tool calls of
+// feature localization.
+func formatLocalization(m *types.C7Metrics) string {
+	s := fmt.Sprintf("  (top-1 %.0f%%, top-3 %.0f%%", m.LocalizationTop1*100, m.LocalizationTop3*100)
+	if m.LocalizationToolCalls >= 0 {
+		s += fmt.Sprintf(", %.1f tool calls", m.LocalizationToolCalls)
+	}
+	return s + ")"
+}
+
+// c7Interval returns the interval of a metric's score over repeated runs, or
+// nil for a single run.
+func c7Interval(m *types.C7Metrics, metricID string) *types.ScoreInterval {
+	for _, mr := range m.MetricResults {
+		if mr.MetricID == metricID {
+			return mr.Interval
+		}
+	}
+	return nil
 }
 
 // renderC7LegacyMetrics renders legacy 0-100 scale metrics.
@@ -106,13 +149,35 @@ func renderC7Summary(w io.Writer, m *types.C7Metrics) {
 	fmt.Fprintln(w, "  ─────────────────────────────────────")
 	if m.MECEScore > 0 {
 		os := c7ScoreColor(int(m.MECEScore * float64(c7ScoreScale)))
-		os.Fprintf(w, "  MECE Score:           %.1f/10\n", m.MECEScore)
+		os.Fprintf(w, "  MECE Score:           %.1f/10%s\n", m.MECEScore, formatInterval(m.MECEInterval))
 	} else {
 		os := c7ScoreColor(int(m.OverallScore))
 		os.Fprintf(w, "  Overall score:        %.1f/100\n", m.OverallScore)
 	}
+	if m.Repeats > 1 {
+		fmt.Fprintf(w, "  Repeats:              %d\n", m.Repeats)
+	}
+	if nav := m.Navigation; nav != nil {
+		fmt.Fprintf(w, "  Navigation per task:  %.1f tool calls, %.1f files read, %.1f redundant reads\n",
+			nav.ToolCalls, nav.FilesRead, nav.RedundantReads)
+	}
 	fmt.Fprintf(w, "  Duration:             %.1fs\n", m.TotalDuration)
 	fmt.Fprintf(w, "  Estimated cost:       $%.4f\n", m.CostUSD)
+	if m.CachedResponses > 0 {
+		fmt.Fprintf(w, "  Cached responses:     %d\n", m.CachedResponses)
+	}
+	if m.Retries > 0 {
+		fmt.Fprintf(w, "  Retried calls:        %d\n", m.Retries)
+	}
+	var skipped []string
+	for _, mr := range m.MetricResults {
+		if mr.Status == "skipped" {
+			skipped = append(skipped, mr.MetricName)
+		}
+	}
+	if len(skipped) > 0 {
+		color.New(color.FgYellow).Fprintf(w, "  Skipped (budget):     %s\n", strings.Join(skipped, ", "))
+	}
 }
 
 // renderC7VerboseTasks renders per-task breakdown in verbose mode.
@@ -176,7 +241,7 @@ func RenderC7Debug(w io.Writer, analysisResults []*types.AnalysisResult) {
 		for i, ds := range mr.DebugSamples {
 			fmt.Fprintf(w, "  Sample %d: %s\n", i+1, ds.Description)
 			fmt.Fprintf(w, "  File:     %s\n", ds.FilePath)
-			fmt.Fprintf(w, "  Score:    %d/10  Duration: %.1fs\n", ds.Score, ds.Duration)
+			fmt.Fprintf(w, "  Score:    %d/10  Duration: %.1fs%s\n", ds.Score, ds.Duration, formatRetries(ds.Retries))
 
 			// Prompt (truncated, dim)
 			prompt := truncateString(ds.Prompt, truncateShort)
@@ -188,6 +253,9 @@ func RenderC7Debug(w io.Writer, analysisResults []*types.AnalysisResult) {
 
 			// Score trace
 			renderScoreTrace(w, ds.ScoreTrace)
+			if tt := ds.ToolTrace; tt != nil {
+				fmt.Fprintf(w, "  Tools:    %d calls, %d files read, %d redundant reads\n", len(tt.Calls), tt.FilesRead, tt.RedundantReads)
+			}
 
 			// Error (red, if present)
 			if ds.Error != "" {
@@ -202,6 +270,14 @@ func RenderC7Debug(w io.Writer, analysisResults []*types.AnalysisResult) {
 	}
 }
 
+// formatRetries returns the retry count of a sample, or "" if it had none.
+func formatRetries(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  Retries: %d", n)
+}
+
 // renderScoreTrace prints the score trace breakdown for a single debug sample.
 func renderScoreTrace(w io.Writer, trace types.C7ScoreTrace) {
 	var parts []string
@@ -221,4 +297,28 @@ func renderScoreTrace(w io.Writer, trace types.C7ScoreTrace) {
 		indicators = " "
 	}
 	fmt.Fprintf(w, "  Trace:    base=%d%s-> final=%d\n", trace.BaseScore, indicators, trace.FinalScore)
+	for _, g := range trace.GroundTruth {
+		fmt.Fprintf(w, "  Truth:    %s precision=%.0f%% recall=%.0f%% matched=%d missed=%d hallucinated=%d\n",
+			g.Kind, g.Precision*100, g.Recall*100, len(g.Matched), len(g.Missed), len(g.Hallucinated))
+		if len(g.Missed) > 0 {
+			fmt.Fprintf(w, "            missed: %s\n", strings.Join(g.Missed, ", "))
+		}
+		if len(g.Hallucinated) > 0 {
+			fmt.Fprintf(w, "            hallucinated: %s\n", strings.Join(g.Hallucinated, ", "))
+		}
+	}
+	if trace.RunAgreement != nil {
+		fmt.Fprintf(w, "  Agreement: Jaccard %.2f with the other runs\n", *trace.RunAgreement)
+	}
+	if c := trace.Change; c != nil {
+		status := "pass"
+		if !c.Passed {
+			status = "fail"
+		}
+		fmt.Fprintf(w, "  Change:   build/tests=%s diff=+%d/-%d files=%s\n",
+			status, c.LinesAdded, c.LinesDeleted, strings.Join(c.FilesTouched, ", "))
+	}
+	if trace.Justification != "" {
+		fmt.Fprintf(w, "  Judge:    %s\n", trace.Justification)
+	}
 }
@@ -92,6 +92,8 @@ func newTestAnalysisResults() []*types.AnalysisResult {
 					OverallScore:           74.0,
 					TotalDuration:          45.5,
 					CostUSD:                0.0125,
+					Navigation:             &types.C7Navigation{Samples: 3, ToolCalls: 4, FilesRead: 2.5, RedundantReads: 0.5},
+					Retries:                2,
 					TaskResults: []types.C7TaskResult{
 						{TaskID: "intent_clarity", TaskName: "Intent Clarity", Score: 75, Status: "completed", Duration: 12.3, Reasoning: "Clear function signatures"},
 						{TaskID: "modification_confidence", TaskName: "Modification Confidence", Score: 68, Status: "completed", Duration: 11.2, Reasoning: "Good test coverage"},
@@ -243,7 +245,9 @@ func TestRenderSummaryWithMetrics(t *testing.T) {
 		"Semantic complete:",
 		"Overall score:",
 		"Duration:",
+		"Navigation per task:  4.0 tool calls, 2.5 files read, 0.5 redundant reads",
 		"Estimated cost:",
+		"Retried calls:        2",
 	}
 	for _, check := range c7Checks {
 		if !strings.Contains(out, check) {
@@ -511,6 +515,11 @@ func TestRenderC7Debug(t *testing.T) {
 										},
 										FinalScore: 7,
 									},
+									ToolTrace: &types.C7ToolTrace{
+										Calls:          []types.C7ToolCall{{Tool: "Read", Path: "test.go"}, {Tool: "Read", Path: "test.go"}},
+										FilesRead:      1,
+										RedundantReads: 1,
+									},
 								},
 							},
 						},
@@ -534,6 +543,7 @@ func TestRenderC7Debug(t *testing.T) {
 		"positive:returns",
 		"Explain this code",
 		"The code implements",
+		"Tools:    2 calls, 1 files read, 1 redundant reads",
 	}
 	for _, check := range checks {
 		if !strings.Contains(out, check) {
@@ -872,6 +882,148 @@ func TestRenderScores_UnavailableCategory(t *testing.T) {
 	}
 }
 
+func TestRenderScores_LLMBudget(t *testing.T) {
+	var buf bytes.Buffer
+	scored := &types.ScoredResult{
+		Composite:  7.0,
+		Tier:       "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C7", Score: 6.0, Weight: 0.10}},
+		LLMBudget: &types.LLMBudget{
+			LimitUSD:       2,
+			SpentUSD:       2.05,
+			TokensUsed:     410000,
+			Exhausted:      true,
+			DroppedSamples: 3,
+			Skipped:        []string{"C7 change_success"},
+		},
+	}
+
+	RenderScores(&buf, scored, false)
+	out := buf.String()
+
+	if !strings.Contains(out, "$2.05, 410000 tokens of $2.00") {
+		t.Errorf("output should show spend against the budget\nGot:\n%s", out)
+	}
+	if !strings.Contains(out, "3 C7 samples dropped; skipped C7 change_success") {
+		t.Errorf("output should list the budget cuts\nGot:\n%s", out)
+	}
+
+	buf.Reset()
+	scored.LLMBudget = &types.LLMBudget{LimitUSD: 2, TokensUsed: 1200, Exhausted: true, RefusedCalls: 4, UnpricedModels: []string{"gpt-5-codex"}}
+	RenderScores(&buf, scored, false)
+	if !strings.Contains(buf.String(), "4 calls refused; no price for gpt-5-codex") {
+		t.Errorf("output should name the unpriced model\nGot:\n%s", buf.String())
+	}
+}
+
+func TestRenderScores_Interval(t *testing.T) {
+	var buf bytes.Buffer
+	band := &types.ScoreInterval{Runs: 5, Mean: 7.0, StdDev: 0.21, Low: 6.8, High: 7.3}
+	scored := &types.ScoredResult{
+		Composite:         7.0,
+		Tier:              "Agent-Assisted",
+		Categories:        []types.CategoryScore{{Name: "C7", Score: 6.0, Weight: 0.10, Interval: &types.ScoreInterval{Runs: 5, Mean: 6.0, StdDev: 0.4, Low: 5.6, High: 6.5}}},
+		CompositeInterval: band,
+	}
+
+	RenderScores(&buf, scored, false)
+	out := buf.String()
+
+	if !strings.Contains(out, "7.0 / 10  (±0.2, 95% CI 6.8-7.3, 5 runs)") {
+		t.Errorf("output should show the composite's uncertainty band\nGot:\n%s", out)
+	}
+	if !strings.Contains(out, "6.0 / 10  (±0.4, 95% CI 5.6-6.5, 5 runs)") {
+		t.Errorf("output should show the C7 interval\nGot:\n%s", out)
+	}
+}
+
+func TestRenderScores_Interrupted(t *testing.T) {
+	var buf bytes.Buffer
+	scored := &types.ScoredResult{
+		Composite:   7.0,
+		Tier:        "Agent-Assisted",
+		Categories:  []types.CategoryScore{{Name: "C7", Score: 6.0, Weight: 0.10, Interrupted: true}},
+		Interrupted: []string{"C5", "C7"},
+	}
+
+	RenderScores(&buf, scored, false)
+	out := buf.String()
+
+	if !strings.Contains(out, "6.0 / 10 (partial)") {
+		t.Errorf("output should mark the partially scored category\nGot:\n%s", out)
+	}
+	if !strings.Contains(out, "Cut short:                C5, C7") {
+		t.Errorf("output should list the categories cut short\nGot:\n%s", out)
+	}
+}
+
+func TestRenderDiagnostics(t *testing.T) {
+	diags := []types.Diagnostic{
+		{Severity: types.SeverityError, Analyzer: "C3", Message: "analyzer failed: boom"},
+		{Severity: types.SeverityWarning, Analyzer: "parser", File: "pkg/a.go", Message: "undefined: y"},
+		{Severity: types.SeverityInfo, Analyzer: "discovery", File: "link.go", Message: "skipped symlink"},
+	}
+
+	var buf bytes.Buffer
+	RenderDiagnostics(&buf, diags, false)
+	out := buf.String()
+	for _, want := range []string{"Diagnostics", "analyzer failed: boom", "pkg/a.go: undefined: y"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\nGot:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "skipped symlink") {
+		t.Errorf("info diagnostics should need --verbose\nGot:\n%s", out)
+	}
+
+	buf.Reset()
+	RenderDiagnostics(&buf, diags, true)
+	if !strings.Contains(buf.String(), "link.go: skipped symlink") {
+		t.Errorf("verbose output should list info diagnostics\nGot:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	RenderDiagnostics(&buf, diags[2:], false)
+	if buf.Len() != 0 {
+		t.Errorf("only info diagnostics should print nothing without --verbose, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderModules(t *testing.T) {
+	modules := []types.ModuleScore{
+		{Module: types.Module{Name: "example.com/util", Path: "libs/util", Language: types.LangGo}, LOC: 40, Composite: 8.2, Tier: "Agent-Ready"},
+		{Module: types.Module{Name: "web", Path: "web", Language: types.LangTypeScript}, LOC: 900, Composite: 5.1, Tier: "Agent-Limited"},
+	}
+
+	var buf bytes.Buffer
+	RenderModules(&buf, modules)
+	out := buf.String()
+	for _, want := range []string{"Modules", "libs/util", "example.com/util", "900 LOC", "8.2 / 10", "Agent-Limited"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\nGot:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	RenderModules(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("single-module project should print nothing, got:\n%s", buf.String())
+	}
+}
+
+func TestRenderDiagnostics_Truncated(t *testing.T) {
+	var diags []types.Diagnostic
+	for range diagnosticsTopN + 3 {
+		diags = append(diags, types.Diagnostic{Severity: types.SeverityWarning, Analyzer: "parser", Message: "undefined: y"})
+	}
+
+	var buf bytes.Buffer
+	RenderDiagnostics(&buf, diags, false)
+	if !strings.Contains(buf.String(), "... and 3 more") {
+		t.Errorf("output should truncate to %d diagnostics\nGot:\n%s", diagnosticsTopN, buf.String())
+	}
+}
+
 func TestRenderScores_Verbose(t *testing.T) {
 	var buf bytes.Buffer
 	scored := &types.ScoredResult{
@@ -1036,3 +1188,53 @@ func TestRenderBadge_Nil(t *testing.T) {
 		t.Errorf("expected empty output for nil scored result, got:\n%s", out)
 	}
 }
+
+func TestRenderWatchDashboard(t *testing.T) {
+	start := &types.ScoredResult{
+		Composite: 6.0, Tier: "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C1", Score: 6.0}, {Name: "C4", Score: 5.0}},
+	}
+	prev := &types.ScoredResult{
+		Composite: 6.2, Tier: "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C1", Score: 6.0}, {Name: "C4", Score: 5.5}},
+	}
+	cur := &types.ScoredResult{
+		Composite: 6.5, Tier: "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C1", Score: 6.0}, {Name: "C4", Score: 6.5}},
+	}
+
+	var buf bytes.Buffer
+	RenderWatchDashboard(&buf, WatchFrame{
+		Dir: "/proj", Current: cur, Start: start, Previous: prev,
+		Changed: []string{"README.md"}, Rerun: []string{"C4"},
+	}, false)
+	out := buf.String()
+
+	for _, want := range []string{"ARS Watch: /proj", "re-ran C4", "*C4: Documentation Quality", "+1.0", "+1.5", "+0.3", "+0.5", "Changed: README.md"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dashboard missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "*C1") {
+		t.Error("C1 was not re-run and should not be marked")
+	}
+	if strings.Contains(out, clearScreen) {
+		t.Error("screen should not be cleared when clear=false")
+	}
+}
+
+func TestRenderWatchDashboard_FirstFrame(t *testing.T) {
+	cur := &types.ScoredResult{
+		Composite: 7.0, Tier: "Agent-Assisted",
+		Categories: []types.CategoryScore{{Name: "C1", Score: 7.0}},
+	}
+	var buf bytes.Buffer
+	RenderWatchDashboard(&buf, WatchFrame{Dir: "/proj", Current: cur, Start: cur}, true)
+	out := buf.String()
+	if !strings.HasPrefix(out, clearScreen) {
+		t.Error("expected screen clear prefix")
+	}
+	if !strings.Contains(out, "initial scan") {
+		t.Errorf("first frame should report the initial scan:\n%s", out)
+	}
+}
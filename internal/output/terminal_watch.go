@@ -0,0 +1,134 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Watch dashboard display constants.
+const (
+	watchDeltaEpsilon = 0.05 // Deltas smaller than this render as unchanged
+	watchMaxChanged   = 5    // Changed files listed before summarizing the rest
+	clearScreen       = "\033[H\033[2J"
+)
+
+// WatchFrame is one refresh of the `ars watch` dashboard.
+type WatchFrame struct {
+	Dir      string
+	Current  *types.ScoredResult
+	Start    *types.ScoredResult // score when watching began
+	Previous *types.ScoredResult // score before this update; nil on the first frame
+	Changed  []string            // changed files, relative to Dir
+	Rerun    []string            // categories re-analyzed for this frame
+	Warnings []string
+	Elapsed  time.Duration
+	At       time.Time
+}
+
+// RenderWatchDashboard prints a compact score table with deltas against the
+// previous update and the start of the session. If clear is true the terminal
+// is cleared first so the dashboard redraws in place.
+func RenderWatchDashboard(w io.Writer, f WatchFrame, clear bool) {
+	if clear {
+		fmt.Fprint(w, clearScreen)
+	}
+	bold := color.New(color.Bold)
+	faint := color.New(color.FgHiBlack)
+
+	bold.Fprintf(w, "ARS Watch: %s\n", f.Dir)
+	status := fmt.Sprintf("%s  initial scan in %.1fs", f.At.Format("15:04:05"), f.Elapsed.Seconds())
+	if f.Previous != nil {
+		status = fmt.Sprintf("%s  re-ran %s in %.1fs", f.At.Format("15:04:05"), strings.Join(f.Rerun, " "), f.Elapsed.Seconds())
+	}
+	faint.Fprintln(w, status)
+	fmt.Fprintln(w, "────────────────────────────────────────────────────────")
+	faint.Fprintf(w, "  %-28s %6s %8s %8s\n", "", "score", "Δlast", "Δstart")
+
+	rerun := make(map[string]bool, len(f.Rerun))
+	for _, c := range f.Rerun {
+		rerun[c] = true
+	}
+	for _, cat := range f.Current.Categories {
+		marker := " "
+		if rerun[cat.Name] && f.Previous != nil {
+			marker = "*"
+		}
+		label := fmt.Sprintf("%s%s: %s", marker, cat.Name, categoryDisplayNames[cat.Name])
+		if cat.Score < 0 {
+			faint.Fprintf(w, " %-28s %6s\n", label, "n/a")
+			continue
+		}
+		fmt.Fprintf(w, " %-28s ", label)
+		scoreColor(cat.Score).Fprintf(w, "%6.1f", cat.Score)
+		renderWatchDelta(w, cat.Score, categoryScore(f.Previous, cat.Name))
+		renderWatchDelta(w, cat.Score, categoryScore(f.Start, cat.Name))
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "────────────────────────────────────────────────────────")
+	fmt.Fprintf(w, "  %-27s ", "Composite")
+	scoreColor(f.Current.Composite).Fprintf(w, "%6.1f", f.Current.Composite)
+	renderWatchDelta(w, f.Current.Composite, compositeScore(f.Previous))
+	renderWatchDelta(w, f.Current.Composite, compositeScore(f.Start))
+	fmt.Fprint(w, "  ")
+	tierColor(f.Current.Tier).Fprintln(w, f.Current.Tier)
+
+	if len(f.Changed) > 0 {
+		changed := f.Changed
+		more := ""
+		if len(changed) > watchMaxChanged {
+			more = fmt.Sprintf(" (+%d more)", len(changed)-watchMaxChanged)
+			changed = changed[:watchMaxChanged]
+		}
+		faint.Fprintf(w, "\nChanged: %s%s\n", strings.Join(changed, ", "), more)
+	}
+	for _, warn := range f.Warnings {
+		color.New(color.FgYellow).Fprintf(w, "Warning: %s\n", warn)
+	}
+	faint.Fprintln(w, "\nWatching for changes... (Ctrl+C to stop)")
+}
+
+// renderWatchDelta prints cur-prev as a signed, colored column; "-" if prev is unknown.
+func renderWatchDelta(w io.Writer, cur float64, prev float64) {
+	if math.IsNaN(prev) || prev < 0 {
+		color.New(color.FgHiBlack).Fprintf(w, " %8s", "-")
+		return
+	}
+	delta := cur - prev
+	switch {
+	case delta >= watchDeltaEpsilon:
+		color.New(color.FgGreen).Fprintf(w, " %+8.1f", delta)
+	case delta <= -watchDeltaEpsilon:
+		color.New(color.FgRed).Fprintf(w, " %+8.1f", delta)
+	default:
+		color.New(color.FgHiBlack).Fprintf(w, " %8s", "·")
+	}
+}
+
+// categoryScore returns the named category's score in r, or NaN if absent.
+func categoryScore(r *types.ScoredResult, name string) float64 {
+	if r == nil {
+		return math.NaN()
+	}
+	for _, c := range r.Categories {
+		if c.Name == name {
+			return c.Score
+		}
+	}
+	return math.NaN()
+}
+
+// compositeScore returns r's composite score, or NaN if r is nil.
+func compositeScore(r *types.ScoredResult) float64 {
+	if r == nil {
+		return math.NaN()
+	}
+	return r.Composite
+}
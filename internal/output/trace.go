@@ -159,6 +159,19 @@ func renderC7Trace(metricID string, metricResults []types.C7MetricResult) string
 			ds.ScoreTrace.BaseScore, ds.ScoreTrace.FinalScore))
 		b.WriteString(`</div>`)
 
+		renderGroundTruth(&b, ds.ScoreTrace.GroundTruth)
+		if ds.ScoreTrace.RunAgreement != nil {
+			b.WriteString(fmt.Sprintf(`<p class="trace-score-summary">Agreement with other runs (Jaccard): %.2f</p>`,
+				*ds.ScoreTrace.RunAgreement))
+		}
+		renderChangeOutcome(&b, ds.ScoreTrace.Change)
+		renderLocalizationOutcome(&b, ds.ScoreTrace.Localization)
+		if ds.ScoreTrace.Justification != "" {
+			b.WriteString(fmt.Sprintf(`<p class="trace-score-summary">Judge: %s</p>`,
+				template.HTMLEscapeString(ds.ScoreTrace.Justification)))
+		}
+		renderToolTimeline(&b, ds.ToolTrace, ds.Duration)
+
 		// Collapsible prompt section
 		escapedFilePath := template.HTMLEscapeString(ds.FilePath)
 		escapedPrompt := template.HTMLEscapeString(ds.Prompt)
@@ -185,3 +198,110 @@ func renderC7Trace(metricID string, metricResults []types.C7MetricResult) string
 
 	return b.String()
 }
+
+// renderGroundTruth renders the matched, missed and hallucinated items of a
+// ground-truth comparison as a table, one row per item kind.
+func renderGroundTruth(b *strings.Builder, matches []types.C7GroundTruthMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	b.WriteString(`<table class="trace-evidence-table"><thead><tr><th>Ground truth</th><th>Precision</th><th>Recall</th><th>Matched</th><th>Missed</th><th>Hallucinated</th></tr></thead><tbody>`)
+	for _, g := range matches {
+		b.WriteString(fmt.Sprintf(`<tr><td>%s</td><td>%.0f%%</td><td>%.0f%%</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+			template.HTMLEscapeString(g.Kind), g.Precision*100, g.Recall*100,
+			escapeList(g.Matched), escapeList(g.Missed), escapeList(g.Hallucinated)))
+	}
+	b.WriteString(`</tbody></table>`)
+}
+
+// renderChangeOutcome renders the build/test result and diff of a change task.
+func renderChangeOutcome(b *strings.Builder, c *types.C7ChangeOutcome) {
+	if c == nil {
+		return
+	}
+	status := "pass"
+	if !c.Passed {
+		status = "fail"
+	}
+	b.WriteString(`<table class="trace-evidence-table"><thead><tr><th>Build &amp; tests</th><th>Lines added</th><th>Lines deleted</th><th>Files touched</th></tr></thead><tbody>`)
+	b.WriteString(fmt.Sprintf(`<tr><td>%s</td><td>%d</td><td>%d</td><td>%s</td></tr>`,
+		status, c.LinesAdded, c.LinesDeleted, escapeList(c.FilesTouched)))
+	b.WriteString(`</tbody></table>`)
+	if c.Output != "" {
+		b.WriteString(`<details class="trace-collapsible"><summary>Build/test output</summary>`)
+		b.WriteString(fmt.Sprintf(`<div class="trace-code-block"><pre><code>%s</code></pre></div></details>`,
+			template.HTMLEscapeString(c.Output)))
+	}
+}
+
+// renderLocalizationOutcome renders the expected symbol of a feature
+// localization sample next to the agent's ranked answers.
+func renderLocalizationOutcome(b *strings.Builder, l *types.C7LocalizationOutcome) {
+	if l == nil {
+		return
+	}
+	rank := "not found"
+	if l.Rank > 0 {
+		rank = fmt.Sprintf("#%d", l.Rank)
+	}
+	toolCalls := "n/a"
+	if l.ToolCalls >= 0 {
+		toolCalls = fmt.Sprintf("%d", l.ToolCalls)
+	}
+	b.WriteString(`<table class="trace-evidence-table"><thead><tr><th>Expected</th><th>Answers</th><th>Rank</th><th>Tool calls</th></tr></thead><tbody>`)
+	b.WriteString(fmt.Sprintf(`<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>`,
+		template.HTMLEscapeString(l.Expected), escapeList(l.Answers), rank, toolCalls))
+	b.WriteString(`</tbody></table>`)
+}
+
+// renderToolTimeline renders the tool calls of a sample as a timeline, one row
+// per call with a bar placed by its start and duration within the run. Rows
+// stop at toolTimelineMaxRows to keep the report small.
+func renderToolTimeline(b *strings.Builder, trace *types.C7ToolTrace, runSeconds float64) {
+	if trace == nil {
+		return
+	}
+	summary := fmt.Sprintf("Tool calls: %d &middot; files read: %d &middot; redundant reads: %d",
+		len(trace.Calls), trace.FilesRead, trace.RedundantReads)
+	if len(trace.Calls) == 0 {
+		b.WriteString(fmt.Sprintf(`<p class="trace-score-summary">%s</p>`, summary))
+		return
+	}
+
+	span := runSeconds
+	for _, c := range trace.Calls {
+		span = max(span, c.Start+c.Duration)
+	}
+	if span <= 0 {
+		span = 1
+	}
+
+	b.WriteString(fmt.Sprintf(`<details class="trace-collapsible"><summary>%s</summary><div class="trace-timeline">`, summary))
+	for i, c := range trace.Calls {
+		if i == toolTimelineMaxRows {
+			b.WriteString(fmt.Sprintf(`<div class="trace-timeline-more">&hellip; %d more</div>`, len(trace.Calls)-i))
+			break
+		}
+		cssClass := "trace-timeline-row"
+		if c.Error {
+			cssClass += " error"
+		}
+		label := c.Input
+		if c.Tool == "Read" && c.Path != "" {
+			label = c.Path
+		}
+		b.WriteString(fmt.Sprintf(`<div class="%s"><span>+%.1fs</span><b>%s</b><span class="trace-timeline-track"><span style="left:%.1f%%;width:%.1f%%"></span></span><span>%s</span></div>`,
+			cssClass, c.Start, template.HTMLEscapeString(c.Tool),
+			c.Start/span*100, max(c.Duration/span*100, 0.5), template.HTMLEscapeString(label)))
+	}
+	b.WriteString(`</div></details>`)
+}
+
+// escapeList joins HTML-escaped items with line breaks.
+func escapeList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = template.HTMLEscapeString(item)
+	}
+	return strings.Join(escaped, "<br>")
+}
@@ -0,0 +1,197 @@
+package parser
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tree_sitter "github.com/tree-sitter/go-tree-sitter"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// GoPackageCache keeps parsed Go packages in memory and reloads only the packages
+// whose files were invalidated. GoPackagesParser.Parse reloads the whole module on
+// every call, which is too slow for long-running callers (ars watch, ars lsp).
+type GoPackageCache struct {
+	root      string
+	parser    *GoPackagesParser
+	pkgs      []*ParsedPackage
+	loaded    bool
+	fullStale bool            // module-level file changed; reload everything
+	dirty     map[string]bool // package directories to reload
+}
+
+// NewGoPackageCache creates a cache for the module rooted at root. Nothing is
+// parsed until Packages is first called.
+func NewGoPackageCache(root string) *GoPackageCache {
+	return &GoPackageCache{
+		root:   root,
+		parser: &GoPackagesParser{},
+		dirty:  make(map[string]bool),
+	}
+}
+
+// Invalidate marks the package containing path as stale. Changes to go.mod or
+// go.sum invalidate the whole module. Non-Go files are ignored.
+func (c *GoPackageCache) Invalidate(path string) {
+	switch filepath.Base(path) {
+	case "go.mod", "go.sum", "go.work", "go.work.sum":
+		c.fullStale = true
+		return
+	}
+	if filepath.Ext(path) != ".go" {
+		return
+	}
+	c.dirty[filepath.Dir(path)] = true
+}
+
+// Packages returns the cached packages, first reloading anything invalidated
+// since the previous call. The first call parses the whole module.
+func (c *GoPackageCache) Packages(ctx context.Context) ([]*ParsedPackage, error) {
+	if !c.loaded || c.fullStale {
+		pkgs, err := c.parser.Parse(ctx, c.root)
+		if err != nil {
+			return nil, err
+		}
+		c.pkgs = pkgs
+		c.loaded = true
+		c.fullStale = false
+		c.dirty = make(map[string]bool)
+		return c.pkgs, nil
+	}
+	if len(c.dirty) == 0 {
+		return c.pkgs, nil
+	}
+
+	dirs := make([]string, 0, len(c.dirty))
+	for dir := range c.dirty {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var patterns []string
+	for _, dir := range dirs {
+		if !hasGoFiles(dir) {
+			continue // package deleted; dropping it below is enough
+		}
+		rel, err := filepath.Rel(c.root, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		patterns = append(patterns, "./"+filepath.ToSlash(rel))
+	}
+
+	var fresh []*ParsedPackage
+	if len(patterns) > 0 {
+		var err error
+		fresh, err = c.parser.ParsePatterns(ctx, c.root, patterns...)
+		if err != nil {
+			return nil, fmt.Errorf("reload packages: %w", err)
+		}
+	}
+
+	kept := make([]*ParsedPackage, 0, len(c.pkgs))
+	for _, pkg := range c.pkgs {
+		if !c.dirty[PackageDir(pkg)] {
+			kept = append(kept, pkg)
+		}
+	}
+	c.pkgs = append(kept, fresh...)
+	c.dirty = make(map[string]bool)
+	return c.pkgs, nil
+}
+
+// PackageDir returns the directory holding a package's files, or "" if the
+// package has no Go files.
+func PackageDir(pkg *ParsedPackage) string {
+	if len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+// hasGoFiles reports whether dir exists and contains at least one .go file.
+func hasGoFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+			return true
+		}
+	}
+	return false
+}
+
+// treeCacheKey identifies a parse by grammar and exact content.
+type treeCacheKey struct {
+	lang types.Language
+	tsx  bool
+	sum  [sha256.Size]byte
+}
+
+// treeCacheEntry is a cached tree plus whether it was requested since the last prune.
+type treeCacheEntry struct {
+	tree *tree_sitter.Tree
+	used bool
+}
+
+// EnableTreeCache makes ParseFile keep every parsed tree in memory, keyed by content,
+// and hand out copies on later calls with identical content. Long-running callers
+// use this so that only changed files are re-parsed; call PruneTreeCache after each
+// analysis pass to release trees of files that changed or disappeared.
+func (p *TreeSitterParser) EnableTreeCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cache == nil {
+		p.cache = make(map[treeCacheKey]*treeCacheEntry)
+	}
+}
+
+// PruneTreeCache releases cached trees that were not requested since the previous
+// prune and returns how many were dropped.
+func (p *TreeSitterParser) PruneTreeCache() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	dropped := 0
+	for key, entry := range p.cache {
+		if !entry.used {
+			entry.tree.Close()
+			delete(p.cache, key)
+			dropped++
+			continue
+		}
+		entry.used = false
+	}
+	return dropped
+}
+
+// cachedTree returns a copy of the cached tree for key, if present. Caller holds p.mu.
+func (p *TreeSitterParser) cachedTree(key treeCacheKey) *tree_sitter.Tree {
+	entry, ok := p.cache[key]
+	if !ok {
+		return nil
+	}
+	entry.used = true
+	return entry.tree.Clone()
+}
+
+// storeTree caches tree under key and returns a copy for the caller. Caller holds p.mu.
+func (p *TreeSitterParser) storeTree(key treeCacheKey, tree *tree_sitter.Tree) *tree_sitter.Tree {
+	p.cache[key] = &treeCacheEntry{tree: tree, used: true}
+	return tree.Clone()
+}
+
+// closeTreeCache releases all cached trees. Caller holds p.mu.
+func (p *TreeSitterParser) closeTreeCache() {
+	for key, entry := range p.cache {
+		entry.tree.Close()
+		delete(p.cache, key)
+	}
+}
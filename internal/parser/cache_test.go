@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// writeModule creates a two-package Go module in a temp directory.
+func writeModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/cache\n\ngo 1.21\n",
+		"a/a.go":      "package a\n\nfunc A() int { return 1 }\n",
+		"b/b.go":      "package b\n\nimport \"example.com/cache/a\"\n\nfunc B() int { return a.A() }\n",
+		"b/b_test.go": "package b\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) { _ = B() }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func findPackage(pkgs []*ParsedPackage, pkgPath string) *ParsedPackage {
+	for _, p := range pkgs {
+		if p.PkgPath == pkgPath && p.ForTest == "" {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestGoPackageCache_ReloadsOnlyInvalidatedPackage(t *testing.T) {
+	root := writeModule(t)
+	cache := NewGoPackageCache(root)
+
+	first, err := cache.Packages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a1 := findPackage(first, "example.com/cache/a")
+	b1 := findPackage(first, "example.com/cache/b")
+	if a1 == nil || b1 == nil {
+		t.Fatalf("expected packages a and b, got %d packages", len(first))
+	}
+
+	// No invalidation: same slice, same packages.
+	again, err := cache.Packages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findPackage(again, "example.com/cache/a") != a1 {
+		t.Error("package a should be served from cache without invalidation")
+	}
+
+	aFile := filepath.Join(root, "a", "a.go")
+	if err := os.WriteFile(aFile, []byte("package a\n\nfunc A() int { return 2 }\n\nfunc Extra() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache.Invalidate(aFile)
+	second, err := cache.Packages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a2 := findPackage(second, "example.com/cache/a")
+	if a2 == nil || a2 == a1 {
+		t.Fatal("package a should have been reloaded")
+	}
+	if a2.Types.Scope().Lookup("Extra") == nil {
+		t.Error("reloaded package a should contain Extra")
+	}
+	if findPackage(second, "example.com/cache/b") != b1 {
+		t.Error("package b should not have been reloaded")
+	}
+	if len(second) != len(first) {
+		t.Errorf("package count changed from %d to %d", len(first), len(second))
+	}
+}
+
+func TestGoPackageCache_DeletedPackageDropped(t *testing.T) {
+	root := writeModule(t)
+	cache := NewGoPackageCache(root)
+	if _, err := cache.Packages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	aFile := filepath.Join(root, "a", "a.go")
+	if err := os.RemoveAll(filepath.Dir(aFile)); err != nil {
+		t.Fatal(err)
+	}
+	cache.Invalidate(aFile)
+	pkgs, err := cache.Packages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if findPackage(pkgs, "example.com/cache/a") != nil {
+		t.Error("deleted package a should be dropped from the cache")
+	}
+}
+
+func TestGoPackageCache_IgnoresNonGoFiles(t *testing.T) {
+	cache := NewGoPackageCache(t.TempDir())
+	cache.Invalidate("/x/README.md")
+	if len(cache.dirty) != 0 || cache.fullStale {
+		t.Error("non-Go files should not invalidate anything")
+	}
+	cache.Invalidate("/x/go.mod")
+	if !cache.fullStale {
+		t.Error("go.mod should invalidate the whole module")
+	}
+}
+
+func TestTreeCache_ReusesAndPrunes(t *testing.T) {
+	p, err := NewTreeSitterParser()
+	if err != nil {
+		t.Skip("Tree-sitter not available:", err)
+	}
+	defer p.Close()
+	p.EnableTreeCache()
+
+	src := []byte("def f():\n    return 1\n")
+	t1, err := p.ParseFile(types.LangPython, ".py", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t1.Close() // callers close their copy; the cached tree must survive
+	t2, err := p.ParseFile(types.LangPython, ".py", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer t2.Close()
+	if t2.RootNode().Kind() != "module" {
+		t.Errorf("cached tree root = %q, want module", t2.RootNode().Kind())
+	}
+	if len(p.cache) != 1 {
+		t.Fatalf("cache size = %d, want 1", len(p.cache))
+	}
+
+	// First prune keeps trees used since the cache was enabled; the second drops
+	// trees nobody requested in between.
+	if n := p.PruneTreeCache(); n != 0 {
+		t.Errorf("first prune dropped %d trees, want 0", n)
+	}
+	if n := p.PruneTreeCache(); n != 1 {
+		t.Errorf("second prune dropped %d trees, want 1", n)
+	}
+}
@@ -3,13 +3,20 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
-	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
+	arstypes "github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
 // ParsedPackage holds all analysis-relevant data for a single Go package
@@ -19,7 +26,7 @@ type ParsedPackage struct {
 	Name      string                        // Package name (e.g., "main", "parser")
 	PkgPath   string                        // Full import path
 	GoFiles   []string                      // .go source file paths
-	Syntax    []*ast.File                   // Parsed AST for each file
+	Syntax    []*ast.File                   // Parsed AST for each file; nil if loaded without syntax (see ParseExcept)
 	Fset      *token.FileSet                // Shared file set for position info
 	Types     *types.Package                // Type-checked package
 	TypesInfo *types.Info                   // Detailed type info (uses, defs, etc.)
@@ -32,20 +39,99 @@ type GoPackagesParser struct{}
 
 // Parse loads all packages in the given root directory using go/packages.Load.
 // It returns source packages and test packages separately identified via ForTest.
-// Packages with errors are skipped with a log warning.
-func (p *GoPackagesParser) Parse(rootDir string) ([]*ParsedPackage, error) {
-	cfg := createPackageConfig(rootDir)
-	pkgs, err := packages.Load(cfg, "./...")
+// Packages with errors are skipped with a log warning. Cancelling ctx stops
+// the underlying go list.
+func (p *GoPackagesParser) Parse(ctx context.Context, rootDir string) ([]*ParsedPackage, error) {
+	return p.ParsePatterns(ctx, rootDir, "./...")
+}
+
+// ParsePatterns loads only the packages matching the given go/packages patterns
+// (e.g. "./internal/foo"), resolved relative to rootDir. It is used by callers
+// that re-analyze a single package after an edit instead of the whole module.
+func (p *GoPackagesParser) ParsePatterns(ctx context.Context, rootDir string, patterns ...string) ([]*ParsedPackage, error) {
+	cfg := createPackageConfig(ctx, rootDir)
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("packages.Load: %w", err)
+	}
+
+	return deduplicateAndConvertPackages(ctx, rootDir, pkgs), nil
+}
+
+// ParseExcept loads all packages in rootDir like Parse, but type-checks only the
+// directories holding a package for which cached reports false. Packages of
+// the other directories carry their name, files and imports but no syntax or
+// type information; callers use it to skip packages whose results they have
+// cached. Packages outside rootDir, such as generated test mains, and
+// packages with load errors are never passed to cached. Type errors are only
+// found, and reported, in the packages that are type-checked.
+func (p *GoPackagesParser) ParseExcept(ctx context.Context, rootDir string, cached func(pkg *ParsedPackage) bool) ([]*ParsedPackage, error) {
+	cfg := createPackageConfig(ctx, rootDir)
+	cfg.Mode = packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedForTest
+	meta, err := packages.Load(cfg, "./...")
 	if err != nil {
 		return nil, fmt.Errorf("packages.Load: %w", err)
 	}
 
-	return deduplicateAndConvertPackages(pkgs), nil
+	parse := make(map[string]bool) // package directory -> needs type-checking
+	for _, pkg := range meta {
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+		dir := filepath.Dir(pkg.GoFiles[0])
+		if rel, err := filepath.Rel(rootDir, dir); err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if !parse[dir] {
+			parse[dir] = len(pkg.Errors) > 0 || !cached(convertToParsedPackage(pkg))
+		}
+	}
+	var patterns []string
+	for dir, needed := range parse {
+		if needed {
+			rel, _ := filepath.Rel(rootDir, dir)
+			patterns = append(patterns, "./"+filepath.ToSlash(rel))
+		}
+	}
+	sort.Strings(patterns)
+
+	var parsed []*ParsedPackage
+	if len(patterns) > 0 {
+		if parsed, err = p.ParsePatterns(ctx, rootDir, patterns...); err != nil {
+			return nil, err
+		}
+	}
+	// Keep the order of a full load, with parsed packages in place of their
+	// metadata.
+	byID := make(map[string]*ParsedPackage, len(parsed))
+	for _, pkg := range parsed {
+		byID[pkg.ID] = pkg
+	}
+	seen := make(map[string]*ParsedPackage)
+	var result []*ParsedPackage
+	for _, pkg := range meta {
+		pp, ok := byID[pkg.ID]
+		if !ok {
+			if len(pkg.GoFiles) > 0 && parse[filepath.Dir(pkg.GoFiles[0])] {
+				continue // failed to load with syntax; reported by ParsePatterns
+			}
+			pp = convertToParsedPackage(pkg)
+		}
+		delete(byID, pkg.ID)
+		addPackageToResult(pp, &result, seen)
+	}
+	for _, pkg := range parsed {
+		if _, ok := byID[pkg.ID]; ok {
+			addPackageToResult(pkg, &result, seen)
+		}
+	}
+	return result, nil
 }
 
 // createPackageConfig creates a packages.Config for loading Go packages.
-func createPackageConfig(rootDir string) *packages.Config {
+func createPackageConfig(ctx context.Context, rootDir string) *packages.Config {
 	return &packages.Config{
+		Context: ctx,
 		Mode: packages.NeedName |
 			packages.NeedFiles |
 			packages.NeedImports |
@@ -60,12 +146,21 @@ func createPackageConfig(rootDir string) *packages.Config {
 }
 
 // deduplicateAndConvertPackages converts and deduplicates loaded packages.
-func deduplicateAndConvertPackages(pkgs []*packages.Package) []*ParsedPackage {
+func deduplicateAndConvertPackages(ctx context.Context, rootDir string, pkgs []*packages.Package) []*ParsedPackage {
 	seen := make(map[string]*ParsedPackage)
+	reported := make(map[arstypes.Diagnostic]bool)
 	var result []*ParsedPackage
 
 	for _, pkg := range pkgs {
-		if !isValidPackage(pkg) {
+		valid := isValidPackage(pkg)
+		for _, d := range packageDiagnostics(rootDir, pkg, valid) {
+			// Test variants repeat the errors of the package they extend.
+			if !reported[d] {
+				reported[d] = true
+				diagnostics.Report(ctx, d)
+			}
+		}
+		if !valid {
 			continue
 		}
 
@@ -76,12 +171,9 @@ func deduplicateAndConvertPackages(pkgs []*packages.Package) []*ParsedPackage {
 	return result
 }
 
-// isValidPackage checks if a package has valid data or logs errors.
+// isValidPackage checks if a package has enough data to analyze.
 func isValidPackage(pkg *packages.Package) bool {
 	if len(pkg.Errors) > 0 {
-		for _, e := range pkg.Errors {
-			log.Printf("warning: package %s: %s", pkg.PkgPath, e)
-		}
 		// Still include if we got useful data (partial results)
 		if pkg.Types == nil || len(pkg.Syntax) == 0 {
 			return false
@@ -90,6 +182,50 @@ func isValidPackage(pkg *packages.Package) bool {
 	return true
 }
 
+// packageDiagnostics converts a package's load and type errors into warning
+// diagnostics. Errors of a package that was left out say so, since its code
+// is missing from every Go metric.
+func packageDiagnostics(rootDir string, pkg *packages.Package, included bool) []arstypes.Diagnostic {
+	var diags []arstypes.Diagnostic
+	for _, e := range pkg.Errors {
+		msg := fmt.Sprintf("package %s: %s", pkg.PkgPath, strings.TrimSpace(e.Msg))
+		if !included {
+			msg += " (package skipped)"
+		}
+		diags = append(diags, arstypes.Diagnostic{
+			Severity: arstypes.SeverityWarning,
+			Analyzer: "parser",
+			File:     positionFile(rootDir, e.Pos),
+			Message:  msg,
+		})
+	}
+	return diags
+}
+
+// positionFile extracts the file of a "file:line:col" position, relative to
+// rootDir where possible. It returns "" for positions without a file.
+func positionFile(rootDir, pos string) string {
+	file := pos
+	// Strip up to two trailing numeric ":line" and ":col" parts.
+	for range 2 {
+		i := strings.LastIndexByte(file, ':')
+		if i < 0 {
+			break
+		}
+		if _, err := strconv.Atoi(file[i+1:]); err != nil {
+			break
+		}
+		file = file[:i]
+	}
+	if file == "" || file == "-" {
+		return ""
+	}
+	if rel, err := filepath.Rel(rootDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	return filepath.ToSlash(file)
+}
+
 // convertToParsedPackage converts a packages.Package to ParsedPackage.
 func convertToParsedPackage(pkg *packages.Package) *ParsedPackage {
 	return &ParsedPackage{
@@ -1,9 +1,15 @@
 package parser
 
 import (
+	"context"
+	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
+	arstypes "github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
 // repoRoot returns the absolute path to the repository root.
@@ -25,7 +31,7 @@ func TestParseReturnsPackages(t *testing.T) {
 	root := repoRoot(t)
 	p := &GoPackagesParser{}
 
-	pkgs, err := p.Parse(root)
+	pkgs, err := p.Parse(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Parse() error: %v", err)
 	}
@@ -45,7 +51,7 @@ func TestParsedPackageHasSyntax(t *testing.T) {
 	root := repoRoot(t)
 	p := &GoPackagesParser{}
 
-	pkgs, err := p.Parse(root)
+	pkgs, err := p.Parse(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Parse() error: %v", err)
 	}
@@ -67,7 +73,7 @@ func TestParsedPackageHasFset(t *testing.T) {
 	root := repoRoot(t)
 	p := &GoPackagesParser{}
 
-	pkgs, err := p.Parse(root)
+	pkgs, err := p.Parse(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Parse() error: %v", err)
 	}
@@ -83,7 +89,7 @@ func TestParsedPackageHasTypeInfo(t *testing.T) {
 	root := repoRoot(t)
 	p := &GoPackagesParser{}
 
-	pkgs, err := p.Parse(root)
+	pkgs, err := p.Parse(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Parse() error: %v", err)
 	}
@@ -105,7 +111,7 @@ func TestTestPackagesIdentified(t *testing.T) {
 	root := repoRoot(t)
 	p := &GoPackagesParser{}
 
-	pkgs, err := p.Parse(root)
+	pkgs, err := p.Parse(context.Background(), root)
 	if err != nil {
 		t.Fatalf("Parse() error: %v", err)
 	}
@@ -122,3 +128,117 @@ func TestTestPackagesIdentified(t *testing.T) {
 		t.Error("no test packages found (ForTest field not set on any package)")
 	}
 }
+
+func TestParseReportsTypeErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.com/broken\n\ngo 1.21\n",
+		"main.go": "package main\n\nfunc main() { undefinedFunc() }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var diags []arstypes.Diagnostic
+	ctx := diagnostics.WithRecorder(context.Background(), func(d arstypes.Diagnostic) { diags = append(diags, d) })
+
+	p := &GoPackagesParser{}
+	if _, err := p.Parse(ctx, dir); err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics, want 1: %+v", len(diags), diags)
+	}
+	d := diags[0]
+	if d.Severity != arstypes.SeverityWarning || d.Analyzer != "parser" || d.File != "main.go" {
+		t.Errorf("diagnostic = %+v, want parser warning for main.go", d)
+	}
+	if !strings.Contains(d.Message, "undefinedFunc") {
+		t.Errorf("message = %q, want the type error", d.Message)
+	}
+}
+
+func TestParseExcept(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":      "module example.com/mod\n\ngo 1.21\n",
+		"a/a.go":      "package a\n\nfunc A() int { return 1 }\n",
+		"a/a_test.go": "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) { A() }\n",
+		"b/b.go":      "package b\n\nimport \"example.com/mod/a\"\n\nfunc B() int { return a.A() }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &GoPackagesParser{}
+	var asked []string
+	pkgs, err := p.ParseExcept(context.Background(), dir, func(pkg *ParsedPackage) bool {
+		asked = append(asked, pkg.ID)
+		return pkg.PkgPath == "example.com/mod/a"
+	})
+	if err != nil {
+		t.Fatalf("ParseExcept() error: %v", err)
+	}
+	if len(asked) == 0 {
+		t.Fatal("cached was never called")
+	}
+
+	byID := make(map[string]*ParsedPackage)
+	for _, pkg := range pkgs {
+		byID[pkg.ID] = pkg
+	}
+	a, b := byID["example.com/mod/a"], byID["example.com/mod/b"]
+	if a == nil || b == nil {
+		t.Fatalf("packages = %v, want a and b", asked)
+	}
+	if a.Syntax != nil || a.TypesInfo != nil || len(a.GoFiles) != 1 {
+		t.Errorf("cached package a: syntax %d, files %v, want no syntax and its file", len(a.Syntax), a.GoFiles)
+	}
+	if aTest := byID["example.com/mod/a [example.com/mod/a.test]"]; aTest == nil || aTest.Syntax != nil || len(aTest.GoFiles) != 2 {
+		t.Errorf("test variant of a = %+v, want its files without syntax", aTest)
+	}
+	if len(b.Syntax) != 1 || b.TypesInfo == nil {
+		t.Errorf("package b: syntax %d, want type-checked", len(b.Syntax))
+	}
+	if _, ok := b.Imports["example.com/mod/a"]; !ok {
+		t.Errorf("b imports = %v, want example.com/mod/a", b.Imports)
+	}
+
+	all, err := p.ParseExcept(context.Background(), dir, func(*ParsedPackage) bool { return true })
+	if err != nil {
+		t.Fatalf("ParseExcept() error: %v", err)
+	}
+	for _, pkg := range all {
+		if pkg.Syntax != nil {
+			t.Errorf("package %s parsed although everything is cached", pkg.ID)
+		}
+	}
+}
+
+func TestPositionFile(t *testing.T) {
+	root := filepath.FromSlash("/src/proj")
+	tests := []struct {
+		pos  string
+		want string
+	}{
+		{filepath.FromSlash("/src/proj/pkg/a.go") + ":3:7", "pkg/a.go"},
+		{filepath.FromSlash("/src/proj/a.go") + ":3", "a.go"},
+		{filepath.FromSlash("/elsewhere/b.go") + ":1:1", "/elsewhere/b.go"},
+		{"", ""},
+		{"-", ""},
+	}
+	for _, tt := range tests {
+		if got := positionFile(root, tt.pos); got != tt.want {
+			t.Errorf("positionFile(%q) = %q, want %q", tt.pos, got, tt.want)
+		}
+	}
+}
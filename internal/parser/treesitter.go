@@ -6,6 +6,7 @@
 package parser
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -37,6 +38,7 @@ type TreeSitterParser struct {
 	pythonParser *tree_sitter.Parser
 	tsParser     *tree_sitter.Parser
 	tsxParser    *tree_sitter.Parser
+	cache        map[treeCacheKey]*treeCacheEntry // nil unless EnableTreeCache was called
 }
 
 // NewTreeSitterParser creates parsers for Python, TypeScript, and TSX.
@@ -75,6 +77,9 @@ func NewTreeSitterParser() (*TreeSitterParser, error) {
 
 // Close releases all parser resources. Must be called when done.
 func (p *TreeSitterParser) Close() {
+	p.mu.Lock()
+	p.closeTreeCache()
+	p.mu.Unlock()
 	if p.pythonParser != nil {
 		p.pythonParser.Close()
 	}
@@ -109,11 +114,22 @@ func (p *TreeSitterParser) ParseFile(lang types.Language, ext string, content []
 		return nil, fmt.Errorf("unsupported language for Tree-sitter: %s", lang)
 	}
 
+	var key treeCacheKey
+	if p.cache != nil {
+		key = treeCacheKey{lang: lang, tsx: parser == p.tsxParser, sum: sha256.Sum256(content)}
+		if tree := p.cachedTree(key); tree != nil {
+			return tree, nil
+		}
+	}
+
 	tree := parser.Parse(content, nil)
 	if tree == nil {
 		return nil, fmt.Errorf("tree-sitter parse returned nil")
 	}
 
+	if p.cache != nil {
+		return p.storeTree(key, tree), nil
+	}
 	return tree, nil
 }
 
@@ -157,6 +173,31 @@ func (p *TreeSitterParser) ParseTargetFiles(target *types.AnalysisTarget) ([]*Pa
 	return results, nil
 }
 
+// ParseSource parses a single source file of lang from content, which callers
+// usually obtain with SourceContent. The caller must close the returned tree.
+func (p *TreeSitterParser) ParseSource(lang types.Language, sf types.SourceFile, content []byte) (*ParsedTreeSitterFile, error) {
+	ext := strings.ToLower(filepath.Ext(sf.Path))
+	tree, err := p.ParseFile(lang, ext, content)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", sf.RelPath, err)
+	}
+	return &ParsedTreeSitterFile{
+		Path:     sf.Path,
+		RelPath:  sf.RelPath,
+		Tree:     tree,
+		Content:  content,
+		Language: lang,
+	}, nil
+}
+
+// SourceContent returns sf's content, reading the file if it was not preloaded.
+func SourceContent(sf types.SourceFile) ([]byte, error) {
+	if len(sf.Content) > 0 {
+		return sf.Content, nil
+	}
+	return os.ReadFile(sf.Path)
+}
+
 // CloseAll closes all trees in a slice of ParsedTreeSitterFile.
 // Safe to call with nil or empty slice.
 func CloseAll(files []*ParsedTreeSitterFile) {
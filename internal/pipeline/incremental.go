@@ -0,0 +1,293 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer"
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// staticCategories lists the categories Incremental runs, in report order.
+// C7 needs an LLM and minutes per run, so it is never part of incremental analysis.
+var staticCategories = []string{"C1", "C2", "C3", "C4", "C5", "C6"}
+
+// Categories re-run for each kind of changed file.
+var (
+	codeCategories     = []string{"C1", "C2", "C3", "C4", "C6"}
+	testCategories     = []string{"C4", "C6"}
+	docCategories      = []string{"C4"}
+	coverageCategories = []string{"C6"}
+	gitCategories      = []string{"C5"}
+)
+
+// manifestFiles change how the project is parsed, so they trigger a full re-parse.
+var manifestFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "go.work": true,
+	"package.json": true, "tsconfig.json": true,
+	"pyproject.toml": true, "setup.py": true, "requirements.txt": true,
+}
+
+// coverageFiles are the coverage reports C6 parses.
+var coverageFiles = map[string]bool{
+	"cover.out": true, "coverage.out": true, "lcov.info": true, "coverage.lcov": true,
+	"cobertura.xml": true, "coverage.xml": true,
+}
+
+// docExts are file extensions C4 reads as documentation or diagrams.
+var docExts = map[string]bool{
+	".md": true, ".rst": true, ".adoc": true, ".txt": true,
+	".mmd": true, ".mermaid": true, ".puml": true, ".plantuml": true, ".drawio": true,
+}
+
+// AffectedCategories returns the categories whose results can change when the
+// file at relPath (relative to the project root) changes. It returns nil for
+// files no static analyzer reads.
+func AffectedCategories(relPath string) []string {
+	rel := filepath.ToSlash(relPath)
+	base := filepath.Base(rel)
+
+	switch {
+	case rel == ".git/logs/HEAD" || rel == ".git/HEAD":
+		return gitCategories
+	case strings.HasPrefix(rel, ".git/"):
+		return nil
+	case manifestFiles[base]:
+		return codeCategories
+	case coverageFiles[base]:
+		return coverageCategories
+	}
+
+	if lang := discovery.LanguageForFile(base); lang != "" {
+		if discovery.ClassifyFile(base, lang) == types.ClassTest {
+			return testCategories
+		}
+		return codeCategories
+	}
+	if docExts[strings.ToLower(filepath.Ext(base))] || strings.HasPrefix(rel, "docs/") || strings.HasPrefix(rel, "examples/") {
+		return docCategories
+	}
+	return nil
+}
+
+// Incremental keeps parsed Go packages, Tree-sitter trees and per-category results
+// in memory so that a changed file only re-runs the analyzers it can affect.
+// It runs the static categories (C1-C6) only and backs `ars watch`.
+type Incremental struct {
+	dir       string
+	scorer    *scoring.Scorer
+	tsParser  *parser.TreeSitterParser
+	goCache   *parser.GoPackageCache
+	analyzers map[string]analyzerIface
+	results   map[string]*types.AnalysisResult
+	scan      *types.ScanResult
+	hasGo     bool
+	walker    *discovery.Walker
+}
+
+// NewIncremental creates an Incremental analyzer for dir. If cfg is nil,
+// DefaultConfig is used. Call Close to release parser resources.
+func NewIncremental(dir string, cfg *scoring.ScoringConfig) *Incremental {
+	if cfg == nil {
+		cfg = scoring.DefaultConfig()
+	}
+
+	// Tree-sitter is optional, as in New: without it only Go is analyzed.
+	tsParser, err := parser.NewTreeSitterParser()
+	if err != nil {
+		tsParser = nil
+	} else {
+		tsParser.EnableTreeCache()
+	}
+
+	return &Incremental{
+		dir:      dir,
+		scorer:   &scoring.Scorer{Config: cfg},
+		tsParser: tsParser,
+		goCache:  parser.NewGoPackageCache(dir),
+		analyzers: map[string]analyzerIface{
+			"C1": analyzer.NewC1Analyzer(tsParser),
+			"C2": analyzer.NewC2Analyzer(tsParser),
+			"C3": analyzer.NewC3Analyzer(tsParser),
+			"C4": analyzer.NewC4Analyzer(tsParser),
+			"C5": analyzer.NewC5Analyzer(),
+			"C6": analyzer.NewC6Analyzer(tsParser),
+		},
+		results: make(map[string]*types.AnalysisResult),
+		walker:  discovery.NewWalker(),
+	}
+}
+
+// SetPathRules sets the include and exclude globs of file discovery, from
+// .arsrc.yml.
+func (inc *Incremental) SetPathRules(rules discovery.PathRules) {
+	inc.walker.SetPathRules(rules)
+}
+
+// SetGeneratedRules sets the generated-file globs and markers of file
+// discovery, from .arsrc.yml.
+func (inc *Incremental) SetGeneratedRules(rules discovery.GeneratedRules) {
+	inc.walker.SetGeneratedRules(rules)
+}
+
+// Close releases the Tree-sitter parser and its cached trees.
+func (inc *Incremental) Close() {
+	if inc.tsParser != nil {
+		inc.tsParser.Close()
+	}
+}
+
+// Analyze parses the whole project and runs every static analyzer.
+// Analyzer errors are returned as warnings alongside the score.
+func (inc *Incremental) Analyze(ctx context.Context) (*types.ScoredResult, []string, error) {
+	return inc.run(ctx, staticCategories, true)
+}
+
+// Update re-analyzes after the given absolute paths changed. Only the Go packages
+// containing changed files are re-parsed, only changed Tree-sitter files are
+// re-parsed, and only the affected categories are re-run; the rest keep their
+// previous results. It returns the new score, the categories that were re-run
+// (nil if none were affected) and any analyzer warnings. A run cut short by ctx
+// returns ctx.Err() and keeps the previous results.
+func (inc *Incremental) Update(ctx context.Context, paths []string) (*types.ScoredResult, []string, []string, error) {
+	affected := make(map[string]bool)
+	rediscover := false
+	for _, p := range paths {
+		rel, err := filepath.Rel(inc.dir, p)
+		if err != nil {
+			continue
+		}
+		cats := AffectedCategories(rel)
+		for _, c := range cats {
+			affected[c] = true
+		}
+		if len(cats) > 0 && !strings.HasPrefix(filepath.ToSlash(rel), ".git/") {
+			rediscover = true
+		}
+		inc.goCache.Invalidate(p)
+	}
+
+	var categories []string
+	for _, c := range staticCategories {
+		if affected[c] {
+			categories = append(categories, c)
+		}
+	}
+	if len(categories) == 0 {
+		return nil, nil, nil, nil
+	}
+
+	scored, warnings, err := inc.run(ctx, categories, rediscover)
+	return scored, categories, warnings, err
+}
+
+// run refreshes discovery (if requested) and Go packages, then runs the given
+// categories' analyzers concurrently and rescores all cached results.
+func (inc *Incremental) run(ctx context.Context, categories []string, rediscover bool) (*types.ScoredResult, []string, error) {
+	if rediscover || inc.scan == nil {
+		scan, err := inc.walker.Discover(ctx, inc.dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		inc.scan = scan
+		inc.hasGo = false
+		for _, l := range discovery.DetectProjectLanguages(inc.dir) {
+			if l == types.LangGo {
+				inc.hasGo = true
+			}
+		}
+	}
+
+	var warnings []string
+	var pkgs []*parser.ParsedPackage
+	if inc.hasGo {
+		var err error
+		pkgs, err = inc.goCache.Packages(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Go parsing error: %v", err))
+		}
+		pkgs = withoutDirs(pkgs, inc.walker.ExcludesDir)
+	}
+
+	var targets []*types.AnalysisTarget
+	if len(pkgs) > 0 {
+		targets = append(targets, buildGoTargets(inc.dir, pkgs, inc.scan)...)
+	}
+	targets = append(targets, buildNonGoTargets(inc.dir, inc.scan)...)
+	if len(targets) == 0 {
+		return nil, warnings, fmt.Errorf("no analyzable source files found in %s", inc.dir)
+	}
+
+	var mu sync.Mutex
+	g := new(errgroup.Group)
+	for _, cat := range categories {
+		a := inc.analyzers[cat]
+		if ga, ok := a.(goAwareAnalyzer); ok && len(pkgs) > 0 {
+			ga.SetGoPackages(pkgs)
+		}
+		g.Go(func() error {
+			ar, err := a.Analyze(ctx, targets)
+			mu.Lock()
+			defer mu.Unlock()
+			if ctx.Err() != nil {
+				return nil // a partial result must not replace the previous one
+			}
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s analyzer error: %v", a.Name(), err))
+				return nil // keep the previous result for this category
+			}
+			inc.results[cat] = ar
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(warnings)
+
+	// Only a run of every code analyzer requests every tree, so only then can
+	// unrequested trees be assumed stale.
+	if inc.tsParser != nil && containsAll(categories, codeCategories) {
+		inc.tsParser.PruneTreeCache()
+	}
+
+	results := make([]*types.AnalysisResult, 0, len(inc.results))
+	for _, cat := range staticCategories {
+		if ar, ok := inc.results[cat]; ok {
+			results = append(results, ar)
+		}
+	}
+	scored, err := inc.scorer.Score(results)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("scoring: %w", err)
+	}
+	scored.ProjectName = filepath.Base(inc.dir)
+	return scored, warnings, nil
+}
+
+// containsAll reports whether every element of want is in have.
+func containsAll(have, want []string) bool {
+	set := make(map[string]bool, len(have))
+	for _, h := range have {
+		set[h] = true
+	}
+	for _, w := range want {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestAffectedCategories(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"internal/foo.go", codeCategories},
+		{"internal/foo_test.go", testCategories},
+		{"app/models.py", codeCategories},
+		{"tests/test_models.py", testCategories},
+		{"src/index.ts", codeCategories},
+		{"src/index.test.ts", testCategories},
+		{"go.mod", codeCategories},
+		{"package.json", codeCategories},
+		{"README.md", docCategories},
+		{"docs/architecture.png", docCategories},
+		{"cover.out", coverageCategories},
+		{".git/logs/HEAD", gitCategories},
+		{".git/index", nil},
+		{"Makefile", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := AffectedCategories(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AffectedCategories(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func writeProjectFile(t *testing.T, root, name, content string) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestIncremental_UpdateRerunsAffectedCategories(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "go.mod", "module example.com/inc\n\ngo 1.21\n")
+	src := writeProjectFile(t, root, "lib/lib.go", "package lib\n\nfunc Add(a, b int) int { return a + b }\n")
+	readme := writeProjectFile(t, root, "README.md", "# inc\n")
+
+	inc := NewIncremental(root, nil)
+	defer inc.Close()
+
+	start, _, err := inc.Analyze(context.Background())
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if len(start.Categories) == 0 {
+		t.Fatal("expected scored categories after initial analysis")
+	}
+
+	// Unrelated file: nothing re-runs.
+	other := writeProjectFile(t, root, "Makefile", "all:\n")
+	scored, rerun, _, err := inc.Update(context.Background(), []string{other})
+	if err != nil || scored != nil || rerun != nil {
+		t.Errorf("Update(Makefile) = (%v, %v, %v), want no re-run", scored, rerun, err)
+	}
+
+	// Documentation change: only C4.
+	writeProjectFile(t, root, "README.md", "# inc\n\n"+strings.Repeat("Adds numbers for the inc example project. ", 40)+"\n")
+	scored, rerun, _, err = inc.Update(context.Background(), []string{readme})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rerun, docCategories) {
+		t.Errorf("README change re-ran %v, want %v", rerun, docCategories)
+	}
+	if scored == nil || len(scored.Categories) != len(start.Categories) {
+		t.Fatal("partial re-run should still score every category")
+	}
+
+	// Source change: code categories, with the edited package re-parsed.
+	writeProjectFile(t, root, "lib/lib.go", "package lib\n\n// Add adds.\nfunc Add(a, b int) int { return a + b }\n\n// Sub subtracts.\nfunc Sub(a, b int) int { return a - b }\n")
+	_, rerun, _, err = inc.Update(context.Background(), []string{src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rerun, codeCategories) {
+		t.Errorf("source change re-ran %v, want %v", rerun, codeCategories)
+	}
+	c1 := inc.results["C1"]
+	if c1 == nil {
+		t.Fatal("missing C1 result")
+	}
+	found := false
+	for _, fn := range c1.Metrics["c1"].(*types.C1Metrics).Functions {
+		if fn.Name == "Sub" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("C1 should see the newly added function after the package reload")
+	}
+}
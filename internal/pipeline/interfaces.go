@@ -1,6 +1,10 @@
 package pipeline
 
 import (
+	"context"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
@@ -8,15 +12,23 @@ import (
 // parseProvider loads and parses Go packages from a module directory.
 // Kept for Go parser compatibility; will be deprecated when multi-parser replaces it.
 type parseProvider interface {
-	Parse(rootDir string) ([]*parser.ParsedPackage, error)
+	Parse(ctx context.Context, rootDir string) ([]*parser.ParsedPackage, error)
+}
+
+// partialParseProvider is a parseProvider that can skip type-checking the
+// packages whose results are cached (see parser.GoPackagesParser.ParseExcept).
+type partialParseProvider interface {
+	parseProvider
+	ParseExcept(ctx context.Context, rootDir string, cached func(pkg *parser.ParsedPackage) bool) ([]*parser.ParsedPackage, error)
 }
 
 // analyzerIface runs a specific analysis pass over analysis targets.
 // Targets are language-agnostic; analyzers that need Go-specific data
-// should also implement goAwareAnalyzer.
+// should also implement goAwareAnalyzer. Analyze should return promptly once
+// ctx is done, either with ctx.Err() or with the results gathered so far.
 type analyzerIface interface {
 	Name() string
-	Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error)
+	Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error)
 }
 
 // goAwareAnalyzer is an analyzerIface that also needs access to Go-specific
@@ -26,3 +38,34 @@ type goAwareAnalyzer interface {
 	analyzerIface
 	SetGoPackages(pkgs []*parser.ParsedPackage)
 }
+
+// cacheAwareAnalyzer is an analyzerIface that can serve per-file results from
+// the on-disk results cache. The pipeline calls SetCache before Analyze.
+type cacheAwareAnalyzer interface {
+	analyzerIface
+	SetCache(store *cache.Store)
+}
+
+// goCacheAwareAnalyzer is a goAwareAnalyzer that can analyze Go packages
+// loaded without syntax and type information from its cached results.
+// GoResultsCached reports whether it has every result it derives from pkg
+// cached, given the content of pkg's files. The pipeline skips type-checking
+// a package only if every goAwareAnalyzer implements it and reports true.
+type goCacheAwareAnalyzer interface {
+	goAwareAnalyzer
+	GoResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool
+}
+
+// llmCacheAwareAnalyzer is an analyzerIface whose LLM calls can be served from
+// the LLM response cache. The pipeline calls SetLLMCache before Analyze.
+type llmCacheAwareAnalyzer interface {
+	analyzerIface
+	SetLLMCache(store *cache.LLMStore)
+}
+
+// budgetAwareAnalyzer is an analyzerIface whose LLM calls count against the
+// shared LLM budget. The pipeline calls SetBudget before Analyze.
+type budgetAwareAnalyzer interface {
+	analyzerIface
+	SetBudget(budget *agent.Budget)
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// repoCategories lists the categories analyzed once for a whole monorepo
+// rather than per module: C4's README, CHANGELOG, CONTRIBUTING and docs/ live
+// at the repository root (and its LLM evaluation is paid for once), C5 reads
+// the repository's git history and C7's agents work in a checkout of the
+// whole repository.
+var repoCategories = []string{"C4", "C5", "C7"}
+
+// repoAnalyzers returns the analyzers of the repository-level categories.
+func (p *Pipeline) repoAnalyzers() []analyzerIface {
+	var as []analyzerIface
+	for _, a := range p.analyzers {
+		if slices.Contains(repoCategories, analyzerCategory(a, nil)) {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// moduleAnalyzers returns the analyzers run once per monorepo module.
+func (p *Pipeline) moduleAnalyzers() []analyzerIface {
+	var as []analyzerIface
+	for _, a := range p.analyzers {
+		if !slices.Contains(repoCategories, analyzerCategory(a, nil)) {
+			as = append(as, a)
+		}
+	}
+	return as
+}
+
+// analyzeModules analyzes each module of a monorepo as its own project, with
+// its own root, module path and import graph, and records the module scores
+// for scoreAndRecommend to aggregate. It returns the discovery result of the
+// whole repository together with the targets and Go packages of all modules,
+// rooted at dir, for the repository-level analyzers.
+func (p *Pipeline) analyzeModules(ctx context.Context, dir string, modules []types.Module) (*types.ScanResult, []*types.AnalysisTarget, []*parser.ParsedPackage, error) {
+	p.startStage("discover", "Scanning files...")
+	repoWalker := p.newWalker()
+	scan, err := repoWalker.Discover(ctx, dir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	analyzers := p.moduleAnalyzers()
+	repoTargets := make(map[types.Language]*types.AnalysisTarget)
+	var allPkgs []*parser.ParsedPackage
+	var interrupted []string
+	for i, m := range modules {
+		if ctx.Err() != nil {
+			break
+		}
+		p.module = m.Path
+		targets, pkgs := p.moduleTargets(ctx, dir, m, modules, repoWalker, scan)
+		if len(targets) == 0 {
+			p.module = ""
+			continue
+		}
+
+		p.injectGoPackages(pkgs)
+		p.startStage("analyze", fmt.Sprintf("Analyzing %s (%d/%d)...", m.Name, i+1, len(modules)))
+		results, cut := p.runAnalyzers(ctx, analyzers, targets)
+		p.module = ""
+		interrupted = mergeInterrupted(interrupted, cut)
+
+		ms := types.ModuleScore{Module: m, LOC: sourceLines(targets), Results: results}
+		if scored, err := p.scorer.Score(results); err == nil {
+			ms.Categories = scored.Categories
+			ms.Composite, ms.Tier = scored.Composite, scored.Tier
+		}
+		p.modules = append(p.modules, ms)
+		addRepoTargets(repoTargets, dir, m, targets)
+		allPkgs = append(allPkgs, pkgs...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(p.modules) == 0 {
+		return nil, nil, nil, fmt.Errorf("no analyzable source files found in any of the %d modules of %s", len(modules), dir)
+	}
+	p.interrupted = interrupted
+
+	var targets []*types.AnalysisTarget
+	p.langs = nil
+	for _, lang := range []types.Language{types.LangGo, types.LangPython, types.LangTypeScript} {
+		if t, ok := repoTargets[lang]; ok {
+			targets = append(targets, t)
+			p.langs = append(p.langs, lang)
+		}
+	}
+	return scan, targets, allPkgs, nil
+}
+
+// moduleTargets discovers and parses one module of the repository in dir and
+// returns its analysis targets, rooted at the module directory. Only files of
+// the module's language count; the directories of nested modules are left to
+// them. Go packages in directories the repository walk excluded are dropped,
+// and Go files it classified as generated, in repoScan, are marked as such.
+func (p *Pipeline) moduleTargets(ctx context.Context, dir string, m types.Module, modules []types.Module, repoWalker *discovery.Walker, repoScan *types.ScanResult) ([]*types.AnalysisTarget, []*parser.ParsedPackage) {
+	nested := discovery.NestedModuleDirs(m, modules)
+	if m.Language == types.LangGo {
+		p.startStage("parse", fmt.Sprintf("Parsing Go packages of %s...", m.Name))
+		pkgs, err := p.parseGo(ctx, m.Dir)
+		if err != nil && ctx.Err() == nil {
+			p.failf("parser", "Go parsing of module %s failed: %v", m.Name, err)
+		}
+		pkgs = withoutDirs(pkgs, func(pkgDir string) bool {
+			return underAny(pkgDir, nested) || repoWalker.ExcludesDir(pkgDir)
+		})
+		return buildGoTargets(m.Dir, pkgs, repoScan), pkgs
+	}
+
+	walker := p.newWalker()
+	rules := p.pathRules
+	rules.Dir = dir
+	walker.SetPathRules(rules)
+	walker.SkipDirs(nested...)
+	scan, err := walker.Discover(ctx, m.Dir)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.failf("discovery", "module %s: %v", m.Name, err)
+		}
+		return nil, nil
+	}
+	var targets []*types.AnalysisTarget
+	for _, t := range buildNonGoTargets(m.Dir, scan) {
+		if t.Language == m.Language {
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
+}
+
+// underAny reports whether dir is one of dirs or lies below one. Nested
+// module directories are dropped this way, since "./..." still matches them
+// in workspace mode (go.work).
+func underAny(dir string, dirs []string) bool {
+	for _, d := range dirs {
+		if rel, err := filepath.Rel(d, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// addRepoTargets adds the files of a module's targets to the repository-level
+// targets, with paths relative to the repository root dir.
+func addRepoTargets(repo map[types.Language]*types.AnalysisTarget, dir string, m types.Module, targets []*types.AnalysisTarget) {
+	for _, t := range targets {
+		rt, ok := repo[t.Language]
+		if !ok {
+			rt = &types.AnalysisTarget{Language: t.Language, RootDir: dir}
+			repo[t.Language] = rt
+		}
+		for _, f := range t.Files {
+			f.RelPath = filepath.Join(filepath.FromSlash(m.Path), f.RelPath)
+			rt.Files = append(rt.Files, f)
+		}
+	}
+}
+
+// sourceLines counts the lines of the source files of targets, a module's
+// weight in the repository totals.
+func sourceLines(targets []*types.AnalysisTarget) int {
+	n := 0
+	for _, t := range targets {
+		for _, f := range t.Files {
+			if f.Class == types.ClassSource {
+				n += f.Lines
+			}
+		}
+	}
+	return n
+}
+
+// mergeInterrupted returns the sorted union of two lists of categories.
+func mergeInterrupted(a, b []string) []string {
+	for _, c := range b {
+		if !slices.Contains(a, c) {
+			a = append(a, c)
+		}
+	}
+	slices.Sort(a)
+	return a
+}
@@ -1,24 +1,36 @@
 package pipeline
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent/metrics"
 	"github.com/ingo-eichhorst/agent-readyness/internal/analyzer"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/diagnostics"
 	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+	"github.com/ingo-eichhorst/agent-readyness/internal/events"
 	"github.com/ingo-eichhorst/agent-readyness/internal/output"
 	"github.com/ingo-eichhorst/agent-readyness/internal/parser"
 	"github.com/ingo-eichhorst/agent-readyness/internal/recommend"
 	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -28,6 +40,16 @@ const (
 	bytesPerKB       = 1024             // Bytes per kilobyte for file size display
 )
 
+// Exit codes of a scan cut short, following the shell conventions for SIGINT
+// and timeout(1).
+const (
+	exitInterrupted = 130
+	exitTimedOut    = 124
+)
+
+// exitStrict is Run's exit code when --strict is set and an analyzer failed.
+const exitStrict = 3
+
 // Pipeline orchestrates the scan workflow: discover -> parse -> analyze -> score -> output.
 type Pipeline struct {
 	verbose      bool
@@ -41,19 +63,51 @@ type Pipeline struct {
 	threshold    float64
 	jsonOutput   bool
 	onProgress   ProgressFunc
-	evaluator    *agent.Evaluator // CLI-based evaluator for LLM analysis
-	cliStatus    agent.CLIStatus  // cached CLI availability status
-	htmlOutput   string           // optional path for HTML report output
-	baselinePath string           // optional path to previous JSON for trend comparison
-	badgeOutput  bool             // generate shields.io badge markdown
-	debugC7      bool             // C7 debug mode enabled
-	debugWriter  io.Writer        // io.Discard (normal) or os.Stderr (debug)
-	debugDir     string           // directory for C7 response persistence and replay
-	langs        []types.Language // detected project languages
+	evaluator    *agent.Evaluator         // CLI-based evaluator for LLM analysis
+	cliStatus    agent.CLIStatus          // cached CLI availability status
+	htmlOutput   string                   // optional path for HTML report output
+	baselinePath string                   // optional path to previous JSON for trend comparison
+	badgeOutput  bool                     // generate shields.io badge markdown
+	debugC7      bool                     // C7 debug mode enabled
+	debugWriter  io.Writer                // io.Discard (normal) or os.Stderr (debug)
+	debugDir     string                   // directory for C7 response persistence and replay
+	langs        []types.Language         // detected project languages
+	budget       *agent.Budget            // LLM budget reported with the scores; nil for no limit
+	strict       bool                     // analyzer failures fail the run
+	pathRules    discovery.PathRules      // include and exclude globs of file discovery
+	genRules     discovery.GeneratedRules // generated-file globs and markers of file discovery
+	cache        *cache.Store             // per-file results cache; nil parses every Go package
+	warnMu       sync.Mutex
+	warnings     []string           // non-fatal problems of the current run
+	diagnostics  []types.Diagnostic // problems of the current run, guarded by warnMu
+	interrupted  []string           // categories cut short in the current run
+
+	events     func(types.Event) // progress event stream; nil for none
+	stage      string            // stage of the current run, for its end event
+	stageStart time.Time
+	runStart   time.Time
+	tokens     atomic.Int64 // LLM tokens of the current run
+
+	modules []types.ModuleScore // per-module scores of a monorepo run; nil otherwise
+	module  string              // path of the monorepo module being analyzed; "" at repository level
+}
+
+// Result is the outcome of Analyze: the discovered files, per-category analysis
+// results, scores and recommendations, plus any non-fatal warnings.
+type Result struct {
+	Scan            *types.ScanResult
+	Analyses        []*types.AnalysisResult
+	Scored          *types.ScoredResult // nil if scoring failed
+	Recommendations []recommend.Recommendation
+	Languages       []types.Language
+	Warnings        []string
+	Diagnostics     []types.Diagnostic // problems met during the scan, most severe first
+	Interrupted     []string           // categories cut short by cancellation; nil for a complete scan
 }
 
 // New creates a Pipeline with GoPackagesParser, all analyzers, and a scorer.
-// If cfg is nil, DefaultConfig is used. If onProgress is nil, a no-op is used.
+// Registered plugins (see pkg/plugin) run alongside the built-in analyzers and
+// their default scoring configuration is added to cfg. If cfg is nil, DefaultConfig is used. If onProgress is nil, a no-op is used.
 // The pipeline auto-creates a Tree-sitter parser for Python/TypeScript analysis.
 // CLI availability is detected at startup; if available, LLM features are auto-enabled.
 func New(w io.Writer, verbose bool, cfg *scoring.ScoringConfig, threshold float64, jsonOutput bool, onProgress ProgressFunc) *Pipeline {
@@ -76,7 +130,7 @@ func New(w io.Writer, verbose bool, cfg *scoring.ScoringConfig, threshold float6
 
 	c2Analyzer := analyzer.NewC2Analyzer(tsParser)
 	c4Analyzer := analyzer.NewC4Analyzer(tsParser)
-	c7Analyzer := analyzer.NewC7Analyzer()
+	c7Analyzer := analyzer.NewC7Analyzer(tsParser)
 
 	// Detect CLI availability and auto-enable LLM features
 	cliStatus := agent.GetCLIStatus()
@@ -87,6 +141,17 @@ func New(w io.Writer, verbose bool, cfg *scoring.ScoringConfig, threshold float6
 		c7Analyzer.SetEvaluator(evaluator) // Auto-enable C7 when CLI available
 	}
 
+	analyzers := []analyzerIface{
+		analyzer.NewC1Analyzer(tsParser),
+		c2Analyzer,
+		analyzer.NewC3Analyzer(tsParser),
+		c4Analyzer,
+		analyzer.NewC5Analyzer(), // No tsParser needed - git-based analysis
+		analyzer.NewC6Analyzer(tsParser),
+		c7Analyzer, // C7 runs but returns Available:false if LLM disabled
+	}
+	analyzers = append(analyzers, pluginAnalyzers(cfg, plugin.Registered())...)
+
 	return &Pipeline{
 		verbose:     verbose,
 		writer:      w,
@@ -95,19 +160,11 @@ func New(w io.Writer, verbose bool, cfg *scoring.ScoringConfig, threshold float6
 		onProgress:  onProgress,
 		debugWriter: io.Discard,
 		parser:      &parser.GoPackagesParser{},
-		analyzers: []analyzerIface{
-			analyzer.NewC1Analyzer(tsParser),
-			c2Analyzer,
-			analyzer.NewC3Analyzer(tsParser),
-			c4Analyzer,
-			analyzer.NewC5Analyzer(), // No tsParser needed - git-based analysis
-			analyzer.NewC6Analyzer(tsParser),
-			c7Analyzer, // C7 runs but returns Available:false if LLM disabled
-		},
-		c7Analyzer: c7Analyzer,
-		scorer:     &scoring.Scorer{Config: cfg},
-		evaluator:  evaluator,
-		cliStatus:  cliStatus,
+		analyzers:   analyzers,
+		c7Analyzer:  c7Analyzer,
+		scorer:      &scoring.Scorer{Config: cfg},
+		evaluator:   evaluator,
+		cliStatus:   cliStatus,
 	}
 }
 
@@ -139,6 +196,47 @@ func (p *Pipeline) SetC7Enabled() {
 	}
 }
 
+// SetAgentBackend selects the coding agent C7 evaluates (see agent.NewBackend).
+// This enables C7 even when the Claude CLI is not installed.
+func (p *Pipeline) SetAgentBackend(b agent.Backend) {
+	if p.c7Analyzer != nil {
+		p.c7Analyzer.SetBackend(b)
+	}
+}
+
+// SetAgentLimits bounds the concurrency, start rate and retries of C7's agent
+// calls (see agent.Limits).
+func (p *Pipeline) SetAgentLimits(limits agent.Limits) {
+	if p.c7Analyzer != nil {
+		p.c7Analyzer.SetLimits(limits)
+	}
+}
+
+// SetC7MetricOptions configures C7's metrics, e.g. M1's run and sample counts.
+// User-defined tasks are added to the C7 scoring config with their weights.
+func (p *Pipeline) SetC7MetricOptions(opts metrics.Options) {
+	if p.c7Analyzer != nil {
+		p.c7Analyzer.SetMetricOptions(opts)
+	}
+	for _, t := range opts.Tasks {
+		p.scorer.Config.AddC7Metric(t.ID, t.EffectiveWeight())
+	}
+}
+
+// SetJudge selects the judge C4 uses for its LLM-based metrics (see agent.NewJudge).
+// This enables C4's LLM evaluation even when the Claude CLI is not installed.
+// C7 uses it for the rubrics of user-defined tasks and for judge scoring mode.
+func (p *Pipeline) SetJudge(j agent.Judge) {
+	for _, a := range p.analyzers {
+		if c4, ok := a.(*analyzer.C4Analyzer); ok {
+			c4.SetJudge(j)
+		}
+	}
+	if p.c7Analyzer != nil {
+		p.c7Analyzer.SetJudge(j)
+	}
+}
+
 // SetHTMLOutput configures HTML report generation.
 // If htmlPath is non-empty, an HTML report will be generated at that path.
 // If baselinePath is non-empty, the report will include trend comparison.
@@ -147,6 +245,40 @@ func (p *Pipeline) SetHTMLOutput(htmlPath, baselinePath string) {
 	p.baselinePath = baselinePath
 }
 
+// SetCache enables the per-file results cache for every analyzer that supports
+// it. Unchanged files are then neither re-parsed with Tree-sitter nor re-analyzed,
+// and Go packages whose results are all cached are not type-checked.
+func (p *Pipeline) SetCache(store *cache.Store) {
+	p.cache = store
+	for _, a := range p.analyzers {
+		if ca, ok := a.(cacheAwareAnalyzer); ok {
+			ca.SetCache(store)
+		}
+	}
+}
+
+// SetLLMCache enables the LLM response cache for every analyzer that calls an
+// LLM, so unchanged samples reuse earlier agent responses and judge scores.
+func (p *Pipeline) SetLLMCache(store *cache.LLMStore) {
+	for _, a := range p.analyzers {
+		if la, ok := a.(llmCacheAwareAnalyzer); ok {
+			la.SetLLMCache(store)
+		}
+	}
+}
+
+// SetLLMBudget limits the LLM calls of every analyzer that makes them with
+// one shared budget. The scored result then reports spend and any samples,
+// metrics or evaluations skipped to stay within it.
+func (p *Pipeline) SetLLMBudget(budget *agent.Budget) {
+	p.budget = budget
+	for _, a := range p.analyzers {
+		if ba, ok := a.(budgetAwareAnalyzer); ok {
+			ba.SetBudget(budget)
+		}
+	}
+}
+
 // SetBadgeOutput enables shields.io badge markdown generation in output.
 func (p *Pipeline) SetBadgeOutput(enabled bool) {
 	p.badgeOutput = enabled
@@ -164,6 +296,14 @@ func (p *Pipeline) SetC7Debug(enabled bool) {
 	}
 }
 
+// SetC7Progress sets where C7 draws its live progress display, which it only
+// does on a terminal. By default it is not drawn.
+func (p *Pipeline) SetC7Progress(w io.Writer) {
+	if p.c7Analyzer != nil {
+		p.c7Analyzer.SetProgressWriter(w)
+	}
+}
+
 // SetDebugDir configures the directory for C7 response persistence and replay.
 func (p *Pipeline) SetDebugDir(dir string) {
 	p.debugDir = dir
@@ -172,41 +312,289 @@ func (p *Pipeline) SetDebugDir(dir string) {
 	}
 }
 
-// Run executes the full pipeline on the given directory.
-func (p *Pipeline) Run(dir string) error {
-	result, targets, pkgs, err := p.discoverAndParse(dir)
-	if err != nil {
+// SetPlugins adds plugin categories to the scans of this pipeline only, on
+// top of the compiled-in plugins registered with pkg/plugin. It is used for
+// the external plugins of .arsrc.yml (see plugin.Check).
+func (p *Pipeline) SetPlugins(plugins []plugin.Plugin) {
+	p.analyzers = append(p.analyzers, pluginAnalyzers(p.scorer.Config, plugins)...)
+}
+
+// SetStrict makes Run fail with exit code 3 when any error diagnostic, such as
+// an analyzer failure, was reported. The report is still rendered.
+func (p *Pipeline) SetStrict(enabled bool) {
+	p.strict = enabled
+}
+
+// SetPathRules sets the include and exclude globs of file discovery, from
+// .arsrc.yml. Exclude globs also drop the Go packages of excluded directories.
+func (p *Pipeline) SetPathRules(rules discovery.PathRules) {
+	p.pathRules = rules
+}
+
+// SetGeneratedRules sets the generated-file globs and markers of file
+// discovery, from .arsrc.yml.
+func (p *Pipeline) SetGeneratedRules(rules discovery.GeneratedRules) {
+	p.genRules = rules
+}
+
+// newWalker returns a discovery walker applying the pipeline's path and
+// generated-file rules.
+func (p *Pipeline) newWalker() *discovery.Walker {
+	w := discovery.NewWalker()
+	w.SetPathRules(p.pathRules)
+	w.SetGeneratedRules(p.genRules)
+	return w
+}
+
+// SetEvents streams the progress of every run to emit: stage start and end,
+// per-analyzer timings, C7 metric and sample progress, LLM token counts,
+// diagnostics and, from Run and EmitSummary, a final summary. emit may be
+// called concurrently.
+func (p *Pipeline) SetEvents(emit func(types.Event)) {
+	p.events = emit
+}
+
+// Run executes the full pipeline on the given directory. If ctx is done
+// while the analyzers run, the partial report is still rendered and Run
+// returns an ExitError with code 130 (cancelled) or 124 (deadline exceeded).
+func (p *Pipeline) Run(ctx context.Context, dir string) error {
+	res, err := p.Analyze(ctx, dir)
+	if res == nil {
+		p.EmitSummary(nil, err)
 		return err
 	}
 
-	p.injectGoPackages(pkgs)
-	p.runAnalyzers(targets)
-	recs := p.scoreAndRecommend(dir)
+	if renderErr := p.render(res); renderErr != nil {
+		p.EmitSummary(res, renderErr)
+		return renderErr
+	}
+	p.EmitSummary(res, err)
 
-	if err := p.renderOutput(result, recs); err != nil {
-		return err
+	if err != nil {
+		return cutShortError(err, res.Interrupted)
 	}
 
-	if p.htmlOutput != "" && p.scored != nil {
-		if err := p.generateHTMLReport(recs); err != nil {
-			return fmt.Errorf("generate HTML report: %w", err)
+	if p.strict {
+		if n := types.CountSeverity(res.Diagnostics, types.SeverityError); n > 0 {
+			return &types.ExitError{
+				Code:    exitStrict,
+				Message: fmt.Sprintf("%d analyzer failure(s) with --strict", n),
+			}
 		}
 	}
 
-	if p.threshold > 0 && p.scored != nil && p.scored.Composite < p.threshold {
+	if p.threshold > 0 && res.Scored != nil && res.Scored.Composite < p.threshold {
 		return &types.ExitError{
 			Code:    2,
-			Message: fmt.Sprintf("Score %.1f is below threshold %.1f", p.scored.Composite, p.threshold),
+			Message: fmt.Sprintf("Score %.1f is below threshold %.1f", res.Scored.Composite, p.threshold),
 		}
 	}
 
 	return nil
 }
 
-func (p *Pipeline) discoverAndParse(dir string) (*types.ScanResult, []*types.AnalysisTarget, []*parser.ParsedPackage, error) {
-	p.onProgress("discover", "Scanning files...")
-	walker := discovery.NewWalker()
-	result, err := walker.Discover(dir)
+// Analyze discovers, parses, analyzes and scores dir without rendering any output.
+// Problems met along the way are collected in the Result's Diagnostics;
+// failures are also listed in Warnings. If ctx is done before the analyzers start, Analyze returns
+// ctx.Err(); if it is done while they run, it returns the partial Result, with
+// the categories cut short in Interrupted, together with ctx.Err().
+func (p *Pipeline) Analyze(ctx context.Context, dir string) (*Result, error) {
+	p.warnMu.Lock()
+	p.warnings = nil
+	p.diagnostics = nil
+	p.warnMu.Unlock()
+	p.scored = nil
+	p.interrupted = nil
+	p.runStart = time.Now()
+	p.tokens.Store(0)
+	p.modules = nil
+	ctx = diagnostics.WithRecorder(ctx, p.diagnose)
+	if p.events != nil {
+		ctx = events.WithEmitter(ctx, p.events)
+	}
+
+	analyzers := p.analyzers
+	var result *types.ScanResult
+	var targets []*types.AnalysisTarget
+	var pkgs []*parser.ParsedPackage
+	var err error
+	if modules := p.newWalker().DetectModules(dir); len(modules) > 1 {
+		result, targets, pkgs, err = p.analyzeModules(ctx, dir, modules)
+		analyzers = p.repoAnalyzers()
+	} else {
+		result, targets, pkgs, err = p.discoverAndParse(ctx, dir)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		p.endStage()
+		return nil, err
+	}
+
+	p.injectGoPackages(pkgs)
+	p.startStage("analyze", "Analyzing code...")
+	results, interrupted := p.runAnalyzers(ctx, analyzers, targets)
+	p.results = results
+	p.interrupted = mergeInterrupted(p.interrupted, interrupted)
+	recs := p.scoreAndRecommend(dir)
+	p.endStage()
+	diags := p.sortedDiagnostics()
+	if p.scored != nil {
+		p.scored.Diagnostics = diags
+	}
+
+	return &Result{
+		Scan:            result,
+		Analyses:        p.results,
+		Scored:          p.scored,
+		Recommendations: recs,
+		Languages:       p.langs,
+		Warnings:        p.warnings,
+		Diagnostics:     diags,
+		Interrupted:     p.interrupted,
+	}, ctx.Err()
+}
+
+// render writes res in the configured formats as the "render" stage.
+func (p *Pipeline) render(res *Result) error {
+	defer p.endStage()
+	if err := p.renderOutput(res); err != nil {
+		return err
+	}
+	if p.htmlOutput != "" && res.Scored != nil {
+		if err := p.generateHTMLReport(res); err != nil {
+			return fmt.Errorf("generate HTML report: %w", err)
+		}
+	}
+	return nil
+}
+
+// emit sends e to the event stream set with SetEvents, if any.
+func (p *Pipeline) emit(e types.Event) {
+	if p.events == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Module == "" {
+		e.Module = p.module
+	}
+	p.events(e)
+}
+
+// startStage ends the current stage, reports the new one to the progress
+// callback and emits its start event.
+func (p *Pipeline) startStage(stage, detail string) {
+	p.endStage()
+	p.onProgress(stage, detail)
+	p.stage, p.stageStart = stage, time.Now()
+	p.emit(types.Event{Type: types.EventStageStart, Stage: stage, Detail: detail})
+}
+
+// endStage emits the end event of the current stage, if one is running.
+func (p *Pipeline) endStage() {
+	if p.stage == "" {
+		return
+	}
+	p.emit(types.Event{Type: types.EventStageEnd, Stage: p.stage, DurationMS: time.Since(p.stageStart).Milliseconds()})
+	p.stage = ""
+}
+
+// EmitSummary emits the final event of a run: the scores of res, or why
+// there are none. Run emits it itself; callers of Analyze emit it once they
+// are done with the result. err is the error the run ends with, if any.
+func (p *Pipeline) EmitSummary(res *Result, err error) {
+	summary := &types.ScanSummary{
+		DurationMS:  time.Since(p.runStart).Milliseconds(),
+		TotalTokens: int(p.tokens.Load()),
+		Categories:  map[string]float64{},
+	}
+	if res != nil {
+		summary.Errors = types.CountSeverity(res.Diagnostics, types.SeverityError)
+		summary.Warnings = types.CountSeverity(res.Diagnostics, types.SeverityWarning)
+		summary.Interrupted = res.Interrupted
+	}
+	switch {
+	case res != nil && res.Scored != nil:
+		summary.Composite = res.Scored.Composite
+		summary.Tier = res.Scored.Tier
+		for _, c := range res.Scored.Categories {
+			summary.Categories[c.Name] = c.Score
+		}
+		if err != nil && len(res.Interrupted) == 0 {
+			summary.Failed = err.Error()
+		}
+	case err != nil:
+		summary.Failed = err.Error()
+	default:
+		summary.Failed = "scoring failed"
+	}
+	p.emit(types.Event{Type: types.EventSummary, Summary: summary})
+}
+
+// cutShortError is Run's error for a scan whose context ended during analysis.
+func cutShortError(err error, interrupted []string) error {
+	code, what := exitInterrupted, "interrupted"
+	if errors.Is(err, context.DeadlineExceeded) {
+		code, what = exitTimedOut, "timed out"
+	}
+	msg := "scan " + what
+	if len(interrupted) > 0 {
+		msg += "; partial report, cut short: " + strings.Join(interrupted, ", ")
+	}
+	return &types.ExitError{Code: code, Message: msg}
+}
+
+// failf records a stage that failed without stopping the scan, such as an
+// analyzer returning an error, as an error diagnostic and a warning.
+func (p *Pipeline) failf(analyzer, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	p.warnMu.Lock()
+	p.warnings = append(p.warnings, analyzer+": "+msg)
+	p.warnMu.Unlock()
+	p.diagnose(types.Diagnostic{Severity: types.SeverityError, Analyzer: analyzer, Message: msg})
+}
+
+// diagnose records a diagnostic of the current run. It is the recorder the
+// pipeline installs on the context, so it may be called concurrently.
+func (p *Pipeline) diagnose(d types.Diagnostic) {
+	if p.module != "" && p.module != "." && d.File != "" {
+		d.File = path.Join(p.module, d.File)
+	}
+	p.warnMu.Lock()
+	p.diagnostics = append(p.diagnostics, d)
+	p.warnMu.Unlock()
+	p.emit(types.Event{Type: types.EventDiagnostic, Diagnostic: &d})
+}
+
+// sortedDiagnostics returns the run's diagnostics ordered by severity, then
+// analyzer, file and message, so reports are stable across runs.
+func (p *Pipeline) sortedDiagnostics() []types.Diagnostic {
+	p.warnMu.Lock()
+	diags := slices.Clone(p.diagnostics)
+	p.warnMu.Unlock()
+	rank := map[types.Severity]int{types.SeverityError: 0, types.SeverityWarning: 1, types.SeverityInfo: 2}
+	slices.SortStableFunc(diags, func(a, b types.Diagnostic) int {
+		if c := cmp.Compare(rank[a.Severity], rank[b.Severity]); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Analyzer, b.Analyzer); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Message, b.Message)
+	})
+	return diags
+}
+
+func (p *Pipeline) discoverAndParse(ctx context.Context, dir string) (*types.ScanResult, []*types.AnalysisTarget, []*parser.ParsedPackage, error) {
+	p.startStage("discover", "Scanning files...")
+	walker := p.newWalker()
+	result, err := walker.Discover(ctx, dir)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -227,16 +615,20 @@ func (p *Pipeline) discoverAndParse(dir string) (*types.ScanResult, []*types.Ana
 
 	var pkgs []*parser.ParsedPackage
 	if hasGo {
-		p.onProgress("parse", "Parsing Go packages...")
-		pkgs, err = p.parser.Parse(dir)
+		p.startStage("parse", "Parsing Go packages...")
+		pkgs, err = p.parseGo(ctx, dir)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, nil, ctxErr
+		}
 		if err != nil {
-			fmt.Fprintf(p.writer, "Warning: Go parsing error: %v\n", err)
+			p.failf("parser", "Go parsing failed: %v", err)
 		}
+		pkgs = withoutDirs(pkgs, walker.ExcludesDir)
 	}
 
 	var targets []*types.AnalysisTarget
 	if len(pkgs) > 0 {
-		targets = append(targets, buildGoTargets(dir, pkgs)...)
+		targets = append(targets, buildGoTargets(dir, pkgs, result)...)
 	}
 	targets = append(targets, buildNonGoTargets(dir, result)...)
 
@@ -247,6 +639,46 @@ func (p *Pipeline) discoverAndParse(dir string) (*types.ScanResult, []*types.Ana
 	return result, targets, pkgs, nil
 }
 
+// parseGo loads the Go packages of the module in dir. With the results cache
+// enabled, packages whose results every Go-aware analyzer has cached are
+// loaded without syntax and type information, which would otherwise take most
+// of a repeat scan.
+func (p *Pipeline) parseGo(ctx context.Context, dir string) ([]*parser.ParsedPackage, error) {
+	pp, ok := p.parser.(partialParseProvider)
+	if !ok || p.cache == nil {
+		return p.parser.Parse(ctx, dir)
+	}
+	contents := make(map[string][]byte)
+	return pp.ParseExcept(ctx, dir, func(pkg *parser.ParsedPackage) bool {
+		for _, file := range pkg.GoFiles {
+			if _, ok := contents[file]; ok {
+				continue
+			}
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return false
+			}
+			contents[file] = content
+		}
+		return p.goResultsCached(pkg, contents)
+	})
+}
+
+// goResultsCached reports whether every Go-aware analyzer has the results it
+// derives from pkg cached, given the content of its files.
+func (p *Pipeline) goResultsCached(pkg *parser.ParsedPackage, contents map[string][]byte) bool {
+	for _, a := range p.analyzers {
+		if _, ok := a.(goAwareAnalyzer); !ok {
+			continue
+		}
+		ca, ok := a.(goCacheAwareAnalyzer)
+		if !ok || !ca.GoResultsCached(pkg, contents) {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *Pipeline) injectGoPackages(pkgs []*parser.ParsedPackage) {
 	if len(pkgs) == 0 {
 		return
@@ -258,24 +690,34 @@ func (p *Pipeline) injectGoPackages(pkgs []*parser.ParsedPackage) {
 	}
 }
 
-func (p *Pipeline) runAnalyzers(targets []*types.AnalysisTarget) {
-	p.onProgress("analyze", "Analyzing code...")
-	p.results = nil
+// runAnalyzers runs analyzers in parallel and returns their results ordered by
+// category. An analyzer still running when ctx is done is returned as
+// interrupted; its partial result, if it returns one, is kept.
+func (p *Pipeline) runAnalyzers(ctx context.Context, analyzers []analyzerIface, targets []*types.AnalysisTarget) ([]*types.AnalysisResult, []string) {
 	g := new(errgroup.Group)
 	var mu sync.Mutex
 	var analysisResults []*types.AnalysisResult
+	var interrupted []string
 
-	for _, a := range p.analyzers {
+	for _, a := range analyzers {
 		a := a
 		g.Go(func() error {
-			ar, err := a.Analyze(targets)
-			if err != nil {
-				fmt.Fprintf(p.writer, "Warning: %s analyzer error: %v\n", a.Name(), err)
+			start := time.Now()
+			ar, err := a.Analyze(p.analyzerContext(ctx, a), targets)
+			cut := ctx.Err() != nil
+			p.emitAnalyzerEnd(a, ar, err, start)
+			if err != nil && !cut {
+				p.failf(analyzerCategory(a, ar), "analyzer failed: %v", err)
 				return nil
 			}
 			mu.Lock()
-			analysisResults = append(analysisResults, ar)
-			mu.Unlock()
+			defer mu.Unlock()
+			if cut {
+				interrupted = append(interrupted, analyzerCategory(a, ar))
+			}
+			if err == nil {
+				analysisResults = append(analysisResults, ar)
+			}
 			return nil
 		})
 	}
@@ -284,16 +726,55 @@ func (p *Pipeline) runAnalyzers(targets []*types.AnalysisTarget) {
 	sort.Slice(analysisResults, func(i, j int) bool {
 		return analysisResults[i].Category < analysisResults[j].Category
 	})
-	p.results = analysisResults
+	sort.Strings(interrupted)
+	return analysisResults, interrupted
+}
+
+// analyzerContext emits a's start event and returns the context it runs
+// under, which counts the tokens of its LLM calls for the event stream.
+func (p *Pipeline) analyzerContext(ctx context.Context, a analyzerIface) context.Context {
+	if p.events == nil {
+		return ctx
+	}
+	category := analyzerCategory(a, nil)
+	p.emit(types.Event{Type: types.EventAnalyzerStart, Analyzer: category})
+	return metrics.WithUsageRecorder(ctx, func(u metrics.Usage) {
+		total := p.tokens.Add(int64(u.Tokens()))
+		p.emit(types.Event{Type: types.EventTokens, Analyzer: category, Tokens: u.Tokens(), TotalTokens: int(total)})
+	})
+}
+
+// emitAnalyzerEnd emits the end event of an analyzer started at start.
+func (p *Pipeline) emitAnalyzerEnd(a analyzerIface, ar *types.AnalysisResult, err error, start time.Time) {
+	e := types.Event{Type: types.EventAnalyzerEnd, Analyzer: analyzerCategory(a, ar), DurationMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	p.emit(e)
+}
+
+// analyzerCategory returns the category an analyzer reports: that of its
+// result or, without one, the identifier its name starts with ("C5: ...").
+func analyzerCategory(a analyzerIface, ar *types.AnalysisResult) string {
+	if ar != nil && ar.Category != "" {
+		return ar.Category
+	}
+	id, _, _ := strings.Cut(a.Name(), ":")
+	return id
 }
 
 func (p *Pipeline) scoreAndRecommend(dir string) []recommend.Recommendation {
-	p.onProgress("score", "Computing scores...")
+	p.startStage("score", "Computing scores...")
 	scored, err := p.scorer.Score(p.results)
+	if err == nil && p.modules != nil {
+		scored = p.scorer.Aggregate(p.modules, scored.Categories)
+	}
 	if err != nil {
-		fmt.Fprintf(p.writer, "Warning: scoring error: %v\n", err)
+		p.failf("scoring", "scoring failed: %v", err)
 	} else {
 		scored.ProjectName = filepath.Base(dir)
+		scored.LLMBudget = p.budget.Report()
+		markInterrupted(scored, p.interrupted)
 		p.scored = scored
 	}
 
@@ -309,33 +790,88 @@ func (p *Pipeline) scoreAndRecommend(dir string) []recommend.Recommendation {
 	return recs
 }
 
-func (p *Pipeline) renderOutput(result *types.ScanResult, recs []recommend.Recommendation) error {
-	p.onProgress("render", "Generating output...")
-	if p.jsonOutput {
-		if p.scored != nil {
-			report := output.BuildJSONReport(p.scored, recs, p.verbose, p.badgeOutput)
-			if err := output.RenderJSON(p.writer, report); err != nil {
-				return fmt.Errorf("render JSON: %w", err)
-			}
+// markInterrupted records the categories cut short in scored.
+func markInterrupted(scored *types.ScoredResult, interrupted []string) {
+	if len(interrupted) == 0 {
+		return
+	}
+	scored.Interrupted = interrupted
+	for i := range scored.Categories {
+		if slices.Contains(interrupted, scored.Categories[i].Name) {
+			scored.Categories[i].Interrupted = true
 		}
+	}
+}
+
+func (p *Pipeline) renderOutput(res *Result) error {
+	p.startStage("render", "Generating output...")
+	if p.jsonOutput {
+		return p.WriteJSON(p.writer, res)
+	}
+	p.WriteText(p.writer, res)
+	return nil
+}
+
+// WriteText renders res as the terminal report: summary, scores, monorepo
+// modules, recommendations, diagnostics and, if enabled, the badge.
+func (p *Pipeline) WriteText(w io.Writer, res *Result) {
+	output.RenderSummary(w, res.Scan, res.Analyses, p.verbose)
+	if res.Scored != nil {
+		output.RenderScores(w, res.Scored, p.verbose)
+		output.RenderModules(w, res.Scored.Modules)
+	}
+	if len(res.Recommendations) > 0 {
+		output.RenderRecommendations(w, res.Recommendations)
+	}
+	output.RenderDiagnostics(w, res.Diagnostics, p.verbose)
+	if p.badgeOutput && res.Scored != nil {
+		output.RenderBadge(w, res.Scored)
+	}
+}
+
+// WriteJSON renders res as the JSON report. Nothing is written if scoring failed.
+func (p *Pipeline) WriteJSON(w io.Writer, res *Result) error {
+	if res.Scored == nil {
 		return nil
 	}
+	report := output.BuildJSONReport(res.Scored, res.Recommendations, p.verbose, p.badgeOutput)
+	if err := output.RenderJSON(w, report); err != nil {
+		return fmt.Errorf("render JSON: %w", err)
+	}
+	return nil
+}
+
+// WriteHTML renders res as the self-contained HTML report. If baseline is
+// non-nil, the report includes a trend comparison against it.
+func (p *Pipeline) WriteHTML(w io.Writer, res *Result, baseline *types.ScoredResult) error {
+	if res.Scored == nil {
+		return fmt.Errorf("no scores to report")
+	}
 
-	output.RenderSummary(p.writer, result, p.results, p.verbose)
-	if p.scored != nil {
-		output.RenderScores(p.writer, p.scored, p.verbose)
+	gen, err := output.NewHTMLGenerator()
+	if err != nil {
+		return fmt.Errorf("create HTML generator: %w", err)
 	}
-	if len(recs) > 0 {
-		output.RenderRecommendations(p.writer, recs)
+
+	// Build trace data for modal rendering
+	langStrings := make([]string, len(res.Languages))
+	for i, l := range res.Languages {
+		langStrings[i] = string(l)
 	}
-	if p.badgeOutput && p.scored != nil {
-		output.RenderBadge(p.writer, p.scored)
+	traceData := &output.TraceData{
+		ScoringConfig:   p.scorer.Config,
+		AnalysisResults: res.Analyses,
+		Languages:       langStrings,
+	}
+
+	if err := gen.GenerateReport(w, res.Scored, res.Recommendations, baseline, traceData); err != nil {
+		return fmt.Errorf("generate report: %w", err)
 	}
 	return nil
 }
 
 // generateHTMLReport creates an HTML report file at the configured path.
-func (p *Pipeline) generateHTMLReport(recs []recommend.Recommendation) error {
+func (p *Pipeline) generateHTMLReport(res *Result) error {
 	// Load baseline if provided
 	var baseline *types.ScoredResult
 	if p.baselinePath != "" {
@@ -347,12 +883,6 @@ func (p *Pipeline) generateHTMLReport(recs []recommend.Recommendation) error {
 		}
 	}
 
-	// Create HTML generator
-	gen, err := output.NewHTMLGenerator()
-	if err != nil {
-		return fmt.Errorf("create HTML generator: %w", err)
-	}
-
 	// Create output file
 	f, err := os.Create(p.htmlOutput)
 	if err != nil {
@@ -360,20 +890,8 @@ func (p *Pipeline) generateHTMLReport(recs []recommend.Recommendation) error {
 	}
 	defer f.Close()
 
-	// Build trace data for modal rendering
-	langStrings := make([]string, len(p.langs))
-	for i, l := range p.langs {
-		langStrings[i] = string(l)
-	}
-	traceData := &output.TraceData{
-		ScoringConfig:   p.scorer.Config,
-		AnalysisResults: p.results,
-		Languages:       langStrings,
-	}
-
-	// Generate report
-	if err := gen.GenerateReport(f, p.scored, recs, baseline, traceData); err != nil {
-		return fmt.Errorf("generate report: %w", err)
+	if err := p.WriteHTML(f, res, baseline); err != nil {
+		return err
 	}
 
 	// Report file size
@@ -417,9 +935,31 @@ func loadBaseline(path string) (*types.ScoredResult, error) {
 	return result, nil
 }
 
+// withoutDirs returns the Go packages except those whose directory drop
+// reports. pkgs itself, which may be cached, is left unchanged.
+func withoutDirs(pkgs []*parser.ParsedPackage, drop func(dir string) bool) []*parser.ParsedPackage {
+	var kept []*parser.ParsedPackage
+	for _, pkg := range pkgs {
+		if len(pkg.GoFiles) > 0 && drop(filepath.Dir(pkg.GoFiles[0])) {
+			continue
+		}
+		kept = append(kept, pkg)
+	}
+	return kept
+}
+
 // buildGoTargets creates an []*types.AnalysisTarget from parsed Go packages.
 // This bridges the Go-specific parser output to the language-agnostic interface.
-func buildGoTargets(rootDir string, pkgs []*parser.ParsedPackage) []*types.AnalysisTarget {
+// Files the discovery walk in scan classified as generated keep that class.
+func buildGoTargets(rootDir string, pkgs []*parser.ParsedPackage, scan *types.ScanResult) []*types.AnalysisTarget {
+	generated := make(map[string]bool)
+	if scan != nil {
+		for _, f := range scan.Files {
+			if f.Class == types.ClassGenerated && f.Language == types.LangGo {
+				generated[f.Path] = true
+			}
+		}
+	}
 	seen := make(map[string]bool)
 	var files []types.SourceFile
 
@@ -440,6 +980,9 @@ func buildGoTargets(rootDir string, pkgs []*parser.ParsedPackage) []*types.Analy
 			if isTest {
 				class = types.ClassTest
 			}
+			if generated[goFile] {
+				class = types.ClassGenerated
+			}
 
 			sf := types.SourceFile{
 				Path:     goFile,
@@ -2,14 +2,19 @@ package pipeline
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -20,7 +25,7 @@ func (s *stubAnalyzer) Name() string {
 	return "stub"
 }
 
-func (s *stubAnalyzer) Analyze(_ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (s *stubAnalyzer) Analyze(_ context.Context, _ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	return &types.AnalysisResult{
 		Name:    "stub",
 		Metrics: make(map[string]types.CategoryMetrics),
@@ -37,7 +42,7 @@ func TestPipelineRun(t *testing.T) {
 	p := New(&buf, false, nil, 0, false, nil)
 	p.DisableLLM()
 
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() returned error: %v", err)
 	}
 
@@ -89,7 +94,7 @@ func TestPipelineRunVerbose(t *testing.T) {
 	p := New(&buf, true, nil, 0, false, nil)
 	p.DisableLLM()
 
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() returned error: %v", err)
 	}
 
@@ -110,7 +115,7 @@ func TestStubAnalyzerReturnsEmpty(t *testing.T) {
 		t.Errorf("expected name 'stub', got %q", a.Name())
 	}
 
-	result, err := a.Analyze(nil)
+	result, err := a.Analyze(context.Background(), nil)
 	if err != nil {
 		t.Fatalf("stubAnalyzer.Analyze() returned error: %v", err)
 	}
@@ -136,13 +141,133 @@ func TestPipelineAnalyzerErrorContinues(t *testing.T) {
 		&stubAnalyzer{},
 	}
 
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() should not fail when analyzer errors: %v", err)
 	}
 
 	out := buf.String()
-	if !strings.Contains(out, "Warning:") {
-		t.Error("expected warning about analyzer error in output")
+	if !strings.Contains(out, "Diagnostics") || !strings.Contains(out, "analyzer failed: test error") {
+		t.Errorf("expected analyzer failure in diagnostics output, got:\n%s", out)
+	}
+}
+
+func TestAnalyzeReportsAnalyzerFailureDiagnostic(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.DisableLLM()
+	p.analyzers = []analyzerIface{&errorAnalyzer{}, &stubAnalyzer{}}
+
+	res, err := p.Analyze(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+
+	want := types.Diagnostic{Severity: types.SeverityError, Analyzer: "error-test", Message: "analyzer failed: test error"}
+	if len(res.Diagnostics) == 0 || res.Diagnostics[0] != want {
+		t.Fatalf("Diagnostics = %+v, want %+v first", res.Diagnostics, want)
+	}
+	if res.Scored == nil || len(res.Scored.Diagnostics) != len(res.Diagnostics) {
+		t.Error("scored result should carry the same diagnostics")
+	}
+	if len(res.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the analyzer failure", res.Warnings)
+	}
+}
+
+func TestRunStrictFailsOnAnalyzerError(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	p := New(&buf, false, nil, 0, false, nil)
+	p.DisableLLM()
+	p.SetStrict(true)
+	p.analyzers = []analyzerIface{&errorAnalyzer{}, &stubAnalyzer{}}
+
+	err = p.Run(context.Background(), root)
+	var exitErr *types.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != exitStrict {
+		t.Fatalf("Run() error = %v, want exit code %d", err, exitStrict)
+	}
+	if !strings.Contains(buf.String(), "Diagnostics") {
+		t.Error("report should still be rendered with --strict")
+	}
+
+	// Without failures, strict mode does not change the outcome.
+	p.analyzers = []analyzerIface{&stubAnalyzer{}}
+	if err := p.Run(context.Background(), root); err != nil {
+		t.Errorf("Run() without failures error = %v", err)
+	}
+}
+
+// cancellingAnalyzer cancels the scan after a short delay, as Ctrl-C would,
+// and then stops like an analyzer honouring its context.
+type cancellingAnalyzer struct {
+	cancel context.CancelFunc
+}
+
+func (c *cancellingAnalyzer) Name() string { return "C7: Agent Evaluation" }
+
+func (c *cancellingAnalyzer) Analyze(ctx context.Context, _ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	time.Sleep(100 * time.Millisecond)
+	c.cancel()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestPipelineRun_Interrupted(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	p := New(&buf, false, nil, 0, false, nil)
+	p.DisableLLM()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.analyzers = []analyzerIface{
+		&slowAnalyzer{name: "fast-c1", category: "C1"},
+		&slowAnalyzer{name: "C6: Testing", category: "C6", delay: time.Minute},
+		&cancellingAnalyzer{cancel: cancel},
+	}
+
+	err = p.Run(ctx, root)
+	var exitErr *types.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != exitInterrupted {
+		t.Fatalf("Run() error = %v, want ExitError with code %d", err, exitInterrupted)
+	}
+	if !strings.Contains(exitErr.Message, "C6, C7") {
+		t.Errorf("error message %q should name the categories cut short", exitErr.Message)
+	}
+	if p.scored == nil {
+		t.Fatal("interrupted scan should still be scored")
+	}
+	if got := p.scored.Interrupted; len(got) != 2 || got[0] != "C6" || got[1] != "C7" {
+		t.Errorf("Interrupted = %v, want [C6 C7]", got)
+	}
+	if len(p.results) != 1 || p.results[0].Category != "C1" {
+		t.Errorf("results = %v, want only the completed C1", p.results)
+	}
+	if !strings.Contains(buf.String(), "Cut short:") {
+		t.Errorf("partial report should name the categories cut short:\n%s", buf.String())
+	}
+}
+
+func TestCutShortError(t *testing.T) {
+	err := cutShortError(context.DeadlineExceeded, []string{"C7"})
+	var exitErr *types.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != exitTimedOut {
+		t.Fatalf("cutShortError(DeadlineExceeded) = %v, want code %d", err, exitTimedOut)
+	}
+	if exitErr.Message != "scan timed out; partial report, cut short: C7" {
+		t.Errorf("message = %q", exitErr.Message)
 	}
 }
 
@@ -156,7 +281,7 @@ func TestPipelineScoringStage(t *testing.T) {
 	p := New(&buf, false, nil, 0, false, nil)
 	p.DisableLLM()
 
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() returned error: %v", err)
 	}
 
@@ -199,11 +324,12 @@ type errorAnalyzer struct{}
 
 func (e *errorAnalyzer) Name() string { return "error-test" }
 
-func (e *errorAnalyzer) Analyze(_ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+func (e *errorAnalyzer) Analyze(_ context.Context, _ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
 	return nil, errors.New("test error")
 }
 
-// slowAnalyzer sleeps for a given duration then returns a result with the given category.
+// slowAnalyzer sleeps for a given duration then returns a result with the given
+// category, or ctx.Err() if ctx is done first.
 type slowAnalyzer struct {
 	name     string
 	category string
@@ -212,8 +338,12 @@ type slowAnalyzer struct {
 
 func (s *slowAnalyzer) Name() string { return s.name }
 
-func (s *slowAnalyzer) Analyze(_ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
-	time.Sleep(s.delay)
+func (s *slowAnalyzer) Analyze(ctx context.Context, _ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 	return &types.AnalysisResult{
 		Name:     s.name,
 		Category: s.category,
@@ -244,11 +374,11 @@ func TestParallelAnalyzers(t *testing.T) {
 	baseline.DisableLLM()
 	baseline.analyzers = []analyzerIface{} // no analyzers
 	baseStart := time.Now()
-	_ = baseline.Run(root) // ignore errors from empty analyzers
+	_ = baseline.Run(context.Background(), root) // ignore errors from empty analyzers
 	baselineTime := time.Since(baseStart)
 
 	start := time.Now()
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() returned error: %v", err)
 	}
 	elapsed := time.Since(start)
@@ -288,7 +418,7 @@ func TestProgressCallbackInvoked(t *testing.T) {
 	p := New(&buf, false, nil, 0, false, onProgress)
 	p.DisableLLM()
 
-	if err := p.Run(root); err != nil {
+	if err := p.Run(context.Background(), root); err != nil {
 		t.Fatalf("Pipeline.Run() returned error: %v", err)
 	}
 
@@ -308,6 +438,292 @@ func TestProgressCallbackInvoked(t *testing.T) {
 	}
 }
 
+func TestEventStream(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var got []types.Event
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.DisableLLM()
+	p.analyzers = []analyzerIface{&errorAnalyzer{}, &stubAnalyzer{}}
+	p.SetEvents(func(e types.Event) {
+		mu.Lock()
+		got = append(got, e)
+		mu.Unlock()
+	})
+
+	if err := p.Run(context.Background(), root); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	count := map[types.EventType]int{}
+	starts := map[string]bool{}
+	for _, e := range got {
+		if e.Time.IsZero() {
+			t.Errorf("%s event has no time", e.Type)
+		}
+		count[e.Type]++
+		switch e.Type {
+		case types.EventStageStart:
+			starts[e.Stage] = true
+		case types.EventStageEnd:
+			if !starts[e.Stage] {
+				t.Errorf("stage %q ended before it started", e.Stage)
+			}
+		case types.EventDiagnostic:
+			if e.Diagnostic == nil || e.Diagnostic.Analyzer != "error-test" {
+				t.Errorf("diagnostic event = %+v, want the analyzer failure", e.Diagnostic)
+			}
+		}
+	}
+	for _, stage := range []string{"discover", "parse", "analyze", "score", "render"} {
+		if !starts[stage] {
+			t.Errorf("no start event for stage %q", stage)
+		}
+	}
+	if count[types.EventStageStart] != count[types.EventStageEnd] {
+		t.Errorf("%d stage starts, %d stage ends", count[types.EventStageStart], count[types.EventStageEnd])
+	}
+	if count[types.EventAnalyzerStart] != 2 || count[types.EventAnalyzerEnd] != 2 {
+		t.Errorf("analyzer events = %d starts, %d ends, want 2 each", count[types.EventAnalyzerStart], count[types.EventAnalyzerEnd])
+	}
+
+	last := got[len(got)-1]
+	if last.Type != types.EventSummary || last.Summary == nil {
+		t.Fatalf("last event = %+v, want the summary", last)
+	}
+	if last.Summary.Errors != 1 || last.Summary.Failed != "" {
+		t.Errorf("summary = %+v, want 1 error and no failure", last.Summary)
+	}
+}
+
+func TestAnalyzeMonorepo(t *testing.T) {
+	// go.work puts the Go modules in workspace mode, which rejects -mod=mod.
+	t.Setenv("GOFLAGS", "")
+	root, err := filepath.Abs("../../testdata/monorepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.DisableLLM()
+	res, err := p.Analyze(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Analyze() error: %v", err)
+	}
+	if res.Scored == nil {
+		t.Fatal("Analyze() returned no scores")
+	}
+
+	var paths []string
+	for _, m := range res.Scored.Modules {
+		paths = append(paths, m.Path)
+		if m.LOC == 0 {
+			t.Errorf("module %s has no lines of code", m.Path)
+		}
+		for _, cs := range m.Categories {
+			if cs.Name == "C4" || cs.Name == "C5" || cs.Name == "C7" {
+				t.Errorf("module %s scored repository-level category %s", m.Path, cs.Name)
+			}
+		}
+	}
+	want := []string{"libs/util", "packages/web", "services/api", "tools/lint"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("modules = %v, want %v", paths, want)
+	}
+
+	// Only the repository root has a README; C4 must find it there
+	var c4 *types.C4Metrics
+	for _, ar := range res.Analyses {
+		if ar.Category == "C4" {
+			c4, _ = ar.Metrics["c4"].(*types.C4Metrics)
+		}
+	}
+	if c4 == nil || !c4.ReadmePresent {
+		t.Errorf("C4 metrics = %+v, want the repository root README", c4)
+	}
+	if c4 != nil && c4.PublicAPIs == 0 {
+		t.Error("C4 counted no public APIs across the modules")
+	}
+
+	var buf bytes.Buffer
+	p.WriteText(&buf, res)
+	for _, path := range want {
+		if !strings.Contains(buf.String(), path) {
+			t.Errorf("text report does not list module %s", path)
+		}
+	}
+}
+
+func TestDiscoverAndParsePathRules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/rules\n\ngo 1.21\n",
+		"main.go":        "package main\n\nfunc main() {}\n",
+		"build/build.go": "package build\n\n// Run builds.\nfunc Run() {}\n",
+		"gen/gen.go":     "package gen\n\n// Gen is generated.\nfunc Gen() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.SetPathRules(discovery.PathRules{Include: []string{"build"}, Exclude: []string{"gen"}})
+	scan, targets, pkgs, err := p.discoverAndParse(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("discoverAndParse() error: %v", err)
+	}
+
+	found := map[string]bool{}
+	for _, f := range scan.Files {
+		found[filepath.ToSlash(f.RelPath)] = true
+	}
+	if !found["build/build.go"] || found["gen/gen.go"] {
+		t.Errorf("discovered files = %v, want build/build.go but not gen/gen.go", found)
+	}
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == "example.com/rules/gen" {
+			t.Error("Go package in excluded gen/ was kept")
+		}
+	}
+	for _, tgt := range targets {
+		for _, f := range tgt.Files {
+			if filepath.ToSlash(f.RelPath) == "gen/gen.go" {
+				t.Error("gen/gen.go is an analysis target")
+			}
+		}
+	}
+}
+
+func TestDiscoverAndParseGeneratedFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/gen\n\ngo 1.21\n",
+		"main.go":         "package main\n\nfunc main() {}\n",
+		"zz_generated.go": "// Code generated by controller-gen. DO NOT EDIT.\n\npackage main\n\nfunc deepCopy() {}\n",
+		"web/app.ts":      "export const x = 1;\n",
+		"web/api.gen.ts":  "export const y = 2;\n",
+		"web/client.ts":   "// Built by acme-gen\nexport const z = 3;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.SetGeneratedRules(discovery.GeneratedRules{Markers: []string{"built by acme-gen"}})
+	_, targets, _, err := p.discoverAndParse(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("discoverAndParse() error: %v", err)
+	}
+
+	classes := map[string]types.FileClass{}
+	for _, tgt := range targets {
+		for _, f := range tgt.Files {
+			classes[filepath.ToSlash(f.RelPath)] = f.Class
+		}
+	}
+	if classes["main.go"] != types.ClassSource || classes["zz_generated.go"] != types.ClassGenerated {
+		t.Errorf("Go target classes = %v, want main.go source and zz_generated.go generated", classes)
+	}
+	if classes["web/app.ts"] != types.ClassSource {
+		t.Errorf("web/app.ts class = %v, want source", classes["web/app.ts"])
+	}
+	for _, rel := range []string{"web/api.gen.ts", "web/client.ts"} {
+		if _, ok := classes[rel]; ok {
+			t.Errorf("generated %s is an analysis target", rel)
+		}
+	}
+}
+
+func TestAnalyzeCacheSkipsParsingCachedPackages(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles := func(files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeFiles(map[string]string{
+		"go.mod":      "module example.com/cached\n\ngo 1.21\n",
+		"a/a.go":      "package a\n\n// A is used by b.\nfunc A(x any) int { return 42 }\n\n// Unused is dead.\nfunc Unused() {}\n",
+		"a/a_test.go": "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {\n\tif A(nil) != 42 {\n\t\tt.Error(\"A\")\n\t}\n}\n",
+		"b/b.go":      "package b\n\nimport \"example.com/cached/a\"\n\n// B calls A.\nfunc B() int { return a.A(7) * 3 }\n",
+	})
+
+	p := New(io.Discard, false, nil, 0, false, nil)
+	p.DisableLLM()
+	p.SetCache(cache.Open(t.TempDir(), ""))
+	goMetrics := func() map[string]types.CategoryMetrics {
+		t.Helper()
+		res, err := p.Analyze(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("Analyze() error: %v", err)
+		}
+		metrics := make(map[string]types.CategoryMetrics)
+		for _, ar := range res.Analyses {
+			for _, key := range []string{"c1", "c2", "c3", "c6"} {
+				if m, ok := ar.Metrics[key]; ok {
+					metrics[key] = m
+				}
+			}
+		}
+		return metrics
+	}
+	parsed := func() map[string]bool {
+		t.Helper()
+		_, _, pkgs, err := p.discoverAndParse(context.Background(), dir)
+		if err != nil {
+			t.Fatalf("discoverAndParse() error: %v", err)
+		}
+		parsed := make(map[string]bool)
+		for _, pkg := range pkgs {
+			if pkg.ForTest == "" && !strings.HasSuffix(pkg.PkgPath, ".test") {
+				parsed[pkg.PkgPath] = pkg.Syntax != nil
+			}
+		}
+		return parsed
+	}
+
+	cold := goMetrics()
+	c3, _ := cold["c3"].(*types.C3Metrics)
+	c6, _ := cold["c6"].(*types.C6Metrics)
+	if c3 == nil || len(c3.DeadExports) != 2 || c6 == nil || len(c6.TestFunctions) != 1 {
+		t.Fatalf("cold scan C3 %+v, C6 %+v, want dead exports Unused and B and test TestA", c3, c6)
+	}
+	if got := parsed(); got["example.com/cached/a"] || got["example.com/cached/b"] {
+		t.Errorf("parsed packages on a repeat scan = %v, want none", got)
+	}
+	if warm := goMetrics(); !reflect.DeepEqual(warm, cold) {
+		t.Errorf("cached scan metrics differ:\n got %+v\nwant %+v", warm, cold)
+	}
+
+	writeFiles(map[string]string{"b/b.go": "package b\n\nimport \"example.com/cached/a\"\n\n// B calls A.\nfunc B() int { return a.A(7) * 5 }\n"})
+	if got := parsed(); got["example.com/cached/a"] || !got["example.com/cached/b"] {
+		t.Errorf("parsed packages after editing b = %v, want only b", got)
+	}
+}
+
 func TestDefaultPipelineHasZeroCostDebug(t *testing.T) {
 	var buf bytes.Buffer
 	p := New(&buf, false, nil, 0, false, nil)
@@ -0,0 +1,48 @@
+package pipeline
+
+import (
+	"context"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// pluginAnalyzer adapts a registered plugin to analyzerIface.
+type pluginAnalyzer struct {
+	plugin plugin.Plugin
+}
+
+func (a pluginAnalyzer) Name() string {
+	c := a.plugin.Category()
+	return c.ID + ": " + c.Name
+}
+
+// Analyze runs the plugin and stamps its category on the result, so a plugin
+// cannot report metrics under another category.
+func (a pluginAnalyzer) Analyze(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	c := a.plugin.Category()
+	ar, err := plugin.Analyze(ctx, a.plugin, targets)
+	if err != nil {
+		return nil, err
+	}
+	if ar == nil {
+		ar = &types.AnalysisResult{}
+	}
+	ar.Category = c.ID
+	if ar.Name == "" {
+		ar.Name = c.Name
+	}
+	return ar, nil
+}
+
+// pluginAnalyzers returns an analyzer for every plugin and adds the plugins
+// to cfg, which scores them.
+func pluginAnalyzers(cfg *scoring.ScoringConfig, plugins []plugin.Plugin) []analyzerIface {
+	var analyzers []analyzerIface
+	for _, p := range plugins {
+		cfg.AddPlugin(p)
+		analyzers = append(analyzers, pluginAnalyzer{plugin: p})
+	}
+	return analyzers
+}
@@ -0,0 +1,120 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+type todoPlugin struct{}
+
+func (todoPlugin) Category() plugin.Category {
+	return plugin.Category{
+		ID:     "C8",
+		Name:   "Open TODOs",
+		Weight: 0.1,
+		Metrics: []plugin.Metric{{
+			Name:        "todo_count",
+			DisplayName: "TODO comments",
+			Action:      "Resolve TODO comments (%.0f open, target %.0f)",
+			Weight:      1,
+			Breakpoints: []plugin.Breakpoint{{Value: 0, Score: 10}, {Value: 10, Score: 1}},
+		}},
+	}
+}
+
+func (todoPlugin) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	// Report under another category to check the pipeline stamps the right one.
+	ar := plugin.Result("C8", plugin.Values{"todo_count": {Value: 8}})
+	ar.Category = "C1"
+	return ar, nil
+}
+
+func TestPipeline_RunsRegisteredPlugins(t *testing.T) {
+	plugin.MustRegister(todoPlugin{})
+	t.Cleanup(func() { plugin.Unregister("C8") })
+
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	p := New(&buf, true, nil, 0, false, nil)
+	p.DisableLLM()
+	res, err := p.Analyze(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	var c8 *types.CategoryScore
+	for i := range res.Scored.Categories {
+		if res.Scored.Categories[i].Name == "C8" {
+			c8 = &res.Scored.Categories[i]
+		}
+	}
+	if c8 == nil {
+		t.Fatal("plugin category C8 not scored")
+	}
+	if c8.Weight != 0.1 || c8.Score <= 1 || c8.Score >= 10 {
+		t.Errorf("C8 = weight %v, score %v", c8.Weight, c8.Score)
+	}
+
+	foundRec := false
+	for _, rec := range res.Recommendations {
+		if rec.MetricName == "todo_count" {
+			foundRec = strings.HasPrefix(rec.Action, "Resolve TODO comments (8 open")
+		}
+	}
+	if !foundRec {
+		t.Errorf("no recommendation using the plugin's action template: %+v", res.Recommendations)
+	}
+
+	p.WriteText(&buf, res)
+	out := buf.String()
+	if !strings.Contains(out, "C8: Open TODOs") || !strings.Contains(out, "TODO comments:") {
+		t.Errorf("terminal output lacks plugin display names:\n%s", out)
+	}
+}
+
+func TestPipeline_SetPluginsScopedToPipeline(t *testing.T) {
+	root, err := filepath.Abs("../../testdata/valid-go-project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scoped := New(&bytes.Buffer{}, false, nil, 0, false, nil)
+	scoped.DisableLLM()
+	scoped.SetPlugins([]plugin.Plugin{todoPlugin{}})
+	res, err := scoped.Analyze(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	var c8 *types.CategoryScore
+	for i := range res.Scored.Categories {
+		if res.Scored.Categories[i].Name == "C8" {
+			c8 = &res.Scored.Categories[i]
+		}
+	}
+	if c8 == nil || c8.DisplayName != "Open TODOs" {
+		t.Fatalf("plugin category C8 = %+v, want it scored with its display name", c8)
+	}
+
+	if _, ok := plugin.Lookup("C8"); ok {
+		t.Error("SetPlugins registered the plugin globally")
+	}
+	other := New(&bytes.Buffer{}, false, nil, 0, false, nil)
+	other.DisableLLM()
+	res, err = other.Analyze(context.Background(), root)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	for _, cat := range res.Scored.Categories {
+		if cat.Name == "C8" {
+			t.Error("plugin of another pipeline was run")
+		}
+	}
+}
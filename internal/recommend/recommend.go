@@ -137,8 +137,8 @@ func Generate(scored *types.ScoredResult, cfg *scoring.ScoringConfig) []Recommen
 				TargetScore:      targetScore,
 				ScoreImprovement: impact,
 				Effort:           effort,
-				Summary:          buildSummary(ss.MetricName, ss.RawValue, targetValue),
-				Action:           buildAction(ss.MetricName, ss.RawValue, targetValue),
+				Summary:          buildSummary(cfg, ss.MetricName, ss.RawValue, targetValue),
+				Action:           buildAction(cfg, ss.MetricName, ss.RawValue, targetValue),
 			}
 			candidates = append(candidates, rec)
 		}
@@ -328,12 +328,15 @@ var displayNames = map[string]string{
 }
 
 // buildSummary creates an agent-readiness framed summary for a recommendation.
-func buildSummary(metricName string, currentValue, targetValue float64) string {
+func buildSummary(cfg *scoring.ScoringConfig, metricName string, currentValue, targetValue float64) string {
 	dn := displayNames[metricName]
+	impact := agentImpact[metricName]
+	if pm, ok := cfg.PluginMetric(metricName); ok {
+		dn, impact = pm.DisplayName, pm.Impact
+	}
 	if dn == "" {
 		dn = metricName
 	}
-	impact := agentImpact[metricName]
 	if impact == "" {
 		impact = "Improving this metric enhances agent effectiveness"
 	}
@@ -341,9 +344,13 @@ func buildSummary(metricName string, currentValue, targetValue float64) string {
 }
 
 // buildAction creates a concrete improvement action string.
-func buildAction(metricName string, currentValue, targetValue float64) string {
+func buildAction(cfg *scoring.ScoringConfig, metricName string, currentValue, targetValue float64) string {
 	tmpl, ok := actionTemplates[metricName]
 	if !ok {
+		// Plugin templates always receive the current and target value.
+		if pm, found := cfg.PluginMetric(metricName); found && pm.Action != "" {
+			return fmt.Sprintf(pm.Action, currentValue, targetValue)
+		}
 		return fmt.Sprintf("Improve %s from %.1f to %.1f", metricName, currentValue, targetValue)
 	}
 
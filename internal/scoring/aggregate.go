@@ -0,0 +1,144 @@
+package scoring
+
+import (
+	"sort"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Aggregate combines the scores of a monorepo's modules into repository
+// totals. Each category and sub-score is the mean of the modules' scores,
+// weighted by their lines of code, over the modules where it is available.
+// shared holds the categories scored once for the whole repository, such as
+// C5 and C7, which are used as they are. The composite and tier are computed
+// from the totals; the modules are attached in the order given.
+func (s *Scorer) Aggregate(modules []types.ModuleScore, shared []types.CategoryScore) *types.ScoredResult {
+	byName := make(map[string]types.CategoryScore)
+	for _, cs := range shared {
+		byName[cs.Name] = cs
+	}
+	for _, name := range moduleCategoryNames(modules) {
+		if _, ok := byName[name]; !ok {
+			byName[name] = aggregateCategory(name, modules)
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	categories := make([]types.CategoryScore, 0, len(names))
+	for _, name := range names {
+		categories = append(categories, byName[name])
+	}
+
+	composite := s.computeComposite(categories)
+	return &types.ScoredResult{
+		Categories:        categories,
+		Composite:         composite,
+		Tier:              s.classifyTier(composite),
+		CompositeInterval: s.compositeInterval(categories, composite),
+		Modules:           modules,
+	}
+}
+
+// moduleCategoryNames returns the categories scored in any module.
+func moduleCategoryNames(modules []types.ModuleScore) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range modules {
+		for _, cs := range m.Categories {
+			if !seen[cs.Name] {
+				seen[cs.Name] = true
+				names = append(names, cs.Name)
+			}
+		}
+	}
+	return names
+}
+
+// moduleWeight is a module's weight in the totals: its lines of code, but at
+// least 1 so that modules without counted lines still take part.
+func moduleWeight(m types.ModuleScore) float64 {
+	return float64(max(m.LOC, 1))
+}
+
+// aggregateCategory returns the LOC-weighted total of category name over the
+// modules. It is unavailable (-1) if no module could score it.
+func aggregateCategory(name string, modules []types.ModuleScore) types.CategoryScore {
+	total := types.CategoryScore{Name: name, Score: -1}
+	var sum, weight float64
+	var scored []types.ModuleScore
+	var cats []types.CategoryScore
+	for _, m := range modules {
+		for _, cs := range m.Categories {
+			if cs.Name != name {
+				continue
+			}
+			total.Weight = cs.Weight
+			total.DisplayName, total.Impact = cs.DisplayName, cs.Impact
+			total.Interrupted = total.Interrupted || cs.Interrupted
+			if cs.Score < 0 {
+				continue
+			}
+			sum += cs.Score * moduleWeight(m)
+			weight += moduleWeight(m)
+			scored = append(scored, m)
+			cats = append(cats, cs)
+		}
+	}
+	if weight == 0 {
+		return total
+	}
+	total.Score = sum / weight
+	total.SubScores = aggregateSubScores(scored, cats)
+	return total
+}
+
+// aggregateSubScores returns the LOC-weighted sub-scores of one category,
+// given the category's score in each module. Raw values are weighted like the
+// scores; evidence is taken from the modules in order, up to evidenceTopN.
+func aggregateSubScores(modules []types.ModuleScore, cats []types.CategoryScore) []types.SubScore {
+	var subs []types.SubScore
+	index := make(map[string]int)
+	weights := make(map[string]float64)
+	for i, cs := range cats {
+		w := moduleWeight(modules[i])
+		for _, ss := range cs.SubScores {
+			j, ok := index[ss.MetricName]
+			if !ok {
+				j = len(subs)
+				index[ss.MetricName] = j
+				subs = append(subs, types.SubScore{
+					MetricName:  ss.MetricName,
+					Weight:      ss.Weight,
+					Evidence:    []types.EvidenceItem{},
+					DisplayName: ss.DisplayName,
+					Description: ss.Description,
+				})
+			}
+			if !ss.Available {
+				continue
+			}
+			agg := &subs[j]
+			agg.Available = true
+			agg.Score += ss.Score * w
+			agg.RawValue += ss.RawValue * w
+			weights[ss.MetricName] += w
+			for _, ev := range ss.Evidence {
+				if len(agg.Evidence) == evidenceTopN {
+					break
+				}
+				agg.Evidence = append(agg.Evidence, ev)
+			}
+		}
+	}
+	for i := range subs {
+		if w := weights[subs[i].MetricName]; w > 0 {
+			subs[i].Score /= w
+			subs[i].RawValue /= w
+		}
+	}
+	return subs
+}
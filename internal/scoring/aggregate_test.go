@@ -0,0 +1,79 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+func TestAggregate_WeightsByLOC(t *testing.T) {
+	s := &Scorer{Config: DefaultConfig()}
+	modules := []types.ModuleScore{
+		{
+			Module: types.Module{Name: "a", Path: "a"},
+			LOC:    300,
+			Categories: []types.CategoryScore{
+				{Name: "C1", Score: 9, Weight: 0.25, SubScores: []types.SubScore{
+					{MetricName: "complexity_avg", RawValue: 2, Score: 9, Weight: 1, Available: true},
+				}},
+				{Name: "C3", Score: -1, Weight: 0.2},
+			},
+		},
+		{
+			Module: types.Module{Name: "b", Path: "b"},
+			LOC:    100,
+			Categories: []types.CategoryScore{
+				{Name: "C1", Score: 5, Weight: 0.25, SubScores: []types.SubScore{
+					{MetricName: "complexity_avg", RawValue: 10, Score: 5, Weight: 1, Available: true},
+				}},
+				{Name: "C3", Score: -1, Weight: 0.2},
+			},
+		},
+	}
+	shared := []types.CategoryScore{{Name: "C5", Score: 6, Weight: 0.1}}
+
+	got := s.Aggregate(modules, shared)
+
+	byName := make(map[string]types.CategoryScore)
+	for _, cs := range got.Categories {
+		byName[cs.Name] = cs
+	}
+	if len(byName) != 3 {
+		t.Fatalf("got %d categories, want C1, C3 and C5: %+v", len(byName), got.Categories)
+	}
+	// (9*300 + 5*100) / 400
+	if c1 := byName["C1"]; math.Abs(c1.Score-8) > 1e-9 {
+		t.Errorf("C1 score = %v, want 8", c1.Score)
+	}
+	if ss := byName["C1"].SubScores; len(ss) != 1 || math.Abs(ss[0].RawValue-4) > 1e-9 {
+		t.Errorf("C1 sub-scores = %+v, want complexity_avg raw value 4", ss)
+	}
+	if c3 := byName["C3"]; c3.Score != -1 {
+		t.Errorf("C3 score = %v, want -1 when no module could score it", c3.Score)
+	}
+	if c5 := byName["C5"]; c5.Score != 6 {
+		t.Errorf("C5 score = %v, want the shared score 6", c5.Score)
+	}
+	if want := s.computeComposite(got.Categories); got.Composite != want {
+		t.Errorf("Composite = %v, want %v", got.Composite, want)
+	}
+	if got.Tier != s.classifyTier(got.Composite) {
+		t.Errorf("Tier = %q for composite %v", got.Tier, got.Composite)
+	}
+	if len(got.Modules) != 2 || got.Modules[0].Name != "a" {
+		t.Errorf("Modules = %+v, want the two modules in order", got.Modules)
+	}
+}
+
+func TestAggregate_EmptyModuleStillCounts(t *testing.T) {
+	s := &Scorer{Config: DefaultConfig()}
+	modules := []types.ModuleScore{
+		{LOC: 0, Categories: []types.CategoryScore{{Name: "C1", Score: 4, Weight: 0.25}}},
+		{LOC: 0, Categories: []types.CategoryScore{{Name: "C1", Score: 8, Weight: 0.25}}},
+	}
+	got := s.Aggregate(modules, nil)
+	if len(got.Categories) != 1 || got.Categories[0].Score != 6 {
+		t.Errorf("Categories = %+v, want C1 as the plain mean 6", got.Categories)
+	}
+}
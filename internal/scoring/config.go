@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
 )
 
 // Score scale constants used in breakpoint definitions.
@@ -208,6 +210,11 @@ const (
 	C7ScoreAboveAvg = 7.0
 )
 
+// c7WeightSum normalizes the C7 metric weights to a total of 1. M1-M5 keep
+// their original weights relative to each other (0.20, 0.25, 0.25, 0.15,
+// 0.15); Change Success (M6) and Feature Localization (M7) add 0.15 each.
+const c7WeightSum = MetricWeightMedium + 2*MetricWeightHigh + 4*MetricWeightStandard
+
 // Breakpoint defines a mapping from a raw metric value to a score.
 // Breakpoints must be sorted by Value in ascending order.
 type Breakpoint struct {
@@ -241,6 +248,8 @@ type tierConfig struct {
 type ScoringConfig struct {
 	Categories map[string]CategoryConfig `yaml:"categories"`
 	Tiers      []tierConfig             `yaml:"tiers"`
+
+	plugins map[string]plugin.Plugin // plugin categories of the scan, by id (see AddPlugin)
 }
 
 // Category returns the CategoryConfig for the given category name.
@@ -679,7 +688,7 @@ func defaultC7Config() CategoryConfig {
 			// M1: Task Execution Consistency
 			{
 				Name:   "task_execution_consistency",
-				Weight: MetricWeightMedium,
+				Weight: MetricWeightMedium / c7WeightSum,
 				Breakpoints: []Breakpoint{
 					{Value: 1, Score: ScoreMinimum},
 					{Value: C7ScorePoor, Score: ScorePoor},
@@ -690,7 +699,7 @@ func defaultC7Config() CategoryConfig {
 			// M2: Code Behavior Comprehension
 			{
 				Name:   "code_behavior_comprehension",
-				Weight: MetricWeightHigh,
+				Weight: MetricWeightHigh / c7WeightSum,
 				Breakpoints: []Breakpoint{
 					{Value: 1, Score: ScoreMinimum},
 					{Value: C7ScorePoor, Score: ScorePoor},
@@ -701,7 +710,7 @@ func defaultC7Config() CategoryConfig {
 			// M3: Cross-File Navigation
 			{
 				Name:   "cross_file_navigation",
-				Weight: MetricWeightHigh,
+				Weight: MetricWeightHigh / c7WeightSum,
 				Breakpoints: []Breakpoint{
 					{Value: 1, Score: ScoreMinimum},
 					{Value: C7ScorePoor, Score: ScorePoor},
@@ -712,7 +721,7 @@ func defaultC7Config() CategoryConfig {
 			// M4: Identifier Interpretability
 			{
 				Name:   "identifier_interpretability",
-				Weight: MetricWeightStandard,
+				Weight: MetricWeightStandard / c7WeightSum,
 				Breakpoints: []Breakpoint{
 					{Value: 1, Score: ScoreMinimum},
 					{Value: C7ScorePoor, Score: ScorePoor},
@@ -723,7 +732,29 @@ func defaultC7Config() CategoryConfig {
 			// M5: Documentation Accuracy Detection
 			{
 				Name:   "documentation_accuracy_detection",
-				Weight: MetricWeightStandard,
+				Weight: MetricWeightStandard / c7WeightSum,
+				Breakpoints: []Breakpoint{
+					{Value: 1, Score: ScoreMinimum},
+					{Value: C7ScorePoor, Score: ScorePoor},
+					{Value: C7ScoreAboveAvg, Score: ScoreAboveAvg},
+					{Value: 10, Score: ScoreExcellent},
+				},
+			},
+			// M6: Change Success
+			{
+				Name:   "change_success",
+				Weight: MetricWeightStandard / c7WeightSum,
+				Breakpoints: []Breakpoint{
+					{Value: 1, Score: ScoreMinimum},
+					{Value: C7ScorePoor, Score: ScorePoor},
+					{Value: C7ScoreAboveAvg, Score: ScoreAboveAvg},
+					{Value: 10, Score: ScoreExcellent},
+				},
+			},
+			// M7: Feature Localization
+			{
+				Name:   "feature_localization",
+				Weight: MetricWeightStandard / c7WeightSum,
 				Breakpoints: []Breakpoint{
 					{Value: 1, Score: ScoreMinimum},
 					{Value: C7ScorePoor, Score: ScorePoor},
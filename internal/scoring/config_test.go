@@ -114,6 +114,32 @@ func TestDefaultConfig_MetricWeightsSum(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_C7RelativeWeights(t *testing.T) {
+	// M1-M5 keep the 0.20/0.25/0.25/0.15/0.15 split they had before M6 and M7.
+	want := map[string]float64{
+		"task_execution_consistency":       0.20,
+		"code_behavior_comprehension":      0.25,
+		"cross_file_navigation":            0.25,
+		"identifier_interpretability":      0.15,
+		"documentation_accuracy_detection": 0.15,
+	}
+	m1 := 0.0
+	for _, m := range DefaultConfig().Categories["C7"].Metrics {
+		if m.Name == "task_execution_consistency" {
+			m1 = m.Weight
+		}
+	}
+	for _, m := range DefaultConfig().Categories["C7"].Metrics {
+		w, ok := want[m.Name]
+		if !ok {
+			continue
+		}
+		if diff := m.Weight/m1 - w/0.20; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s weight relative to M1 = %v, want %v", m.Name, m.Weight/m1, w/0.20)
+		}
+	}
+}
+
 func TestDefaultConfig_BreakpointsSorted(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -22,6 +22,8 @@ func extractC7(ar *types.AnalysisResult) (map[string]float64, map[string]bool, m
 			"cross_file_navigation":            true,
 			"identifier_interpretability":      true,
 			"documentation_accuracy_detection": true,
+			"change_success":                   true,
+			"feature_localization":             true,
 		}
 		emptyEvidence := make(map[string][]types.EvidenceItem)
 		for k := range unavailable {
@@ -36,13 +38,118 @@ func extractC7(ar *types.AnalysisResult) (map[string]float64, map[string]bool, m
 		"cross_file_navigation":            {},
 		"identifier_interpretability":      {},
 		"documentation_accuracy_detection": {},
+		"change_success":                   {},
+		"feature_localization":             {},
 	}
 
-	return map[string]float64{
+	// Change success needs a git repository whose build and tests pass before
+	// the change, and feature localization documented exports; a score of 0
+	// means the metric could not run and is not a failure.
+	var unavailable map[string]bool
+	if m.ChangeSuccess == 0 {
+		unavailable = map[string]bool{"change_success": true}
+	}
+	if m.FeatureLocalization == 0 {
+		if unavailable == nil {
+			unavailable = make(map[string]bool)
+		}
+		unavailable["feature_localization"] = true
+	}
+
+	values := map[string]float64{
 		"task_execution_consistency":       float64(m.TaskExecutionConsistency),
 		"code_behavior_comprehension":      float64(m.CodeBehaviorComprehension),
 		"cross_file_navigation":            float64(m.CrossFileNavigation),
 		"identifier_interpretability":      float64(m.IdentifierInterpretability),
 		"documentation_accuracy_detection": float64(m.DocumentationAccuracyDetection),
-	}, nil, evidence
+		"change_success":                   float64(m.ChangeSuccess),
+		"feature_localization":             float64(m.FeatureLocalization),
+	}
+
+	// User-defined tasks only appear in MetricResults. A task whose samples
+	// all failed scores 0 and is unavailable, like change success.
+	for _, mr := range m.MetricResults {
+		if _, builtin := values[mr.MetricID]; builtin {
+			continue
+		}
+		values[mr.MetricID] = float64(mr.Score)
+		evidence[mr.MetricID] = []types.EvidenceItem{}
+		if mr.Score == 0 {
+			if unavailable == nil {
+				unavailable = make(map[string]bool)
+			}
+			unavailable[mr.MetricID] = true
+		}
+	}
+
+	// With repeated runs, score the mean rather than its rounded value.
+	for _, mr := range m.MetricResults {
+		if mr.Interval != nil {
+			values[mr.MetricID] = mr.Interval.Mean
+		}
+	}
+
+	// Metrics skipped to stay within the LLM budget are unavailable, with the
+	// reason as evidence.
+	for _, mr := range m.MetricResults {
+		if mr.Status != "skipped" {
+			continue
+		}
+		if unavailable == nil {
+			unavailable = make(map[string]bool)
+		}
+		unavailable[mr.MetricID] = true
+		evidence[mr.MetricID] = []types.EvidenceItem{{Description: mr.Reasoning}}
+	}
+
+	return values, unavailable, evidence
+}
+
+// extractC7Repeats returns the raw C7 metric values of each repeated run
+// (--c7-repeats), leaving out metrics that failed in that run, which are then
+// scored at their mean. It returns nil for a single run.
+func extractC7Repeats(ar *types.AnalysisResult) []map[string]float64 {
+	m, ok := ar.Metrics["c7"].(*types.C7Metrics)
+	if !ok || !m.Available || m.Repeats < 2 {
+		return nil
+	}
+	runs := make([]map[string]float64, m.Repeats)
+	for r := range runs {
+		runs[r] = make(map[string]float64)
+		for _, mr := range m.MetricResults {
+			if r < len(mr.RepeatScores) && mr.RepeatScores[r] != types.FailedRepeat {
+				runs[r][mr.MetricID] = float64(mr.RepeatScores[r])
+			}
+		}
+	}
+	return runs
+}
+
+// AddC7Metric adds a user-defined C7 task to the C7 category, scored with the
+// same breakpoints as the built-in C7 metrics. A metric already configured
+// under that name is kept.
+func (sc *ScoringConfig) AddC7Metric(name string, weight float64) {
+	if sc.Categories == nil {
+		sc.Categories = make(map[string]CategoryConfig)
+	}
+	cat, ok := sc.Categories["C7"]
+	if !ok {
+		cat = defaultC7Config()
+	}
+	for _, mt := range cat.Metrics {
+		if mt.Name == name {
+			return
+		}
+	}
+	cat.Metrics = append(cat.Metrics, MetricThresholds{
+		Name:   name,
+		Weight: weight,
+		Breakpoints: []Breakpoint{
+			{Value: 1, Score: ScoreMinimum},
+			{Value: C7ScorePoor, Score: ScorePoor},
+			{Value: C7ScoreAboveAvg, Score: ScoreAboveAvg},
+			{Value: 10, Score: ScoreExcellent},
+		},
+	})
+	sc.Categories["C7"] = cat
 }
@@ -0,0 +1,85 @@
+package scoring
+
+import (
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// lookupExtractor returns the metric extractor of a built-in or plugin category.
+func (sc *ScoringConfig) lookupExtractor(category string) (metricExtractor, bool) {
+	if extractor, ok := metricExtractors[category]; ok {
+		return extractor, true
+	}
+	p, ok := sc.Plugin(category)
+	if !ok {
+		return nil, false
+	}
+	return func(ar *types.AnalysisResult) (map[string]float64, map[string]bool, map[string][]types.EvidenceItem) {
+		return plugin.Extract(p, ar)
+	}, true
+}
+
+// AddPlugin adds a plugin category to the scan scored with sc, together with
+// its default scoring configuration. Settings already present in sc (from a
+// scoring config file or .arsrc.yml weight overrides) take precedence; a
+// configured category without metrics gets the plugin's metrics.
+func (sc *ScoringConfig) AddPlugin(p plugin.Plugin) {
+	c := p.Category()
+	if sc.plugins == nil {
+		sc.plugins = make(map[string]plugin.Plugin)
+	}
+	sc.plugins[c.ID] = p
+	if sc.Categories == nil {
+		sc.Categories = make(map[string]CategoryConfig)
+	}
+	cat, exists := sc.Categories[c.ID]
+	if cat.Name == "" {
+		cat.Name = c.Name
+	}
+	if !exists {
+		cat.Weight = c.Weight
+	}
+	if len(cat.Metrics) == 0 {
+		for _, m := range c.Metrics {
+			mt := MetricThresholds{Name: m.Name, Weight: m.Weight}
+			for _, bp := range m.Breakpoints {
+				mt.Breakpoints = append(mt.Breakpoints, Breakpoint{Value: bp.Value, Score: bp.Score})
+			}
+			cat.Metrics = append(cat.Metrics, mt)
+		}
+	}
+	sc.Categories[c.ID] = cat
+}
+
+// Plugin returns the plugin added under category id.
+func (sc *ScoringConfig) Plugin(id string) (plugin.Plugin, bool) {
+	p, ok := sc.plugins[id]
+	return p, ok
+}
+
+// PluginMetric returns the description of a metric of an added plugin.
+func (sc *ScoringConfig) PluginMetric(name string) (plugin.Metric, bool) {
+	for _, p := range sc.plugins {
+		for _, m := range p.Category().Metrics {
+			if m.Name == name {
+				return m, true
+			}
+		}
+	}
+	return plugin.Metric{}, false
+}
+
+// describePlugin sets the display names and descriptions of a plugin
+// category's score, which the report renders in place of those of C1-C7.
+func describePlugin(cs *types.CategoryScore, p plugin.Plugin) {
+	c := p.Category()
+	cs.DisplayName, cs.Impact = c.Name, c.Impact
+	for i := range cs.SubScores {
+		ss := &cs.SubScores[i]
+		for _, m := range c.Metrics {
+			if m.Name == ss.MetricName {
+				ss.DisplayName, ss.Description = m.DisplayName, m.Description
+			}
+		}
+	}
+}
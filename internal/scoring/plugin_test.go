@@ -0,0 +1,66 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+type lintPlugin struct{}
+
+func (lintPlugin) Category() plugin.Category {
+	return plugin.Category{
+		ID:     "X1",
+		Name:   "Lint Hygiene",
+		Weight: 0.5,
+		Metrics: []plugin.Metric{
+			{Name: "lint_warnings", Weight: 1, Breakpoints: []plugin.Breakpoint{{Value: 0, Score: 10}, {Value: 100, Score: 1}}},
+		},
+	}
+}
+
+func (lintPlugin) Analyze(_ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	return plugin.Result("X1", plugin.Values{"lint_warnings": {Value: 50}}), nil
+}
+
+func TestAddPlugin(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AddPlugin(lintPlugin{})
+	cat := cfg.Category("X1")
+	if cat.Weight != 0.5 || len(cat.Metrics) != 1 || len(cat.Metrics[0].Breakpoints) != 2 {
+		t.Errorf("defaults not added: %+v", cat)
+	}
+
+	// A weight configured before the plugin was known is kept.
+	cfg = DefaultConfig()
+	cfg.Categories["X1"] = CategoryConfig{Weight: 0.2}
+	cfg.AddPlugin(lintPlugin{})
+	if cat := cfg.Category("X1"); cat.Weight != 0.2 || len(cat.Metrics) != 1 {
+		t.Errorf("configured weight overridden: %+v", cat)
+	}
+}
+
+func TestScore_PluginCategory(t *testing.T) {
+	cfg := &ScoringConfig{Categories: map[string]CategoryConfig{}, Tiers: defaultTiers()}
+	cfg.AddPlugin(lintPlugin{})
+	ar, _ := lintPlugin{}.Analyze(nil)
+
+	scored, err := (&Scorer{Config: cfg}).Score([]*types.AnalysisResult{ar})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scored.Categories) != 1 {
+		t.Fatalf("got %d categories, want 1", len(scored.Categories))
+	}
+	want := Interpolate(cfg.Category("X1").Metrics[0].Breakpoints, 50)
+	if got := scored.Categories[0].Score; got != want {
+		t.Errorf("X1 score = %v, want %v", got, want)
+	}
+	if c := scored.Categories[0]; c.DisplayName != "Lint Hygiene" {
+		t.Errorf("X1 display name = %q, want the plugin's", c.DisplayName)
+	}
+	if scored.Composite != want {
+		t.Errorf("composite = %v, want %v", scored.Composite, want)
+	}
+}
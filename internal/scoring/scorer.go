@@ -12,6 +12,10 @@
 package scoring
 
 import (
+	"maps"
+	"math"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/stats"
 	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
 )
 
@@ -42,6 +46,12 @@ var metricExtractors = map[string]metricExtractor{
 	"C7": extractC7,
 }
 
+// repeatExtractors maps category name to a function returning the raw metric
+// values of each repeated run, for categories measured more than once.
+var repeatExtractors = map[string]func(ar *types.AnalysisResult) []map[string]float64{
+	"C7": extractC7Repeats,
+}
+
 // defaultInterpolateScore is the fallback score when no breakpoints are defined.
 const defaultInterpolateScore = 5.0
 
@@ -139,45 +149,126 @@ func (s *Scorer) Score(results []*types.AnalysisResult) (*types.ScoredResult, er
 			continue
 		}
 
-		extractor, ok := metricExtractors[ar.Category]
+		extractor, ok := s.Config.lookupExtractor(ar.Category)
 		if !ok {
 			continue
 		}
 
 		rawValues, unavailable, evidence := extractor(ar)
 		if rawValues == nil {
-			categories = append(categories, types.CategoryScore{
+			cs := types.CategoryScore{
 				Name:   ar.Category,
 				Weight: catConfig.Weight,
-			})
+			}
+			if p, ok := s.Config.Plugin(ar.Category); ok {
+				describePlugin(&cs, p)
+			}
+			categories = append(categories, cs)
 			continue
 		}
 
 		subScores, score := scoreMetrics(catConfig, rawValues, unavailable, evidence)
-		categories = append(categories, types.CategoryScore{
+		cs := types.CategoryScore{
 			Name:      ar.Category,
 			Score:     score,
 			Weight:    catConfig.Weight,
 			SubScores: subScores,
-		})
+		}
+		if repeats, ok := repeatExtractors[ar.Category]; ok && score >= 0 {
+			scoreRepeats(&cs, catConfig, rawValues, repeats(ar), unavailable)
+		}
+		if p, ok := s.Config.Plugin(ar.Category); ok {
+			describePlugin(&cs, p)
+		}
+		categories = append(categories, cs)
 	}
 
 	composite := s.computeComposite(categories)
 	tier := s.classifyTier(composite)
 
 	return &types.ScoredResult{
-		Categories: categories,
-		Composite:  composite,
-		Tier:       tier,
+		Categories:        categories,
+		Composite:         composite,
+		Tier:              tier,
+		CompositeInterval: s.compositeInterval(categories, composite),
 	}, nil
 }
 
+// scoreRepeats sets the intervals of a category measured over repeated runs:
+// of each sub-score's raw value and of the category score, scored once per run
+// with that run's values in place of rawValues.
+func scoreRepeats(cs *types.CategoryScore, catConfig CategoryConfig, rawValues map[string]float64, runs []map[string]float64, unavailable map[string]bool) {
+	if len(runs) < 2 {
+		return
+	}
+	var scores []float64
+	for _, run := range runs {
+		values := maps.Clone(rawValues)
+		maps.Copy(values, run)
+		if _, score := scoreMetrics(catConfig, values, unavailable, nil); score >= 0 {
+			scores = append(scores, score)
+		}
+	}
+	cs.Interval = stats.Interval(scores)
+
+	for i := range cs.SubScores {
+		ss := &cs.SubScores[i]
+		if !ss.Available {
+			continue
+		}
+		var values []float64
+		for _, run := range runs {
+			if v, ok := run[ss.MetricName]; ok {
+				values = append(values, v)
+			}
+		}
+		ss.Interval = stats.Interval(values)
+	}
+}
+
+// compositeInterval propagates the category intervals into an uncertainty
+// band of the composite: the composite with every such category at its lower
+// and at its upper bound. The standard deviation assumes independent
+// categories. It returns nil if no category has an interval.
+func (s *Scorer) compositeInterval(categories []types.CategoryScore, composite float64) *types.ScoreInterval {
+	low := make([]types.CategoryScore, len(categories))
+	high := make([]types.CategoryScore, len(categories))
+	copy(low, categories)
+	copy(high, categories)
+
+	var runs int
+	var totalWeight, variance float64
+	for i, cat := range categories {
+		if cat.Score < 0 {
+			continue
+		}
+		totalWeight += cat.Weight
+		if cat.Interval == nil {
+			continue
+		}
+		runs = max(runs, cat.Interval.Runs)
+		low[i].Score = cat.Interval.Low
+		high[i].Score = cat.Interval.High
+		variance += cat.Weight * cat.Weight * cat.Interval.StdDev * cat.Interval.StdDev
+	}
+	if runs == 0 || totalWeight == 0 {
+		return nil
+	}
+	return &types.ScoreInterval{
+		Runs:   runs,
+		Mean:   composite,
+		StdDev: math.Sqrt(variance) / totalWeight,
+		Low:    s.computeComposite(low),
+		High:   s.computeComposite(high),
+	}
+}
+
 // scoreMetrics is a generic scoring helper for any category.
 func scoreMetrics(catConfig CategoryConfig, rawValues map[string]float64, unavailable map[string]bool, evidence map[string][]types.EvidenceItem) ([]types.SubScore, float64) {
 	var subScores []types.SubScore
 
 	for _, mt := range catConfig.Metrics {
-		rv := rawValues[mt.Name]
+		rv, measured := rawValues[mt.Name]
 		ev := evidence[mt.Name]
 		if ev == nil {
 			ev = make([]types.EvidenceItem, 0)
@@ -190,7 +281,9 @@ func scoreMetrics(catConfig CategoryConfig, rawValues map[string]float64, unavai
 			Evidence:   ev,
 		}
 
-		if unavailable[mt.Name] {
+		// Metrics the extractor did not report (e.g. custom C7 tasks that did
+		// not run) are unavailable rather than scored as zero.
+		if unavailable[mt.Name] || !measured {
 			ss.Available = false
 			ss.Score = 0
 		} else {
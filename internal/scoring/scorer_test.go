@@ -864,6 +864,8 @@ func TestExtractC7_ReturnsAllMetrics(t *testing.T) {
 				CrossFileNavigation:            6,
 				IdentifierInterpretability:     7,
 				DocumentationAccuracyDetection: 5,
+				ChangeSuccess:                  9,
+				FeatureLocalization:            8,
 			},
 		},
 	}
@@ -880,6 +882,8 @@ func TestExtractC7_ReturnsAllMetrics(t *testing.T) {
 		"cross_file_navigation",
 		"identifier_interpretability",
 		"documentation_accuracy_detection",
+		"change_success",
+		"feature_localization",
 	}
 
 	for _, key := range expectedKeys {
@@ -910,9 +914,9 @@ func TestExtractC7_ReturnsAllMetrics(t *testing.T) {
 		t.Errorf("documentation_accuracy_detection = %v, want 5.0", rawValues["documentation_accuracy_detection"])
 	}
 
-	// Verify count: exactly 5 keys
-	if len(rawValues) != 5 {
-		t.Errorf("expected 5 keys, got %d", len(rawValues))
+	// Verify count: exactly 7 keys
+	if len(rawValues) != 7 {
+		t.Errorf("expected 7 keys, got %d", len(rawValues))
 	}
 }
 
@@ -933,6 +937,8 @@ func TestExtractC7_UnavailableMarksAllMetrics(t *testing.T) {
 		"cross_file_navigation",
 		"identifier_interpretability",
 		"documentation_accuracy_detection",
+		"change_success",
+		"feature_localization",
 	}
 
 	for _, key := range expectedUnavailable {
@@ -941,9 +947,197 @@ func TestExtractC7_UnavailableMarksAllMetrics(t *testing.T) {
 		}
 	}
 
-	// Verify count: exactly 5 unavailable
-	if len(unavailable) != 5 {
-		t.Errorf("expected 5 unavailable metrics, got %d", len(unavailable))
+	// Verify count: exactly 7 unavailable
+	if len(unavailable) != 7 {
+		t.Errorf("expected 7 unavailable metrics, got %d", len(unavailable))
+	}
+}
+
+func TestExtractC7_ChangeSuccessNotRun(t *testing.T) {
+	ar := &types.AnalysisResult{
+		Metrics: map[string]types.CategoryMetrics{
+			"c7": &types.C7Metrics{
+				Available:                      true,
+				TaskExecutionConsistency:       8,
+				CodeBehaviorComprehension:      7,
+				CrossFileNavigation:            6,
+				IdentifierInterpretability:     7,
+				DocumentationAccuracyDetection: 5,
+				FeatureLocalization:            6,
+			},
+		},
+	}
+
+	_, unavailable, _ := extractC7(ar)
+
+	// A change task that could not run is not scored as a failure
+	if len(unavailable) != 1 || !unavailable["change_success"] {
+		t.Errorf("unavailable = %v, want only change_success", unavailable)
+	}
+}
+
+func TestScoreC7_CustomTasks(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.AddC7Metric("add_migration", 0.10)
+	cfg.AddC7Metric("add_migration", 0.50) // already configured: kept
+	cfg.AddC7Metric("review_api", 0.10)
+
+	var added []MetricThresholds
+	for _, mt := range cfg.Categories["C7"].Metrics {
+		if mt.Name == "add_migration" || mt.Name == "review_api" {
+			added = append(added, mt)
+		}
+	}
+	if len(added) != 2 || added[0].Weight != 0.10 {
+		t.Fatalf("AddC7Metric() added %+v, want two tasks with weight 0.10", added)
+	}
+
+	s := &Scorer{Config: cfg}
+	ar := &types.AnalysisResult{
+		Category: "C7",
+		Metrics: map[string]types.CategoryMetrics{
+			"c7": &types.C7Metrics{
+				Available:                 true,
+				CodeBehaviorComprehension: 7,
+				MetricResults: []types.C7MetricResult{
+					{MetricID: "code_behavior_comprehension", Score: 7},
+					{MetricID: "add_migration", Score: 9},
+				},
+			},
+		},
+	}
+	result, err := s.Score([]*types.AnalysisResult{ar})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subScores := make(map[string]types.SubScore)
+	for _, ss := range result.Categories[0].SubScores {
+		subScores[ss.MetricName] = ss
+	}
+	if ss := subScores["add_migration"]; !ss.Available || ss.RawValue != 9 {
+		t.Errorf("add_migration = %+v, want available with raw value 9", ss)
+	}
+	// A configured task that did not run is unavailable, not a zero score
+	if ss := subScores["review_api"]; ss.Available {
+		t.Errorf("review_api = %+v, want unavailable", ss)
+	}
+}
+
+func TestScoreC7_BudgetSkippedMetric(t *testing.T) {
+	s := &Scorer{Config: DefaultConfig()}
+	ar := &types.AnalysisResult{
+		Category: "C7",
+		Metrics: map[string]types.CategoryMetrics{
+			"c7": &types.C7Metrics{
+				Available:                 true,
+				CodeBehaviorComprehension: 7,
+				ChangeSuccess:             6,
+				MetricResults: []types.C7MetricResult{
+					{MetricID: "code_behavior_comprehension", Score: 7, Status: "completed"},
+					{MetricID: "cross_file_navigation", Status: "skipped", Reasoning: "skipped: LLM budget exhausted"},
+				},
+			},
+		},
+	}
+	result, err := s.Score([]*types.AnalysisResult{ar})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, ss := range result.Categories[0].SubScores {
+		if ss.MetricName != "cross_file_navigation" {
+			continue
+		}
+		if ss.Available {
+			t.Errorf("skipped metric is available: %+v", ss)
+		}
+		if len(ss.Evidence) != 1 || ss.Evidence[0].Description != "skipped: LLM budget exhausted" {
+			t.Errorf("evidence = %+v, want the skip reason", ss.Evidence)
+		}
+	}
+}
+
+func TestExtractC7Repeats_FailedVersusZero(t *testing.T) {
+	ar := &types.AnalysisResult{
+		Category: "C7",
+		Metrics: map[string]types.CategoryMetrics{
+			"c7": &types.C7Metrics{
+				Available: true,
+				Repeats:   2,
+				MetricResults: []types.C7MetricResult{
+					{MetricID: "code_behavior_comprehension", RepeatScores: []int{types.FailedRepeat, 6}},
+					{MetricID: "cross_file_navigation", RepeatScores: []int{0, 4}},
+				},
+			},
+		},
+	}
+	runs := extractC7Repeats(ar)
+	if len(runs) != 2 {
+		t.Fatalf("runs = %v, want 2", runs)
+	}
+	if _, ok := runs[0]["code_behavior_comprehension"]; ok {
+		t.Error("failed repeat should be left out")
+	}
+	if v, ok := runs[0]["cross_file_navigation"]; !ok || v != 0 {
+		t.Errorf("repeat scoring 0 = %v, %v; want 0, true", v, ok)
+	}
+}
+
+func TestScoreC7_Repeats(t *testing.T) {
+	s := &Scorer{Config: DefaultConfig()}
+	c7 := &types.AnalysisResult{
+		Category: "C7",
+		Metrics: map[string]types.CategoryMetrics{
+			"c7": &types.C7Metrics{
+				Available:                 true,
+				Repeats:                   3,
+				CodeBehaviorComprehension: 7,
+				CrossFileNavigation:       5,
+				MetricResults: []types.C7MetricResult{
+					{MetricID: "code_behavior_comprehension", Score: 7, RepeatScores: []int{6, 7, 9},
+						Interval: &types.ScoreInterval{Runs: 3, Mean: 22.0 / 3}},
+					{MetricID: "cross_file_navigation", Score: 5, RepeatScores: []int{5, 5, 5},
+						Interval: &types.ScoreInterval{Runs: 3, Mean: 5}},
+				},
+			},
+		},
+	}
+	c6 := &types.AnalysisResult{
+		Category: "C6",
+		Metrics:  map[string]types.CategoryMetrics{"c6": &types.C6Metrics{TestToCodeRatio: 0.8, CoveragePercent: -1}},
+	}
+	result, err := s.Score([]*types.AnalysisResult{c6, c7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cat := result.Categories[1]
+	if cat.Interval == nil || cat.Interval.Runs != 3 {
+		t.Fatalf("C7 interval = %+v, want 3 runs", cat.Interval)
+	}
+	if cat.Interval.Low > cat.Score || cat.Interval.High < cat.Score || cat.Interval.Low == cat.Interval.High {
+		t.Errorf("C7 interval [%.2f, %.2f] should bracket the score %.2f", cat.Interval.Low, cat.Interval.High, cat.Score)
+	}
+	for _, ss := range cat.SubScores {
+		switch ss.MetricName {
+		case "code_behavior_comprehension":
+			if math.Abs(ss.RawValue-22.0/3) > 1e-9 || ss.Interval == nil || ss.Interval.StdDev == 0 {
+				t.Errorf("M2 raw %.3f, interval %+v; want the mean 7.33 with a spread", ss.RawValue, ss.Interval)
+			}
+		case "identifier_interpretability":
+			if ss.Interval != nil {
+				t.Errorf("unmeasured metric has an interval: %+v", ss.Interval)
+			}
+		}
+	}
+
+	band := result.CompositeInterval
+	if band == nil || band.Mean != result.Composite || band.Low > result.Composite || band.High < result.Composite {
+		t.Fatalf("composite %.2f, band %+v; want a band around the composite", result.Composite, band)
+	}
+	if result.Categories[0].Interval != nil {
+		t.Errorf("C6 has an interval without repeats: %+v", result.Categories[0].Interval)
 	}
 }
 
@@ -961,6 +1155,8 @@ func TestScoreC7_NonZeroSubScores(t *testing.T) {
 				CrossFileNavigation:            6,
 				IdentifierInterpretability:     7,
 				DocumentationAccuracyDetection: 5,
+				ChangeSuccess:                  9,
+				FeatureLocalization:            8,
 			},
 		},
 	}
@@ -972,19 +1168,19 @@ func TestScoreC7_NonZeroSubScores(t *testing.T) {
 	if got.Weight != 0.10 {
 		t.Errorf("weight = %v, want 0.10", got.Weight)
 	}
-	if len(got.SubScores) != 5 {
-		t.Fatalf("subscore count = %d, want 5", len(got.SubScores))
+	if len(got.SubScores) != 7 {
+		t.Fatalf("subscore count = %d, want 7", len(got.SubScores))
 	}
 
-	// All 5 MECE metrics should produce non-zero scores
+	// All 7 MECE metrics should produce non-zero scores
 	nonZero := 0
 	for _, ss := range got.SubScores {
 		if ss.Score > 0 {
 			nonZero++
 		}
 	}
-	if nonZero != 5 {
-		t.Errorf("expected 5 non-zero sub-scores, got %d", nonZero)
+	if nonZero != 7 {
+		t.Errorf("expected 7 non-zero sub-scores, got %d", nonZero)
 	}
 
 	// Category score should be non-zero (this was the original bug)
@@ -992,7 +1188,7 @@ func TestScoreC7_NonZeroSubScores(t *testing.T) {
 		t.Errorf("C7 category score = %v, want > 0 (was the original bug)", got.Score)
 	}
 
-	// With values 5-8, score should be reasonable (not near zero or max)
+	// With values 5-9, score should be reasonable (not near zero or max)
 	if got.Score < 4.0 || got.Score > 9.0 {
 		t.Errorf("C7 score = %v, want between 4.0 and 9.0 for mid-range inputs", got.Score)
 	}
@@ -1176,13 +1372,15 @@ func TestExtractEvidence_AllCategories(t *testing.T) {
 						CrossFileNavigation:            5,
 						IdentifierInterpretability:     8,
 						DocumentationAccuracyDetection: 4,
+						ChangeSuccess:                  6,
+						FeatureLocalization:            7,
 					},
 				},
 			},
 			// C7 is score-based: all evidence arrays are present but empty
 			nonEmptyMetrics: []string{},
-			emptyMetrics:    []string{"task_execution_consistency", "code_behavior_comprehension", "cross_file_navigation", "identifier_interpretability", "documentation_accuracy_detection"},
-			totalKeys:       5,
+			emptyMetrics:    []string{"task_execution_consistency", "code_behavior_comprehension", "cross_file_navigation", "identifier_interpretability", "documentation_accuracy_detection", "change_success", "feature_localization"},
+			totalKeys:       7,
 		},
 	}
 
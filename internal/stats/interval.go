@@ -0,0 +1,77 @@
+// Package stats summarizes scores measured over repeated runs, such as C7's
+// agent metrics with --c7-repeats, so that a change in score can be told
+// apart from run-to-run noise.
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Bootstrap parameters.
+const (
+	bootstrapResamples  = 2000
+	confidenceLevel     = 0.95
+	bootstrapSeedStream = 0x41525321 // fixed, so the same runs give the same interval
+)
+
+// Interval returns the mean, sample standard deviation and 95% percentile
+// bootstrap confidence interval of the mean of values. It returns nil for
+// fewer than two values, which leave no spread to estimate.
+func Interval(values []float64) *types.ScoreInterval {
+	if len(values) < 2 {
+		return nil
+	}
+	mean := Mean(values)
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(len(values)), bootstrapSeedStream))
+	means := make([]float64, bootstrapResamples)
+	for i := range means {
+		var sum float64
+		for range values {
+			sum += values[rng.IntN(len(values))]
+		}
+		means[i] = sum / float64(len(values))
+	}
+	sort.Float64s(means)
+
+	alpha := (1 - confidenceLevel) / 2
+	return &types.ScoreInterval{
+		Runs:   len(values),
+		Mean:   mean,
+		StdDev: math.Sqrt(ss / float64(len(values)-1)),
+		Low:    percentile(means, alpha),
+		High:   percentile(means, 1-alpha),
+	}
+}
+
+// Mean returns the arithmetic mean of values, or 0 for none.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// percentile returns the p-quantile (0-1) of sorted by nearest rank.
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(float64(len(sorted))*p)) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
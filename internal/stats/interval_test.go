@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterval(t *testing.T) {
+	if got := Interval([]float64{7}); got != nil {
+		t.Errorf("Interval(one value) = %+v, want nil", got)
+	}
+
+	got := Interval([]float64{6, 7, 8, 7, 7})
+	if got.Runs != 5 || math.Abs(got.Mean-7) > 1e-9 {
+		t.Errorf("Runs, Mean = %d, %.2f, want 5, 7", got.Runs, got.Mean)
+	}
+	if want := math.Sqrt(0.5); math.Abs(got.StdDev-want) > 1e-9 {
+		t.Errorf("StdDev = %.4f, want %.4f", got.StdDev, want)
+	}
+	if got.Low > got.Mean || got.High < got.Mean || got.Low < 6 || got.High > 8 {
+		t.Errorf("interval [%.2f, %.2f] does not bracket the mean within the data range", got.Low, got.High)
+	}
+	if again := Interval([]float64{6, 7, 8, 7, 7}); *again != *got {
+		t.Errorf("Interval is not deterministic: %+v vs %+v", again, got)
+	}
+
+	same := Interval([]float64{5, 5, 5})
+	if same.StdDev != 0 || same.Low != 5 || same.High != 5 {
+		t.Errorf("constant runs: %+v, want a zero-width interval at 5", same)
+	}
+}
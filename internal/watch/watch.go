@@ -0,0 +1,127 @@
+// Package watch delivers debounced batches of file changes under a project
+// directory. fsnotify watches single directories, so the watcher registers every
+// directory the discovery walker would visit and adds new ones as they appear.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+)
+
+// DefaultDebounce is how long the watcher waits after the last event before
+// delivering a batch. Editors and git write several files per save or commit.
+const DefaultDebounce = 300 * time.Millisecond
+
+// Watcher watches a directory tree and reports changed files in batches.
+type Watcher struct {
+	root     string
+	debounce time.Duration
+	walker   *discovery.Walker
+	fsw      *fsnotify.Watcher
+}
+
+// New creates a Watcher for root. It registers every directory walker walks,
+// so that include and exclude globs and .arsignore rules apply, plus
+// .git/logs, whose HEAD file is appended on every commit and checkout. A nil
+// walker walks the default directories.
+func New(root string, debounce time.Duration, walker *discovery.Walker) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if walker == nil {
+		walker = discovery.NewWalker()
+	}
+	w := &Watcher{root: root, debounce: debounce, walker: walker, fsw: fsw}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	gitLogs := filepath.Join(root, ".git", "logs")
+	if info, err := os.Stat(gitLogs); err == nil && info.IsDir() {
+		_ = fsw.Add(gitLogs) // optional: without it C5 is only computed once
+	}
+	return w, nil
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// addTree registers dir and all of its subdirectories that discovery would visit.
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			return nil // unreadable subdirectory: skip it, as discovery does
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && !w.walker.WalksDir(w.root, path) {
+			return fs.SkipDir
+		}
+		return w.fsw.Add(path)
+	})
+}
+
+// Run delivers batches of changed absolute paths to onBatch until ctx is cancelled
+// or the watcher fails. Paths in a batch are sorted and unique. onBatch runs on the
+// watcher goroutine; events arriving meanwhile are collected into the next batch.
+func (w *Watcher) Run(ctx context.Context, onBatch func(paths []string)) error {
+	pending := make(map[string]bool)
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue // permission/timestamp-only changes never affect analysis
+			}
+			if ev.Has(fsnotify.Create) {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() && w.walker.WalksDir(w.root, ev.Name) {
+					_ = w.addTree(ev.Name)
+				}
+			}
+			pending[ev.Name] = true
+			timer.Reset(w.debounce)
+
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			paths := make([]string, 0, len(pending))
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			onBatch(paths)
+		}
+	}
+}
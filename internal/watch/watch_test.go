@@ -0,0 +1,130 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/discovery"
+)
+
+// collect runs the watcher and returns a channel receiving each batch.
+func collect(t *testing.T, root string, walker *discovery.Walker) chan []string {
+	t.Helper()
+	w, err := New(root, 50*time.Millisecond, walker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []string, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = w.Run(ctx, func(paths []string) { batches <- paths })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		w.Close()
+	})
+	return batches
+}
+
+func waitFor(t *testing.T, batches chan []string, want string) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case batch := <-batches:
+			for _, p := range batch {
+				if p == want {
+					return
+				}
+			}
+		case <-deadline:
+			t.Fatalf("no batch containing %s", want)
+		}
+	}
+}
+
+func TestWatcher_ReportsChangedFiles(t *testing.T) {
+	root := t.TempDir()
+	batches := collect(t, root, nil)
+
+	path := filepath.Join(root, "main.go")
+	if err := os.WriteFile(path, []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, batches, path)
+}
+
+func TestWatcher_WatchesNewDirectories(t *testing.T) {
+	root := t.TempDir()
+	batches := collect(t, root, nil)
+
+	dir := filepath.Join(root, "pkg")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, batches, dir)
+
+	path := filepath.Join(dir, "pkg.go")
+	if err := os.WriteFile(path, []byte("package pkg\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, batches, path)
+}
+
+func TestWatcher_SkipsIgnoredDirectories(t *testing.T) {
+	root := t.TempDir()
+	ignored := filepath.Join(root, "node_modules")
+	if err := os.Mkdir(ignored, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w, err := New(root, DefaultDebounce, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	for _, p := range w.fsw.WatchList() {
+		if p == ignored {
+			t.Error("node_modules should not be watched")
+		}
+	}
+}
+
+func TestWatcher_FollowsPathRules(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"build/gen", "docs"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	walker := discovery.NewWalker()
+	walker.SetPathRules(discovery.PathRules{Include: []string{"build/gen/**"}, Exclude: []string{"docs"}})
+	w, err := New(root, DefaultDebounce, walker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	watched := make(map[string]bool)
+	for _, p := range w.fsw.WatchList() {
+		watched[p] = true
+	}
+	if !watched[filepath.Join(root, "build", "gen")] {
+		t.Error("included build/gen should be watched")
+	}
+	if watched[filepath.Join(root, "docs")] {
+		t.Error("excluded docs should not be watched")
+	}
+
+	batches := collect(t, root, walker)
+	path := filepath.Join(root, "build", "gen", "gen.go")
+	if err := os.WriteFile(path, []byte("package gen\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, batches, path)
+}
@@ -0,0 +1,356 @@
+// Package ars exposes the agent-readiness scanner as a Go library.
+//
+// Scan runs the same analysis as "ars scan" and returns the scores,
+// recommendations and per-category analysis results as values instead of
+// printing them:
+//
+//	report, err := ars.Scan(ctx, "./myproject")
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Printf("%.1f %s\n", report.Composite, report.Tier)
+//
+// Nothing is written to stdout or stderr unless requested with WithOutput.
+// Categories added with pkg/plugin are scanned and scored like the built-in
+// ones. External plugins declared in a project's .arsrc.yml are commands from
+// the scanned project; they run only with WithPlugins, and only for that scan.
+//
+// # Compatibility
+//
+// The exported API of this package and of pkg/types follows semantic
+// versioning: within a major version, exported identifiers are neither
+// removed nor changed incompatibly. New options, Report fields and metric
+// fields may be added in minor releases, so do not rely on struct literal
+// positions or on the exact set of categories and metrics. Scores of the same
+// project can change between releases as analyzers and scoring breakpoints
+// are refined.
+package ars
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ingo-eichhorst/agent-readyness/internal/agent"
+	"github.com/ingo-eichhorst/agent-readyness/internal/cache"
+	"github.com/ingo-eichhorst/agent-readyness/internal/config"
+	"github.com/ingo-eichhorst/agent-readyness/internal/pipeline"
+	"github.com/ingo-eichhorst/agent-readyness/internal/scoring"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/plugin"
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Format selects how WithOutput renders a report.
+type Format string
+
+// Supported output formats.
+const (
+	FormatText Format = "text" // terminal report, as printed by "ars scan"
+	FormatJSON Format = "json" // JSON report, as printed by "ars scan --json"
+	FormatHTML Format = "html" // self-contained HTML report
+)
+
+// Recommendation is a ranked improvement suggestion.
+type Recommendation struct {
+	Rank             int     // 1-based rank
+	Category         string  // e.g., "C1"
+	MetricName       string  // e.g., "complexity_avg"
+	CurrentValue     float64 // raw metric value
+	CurrentScore     float64 // 1-10 metric score
+	TargetValue      float64 // next breakpoint value that improves score
+	TargetScore      float64 // what metric score would be at target
+	ScoreImprovement float64 // estimated composite improvement
+	Effort           string  // "Low", "Medium", "High"
+	Summary          string  // agent-readiness framed description
+	Action           string  // concrete improvement action
+}
+
+// Report is the result of a scan.
+type Report struct {
+	ProjectName     string                  // basename of the scanned directory
+	Composite       float64                 // weighted composite score (1-10)
+	Tier            string                  // tier classification (e.g., "Agent-Ready")
+	Categories      []types.CategoryScore   // per-category scores with sub-scores
+	Recommendations []Recommendation        // ranked improvements, best first
+	Files           *types.ScanResult       // discovered and classified files
+	Results         []*types.AnalysisResult // raw per-category analyzer output
+	Warnings        []string                // non-fatal problems (e.g., an analyzer failed)
+	LLMBudget       *types.LLMBudget        // LLM spend and cuts; nil without WithLLMBudget
+
+	CompositeInterval *types.ScoreInterval // composite uncertainty band with WithC7Repeats; nil otherwise
+	Interrupted       []string             // categories cut short by a cancelled ctx; nil for a complete scan
+	Diagnostics       []types.Diagnostic   // problems met during the scan, most severe first
+	Modules           []types.ModuleScore  // monorepo module scores; nil for a single-module project
+
+	p   *pipeline.Pipeline
+	res *pipeline.Result
+}
+
+// Option configures Scan.
+type Option func(*options)
+
+type options struct {
+	llm           bool
+	scoringConfig string
+	projectConfig string
+	progress      func(stage, detail string)
+	events        func(types.Event)
+	verbose       bool
+	cacheDir      string
+	budgetUSD     float64
+	budgetTokens  int
+	c7Repeats     int
+	plugins       bool
+	outputs       []output
+}
+
+type output struct {
+	w      io.Writer
+	format Format
+}
+
+// WithLLM enables the LLM-backed features (C4 content quality and C7 agent
+// evaluation) when the Claude CLI is installed. C7 uses the agent backend and
+// C4 the judge selected in .arsrc.yml, if any. They are disabled by default
+// because they are slow and incur API costs.
+func WithLLM(enabled bool) Option {
+	return func(o *options) { o.llm = enabled }
+}
+
+// WithScoringConfigFile loads scoring weights and breakpoints from a YAML file
+// instead of the built-in defaults.
+func WithScoringConfigFile(path string) Option {
+	return func(o *options) { o.scoringConfig = path }
+}
+
+// WithProjectConfigFile loads the project configuration (.arsrc.yml format)
+// from path. By default .arsrc.yml or .arsrc.yaml in the scanned directory is
+// used if present.
+func WithProjectConfigFile(path string) Option {
+	return func(o *options) { o.projectConfig = path }
+}
+
+// WithProgress registers a callback invoked at the start of each stage
+// ("discover", "parse", "analyze", "score").
+func WithProgress(fn func(stage, detail string)) Option {
+	return func(o *options) { o.progress = fn }
+}
+
+// WithEvents streams the scan's progress to fn as it happens: stage and
+// analyzer start and end with durations, C7 metric and sample progress, LLM
+// token counts, diagnostics and a final EventSummary. fn may be called
+// concurrently. Use it instead of WithProgress for progress bars.
+func WithEvents(fn func(types.Event)) Option {
+	return func(o *options) { o.events = fn }
+}
+
+// WithVerbose includes per-metric details in text and JSON output.
+func WithVerbose(verbose bool) Option {
+	return func(o *options) { o.verbose = verbose }
+}
+
+// WithCache reuses per-file analysis results and, with WithLLM, LLM responses
+// stored under dir, so unchanged files are not re-analyzed or re-sent to the
+// agent by later scans. Caching is disabled by default.
+func WithCache(dir string) Option {
+	return func(o *options) { o.cacheDir = dir }
+}
+
+// WithLLMBudget caps the LLM spend of a scan with WithLLM at usd dollars and
+// tokens tokens; zero means no limit. C7 evaluates fewer samples or skips
+// metrics to stay within it, which Report.LLMBudget records.
+func WithLLMBudget(usd float64, tokens int) Option {
+	return func(o *options) {
+		o.budgetUSD = usd
+		o.budgetTokens = tokens
+	}
+}
+
+// WithC7Repeats runs every C7 metric's samples n times in a scan with
+// WithLLM, so that the C7 scores carry confidence intervals and
+// Report.CompositeInterval an uncertainty band. n <= 1 runs them once.
+func WithC7Repeats(n int) Option {
+	return func(o *options) { o.c7Repeats = n }
+}
+
+// WithPlugins runs the external plugin commands declared under "plugins" in
+// the project's .arsrc.yml. Only enable it for trusted projects: the commands
+// come from the scanned directory. Without it they are ignored.
+func WithPlugins(enabled bool) Option {
+	return func(o *options) { o.plugins = enabled }
+}
+
+// WithOutput renders the report to w in the given format once the scan
+// completes. It may be given several times.
+func WithOutput(w io.Writer, format Format) Option {
+	return func(o *options) { o.outputs = append(o.outputs, output{w: w, format: format}) }
+}
+
+// Scan analyzes the project in dir and returns its scored report.
+// A scan cancelled before its analyzers start returns ctx.Err(). Once they
+// run, the report is partial: Scan returns it together with ctx.Err(), with
+// the categories cut short in Report.Interrupted.
+func Scan(ctx context.Context, dir string, opts ...Option) (*Report, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for _, out := range o.outputs {
+		switch out.format {
+		case FormatText, FormatJSON, FormatHTML:
+		default:
+			return nil, fmt.Errorf("unknown output format %q", out.format)
+		}
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve path: %w", err)
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("cannot access %s: %w", dir, err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	cfg, err := scoring.LoadConfig(o.scoringConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load scoring config: %w", err)
+	}
+	projectCfg, err := config.LoadProjectConfig(dir, o.projectConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load project config: %w", err)
+	}
+	if projectCfg != nil {
+		projectCfg.ApplyToScoringConfig(cfg)
+	}
+	var plugins []plugin.Plugin
+	if o.plugins {
+		if plugins, err = projectCfg.LoadPlugins(dir); err != nil {
+			return nil, fmt.Errorf("load plugins: %w", err)
+		}
+	}
+
+	p := pipeline.New(io.Discard, o.verbose, cfg, 0, false, o.progress)
+	p.SetPlugins(plugins)
+	p.SetPathRules(projectCfg.PathRules())
+	p.SetGeneratedRules(projectCfg.GeneratedRules())
+	if o.events != nil {
+		p.SetEvents(o.events)
+	}
+	if !o.llm {
+		p.DisableLLM()
+	} else {
+		backend, err := projectCfg.AgentBackend()
+		if err != nil {
+			return nil, fmt.Errorf("configure agent backend: %w", err)
+		}
+		if backend != nil {
+			p.SetAgentBackend(backend)
+		}
+		c7Opts, err := projectCfg.C7MetricOptions(dir)
+		if err != nil {
+			return nil, fmt.Errorf("load C7 tasks: %w", err)
+		}
+		c7Opts.Repeats = o.c7Repeats
+		p.SetC7MetricOptions(c7Opts)
+		p.SetAgentLimits(projectCfg.AgentLimits())
+		judge, err := projectCfg.C4Judge()
+		if err != nil {
+			return nil, fmt.Errorf("configure judge: %w", err)
+		}
+		if judge != nil {
+			p.SetJudge(judge)
+		}
+		if o.budgetUSD > 0 || o.budgetTokens > 0 {
+			budget := agent.NewBudget(o.budgetUSD, o.budgetTokens, projectCfg.LLMPrices())
+			if err := budget.CheckPrices(projectCfg.PricedModels()...); err != nil {
+				return nil, fmt.Errorf("LLM budget: %w", err)
+			}
+			p.SetLLMBudget(budget)
+		}
+	}
+	if o.cacheDir != "" {
+		p.SetCache(cache.Open(o.cacheDir, projectCfg.CacheKey()))
+		if o.llm {
+			ttl, maxBytes := projectCfg.LLMCacheLimits()
+			p.SetLLMCache(cache.OpenLLM(o.cacheDir, cache.ModeReadWrite, ttl, maxBytes))
+		}
+	}
+
+	res, err := p.Analyze(ctx, dir)
+	if res == nil {
+		p.EmitSummary(nil, err)
+		return nil, err
+	}
+	if res.Scored == nil {
+		if err == nil {
+			err = fmt.Errorf("score %s: %s", dir, strings.Join(res.Warnings, "; "))
+		}
+		p.EmitSummary(res, err)
+		return nil, err
+	}
+
+	report := newReport(p, res)
+	for _, out := range o.outputs {
+		if writeErr := report.Write(out.w, out.format); writeErr != nil {
+			p.EmitSummary(res, writeErr)
+			return report, writeErr
+		}
+	}
+	p.EmitSummary(res, err)
+	return report, err
+}
+
+func newReport(p *pipeline.Pipeline, res *pipeline.Result) *Report {
+	r := &Report{
+		ProjectName: res.Scored.ProjectName,
+		Composite:   res.Scored.Composite,
+		Tier:        res.Scored.Tier,
+		Categories:  res.Scored.Categories,
+		Files:       res.Scan,
+		Results:     res.Analyses,
+		Warnings:    res.Warnings,
+		LLMBudget:   res.Scored.LLMBudget,
+		p:           p,
+		res:         res,
+
+		CompositeInterval: res.Scored.CompositeInterval,
+		Interrupted:       res.Interrupted,
+		Diagnostics:       res.Diagnostics,
+		Modules:           res.Scored.Modules,
+	}
+	for _, rec := range res.Recommendations {
+		r.Recommendations = append(r.Recommendations, Recommendation(rec))
+	}
+	return r
+}
+
+// Category returns the score of the named category (e.g., "C1"), or false if
+// it was not scored.
+func (r *Report) Category(name string) (types.CategoryScore, bool) {
+	for _, c := range r.Categories {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return types.CategoryScore{}, false
+}
+
+// Write renders the report to w in the given format.
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatText:
+		r.p.WriteText(w, r.res)
+		return nil
+	case FormatJSON:
+		return r.p.WriteJSON(w, r.res)
+	case FormatHTML:
+		return r.p.WriteHTML(w, r.res, nil)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
@@ -0,0 +1,152 @@
+package ars
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+const validGoProject = "../../testdata/valid-go-project"
+
+func TestScan_OutputsAndProgress(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	var stages []string
+	var mu sync.Mutex
+	var events []types.Event
+	report, err := Scan(context.Background(), validGoProject,
+		WithOutput(&jsonBuf, FormatJSON),
+		WithOutput(&textBuf, FormatText),
+		WithProgress(func(stage, _ string) { stages = append(stages, stage) }),
+		WithEvents(func(e types.Event) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	var decoded struct {
+		CompositeScore float64 `json:"composite_score"`
+		Tier           string  `json:"tier"`
+	}
+	if err := json.Unmarshal(jsonBuf.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON output does not parse: %v", err)
+	}
+	if decoded.CompositeScore != report.Composite || decoded.Tier != report.Tier {
+		t.Errorf("JSON output = %.2f %q, report = %.2f %q", decoded.CompositeScore, decoded.Tier, report.Composite, report.Tier)
+	}
+	if !strings.Contains(textBuf.String(), report.Tier) {
+		t.Errorf("text output does not mention tier %q", report.Tier)
+	}
+
+	if got := strings.Join(stages, ","); got != "discover,parse,analyze,score" {
+		t.Errorf("progress stages = %s", got)
+	}
+	if len(events) == 0 || events[len(events)-1].Summary == nil || events[len(events)-1].Summary.Composite != report.Composite {
+		t.Errorf("last event should summarize the report, got %d events", len(events))
+	}
+	if len(report.Results) == 0 || report.Files == nil || report.Files.TotalFiles == 0 {
+		t.Errorf("report lacks analysis results or files: %d results", len(report.Results))
+	}
+	if _, ok := report.Category("C1"); !ok {
+		t.Error("Category(C1) not found")
+	}
+	for i, rec := range report.Recommendations {
+		if rec.Rank != i+1 {
+			t.Errorf("recommendation %d has rank %d", i, rec.Rank)
+		}
+	}
+}
+
+func TestScan_Errors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Scan(ctx, validGoProject); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled scan: err = %v, want context.Canceled", err)
+	}
+
+	if _, err := Scan(context.Background(), validGoProject, WithOutput(&bytes.Buffer{}, "yaml")); err == nil {
+		t.Error("unknown output format: expected error")
+	}
+	if _, err := Scan(context.Background(), validGoProject+"/main.go"); err == nil {
+		t.Error("file instead of directory: expected error")
+	}
+	if _, err := Scan(context.Background(), validGoProject, WithScoringConfigFile("/nonexistent/scoring.yml")); err == nil {
+		t.Error("missing scoring config: expected error")
+	}
+}
+
+func TestScan_PluginsRequireOptIn(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	script := filepath.Join(dir, "plugin.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ntouch "+marker+"\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "arsrc.yml")
+	if err := os.WriteFile(cfgPath, []byte("plugins:\n  - command: "+script+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Scan(context.Background(), validGoProject, WithProjectConfigFile(cfgPath)); err != nil {
+		t.Fatalf("Scan without WithPlugins: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("plugin command ran without WithPlugins")
+	}
+
+	if _, err := Scan(context.Background(), validGoProject, WithProjectConfigFile(cfgPath), WithPlugins(true)); err == nil {
+		t.Error("failing plugin with WithPlugins: expected error")
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("plugin command did not run with WithPlugins")
+	}
+}
+
+func TestScan_C7WritesNothingToStderr(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "arsrc.yml")
+	cfg := `agent:
+  backend: command
+  command: ["sh", "-c", "echo The function returns an error when the input is empty.", "{prompt}"]
+judge:
+  backend: command
+  command: ["sh", "-c", "cat >/dev/null; echo '{\"score\": 6, \"reason\": \"fixed\"}'"]
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = stderr
+	report, err := Scan(context.Background(), validGoProject, WithProjectConfigFile(cfgPath), WithLLM(true))
+	os.Stderr = orig
+	stderr.Close()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if c7, ok := report.Category("C7"); !ok || c7.Score == 0 {
+		t.Errorf("C7 = %+v, want it evaluated by the command backend", c7)
+	}
+	written, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) > 0 {
+		t.Errorf("Scan wrote to stderr:\n%s", written)
+	}
+}
@@ -0,0 +1,63 @@
+package ars_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/ars"
+)
+
+func ExampleScan() {
+	report, err := ars.Scan(context.Background(), "../../testdata/valid-go-project")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(report.ProjectName)
+	for _, c := range report.Categories {
+		if c.Score < 0 {
+			fmt.Printf("%s n/a\n", c.Name)
+			continue
+		}
+		fmt.Printf("%s %.1f\n", c.Name, c.Score)
+	}
+	// Output:
+	// valid-go-project
+	// C1 9.9
+	// C2 10.0
+	// C3 9.9
+	// C4 2.0
+	// C5 n/a
+	// C6 6.4
+	// C7 n/a
+}
+
+func ExampleScan_jsonOutput() {
+	// Render the same JSON report as "ars scan --json" to stdout.
+	_, err := ars.Scan(context.Background(), ".",
+		ars.WithOutput(os.Stdout, ars.FormatJSON),
+		ars.WithProgress(func(stage, detail string) {
+			fmt.Fprintln(os.Stderr, detail)
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleReport_Category() {
+	report, err := ars.Scan(context.Background(), "../../testdata/valid-go-project")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if c6, ok := report.Category("C6"); ok {
+		for _, s := range c6.SubScores {
+			if s.Available {
+				fmt.Printf("%s: %.1f\n", s.MetricName, s.Score)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// ProtocolVersion is the version of the external plugin protocol.
+//
+// An external plugin is an executable that reads one JSON request from stdin,
+// writes one JSON response to stdout and exits. It is started twice per scan:
+//
+//	{"protocol": 1, "method": "describe"}
+//
+// must be answered with a Category object, and
+//
+//	{"protocol": 1, "method": "analyze", "root_dir": "/abs/project",
+//	 "targets": [{"language": "go", "files": [{"path": "/abs/project/main.go",
+//	              "rel_path": "main.go", "class": "source", "lines": 42}]}]}
+//
+// with {"metrics": {"<metric name>": {"value": 3, "evidence": [...]}}}. Metrics
+// missing from the response are reported as unavailable. A response of
+// {"error": "..."} or a non-zero exit status fails the plugin's analysis; stderr
+// is included in the error.
+const ProtocolVersion = 1
+
+// DefaultCommandTimeout bounds each invocation of an external plugin.
+const DefaultCommandTimeout = 60 * time.Second
+
+type execRequest struct {
+	Protocol int          `json:"protocol"`
+	Method   string       `json:"method"`
+	RootDir  string       `json:"root_dir,omitempty"`
+	Targets  []execTarget `json:"targets,omitempty"`
+}
+
+type execTarget struct {
+	Language string     `json:"language"`
+	Files    []execFile `json:"files"`
+}
+
+type execFile struct {
+	Path    string `json:"path"`
+	RelPath string `json:"rel_path"`
+	Class   string `json:"class"`
+	Lines   int    `json:"lines"`
+}
+
+type execResponse struct {
+	Metrics Values `json:"metrics"`
+	Error   string `json:"error"`
+}
+
+// commandPlugin runs an external executable speaking the JSON protocol.
+type commandPlugin struct {
+	name     string
+	args     []string
+	dir      string
+	timeout  time.Duration
+	category Category
+}
+
+// LoadCommand starts the executable name with args in dir, asks it to describe
+// its category and returns it as a Plugin. A zero timeout means DefaultCommandTimeout.
+func LoadCommand(name string, args []string, dir string, timeout time.Duration) (Plugin, error) {
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	p := &commandPlugin{name: name, args: args, dir: dir, timeout: timeout}
+
+	out, err := p.call(context.Background(), execRequest{Protocol: ProtocolVersion, Method: "describe"})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(out, &p.category); err != nil {
+		return nil, fmt.Errorf("plugin %s: parse describe response: %w", name, err)
+	}
+	if err := p.category.Validate(); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	return p, nil
+}
+
+func (p *commandPlugin) Category() Category {
+	return p.category
+}
+
+func (p *commandPlugin) Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	return p.AnalyzeContext(context.Background(), targets)
+}
+
+// AnalyzeContext implements ContextAnalyzer: the executable is killed once ctx
+// is done.
+func (p *commandPlugin) AnalyzeContext(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	req := execRequest{Protocol: ProtocolVersion, Method: "analyze"}
+	for _, t := range targets {
+		if req.RootDir == "" {
+			req.RootDir = t.RootDir
+		}
+		et := execTarget{Language: string(t.Language), Files: make([]execFile, 0, len(t.Files))}
+		for _, f := range t.Files {
+			et.Files = append(et.Files, execFile{Path: f.Path, RelPath: f.RelPath, Class: f.Class.String(), Lines: f.Lines})
+		}
+		req.Targets = append(req.Targets, et)
+	}
+
+	out, err := p.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp execResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("plugin %s: parse analyze response: %w", p.name, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("plugin %s: %s", p.name, resp.Error)
+	}
+	if resp.Metrics == nil {
+		resp.Metrics = Values{}
+	}
+	return Result(p.category.ID, resp.Metrics), nil
+}
+
+// call runs the executable once with req on stdin and returns its stdout.
+func (p *commandPlugin) call(ctx context.Context, req execRequest) ([]byte, error) {
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(callCtx, p.name, p.args...)
+	cmd.Dir = p.dir
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("plugin %s: %s: %w", p.name, req.Method, ctx.Err())
+		}
+		if callCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s: %s timed out after %s", p.name, req.Method, p.timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s: %s: %w: %s", p.name, req.Method, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s: %s: %w", p.name, req.Method, err)
+	}
+	return stdout.Bytes(), nil
+}
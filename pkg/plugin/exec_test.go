@@ -0,0 +1,124 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// TestHelperPlugin is not a real test: it is the external plugin executable
+// started by the tests below, selected by ARS_TEST_PLUGIN.
+func TestHelperPlugin(t *testing.T) {
+	mode := os.Getenv("ARS_TEST_PLUGIN")
+	if mode == "" {
+		return
+	}
+	var req execRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	switch {
+	case mode == "crash":
+		fmt.Fprintln(os.Stderr, "boom")
+		os.Exit(3)
+	case mode == "hang", mode == "hang-analyze" && req.Method == "analyze":
+		time.Sleep(time.Minute)
+	case req.Method == "describe":
+		json.NewEncoder(os.Stdout).Encode(securityCategory())
+	case mode == "error":
+		fmt.Fprint(os.Stdout, `{"error": "no lockfile"}`)
+	default:
+		files := 0
+		for _, t := range req.Targets {
+			files += len(t.Files)
+		}
+		json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"metrics": map[string]Value{"hardcoded_secrets": {Value: float64(files)}},
+		})
+	}
+	os.Exit(0)
+}
+
+func helperArgs() []string {
+	return []string{"-test.run=^TestHelperPlugin$"}
+}
+
+func TestLoadCommand(t *testing.T) {
+	t.Setenv("ARS_TEST_PLUGIN", "ok")
+	p, err := LoadCommand(os.Args[0], helperArgs(), t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("LoadCommand: %v", err)
+	}
+	if c := p.Category(); c.ID != "C8" || len(c.Metrics) != 2 {
+		t.Fatalf("Category() = %+v", c)
+	}
+
+	targets := []*types.AnalysisTarget{{
+		Language: types.LangGo,
+		RootDir:  "/project",
+		Files:    []types.SourceFile{{Path: "/project/a.go", RelPath: "a.go"}, {Path: "/project/b.go", RelPath: "b.go"}},
+	}}
+	ar, err := p.Analyze(targets)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	raw, unavailable, _ := Extract(p, ar)
+	if raw["hardcoded_secrets"] != 2 {
+		t.Errorf("hardcoded_secrets = %v, want 2 (file count echoed by helper)", raw["hardcoded_secrets"])
+	}
+	if !unavailable["unpinned_deps"] {
+		t.Error("metric missing from the response should be unavailable")
+	}
+}
+
+func TestLoadCommand_Failures(t *testing.T) {
+	t.Setenv("ARS_TEST_PLUGIN", "crash")
+	if _, err := LoadCommand(os.Args[0], helperArgs(), "", 0); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("crashing plugin: err = %v, want stderr in error", err)
+	}
+
+	t.Setenv("ARS_TEST_PLUGIN", "hang")
+	if _, err := LoadCommand(os.Args[0], helperArgs(), "", 200*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("hanging plugin: err = %v, want timeout", err)
+	}
+
+	t.Setenv("ARS_TEST_PLUGIN", "error")
+	p, err := LoadCommand(os.Args[0], helperArgs(), "", 0)
+	if err != nil {
+		t.Fatalf("LoadCommand: %v", err)
+	}
+	if _, err := p.Analyze(nil); err == nil || !strings.Contains(err.Error(), "no lockfile") {
+		t.Errorf("error response: err = %v", err)
+	}
+
+	if _, err := LoadCommand("/nonexistent/ars-plugin", nil, "", 0); err == nil {
+		t.Error("missing executable: expected error")
+	}
+}
+
+func TestCommandPlugin_AnalyzeContext(t *testing.T) {
+	t.Setenv("ARS_TEST_PLUGIN", "hang-analyze")
+	p, err := LoadCommand(os.Args[0], helperArgs(), "", time.Minute)
+	if err != nil {
+		t.Fatalf("LoadCommand: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = Analyze(ctx, p, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cancelled analysis: err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("plugin kept running for %s after the context was done", elapsed)
+	}
+}
@@ -0,0 +1,299 @@
+// Package plugin lets third parties add scoring categories to ARS.
+//
+// A plugin describes its category (identifier, display name, default weight,
+// metrics with breakpoints, descriptions and recommendation templates) and
+// analyzes the same targets the built-in analyzers see. Its metrics are scored,
+// weighted into the composite, rendered and turned into recommendations exactly
+// like those of C1-C7.
+//
+// Plugins are either Go packages compiled into a custom build, registered from
+// an init function:
+//
+//	func init() { plugin.MustRegister(securityPlugin{}) }
+//
+// or external executables listed under "plugins" in .arsrc.yml that speak the
+// JSON protocol documented at ProtocolVersion (see LoadCommand). External
+// plugins run only when the user allows it, and only for the scan that loaded
+// them.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+// Breakpoint maps a raw metric value to a score (1-10).
+// A metric's breakpoints must be sorted by Value in ascending order.
+type Breakpoint struct {
+	Value float64 `json:"value"`
+	Score float64 `json:"score"`
+}
+
+// Metric describes one scored metric of a plugin category.
+type Metric struct {
+	Name        string       `json:"name"`                   // identifier, unique across all categories (e.g., "hardcoded_secrets")
+	DisplayName string       `json:"display_name,omitempty"` // label in terminal and HTML output
+	Description string       `json:"description,omitempty"`  // 1-2 sentences shown in the HTML report
+	Impact      string       `json:"impact,omitempty"`       // why the metric matters for agents, used in recommendation summaries
+	Action      string       `json:"action,omitempty"`       // fmt template receiving the current and target value, e.g. "Remove secrets (%.0f found, target %.0f)"
+	Weight      float64      `json:"weight"`                 // default weight within the category
+	Breakpoints []Breakpoint `json:"breakpoints"`            // default scoring breakpoints
+}
+
+// Category describes a plugin category and its default scoring configuration.
+// Weight and breakpoints can be overridden in the scoring config or .arsrc.yml
+// like those of built-in categories.
+type Category struct {
+	ID      string   `json:"id"`               // identifier used in reports and config (e.g., "C8")
+	Name    string   `json:"name"`             // display name (e.g., "Security Hygiene")
+	Impact  string   `json:"impact,omitempty"` // one sentence on why the category matters for agents
+	Weight  float64  `json:"weight"`           // default weight in the composite score
+	Metrics []Metric `json:"metrics"`
+}
+
+// builtinCategories are the identifiers reserved for the built-in analyzers.
+var builtinCategories = map[string]bool{
+	"C1": true, "C2": true, "C3": true, "C4": true, "C5": true, "C6": true, "C7": true,
+}
+
+// Validate reports whether c can be registered.
+func (c Category) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("category has no id")
+	}
+	if builtinCategories[c.ID] {
+		return fmt.Errorf("category %s is reserved for a built-in analyzer", c.ID)
+	}
+	if c.Name == "" {
+		return fmt.Errorf("category %s has no name", c.ID)
+	}
+	if c.Weight < 0 {
+		return fmt.Errorf("category %s: weight must be >= 0, got %f", c.ID, c.Weight)
+	}
+	if len(c.Metrics) == 0 {
+		return fmt.Errorf("category %s has no metrics", c.ID)
+	}
+	seen := make(map[string]bool)
+	for _, m := range c.Metrics {
+		if m.Name == "" {
+			return fmt.Errorf("category %s: metric has no name", c.ID)
+		}
+		if seen[m.Name] {
+			return fmt.Errorf("category %s: duplicate metric %q", c.ID, m.Name)
+		}
+		seen[m.Name] = true
+		if len(m.Breakpoints) == 0 {
+			return fmt.Errorf("category %s: metric %q has no breakpoints", c.ID, m.Name)
+		}
+		for i := 1; i < len(m.Breakpoints); i++ {
+			if m.Breakpoints[i].Value < m.Breakpoints[i-1].Value {
+				return fmt.Errorf("category %s: breakpoints of metric %q are not sorted by value", c.ID, m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Plugin is a custom analysis category.
+type Plugin interface {
+	// Category returns the category description. It must be constant.
+	Category() Category
+	// Analyze inspects the project's targets (one per detected language) and
+	// returns the category's result. Result.Category is set to Category().ID.
+	// Unless the plugin implements Extractor, Metrics[Category().ID] must hold Values.
+	Analyze(targets []*types.AnalysisTarget) (*types.AnalysisResult, error)
+}
+
+// ContextAnalyzer is implemented by plugins whose analysis can be cancelled.
+// ARS calls AnalyzeContext instead of Analyze with the scan's context, which is
+// done once the scan is interrupted or exceeds its --timeout.
+type ContextAnalyzer interface {
+	AnalyzeContext(ctx context.Context, targets []*types.AnalysisTarget) (*types.AnalysisResult, error)
+}
+
+// Analyze runs p with ctx if it implements ContextAnalyzer and without otherwise.
+func Analyze(ctx context.Context, p Plugin, targets []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	if ca, ok := p.(ContextAnalyzer); ok {
+		return ca.AnalyzeContext(ctx, targets)
+	}
+	return p.Analyze(targets)
+}
+
+// Extractor is implemented by plugins that store their own metric types in
+// AnalysisResult.Metrics. It returns raw values per metric name, the metrics that
+// could not be measured, and per-metric evidence (worst offenders first).
+type Extractor interface {
+	Extract(ar *types.AnalysisResult) (rawValues map[string]float64, unavailable map[string]bool, evidence map[string][]types.EvidenceItem)
+}
+
+// Value is a measured metric value.
+type Value struct {
+	Value       float64              `json:"value"`
+	Unavailable bool                 `json:"unavailable,omitempty"` // excluded from scoring, e.g. when nothing could be measured
+	Evidence    []types.EvidenceItem `json:"evidence,omitempty"`
+}
+
+// Values holds a plugin category's metric values by metric name. It is the
+// default metrics type for plugins that do not implement Extractor.
+type Values map[string]Value
+
+// IsCategoryMetrics implements types.CategoryMetrics.
+func (Values) IsCategoryMetrics() {}
+
+// Result wraps values in an AnalysisResult for category id.
+func Result(id string, values Values) *types.AnalysisResult {
+	return &types.AnalysisResult{
+		Name:     id,
+		Category: id,
+		Metrics:  map[string]types.CategoryMetrics{id: values},
+	}
+}
+
+// Extract returns ar's raw values, unavailable metrics and evidence, using p's
+// Extractor if it has one and the Values stored under the category id otherwise.
+// It returns nil maps if ar holds no values, marking the category unavailable.
+func Extract(p Plugin, ar *types.AnalysisResult) (map[string]float64, map[string]bool, map[string][]types.EvidenceItem) {
+	if e, ok := p.(Extractor); ok {
+		return e.Extract(ar)
+	}
+	values, ok := ar.Metrics[ar.Category].(Values)
+	if !ok {
+		return nil, nil, nil
+	}
+	raw := make(map[string]float64, len(values))
+	unavailable := make(map[string]bool)
+	evidence := make(map[string][]types.EvidenceItem)
+	for _, m := range p.Category().Metrics {
+		v, ok := values[m.Name]
+		if !ok || v.Unavailable {
+			unavailable[m.Name] = true
+			continue
+		}
+		raw[m.Name] = v.Value
+		evidence[m.Name] = v.Evidence
+	}
+	return raw, unavailable, evidence
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Plugin)
+)
+
+// Register adds p to the compiled-in plugins run by every subsequent scan. A
+// plugin registered earlier under the same category id is replaced. External
+// plugins loaded with LoadCommand are not registered but passed to the scan
+// that runs them.
+func Register(p Plugin) error {
+	c := p.Category()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for id, other := range registry {
+		if id == c.ID {
+			continue
+		}
+		if err := checkMetrics(c, other.Category()); err != nil {
+			return err
+		}
+	}
+	registry[c.ID] = p
+	return nil
+}
+
+// Check reports whether plugins can be run by a scan together with the
+// registered plugins: each category must be valid, and category ids and
+// metric names must be unique.
+func Check(plugins []Plugin) error {
+	others := Registered()
+	for _, p := range plugins {
+		c := p.Category()
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		for _, other := range others {
+			oc := other.Category()
+			if oc.ID == c.ID {
+				return fmt.Errorf("category %s is already defined", c.ID)
+			}
+			if err := checkMetrics(c, oc); err != nil {
+				return err
+			}
+		}
+		others = append(others, p)
+	}
+	return nil
+}
+
+// checkMetrics returns an error if c defines a metric of other.
+func checkMetrics(c, other Category) error {
+	for _, m := range c.Metrics {
+		if _, ok := findMetric(other, m.Name); ok {
+			return fmt.Errorf("category %s: metric %q is already defined by category %s", c.ID, m.Name, other.ID)
+		}
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics on error. It is meant for init functions.
+func MustRegister(p Plugin) {
+	if err := Register(p); err != nil {
+		panic("plugin: " + err.Error())
+	}
+}
+
+// Unregister removes the plugin registered under category id, if any.
+func Unregister(id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(registry, id)
+}
+
+// Registered returns all registered plugins sorted by category id.
+func Registered() []Plugin {
+	mu.RLock()
+	defer mu.RUnlock()
+	plugins := make([]Plugin, 0, len(registry))
+	for _, p := range registry {
+		plugins = append(plugins, p)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Category().ID < plugins[j].Category().ID
+	})
+	return plugins
+}
+
+// Lookup returns the plugin registered under category id.
+func Lookup(id string) (Plugin, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := registry[id]
+	return p, ok
+}
+
+// LookupMetric returns the description of a plugin metric by name.
+func LookupMetric(name string) (Metric, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, p := range registry {
+		if m, ok := findMetric(p.Category(), name); ok {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+func findMetric(c Category, name string) (Metric, bool) {
+	for _, m := range c.Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
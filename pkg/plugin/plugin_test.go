@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ingo-eichhorst/agent-readyness/pkg/types"
+)
+
+type testPlugin struct {
+	category Category
+	values   Values
+}
+
+func (p testPlugin) Category() Category { return p.category }
+
+func (p testPlugin) Analyze(_ []*types.AnalysisTarget) (*types.AnalysisResult, error) {
+	return Result(p.category.ID, p.values), nil
+}
+
+func securityCategory() Category {
+	return Category{
+		ID:     "C8",
+		Name:   "Security Hygiene",
+		Weight: 0.1,
+		Metrics: []Metric{
+			{Name: "hardcoded_secrets", Weight: 0.6, Breakpoints: []Breakpoint{{0, 10}, {5, 1}}},
+			{Name: "unpinned_deps", Weight: 0.4, Breakpoints: []Breakpoint{{0, 10}, {20, 1}}},
+		},
+	}
+}
+
+func TestCategoryValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Category)
+		want   string
+	}{
+		{"valid", func(*Category) {}, ""},
+		{"no id", func(c *Category) { c.ID = "" }, "no id"},
+		{"builtin id", func(c *Category) { c.ID = "C3" }, "reserved"},
+		{"no name", func(c *Category) { c.Name = "" }, "no name"},
+		{"negative weight", func(c *Category) { c.Weight = -1 }, "weight"},
+		{"no metrics", func(c *Category) { c.Metrics = nil }, "no metrics"},
+		{"duplicate metric", func(c *Category) { c.Metrics[1].Name = "hardcoded_secrets" }, "duplicate"},
+		{"no breakpoints", func(c *Category) { c.Metrics[0].Breakpoints = nil }, "no breakpoints"},
+		{"unsorted breakpoints", func(c *Category) {
+			c.Metrics[0].Breakpoints = []Breakpoint{{5, 1}, {0, 10}}
+		}, "not sorted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := securityCategory()
+			tt.modify(&c)
+			err := c.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	p := testPlugin{category: securityCategory()}
+	if err := Register(p); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	t.Cleanup(func() { Unregister("C8") })
+
+	if got, ok := Lookup("C8"); !ok || got.Category().Name != "Security Hygiene" {
+		t.Errorf("Lookup(C8) = %v, %v", got, ok)
+	}
+	if m, ok := LookupMetric("unpinned_deps"); !ok || m.Weight != 0.4 {
+		t.Errorf("LookupMetric(unpinned_deps) = %+v, %v", m, ok)
+	}
+	if _, ok := LookupMetric("complexity_avg"); ok {
+		t.Error("LookupMetric found a built-in metric")
+	}
+
+	// Re-registering the same category replaces it.
+	replacement := securityCategory()
+	replacement.Name = "Security"
+	if err := Register(testPlugin{category: replacement}); err != nil {
+		t.Fatalf("re-Register: %v", err)
+	}
+	if got := Registered(); len(got) != 1 || got[0].Category().Name != "Security" {
+		t.Errorf("Registered() after replace = %d plugins", len(got))
+	}
+
+	// Metric names must be unique across categories.
+	other := securityCategory()
+	other.ID = "C9"
+	if err := Register(testPlugin{category: other}); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("Register with clashing metric = %v, want error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := testPlugin{category: securityCategory()}
+	if err := Check([]Plugin{p}); err != nil {
+		t.Errorf("Check(valid) = %v", err)
+	}
+	if err := Check([]Plugin{p, p}); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("Check(duplicate id) = %v, want error", err)
+	}
+	other := securityCategory()
+	other.ID = "C9"
+	if err := Check([]Plugin{p, testPlugin{category: other}}); err == nil || !strings.Contains(err.Error(), "already defined") {
+		t.Errorf("Check(clashing metric) = %v, want error", err)
+	}
+
+	if err := Register(p); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Unregister("C8") })
+	if err := Check([]Plugin{p}); err == nil {
+		t.Error("Check accepted a category already registered")
+	}
+	if len(Registered()) != 1 {
+		t.Error("Check registered plugins")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	evidence := []types.EvidenceItem{{FilePath: "config.go", Line: 3, Value: 1}}
+	p := testPlugin{
+		category: securityCategory(),
+		values:   Values{"hardcoded_secrets": {Value: 2, Evidence: evidence}},
+	}
+	ar, _ := p.Analyze(nil)
+
+	raw, unavailable, ev := Extract(p, ar)
+	if raw["hardcoded_secrets"] != 2 || len(ev["hardcoded_secrets"]) != 1 {
+		t.Errorf("hardcoded_secrets = %v with %d evidence, want 2 with 1", raw["hardcoded_secrets"], len(ev["hardcoded_secrets"]))
+	}
+	if !unavailable["unpinned_deps"] {
+		t.Error("metric missing from Values should be unavailable")
+	}
+
+	raw, _, _ = Extract(p, &types.AnalysisResult{Category: "C8"})
+	if raw != nil {
+		t.Errorf("result without Values: raw = %v, want nil", raw)
+	}
+}
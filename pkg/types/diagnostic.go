@@ -0,0 +1,37 @@
+package types
+
+// Severity ranks a Diagnostic.
+type Severity string
+
+const (
+	// SeverityError marks a failure that removed results from the scan,
+	// such as an analyzer that returned an error.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem the scan worked around, such as a
+	// file that could not be read or a package that failed to type-check.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo marks a notable but expected event, such as a skipped
+	// symlink.
+	SeverityInfo Severity = "info"
+)
+
+// Diagnostic is a problem met while scanning a project. Diagnostics replace
+// free-form warnings so reports can show which parts of a score rest on
+// incomplete input.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	Analyzer string   `json:"analyzer,omitempty"` // category or stage that reported it, e.g. "C1", "parser"
+	File     string   `json:"file,omitempty"`     // path relative to the project root; empty if not file-specific
+	Message  string   `json:"message"`
+}
+
+// CountSeverity returns how many diagnostics have severity s.
+func CountSeverity(diags []Diagnostic, s Severity) int {
+	n := 0
+	for _, d := range diags {
+		if d.Severity == s {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,68 @@
+package types
+
+import "time"
+
+// EventType identifies what an Event reports.
+type EventType string
+
+const (
+	// EventStageStart marks the start of a pipeline stage ("discover",
+	// "parse", "analyze", "score", "render").
+	EventStageStart EventType = "stage_start"
+	// EventStageEnd marks the end of a stage, with its duration.
+	EventStageEnd EventType = "stage_end"
+	// EventAnalyzerStart marks an analyzer starting on the parsed project.
+	EventAnalyzerStart EventType = "analyzer_start"
+	// EventAnalyzerEnd marks an analyzer finishing, with its duration and
+	// any error.
+	EventAnalyzerEnd EventType = "analyzer_end"
+	// EventMetricStart marks a C7 metric starting, with its number of samples.
+	EventMetricStart EventType = "c7_metric_start"
+	// EventMetricSample marks a C7 sample finishing.
+	EventMetricSample EventType = "c7_sample"
+	// EventMetricEnd marks a C7 metric finishing, with its score and tokens.
+	EventMetricEnd EventType = "c7_metric_end"
+	// EventTokens reports the tokens of one LLM call and the running total.
+	EventTokens EventType = "tokens"
+	// EventDiagnostic carries a Diagnostic as soon as it is reported.
+	EventDiagnostic EventType = "diagnostic"
+	// EventSummary is the last event of a scan.
+	EventSummary EventType = "summary"
+)
+
+// Event is one step of a scan's progress, written by --events ndjson as one
+// JSON object per line. Only the fields relevant to its Type are set.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	Stage    string `json:"stage,omitempty"`    // stage events
+	Detail   string `json:"detail,omitempty"`   // human-readable stage description
+	Analyzer string `json:"analyzer,omitempty"` // category of analyzer and token events, e.g. "C7"
+	Module   string `json:"module,omitempty"`   // monorepo module being analyzed, by path
+	Metric   string `json:"metric,omitempty"`   // C7 metric ID
+
+	Sample      int    `json:"sample,omitempty"`       // samples finished so far
+	Total       int    `json:"total,omitempty"`        // samples planned, including repeats
+	Score       int    `json:"score,omitempty"`        // C7 metric score (1-10); 0 if it failed
+	Tokens      int    `json:"tokens,omitempty"`       // tokens of the call or metric
+	TotalTokens int    `json:"total_tokens,omitempty"` // tokens used by the scan so far
+	DurationMS  int64  `json:"duration_ms,omitempty"`  // end events: time since the matching start
+	Error       string `json:"error,omitempty"`
+
+	Diagnostic *Diagnostic  `json:"diagnostic,omitempty"`
+	Summary    *ScanSummary `json:"summary,omitempty"`
+}
+
+// ScanSummary is the outcome of a scan, reported by EventSummary.
+type ScanSummary struct {
+	Composite   float64            `json:"composite_score"`
+	Tier        string             `json:"tier"`
+	Categories  map[string]float64 `json:"categories"`            // category score by name
+	DurationMS  int64              `json:"duration_ms"`           // whole scan
+	TotalTokens int                `json:"total_tokens"`          // all LLM calls of the scan
+	Errors      int                `json:"errors"`                // error diagnostics
+	Warnings    int                `json:"warnings"`              // warning diagnostics
+	Interrupted []string           `json:"interrupted,omitempty"` // categories cut short
+	Failed      string             `json:"failed,omitempty"`      // why the scan produced no scores
+}
@@ -0,0 +1,27 @@
+package types
+
+// Module is one independently built unit of a monorepo: a Go module, a
+// package of an npm or pnpm workspace, or a Python project with its own
+// pyproject.toml.
+type Module struct {
+	Name     string   // module path, package name, or directory if unnamed
+	Path     string   // directory relative to the repository root, "." for the root
+	Dir      string   // absolute directory
+	Language Language // language the module is analyzed as
+	Marker   string   // file that declares it: "go.mod", "package.json" or "pyproject.toml"
+}
+
+// ModuleScore is the score of one module of a monorepo, analyzed as its own
+// project with its own module path and import graph.
+type ModuleScore struct {
+	Module
+	LOC        int             // source lines; the module's weight in the repository totals
+	Composite  float64         // weighted composite score of the module's categories
+	Tier       string          // tier classification of Composite
+	Categories []CategoryScore // per-category scores of the module
+
+	// Results holds the module's raw analyzer output. Repository-level
+	// categories (C5, C7) are analyzed once for the whole repository and are
+	// not part of it.
+	Results []*AnalysisResult
+}
@@ -6,14 +6,63 @@ type ScoredResult struct {
 	Categories  []CategoryScore // Per-category scores (C1, C3, C6)
 	Composite   float64         // Weighted composite score (1-10)
 	Tier        string          // Tier classification (e.g., "Agent-Ready")
+	LLMBudget   *LLMBudget      // LLM spend against --llm-budget-*; nil without a budget
+
+	// CompositeInterval is the composite's uncertainty band from repeated C7
+	// runs (--c7-repeats); nil for a single run.
+	CompositeInterval *ScoreInterval
+
+	// Interrupted lists the categories whose analysis was cut short because
+	// the scan was cancelled or exceeded its timeout; nil for a complete scan.
+	// A cut-short category is either missing or scored from partial results.
+	Interrupted []string
+
+	// Diagnostics lists the problems met during the scan, most severe first.
+	// An error diagnostic means a category lost results, for example because
+	// its analyzer failed; nil for a clean scan.
+	Diagnostics []Diagnostic
+
+	// Modules holds the per-module scores of a monorepo, in path order; nil
+	// for a single-module project. Categories and Composite are then the
+	// line-of-code weighted totals of the modules.
+	Modules []ModuleScore
+}
+
+// ScoreInterval summarizes a score measured over repeated runs.
+type ScoreInterval struct {
+	Runs   int     `json:"runs"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Low    float64 `json:"ci_low"`  // 95% bootstrap confidence interval
+	High   float64 `json:"ci_high"` // of the mean
+}
+
+// LLMBudget reports LLM usage against the configured budget and what was cut
+// to stay within it.
+type LLMBudget struct {
+	LimitUSD       float64  `json:"limit_usd,omitempty"`    // 0 if no cost limit
+	LimitTokens    int      `json:"limit_tokens,omitempty"` // 0 if no token limit
+	SpentUSD       float64  `json:"spent_usd"`
+	TokensUsed     int      `json:"tokens_used"`
+	Exhausted      bool     `json:"exhausted"`                 // a limit was reached
+	DroppedSamples int      `json:"dropped_samples,omitempty"` // C7 samples not evaluated
+	RefusedCalls   int      `json:"refused_calls,omitempty"`   // LLM calls refused once exhausted
+	Skipped        []string `json:"skipped,omitempty"`         // e.g. "C7 change_success"
+	UnpricedModels []string `json:"unpriced_models,omitempty"` // models used without a known price
 }
 
 // CategoryScore holds the score for one category (e.g., C1 Code Health).
 type CategoryScore struct {
-	Name      string     // Category identifier (e.g., "C1")
-	Score     float64    // Weighted average of sub-scores (1-10)
-	Weight    float64    // Weight in composite score
-	SubScores []SubScore // Per-metric sub-scores
+	Name      string         // Category identifier (e.g., "C1")
+	Score     float64        // Weighted average of sub-scores (1-10)
+	Weight    float64        // Weight in composite score
+	SubScores []SubScore     // Per-metric sub-scores
+	Interval  *ScoreInterval // Score over repeated runs (C7 with --c7-repeats); nil otherwise
+
+	Interrupted bool // scored from partial results of a cut-short analysis
+
+	DisplayName string // plugin categories: the category's display name; empty for C1-C7
+	Impact      string // plugin categories: why the category matters for agents
 }
 
 // EvidenceItem represents a single worst-offender for a metric.
@@ -32,6 +81,10 @@ type SubScore struct {
 	Weight     float64        `json:"weight"`
 	Available  bool           `json:"available"`
 	Evidence   []EvidenceItem `json:"evidence"`
+	Interval   *ScoreInterval `json:"interval,omitempty"` // raw value over repeated runs; nil for one run
+
+	DisplayName string `json:"display_name,omitempty"` // plugin metrics: label in reports; empty for C1-C7
+	Description string `json:"description,omitempty"`  // plugin metrics: 1-2 sentence description
 }
 
 // ExitError is returned when the CLI should exit with a specific code.
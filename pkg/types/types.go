@@ -9,6 +9,21 @@ const (
 	LangTypeScript Language = "typescript"
 )
 
+// BuildCommands returns the commands that build and test a project in the
+// language, in order, or nil for unknown languages. Generated agent prompts
+// and the C7 change-success metric share them.
+func (l Language) BuildCommands() []string {
+	switch l {
+	case LangGo:
+		return []string{"go build ./...", "go test ./..."}
+	case LangPython:
+		return []string{"python -m pytest"}
+	case LangTypeScript:
+		return []string{"npm test"}
+	}
+	return nil
+}
+
 // AnalysisTarget is the language-agnostic unit of analysis.
 // Each target represents one language found in the project.
 type AnalysisTarget struct {
@@ -75,6 +90,16 @@ type ScanResult struct {
 	SymlinkCount   int                 // Symlinks detected and skipped
 	Files          []DiscoveredFile    // All discovered files
 	PerLanguage    map[Language]int    // Source file count per language
+	Exclusions     []ExclusionRule     // What each exclusion rule kept out, by source and pattern
+}
+
+// ExclusionRule counts the files and directories one exclusion rule kept out
+// of a scan.
+type ExclusionRule struct {
+	Source  string // where the rule is defined: "default", ".arsrc.yml", ".arsignore", ".gitignore" (or a nested one such as "api/.gitignore"), ".git/info/exclude" or "core.excludesFile"
+	Pattern string // the rule as written, e.g. "build" or "*.pb.go"
+	Files   int    // source files excluded
+	Dirs    int    // directories not walked
 }
 
 // AnalysisResult holds the output of a single analysis pass (Phase 2).
@@ -151,7 +176,7 @@ func (*C3Metrics) IsCategoryMetrics() {}
 type DeadExport struct {
 	Package string
 	Name    string
-	File    string
+	File    string // base name for Go, whose Package locates it; slash-separated path relative to the root otherwise
 	Line    int
 	Kind    string // "func", "type", "var", "const"
 }
@@ -255,20 +280,23 @@ type C4Metrics struct {
 	DocumentedAPIs   int
 
 	// LLM-based metrics (only populated if --enable-c4-llm is used)
-	LLMEnabled        bool    // true if LLM analysis was performed
-	ReadmeClarity     int     // 1-10 scale
-	ExampleQuality    int     // 1-10 scale
-	Completeness      int     // 1-10 scale
-	CrossRefCoherence int     // 1-10 scale
-	LLMCostUSD        float64 // Actual cost incurred
-	LLMTokensUsed     int     // Total tokens used
-	LLMFilesSampled   int     // Number of files sampled for LLM analysis
+	LLMEnabled        bool     // true if LLM analysis was performed
+	ReadmeClarity     int      // 1-10 scale
+	ExampleQuality    int      // 1-10 scale
+	Completeness      int      // 1-10 scale
+	CrossRefCoherence int      // 1-10 scale
+	LLMCostUSD        float64  // Actual cost incurred
+	LLMTokensUsed     int      // Total tokens used
+	LLMFilesSampled   int      // Number of files sampled for LLM analysis
+	LLMJudge          string   // Judge backend that produced the scores (e.g., "claude", "openai")
+	LLMModel          string   // Judge model; empty if the backend default was used
+	LLMTemperature    *float64 // Judge sampling temperature; nil if the backend does not expose it
 }
 
 // IsCategoryMetrics marks C4Metrics as a CategoryMetrics implementation.
 func (*C4Metrics) IsCategoryMetrics() {}
 
-// C7Metrics holds Agent Evaluation metric results including 5 MECE metrics.
+// C7Metrics holds Agent Evaluation metric results including the MECE metrics.
 type C7Metrics struct {
 	Available bool // false if claude CLI not found or user declined
 
@@ -278,16 +306,32 @@ type C7Metrics struct {
 	CrossFileCoherence     int // 0-100 score
 	SemanticCompleteness   int // 0-100 score
 
-	// NEW: 5 MECE metrics (1-10 scale)
+	// NEW: MECE metrics (1-10 scale)
 	TaskExecutionConsistency       int // M1: Reproducibility across runs (1-10)
 	CodeBehaviorComprehension      int // M2: Understanding what code does (1-10)
 	CrossFileNavigation            int // M3: Tracing dependencies across files (1-10)
 	IdentifierInterpretability     int // M4: Inferring meaning from names (1-10)
 	DocumentationAccuracyDetection int // M5: Detecting comment/code mismatches (1-10)
+	ChangeSuccess                  int // M6: Edits that still build and pass tests (1-10); 0 if not run
+	FeatureLocalization            int // M7: Finding the code for a described feature (1-10); 0 if not run
+
+	// Feature localization (M7) details, over the samples that ran.
+	LocalizationTop1      float64 // share of symbols the agent named first (0-1)
+	LocalizationTop3      float64 // share of symbols among the agent's three answers (0-1)
+	LocalizationToolCalls float64 // mean tool calls per sample; -1 if the backend reports none
+
+	// Navigation effort over every sample whose tool calls were traced; nil
+	// if the backend does not trace tool calls.
+	Navigation *C7Navigation
 
 	// Aggregate scores
 	OverallScore float64 // Legacy: average of 4 task scores (0-100)
-	MECEScore    float64 // NEW: weighted average of 5 MECE metrics (1-10)
+	MECEScore    float64 // NEW: weighted average of the MECE metrics (1-10)
+
+	// Repeated runs (--c7-repeats): every metric's samples ran Repeats times,
+	// and the scores above are the means.
+	Repeats      int            // 1 unless --c7-repeats was set
+	MECEInterval *ScoreInterval // MECEScore over the repeats; nil for one run
 
 	// Detailed results
 	TaskResults   []C7TaskResult   // Legacy task results
@@ -297,6 +341,9 @@ type C7Metrics struct {
 	TotalDuration float64 // seconds
 	TokensUsed    int     // estimated total tokens
 	CostUSD       float64 // estimated cost
+
+	CachedResponses int // agent responses served from the LLM response cache
+	Retries         int // agent calls retried after rate limits or transient errors
 }
 
 // IsCategoryMetrics marks C7Metrics as a CategoryMetrics implementation.
@@ -312,6 +359,10 @@ type C7TaskResult struct {
 	Reasoning string  // scoring rationale from LLM judge
 }
 
+// FailedRepeat is the entry of C7MetricResult.RepeatScores for a repeat in
+// which the metric failed, as opposed to one that scored 0.
+const FailedRepeat = -1
+
 // C7MetricResult holds results for a single MECE metric.
 type C7MetricResult struct {
 	MetricID     string           // e.g., "task_execution_consistency"
@@ -322,6 +373,18 @@ type C7MetricResult struct {
 	Reasoning    string           // scoring rationale
 	Samples      []string         // sample descriptions used
 	DebugSamples []C7DebugSample  `json:"debug_samples,omitempty"` // only present when debug active
+	RepeatScores []int            `json:"repeat_scores,omitempty"` // score of each repeat, FailedRepeat if it failed; nil for one run
+	Interval     *ScoreInterval   `json:"interval,omitempty"`      // Score over the repeats; nil for one run
+	Navigation   *C7Navigation    `json:"navigation,omitempty"`    // nil if no tool calls were traced
+}
+
+// C7Navigation is the mean navigation effort of the samples whose tool calls
+// were traced.
+type C7Navigation struct {
+	Samples        int     `json:"samples"`         // traced samples
+	ToolCalls      float64 `json:"tool_calls"`      // mean tool calls per sample
+	FilesRead      float64 `json:"files_read"`      // mean distinct files read per sample
+	RedundantReads float64 `json:"redundant_reads"` // mean repeated reads of the same file and range per sample
 }
 
 // C7IndicatorMatch records one heuristic indicator check during scoring.
@@ -331,11 +394,47 @@ type C7IndicatorMatch struct {
 	Delta   int    `json:"delta"`   // Point contribution (+1, -1, +2, etc.)
 }
 
+// C7GroundTruthMatch compares the items of one kind an agent referenced with
+// ground truth from static analysis.
+type C7GroundTruthMatch struct {
+	Kind         string   `json:"kind"`                   // "files" or "symbols"
+	Matched      []string `json:"matched,omitempty"`      // Expected and referenced
+	Missed       []string `json:"missed,omitempty"`       // Expected but not referenced
+	Hallucinated []string `json:"hallucinated,omitempty"` // Referenced but not in the ground truth
+	Precision    float64  `json:"precision"`
+	Recall       float64  `json:"recall"`
+}
+
 // C7ScoreTrace records the complete scoring breakdown for one sample.
 type C7ScoreTrace struct {
-	BaseScore  int                `json:"base_score"`  // Starting score before adjustments
-	Indicators []C7IndicatorMatch `json:"indicators"`  // Each indicator checked
-	FinalScore int                `json:"final_score"` // Score after clamping to 1-10
+	BaseScore   int                  `json:"base_score"`             // Starting score before adjustments
+	Indicators  []C7IndicatorMatch   `json:"indicators"`             // Each indicator checked
+	FinalScore  int                  `json:"final_score"`            // Score after clamping to 1-10
+	GroundTruth []C7GroundTruthMatch `json:"ground_truth,omitempty"` // Set when scored against static analysis
+
+	RunAgreement  *float64         `json:"run_agreement,omitempty"` // M1: mean Jaccard similarity with the other runs
+	Change        *C7ChangeOutcome       `json:"change,omitempty"`        // M6: build/test result and diff of the agent's change
+	Localization  *C7LocalizationOutcome `json:"localization,omitempty"`  // M7: rank of the expected symbol among the agent's answers
+	Justification string                 `json:"justification,omitempty"` // Judge's reasoning when an LLM judge scored the response
+}
+
+// C7LocalizationOutcome records where the expected symbol ranked among an
+// agent's answers to a feature localization prompt.
+type C7LocalizationOutcome struct {
+	Expected  string   `json:"expected"`          // "file: Symbol" the agent should have named
+	Answers   []string `json:"answers,omitempty"` // the agent's ranked "file: Symbol" answers
+	Rank      int      `json:"rank"`              // 1-based rank of the expected symbol; 0 if not named
+	FileRank  int      `json:"file_rank"`         // 1-based rank of the first answer in the expected file; 0 if none
+	ToolCalls int      `json:"tool_calls"`        // -1 if the backend does not report them
+}
+
+// C7ChangeOutcome records how the project fared after an agent's change.
+type C7ChangeOutcome struct {
+	Passed       bool     `json:"passed"`           // build and tests succeeded after the change
+	Output       string   `json:"output,omitempty"` // failing command and the tail of its output
+	LinesAdded   int      `json:"lines_added"`
+	LinesDeleted int      `json:"lines_deleted"`
+	FilesTouched []string `json:"files_touched"`
 }
 
 // C7DebugSample holds complete debug data for one metric sample evaluation.
@@ -349,4 +448,24 @@ type C7DebugSample struct {
 	Duration    float64      `json:"duration_seconds"`
 	ScoreTrace  C7ScoreTrace `json:"score_trace"`
 	Error       string       `json:"error,omitempty"`
+	ToolTrace   *C7ToolTrace `json:"tool_trace,omitempty"` // nil if the backend does not trace tool calls
+	Retries     int          `json:"retries,omitempty"`    // agent calls retried after rate limits or transient errors
+}
+
+// C7ToolTrace is the tool-call trace of one agent run and the navigation
+// effort derived from it.
+type C7ToolTrace struct {
+	Calls          []C7ToolCall `json:"calls"`
+	FilesRead      int          `json:"files_read"`      // distinct files read
+	RedundantReads int          `json:"redundant_reads"` // reads repeating an earlier read of the same file and range
+}
+
+// C7ToolCall is one tool invocation of an agent.
+type C7ToolCall struct {
+	Tool     string  `json:"tool"`             // e.g. "Read", "Grep"
+	Input    string  `json:"input"`            // arguments as compact JSON, truncated
+	Path     string  `json:"path,omitempty"`   // file or directory the call targets
+	Start    float64 `json:"start_seconds"`    // offset from the start of the run
+	Duration float64 `json:"duration_seconds"` // until the tool result arrived; 0 if unknown
+	Error    bool    `json:"error,omitempty"`  // the tool reported an error
 }
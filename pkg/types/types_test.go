@@ -93,3 +93,17 @@ func TestExitErrorCodes(t *testing.T) {
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr
 }
+
+func TestCountSeverity(t *testing.T) {
+	diags := []Diagnostic{
+		{Severity: SeverityError},
+		{Severity: SeverityWarning},
+		{Severity: SeverityError},
+	}
+	if got := CountSeverity(diags, SeverityError); got != 2 {
+		t.Errorf("CountSeverity(error) = %d, want 2", got)
+	}
+	if got := CountSeverity(nil, SeverityInfo); got != 0 {
+		t.Errorf("CountSeverity(nil) = %d, want 0", got)
+	}
+}
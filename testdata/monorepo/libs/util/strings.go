@@ -0,0 +1,12 @@
+// Package util holds helpers shared by the services.
+package util
+
+import "strings"
+
+// Title upper-cases the first letter of s.
+func Title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
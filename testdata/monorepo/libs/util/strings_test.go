@@ -0,0 +1,9 @@
+package util
+
+import "testing"
+
+func TestTitle(t *testing.T) {
+	if got := Title("api"); got != "Api" {
+		t.Errorf("Title(api) = %q", got)
+	}
+}
@@ -0,0 +1,4 @@
+/** Formats a greeting for the given name. */
+export function greet(name: string): string {
+  return `Hello, ${name}`;
+}
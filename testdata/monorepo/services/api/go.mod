@@ -0,0 +1,5 @@
+module example.com/api
+
+go 1.25
+
+require example.com/util v0.0.0
@@ -0,0 +1,17 @@
+// Command api serves a greeting.
+package main
+
+import (
+	"fmt"
+
+	"example.com/util"
+)
+
+func main() {
+	fmt.Println(greeting("world"))
+}
+
+// greeting returns the greeting for name.
+func greeting(name string) string {
+	return "Hello, " + util.Title(name)
+}
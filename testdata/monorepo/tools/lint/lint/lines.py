@@ -0,0 +1,6 @@
+"""Lint helpers for the monorepo."""
+
+
+def count_lines(text: str) -> int:
+    """Return the number of lines in text."""
+    return len(text.splitlines())